@@ -1,8 +1,15 @@
 package main
 
 import (
+	"context"
 	"db-mcp/mcp"
+	"encoding/json"
+	"flag"
+	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"strings"
 
 	_ "github.com/denisenkom/go-mssqldb"
 	_ "github.com/go-sql-driver/mysql"
@@ -12,6 +19,26 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "bench":
+			// Bench mode replays a recorded trace instead of serving stdio
+			runBench(os.Args[2:])
+			return
+		case "quickstart":
+			// Quickstart verifies a connection string and prints a client config
+			runQuickstart(os.Args[2:])
+			return
+		}
+	}
+
+	flags := flag.NewFlagSet("db-mcp", flag.ExitOnError)
+	configPath := flags.String("config", "", "JSON or YAML configuration file (overrides DB_CONFIG_FILE)")
+	_ = flags.Parse(os.Args[1:])
+	if *configPath != "" {
+		os.Setenv("DB_CONFIG_FILE", *configPath)
+	}
+
 	// Define MCP Server
 	mcpServer, err := mcp.NewMcpServer()
 	if err != nil {
@@ -26,3 +53,108 @@ func main() {
 		return
 	}
 }
+
+// runBench replays a tool-call trace against the database from DB_DRIVER/DB_CONNECTION_STRING
+// (or the -driver/-connection flags) and prints latency percentiles per tool
+func runBench(args []string) {
+	flags := flag.NewFlagSet("bench", flag.ExitOnError)
+	tracePath := flags.String("trace", "", "JSON Lines file with one tool call per line (required)")
+	concurrency := flags.Int("concurrency", mcp.DefaultBenchConcurrency, "Number of concurrent callers")
+	iterations := flags.Int("iterations", mcp.DefaultBenchIterations, "Number of times the trace is replayed")
+	driver := flags.String("driver", "", "Database driver (overrides DB_DRIVER)")
+	connection := flags.String("connection", "", "Connection string (overrides DB_CONNECTION_STRING)")
+	configPath := flags.String("config", "", "JSON or YAML configuration file (overrides DB_CONFIG_FILE)")
+	asJSON := flags.Bool("json", false, "Print the report as JSON")
+	_ = flags.Parse(args)
+
+	if *tracePath == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if *driver != "" {
+		os.Setenv("DB_DRIVER", *driver)
+	}
+	if *connection != "" {
+		os.Setenv("DB_CONNECTION_STRING", *connection)
+	}
+	if *configPath != "" {
+		os.Setenv("DB_CONFIG_FILE", *configPath)
+	}
+
+	mcpServer, err := mcp.NewMcpServer()
+	if err != nil {
+		log.Fatalf("Error setting up MCP server: %v", err)
+	}
+	defer mcpServer.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	report, err := mcpServer.RunBench(ctx, mcp.BenchConfig{
+		TracePath:   *tracePath,
+		Concurrency: *concurrency,
+		Iterations:  *iterations,
+	})
+	if report == nil {
+		log.Fatalf("Error running bench: %v", err)
+	}
+	if err != nil {
+		log.Printf("Bench interrupted: %v", err)
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	} else {
+		err = report.WriteText(os.Stdout)
+	}
+	if err != nil {
+		log.Fatalf("Error writing bench report: %v", err)
+	}
+}
+
+// runQuickstart detects the driver of a connection string, checks the connection and
+// prints a ready-to-paste MCP client configuration with the recommended limits
+func runQuickstart(args []string) {
+	flags := flag.NewFlagSet("quickstart", flag.ExitOnError)
+	name := flags.String("name", "database", "Server name in the MCP client configuration")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: db-mcp quickstart [-name database] <connection-string>")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	result, err := mcp.Quickstart(context.Background(), flags.Arg(0))
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	command, err := os.Executable()
+	if err != nil {
+		command = os.Args[0]
+	}
+
+	config, err := result.ClientConfig(*name, command)
+	if err != nil {
+		log.Fatalf("Error writing client configuration: %v", err)
+	}
+
+	fmt.Printf("Connected to %s (%s)\n\n", result.Driver, firstLine(result.Version))
+	fmt.Println("Add this to your MCP client configuration:")
+	fmt.Println()
+	fmt.Println(string(config))
+}
+
+// firstLine returns the first line of a multi-line version string
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return strings.TrimSpace(s[:i])
+	}
+	return s
+}
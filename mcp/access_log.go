@@ -0,0 +1,268 @@
+package mcp
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// accessLog records the tables read by each successful tool call, so table_access_report
+// can tell owners which tables agents rely on and which tools drive the load. It is kept
+// in memory and holds the last MaxAccessLogEntries accesses since the server started.
+type accessLog struct {
+	mu      sync.Mutex
+	entries []tableAccess
+	next    int
+}
+
+// tableAccess is a read of a table by a tool call. Schema is empty when the call did not
+// name one and the table resolved against the default schema or search path.
+type tableAccess struct {
+	at     time.Time
+	tool   string
+	schema string
+	table  string
+}
+
+// newAccessLog returns an empty access log
+func newAccessLog() *accessLog {
+	return &accessLog{}
+}
+
+// middleware records the tables read by the tool calls that succeed
+func (l *accessLog) middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, request)
+		if err != nil || result == nil || result.IsError {
+			return result, err
+		}
+
+		now := time.Now()
+		tool := request.Params.Name
+		for _, name := range accessedTables(tool, request.GetArguments()) {
+			l.add(tableAccess{at: now, tool: tool, schema: name[0], table: name[1]})
+		}
+		return result, err
+	}
+}
+
+// add appends an access, overwriting the oldest once the log is full
+func (l *accessLog) add(access tableAccess) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.entries) < MaxAccessLogEntries {
+		l.entries = append(l.entries, access)
+		return
+	}
+	l.entries[l.next] = access
+	l.next = (l.next + 1) % MaxAccessLogEntries
+}
+
+// since returns a copy of the accesses at or after from, oldest first, and false when
+// older accesses of that period were already overwritten
+func (l *accessLog) since(from time.Time) ([]tableAccess, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	accesses := make([]tableAccess, 0, len(l.entries))
+	for i := range l.entries {
+		access := l.entries[(l.next+i)%len(l.entries)]
+		if !access.at.Before(from) {
+			accesses = append(accesses, access)
+		}
+	}
+	complete := len(l.entries) < MaxAccessLogEntries || l.entries[l.next].at.Before(from)
+	return accesses, complete
+}
+
+// accessedTables returns the schema and name of the tables a tool call read, from its
+// table arguments or, for execute_query and export_query, from the FROM and JOIN clauses
+// of the query
+func accessedTables(tool string, args map[string]interface{}) [][2]string {
+	tables := argumentTables(args)
+	if tool == "execute_query" || tool == "export_query" {
+		if query, ok := args["query"].(string); ok {
+			tables = append(tables, queryTables(query)...)
+		}
+	}
+	return tables
+}
+
+// argumentTables returns the schema and name of the tables named by the table_name,
+// object_name and tables arguments of a tool call
+func argumentTables(args map[string]interface{}) [][2]string {
+	schema, _ := args["schema"].(string)
+
+	var tables [][2]string
+	for _, key := range []string{"table_name", "object_name"} {
+		if name, ok := args[key].(string); ok && name != "" {
+			tables = append(tables, argumentTable(schema, name))
+		}
+	}
+	if names, ok := args["tables"].([]interface{}); ok {
+		for _, name := range names {
+			if name, ok := name.(string); ok && name != "" {
+				tables = append(tables, argumentTable(schema, name))
+			}
+		}
+	}
+	return tables
+}
+
+// argumentTable returns the schema and name of a table argument without their quotes,
+// taking the schema from a schema.table name when the schema argument is not set
+func argumentTable(schema, name string) [2]string {
+	if schema == "" {
+		if schemaName, tableName, ok := splitQualifiedName(name); ok {
+			schema, name = schemaName, tableName
+		}
+	}
+	schema, _ = unquoteIdentifier(schema)
+	name, _ = unquoteIdentifier(name)
+	return [2]string{schema, name}
+}
+
+// queryTables returns the schema and name of the tables in the FROM and JOIN clauses of a
+// query, once each. Common table expressions are reported as tables too.
+func queryTables(query string) [][2]string {
+	query = reBlockComments.ReplaceAllString(query, " ")
+	query = reLineComments.ReplaceAllString(query, " ")
+	query = reSingleQuotes.ReplaceAllString(query, "''")
+
+	seen := make(map[[2]string]bool)
+	var tables [][2]string
+	for _, match := range reQueryTables.FindAllStringSubmatch(query, -1) {
+		parts := strings.Split(match[1], ".")
+		for i, part := range parts {
+			parts[i] = strings.Trim(strings.TrimSpace(part), "\"[]`")
+		}
+
+		var name [2]string
+		name[1] = parts[len(parts)-1]
+		if len(parts) > 1 {
+			name[0] = parts[len(parts)-2]
+		}
+		if name[1] == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		tables = append(tables, name)
+	}
+	return tables
+}
+
+// tableAccessSummary is the read count of a table over the report window
+type tableAccessSummary struct {
+	Schema     string         `json:"schema,omitempty"`
+	Table      string         `json:"table"`
+	Reads      int            `json:"reads"`
+	LastAccess time.Time      `json:"last_access"`
+	Tools      map[string]int `json:"tools"`
+}
+
+// tableAccessReportArgs are the arguments of table_access_report
+type tableAccessReportArgs struct {
+	Window string `json:"window,omitempty" jsonschema_description:"Report only the accesses of this last period, as a duration such as 30m or 24h (optional, default: since the server started)"`
+	Schema string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	pageArgs
+}
+
+func (s *DbMCPServer) toolTableAccessReport() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("table_access_report", "Reports how often this MCP server read each table, with the last access time and the tools that read it, most read first. Shows which tables agents rely on and which tools drive the load. Covers the tool calls since the server started", s.handleTableAccessReport)
+}
+
+func (s *DbMCPServer) handleTableAccessReport(ctx context.Context, request mcp.CallToolRequest, args tableAccessReportArgs) (*mcp.CallToolResult, error) {
+	schema, err := getValidSchema(args.Schema, "")
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	now := time.Now()
+	from := s.started
+	if args.Window != "" {
+		window, err := time.ParseDuration(args.Window)
+		if err != nil || window <= 0 {
+			return toolErrorResult(ErrInvalidWindow), nil
+		}
+		if now.Add(-window).After(from) {
+			from = now.Add(-window)
+		}
+	}
+
+	pagination := args.pagination()
+
+	byTable := make(map[[2]string]*tableAccessSummary)
+	toolReads := make(map[string]int)
+	accesses, complete := s.accesses.since(from)
+	for _, access := range accesses {
+		if schema != "" && !strings.EqualFold(access.schema, schema) {
+			continue
+		}
+		key := [2]string{strings.ToLower(access.schema), strings.ToLower(access.table)}
+		summary := byTable[key]
+		if summary == nil {
+			summary = &tableAccessSummary{Schema: access.schema, Table: access.table, Tools: map[string]int{}}
+			byTable[key] = summary
+		}
+		summary.Reads++
+		summary.Tools[access.tool]++
+		// Accesses come oldest first
+		summary.LastAccess = access.at.UTC()
+		toolReads[access.tool]++
+	}
+
+	tables := make([]*tableAccessSummary, 0, len(byTable))
+	for _, summary := range byTable {
+		tables = append(tables, summary)
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		if tables[i].Reads != tables[j].Reads {
+			return tables[i].Reads > tables[j].Reads
+		}
+		if tables[i].Schema != tables[j].Schema {
+			return tables[i].Schema < tables[j].Schema
+		}
+		return tables[i].Table < tables[j].Table
+	})
+
+	total := len(tables)
+	start := pagination.Offset
+	if start > total {
+		start = total
+	}
+	end := start + pagination.PageSize
+	if end > total {
+		end = total
+	}
+	page := tables[start:end]
+
+	response := map[string]interface{}{
+		"tables":     page,
+		"tool_reads": toolReads,
+		"window": map[string]interface{}{
+			"from": from.UTC().Format(time.RFC3339),
+			"to":   now.UTC().Format(time.RFC3339),
+		},
+		"pagination": map[string]interface{}{
+			"page":      pagination.Page,
+			"page_size": pagination.PageSize,
+			"count":     len(page),
+			"total":     total,
+			"has_more":  end < total,
+		},
+	}
+	if schema != "" {
+		response["schema"] = schema
+	}
+	if !complete {
+		response["message"] = translate("The access log is full: older accesses of the window were dropped")
+	}
+
+	return jsonToolResult(response), nil
+}
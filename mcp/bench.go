@@ -0,0 +1,280 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// BenchConfig configures a bench run that replays a recorded tool-call trace
+type BenchConfig struct {
+	// TracePath is a JSON Lines file with one tool call per line
+	TracePath string
+	// Concurrency is the number of workers issuing calls in parallel
+	Concurrency int
+	// Iterations is the number of times the whole trace is replayed
+	Iterations int
+}
+
+// BenchToolStats holds the latency distribution of one tool
+type BenchToolStats struct {
+	Tool   string        `json:"tool"`
+	Calls  int           `json:"calls"`
+	Errors int           `json:"errors"`
+	Mean   time.Duration `json:"mean_ns"`
+	P50    time.Duration `json:"p50_ns"`
+	P90    time.Duration `json:"p90_ns"`
+	P95    time.Duration `json:"p95_ns"`
+	P99    time.Duration `json:"p99_ns"`
+	Max    time.Duration `json:"max_ns"`
+}
+
+// BenchReport is the result of a bench run
+type BenchReport struct {
+	Calls       int              `json:"calls"`
+	Errors      int              `json:"errors"`
+	Concurrency int              `json:"concurrency"`
+	Elapsed     time.Duration    `json:"elapsed_ns"`
+	Throughput  float64          `json:"calls_per_second"`
+	Tools       []BenchToolStats `json:"tools"`
+}
+
+// traceCall is a recorded tool call. Both the short form {"tool": ..., "arguments": ...}
+// and a JSON-RPC tools/call request {"params": {"name": ..., "arguments": ...}} are accepted.
+type traceCall struct {
+	Tool      string                 `json:"tool"`
+	Arguments map[string]interface{} `json:"arguments"`
+	Params    *struct {
+		Name      string                 `json:"name"`
+		Arguments map[string]interface{} `json:"arguments"`
+	} `json:"params"`
+}
+
+// benchSample is the outcome of one replayed call
+type benchSample struct {
+	tool     string
+	duration time.Duration
+	failed   bool
+}
+
+// RunBench replays the trace against the connected database and reports latency
+// percentiles per tool. Calls go through the registered tool handlers, so results
+// include validation, query execution and JSON serialization, on the view serving the
+// default connection when the run starts.
+func (s *DbMCPServer) RunBench(ctx context.Context, config BenchConfig) (*BenchReport, error) {
+	view, release := s.defaultConn.acquire()
+	defer release()
+	if err := view.requireConnection(); err != nil {
+		return nil, err
+	}
+
+	calls, err := loadTrace(config.TracePath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, call := range calls {
+		if view.server.GetTool(call.Tool) == nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownBenchTool, call.Tool)
+		}
+	}
+
+	concurrency := config.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBenchConcurrency
+	}
+	iterations := config.Iterations
+	if iterations <= 0 {
+		iterations = DefaultBenchIterations
+	}
+
+	jobs := make(chan traceCall)
+	results := make([][]benchSample, concurrency)
+	var wg sync.WaitGroup
+
+	started := time.Now()
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for call := range jobs {
+				results[worker] = append(results[worker], view.replayCall(ctx, call))
+			}
+		}(i)
+	}
+
+feed:
+	for i := 0; i < iterations; i++ {
+		for _, call := range calls {
+			select {
+			case jobs <- call:
+			case <-ctx.Done():
+				break feed
+			}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	elapsed := time.Since(started)
+
+	var samples []benchSample
+	for _, workerSamples := range results {
+		samples = append(samples, workerSamples...)
+	}
+
+	report := summarizeBench(samples)
+	report.Concurrency = concurrency
+	report.Elapsed = elapsed
+	if elapsed > 0 {
+		report.Throughput = float64(report.Calls) / elapsed.Seconds()
+	}
+
+	return report, ctx.Err()
+}
+
+// replayCall invokes a tool handler and measures its latency
+func (s *DbMCPServer) replayCall(ctx context.Context, call traceCall) benchSample {
+	tool := s.server.GetTool(call.Tool)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = call.Tool
+	request.Params.Arguments = call.Arguments
+
+	// Handlers are called directly, so attribute the statements as the middleware would
+	ctx = withWatermarkCall(ctx, watermarkCall{tool: call.Tool, client: "bench"})
+
+	started := time.Now()
+	result, err := tool.Handler(ctx, request)
+	duration := time.Since(started)
+
+	return benchSample{
+		tool:     call.Tool,
+		duration: duration,
+		failed:   err != nil || result == nil || result.IsError,
+	}
+}
+
+// loadTrace reads the recorded tool calls, skipping blank lines
+func loadTrace(path string) ([]traceCall, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrReadingBenchTrace, err)
+	}
+	defer file.Close()
+
+	var calls []traceCall
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), MaxBenchTraceLineBytes)
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := scanner.Bytes()
+		if len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+
+		var call traceCall
+		if err = json.Unmarshal(data, &call); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrReadingBenchTrace, line, err)
+		}
+		if call.Tool == "" && call.Params != nil {
+			call.Tool = call.Params.Name
+			call.Arguments = call.Params.Arguments
+		}
+		if call.Tool == "" {
+			return nil, fmt.Errorf("%w: line %d: missing tool name", ErrReadingBenchTrace, line)
+		}
+		calls = append(calls, call)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrReadingBenchTrace, err)
+	}
+
+	if len(calls) == 0 {
+		return nil, ErrEmptyBenchTrace
+	}
+
+	return calls, nil
+}
+
+// summarizeBench groups the samples per tool and computes the latency percentiles
+func summarizeBench(samples []benchSample) *BenchReport {
+	byTool := make(map[string][]benchSample)
+	for _, sample := range samples {
+		byTool[sample.tool] = append(byTool[sample.tool], sample)
+	}
+
+	report := &BenchReport{Calls: len(samples)}
+	for tool, toolSamples := range byTool {
+		durations := make([]time.Duration, len(toolSamples))
+		stats := BenchToolStats{Tool: tool, Calls: len(toolSamples)}
+		var total time.Duration
+		for i, sample := range toolSamples {
+			durations[i] = sample.duration
+			total += sample.duration
+			if sample.failed {
+				stats.Errors++
+			}
+		}
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+		stats.Mean = total / time.Duration(len(durations))
+		stats.P50 = percentile(durations, 50)
+		stats.P90 = percentile(durations, 90)
+		stats.P95 = percentile(durations, 95)
+		stats.P99 = percentile(durations, 99)
+		stats.Max = durations[len(durations)-1]
+
+		report.Errors += stats.Errors
+		report.Tools = append(report.Tools, stats)
+	}
+	sort.Slice(report.Tools, func(i, j int) bool { return report.Tools[i].Tool < report.Tools[j].Tool })
+
+	return report
+}
+
+// percentile returns the nearest-rank percentile of sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// WriteText writes the report as an aligned table
+func (r *BenchReport) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TOOL\tCALLS\tERRORS\tMEAN\tP50\tP90\tP95\tP99\tMAX")
+	for _, t := range r.Tools {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s\n", t.Tool, t.Calls, t.Errors,
+			roundLatency(t.Mean), roundLatency(t.P50), roundLatency(t.P90),
+			roundLatency(t.P95), roundLatency(t.P99), roundLatency(t.Max))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\n%d calls, %d errors, concurrency %d, %s elapsed, %.1f calls/s\n",
+		r.Calls, r.Errors, r.Concurrency, r.Elapsed.Round(time.Millisecond), r.Throughput)
+	return err
+}
+
+// roundLatency rounds a latency for display
+func roundLatency(d time.Duration) time.Duration {
+	if d >= time.Second {
+		return d.Round(time.Millisecond)
+	}
+	return d.Round(time.Microsecond)
+}
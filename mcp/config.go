@@ -0,0 +1,224 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The configuration file, set with DB_CONFIG_FILE or the -config flag, gathers the settings
+// of the server in one JSON or YAML file. Each setting stands for an environment variable,
+// which the file sets when it is not set already: the environment overrides the file, and
+// the rest of the server keeps reading its settings from the environment only.
+
+// serverConfig is the content of the configuration file
+type serverConfig struct {
+	Driver               string                 `json:"driver,omitempty" yaml:"driver"`
+	ConnectionString     string                 `json:"connection_string,omitempty" yaml:"connection_string"`
+	SearchPath           []string               `json:"search_path,omitempty" yaml:"search_path"`
+	LazyConnect          *bool                  `json:"lazy_connect,omitempty" yaml:"lazy_connect"`
+	Language             string                 `json:"language,omitempty" yaml:"language"`
+	ConnectionsFile      string                 `json:"connections_file,omitempty" yaml:"connections_file"`
+	Pool                 poolConfig             `json:"pool" yaml:"pool"`
+	Timeouts             timeoutsConfig         `json:"timeouts" yaml:"timeouts"`
+	ValidationPolicyFile string                 `json:"validation_policy_file,omitempty" yaml:"validation_policy_file"`
+	ValidationPolicy     map[string]interface{} `json:"validation_policy,omitempty" yaml:"validation_policy"`
+	TLS                  tlsConfig              `json:"tls" yaml:"tls"`
+	Secret               secretConfig           `json:"secret" yaml:"secret"`
+	Tools                toolsConfig            `json:"tools" yaml:"tools"`
+	Env                  map[string]interface{} `json:"env,omitempty" yaml:"env"`
+}
+
+// poolConfig is the connection pool section of the configuration file
+type poolConfig struct {
+	MaxOpenConns    *int   `json:"max_open_conns,omitempty" yaml:"max_open_conns"`
+	MaxIdleConns    *int   `json:"max_idle_conns,omitempty" yaml:"max_idle_conns"`
+	ConnMaxLifetime string `json:"conn_max_lifetime,omitempty" yaml:"conn_max_lifetime"`
+	IdleTimeout     string `json:"idle_timeout,omitempty" yaml:"idle_timeout"`
+}
+
+// timeoutsConfig is the timeouts section of the configuration file
+type timeoutsConfig struct {
+	MaxQueryTimeout     string `json:"max_query_timeout,omitempty" yaml:"max_query_timeout"`
+	WatchdogSoftTimeout string `json:"watchdog_soft_timeout,omitempty" yaml:"watchdog_soft_timeout"`
+	WatchdogHardTimeout string `json:"watchdog_hard_timeout,omitempty" yaml:"watchdog_hard_timeout"`
+}
+
+// tlsConfig is the tls section of the configuration file, and of a connection of
+// DB_CONNECTIONS_FILE
+type tlsConfig struct {
+	Enabled    *bool  `json:"enabled,omitempty" yaml:"enabled"`
+	CAFile     string `json:"ca_file,omitempty" yaml:"ca_file"`
+	CertFile   string `json:"cert_file,omitempty" yaml:"cert_file"`
+	KeyFile    string `json:"key_file,omitempty" yaml:"key_file"`
+	ServerName string `json:"server_name,omitempty" yaml:"server_name"`
+	SkipVerify *bool  `json:"skip_verify,omitempty" yaml:"skip_verify"`
+}
+
+// args returns the TLS options of a connection as the arguments of the datasource tools,
+// which fall back to the environment
+func (c *tlsConfig) args() tlsArgs {
+	if c == nil {
+		return tlsArgs{}
+	}
+	return tlsArgs{
+		TLS:           c.Enabled,
+		TLSCAFile:     c.CAFile,
+		TLSCertFile:   c.CertFile,
+		TLSKeyFile:    c.KeyFile,
+		TLSServerName: c.ServerName,
+		TLSSkipVerify: c.SkipVerify,
+	}
+}
+
+// secretConfig is the secret section of the configuration file
+type secretConfig struct {
+	Source          string `json:"source,omitempty" yaml:"source"`
+	Name            string `json:"name,omitempty" yaml:"name"`
+	Key             string `json:"key,omitempty" yaml:"key"`
+	Target          string `json:"target,omitempty" yaml:"target"`
+	RefreshInterval string `json:"refresh_interval,omitempty" yaml:"refresh_interval"`
+}
+
+// toolsConfig is the tools section of the configuration file
+type toolsConfig struct {
+	Enabled  []string `json:"enabled,omitempty" yaml:"enabled"`
+	Disabled []string `json:"disabled,omitempty" yaml:"disabled"`
+}
+
+// loadConfigFile reads the configuration file of DB_CONFIG_FILE, if set, and sets the
+// environment variables of its settings that are not set already
+func loadConfigFile() error {
+	path := strings.TrimSpace(os.Getenv("DB_CONFIG_FILE"))
+	if path == "" {
+		return nil
+	}
+	config, err := readConfigFile(path)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrInvalidConfigFile, path, err)
+	}
+	settings, err := config.environment()
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrInvalidConfigFile, path, err)
+	}
+	// Empty variables are unset, as for the settings read from the environment
+	for _, setting := range settings {
+		if os.Getenv(setting[0]) == "" {
+			os.Setenv(setting[0], setting[1])
+		}
+	}
+	return nil
+}
+
+// readConfigFile reads a configuration file, as YAML when it ends in .yaml or .yml and as
+// JSON otherwise. Unknown fields make the file invalid, so a misspelled setting is not
+// silently ignored.
+func readConfigFile(path string) (*serverConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &serverConfig{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(config)
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(config)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// environment returns the environment variables of the settings of the file, with their
+// value. ${VAR} references in the connection string are read from the environment, so
+// passwords stay out of the file.
+func (c *serverConfig) environment() ([][2]string, error) {
+	var settings [][2]string
+	set := func(name, value string) {
+		if value != "" {
+			settings = append(settings, [2]string{name, value})
+		}
+	}
+
+	set("DB_DRIVER", c.Driver)
+	set("DB_CONNECTION_STRING", os.ExpandEnv(c.ConnectionString))
+	set("DB_SEARCH_PATH", strings.Join(c.SearchPath, ","))
+	if c.LazyConnect != nil {
+		set("DB_LAZY_CONNECT", strconv.FormatBool(*c.LazyConnect))
+	}
+	set("DB_LANGUAGE", c.Language)
+	set("DB_CONNECTIONS_FILE", c.ConnectionsFile)
+	if c.Pool.MaxOpenConns != nil {
+		set("DB_MAX_OPEN_CONNS", strconv.Itoa(*c.Pool.MaxOpenConns))
+	}
+	if c.Pool.MaxIdleConns != nil {
+		set("DB_MAX_IDLE_CONNS", strconv.Itoa(*c.Pool.MaxIdleConns))
+	}
+	set("DB_CONN_MAX_LIFETIME", c.Pool.ConnMaxLifetime)
+	set("DB_IDLE_TIMEOUT", c.Pool.IdleTimeout)
+	set("DB_MAX_QUERY_TIMEOUT", c.Timeouts.MaxQueryTimeout)
+	set("DB_WATCHDOG_SOFT_TIMEOUT", c.Timeouts.WatchdogSoftTimeout)
+	set("DB_WATCHDOG_HARD_TIMEOUT", c.Timeouts.WatchdogHardTimeout)
+	set("DB_VALIDATION_POLICY_FILE", c.ValidationPolicyFile)
+	if c.ValidationPolicy != nil {
+		policy, err := json.Marshal(c.ValidationPolicy)
+		if err != nil {
+			return nil, fmt.Errorf("validation_policy: %w", err)
+		}
+		set("DB_VALIDATION_POLICY", string(policy))
+	}
+	if c.TLS.Enabled != nil {
+		set("DB_TLS", strconv.FormatBool(*c.TLS.Enabled))
+	}
+	set("DB_TLS_CA_FILE", c.TLS.CAFile)
+	set("DB_TLS_CERT_FILE", c.TLS.CertFile)
+	set("DB_TLS_KEY_FILE", c.TLS.KeyFile)
+	set("DB_TLS_SERVER_NAME", c.TLS.ServerName)
+	if c.TLS.SkipVerify != nil {
+		set("DB_TLS_SKIP_VERIFY", strconv.FormatBool(*c.TLS.SkipVerify))
+	}
+	set("DB_SECRET_SOURCE", c.Secret.Source)
+	set("DB_SECRET_NAME", c.Secret.Name)
+	set("DB_SECRET_KEY", c.Secret.Key)
+	set("DB_SECRET_TARGET", c.Secret.Target)
+	set("DB_SECRET_REFRESH_INTERVAL", c.Secret.RefreshInterval)
+	set("DB_ENABLED_TOOLS", strings.Join(c.Tools.Enabled, ","))
+	set("DB_DISABLED_TOOLS", strings.Join(c.Tools.Disabled, ","))
+
+	// Any other setting of the environment, in a stable order
+	names := make([]string, 0, len(c.Env))
+	for name := range c.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !strings.HasPrefix(name, "DB_") || name == "DB_CONFIG_FILE" {
+			return nil, fmt.Errorf("env: %s", name)
+		}
+		switch value := c.Env[name].(type) {
+		case string:
+			set(name, value)
+		case float64:
+			set(name, strconv.FormatFloat(value, 'f', -1, 64))
+		case int, int64, bool:
+			set(name, fmt.Sprint(value))
+		default:
+			return nil, fmt.Errorf("env: %s", name)
+		}
+	}
+	return settings, nil
+}
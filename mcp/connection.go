@@ -5,44 +5,50 @@ import (
 	"database/sql"
 	"log"
 	"os"
+	"strconv"
+	"time"
 )
 
 // newDbConnection creates a new database connection from environment variables.
 // Returns nil connection if DB_CONNECTION_STRING is not set (allows dynamic configuration later).
 // If connection string is set but connection fails, logs a warning and returns nil connection
 // (server starts without connection, allowing user to reconfigure via tools).
-func newDbConnection() (*sql.DB, string, error) {
+// With a secrets backend, the connection string or its password is read from the secret.
+func newDbConnection(secrets *secretRotation) (*sql.DB, string, error) {
 	// Get database driver type (default to sqlserver for backward compatibility)
-	driver := os.Getenv("DB_DRIVER")
-	if driver == "" {
-		driver = string(DriverSQLServer)
-	}
+	driver := getEnvDriver()
 
 	// Connection configuration from environment variable
 	connString := os.Getenv("DB_CONNECTION_STRING")
+	if secrets != nil {
+		resolved, err := secrets.resolve(context.Background())
+		if err != nil {
+			log.Printf("Warning: Could not read the secret of DB_SECRET_SOURCE: %v. Server starting without database connection. Use configure_datasource to connect.", err)
+			return nil, driver, nil
+		}
+		connString = resolved
+	}
 	if connString == "" {
 		// No connection string provided - server will start without database connection
 		// Use configure_datasource tool to connect later
 		return nil, driver, nil
 	}
 
-	db, err := sql.Open(driver, connString)
+	db, err := openEnvDatabase(driver, connString)
 	if err != nil {
 		// Log warning but don't fail - allow server to start
 		log.Printf("Warning: Could not open database connection: %v. Server starting without database connection. Use configure_datasource to connect.", err)
 		return nil, driver, nil
 	}
 
-	// Configure connection pool
-	db.SetMaxOpenConns(DBMaxOpenConns)
-	db.SetMaxIdleConns(DBMaxIdleConns)
-	db.SetConnMaxLifetime(DBConnMaxLifetime)
-
-	// Test connection with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), DBPingTimeout)
-	defer cancel()
+	// The pool connects on demand, so with lazy connect the first tool call opens the connection
+	if getEnvLazyConnect() {
+		log.Printf("DB_LAZY_CONNECT is set: the database connection opens on the first tool call")
+		return db, driver, nil
+	}
 
-	if err = db.PingContext(ctx); err != nil {
+	// Test connection, waiting for a paused database to resume
+	if err = pingDatabase(context.Background(), db); err != nil {
 		// Log warning but don't fail - allow server to start
 		log.Printf("Warning: Could not connect to database: %v. Server starting without database connection. Use configure_datasource to connect.", err)
 		db.Close()
@@ -52,6 +58,105 @@ func newDbConnection() (*sql.DB, string, error) {
 	return db, driver, nil
 }
 
+// openEnvDatabase opens the connection pool of a connection string of the environment,
+// with the SQL Server authentication, TLS options and search path of the environment
+func openEnvDatabase(driver, connString string) (*sql.DB, error) {
+	if driver == string(DriverSQLServer) {
+		resolved, err := applySQLServerAuth(connString, getEnvSQLServerAuth())
+		if err != nil {
+			return nil, err
+		}
+		connString = resolved
+	}
+
+	connString, _, err := resolveTLS(normalizeDriver(driver), connString, tlsArgs{})
+	if err != nil {
+		return nil, err
+	}
+
+	searchPath, err := getEnvSearchPath()
+	if err != nil {
+		log.Printf("Warning: Ignoring DB_SEARCH_PATH: %v", err)
+	}
+
+	return openDatabase(driver, connString, searchPath)
+}
+
+// openDatabase opens a connection pool for the driver and applies pool settings, which
+// DB_MAX_OPEN_CONNS, DB_MAX_IDLE_CONNS and DB_CONN_MAX_LIFETIME override.
+// When a search path is given, it is applied to every new session of the pool.
+func openDatabase(driver, connString string, searchPath []string) (*sql.DB, error) {
+	db, err := sql.Open(driver, connString)
+	if err != nil {
+		return nil, err
+	}
+
+	statements := NewQueryBuilder(driver).SearchPathStatements(searchPath)
+	watermark := getQueryWatermark()
+	if len(statements) > 0 || driver == string(DriverSQLServer) || watermark != nil {
+		connector, err := newConnector(db.Driver(), connString)
+		db.Close()
+		if err != nil {
+			return nil, err
+		}
+		// Retry logins while an Azure SQL serverless database resumes from auto-pause
+		if driver == string(DriverSQLServer) {
+			connector = &resumeConnector{Connector: connector}
+		}
+		if len(statements) > 0 {
+			connector = &sessionConnector{Connector: connector, statements: statements}
+		}
+		// Tag every statement with the session and tool that issued it
+		if watermark != nil {
+			connector = &watermarkConnector{Connector: connector, watermark: watermark}
+		}
+		db = sql.OpenDB(connector)
+	}
+
+	// Configure connection pool
+	db.SetMaxOpenConns(int(envInt("DB_MAX_OPEN_CONNS", DBMaxOpenConns)))
+	db.SetMaxIdleConns(int(envInt("DB_MAX_IDLE_CONNS", DBMaxIdleConns)))
+	db.SetConnMaxLifetime(envDuration("DB_CONN_MAX_LIFETIME", DBConnMaxLifetime))
+	// Close connections left idle, so a long-lived server does not hold them all day
+	db.SetConnMaxIdleTime(getEnvIdleTimeout())
+
+	return db, nil
+}
+
+// getEnvDriver reads the driver of the connection of the environment from DB_DRIVER,
+// sqlserver when it is not set
+func getEnvDriver() string {
+	if driver := os.Getenv("DB_DRIVER"); driver != "" {
+		return driver
+	}
+	return string(DriverSQLServer)
+}
+
+// getEnvSearchPath reads the default schema list from DB_SEARCH_PATH
+func getEnvSearchPath() ([]string, error) {
+	return parseSearchPath(os.Getenv("DB_SEARCH_PATH"))
+}
+
+// getEnvIdleTimeout reads from DB_IDLE_TIMEOUT how long a pooled connection may stay idle
+// before it is closed (0 disables it)
+func getEnvIdleTimeout() time.Duration {
+	return envDuration("DB_IDLE_TIMEOUT", DefaultDBIdleTimeout)
+}
+
+// getEnvLazyConnect reports whether DB_LAZY_CONNECT defers connecting until the first tool call
+func getEnvLazyConnect() bool {
+	value := os.Getenv("DB_LAZY_CONNECT")
+	if value == "" {
+		return false
+	}
+	lazy, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Warning: Ignoring invalid DB_LAZY_CONNECT=%q", value)
+		return false
+	}
+	return lazy
+}
+
 // requireConnection checks if a database connection is available
 func (s *DbMCPServer) requireConnection() error {
 	if s.db == nil {
@@ -0,0 +1,263 @@
+package mcp
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// The TLS options of a connection are applied to its connection string in the form of each
+// driver, so users do not have to write them into it by hand: sslmode, sslrootcert, sslcert
+// and sslkey for PostgreSQL, encrypt, trustservercertificate, certificate and
+// hostnameincertificate for SQL Server, and a tls.Config registered with the MySQL driver.
+// SQLite and Oracle (whose TLS is set up in its wallet) take no TLS options.
+
+// tlsOptions holds the TLS options of a connection
+type tlsOptions struct {
+	Enabled    bool
+	CAFile     string
+	CertFile   string
+	KeyFile    string
+	ServerName string
+	SkipVerify bool
+}
+
+// enabled reports whether the connection uses TLS, which any option turns on
+func (o tlsOptions) enabled() bool {
+	return o.Enabled || o.CAFile != "" || o.CertFile != "" || o.KeyFile != "" || o.ServerName != "" || o.SkipVerify
+}
+
+// getEnvTLS reads the TLS options from DB_TLS, DB_TLS_CA_FILE, DB_TLS_CERT_FILE,
+// DB_TLS_KEY_FILE, DB_TLS_SERVER_NAME and DB_TLS_SKIP_VERIFY
+func getEnvTLS() tlsOptions {
+	return tlsOptions{
+		Enabled:    getEnvTLSFlag("DB_TLS"),
+		CAFile:     strings.TrimSpace(os.Getenv("DB_TLS_CA_FILE")),
+		CertFile:   strings.TrimSpace(os.Getenv("DB_TLS_CERT_FILE")),
+		KeyFile:    strings.TrimSpace(os.Getenv("DB_TLS_KEY_FILE")),
+		ServerName: strings.TrimSpace(os.Getenv("DB_TLS_SERVER_NAME")),
+		SkipVerify: getEnvTLSFlag("DB_TLS_SKIP_VERIFY"),
+	}
+}
+
+// getEnvTLSFlag reads a boolean TLS setting
+func getEnvTLSFlag(name string) bool {
+	value := os.Getenv(name)
+	if value == "" {
+		return false
+	}
+	flag, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Warning: Ignoring invalid %s=%q", name, value)
+		return false
+	}
+	return flag
+}
+
+// tlsArgs are the TLS arguments of the datasource tools
+type tlsArgs struct {
+	TLS           *bool  `json:"tls,omitempty" jsonschema_description:"Encrypt the connection with TLS (optional, defaults to DB_TLS; the other tls_ options turn it on, and false turns off the TLS options of the environment). Applies to sqlserver, postgres and mysql"`
+	TLSCAFile     string `json:"tls_ca_file,omitempty" jsonschema_description:"PEM file of the certificate authority the server certificate is verified against (optional, defaults to DB_TLS_CA_FILE and the system certificates)"`
+	TLSCertFile   string `json:"tls_cert_file,omitempty" jsonschema_description:"PEM file of the client certificate, for servers requiring one (optional, with tls_key_file; postgres and mysql only)"`
+	TLSKeyFile    string `json:"tls_key_file,omitempty" jsonschema_description:"PEM file of the private key of the client certificate (optional, with tls_cert_file)"`
+	TLSServerName string `json:"tls_server_name,omitempty" jsonschema_description:"Name the server certificate is verified against, when it is not the host of the connection string (optional; sqlserver and mysql only)"`
+	TLSSkipVerify *bool  `json:"tls_skip_verify,omitempty" jsonschema_description:"Encrypt without verifying the server certificate, for test servers with self-signed certificates (optional, defaults to DB_TLS_SKIP_VERIFY)"`
+}
+
+// set reports whether any TLS argument is passed
+func (a tlsArgs) set() bool {
+	return a.TLS != nil || a.TLSCAFile != "" || a.TLSCertFile != "" || a.TLSKeyFile != "" || a.TLSServerName != "" || a.TLSSkipVerify != nil
+}
+
+// options returns the TLS options of the arguments, falling back to the environment
+func (a tlsArgs) options() tlsOptions {
+	if a.TLS != nil && !*a.TLS {
+		return tlsOptions{}
+	}
+	options := getEnvTLS()
+	if a.TLS != nil {
+		options.Enabled = true
+	}
+	if a.TLSCAFile != "" {
+		options.CAFile = a.TLSCAFile
+	}
+	if a.TLSCertFile != "" {
+		options.CertFile = a.TLSCertFile
+	}
+	if a.TLSKeyFile != "" {
+		options.KeyFile = a.TLSKeyFile
+	}
+	if a.TLSServerName != "" {
+		options.ServerName = a.TLSServerName
+	}
+	if a.TLSSkipVerify != nil {
+		options.SkipVerify = *a.TLSSkipVerify
+	}
+	return options
+}
+
+// resolveTLS applies the TLS options of a tool call to the connection string. The options
+// are rejected for the drivers without TLS options when passed explicitly, and the ones of
+// the environment are ignored for them.
+func resolveTLS(driver, connString string, args tlsArgs) (string, tlsOptions, error) {
+	switch DriverType(driver) {
+	case DriverSQLServer, DriverPostgresSQL, DriverMySQL:
+	default:
+		if args.set() && args.options().enabled() {
+			return "", tlsOptions{}, ErrTLSUnsupportedDriver
+		}
+		return connString, tlsOptions{}, nil
+	}
+
+	options := args.options()
+	connString, err := applyTLS(driver, connString, options)
+	return connString, options, err
+}
+
+// applyTLS rewrites a connection string for the TLS options of the driver. The connection
+// string is used as is when TLS is not enabled.
+func applyTLS(driver, connString string, options tlsOptions) (string, error) {
+	if !options.enabled() {
+		return connString, nil
+	}
+	if (options.CertFile == "") != (options.KeyFile == "") {
+		return "", ErrTLSClientKeyRequired
+	}
+
+	switch DriverType(driver) {
+	case DriverSQLServer:
+		return applySQLServerTLS(connString, options)
+	case DriverPostgresSQL:
+		return applyPostgresTLS(connString, options)
+	case DriverMySQL:
+		return applyMySQLTLS(connString, options)
+	}
+	return "", ErrTLSUnsupportedDriver
+}
+
+// applySQLServerTLS sets encrypt, trustservercertificate, and certificate and
+// hostnameincertificate when their option is set, keeping those of the connection string
+// otherwise. The SQL Server driver takes no client certificates.
+func applySQLServerTLS(connString string, options tlsOptions) (string, error) {
+	if options.CertFile != "" {
+		return "", fmt.Errorf("%w: tls_cert_file (sqlserver)", ErrTLSOptionUnsupported)
+	}
+
+	params := parseSQLServerConnString(connString)
+	params.set("encrypt", "true")
+	if options.SkipVerify {
+		params.set("trustservercertificate", "true")
+	} else {
+		params.set("trustservercertificate", "false")
+		if options.CAFile != "" {
+			params.set("certificate", options.CAFile)
+		}
+	}
+	if options.ServerName != "" {
+		params.set("hostnameincertificate", options.ServerName)
+	}
+	return params.String(), nil
+}
+
+// applyPostgresTLS sets sslmode, sslrootcert, sslcert and sslkey, in a URL or in a
+// key=value connection string. The server certificate is verified against the host of the
+// connection string, which the PostgreSQL driver does not let another name replace.
+func applyPostgresTLS(connString string, options tlsOptions) (string, error) {
+	if options.ServerName != "" {
+		return "", fmt.Errorf("%w: tls_server_name (postgres)", ErrTLSOptionUnsupported)
+	}
+
+	params := [][2]string{{"sslmode", "verify-full"}}
+	if options.SkipVerify {
+		params[0][1] = "require"
+	} else if options.CAFile != "" {
+		params = append(params, [2]string{"sslrootcert", options.CAFile})
+	}
+	if options.CertFile != "" {
+		params = append(params, [2]string{"sslcert", options.CertFile}, [2]string{"sslkey", options.KeyFile})
+	}
+
+	return setPostgresParams(connString, params)
+}
+
+// setPostgresParams sets parameters of a PostgreSQL connection string, in the query of a
+// URL or after the settings of a key=value connection string, where the last value wins
+func setPostgresParams(connString string, params [][2]string) (string, error) {
+	if isPostgresURL(connString) {
+		u, err := url.Parse(connString)
+		if err != nil {
+			return "", err
+		}
+		query := u.Query()
+		for _, param := range params {
+			query.Set(param[0], param[1])
+		}
+		u.RawQuery = query.Encode()
+		return u.String(), nil
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.TrimSpace(connString))
+	for _, param := range params {
+		value := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(param[1])
+		fmt.Fprintf(&b, " %s='%s'", param[0], value)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// isPostgresURL reports whether a PostgreSQL connection string is a URL rather than
+// key=value settings
+func isPostgresURL(connString string) bool {
+	lower := strings.ToLower(connString)
+	return strings.HasPrefix(lower, "postgres://") || strings.HasPrefix(lower, "postgresql://")
+}
+
+// applyMySQLTLS registers a tls.Config with the MySQL driver and names it in the tls
+// parameter. Configs are named after their options, so connecting again with the same
+// options reuses the name, reading the certificate files again.
+func applyMySQLTLS(connString string, options tlsOptions) (string, error) {
+	config, err := mysql.ParseDSN(connString)
+	if err != nil {
+		return "", err
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         options.ServerName,
+		InsecureSkipVerify: options.SkipVerify,
+	}
+	if options.CAFile != "" && !options.SkipVerify {
+		pem, err := os.ReadFile(options.CAFile)
+		if err != nil {
+			return "", err
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return "", fmt.Errorf("%w: %s", ErrInvalidTLSCertificate, options.CAFile)
+		}
+	}
+	if options.CertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(options.CertFile, options.KeyFile)
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrInvalidTLSCertificate, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%+v", options)))
+	name := "db-mcp-" + hex.EncodeToString(sum[:8])
+	if err = mysql.RegisterTLSConfig(name, tlsConfig); err != nil {
+		return "", err
+	}
+	config.TLS = nil
+	config.TLSConfig = name
+	return config.FormatDSN(), nil
+}
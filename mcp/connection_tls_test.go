@@ -0,0 +1,129 @@
+package mcp
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApplyTLS(t *testing.T) {
+	tests := []struct {
+		name       string
+		driver     DriverType
+		connString string
+		options    tlsOptions
+		want       string
+		wantErr    error
+	}{
+		{
+			name:       "disabled",
+			driver:     DriverPostgresSQL,
+			connString: "host=h",
+			want:       "host=h",
+		},
+		{
+			name:       "sqlserver url keeps its certificate and host name",
+			driver:     DriverSQLServer,
+			connString: "sqlserver://u:p@h?database=d&certificate=/etc/ca.pem&hostnameincertificate=db.corp",
+			options:    tlsOptions{Enabled: true},
+			want:       "sqlserver://u:p@h?certificate=%2Fetc%2Fca.pem&database=d&encrypt=true&hostnameincertificate=db.corp&trustservercertificate=false",
+		},
+		{
+			name:       "sqlserver settings keep their certificate and host name",
+			driver:     DriverSQLServer,
+			connString: "server=h;user id=u;certificate=/etc/ca.pem;hostnameincertificate=db.corp",
+			options:    tlsOptions{Enabled: true},
+			want:       "server=h;user id=u;certificate=/etc/ca.pem;hostnameincertificate=db.corp;encrypt=true;trustservercertificate=false",
+		},
+		{
+			name:       "sqlserver options",
+			driver:     DriverSQLServer,
+			connString: "sqlserver://u:p@h?database=d",
+			options:    tlsOptions{CAFile: "/ca.pem", ServerName: "db.corp"},
+			want:       "sqlserver://u:p@h?certificate=%2Fca.pem&database=d&encrypt=true&hostnameincertificate=db.corp&trustservercertificate=false",
+		},
+		{
+			name:       "sqlserver skip verify",
+			driver:     DriverSQLServer,
+			connString: "server=h;user id=u",
+			options:    tlsOptions{SkipVerify: true},
+			want:       "server=h;user id=u;encrypt=true;trustservercertificate=true",
+		},
+		{
+			name:       "sqlserver client certificate",
+			driver:     DriverSQLServer,
+			connString: "server=h",
+			options:    tlsOptions{CertFile: "/c.pem", KeyFile: "/k.pem"},
+			wantErr:    ErrTLSOptionUnsupported,
+		},
+		{
+			name:       "postgres url",
+			driver:     DriverPostgresSQL,
+			connString: "postgres://u@h/db",
+			options:    tlsOptions{CAFile: "/ca.pem"},
+			want:       "postgres://u@h/db?sslmode=verify-full&sslrootcert=%2Fca.pem",
+		},
+		{
+			name:       "postgres settings",
+			driver:     DriverPostgresSQL,
+			connString: "host=h user=u",
+			options:    tlsOptions{SkipVerify: true, CertFile: "/c.pem", KeyFile: "/k.pem"},
+			want:       "host=h user=u sslmode='require' sslcert='/c.pem' sslkey='/k.pem'",
+		},
+		{
+			name:       "postgres server name",
+			driver:     DriverPostgresSQL,
+			connString: "host=h",
+			options:    tlsOptions{ServerName: "db.corp"},
+			wantErr:    ErrTLSOptionUnsupported,
+		},
+		{
+			name:       "certificate without key",
+			driver:     DriverMySQL,
+			connString: "u:p@tcp(h:3306)/db",
+			options:    tlsOptions{CertFile: "/c.pem"},
+			wantErr:    ErrTLSClientKeyRequired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := applyTLS(string(tt.driver), tt.connString, tt.options)
+			switch {
+			case tt.wantErr != nil && !errors.Is(err, tt.wantErr):
+				t.Errorf("applyTLS() error = %v, want %v", err, tt.wantErr)
+			case tt.wantErr == nil && err != nil:
+				t.Errorf("applyTLS() error = %v", err)
+			case got != tt.want:
+				t.Errorf("applyTLS() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyMySQLTLS(t *testing.T) {
+	got, err := applyTLS(string(DriverMySQL), "u:p@tcp(h:3306)/db", tlsOptions{SkipVerify: true})
+	if err != nil || !strings.HasPrefix(got, "u:p@tcp(h:3306)/db?tls=db-mcp-") {
+		t.Errorf("applyTLS() = %q, %v, want a registered tls config", got, err)
+	}
+
+	ca := filepath.Join(t.TempDir(), "ca.pem")
+	if err = os.WriteFile(ca, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = applyTLS(string(DriverMySQL), "u:p@tcp(h:3306)/db", tlsOptions{CAFile: ca}); !errors.Is(err, ErrInvalidTLSCertificate) {
+		t.Errorf("applyTLS() with an invalid CA error = %v, want %v", err, ErrInvalidTLSCertificate)
+	}
+}
+
+func TestResolveTLSUnsupportedDriver(t *testing.T) {
+	enabled := true
+	if _, _, err := resolveTLS(string(DriverSQLite), "file.db", tlsArgs{TLS: &enabled}); !errors.Is(err, ErrTLSUnsupportedDriver) {
+		t.Errorf("resolveTLS() error = %v, want %v", err, ErrTLSUnsupportedDriver)
+	}
+	t.Setenv("DB_TLS", "true")
+	if got, _, err := resolveTLS(string(DriverSQLite), "file.db", tlsArgs{}); err != nil || got != "file.db" {
+		t.Errorf("resolveTLS() = %q, %v, want the environment options ignored", got, err)
+	}
+}
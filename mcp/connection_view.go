@@ -0,0 +1,166 @@
+package mcp
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// The default connection and each named connection are served by a view of the server, whose
+// pool and query builder never change once it serves calls: configure_datasource,
+// disconnect_datasource and the rotation of DB_SECRET_SOURCE replace the view instead. A call
+// holds the view it runs on until it returns, and the pool of a replaced view is closed after
+// the last call holding it, so no call sees its pool closed or swapped while it runs.
+
+// connectionView is a view of the server with the calls running on it
+type connectionView struct {
+	server  *DbMCPServer
+	mu      sync.Mutex
+	calls   int
+	retired bool
+}
+
+// acquire counts a call running on the view. The caller holds the lock of the view's owner,
+// so the view cannot be retired between finding and acquiring it.
+func (v *connectionView) acquire() {
+	v.mu.Lock()
+	v.calls++
+	v.mu.Unlock()
+}
+
+// release ends a call on the view, closing the pool of a retired view after its last call
+func (v *connectionView) release() {
+	v.mu.Lock()
+	v.calls--
+	idle := v.retired && v.calls == 0
+	v.mu.Unlock()
+
+	if idle {
+		if err := v.closePool(); err != nil {
+			log.Printf("Warning: Could not close the pool of a replaced connection: %v", err)
+		}
+	}
+}
+
+// retire marks a replaced view, closing its pool now when no call runs on it, or after the
+// last one otherwise
+func (v *connectionView) retire() error {
+	if v == nil {
+		return nil
+	}
+	v.mu.Lock()
+	idle := !v.retired && v.calls == 0
+	v.retired = true
+	v.mu.Unlock()
+
+	if idle {
+		return v.closePool()
+	}
+	return nil
+}
+
+// closePool closes the pool of the view, which a disconnected one has not
+func (v *connectionView) closePool() error {
+	if v.server.db == nil {
+		return nil
+	}
+	return v.server.db.Close()
+}
+
+// defaultConnection holds the view serving the default connection
+type defaultConnection struct {
+	mu   sync.RWMutex
+	view *connectionView
+}
+
+// acquire returns the view serving the default connection, and the function ending the call
+// on it
+func (d *defaultConnection) acquire() (*DbMCPServer, func()) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	d.view.acquire()
+	return d.view.server, d.view.release
+}
+
+// serves reports whether a view serves the default connection
+func (d *defaultConnection) serves(view *DbMCPServer) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.view.server == view
+}
+
+// replace serves the default connection with a new view and retires the previous one
+func (d *defaultConnection) replace(view *DbMCPServer) {
+	d.mu.Lock()
+	previous := d.view
+	d.view = &connectionView{server: view}
+	d.mu.Unlock()
+
+	previous.retire()
+}
+
+// swap replaces the view serving the default connection only while it is still current, so
+// a view replaced meanwhile by another call is not brought back
+func (d *defaultConnection) swap(current, view *DbMCPServer) bool {
+	d.mu.Lock()
+	previous := d.view
+	if previous.server != current {
+		d.mu.Unlock()
+		return false
+	}
+	d.view = &connectionView{server: view}
+	d.mu.Unlock()
+
+	previous.retire()
+	return true
+}
+
+// close retires the view serving the default connection when the server is closed
+func (d *defaultConnection) close() error {
+	d.mu.RLock()
+	view := d.view
+	d.mu.RUnlock()
+	return view.retire()
+}
+
+// newView returns a view of the server on a connection pool, which is nil for a disconnected
+// default connection: a copy sharing its settings, caches and watchdog, with the tools
+// registered against the pool. connection names the named connection it serves, or is ""
+// for the default one.
+func (s *DbMCPServer) newView(db *sql.DB, driver, connection string) *DbMCPServer {
+	view := *s
+	view.db = db
+	view.queryBuilder = nil
+	if driver != "" {
+		view.queryBuilder = NewQueryBuilder(driver)
+	}
+	view.connection = connection
+	if connection != "" {
+		view.largeTables = s.largeTables.withoutEstimates()
+	}
+	view.view = true
+	view.debugServer = nil
+	view.secrets = nil
+	view.server = server.NewMCPServer("Database MCP", "1.0.0", server.WithToolCapabilities(true))
+	view.registerTools()
+	return &view
+}
+
+// acquireConnection returns the view serving a connection, the default one for "" or its
+// name, and the function ending the call on it. Every call reading a database runs on the
+// view it returns.
+func (s *DbMCPServer) acquireConnection(name string) (*DbMCPServer, func(), error) {
+	if name == "" || strings.EqualFold(name, DefaultConnectionName) {
+		view, release := s.defaultConn.acquire()
+		return view, release, nil
+	}
+	view, release, ok := s.connections.acquire(name)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnknownConnection, name)
+	}
+	return view, release, nil
+}
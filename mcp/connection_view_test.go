@@ -0,0 +1,158 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// openTestPool returns a pool of a database that is never connected to
+func openTestPool(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("postgres", "host=127.0.0.1 port=1 user=test dbname=test sslmode=disable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// poolClosed reports whether a pool was closed
+func poolClosed(db *sql.DB) bool {
+	err := db.PingContext(context.Background())
+	return err != nil && strings.Contains(err.Error(), "database is closed")
+}
+
+func TestDefaultConnectionReplace(t *testing.T) {
+	first, second := openTestPool(t), openTestPool(t)
+	s, err := NewDbMCPServerWithDB(first, "postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	view, release := s.defaultConn.acquire()
+	if view != s {
+		t.Fatalf("the default connection is served by %p, want the server %p", view, s)
+	}
+	next := s.newView(second, "postgres", "")
+	s.defaultConn.replace(next)
+	if poolClosed(first) {
+		t.Error("the replaced pool was closed while a call runs on it")
+	}
+	if s.defaultConn.swap(s, s.newView(nil, "", "")) {
+		t.Error("swap() replaced a view that no longer serves the default connection")
+	}
+	release()
+	if !poolClosed(first) {
+		t.Error("the replaced pool was not closed after the last call on it")
+	}
+
+	if err = s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !poolClosed(second) {
+		t.Error("Close() did not close the pool of the default connection")
+	}
+}
+
+func TestNamedConnectionReplace(t *testing.T) {
+	s, err := NewDbMCPServerWithDB(openTestPool(t), "postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	first, second := openTestPool(t), openTestPool(t)
+	s.addConnection(first, ConnectionInfo{Name: "reports", Driver: "postgres"}, "test")
+	view, release, err := s.acquireConnection("REPORTS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.addConnection(second, ConnectionInfo{Name: "reports", Driver: "postgres"}, "test")
+	s.connections.remove(view)
+	if _, _, err = s.acquireConnection("reports"); err != nil {
+		t.Errorf("removing a replaced view dropped the connection that replaced it: %v", err)
+	}
+	if poolClosed(first) {
+		t.Error("the replaced pool was closed while a call runs on it")
+	}
+	release()
+	if !poolClosed(first) {
+		t.Error("the replaced pool was not closed after the last call on it")
+	}
+}
+
+// TestDisconnectWhileCalling checks that disconnect_datasource leaves the pool open to the
+// calls running on it, and closes it after them
+func TestDisconnectWhileCalling(t *testing.T) {
+	db := openTestPool(t)
+	s, err := NewDbMCPServerWithDB(db, "postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	var disconnect func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	for _, tool := range s.Tools() {
+		if tool.Tool.Name == "disconnect_datasource" {
+			disconnect = tool.Handler
+		}
+	}
+	if disconnect == nil {
+		t.Fatal("Tools() has no disconnect_datasource tool")
+	}
+
+	_, release := s.defaultConn.acquire()
+	var request mcp.CallToolRequest
+	request.Params.Name = "disconnect_datasource"
+	result, err := disconnect(context.Background(), request)
+	if err != nil || result.IsError {
+		t.Fatalf("disconnect_datasource = %+v, %v", result, err)
+	}
+	if s.IsConnected() {
+		t.Error("IsConnected() = true after disconnect_datasource")
+	}
+	if poolClosed(db) {
+		t.Error("disconnect_datasource closed the pool while a call runs on it")
+	}
+	release()
+	if !poolClosed(db) {
+		t.Error("the pool was not closed after the last call on it")
+	}
+}
+
+// TestDefaultConnectionConcurrent replaces the default connection while calls acquire it, for
+// the race detector
+func TestDefaultConnectionConcurrent(t *testing.T) {
+	s, err := NewDbMCPServerWithDB(openTestPool(t), "postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				view, release, err := s.acquireConnection("")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if view.db != nil && poolClosed(view.db) {
+					t.Error("a call acquired a closed pool")
+				}
+				release()
+			}
+		}()
+	}
+	for i := 0; i < 5; i++ {
+		s.defaultConn.replace(s.newView(openTestPool(t), "postgres", ""))
+	}
+	wg.Wait()
+}
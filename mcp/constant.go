@@ -8,11 +8,56 @@ const (
 	DBMaxIdleConns    = 5
 	DBConnMaxLifetime = 5 * time.Minute
 	DBPingTimeout     = 5 * time.Second
+	// DefaultDBIdleTimeout closes pooled connections unused for this long (DB_IDLE_TIMEOUT
+	// overrides it, 0 keeps them until DBConnMaxLifetime); the pool reopens them on demand
+	DefaultDBIdleTimeout = 2 * time.Minute
+)
+
+// Named connections: the name of the default connection in the connection argument and
+// list_connections, and the longest name of a named connection
+const (
+	DefaultConnectionName   = "default"
+	MaxConnectionNameLength = 64
+)
+
+// Secrets backends: the sources of DB_SECRET_SOURCE and the parts of the connection a
+// secret holds (DB_SECRET_TARGET)
+const (
+	SecretSourceVault            = "vault"
+	SecretSourceAWS              = "aws"
+	SecretSourceAzure            = "azure"
+	SecretTargetConnectionString = "connection_string"
+	SecretTargetPassword         = "password"
+)
+
+// Secrets backends: how often the secret is read again to follow its rotation
+// (DB_SECRET_REFRESH_INTERVAL overrides it, 0 disables it), how long a read may take, and
+// the version of the Azure Key Vault REST API
+const (
+	DefaultSecretRefreshInterval = 5 * time.Minute
+	SecretFetchTimeout           = 10 * time.Second
+	AzureKeyVaultAPIVersion      = "7.4"
+)
+
+// Azure SQL serverless auto-pause: login retry backoff and the time allowed to resume
+// when a connection is configured
+const (
+	AzureResumeInitialBackoff = time.Second
+	AzureResumeMaxBackoff     = 10 * time.Second
+	AzureResumeTimeout        = 90 * time.Second
+)
+
+// Query watermark: the comment prepended to every statement (DB_QUERY_WATERMARK overrides
+// the template, "off" disables it)
+const (
+	DefaultQueryWatermark   = "mcp session={session} tool={tool} user={user}"
+	MaxQueryWatermarkLength = 256
 )
 
 // Query validation constants
 const (
 	MaxQueryLength       = 10000 // 10KB - reduced from 50KB for DoS prevention
+	MaxQueryParameters   = 100
 	MaxSubqueryCount     = 10
 	MaxUnionCount        = 5
 	MaxParenthesesDepth  = 20
@@ -20,6 +65,17 @@ const (
 	MaxCharFunctionCount = 10
 )
 
+// Bounds of the query validation limits, set with DB_VALIDATION_POLICY_FILE or their
+// environment variables (DB_MAX_QUERY_LENGTH, DB_MAX_SUBQUERY_COUNT, ...)
+const (
+	MaxQueryLengthBound       = 1000000 // 1MB
+	MaxSubqueryCountBound     = 1000
+	MaxUnionCountBound        = 1000
+	MaxParenthesesDepthBound  = 200
+	MaxHexEncodingCountBound  = 1000
+	MaxCharFunctionCountBound = 1000
+)
+
 // Pagination constants
 const (
 	DefaultPage     = 1
@@ -28,10 +84,186 @@ const (
 	MaxRowsPageSize = 1000
 )
 
+// Table sample constants
+const (
+	DefaultSampleRows  = 10
+	MaxSampleRows      = 1000
+	SampleScanRows     = 100000 // random rows of larger tables come from a table sample
+	SampleOversampling = 10     // the table sample holds about this many times the rows asked
+)
+
+// Column profile constants
+const (
+	DefaultProfileTopValues = 10
+	MaxProfileTopValues     = 100
+)
+
+// Value search constants
+const (
+	DefaultFindValueRows = 20
+	MaxFindValueRows     = 1000
+)
+
+// Aggregation constants
+const (
+	DefaultAggregateGroups = 100
+	MaxAggregateGroups     = 1000
+)
+
+// Dependency graph constants
+const (
+	DefaultDependencyDepth = 1
+	MaxDependencyDepth     = 5
+	MaxDependencyNodes     = 200
+)
+
+// Definition search constants
+const (
+	DefaultDefinitionContextLines = 2
+	MaxDefinitionContextLines     = 10
+	MaxDefinitionObjects          = 100
+	MaxDefinitionMatchesPerObject = 20
+	MaxDefinitionLineLength       = 500
+)
+
+// Remote server constants
+const (
+	MaxForeignTablesPerServer = 100
+)
+
+// Entity relationship diagram constants
+const (
+	MaxERDTables = 100
+)
+
+// LargeTableEstimateTTL is how long the large-table guard keeps the catalog row estimates
+// of a schema before reading them again
+const LargeTableEstimateTTL = 10 * time.Minute
+
+// Schema overview constants
+const (
+	DefaultOverviewLargestTables = 10
+	MaxOverviewLargestTables     = 50
+)
+
 // Query timeout constants
 const (
 	DefaultQueryTimeout = 30 * time.Second
 	ShortQueryTimeout   = 10 * time.Second
+	// DefaultMaxQueryTimeout caps the timeout_seconds of a call (DB_MAX_QUERY_TIMEOUT overrides it)
+	DefaultMaxQueryTimeout = 5 * time.Minute
+	// Default and maximum time explain_query may run a query to get its actual plan
+	DefaultAnalyzeTimeout = 10 * time.Second
+	MaxAnalyzeTimeout     = 30 * time.Second
+)
+
+// JSON serialization constants
+const (
+	MaxIndentedJSONBytes     = 256 << 10 // larger payloads are returned compact
+	MaxPooledJSONBufferBytes = 4 << 20   // larger buffers are not returned to the pool
+)
+
+// Result memory constants
+const (
+	DefaultMaxResultBytes = 64 << 20 // 64MB
+	ResultRowOverhead     = 16       // approximate cost of a row
+	ResultEntryOverhead   = 16       // approximate cost of a cell
+	MaxBinaryValueBytes   = 1000     // binary values are truncated past it
+)
+
+// Result row constants
+const (
+	DefaultMaxResultRows = 10000 // cap of max_rows, overridable with DB_MAX_RESULT_ROWS
+	DefaultQueryRows     = 100   // rows returned by execute_query without max_rows
+)
+
+// Result cursors: execute_query with cursor keeps its result open for fetch_more until
+// it is read, CursorIdleTTL passes without a fetch or CursorLifetime after the query
+// started. Every open cursor holds a connection, so at most MaxOpenCursors are open.
+const (
+	CursorIdleTTL  = 5 * time.Minute
+	CursorLifetime = 30 * time.Minute
+	MaxOpenCursors = 5
+)
+
+// Listing tool previews: responses larger than DefaultPreviewBytes (DB_PREVIEW_BYTES
+// overrides it, 0 disables previews) keep PreviewItems items per list, and the full
+// response is kept for fetch_full
+const (
+	DefaultPreviewBytes = 32 << 10 // 32KB
+	PreviewItems        = 20
+	ResultHandleTTL     = 10 * time.Minute
+	MaxCachedResults    = 20
+)
+
+// DefaultQueryCacheSize is the number of execute_query responses the query cache keeps
+// (DB_QUERY_CACHE_SIZE overrides it)
+const DefaultQueryCacheSize = 100
+
+// Query export constants: export_query stops after MaxExportRows rows, writes row groups of
+// ExportRowGroupRows rows, and returns files without DB_EXPORT_DIR inline up to
+// MaxInlineExportBytes
+const (
+	MaxExportRows        = 1000000
+	ExportRowGroupRows   = 50000
+	MaxInlineExportBytes = 8 << 20 // 8MB
+	ExportQueryTimeout   = 5 * time.Minute
+)
+
+// MarkdownMaxColumnWidth is the widest a markdown table column is padded to; longer cells
+// overflow it
+const MarkdownMaxColumnWidth = 40
+
+// Write mode constants: a write call is rolled back once it affects more than
+// DefaultMaxAffectedRows rows (DB_MAX_AFFECTED_ROWS overrides it), and execute_insert takes
+// at most MaxInsertRows rows
+const (
+	DefaultMaxAffectedRows = 100
+	MaxInsertRows          = 1000
+)
+
+// MaxAccessLogEntries is the number of table accesses kept for table_access_report
+const MaxAccessLogEntries = 10000
+
+// Query history constants
+const (
+	DefaultQueryHistorySize   = 1000 // queries kept for get_query_history, DB_QUERY_HISTORY_SIZE
+	DefaultQueryHistoryLimit  = 50
+	MaxQueryHistoryQueryBytes = 2000 // normalized query text kept per entry
+)
+
+// StaleStatisticsPercent is the share of rows modified since statistics were last updated
+// above which list_statistics reports them as stale (the default autoanalyze and Oracle
+// STALE_PERCENT threshold)
+const StaleStatisticsPercent = 10
+
+// Query watchdog constants
+const (
+	WatchdogInterval       = time.Second
+	WatchdogSoftDuration   = 5 * time.Second
+	WatchdogSoftRows       = 10000
+	WatchdogLogQueryLength = 200
+)
+
+// Runtime diagnostics constants
+const (
+	DebugServerReadHeaderTimeout = 10 * time.Second
+	DebugServerShutdownTimeout   = 5 * time.Second
+	RuntimeStatsRecentPauses     = 10
+)
+
+// Quickstart recommended limits
+const (
+	QuickstartMaxResultBytes = 16 << 20 // 16MB
+	QuickstartHardTimeout    = time.Minute
+	QuickstartHardRows       = 100000
+)
+
+// Bench mode
+const (
+	DefaultBenchConcurrency = 4
+	DefaultBenchIterations  = 1
+	MaxBenchTraceLineBytes  = 4 << 20
 )
 
 // Drivers
@@ -43,6 +275,23 @@ const (
 	DriverSQLite      DriverType = "sqlite3"
 )
 
+// Database error categories
+const (
+	ErrorCategorySyntax      ErrorCategory = "syntax"
+	ErrorCategoryPermission  ErrorCategory = "permission"
+	ErrorCategoryTimeout     ErrorCategory = "timeout"
+	ErrorCategoryConstraint  ErrorCategory = "constraint"
+	ErrorCategoryUnavailable ErrorCategory = "unavailable"
+	ErrorCategoryUnknown     ErrorCategory = "unknown"
+)
+
+// SQL Server authentication modes
+const (
+	SQLServerAuthSQL        = "sql"
+	SQLServerAuthNTLM       = "ntlm"
+	SQLServerAuthIntegrated = "integrated"
+)
+
 // Default schema per driver
 const (
 	DefaultSchemaSQLServer = "dbo"
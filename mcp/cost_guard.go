@@ -0,0 +1,314 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// The cost guard asks the planner for the estimated plan of a query before running it, and
+// rejects queries whose estimated cost or rows exceed DB_MAX_QUERY_COST or
+// DB_MAX_ESTIMATED_ROWS, or only warns about them with DB_COST_GUARD_MODE=warn. Databases
+// without cost estimates (SQLite) and plans that cannot be read are not checked.
+
+// costGuard holds the planner thresholds over which queries are rejected or warned about
+type costGuard struct {
+	maxCost  float64
+	maxRows  int64
+	warnOnly bool
+}
+
+// queryEstimate is the planner estimate of a query: the total cost in the units of the
+// planner and the rows it expects to produce (on MySQL, to read in its largest scan)
+type queryEstimate struct {
+	Cost float64 `json:"cost"`
+	Rows float64 `json:"rows"`
+}
+
+// newCostGuard reads the cost guard thresholds from the environment
+func newCostGuard() costGuard {
+	guard := costGuard{
+		maxRows: envInt("DB_MAX_ESTIMATED_ROWS", 0),
+	}
+	if value := os.Getenv("DB_MAX_QUERY_COST"); value != "" {
+		cost, err := strconv.ParseFloat(value, 64)
+		if err != nil || cost < 0 {
+			log.Printf("Warning: Ignoring invalid DB_MAX_QUERY_COST=%q", value)
+		} else {
+			guard.maxCost = cost
+		}
+	}
+	switch mode := strings.ToLower(strings.TrimSpace(os.Getenv("DB_COST_GUARD_MODE"))); mode {
+	case "", "reject":
+	case "warn":
+		guard.warnOnly = true
+	default:
+		log.Printf("Warning: Ignoring invalid DB_COST_GUARD_MODE=%q", mode)
+	}
+	return guard
+}
+
+// enabled reports whether a threshold is configured
+func (g costGuard) enabled() bool {
+	return g.maxCost > 0 || g.maxRows > 0
+}
+
+// exceeded reports whether an estimate is over a threshold
+func (g costGuard) exceeded(estimate queryEstimate) bool {
+	return (g.maxCost > 0 && estimate.Cost > g.maxCost) ||
+		(g.maxRows > 0 && estimate.Rows > float64(g.maxRows))
+}
+
+// details returns an estimate with the thresholds it was checked against
+func (g costGuard) details(estimate queryEstimate) map[string]interface{} {
+	details := map[string]interface{}{
+		"estimate": estimate,
+	}
+	if g.maxCost > 0 {
+		details["max_cost"] = g.maxCost
+	}
+	if g.maxRows > 0 {
+		details["max_estimated_rows"] = g.maxRows
+	}
+	return details
+}
+
+// checkQueryCost runs the cost guard on a query before it is executed. It returns an error
+// result when the query is rejected, or the warning to add to the response when the guard
+// only warns; both are nil when the query is within the thresholds or cannot be estimated,
+// as statements such as SHOW or PRAGMA.
+func (s *DbMCPServer) checkQueryCost(ctx context.Context, query string, params []interface{}) (map[string]interface{}, *mcp.CallToolResult) {
+	if !s.costGuard.enabled() || !s.queryBuilder.IsSelectQuery(query) {
+		return nil, nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	estimate, ok, err := s.estimateQuery(ctx, query, params)
+	if err != nil {
+		log.Printf("Cost guard could not estimate the query: %v\nQuery: %s\n", err, query)
+		return nil, nil
+	}
+	if !ok || !s.costGuard.exceeded(estimate) {
+		return nil, nil
+	}
+
+	details := s.costGuard.details(estimate)
+	if s.costGuard.warnOnly {
+		log.Printf("Cost guard warning: estimated cost %.2f, rows %.0f\nQuery: %s\n", estimate.Cost, estimate.Rows, query)
+		details["message"] = translate("The estimated cost of the query exceeds the limit of the server; it ran anyway, but consider adding filters on indexed columns")
+		return details, nil
+	}
+
+	log.Printf("Query rejected by the cost guard: estimated cost %.2f, rows %.0f\nQuery: %s\n", estimate.Cost, estimate.Rows, query)
+	details["error"] = localizeError(ErrQueryTooExpensive)
+	details["code"] = "cost_limit"
+	details["hint"] = translate("Add filters on indexed columns or aggregate in the query, and check its plan with explain_query")
+	return nil, errorJSONResult(details, ErrQueryTooExpensive)
+}
+
+// queryWarnings returns the warnings of the cost and large-table guards to add to the
+// response of a query, by response key
+func queryWarnings(costWarning, largeTableWarning map[string]interface{}) map[string]interface{} {
+	warnings := map[string]interface{}{}
+	if costWarning != nil {
+		warnings["cost_warning"] = costWarning
+	}
+	if largeTableWarning != nil {
+		warnings["large_table_warning"] = largeTableWarning
+	}
+	return warnings
+}
+
+// estimateQuery returns the planner estimate of a query from its estimated plan, or false
+// if the database gives no cost estimate
+func (s *DbMCPServer) estimateQuery(ctx context.Context, query string, params []interface{}) (queryEstimate, bool, error) {
+	format := s.queryBuilder.ExplainFormat(false)
+	if format == "rows" {
+		return queryEstimate{}, false, nil
+	}
+	explain, setup, planQuery := s.queryBuilder.ExplainQuery(query)
+
+	// The setup statement changes the session, so its connection never returns to the pool
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return queryEstimate{}, false, err
+	}
+	if setup != "" {
+		defer discardConn(conn)
+		if _, err = conn.ExecContext(ctx, setup); err != nil {
+			return queryEstimate{}, false, err
+		}
+	} else {
+		defer conn.Close()
+	}
+
+	// A stored plan is explained without the bound values, which only running the query needs
+	var plan *planRows
+	if planQuery != "" {
+		if _, err = conn.ExecContext(ctx, explain); err == nil {
+			plan, err = queryPlan(ctx, conn, planQuery, nil)
+		}
+	} else {
+		plan, err = queryPlan(ctx, conn, explain, nil, params...)
+	}
+	if err != nil {
+		return queryEstimate{}, false, err
+	}
+
+	document := strings.Join(plan.lines, "\n")
+	switch format {
+	case "json":
+		return jsonPlanEstimate(document)
+	case "xml":
+		return showplanEstimate(document)
+	default:
+		return textPlanEstimate(plan.lines)
+	}
+}
+
+// jsonPlanEstimate reads the estimate of a Postgres or MySQL JSON plan: the total cost and
+// rows of the top Postgres node, or the query cost and largest scan of MySQL
+func jsonPlanEstimate(document string) (queryEstimate, bool, error) {
+	var postgres []struct {
+		Plan struct {
+			TotalCost float64 `json:"Total Cost"`
+			PlanRows  float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(document), &postgres); err == nil {
+		if len(postgres) == 0 {
+			return queryEstimate{}, false, nil
+		}
+		return queryEstimate{Cost: postgres[0].Plan.TotalCost, Rows: postgres[0].Plan.PlanRows}, true, nil
+	}
+
+	var mysql map[string]interface{}
+	if err := json.Unmarshal([]byte(document), &mysql); err != nil {
+		return queryEstimate{}, false, err
+	}
+	block, _ := mysql["query_block"].(map[string]interface{})
+	costInfo, _ := block["cost_info"].(map[string]interface{})
+	cost, ok := planNumber(costInfo["query_cost"])
+	if !ok {
+		return queryEstimate{}, false, nil
+	}
+	return queryEstimate{Cost: cost, Rows: largestScan(block)}, true, nil
+}
+
+// largestScan returns the largest rows_examined_per_scan of a MySQL JSON plan
+func largestScan(node interface{}) float64 {
+	largest := 0.0
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if key == "rows_examined_per_scan" {
+				if rows, ok := planNumber(value); ok {
+					largest = max(largest, rows)
+				}
+				continue
+			}
+			largest = max(largest, largestScan(value))
+		}
+	case []interface{}:
+		for _, value := range v {
+			largest = max(largest, largestScan(value))
+		}
+	}
+	return largest
+}
+
+// showplanEstimate reads the estimate of the first statement of a SQL Server SHOWPLAN_XML plan
+func showplanEstimate(document string) (queryEstimate, bool, error) {
+	cost := reShowplanCost.FindStringSubmatch(document)
+	rows := reShowplanRows.FindStringSubmatch(document)
+	if cost == nil || rows == nil {
+		return queryEstimate{}, false, nil
+	}
+	estimate := queryEstimate{}
+	var err error
+	if estimate.Cost, err = strconv.ParseFloat(cost[1], 64); err != nil {
+		return queryEstimate{}, false, err
+	}
+	if estimate.Rows, err = strconv.ParseFloat(rows[1], 64); err != nil {
+		return queryEstimate{}, false, err
+	}
+	return estimate, true, nil
+}
+
+// textPlanEstimate reads the estimate of the top operation of an Oracle DBMS_XPLAN table,
+// from its Rows and Cost (%CPU) columns
+func textPlanEstimate(lines []string) (queryEstimate, bool, error) {
+	rowsColumn, costColumn := -1, -1
+	for _, line := range lines {
+		cells := strings.Split(line, "|")
+		if rowsColumn < 0 {
+			for i, cell := range cells {
+				switch cell = strings.TrimSpace(cell); {
+				case cell == "Rows":
+					rowsColumn = i
+				case strings.HasPrefix(cell, "Cost"):
+					costColumn = i
+				}
+			}
+			if costColumn < 0 {
+				rowsColumn = -1
+			}
+			continue
+		}
+		if len(cells) <= max(rowsColumn, costColumn) || strings.Trim(cells[1], " *") != "0" {
+			continue
+		}
+		fields := strings.Fields(cells[costColumn])
+		if len(fields) == 0 {
+			return queryEstimate{}, false, nil
+		}
+		cost, ok := planNumber(fields[0])
+		if !ok {
+			return queryEstimate{}, false, nil
+		}
+		rows, _ := planNumber(strings.TrimSpace(cells[rowsColumn]))
+		return queryEstimate{Cost: cost, Rows: rows}, true, nil
+	}
+	return queryEstimate{}, false, nil
+}
+
+// planNumber reads a number of a plan, as a JSON number, an integer or a string that may end in the K,
+// M, G or T scale suffixes of DBMS_XPLAN
+func planNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case string:
+		scale := 1.0
+		if n := len(v); n > 0 {
+			switch v[n-1] {
+			case 'K':
+				scale = 1e3
+			case 'M':
+				scale = 1e6
+			case 'G':
+				scale = 1e9
+			case 'T':
+				scale = 1e12
+			}
+			if scale > 1 {
+				v = v[:n-1]
+			}
+		}
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, false
+		}
+		return n * scale, true
+	}
+	return 0, false
+}
@@ -0,0 +1,353 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// cursorStore keeps the open results of execute_query calls made with cursor, so fetch_more
+// can return them chunk by chunk. Every open cursor holds a database connection, so it is
+// closed once its rows are read, after CursorIdleTTL without a fetch or after CursorLifetime.
+type cursorStore struct {
+	mu      sync.Mutex
+	entries map[string]*resultCursor
+}
+
+// resultCursor is an open query result read by chunks
+type resultCursor struct {
+	mu           sync.Mutex
+	handle       string
+	columns      []string
+	rows         *sql.Rows
+	conn         *sql.Conn // dedicated snapshot connection, discarded on close
+	tx           *sql.Tx   // read-only transaction of the query, rolled back on close
+	watch        *watchedQuery
+	cancel       context.CancelFunc
+	encoder      *resultEncoder
+	values       []interface{}
+	valuePtrs    []interface{}
+	next         bool // rows.Next found a row that was not scanned yet
+	held         bool // values hold a row that did not fit in the memory budget of the last chunk
+	chunkRows    int
+	format       string
+	header       bool                   // the next chunk starts with a CSV header line
+	warnings     map[string]interface{} // warnings of the guards, by response key
+	read         int
+	database     string
+	databaseKind string
+	deadline     time.Time
+	idle         *time.Timer
+	closed       bool
+}
+
+// newCursorStore returns an empty cursor store
+func newCursorStore() *cursorStore {
+	return &cursorStore{entries: make(map[string]*resultCursor)}
+}
+
+// full reports whether MaxOpenCursors cursors are open
+func (c *cursorStore) full() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries) >= MaxOpenCursors
+}
+
+// put stores a cursor under a new handle and closes it when it is left idle
+func (c *cursorStore) put(cursor *resultCursor) {
+	handle := newResultHandle()
+
+	c.mu.Lock()
+	c.entries[handle] = cursor
+	c.mu.Unlock()
+
+	cursor.handle = handle
+	cursor.idle = time.AfterFunc(cursor.ttl(), func() { c.expire(handle) })
+}
+
+// get returns an open cursor
+func (c *cursorStore) get(handle string) (*resultCursor, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cursor, ok := c.entries[handle]
+	return cursor, ok
+}
+
+// remove forgets a cursor without closing it
+func (c *cursorStore) remove(handle string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, handle)
+}
+
+// expire forgets and closes a cursor
+func (c *cursorStore) expire(handle string) {
+	c.mu.Lock()
+	cursor, ok := c.entries[handle]
+	delete(c.entries, handle)
+	c.mu.Unlock()
+
+	if ok {
+		cursor.close()
+	}
+}
+
+// closeAll closes every open cursor
+func (c *cursorStore) closeAll() {
+	c.mu.Lock()
+	cursors := make([]*resultCursor, 0, len(c.entries))
+	for handle, cursor := range c.entries {
+		cursors = append(cursors, cursor)
+		delete(c.entries, handle)
+	}
+	c.mu.Unlock()
+
+	for _, cursor := range cursors {
+		cursor.close()
+	}
+}
+
+// ttl returns how long the cursor may stay idle before it is closed
+func (r *resultCursor) ttl() time.Duration {
+	if remaining := time.Until(r.deadline); remaining < CursorIdleTTL {
+		return remaining
+	}
+	return CursorIdleTTL
+}
+
+// close releases the rows and connection of the cursor
+func (r *resultCursor) close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closeLocked()
+}
+
+func (r *resultCursor) closeLocked() {
+	if r.closed {
+		return
+	}
+	r.closed = true
+	if r.idle != nil {
+		r.idle.Stop()
+	}
+	if r.rows != nil {
+		_ = r.rows.Close()
+	}
+	if r.tx != nil {
+		endReadOnly(r.tx)
+	}
+	r.watch.Done()
+	r.cancel()
+	if r.conn != nil {
+		discardConn(r.conn)
+	}
+}
+
+// fetchLocked reads up to maxRows rows within timeout and reports whether more remain. A chunk that
+// reaches the memory budget stops early and keeps its last row for the next chunk, unless
+// the row alone exceeds the budget, in which case it is dropped as execute_query does.
+func (r *resultCursor) fetchLocked(maxRows int, timeout time.Duration, budget *resultBudget) (*ResultSet, bool, error) {
+	// A slow chunk cancels the whole result, like a query over its timeout
+	timer := time.AfterFunc(timeout, r.cancel)
+	defer timer.Stop()
+
+	results := newResultSet(r.columns)
+	for results.Len() < maxRows {
+		if !r.held {
+			if !r.next && !r.rows.Next() {
+				break
+			}
+			r.next = false
+			if err := r.rows.Scan(r.valuePtrs...); err != nil {
+				return nil, false, ErrReadingRow
+			}
+			r.encoder.encode(r.values)
+		}
+		r.held = false
+
+		if !budget.Add(r.values) {
+			if results.Len() > 0 {
+				r.held = true
+				return results, true, nil
+			}
+			continue
+		}
+		results.AppendRow(r.values)
+		r.read++
+		r.watch.Row()
+	}
+
+	if err := r.watch.Err(); err != nil {
+		return nil, false, err
+	}
+	if results.Len() >= maxRows && r.rows.Next() {
+		r.next = true
+		return results, true, nil
+	}
+	if err := r.rows.Err(); err != nil {
+		return nil, false, ErrReadingResults
+	}
+	return results, false, nil
+}
+
+// openQueryCursor runs a validated query whose result stays open for fetch_more and
+// returns its first chunk. The query outlives the tool call, so it runs on its own context.
+func (s *DbMCPServer) openQueryCursor(query string, params []interface{}, maxRows int, timeout time.Duration, database, format string, header bool, warnings map[string]interface{}) (*mcp.CallToolResult, error) {
+	if s.cursors.full() {
+		return toolErrorResult(ErrTooManyCursors), nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), CursorLifetime)
+	cursor := &resultCursor{
+		cancel:    cancel,
+		chunkRows: maxRows,
+		format:    format,
+		header:    header,
+		warnings:  warnings,
+		database:  database,
+		deadline:  time.Now().Add(CursorLifetime),
+	}
+
+	var err error
+	if database != "" {
+		if cursor.conn, cursor.databaseKind, err = s.snapshotConn(ctx, database); err != nil {
+			cancel()
+			return toolErrorResult(err), nil
+		}
+	}
+
+	if cursor.tx, err = s.beginReadOnly(ctx, cursor.conn); err != nil {
+		result := s.dbErrorResult(ErrExecutingQuery, err)
+		cancel()
+		if cursor.conn != nil {
+			discardConn(cursor.conn)
+		}
+		return result, nil
+	}
+
+	ctx, cursor.watch = s.watchdog.Watch(ctx, "execute_query", query)
+
+	cursor.rows, err = cursor.tx.QueryContext(ctx, query, params...)
+	if err != nil {
+		result := s.queryErrorResult(query, cursor.watch, err)
+		cursor.close()
+		return result, nil
+	}
+
+	if cursor.columns, err = cursor.rows.Columns(); err != nil {
+		cursor.close()
+		return toolErrorResult(ErrRetrievingColumns), nil
+	}
+	cursor.encoder = newResultEncoder(cursor.rows)
+	cursor.values = make([]interface{}, len(cursor.columns))
+	cursor.valuePtrs = make([]interface{}, len(cursor.columns))
+	for i := range cursor.values {
+		cursor.valuePtrs[i] = &cursor.values[i]
+	}
+
+	return s.cursorChunk(cursor, maxRows, timeout), nil
+}
+
+// cursorChunk returns the next chunk of a cursor, storing the cursor while rows remain and
+// closing it once they are read
+func (s *DbMCPServer) cursorChunk(cursor *resultCursor, maxRows int, timeout time.Duration) *mcp.CallToolResult {
+	cursor.mu.Lock()
+	defer cursor.mu.Unlock()
+
+	if cursor.closed {
+		return toolErrorResult(ErrCursorNotFound)
+	}
+
+	budget := s.newResultBudget()
+	results, more, err := cursor.fetchLocked(maxRows, timeout, budget)
+	if err != nil {
+		s.cursors.remove(cursor.handle)
+		cursor.closeLocked()
+		return toolErrorResult(err)
+	}
+
+	response := map[string]interface{}{
+		"row_count": results.Len(),
+		"columns":   cursor.columns,
+		"rows_read": cursor.read,
+		"max_rows":  maxRows,
+		"has_more":  more,
+	}
+	// Only the first chunk has a header, so the CSV chunks can be concatenated
+	if err = setResultRows(response, results, cursor.format, cursor.header); err != nil {
+		s.cursors.remove(cursor.handle)
+		cursor.closeLocked()
+		return toolErrorResult(ErrReadingResults)
+	}
+	cursor.header = false
+	// Only the first chunk carries the warnings of the query
+	for key, warning := range cursor.warnings {
+		response[key] = warning
+	}
+	cursor.warnings = nil
+	if cursor.database != "" {
+		response["database"] = cursor.database
+		response["database_kind"] = cursor.databaseKind
+	}
+	budget.Annotate(response)
+
+	if !more {
+		s.cursors.remove(cursor.handle)
+		cursor.closeLocked()
+		return queryToolResult(response, cursor.format)
+	}
+
+	if cursor.handle == "" {
+		s.cursors.put(cursor)
+	} else {
+		cursor.idle.Reset(cursor.ttl())
+	}
+	response["cursor"] = cursor.handle
+	response["expires_at"] = time.Now().Add(cursor.ttl()).Format(time.RFC3339)
+	response["message"] = translate("More rows remain. Call fetch_more with the cursor for the next chunk, or with close to release it")
+	return queryToolResult(response, cursor.format)
+}
+
+// fetchMoreArgs are the arguments of fetch_more
+type fetchMoreArgs struct {
+	Cursor  string `json:"cursor" jsonschema_description:"Cursor returned by execute_query or a previous fetch_more"`
+	MaxRows int    `json:"max_rows,omitempty" jsonschema_description:"Maximum number of rows of the chunk (default: max_rows of the execute_query call, maximum: DB_MAX_RESULT_ROWS)"`
+	Close   bool   `json:"close,omitempty" jsonschema_description:"Close the cursor without reading more rows (default: false)"`
+}
+
+func (s *DbMCPServer) toolFetchMore() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("fetch_more", "Returns the next chunk of rows of an execute_query result opened with cursor. The cursor is closed once every row is read, after 5 minutes without a fetch or 30 minutes after the query started; close it early when the remaining rows are not needed", s.handleFetchMore)
+}
+
+func (s *DbMCPServer) handleFetchMore(ctx context.Context, request mcp.CallToolRequest, args fetchMoreArgs) (*mcp.CallToolResult, error) {
+	if args.Cursor == "" {
+		return toolErrorResult(ErrCursorRequired), nil
+	}
+
+	cursor, ok := s.cursors.get(args.Cursor)
+	if !ok {
+		return toolErrorResult(ErrCursorNotFound), nil
+	}
+
+	if args.Close {
+		s.cursors.expire(args.Cursor)
+		return jsonToolResult(map[string]interface{}{
+			"cursor": args.Cursor,
+			"closed": true,
+		}), nil
+	}
+
+	maxRows := args.MaxRows
+	if maxRows <= 0 {
+		maxRows = cursor.chunkRows
+	}
+	if maxRows > s.maxResultRows {
+		maxRows = s.maxResultRows
+	}
+
+	return s.cursorChunk(cursor, maxRows, queryTimeout(ctx, DefaultQueryTimeout)), nil
+}
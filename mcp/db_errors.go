@@ -0,0 +1,444 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	mssql "github.com/denisenkom/go-mssqldb"
+	"github.com/go-sql-driver/mysql"
+	"github.com/godror/godror"
+	"github.com/lib/pq"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mattn/go-sqlite3"
+)
+
+// vendorError holds the driver-specific code and message of a database error
+type vendorError struct {
+	Code    string
+	Message string
+}
+
+// extractVendorError unwraps the driver-specific error types into a vendor code and message
+func extractVendorError(err error) (vendorError, bool) {
+	var msErr mssql.Error
+	if errors.As(err, &msErr) {
+		return vendorError{Code: strconv.Itoa(int(msErr.Number)), Message: msErr.Message}, true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return vendorError{Code: string(pqErr.Code), Message: pqErr.Message}, true
+	}
+
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		return vendorError{Code: strconv.Itoa(int(myErr.Number)), Message: myErr.Message}, true
+	}
+
+	if oraErr, ok := godror.AsOraErr(err); ok {
+		return vendorError{Code: fmt.Sprintf("ORA-%05d", oraErr.Code()), Message: oraErr.Message()}, true
+	}
+
+	var liteErr sqlite3.Error
+	if errors.As(err, &liteErr) {
+		return vendorError{Code: strconv.Itoa(int(liteErr.Code)), Message: liteErr.Error()}, true
+	}
+
+	return vendorError{}, false
+}
+
+// PermissionError describes a permission denied by the database and the grant that would allow the operation
+type PermissionError struct {
+	Driver     DriverType
+	VendorCode string
+	Message    string
+	Object     string
+	Permission string
+	Grant      string
+}
+
+// Error returns the error message
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrPermissionDenied, e.Message)
+}
+
+// Unwrap allows errors.Is(err, ErrPermissionDenied)
+func (e *PermissionError) Unwrap() error {
+	return ErrPermissionDenied
+}
+
+// Hint returns a short instruction for the DBA
+func (e *PermissionError) Hint() string {
+	if e.Grant != "" {
+		return translate("Ask a DBA to run: ") + e.Grant
+	}
+	if e.Permission != "" {
+		return fmt.Sprintf(translate("Ask a DBA to grant the %s permission required for this operation"), e.Permission)
+	}
+	return translate("Ask a DBA to grant the permission required for this operation")
+}
+
+// asPermissionError maps a driver permission error to a PermissionError
+func asPermissionError(driver DriverType, err error) (*PermissionError, bool) {
+	vendorErr, ok := extractVendorError(err)
+	if !ok {
+		return nil, false
+	}
+
+	permErr := &PermissionError{
+		Driver:     driver,
+		VendorCode: vendorErr.Code,
+		Message:    vendorErr.Message,
+	}
+
+	switch driver {
+	case DriverSQLServer:
+		if !parseSQLServerPermissionError(permErr) {
+			return nil, false
+		}
+	case DriverPostgresSQL:
+		if !parsePostgresPermissionError(permErr) {
+			return nil, false
+		}
+	case DriverMySQL:
+		if !parseMySQLPermissionError(permErr) {
+			return nil, false
+		}
+	case DriverOracle:
+		if !parseOraclePermissionError(permErr) {
+			return nil, false
+		}
+	case DriverSQLite:
+		if permErr.VendorCode != strconv.Itoa(int(sqlite3.ErrPerm)) && permErr.VendorCode != strconv.Itoa(int(sqlite3.ErrAuth)) {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+
+	return permErr, true
+}
+
+func parseSQLServerPermissionError(e *PermissionError) bool {
+	switch e.VendorCode {
+	case "229", "230":
+		// The SELECT permission was denied on the object 'orders', database 'Shop', schema 'dbo'.
+		if m := reMSSQLObjectPermission.FindStringSubmatch(e.Message); m != nil {
+			e.Permission = strings.ToUpper(m[1])
+			e.Object = fmt.Sprintf("[%s].[%s].[%s]", m[3], m[4], m[2])
+			e.Grant = fmt.Sprintf("GRANT %s ON [%s].[%s] TO [<user>]", e.Permission, m[4], m[2])
+		}
+	case "300":
+		// VIEW SERVER STATE permission was denied on object 'server', database 'master'.
+		if m := reMSSQLServerPermission.FindStringSubmatch(e.Message); m != nil {
+			e.Permission = strings.ToUpper(m[1])
+			e.Object = m[2]
+			e.Grant = fmt.Sprintf("GRANT %s TO [<login>]", e.Permission)
+		}
+	case "916":
+		// The server principal "x" is not able to access the database "y" under the current security context.
+		if m := reMSSQLDatabaseAccess.FindStringSubmatch(e.Message); m != nil {
+			e.Permission = "CONNECT"
+			e.Object = m[2]
+			e.Grant = fmt.Sprintf("USE [%s]; CREATE USER [%s] FOR LOGIN [%s]", m[2], m[1], m[1])
+		}
+	case "262", "297", "8189", "15247":
+	default:
+		return false
+	}
+	return true
+}
+
+func parsePostgresPermissionError(e *PermissionError) bool {
+	if e.VendorCode != "42501" {
+		return false
+	}
+
+	// permission denied for table orders
+	m := rePostgresPermission.FindStringSubmatch(e.Message)
+	if m == nil {
+		return true
+	}
+
+	kind, object := strings.ToLower(m[1]), m[2]
+	e.Object = object
+	switch kind {
+	case "table", "relation", "view", "materialized view", "foreign table":
+		e.Permission = "SELECT"
+		e.Grant = fmt.Sprintf("GRANT SELECT ON %s TO <user>", object)
+	case "sequence":
+		e.Permission = "USAGE"
+		e.Grant = fmt.Sprintf("GRANT USAGE, SELECT ON SEQUENCE %s TO <user>", object)
+	case "schema":
+		e.Permission = "USAGE"
+		e.Grant = fmt.Sprintf("GRANT USAGE ON SCHEMA %s TO <user>", object)
+	case "function", "procedure":
+		e.Permission = "EXECUTE"
+		e.Grant = fmt.Sprintf("GRANT EXECUTE ON %s %s TO <user>", strings.ToUpper(kind), object)
+	case "database":
+		e.Permission = "CONNECT"
+		e.Grant = fmt.Sprintf("GRANT CONNECT ON DATABASE %s TO <user>", object)
+	}
+	return true
+}
+
+func parseMySQLPermissionError(e *PermissionError) bool {
+	switch e.VendorCode {
+	case "1142", "1143", "1370":
+		// SELECT command denied to user 'u'@'h' for table 'orders'
+		if m := reMySQLCommandDenied.FindStringSubmatch(e.Message); m != nil {
+			e.Permission = strings.ToUpper(m[1])
+			e.Object = strings.NewReplacer("`", "", "'", "").Replace(m[4])
+			if strings.EqualFold(m[3], "routine") {
+				e.Grant = fmt.Sprintf("GRANT EXECUTE ON PROCEDURE %s TO %s", e.Object, m[2])
+			} else {
+				e.Grant = fmt.Sprintf("GRANT %s ON %s TO %s", e.Permission, e.Object, m[2])
+			}
+		}
+	case "1044":
+		// Access denied for user 'u'@'h' to database 'shop'
+		if m := reMySQLDatabaseDenied.FindStringSubmatch(e.Message); m != nil {
+			e.Permission = "SELECT"
+			e.Object = m[2]
+			e.Grant = fmt.Sprintf("GRANT SELECT ON `%s`.* TO %s", m[2], m[1])
+		}
+	case "1227":
+		// Access denied; you need (at least one of) the PROCESS privilege(s) for this operation
+		if m := reMySQLPrivilegeNeeded.FindStringSubmatch(e.Message); m != nil {
+			e.Permission = strings.ToUpper(m[1])
+			e.Grant = fmt.Sprintf("GRANT %s ON *.* TO '<user>'@'<host>'", e.Permission)
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+func parseOraclePermissionError(e *PermissionError) bool {
+	switch e.VendorCode {
+	case "ORA-01031":
+	case "ORA-00942":
+		// Oracle reports objects without SELECT privilege as non-existent
+		e.Permission = "SELECT"
+		e.Message += translate(" (the object may exist but not be visible without the SELECT privilege)")
+	default:
+		return false
+	}
+	return true
+}
+
+// classifyError maps a database error to a driver-independent category
+func classifyError(driverType DriverType, err error) ErrorCategory {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return ErrorCategoryTimeout
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) {
+		return ErrorCategoryUnavailable
+	}
+	if _, ok := asPermissionError(driverType, err); ok {
+		return ErrorCategoryPermission
+	}
+
+	vendorErr, ok := extractVendorError(err)
+	if !ok {
+		return ErrorCategoryUnknown
+	}
+
+	switch driverType {
+	case DriverSQLServer:
+		return classifySQLServerError(vendorErr.Code)
+	case DriverPostgresSQL:
+		return classifyPostgresError(vendorErr.Code)
+	case DriverMySQL:
+		return classifyMySQLError(vendorErr.Code)
+	case DriverOracle:
+		return classifyOracleError(vendorErr.Code)
+	case DriverSQLite:
+		return classifySQLiteError(vendorErr.Code)
+	}
+	return ErrorCategoryUnknown
+}
+
+func classifySQLServerError(code string) ErrorCategory {
+	switch code {
+	case "102", "105", "156", "170", "207", "208", "245", "4104", "8114", "8115":
+		return ErrorCategorySyntax
+	case "18456", "4064":
+		return ErrorCategoryPermission
+	case "1222", "3617":
+		return ErrorCategoryTimeout
+	case "515", "547", "2601", "2627":
+		return ErrorCategoryConstraint
+	case "233", "1205", "4060", "10053", "10054", "40197", "40501", "40613":
+		return ErrorCategoryUnavailable
+	}
+	return ErrorCategoryUnknown
+}
+
+func classifyPostgresError(code string) ErrorCategory {
+	switch code {
+	case "57014", "55P03":
+		return ErrorCategoryTimeout
+	case "25006":
+		return ErrorCategoryPermission // write in a read-only transaction
+	case "40001", "40P01", "57P01", "57P02", "57P03":
+		return ErrorCategoryUnavailable
+	}
+
+	// Fall back to the SQLSTATE class
+	if len(code) < 2 {
+		return ErrorCategoryUnknown
+	}
+	switch code[:2] {
+	case "42", "22":
+		return ErrorCategorySyntax
+	case "28":
+		return ErrorCategoryPermission
+	case "23":
+		return ErrorCategoryConstraint
+	case "08", "53":
+		return ErrorCategoryUnavailable
+	}
+	return ErrorCategoryUnknown
+}
+
+func classifyMySQLError(code string) ErrorCategory {
+	switch code {
+	case "1054", "1064", "1146", "1149", "1241", "1366":
+		return ErrorCategorySyntax
+	case "1045", "1792":
+		return ErrorCategoryPermission
+	case "1205", "1317", "3024":
+		return ErrorCategoryTimeout
+	case "1048", "1062", "1451", "1452", "3819":
+		return ErrorCategoryConstraint
+	case "1040", "1053", "1213", "2002", "2003", "2006", "2013":
+		return ErrorCategoryUnavailable
+	}
+	return ErrorCategoryUnknown
+}
+
+func classifyOracleError(code string) ErrorCategory {
+	switch code {
+	case "ORA-00900", "ORA-00904", "ORA-00905", "ORA-00907", "ORA-00911", "ORA-00917", "ORA-00923", "ORA-00933", "ORA-00936", "ORA-01722":
+		return ErrorCategorySyntax
+	case "ORA-01017", "ORA-28000", "ORA-01456":
+		return ErrorCategoryPermission
+	case "ORA-00054", "ORA-01013", "ORA-30006":
+		return ErrorCategoryTimeout
+	case "ORA-00001", "ORA-01400", "ORA-02290", "ORA-02291", "ORA-02292":
+		return ErrorCategoryConstraint
+	case "ORA-00060", "ORA-03113", "ORA-03114", "ORA-03135", "ORA-12170", "ORA-12514", "ORA-12541":
+		return ErrorCategoryUnavailable
+	}
+	return ErrorCategoryUnknown
+}
+
+func classifySQLiteError(code string) ErrorCategory {
+	switch code {
+	case strconv.Itoa(int(sqlite3.ErrError)):
+		return ErrorCategorySyntax
+	case strconv.Itoa(int(sqlite3.ErrBusy)), strconv.Itoa(int(sqlite3.ErrLocked)), strconv.Itoa(int(sqlite3.ErrInterrupt)):
+		return ErrorCategoryTimeout
+	case strconv.Itoa(int(sqlite3.ErrConstraint)):
+		return ErrorCategoryConstraint
+	case strconv.Itoa(int(sqlite3.ErrIoErr)), strconv.Itoa(int(sqlite3.ErrCorrupt)), strconv.Itoa(int(sqlite3.ErrFull)), strconv.Itoa(int(sqlite3.ErrCantOpen)):
+		return ErrorCategoryUnavailable
+	}
+	return ErrorCategoryUnknown
+}
+
+// isRetryable reports whether an operation failing with this category may succeed when retried
+func (c ErrorCategory) isRetryable() bool {
+	return c == ErrorCategoryTimeout || c == ErrorCategoryUnavailable
+}
+
+// dbErrorResult builds the tool result for a failed database call.
+// Classified errors are returned as a structured payload with their category;
+// permission errors also include the grant hint.
+func (s *DbMCPServer) dbErrorResult(base error, err error) *mcp.CallToolResult {
+	if result, ok := s.structuredErrorResult(base, err); ok {
+		return result
+	}
+	return toolErrorResult(fmt.Errorf("%w: %w", base, err))
+}
+
+// structuredErrorResult returns the structured tool result for errors that can be classified
+func (s *DbMCPServer) structuredErrorResult(base error, err error) (*mcp.CallToolResult, bool) {
+	var driverType DriverType
+	if s.queryBuilder != nil {
+		driverType = s.queryBuilder.GetDriver()
+	}
+
+	if permErr, ok := asPermissionError(driverType, err); ok {
+		return permissionErrorResult(base, permErr), true
+	}
+
+	category := classifyError(driverType, err)
+	if category == ErrorCategoryUnknown {
+		return nil, false
+	}
+
+	response := map[string]interface{}{
+		"error":     localizeError(fmt.Errorf("%w: %w", base, err)),
+		"category":  string(category),
+		"retryable": category.isRetryable(),
+		"driver":    string(driverType),
+	}
+	if vendorErr, ok := extractVendorError(err); ok {
+		response["vendor_code"] = vendorErr.Code
+		response["message"] = vendorErr.Message
+	}
+	if isDatabaseResuming(err) {
+		response["hint"] = translate("The database was auto-paused (Azure SQL serverless) and is resuming - retry the call in a few seconds")
+	}
+
+	return errorJSONResult(response, err), true
+}
+
+// permissionErrorResult serializes a PermissionError as a tool error result
+func permissionErrorResult(base error, permErr *PermissionError) *mcp.CallToolResult {
+	response := map[string]interface{}{
+		"error":       localizeError(fmt.Errorf("%w: %w", base, ErrPermissionDenied)),
+		"code":        "permission_denied",
+		"category":    string(ErrorCategoryPermission),
+		"retryable":   false,
+		"driver":      string(permErr.Driver),
+		"vendor_code": permErr.VendorCode,
+		"message":     permErr.Message,
+		"hint":        permErr.Hint(),
+	}
+	if permErr.Object != "" {
+		response["object"] = permErr.Object
+	}
+	if permErr.Permission != "" {
+		response["permission"] = permErr.Permission
+	}
+	if permErr.Grant != "" {
+		response["grant"] = permErr.Grant
+	}
+
+	return errorJSONResult(response, permErr)
+}
+
+// errorJSONResult serializes a structured error response, falling back to the plain error
+func errorJSONResult(response map[string]interface{}, fallback error) *mcp.CallToolResult {
+	// Keep placeholders such as <user> readable in the output
+	buf := getJSONBuffer()
+	defer putJSONBuffer(buf)
+
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(response); err != nil {
+		return toolErrorResult(fallback)
+	}
+
+	return mcp.NewToolResultError(strings.TrimSpace(buf.String()))
+}
@@ -0,0 +1,149 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"log"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// expvarOnce guards the expvar names, which can only be published once per process
+var expvarOnce sync.Once
+
+// startDebugServer serves pprof and expvar on DB_DEBUG_ADDR (e.g. localhost:6060).
+// The endpoints are disabled unless the variable is set.
+func (s *DbMCPServer) startDebugServer() {
+	addr := os.Getenv("DB_DEBUG_ADDR")
+	if addr == "" {
+		return
+	}
+
+	expvarOnce.Do(func() {
+		expvar.Publish("db_pool", expvar.Func(func() interface{} {
+			view, release := s.defaultConn.acquire()
+			defer release()
+			return view.poolStats()
+		}))
+		expvar.Publish("queries_in_flight", expvar.Func(func() interface{} { return s.watchdog.InFlight() }))
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Printf("Warning: Could not start debug server on %s: %v", addr, err)
+		return
+	}
+
+	s.debugServer = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: DebugServerReadHeaderTimeout,
+	}
+	go func(srv *http.Server) {
+		if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Warning: Debug server stopped: %v", err)
+		}
+	}(s.debugServer)
+
+	log.Printf("Debug endpoints (pprof, expvar) listening on %s", listener.Addr())
+}
+
+// stopDebugServer shuts the debug server down if it is running
+func (s *DbMCPServer) stopDebugServer() {
+	if s.debugServer == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DebugServerShutdownTimeout)
+	defer cancel()
+
+	if err := s.debugServer.Shutdown(ctx); err != nil {
+		log.Printf("Warning: Could not stop debug server: %v", err)
+	}
+	s.debugServer = nil
+}
+
+// runtimeStats returns goroutine, heap and GC statistics of the server process
+func (s *DbMCPServer) runtimeStats() map[string]interface{} {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	// PauseNs is a circular buffer; the latest pause is at (NumGC+255)%256
+	pauses := int(m.NumGC)
+	if pauses > RuntimeStatsRecentPauses {
+		pauses = RuntimeStatsRecentPauses
+	}
+	recentPauses := make([]float64, pauses)
+	for i := 0; i < pauses; i++ {
+		idx := (int(m.NumGC) - 1 - i + len(m.PauseNs)) % len(m.PauseNs)
+		recentPauses[i] = durationMs(time.Duration(m.PauseNs[idx]))
+	}
+
+	var lastGC interface{}
+	if m.LastGC > 0 {
+		lastGC = time.Unix(0, int64(m.LastGC)).UTC().Format(time.RFC3339)
+	}
+
+	return map[string]interface{}{
+		"go_version":     runtime.Version(),
+		"uptime_seconds": int64(time.Since(s.started).Seconds()),
+		"goroutines":     runtime.NumGoroutine(),
+		"gomaxprocs":     runtime.GOMAXPROCS(0),
+		"num_cpu":        runtime.NumCPU(),
+		"heap": map[string]interface{}{
+			"alloc_bytes":    m.HeapAlloc,
+			"inuse_bytes":    m.HeapInuse,
+			"idle_bytes":     m.HeapIdle,
+			"released_bytes": m.HeapReleased,
+			"sys_bytes":      m.HeapSys,
+			"objects":        m.HeapObjects,
+			"total_sys":      m.Sys,
+		},
+		"gc": map[string]interface{}{
+			"num_gc":           m.NumGC,
+			"pause_total_ms":   durationMs(time.Duration(m.PauseTotalNs)),
+			"recent_pauses_ms": recentPauses,
+			"last_gc":          lastGC,
+			"next_gc_bytes":    m.NextGC,
+			"cpu_fraction":     m.GCCPUFraction,
+		},
+	}
+}
+
+// poolStats returns the connection pool statistics, or nil without a connection
+func (s *DbMCPServer) poolStats() map[string]interface{} {
+	if s.db == nil {
+		return nil
+	}
+
+	stats := s.db.Stats()
+	return map[string]interface{}{
+		"max_open":             stats.MaxOpenConnections,
+		"open":                 stats.OpenConnections,
+		"in_use":               stats.InUse,
+		"idle":                 stats.Idle,
+		"wait_count":           stats.WaitCount,
+		"wait_duration_ms":     durationMs(stats.WaitDuration),
+		"max_idle_closed":      stats.MaxIdleClosed,
+		"max_idle_time_closed": stats.MaxIdleTimeClosed,
+		"max_lifetime_closed":  stats.MaxLifetimeClosed,
+	}
+}
+
+// durationMs converts a duration to fractional milliseconds
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
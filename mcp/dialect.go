@@ -1,6 +1,9 @@
 package mcp
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // Dialect defines the interface for database-specific SQL generation
 type Dialect interface {
@@ -35,6 +38,14 @@ type Dialect interface {
 	// SupportsFeature checks if the dialect supports a specific feature
 	SupportsFeature(feature DialectFeature) bool
 
+	// SearchPathStatements returns the session statements that make unqualified names
+	// resolve against the given schema list
+	SearchPathStatements(searchPath []string) []string
+
+	// AsOfClause returns the clause placed after a table name to read it as it was at
+	// a point in time, or empty if the database has no time-travel queries
+	AsOfClause(asOf time.Time) string
+
 	// TableMetadata returns SQL components for table metadata queries
 	TableMetadata() TableMetadataSQL
 
@@ -50,8 +61,59 @@ type Dialect interface {
 	// TriggerMetadata returns SQL components for trigger metadata queries
 	TriggerMetadata() TriggerMetadataSQL
 
+	// SynonymMetadata returns SQL components for synonym metadata queries
+	SynonymMetadata() SynonymMetadataSQL
+
+	// TypeMetadata returns SQL components for user-defined type metadata queries
+	TypeMetadata() TypeMetadataSQL
+
+	// DependencyMetadata returns SQL components for object dependency queries
+	DependencyMetadata() DependencyMetadataSQL
+
+	// DefinitionSearch returns SQL components for searching object source code
+	DefinitionSearch() DefinitionSearchSQL
+
+	// PermissionMetadata returns SQL components for object permission queries
+	PermissionMetadata() PermissionMetadataSQL
+
+	// PrincipalMetadata returns SQL components for user and role queries
+	PrincipalMetadata() PrincipalMetadataSQL
+
+	// RemoteServerMetadata returns SQL components for linked server and foreign table queries
+	RemoteServerMetadata() RemoteServerMetadataSQL
+
+	// JobMetadata returns SQL components for scheduled job queries
+	JobMetadata() JobMetadataSQL
+
+	// ChangeTrackingMetadata returns SQL components for change capture status queries
+	ChangeTrackingMetadata() ChangeTrackingMetadataSQL
+
+	// TemporalMetadata returns SQL components for system-versioned table queries
+	TemporalMetadata() TemporalMetadataSQL
+
+	// ConstraintMetadata returns SQL components for constraint metadata queries
+	ConstraintMetadata() ConstraintMetadataSQL
+
 	// DatabaseInfo returns SQL for database information queries
 	DatabaseInfo() DatabaseInfoSQL
+
+	// Explain returns the SQL that returns the estimated plan of a query without running it
+	Explain() ExplainSQL
+
+	// ResultLimit returns the SQL that stops the database from producing more rows than asked
+	ResultLimit() ResultLimitSQL
+
+	// ParseCheck returns the SQL that checks a query against the database without running it
+	ParseCheck() ParseCheckSQL
+
+	// Sample returns the SQL that reads a few rows of a table for sample_table_data
+	Sample() SampleSQL
+
+	// ColumnProfile returns the SQL that profiles the values of a column for profile_column
+	ColumnProfile() ColumnProfileSQL
+
+	// SyntaxReference returns the SQL snippets of the dialect quick reference resource
+	SyntaxReference() SyntaxReferenceSQL
 }
 
 // DialectFeature represents a database feature
@@ -64,6 +126,10 @@ const (
 	FeatureViews
 	FeatureSchemas
 	FeatureILike
+	FeatureCrossDatabase
+	FeatureMaterializedViews
+	FeatureNamedParameters
+	FeatureReadOnlyTransactions
 )
 
 // TableMetadataSQL contains SQL templates for table operations
@@ -99,6 +165,133 @@ type TableMetadataSQL struct {
 
 	// GetForeignKeys query
 	GetForeignKeys string
+
+	// DDLColumns query for get_table_ddl, filtered by schema and table
+	// Columns: name, declared type (empty for computed columns), nullable (YES/NO), default,
+	// identity or generated clause
+	DDLColumns string
+
+	// DDLIndexes query for get_table_ddl: indexes not backing a key constraint, filtered by schema and table
+	// Columns: index name, unique (1/0), kind (e.g. CLUSTERED, BITMAP), method, quoted key column, descending (1/0), filter
+	DDLIndexes string
+
+	// DDLScript query for drivers that keep the CREATE statements of a table and its indexes
+	// Columns: statement
+	DDLScript string
+
+	// DictionaryTables base query for export_data_dictionary
+	// Columns: schema, table, comment
+	DictionaryTables string
+	// DictionaryTableSchemaFilter
+	DictionaryTableSchemaFilter string
+	// DictionaryTableOrderBy
+	DictionaryTableOrderBy string
+
+	// DictionaryColumns base query for export_data_dictionary
+	// Columns: schema, table, column, data type, nullable (YES/NO), default, comment, is primary key (1/0)
+	DictionaryColumns string
+	// DictionaryColumnSchemaFilter
+	DictionaryColumnSchemaFilter string
+	// DictionaryColumnTableFilter takes the placeholder list of table names
+	DictionaryColumnTableFilter string
+	// DictionaryColumnOrderBy
+	DictionaryColumnOrderBy string
+
+	// FindColumns base query for find_column, over the columns of tables and views
+	// Columns: schema, table, table type (table/view), column, data type, nullable (YES/NO), position
+	FindColumns string
+	// FindColumnName is the column name expression compared with the search pattern
+	FindColumnName string
+	// FindColumnSchemaFilter
+	FindColumnSchemaFilter string
+	// FindColumnOrderBy
+	FindColumnOrderBy string
+
+	// ObjectComment query for a table or view and its comment, filtered by schema and name
+	// (empty if the database has no comments). No row means the object does not exist.
+	// Columns: object type (table/view), comment
+	ObjectComment string
+	// ColumnComments query for the commented columns of a table or view, filtered by schema and name
+	// Columns: column, comment
+	ColumnComments string
+
+	// ColumnCollations query for the collatable columns of a table, filtered by schema and table
+	// (empty if columns have no collation). Columns: column, data type, collation (NULL when
+	// inherited), character set, default collation the column inherits from its table or database
+	ColumnCollations string
+
+	// TimeTravel query for whether a table can be read at a point in time and how far back,
+	// filtered by schema and table (empty if any table can be read back to the database limit)
+	// Columns: versioned (1/0), history retention in days (NULL when unlimited)
+	TimeTravel string
+
+	// TableStats base query from catalog statistics (empty if not supported)
+	// Columns: schema, table, approximate row count, data bytes, index bytes
+	TableStats string
+	// StatsSchemaFilter
+	StatsSchemaFilter string
+	// StatsTableFilter
+	StatsTableFilter string
+	// StatsGroupBy is appended after the filters
+	StatsGroupBy string
+	// StatsOrderBy
+	StatsOrderBy string
+
+	// IdentityStatus base query for the identity and auto-increment columns of tables and the
+	// sequences behind them (empty if not supported). Values are text, as they can exceed int64.
+	// Columns: schema, table, column, data type, seed, increment, current value (NULL before
+	// the first insert), lowest and highest value the column and its sequence can hold
+	IdentityStatus string
+	// IdentitySchemaFilter
+	IdentitySchemaFilter string
+	// IdentityTableFilter
+	IdentityTableFilter string
+	// IdentityOrderBy
+	IdentityOrderBy string
+
+	// ComputedColumns base query for computed and generated columns of tables (empty if not supported)
+	// Columns: schema, table, column, data type, expression, persisted (1/0)
+	ComputedColumns string
+	// ComputedExpressionIsTableSQL is set when the expression column holds the CREATE TABLE
+	// statement and the expression of each column must be extracted from it
+	ComputedExpressionIsTableSQL bool
+	// ComputedSchemaFilter
+	ComputedSchemaFilter string
+	// ComputedTableFilter
+	ComputedTableFilter string
+	// ComputedOrderBy
+	ComputedOrderBy string
+
+	// Statistics base query for the optimizer statistics of tables (empty if not supported)
+	// Columns: schema, table, statistics name (NULL for table-level statistics), columns,
+	// last updated, rows, rows sampled, modifications since the update, auto created (1/0)
+	Statistics string
+	// StatisticsSchemaFilter
+	StatisticsSchemaFilter string
+	// StatisticsTableFilter
+	StatisticsTableFilter string
+	// StatisticsOrderBy
+	StatisticsOrderBy string
+
+	// ListPartitions base query (empty if not supported)
+	// Columns: schema, partition name, position, bound, row count, scheme, function, strategy
+	ListPartitions string
+	// PartitionSchemaFilter
+	PartitionSchemaFilter string
+	// PartitionTableFilter
+	PartitionTableFilter string
+	// PartitionOrderBy
+	PartitionOrderBy string
+
+	// PartitionKeys base query
+	// Columns: schema, key column (or expression)
+	PartitionKeys string
+	// PartitionKeySchemaFilter
+	PartitionKeySchemaFilter string
+	// PartitionKeyTableFilter
+	PartitionKeyTableFilter string
+	// PartitionKeyOrderBy
+	PartitionKeyOrderBy string
 }
 
 // ProcedureMetadataSQL contains SQL templates for procedure operations
@@ -131,6 +324,10 @@ type FunctionMetadataSQL struct {
 	OrderBy string
 	// GetCode query
 	GetCode string
+	// GetParameters query taking schema and name (empty if not supported)
+	// Columns: signature, position, name, data type, direction (IN, OUT, INOUT, RETURN), default.
+	// The return type is the RETURN row at position 0; overloads differ in signature.
+	GetParameters string
 }
 
 // ViewMetadataSQL contains SQL templates for view operations
@@ -145,6 +342,16 @@ type ViewMetadataSQL struct {
 	OrderBy string
 	// GetDefinition query
 	GetDefinition string
+
+	// ListMaterializedViews base query
+	// Columns: schema, name, populated, refresh mode, last refresh, staleness, definition
+	ListMaterializedViews string
+	// MaterializedViewSchemaFilter
+	MaterializedViewSchemaFilter string
+	// MaterializedViewNameFilter
+	MaterializedViewNameFilter string
+	// MaterializedViewOrderBy
+	MaterializedViewOrderBy string
 }
 
 // TriggerMetadataSQL contains SQL templates for trigger operations
@@ -165,6 +372,211 @@ type TriggerMetadataSQL struct {
 	GetCode string
 }
 
+// SynonymMetadataSQL contains SQL templates for synonym (alias) operations
+type SynonymMetadataSQL struct {
+	// ListSynonyms base query (empty if not supported)
+	// Columns: schema, name, base object, server or db link, base object type
+	ListSynonyms string
+	// SchemaFilter
+	SchemaFilter string
+	// NameFilter
+	NameFilter string
+	// OrderBy
+	OrderBy string
+}
+
+// PermissionMetadataSQL contains SQL templates for object permission (grant) operations
+type PermissionMetadataSQL struct {
+	// ListObjectPermissions base query taking schema and object name (empty if not supported)
+	// Columns: grantee, grantee type, privilege, state (GRANT, GRANT_WITH_GRANT_OPTION, DENY),
+	// grantor, column (NULL unless column-level), scope (OBJECT, COLUMN, SCHEMA)
+	ListObjectPermissions string
+	// GranteeFilter
+	GranteeFilter string
+	// OrderBy
+	OrderBy string
+
+	// RLSPolicies base query for row-level security policies and the tables that enforce
+	// them (empty if not supported). Columns: schema, table, policy (NULL for a table with
+	// row-level security and no policy), enforced (1/0), forced on the owner (1/0, NULL if
+	// not applicable), kind, command, roles (NULL if the predicate decides), using
+	// expression, check expression
+	RLSPolicies string
+	// RLSSchemaFilter
+	RLSSchemaFilter string
+	// RLSTableFilter
+	RLSTableFilter string
+	// RLSOrderBy
+	RLSOrderBy string
+}
+
+// PrincipalMetadataSQL contains SQL templates for database users, roles and role membership
+type PrincipalMetadataSQL struct {
+	// ListPrincipals base query (empty if not supported)
+	// Columns: name, type, is_role (0/1), is_system (0/1), default schema, create date
+	ListPrincipals string
+	// NameFilter
+	NameFilter string
+	// OrderBy
+	OrderBy string
+	// ListRoleMembers lists role memberships. Columns: role name, member name
+	ListRoleMembers string
+}
+
+// RemoteServerMetadataSQL contains SQL templates for linked servers, foreign servers and
+// database links, and the foreign tables they back
+type RemoteServerMetadataSQL struct {
+	// ListServers base query (empty if not supported)
+	// Columns: name, kind, provider or wrapper, product, data source, catalog, options
+	ListServers string
+	// NameFilter
+	NameFilter string
+	// OrderBy
+	OrderBy string
+	// ListForeignTables lists local tables stored on a remote server (empty if not supported)
+	// Columns: server name, schema, name, remote schema, remote name, options
+	ListForeignTables string
+}
+
+// JobMetadataSQL contains SQL templates for the jobs of the schedulers running statements
+// in the database (SQL Server Agent, pg_cron, pgAgent, MySQL events, Oracle Scheduler)
+type JobMetadataSQL struct {
+	// Schedulers query for the schedulers installed and readable on the connection (empty if none)
+	// Columns: scheduler
+	Schedulers string
+	// ListJobs queries by scheduler, without ORDER BY
+	// Columns: job_name, enabled (1/0), schedule, command, last_run_at, last_run_status,
+	// last_run_message, next_run_at, database_name, owner, description
+	ListJobs map[string]string
+}
+
+// ChangeTrackingMetadataSQL contains SQL templates for the change capture mechanisms that sync
+// pipelines read from (SQL Server Change Tracking and CDC, Postgres logical replication)
+type ChangeTrackingMetadataSQL struct {
+	// Settings query for the database-wide settings (empty if not supported)
+	// Columns: setting, value
+	Settings string
+	// RestrictedSettings query for settings that need extra privileges; its errors are ignored
+	// Columns: setting, value
+	RestrictedSettings string
+	// ListTables queries the tables captured by each mechanism, without filters or ORDER BY
+	// Columns: schema_name, table_name, mechanism, name (publication, NULL if unnamed), details
+	ListTables string
+	// ListSlots query for the replication slots retaining changes (empty if not supported)
+	// Columns: name, plugin, type, active (1/0), database, retained bytes
+	ListSlots string
+}
+
+// TemporalMetadataSQL contains SQL templates for system-versioned (temporal) tables and their
+// history tables, built into SQL Server or added by Postgres extensions
+type TemporalMetadataSQL struct {
+	// Sources query for the temporal table implementations available on the connection (empty if none)
+	// Columns: source
+	Sources string
+	// ListTables queries by source, without filters or ORDER BY
+	// Columns: schema_name, table_name, history_schema, history_table, period_start, period_end,
+	// retention (NULL when the history is kept forever)
+	ListTables map[string]string
+}
+
+// DefinitionSearchSQL contains SQL templates for searching the source of views, routines and triggers
+type DefinitionSearchSQL struct {
+	// Search base query, taking the search term as parameter 1
+	// Columns: schema, name, kind, line, definition. Sources stored line by line (Oracle)
+	// return one row per line; the others return the whole definition as line 1.
+	Search string
+	// SchemaFilter
+	SchemaFilter string
+	// KindFilter takes the quoted IN list of kinds
+	KindFilter string
+	// OrderBy keeps the lines of each object together and in order
+	OrderBy string
+}
+
+// TypeMetadataSQL contains SQL templates for user-defined type operations
+type TypeMetadataSQL struct {
+	// ListTypes base query (empty if not supported)
+	// Columns: schema, name, kind, definition, is nullable
+	ListTypes string
+	// SchemaFilter
+	SchemaFilter string
+	// NameFilter
+	NameFilter string
+	// OrderBy
+	OrderBy string
+	// ListColumns lists the columns of table, composite and object types (empty if not supported).
+	// Filtered with SchemaFilter and NameFilter. Columns: schema, type name, column, data type, is nullable
+	ListColumns string
+	// ColumnsOrderBy keeps the columns of each type together and in order
+	ColumnsOrderBy string
+	// ListUsage lists the routine parameters and return values of each type (empty if not supported).
+	// Filtered with SchemaFilter and NameFilter. Columns: schema, type name, routine schema,
+	// routine name, routine type, parameter name (NULL for return values)
+	ListUsage string
+	// UsageOrderBy
+	UsageOrderBy string
+}
+
+// DependencyMetadataSQL contains SQL templates for object dependency operations.
+// Both queries take the object schema and name as parameters and return
+// schema, name, type and relation ("reference" or "foreign_key"); the query builder
+// sorts them by those columns.
+type DependencyMetadataSQL struct {
+	// Upstream query: objects the given object depends on
+	Upstream string
+	// Downstream query: objects depending on the given object
+	Downstream string
+}
+
+// ConstraintMetadataSQL contains SQL templates for constraint operations
+type ConstraintMetadataSQL struct {
+	// ListForeignKeys base query (one row per column pair)
+	// Columns: schema, table, constraint, column, referenced schema, referenced table,
+	// referenced column, on delete rule, on update rule
+	ListForeignKeys string
+	// ForeignKeySchemaFilter
+	ForeignKeySchemaFilter string
+	// ForeignKeyTableFilter
+	ForeignKeyTableFilter string
+	// ForeignKeyReferencedTableFilter
+	ForeignKeyReferencedTableFilter string
+	// ForeignKeyOrderBy
+	ForeignKeyOrderBy string
+
+	// ListKeyConstraints base query for primary keys and unique constraints (one row per column)
+	// Columns: schema, table, constraint, constraint type, column, position
+	ListKeyConstraints string
+	// KeySchemaFilter
+	KeySchemaFilter string
+	// KeyTableFilter
+	KeyTableFilter string
+	// KeyOrderBy
+	KeyOrderBy string
+
+	// ListCheckConstraints base query
+	// Columns: schema, table, constraint, column (NULL for table-level checks), definition
+	ListCheckConstraints string
+	// CheckDefinitionIsTableSQL is set when the definition column holds the CREATE TABLE
+	// statement and the CHECK clauses must be extracted from it
+	CheckDefinitionIsTableSQL bool
+	// CheckSchemaFilter
+	CheckSchemaFilter string
+	// CheckTableFilter
+	CheckTableFilter string
+	// CheckOrderBy
+	CheckOrderBy string
+
+	// ListDefaults base query for column default expressions
+	// Columns: schema, table, column, constraint (NULL when defaults are not named), definition
+	ListDefaults string
+	// DefaultSchemaFilter
+	DefaultSchemaFilter string
+	// DefaultTableFilter
+	DefaultTableFilter string
+	// DefaultOrderBy
+	DefaultOrderBy string
+}
+
 // DatabaseInfoSQL contains SQL for database info queries
 type DatabaseInfoSQL struct {
 	// Version query
@@ -175,10 +587,129 @@ type DatabaseInfoSQL struct {
 	ObjectCounts string
 	// ListSchemas query
 	ListSchemas string
+	// SchemaOverview query for the object counts and size of each user schema, without
+	// filters or ORDER BY. Columns: schema_name, tables, views, functions, procedures,
+	// total_bytes (NULL when the size cannot be read)
+	SchemaOverview string
+	// ListDatabases query (name, state, size in MB, collation)
+	ListDatabases string
+	// ListExtensions query (name, version, schema, default version, description; empty if not supported)
+	ListExtensions string
+	// ReadOnlyDatabase query returning the kind (snapshot, standby, read-only) of a database
+	// that cannot be written, and no row otherwise (empty if not supported)
+	ReadOnlyDatabase string
+	// Collation query for the default collation of the current database
+	// Columns: collation, character set, server collation (NULL when the server has none of its own)
+	Collation string
 	// SearchObjects query template
 	SearchObjects string
 }
 
+// ExplainSQL contains the statements that return the estimated execution plan of a query
+type ExplainSQL struct {
+	// Setup statement run on a dedicated connection before the query, which then returns
+	// its plan instead of running (empty if the prefix is enough)
+	Setup string
+	// Prefix prepended to the query
+	Prefix string
+	// PlanQuery reads the plan when the explain statement stores it instead of returning it
+	PlanQuery string
+	// Format of the plan: xml or json (one document), text (one line per row) or rows
+	Format string
+
+	// AnalyzeSetup, AnalyzePrefix, AnalyzePlanQuery and AnalyzeFormat are their counterparts
+	// for the plan with actual row counts and timings, which runs the query. The plan is the
+	// last result set of the statement, unless AnalyzePlanQuery reads it afterwards. An
+	// empty AnalyzeFormat means actual plans are not supported.
+	AnalyzeSetup     string
+	AnalyzePrefix    string
+	AnalyzePlanQuery string
+	AnalyzeFormat    string
+}
+
+// ResultLimitSQL contains the SQL that caps the rows a query produces, so the database stops
+// instead of streaming rows the server discards
+type ResultLimitSQL struct {
+	// SessionLimit caps the rows of the following queries of the session (formatted with the
+	// limit), and SessionReset lifts the cap before the connection returns to the pool
+	SessionLimit string
+	SessionReset string
+	// WrapLimit wraps a query to return at most a number of rows (formatted with the query
+	// and the limit), for databases without a session cap
+	WrapLimit string
+}
+
+// ParseCheckSQL contains the SQL that has the database parse a query and resolve its names
+// without running it. When both fields are empty the query is prepared, which the driver
+// sends to the server to be parsed.
+type ParseCheckSQL struct {
+	// Setup statement run on a dedicated connection before the query, which is then
+	// compiled instead of run
+	Setup string
+	// Statement parses the query, passed as its only parameter
+	Statement string
+}
+
+// SampleSQL contains the queries of sample_table_data. Each one is formatted with the
+// quoted column list, the qualified table name, the number of rows and, for TableSample,
+// the percentage of the table to read.
+type SampleSQL struct {
+	// First returns the first rows in storage order, without sorting
+	First string
+	// Random returns random rows, sorting the whole table
+	Random string
+	// TableSample returns random rows of a sample of the table, which large tables read
+	// instead of sorting every row (empty if the database has no table sampling)
+	TableSample string
+}
+
+// ColumnProfileSQL contains the query of profile_column, formatted with the quoted column,
+// the qualified table and the number of most frequent values. It returns one row per
+// frequent value, each with the row count, null count, distinct count, minimum, maximum and
+// average text length of the column, followed by the value and its frequency (both NULL
+// when the column holds only NULLs), most frequent first.
+type ColumnProfileSQL struct {
+	Query string
+	// ApproximateDistinct is set when the distinct count is an estimate
+	ApproximateDistinct bool
+}
+
+// SyntaxReferenceSQL contains the snippets of the syntax quick reference published as an
+// MCP resource for the connected database. Each field is an example expression or clause;
+// t is a table, d a date, s a string and a, b any values.
+type SyntaxReferenceSQL struct {
+	// Product name shown in the reference title
+	Product string
+
+	// Pagination
+	FirstRows string
+	Page      string
+
+	// Dates
+	CurrentTimestamp string
+	CurrentDate      string
+	AddDays          string
+	DaysBetween      string
+	Year             string
+	TruncateToMonth  string
+	FormatDate       string
+
+	// Strings
+	Concat          string
+	Substring       string
+	Length          string
+	Position        string
+	CaseInsensitive string
+	Aggregate       string
+
+	// Other
+	QuoteIdentifier    string
+	Coalesce           string
+	CastToText         string
+	SelectWithoutTable string
+	Parameter          string
+}
+
 // BaseDialect provides common functionality for all dialects
 type BaseDialect struct {
 	driver DriverType
@@ -189,12 +720,10 @@ func (d *BaseDialect) Driver() DriverType {
 	return d.driver
 }
 
-// NormalizeIdentifier default implementation - strips brackets if present
+// NormalizeIdentifier default implementation - strips the quotes of [name], "name" and
+// `name` identifiers, so catalog queries compare the name they stand for
 func (d *BaseDialect) NormalizeIdentifier(name string) string {
-	// Remove SQL Server style brackets [name] if present
-	if len(name) >= 2 && name[0] == '[' && name[len(name)-1] == ']' {
-		return name[1 : len(name)-1]
-	}
+	name, _ = unquoteIdentifier(name)
 	return name
 }
 
@@ -3,6 +3,7 @@ package mcp
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 // MySQLDialect implements Dialect for MySQL/MariaDB
@@ -22,9 +23,9 @@ func (d *MySQLDialect) Placeholder(index int) string {
 	return "?"
 }
 
-// QuoteIdentifier returns `name`
+// QuoteIdentifier returns `name`, with ` doubled
 func (d *MySQLDialect) QuoteIdentifier(name string) string {
-	return fmt.Sprintf("`%s`", name)
+	return quoteIdentifier(name, "`", "`")
 }
 
 // PaginationClause returns LIMIT/OFFSET syntax
@@ -47,6 +48,31 @@ func (d *MySQLDialect) CurrentDatabase() string {
 	return "DATABASE()"
 }
 
+// SearchPathStatements returns USE for the first entry (MySQL databases are schemas)
+func (d *MySQLDialect) SearchPathStatements(searchPath []string) []string {
+	if len(searchPath) == 0 {
+		return nil
+	}
+	return []string{"USE " + d.QuoteIdentifier(d.NormalizeIdentifier(searchPath[0]))}
+}
+
+// AsOfClause returns nothing (MySQL has no time-travel queries)
+func (d *MySQLDialect) AsOfClause(asOf time.Time) string {
+	return ""
+}
+
+// SupportsFeature checks MySQL feature support
+func (d *MySQLDialect) SupportsFeature(feature DialectFeature) bool {
+	switch feature {
+	case FeatureMaterializedViews:
+		return false
+	case FeatureNamedParameters:
+		return false // the driver binds ? placeholders by position only
+	default:
+		return true
+	}
+}
+
 // SystemSchemas returns MySQL system schemas
 func (d *MySQLDialect) SystemSchemas() []string {
 	return []string{"mysql", "information_schema", "performance_schema", "sys"}
@@ -149,7 +175,251 @@ func (d *MySQLDialect) TableMetadata() TableMetadataSQL {
 			WHERE kcu.TABLE_SCHEMA = ?
 				AND kcu.TABLE_NAME = ?
 				AND kcu.REFERENCED_TABLE_NAME IS NOT NULL
-			ORDER BY kcu.CONSTRAINT_NAME`,
+			ORDER BY kcu.CONSTRAINT_NAME, kcu.ORDINAL_POSITION`,
+
+		// COLUMN_DEFAULT holds the raw value: literals are quoted, expression defaults parenthesized
+		DDLColumns: `
+			SELECT
+				COLUMN_NAME,
+				COLUMN_TYPE,
+				IS_NULLABLE,
+				CASE
+					WHEN COLUMN_DEFAULT IS NULL OR COALESCE(GENERATION_EXPRESSION, '') <> '' THEN NULL
+					WHEN COLUMN_DEFAULT LIKE 'CURRENT_TIMESTAMP%' THEN COLUMN_DEFAULT
+					WHEN EXTRA LIKE '%DEFAULT_GENERATED%' THEN CONCAT('(', COLUMN_DEFAULT, ')')
+					WHEN DATA_TYPE IN ('tinyint', 'smallint', 'mediumint', 'int', 'bigint', 'decimal', 'float', 'double', 'bit') THEN COLUMN_DEFAULT
+					ELSE QUOTE(COLUMN_DEFAULT)
+				END AS column_default,
+				CASE
+					WHEN COALESCE(GENERATION_EXPRESSION, '') <> ''
+						THEN CONCAT('GENERATED ALWAYS AS (', GENERATION_EXPRESSION, ')', CASE WHEN EXTRA LIKE '%STORED%' THEN ' STORED' ELSE ' VIRTUAL' END)
+					WHEN EXTRA LIKE '%auto_increment%' THEN 'AUTO_INCREMENT'
+					WHEN EXTRA LIKE '%on update%' THEN UPPER(SUBSTRING(EXTRA, LOCATE('on update', EXTRA)))
+				END AS column_extra
+			FROM INFORMATION_SCHEMA.COLUMNS
+			WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
+			ORDER BY ORDINAL_POSITION`,
+
+		// Functional key parts (MySQL 8.0.13+) have no COLUMN_NAME and are left out
+		DDLIndexes: `
+			SELECT
+				s.INDEX_NAME,
+				CASE WHEN s.NON_UNIQUE = 0 THEN 1 ELSE 0 END AS is_unique,
+				CASE WHEN s.INDEX_TYPE IN ('FULLTEXT', 'SPATIAL') THEN s.INDEX_TYPE END AS index_kind,
+				NULL AS index_method,
+				CONCAT('` + "`" + `', s.COLUMN_NAME, '` + "`" + `', CASE WHEN s.SUB_PART IS NOT NULL THEN CONCAT('(', s.SUB_PART, ')') ELSE '' END) AS column_name,
+				CASE WHEN s.COLLATION = 'D' THEN 1 ELSE 0 END AS is_descending,
+				NULL AS filter
+			FROM INFORMATION_SCHEMA.STATISTICS s
+			WHERE s.TABLE_SCHEMA = ? AND s.TABLE_NAME = ?
+				AND s.COLUMN_NAME IS NOT NULL
+				AND NOT EXISTS (
+					SELECT 1 FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc
+					WHERE tc.TABLE_SCHEMA = s.TABLE_SCHEMA
+						AND tc.TABLE_NAME = s.TABLE_NAME
+						AND tc.CONSTRAINT_NAME = s.INDEX_NAME
+						AND tc.CONSTRAINT_TYPE IN ('PRIMARY KEY', 'UNIQUE'))
+			ORDER BY s.INDEX_NAME, s.SEQ_IN_INDEX`,
+
+		DictionaryTables: `
+			SELECT
+				TABLE_SCHEMA,
+				TABLE_NAME,
+				NULLIF(TABLE_COMMENT, '') AS table_comment
+			FROM INFORMATION_SCHEMA.TABLES
+			WHERE TABLE_TYPE = 'BASE TABLE'
+				AND TABLE_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')`,
+		DictionaryTableSchemaFilter: " AND TABLE_SCHEMA = %s",
+		DictionaryTableOrderBy:      " ORDER BY TABLE_SCHEMA, TABLE_NAME",
+
+		DictionaryColumns: `
+			SELECT
+				c.TABLE_SCHEMA,
+				c.TABLE_NAME,
+				c.COLUMN_NAME,
+				c.COLUMN_TYPE AS data_type,
+				c.IS_NULLABLE,
+				c.COLUMN_DEFAULT,
+				NULLIF(c.COLUMN_COMMENT, '') AS column_comment,
+				CASE WHEN c.COLUMN_KEY = 'PRI' THEN 1 ELSE 0 END AS is_primary_key
+			FROM INFORMATION_SCHEMA.COLUMNS c
+			JOIN INFORMATION_SCHEMA.TABLES t
+				ON t.TABLE_SCHEMA = c.TABLE_SCHEMA AND t.TABLE_NAME = c.TABLE_NAME
+			WHERE t.TABLE_TYPE = 'BASE TABLE'
+				AND c.TABLE_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')`,
+		DictionaryColumnSchemaFilter: " AND c.TABLE_SCHEMA = %s",
+		DictionaryColumnTableFilter:  " AND c.TABLE_NAME IN (%s)",
+		DictionaryColumnOrderBy:      " ORDER BY c.TABLE_SCHEMA, c.TABLE_NAME, c.ORDINAL_POSITION",
+
+		FindColumns: `
+			SELECT
+				c.TABLE_SCHEMA,
+				c.TABLE_NAME,
+				CASE t.TABLE_TYPE WHEN 'VIEW' THEN 'view' ELSE 'table' END AS table_type,
+				c.COLUMN_NAME,
+				c.COLUMN_TYPE AS data_type,
+				c.IS_NULLABLE,
+				c.ORDINAL_POSITION
+			FROM INFORMATION_SCHEMA.COLUMNS c
+			JOIN INFORMATION_SCHEMA.TABLES t
+				ON t.TABLE_SCHEMA = c.TABLE_SCHEMA AND t.TABLE_NAME = c.TABLE_NAME
+			WHERE t.TABLE_TYPE IN ('BASE TABLE', 'VIEW')
+				AND c.TABLE_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')`,
+		FindColumnName:         "c.COLUMN_NAME",
+		FindColumnSchemaFilter: " AND c.TABLE_SCHEMA = %s",
+		FindColumnOrderBy:      " ORDER BY c.TABLE_SCHEMA, c.TABLE_NAME, c.ORDINAL_POSITION",
+
+		ObjectComment: `
+			SELECT
+				CASE TABLE_TYPE WHEN 'VIEW' THEN 'view' ELSE 'table' END AS object_type,
+				CASE WHEN TABLE_TYPE = 'VIEW' THEN NULL ELSE NULLIF(TABLE_COMMENT, '') END AS comment
+			FROM INFORMATION_SCHEMA.TABLES
+			WHERE TABLE_SCHEMA = ?
+				AND TABLE_NAME = ?`,
+		ColumnComments: `
+			SELECT COLUMN_NAME, COLUMN_COMMENT
+			FROM INFORMATION_SCHEMA.COLUMNS
+			WHERE TABLE_SCHEMA = ?
+				AND TABLE_NAME = ?
+				AND COLUMN_COMMENT <> ''
+			ORDER BY ORDINAL_POSITION`,
+
+		// Columns always carry a collation; the table collation is the one they inherit
+		ColumnCollations: `
+			SELECT
+				c.COLUMN_NAME,
+				c.COLUMN_TYPE,
+				c.COLLATION_NAME,
+				c.CHARACTER_SET_NAME,
+				t.TABLE_COLLATION
+			FROM INFORMATION_SCHEMA.COLUMNS c
+			INNER JOIN INFORMATION_SCHEMA.TABLES t
+				ON t.TABLE_SCHEMA = c.TABLE_SCHEMA AND t.TABLE_NAME = c.TABLE_NAME
+			WHERE c.COLLATION_NAME IS NOT NULL
+				AND c.TABLE_SCHEMA = ?
+				AND c.TABLE_NAME = ?
+			ORDER BY c.ORDINAL_POSITION`,
+
+		TableStats: `
+			SELECT
+				TABLE_SCHEMA AS schema_name,
+				TABLE_NAME AS table_name,
+				TABLE_ROWS AS row_count,
+				DATA_LENGTH AS data_bytes,
+				INDEX_LENGTH AS index_bytes
+			FROM INFORMATION_SCHEMA.TABLES
+			WHERE TABLE_TYPE = 'BASE TABLE'
+				AND TABLE_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')`,
+		StatsSchemaFilter: " AND TABLE_SCHEMA = %s",
+		StatsTableFilter:  " AND TABLE_NAME = %s",
+		StatsOrderBy:      " ORDER BY TABLE_SCHEMA, TABLE_NAME",
+
+		// AUTO_INCREMENT is the next value, cached by MySQL 8 for information_schema_stats_expiry
+		IdentityStatus: `
+			SELECT
+				c.TABLE_SCHEMA AS schema_name,
+				c.TABLE_NAME AS table_name,
+				c.COLUMN_NAME AS column_name,
+				c.COLUMN_TYPE AS data_type,
+				CAST(@@auto_increment_offset AS CHAR) AS seed,
+				CAST(@@auto_increment_increment AS CHAR) AS increment,
+				CASE WHEN t.AUTO_INCREMENT > 1 THEN CAST(t.AUTO_INCREMENT - 1 AS CHAR) END AS current_value,
+				CASE WHEN c.COLUMN_TYPE LIKE '%unsigned%' THEN '0'
+					ELSE CASE c.DATA_TYPE
+						WHEN 'tinyint' THEN '-128'
+						WHEN 'smallint' THEN '-32768'
+						WHEN 'mediumint' THEN '-8388608'
+						WHEN 'int' THEN '-2147483648'
+						ELSE '-9223372036854775808'
+					END
+				END AS min_value,
+				CASE WHEN c.COLUMN_TYPE LIKE '%unsigned%'
+					THEN CASE c.DATA_TYPE
+						WHEN 'tinyint' THEN '255'
+						WHEN 'smallint' THEN '65535'
+						WHEN 'mediumint' THEN '16777215'
+						WHEN 'int' THEN '4294967295'
+						ELSE '18446744073709551615'
+					END
+					ELSE CASE c.DATA_TYPE
+						WHEN 'tinyint' THEN '127'
+						WHEN 'smallint' THEN '32767'
+						WHEN 'mediumint' THEN '8388607'
+						WHEN 'int' THEN '2147483647'
+						ELSE '9223372036854775807'
+					END
+				END AS max_value
+			FROM INFORMATION_SCHEMA.COLUMNS c
+			INNER JOIN INFORMATION_SCHEMA.TABLES t
+				ON t.TABLE_SCHEMA = c.TABLE_SCHEMA AND t.TABLE_NAME = c.TABLE_NAME
+			WHERE c.EXTRA LIKE '%auto_increment%'
+				AND c.TABLE_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')`,
+		IdentitySchemaFilter: " AND c.TABLE_SCHEMA = %s",
+		IdentityTableFilter:  " AND c.TABLE_NAME = %s",
+		IdentityOrderBy:      " ORDER BY c.TABLE_SCHEMA, c.TABLE_NAME, c.COLUMN_NAME",
+
+		ComputedColumns: `
+			SELECT
+				c.TABLE_SCHEMA AS schema_name,
+				c.TABLE_NAME AS table_name,
+				c.COLUMN_NAME AS column_name,
+				c.COLUMN_TYPE AS data_type,
+				c.GENERATION_EXPRESSION AS expression,
+				CASE WHEN c.EXTRA LIKE '%STORED GENERATED%' THEN 1 ELSE 0 END AS persisted
+			FROM INFORMATION_SCHEMA.COLUMNS c
+			INNER JOIN INFORMATION_SCHEMA.TABLES t
+				ON t.TABLE_SCHEMA = c.TABLE_SCHEMA AND t.TABLE_NAME = c.TABLE_NAME
+			WHERE t.TABLE_TYPE = 'BASE TABLE'
+				AND (c.EXTRA LIKE '%VIRTUAL GENERATED%' OR c.EXTRA LIKE '%STORED GENERATED%')
+				AND c.TABLE_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')`,
+		ComputedSchemaFilter: " AND c.TABLE_SCHEMA = %s",
+		ComputedTableFilter:  " AND c.TABLE_NAME = %s",
+		ComputedOrderBy:      " ORDER BY c.TABLE_SCHEMA, c.TABLE_NAME, c.ORDINAL_POSITION",
+
+		// Persistent InnoDB statistics; reading them needs SELECT on the mysql schema
+		Statistics: `
+			SELECT
+				s.database_name,
+				s.table_name,
+				NULL,
+				NULL,
+				s.last_update,
+				s.n_rows,
+				NULL,
+				NULL,
+				NULL
+			FROM mysql.innodb_table_stats s
+			WHERE s.database_name NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')`,
+		StatisticsSchemaFilter: " AND s.database_name = %s",
+		StatisticsTableFilter:  " AND s.table_name = %s",
+		StatisticsOrderBy:      " ORDER BY s.database_name, s.table_name",
+
+		ListPartitions: `
+			SELECT
+				TABLE_SCHEMA AS schema_name,
+				COALESCE(SUBPARTITION_NAME, PARTITION_NAME) AS partition_name,
+				PARTITION_ORDINAL_POSITION AS position,
+				PARTITION_DESCRIPTION AS bound,
+				TABLE_ROWS AS row_count,
+				NULL AS scheme_name,
+				NULL AS function_name,
+				PARTITION_METHOD AS strategy
+			FROM INFORMATION_SCHEMA.PARTITIONS
+			WHERE PARTITION_NAME IS NOT NULL`,
+		PartitionSchemaFilter: " AND TABLE_SCHEMA = %s",
+		PartitionTableFilter:  " AND TABLE_NAME = %s",
+		PartitionOrderBy:      " ORDER BY TABLE_SCHEMA, PARTITION_ORDINAL_POSITION, SUBPARTITION_ORDINAL_POSITION",
+
+		// MySQL exposes the partitioning expression rather than a column list
+		PartitionKeys: `
+			SELECT DISTINCT
+				TABLE_SCHEMA AS schema_name,
+				PARTITION_EXPRESSION AS column_name
+			FROM INFORMATION_SCHEMA.PARTITIONS
+			WHERE PARTITION_EXPRESSION IS NOT NULL`,
+		PartitionKeySchemaFilter: " AND TABLE_SCHEMA = %s",
+		PartitionKeyTableFilter:  " AND TABLE_NAME = %s",
+		PartitionKeyOrderBy:      " ORDER BY TABLE_SCHEMA, PARTITION_EXPRESSION",
 	}
 }
 
@@ -200,6 +470,19 @@ func (d *MySQLDialect) FunctionMetadata() FunctionMetadataSQL {
 			SELECT ROUTINE_DEFINITION
 			FROM INFORMATION_SCHEMA.ROUTINES
 			WHERE ROUTINE_SCHEMA = ? AND ROUTINE_NAME = ? AND ROUTINE_TYPE = 'FUNCTION'`,
+
+		// MySQL has no parameter defaults; the return type is ORDINAL_POSITION 0
+		GetParameters: `
+			SELECT
+				CONCAT(SPECIFIC_SCHEMA, '.', SPECIFIC_NAME) AS signature,
+				ORDINAL_POSITION AS position,
+				PARAMETER_NAME,
+				DTD_IDENTIFIER AS data_type,
+				COALESCE(PARAMETER_MODE, 'RETURN') AS direction,
+				NULL AS default_value
+			FROM INFORMATION_SCHEMA.PARAMETERS
+			WHERE SPECIFIC_SCHEMA = ? AND SPECIFIC_NAME = ? AND ROUTINE_TYPE = 'FUNCTION'
+			ORDER BY ORDINAL_POSITION`,
 	}
 }
 
@@ -251,6 +534,321 @@ func (d *MySQLDialect) TriggerMetadata() TriggerMetadataSQL {
 	}
 }
 
+// SynonymMetadata returns MySQL synonym metadata queries
+func (d *MySQLDialect) SynonymMetadata() SynonymMetadataSQL {
+	return SynonymMetadataSQL{
+		ListSynonyms: "", // MySQL doesn't have synonyms
+	}
+}
+
+// TypeMetadata returns MySQL user-defined type metadata queries
+func (d *MySQLDialect) TypeMetadata() TypeMetadataSQL {
+	return TypeMetadataSQL{
+		ListTypes: "", // MySQL doesn't have user-defined types
+	}
+}
+
+// DependencyMetadata returns MySQL object dependency queries
+func (d *MySQLDialect) DependencyMetadata() DependencyMetadataSQL {
+	// MySQL only tracks view-to-table usage (8.0.13+) and foreign keys.
+	// An empty schema resolves to the current database.
+	return DependencyMetadataSQL{
+		Upstream: `
+			WITH target AS (SELECT COALESCE(NULLIF(?, ''), DATABASE()) AS schema_name, ? AS object_name)
+			SELECT
+				u.TABLE_SCHEMA AS schema_name,
+				u.TABLE_NAME AS object_name,
+				COALESCE(t.TABLE_TYPE, 'UNRESOLVED') AS object_type,
+				'reference' AS relation
+			FROM target
+			JOIN INFORMATION_SCHEMA.VIEW_TABLE_USAGE u
+				ON u.VIEW_SCHEMA = target.schema_name AND u.VIEW_NAME = target.object_name
+			LEFT JOIN INFORMATION_SCHEMA.TABLES t
+				ON t.TABLE_SCHEMA = u.TABLE_SCHEMA AND t.TABLE_NAME = u.TABLE_NAME
+			UNION
+			SELECT
+				k.REFERENCED_TABLE_SCHEMA,
+				k.REFERENCED_TABLE_NAME,
+				'BASE TABLE',
+				'foreign_key'
+			FROM target
+			JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE k
+				ON k.TABLE_SCHEMA = target.schema_name AND k.TABLE_NAME = target.object_name
+			WHERE k.REFERENCED_TABLE_NAME IS NOT NULL
+				AND k.REFERENCED_TABLE_NAME <> k.TABLE_NAME`,
+
+		Downstream: `
+			WITH target AS (SELECT COALESCE(NULLIF(?, ''), DATABASE()) AS schema_name, ? AS object_name)
+			SELECT
+				u.VIEW_SCHEMA AS schema_name,
+				u.VIEW_NAME AS object_name,
+				'VIEW' AS object_type,
+				'reference' AS relation
+			FROM target
+			JOIN INFORMATION_SCHEMA.VIEW_TABLE_USAGE u
+				ON u.TABLE_SCHEMA = target.schema_name AND u.TABLE_NAME = target.object_name
+			UNION
+			SELECT
+				k.TABLE_SCHEMA,
+				k.TABLE_NAME,
+				'BASE TABLE',
+				'foreign_key'
+			FROM target
+			JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE k
+				ON k.REFERENCED_TABLE_SCHEMA = target.schema_name AND k.REFERENCED_TABLE_NAME = target.object_name
+			WHERE k.TABLE_NAME <> k.REFERENCED_TABLE_NAME`,
+	}
+}
+
+// DefinitionSearch returns the MySQL source search over views, routines and trigger bodies
+func (d *MySQLDialect) DefinitionSearch() DefinitionSearchSQL {
+	return DefinitionSearchSQL{
+		Search: `
+			SELECT schema_name, object_name, kind, line, definition
+			FROM (
+				SELECT
+					TABLE_SCHEMA AS schema_name,
+					TABLE_NAME AS object_name,
+					'view' AS kind,
+					1 AS line,
+					VIEW_DEFINITION AS definition
+				FROM INFORMATION_SCHEMA.VIEWS
+				UNION ALL
+				SELECT ROUTINE_SCHEMA, ROUTINE_NAME, LOWER(ROUTINE_TYPE), 1, ROUTINE_DEFINITION
+				FROM INFORMATION_SCHEMA.ROUTINES
+				UNION ALL
+				SELECT TRIGGER_SCHEMA, TRIGGER_NAME, 'trigger', 1, ACTION_STATEMENT
+				FROM INFORMATION_SCHEMA.TRIGGERS
+			) found
+			WHERE schema_name NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')
+			  AND LOCATE(?, definition) > 0`,
+		SchemaFilter: " AND schema_name = %s",
+		KindFilter:   " AND kind IN (%s)",
+		OrderBy:      " ORDER BY schema_name, object_name, kind, line",
+	}
+}
+
+// PermissionMetadata returns MySQL table, column and schema privileges (routine
+// privileges live in mysql.procs_priv and are not included). An empty schema
+// resolves to the current database.
+func (d *MySQLDialect) PermissionMetadata() PermissionMetadataSQL {
+	return PermissionMetadataSQL{
+		ListObjectPermissions: `
+			SELECT grantee, grantee_type, privilege, state, grantor, column_name, scope
+			FROM (
+				SELECT
+					p.GRANTEE AS grantee,
+					'USER' AS grantee_type,
+					p.PRIVILEGE_TYPE AS privilege,
+					CASE WHEN p.IS_GRANTABLE = 'YES' THEN 'GRANT_WITH_GRANT_OPTION' ELSE 'GRANT' END AS state,
+					NULL AS grantor,
+					NULL AS column_name,
+					'OBJECT' AS scope
+				FROM (SELECT COALESCE(NULLIF(?, ''), DATABASE()) AS schema_name, ? AS object_name) target
+				JOIN INFORMATION_SCHEMA.TABLE_PRIVILEGES p
+					ON p.TABLE_SCHEMA = target.schema_name AND p.TABLE_NAME = target.object_name
+				UNION ALL
+				SELECT
+					p.GRANTEE,
+					'USER',
+					p.PRIVILEGE_TYPE,
+					CASE WHEN p.IS_GRANTABLE = 'YES' THEN 'GRANT_WITH_GRANT_OPTION' ELSE 'GRANT' END,
+					NULL,
+					p.COLUMN_NAME,
+					'COLUMN'
+				FROM (SELECT COALESCE(NULLIF(?, ''), DATABASE()) AS schema_name, ? AS object_name) target
+				JOIN INFORMATION_SCHEMA.COLUMN_PRIVILEGES p
+					ON p.TABLE_SCHEMA = target.schema_name AND p.TABLE_NAME = target.object_name
+				UNION ALL
+				SELECT
+					p.GRANTEE,
+					'USER',
+					p.PRIVILEGE_TYPE,
+					CASE WHEN p.IS_GRANTABLE = 'YES' THEN 'GRANT_WITH_GRANT_OPTION' ELSE 'GRANT' END,
+					NULL,
+					NULL,
+					'SCHEMA'
+				FROM (SELECT COALESCE(NULLIF(?, ''), DATABASE()) AS schema_name, ? AS object_name) target
+				JOIN INFORMATION_SCHEMA.SCHEMA_PRIVILEGES p ON p.TABLE_SCHEMA = target.schema_name
+			) perms
+			WHERE 1=1`,
+		// GRANTEE is 'user'@'host'; the filter matches the user on any host
+		GranteeFilter: " AND grantee LIKE CONCAT('''', %s, '''@%%')",
+		OrderBy:       " ORDER BY grantee, scope, column_name, privilege",
+
+		RLSPolicies: "", // MySQL has no row-level security
+	}
+}
+
+// PrincipalMetadata returns MySQL accounts as 'user'@'host' with their role grants. Accounts
+// granted to others are reported as roles. Requires SELECT on the mysql schema.
+func (d *MySQLDialect) PrincipalMetadata() PrincipalMetadataSQL {
+	return PrincipalMetadataSQL{
+		ListPrincipals: `
+			SELECT name, principal_type, is_role, is_system, default_schema, create_date
+			FROM (
+				SELECT
+					CONCAT('''', u.User, '''@''', u.Host, '''') AS name,
+					CASE WHEN r.FROM_USER IS NULL THEN 'USER' ELSE 'ROLE' END AS principal_type,
+					CASE WHEN r.FROM_USER IS NULL THEN 0 ELSE 1 END AS is_role,
+					CASE WHEN u.User IN ('mysql.sys', 'mysql.session', 'mysql.infoschema') THEN 1 ELSE 0 END AS is_system,
+					NULL AS default_schema,
+					NULL AS create_date
+				FROM mysql.user u
+				LEFT JOIN (SELECT DISTINCT FROM_USER, FROM_HOST FROM mysql.role_edges) r
+					ON r.FROM_USER = u.User AND r.FROM_HOST = u.Host
+			) principals
+			WHERE 1=1`,
+		NameFilter: " AND name LIKE %s",
+		OrderBy:    " ORDER BY is_role DESC, name",
+		ListRoleMembers: `
+			SELECT
+				CONCAT('''', FROM_USER, '''@''', FROM_HOST, '''') AS role_name,
+				CONCAT('''', TO_USER, '''@''', TO_HOST, '''') AS member_name
+			FROM mysql.role_edges
+			ORDER BY role_name, member_name`,
+	}
+}
+
+// JobMetadata returns the MySQL events of all schemas visible to the user. Events only
+// run while the event_scheduler system variable is ON.
+func (d *MySQLDialect) JobMetadata() JobMetadataSQL {
+	return JobMetadataSQL{
+		Schedulers: `SELECT 'event_scheduler' AS scheduler`,
+		ListJobs: map[string]string{
+			"event_scheduler": `
+				SELECT
+					CONCAT(EVENT_SCHEMA, '.', EVENT_NAME) AS job_name,
+					CASE WHEN STATUS = 'ENABLED' THEN 1 ELSE 0 END AS enabled,
+					CASE EVENT_TYPE
+						WHEN 'ONE TIME' THEN CONCAT('once at ', DATE_FORMAT(EXECUTE_AT, '%Y-%m-%d %H:%i:%s'))
+						ELSE CONCAT('every ', INTERVAL_VALUE, ' ', INTERVAL_FIELD,
+							IFNULL(CONCAT(' from ', DATE_FORMAT(STARTS, '%Y-%m-%d %H:%i:%s')), ''),
+							IFNULL(CONCAT(' until ', DATE_FORMAT(ENDS, '%Y-%m-%d %H:%i:%s')), ''))
+					END AS schedule,
+					EVENT_DEFINITION AS command,
+					DATE_FORMAT(LAST_EXECUTED, '%Y-%m-%d %H:%i:%s') AS last_run_at,
+					NULL AS last_run_status,
+					NULL AS last_run_message,
+					NULL AS next_run_at,
+					EVENT_SCHEMA AS database_name,
+					DEFINER AS owner,
+					NULLIF(EVENT_COMMENT, '') AS description
+				FROM INFORMATION_SCHEMA.EVENTS`,
+		},
+	}
+}
+
+// ChangeTrackingMetadata returns nothing (MySQL captures changes in the server-wide binary log)
+func (d *MySQLDialect) ChangeTrackingMetadata() ChangeTrackingMetadataSQL {
+	return ChangeTrackingMetadataSQL{}
+}
+
+// TemporalMetadata returns nothing (MySQL has no system-versioned tables)
+func (d *MySQLDialect) TemporalMetadata() TemporalMetadataSQL {
+	return TemporalMetadataSQL{}
+}
+
+// RemoteServerMetadata returns the MySQL servers created for FEDERATED tables. Requires
+// SELECT on the mysql schema; the tables themselves do not expose their server.
+func (d *MySQLDialect) RemoteServerMetadata() RemoteServerMetadataSQL {
+	return RemoteServerMetadataSQL{
+		ListServers: `
+			SELECT
+				Server_name AS name,
+				'FEDERATED_SERVER' AS kind,
+				Wrapper AS provider,
+				NULL AS product,
+				NULLIF(CONCAT(Host, IF(Port > 0, CONCAT(':', Port), '')), '') AS data_source,
+				NULLIF(Db, '') AS catalog,
+				CONCAT('user=', Username, IF(Owner <> '', CONCAT(', owner=', Owner), '')) AS options
+			FROM mysql.servers
+			WHERE 1=1`,
+		NameFilter: " AND Server_name LIKE %s",
+		OrderBy:    " ORDER BY Server_name",
+	}
+}
+
+// ConstraintMetadata returns MySQL constraint metadata queries
+func (d *MySQLDialect) ConstraintMetadata() ConstraintMetadataSQL {
+	return ConstraintMetadataSQL{
+		ListForeignKeys: `
+			SELECT
+				kcu.TABLE_SCHEMA AS schema_name,
+				kcu.TABLE_NAME AS table_name,
+				kcu.CONSTRAINT_NAME AS constraint_name,
+				kcu.COLUMN_NAME AS column_name,
+				kcu.REFERENCED_TABLE_SCHEMA AS referenced_schema,
+				kcu.REFERENCED_TABLE_NAME AS referenced_table,
+				kcu.REFERENCED_COLUMN_NAME AS referenced_column,
+				rc.DELETE_RULE AS on_delete,
+				rc.UPDATE_RULE AS on_update
+			FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE kcu
+			JOIN INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS rc
+				ON rc.CONSTRAINT_SCHEMA = kcu.CONSTRAINT_SCHEMA
+				AND rc.CONSTRAINT_NAME = kcu.CONSTRAINT_NAME
+				AND rc.TABLE_NAME = kcu.TABLE_NAME
+			WHERE kcu.REFERENCED_TABLE_NAME IS NOT NULL
+				AND kcu.TABLE_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')`,
+		ForeignKeySchemaFilter:          " AND kcu.TABLE_SCHEMA = %s",
+		ForeignKeyTableFilter:           " AND kcu.TABLE_NAME = %s",
+		ForeignKeyReferencedTableFilter: " AND kcu.REFERENCED_TABLE_NAME = %s",
+		ForeignKeyOrderBy:               " ORDER BY kcu.TABLE_SCHEMA, kcu.TABLE_NAME, kcu.CONSTRAINT_NAME, kcu.ORDINAL_POSITION",
+		ListKeyConstraints: `
+			SELECT
+				tc.TABLE_SCHEMA AS schema_name,
+				tc.TABLE_NAME AS table_name,
+				tc.CONSTRAINT_NAME AS constraint_name,
+				tc.CONSTRAINT_TYPE AS constraint_type,
+				kcu.COLUMN_NAME AS column_name,
+				kcu.ORDINAL_POSITION AS position
+			FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc
+			JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE kcu
+				ON kcu.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA
+				AND kcu.CONSTRAINT_NAME = tc.CONSTRAINT_NAME
+				AND kcu.TABLE_NAME = tc.TABLE_NAME
+			WHERE tc.CONSTRAINT_TYPE IN ('PRIMARY KEY', 'UNIQUE')
+				AND tc.TABLE_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')`,
+		KeySchemaFilter: " AND tc.TABLE_SCHEMA = %s",
+		KeyTableFilter:  " AND tc.TABLE_NAME = %s",
+		KeyOrderBy:      " ORDER BY tc.TABLE_SCHEMA, tc.TABLE_NAME, tc.CONSTRAINT_TYPE, tc.CONSTRAINT_NAME, kcu.ORDINAL_POSITION",
+		// CHECK_CONSTRAINTS requires MySQL 8.0.16+ / MariaDB 10.2+
+		ListCheckConstraints: `
+			SELECT
+				tc.TABLE_SCHEMA AS schema_name,
+				tc.TABLE_NAME AS table_name,
+				tc.CONSTRAINT_NAME AS constraint_name,
+				NULL AS column_name,
+				cc.CHECK_CLAUSE AS definition
+			FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc
+			JOIN INFORMATION_SCHEMA.CHECK_CONSTRAINTS cc
+				ON cc.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA
+				AND cc.CONSTRAINT_NAME = tc.CONSTRAINT_NAME
+			WHERE tc.CONSTRAINT_TYPE = 'CHECK'
+				AND tc.TABLE_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')`,
+		CheckSchemaFilter: " AND tc.TABLE_SCHEMA = %s",
+		CheckTableFilter:  " AND tc.TABLE_NAME = %s",
+		CheckOrderBy:      " ORDER BY tc.TABLE_SCHEMA, tc.TABLE_NAME, tc.CONSTRAINT_NAME",
+		ListDefaults: `
+			SELECT
+				c.TABLE_SCHEMA AS schema_name,
+				c.TABLE_NAME AS table_name,
+				c.COLUMN_NAME AS column_name,
+				NULL AS constraint_name,
+				c.COLUMN_DEFAULT AS definition
+			FROM INFORMATION_SCHEMA.COLUMNS c
+			JOIN INFORMATION_SCHEMA.TABLES t
+				ON t.TABLE_SCHEMA = c.TABLE_SCHEMA
+				AND t.TABLE_NAME = c.TABLE_NAME
+			WHERE t.TABLE_TYPE = 'BASE TABLE'
+				AND c.COLUMN_DEFAULT IS NOT NULL
+				AND c.TABLE_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')`,
+		DefaultSchemaFilter: " AND c.TABLE_SCHEMA = %s",
+		DefaultTableFilter:  " AND c.TABLE_NAME = %s",
+		DefaultOrderBy:      " ORDER BY c.TABLE_SCHEMA, c.TABLE_NAME, c.ORDINAL_POSITION",
+	}
+}
+
 // DatabaseInfo returns MySQL database info queries
 func (d *MySQLDialect) DatabaseInfo() DatabaseInfoSQL {
 	return DatabaseInfoSQL{
@@ -282,6 +880,43 @@ func (d *MySQLDialect) DatabaseInfo() DatabaseInfoSQL {
 			WHERE SCHEMA_NAME NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')
 			ORDER BY SCHEMA_NAME`,
 
+		SchemaOverview: `
+			SELECT
+				s.SCHEMA_NAME AS schema_name,
+				(SELECT COUNT(*) FROM INFORMATION_SCHEMA.TABLES t WHERE t.TABLE_SCHEMA = s.SCHEMA_NAME AND t.TABLE_TYPE = 'BASE TABLE') AS tables,
+				(SELECT COUNT(*) FROM INFORMATION_SCHEMA.TABLES t WHERE t.TABLE_SCHEMA = s.SCHEMA_NAME AND t.TABLE_TYPE = 'VIEW') AS views,
+				(SELECT COUNT(*) FROM INFORMATION_SCHEMA.ROUTINES r WHERE r.ROUTINE_SCHEMA = s.SCHEMA_NAME AND r.ROUTINE_TYPE = 'FUNCTION') AS functions,
+				(SELECT COUNT(*) FROM INFORMATION_SCHEMA.ROUTINES r WHERE r.ROUTINE_SCHEMA = s.SCHEMA_NAME AND r.ROUTINE_TYPE = 'PROCEDURE') AS procedures,
+				(SELECT SUM(t.DATA_LENGTH + t.INDEX_LENGTH)
+					FROM INFORMATION_SCHEMA.TABLES t
+					WHERE t.TABLE_SCHEMA = s.SCHEMA_NAME AND t.TABLE_TYPE = 'BASE TABLE') AS total_bytes
+			FROM INFORMATION_SCHEMA.SCHEMATA s
+			WHERE s.SCHEMA_NAME NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')`,
+
+		ListDatabases: `
+			SELECT
+				s.SCHEMA_NAME,
+				'ONLINE' AS state,
+				ROUND(SUM(t.DATA_LENGTH + t.INDEX_LENGTH) / 1048576, 2) AS size_mb,
+				s.DEFAULT_COLLATION_NAME
+			FROM INFORMATION_SCHEMA.SCHEMATA s
+			LEFT JOIN INFORMATION_SCHEMA.TABLES t ON t.TABLE_SCHEMA = s.SCHEMA_NAME
+			WHERE s.SCHEMA_NAME NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')
+			GROUP BY s.SCHEMA_NAME, s.DEFAULT_COLLATION_NAME
+			ORDER BY s.SCHEMA_NAME`,
+
+		// READ ONLY schemas exist since MySQL 8.0.22
+		ReadOnlyDatabase: `
+			SELECT 'read-only' AS kind
+			FROM INFORMATION_SCHEMA.SCHEMATA_EXTENSIONS
+			WHERE SCHEMA_NAME = ?
+			  AND OPTIONS LIKE '%READ ONLY=1%'`,
+
+		Collation: `
+			SELECT DEFAULT_COLLATION_NAME, DEFAULT_CHARACTER_SET_NAME, @@collation_server
+			FROM INFORMATION_SCHEMA.SCHEMATA
+			WHERE SCHEMA_NAME = DATABASE()`,
+
 		SearchObjects: `
 			SELECT
 				TABLE_SCHEMA AS schema_name,
@@ -297,3 +932,93 @@ func (d *MySQLDialect) DatabaseInfo() DatabaseInfoSQL {
 			ORDER BY TABLE_SCHEMA, TABLE_NAME`,
 	}
 }
+
+// Explain returns the MySQL statements for estimated execution plans
+func (d *MySQLDialect) Explain() ExplainSQL {
+	return ExplainSQL{
+		Prefix: "EXPLAIN FORMAT=JSON ",
+		Format: "json",
+
+		// EXPLAIN ANALYZE needs MySQL 8.0.18 or later and only has the tree format
+		AnalyzePrefix: "EXPLAIN ANALYZE ",
+		AnalyzeFormat: "text",
+	}
+}
+
+// ResultLimit returns the MySQL statements that cap the rows of a query
+func (d *MySQLDialect) ResultLimit() ResultLimitSQL {
+	return ResultLimitSQL{
+		// Derived tables reject duplicate column names, so the cap is set on the session
+		SessionLimit: "SET SESSION sql_select_limit = %d",
+		SessionReset: "SET SESSION sql_select_limit = DEFAULT",
+	}
+}
+
+// ParseCheck returns no statements: the driver prepares statements on the server
+func (d *MySQLDialect) ParseCheck() ParseCheckSQL {
+	return ParseCheckSQL{}
+}
+
+// Sample returns the MySQL queries of sample_table_data
+func (d *MySQLDialect) Sample() SampleSQL {
+	return SampleSQL{
+		First:  "SELECT %[1]s FROM %[2]s LIMIT %[3]d",
+		Random: "SELECT %[1]s FROM %[2]s ORDER BY RAND() LIMIT %[3]d",
+		// Without TABLESAMPLE the table is still scanned, but only the kept rows are sorted
+		TableSample: "SELECT %[1]s FROM %[2]s WHERE RAND() < %[4]s / 100 ORDER BY RAND() LIMIT %[3]d",
+	}
+}
+
+// ColumnProfile returns the MySQL column profile query. Derived tables instead of CTEs
+// keep it working on MySQL 5.7.
+func (d *MySQLDialect) ColumnProfile() ColumnProfileSQL {
+	return ColumnProfileSQL{
+		Query: `
+			SELECT s.row_count, s.null_count, s.distinct_count, s.min_value, s.max_value, s.avg_length, t.top_value, t.frequency
+			FROM (
+				SELECT COUNT(*) AS row_count, COUNT(*) - COUNT(%[1]s) AS null_count, COUNT(DISTINCT %[1]s) AS distinct_count,
+					MIN(%[1]s) AS min_value, MAX(%[1]s) AS max_value, AVG(CHAR_LENGTH(%[1]s)) + 0E0 AS avg_length
+				FROM %[2]s
+			) s
+			LEFT JOIN (
+				SELECT %[1]s AS top_value, COUNT(*) AS frequency
+				FROM %[2]s
+				WHERE %[1]s IS NOT NULL
+				GROUP BY %[1]s
+				ORDER BY COUNT(*) DESC, %[1]s
+				LIMIT %[3]d
+			) t ON 1 = 1
+			ORDER BY t.frequency DESC, t.top_value`,
+	}
+}
+
+// SyntaxReference returns the MySQL syntax quick reference
+func (d *MySQLDialect) SyntaxReference() SyntaxReferenceSQL {
+	return SyntaxReferenceSQL{
+		Product: "MySQL",
+
+		FirstRows: "SELECT * FROM t ORDER BY id LIMIT 10",
+		Page:      "SELECT * FROM t ORDER BY id LIMIT 10 OFFSET 20",
+
+		CurrentTimestamp: "NOW()",
+		CurrentDate:      "CURDATE()",
+		AddDays:          "DATE_ADD(d, INTERVAL 7 DAY)",
+		DaysBetween:      "DATEDIFF(d2, d1)",
+		Year:             "YEAR(d)",
+		TruncateToMonth:  "DATE_FORMAT(d, '%Y-%m-01')",
+		FormatDate:       "DATE_FORMAT(d, '%Y-%m-%d')",
+
+		Concat:          "CONCAT(a, b) (|| is a logical OR)",
+		Substring:       "SUBSTRING(s, 1, 3)",
+		Length:          "CHAR_LENGTH(s) (LENGTH counts bytes)",
+		Position:        "LOCATE('x', s)",
+		CaseInsensitive: "s LIKE '%x%' (case-insensitive with the default _ci collations)",
+		Aggregate:       "GROUP_CONCAT(s ORDER BY s SEPARATOR ', ')",
+
+		QuoteIdentifier:    "`Order Details`",
+		Coalesce:           "COALESCE(a, b) or IFNULL(a, b)",
+		CastToText:         "CAST(a AS CHAR)",
+		SelectWithoutTable: "SELECT NOW()",
+		Parameter:          "WHERE id = ? AND status = ?",
+	}
+}
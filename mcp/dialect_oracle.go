@@ -3,6 +3,7 @@ package mcp
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 // OracleDialect implements Dialect for Oracle Database
@@ -22,9 +23,12 @@ func (d *OracleDialect) Placeholder(index int) string {
 	return fmt.Sprintf(":%d", index)
 }
 
-// QuoteIdentifier returns "NAME" (uppercase)
+// QuoteIdentifier returns "NAME" (uppercase), or the name as written when it is quoted
 func (d *OracleDialect) QuoteIdentifier(name string) string {
-	return fmt.Sprintf(`"%s"`, strings.ToUpper(name))
+	if _, quoted := unquoteIdentifier(name); !quoted {
+		name = strings.ToUpper(name)
+	}
+	return quoteIdentifier(name, `"`, `"`)
 }
 
 // PaginationClause returns OFFSET/FETCH syntax (Oracle 12c+)
@@ -46,20 +50,44 @@ func (d *OracleDialect) CurrentDatabase() string {
 	return "SYS_CONTEXT('USERENV', 'DB_NAME')"
 }
 
+// SearchPathStatements returns ALTER SESSION SET CURRENT_SCHEMA for the first entry
+func (d *OracleDialect) SearchPathStatements(searchPath []string) []string {
+	if len(searchPath) == 0 {
+		return nil
+	}
+	return []string{"ALTER SESSION SET CURRENT_SCHEMA = " + d.QuoteIdentifier(searchPath[0])}
+}
+
+// AsOfClause returns AS OF TIMESTAMP, a flashback query reading the table as it was at a
+// point in time from undo data
+func (d *OracleDialect) AsOfClause(asOf time.Time) string {
+	return fmt.Sprintf("AS OF TIMESTAMP TO_TIMESTAMP_TZ('%s', 'YYYY-MM-DD HH24:MI:SS.FF6 TZH:TZM')", asOf.UTC().Format("2006-01-02 15:04:05.000000 -07:00"))
+}
+
 // SystemSchemas returns Oracle system schemas
 func (d *OracleDialect) SystemSchemas() []string {
 	return []string{"SYS", "SYSTEM", "OUTLN", "XDB", "WMSYS", "CTXSYS", "MDSYS", "OLAPSYS"}
 }
 
-// NormalizeIdentifier converts to uppercase for Oracle, strips brackets if present
+// NormalizeIdentifier converts to uppercase for Oracle, as it folds unquoted names, and
+// strips the quotes of quoted names, whose case is kept
 func (d *OracleDialect) NormalizeIdentifier(name string) string {
-	// Remove SQL Server style brackets [name] if present
-	if len(name) >= 2 && name[0] == '[' && name[len(name)-1] == ']' {
-		name = name[1 : len(name)-1]
+	if inner, quoted := unquoteIdentifier(name); quoted {
+		return inner
 	}
 	return strings.ToUpper(name)
 }
 
+// SupportsFeature checks Oracle feature support
+func (d *OracleDialect) SupportsFeature(feature DialectFeature) bool {
+	switch feature {
+	case FeatureCrossDatabase:
+		return false
+	default:
+		return true
+	}
+}
+
 // TableMetadata returns Oracle table metadata queries
 func (d *OracleDialect) TableMetadata() TableMetadataSQL {
 	return TableMetadataSQL{
@@ -164,7 +192,265 @@ func (d *OracleDialect) TableMetadata() TableMetadataSQL {
 			WHERE ac.constraint_type = 'R'
 				AND ac.owner = :1
 				AND ac.table_name = :2
-			ORDER BY ac.constraint_name`,
+			ORDER BY ac.constraint_name, acc.position, acc_ref.position`,
+
+		// data_default_vc requires Oracle 12.2+, like search_condition_vc
+		DDLColumns: `
+			SELECT
+				c.column_name,
+				CASE
+					WHEN c.data_type IN ('VARCHAR2', 'NVARCHAR2', 'CHAR', 'NCHAR')
+						THEN c.data_type || '(' || c.char_length || CASE c.char_used WHEN 'C' THEN ' CHAR' END || ')'
+					WHEN c.data_type = 'RAW' THEN 'RAW(' || c.data_length || ')'
+					WHEN c.data_type = 'NUMBER' AND c.data_precision IS NOT NULL
+						THEN 'NUMBER(' || c.data_precision || ',' || c.data_scale || ')'
+					WHEN c.data_type = 'NUMBER' AND c.data_scale = 0 THEN 'INTEGER'
+					ELSE c.data_type
+				END AS column_type,
+				CASE c.nullable WHEN 'Y' THEN 'YES' ELSE 'NO' END AS is_nullable,
+				CASE WHEN c.virtual_column = 'NO' AND c.identity_column = 'NO' THEN TRIM(c.data_default_vc) END AS column_default,
+				CASE
+					WHEN c.identity_column = 'YES' THEN 'GENERATED ' || ic.generation_type || ' AS IDENTITY'
+					WHEN c.virtual_column = 'YES' THEN 'GENERATED ALWAYS AS (' || TRIM(c.data_default_vc) || ') VIRTUAL'
+				END AS column_extra
+			FROM all_tab_cols c
+			LEFT JOIN all_tab_identity_cols ic
+				ON ic.owner = c.owner
+				AND ic.table_name = c.table_name
+				AND ic.column_name = c.column_name
+			WHERE c.owner = :1 AND c.table_name = :2
+				AND c.hidden_column = 'NO'
+			ORDER BY c.column_id`,
+
+		// Function-based, domain and LOB indexes are left out
+		DDLIndexes: `
+			SELECT
+				i.index_name,
+				CASE i.uniqueness WHEN 'UNIQUE' THEN 1 ELSE 0 END AS is_unique,
+				CASE i.index_type WHEN 'BITMAP' THEN 'BITMAP' END AS index_kind,
+				NULL AS index_method,
+				'"' || ic.column_name || '"' AS column_name,
+				CASE ic.descend WHEN 'DESC' THEN 1 ELSE 0 END AS is_descending,
+				NULL AS filter
+			FROM all_indexes i
+			JOIN all_ind_columns ic ON ic.index_owner = i.owner AND ic.index_name = i.index_name
+			WHERE i.table_owner = :1 AND i.table_name = :2
+				AND i.index_type IN ('NORMAL', 'BITMAP')
+				AND NOT EXISTS (
+					SELECT 1 FROM all_constraints ac
+					WHERE ac.owner = i.table_owner
+						AND ac.table_name = i.table_name
+						AND ac.index_name = i.index_name
+						AND ac.constraint_type IN ('P', 'U'))
+			ORDER BY i.index_name, ic.column_position`,
+
+		// Optimizer statistics; segment sizes require DBA views, so data size is estimated
+		DictionaryTables: `
+			SELECT
+				t.owner AS table_schema,
+				t.table_name,
+				tc.comments AS table_comment
+			FROM all_tables t
+			LEFT JOIN all_tab_comments tc ON tc.owner = t.owner AND tc.table_name = t.table_name
+			WHERE t.owner NOT IN ('SYS', 'SYSTEM', 'OUTLN', 'XDB', 'WMSYS', 'CTXSYS', 'MDSYS', 'OLAPSYS')`,
+		DictionaryTableSchemaFilter: " AND t.owner = %s",
+		DictionaryTableOrderBy:      " ORDER BY t.owner, t.table_name",
+
+		DictionaryColumns: `
+			SELECT
+				c.owner AS table_schema,
+				c.table_name,
+				c.column_name,
+				CASE
+					WHEN c.data_type IN ('VARCHAR2', 'NVARCHAR2', 'CHAR', 'NCHAR')
+						THEN c.data_type || '(' || c.char_length || CASE c.char_used WHEN 'C' THEN ' CHAR' END || ')'
+					WHEN c.data_type = 'RAW' THEN 'RAW(' || c.data_length || ')'
+					WHEN c.data_type = 'NUMBER' AND c.data_precision IS NOT NULL
+						THEN 'NUMBER(' || c.data_precision || ',' || c.data_scale || ')'
+					ELSE c.data_type
+				END AS data_type,
+				CASE c.nullable WHEN 'Y' THEN 'YES' ELSE 'NO' END AS is_nullable,
+				TRIM(c.data_default_vc) AS column_default,
+				cc.comments AS column_comment,
+				CASE WHEN EXISTS (
+					SELECT 1 FROM all_constraints k
+					JOIN all_cons_columns kc ON kc.owner = k.owner AND kc.constraint_name = k.constraint_name
+					WHERE k.constraint_type = 'P'
+						AND k.owner = c.owner
+						AND k.table_name = c.table_name
+						AND kc.column_name = c.column_name
+				) THEN 1 ELSE 0 END AS is_primary_key
+			FROM all_tab_cols c
+			JOIN all_tables t ON t.owner = c.owner AND t.table_name = c.table_name
+			LEFT JOIN all_col_comments cc
+				ON cc.owner = c.owner AND cc.table_name = c.table_name AND cc.column_name = c.column_name
+			WHERE c.hidden_column = 'NO'
+				AND c.owner NOT IN ('SYS', 'SYSTEM', 'OUTLN', 'XDB', 'WMSYS', 'CTXSYS', 'MDSYS', 'OLAPSYS')`,
+		DictionaryColumnSchemaFilter: " AND c.owner = %s",
+		DictionaryColumnTableFilter:  " AND c.table_name IN (%s)",
+		DictionaryColumnOrderBy:      " ORDER BY c.owner, c.table_name, c.column_id",
+
+		FindColumns: `
+			SELECT
+				c.owner AS table_schema,
+				c.table_name,
+				LOWER(o.object_type) AS table_type,
+				c.column_name,
+				CASE
+					WHEN c.data_type IN ('VARCHAR2', 'NVARCHAR2', 'CHAR', 'NCHAR')
+						THEN c.data_type || '(' || c.char_length || CASE c.char_used WHEN 'C' THEN ' CHAR' END || ')'
+					WHEN c.data_type = 'RAW' THEN 'RAW(' || c.data_length || ')'
+					WHEN c.data_type = 'NUMBER' AND c.data_precision IS NOT NULL
+						THEN 'NUMBER(' || c.data_precision || ',' || c.data_scale || ')'
+					ELSE c.data_type
+				END AS data_type,
+				CASE c.nullable WHEN 'Y' THEN 'YES' ELSE 'NO' END AS is_nullable,
+				c.column_id
+			FROM all_tab_columns c
+			JOIN all_objects o
+				ON o.owner = c.owner AND o.object_name = c.table_name AND o.object_type IN ('TABLE', 'VIEW')
+			WHERE c.owner NOT IN ('SYS', 'SYSTEM', 'OUTLN', 'XDB', 'WMSYS', 'CTXSYS', 'MDSYS', 'OLAPSYS')`,
+		FindColumnName:         "c.column_name",
+		FindColumnSchemaFilter: " AND c.owner = %s",
+		FindColumnOrderBy:      " ORDER BY c.owner, c.table_name, c.column_id",
+
+		ObjectComment: `
+			SELECT LOWER(table_type) AS object_type, comments
+			FROM all_tab_comments
+			WHERE table_type IN ('TABLE', 'VIEW')
+				AND owner = :1
+				AND table_name = :2`,
+		ColumnComments: `
+			SELECT cc.column_name, cc.comments
+			FROM all_col_comments cc
+			JOIN all_tab_cols c
+				ON c.owner = cc.owner AND c.table_name = cc.table_name AND c.column_name = cc.column_name
+			WHERE cc.comments IS NOT NULL
+				AND cc.owner = :1
+				AND cc.table_name = :2
+			ORDER BY c.column_id`,
+
+		// Column collations need Oracle 12.2 or later
+		ColumnCollations: `
+			SELECT
+				c.column_name,
+				c.data_type,
+				c.collation,
+				(SELECT value FROM nls_database_parameters
+					WHERE parameter = CASE WHEN c.data_type IN ('NCHAR', 'NVARCHAR2', 'NCLOB')
+						THEN 'NLS_NCHAR_CHARACTERSET' ELSE 'NLS_CHARACTERSET' END),
+				t.default_collation
+			FROM all_tab_cols c
+			INNER JOIN all_tables t ON t.owner = c.owner AND t.table_name = c.table_name
+			WHERE c.collation IS NOT NULL
+				AND c.hidden_column = 'NO'
+				AND c.owner = :1
+				AND c.table_name = :2
+			ORDER BY c.column_id`,
+
+		TableStats: `
+			SELECT
+				owner AS schema_name,
+				table_name,
+				num_rows AS row_count,
+				num_rows * avg_row_len AS data_bytes,
+				NULL AS index_bytes
+			FROM all_tables
+			WHERE owner NOT IN ('SYS', 'SYSTEM')`,
+		StatsSchemaFilter: " AND owner = %s",
+		StatsTableFilter:  " AND table_name = %s",
+		StatsOrderBy:      " ORDER BY owner, table_name",
+
+		// LAST_NUMBER is the last value written to disk, ahead of the used values by up to the
+		// sequence cache
+		IdentityStatus: `
+			SELECT
+				ic.owner AS schema_name,
+				ic.table_name,
+				ic.column_name,
+				tc.data_type || CASE WHEN tc.data_precision IS NOT NULL THEN '(' || tc.data_precision || ')' END AS data_type,
+				REGEXP_SUBSTR(ic.identity_options, 'START WITH: (-?[0-9]+)', 1, 1, NULL, 1) AS seed,
+				TO_CHAR(sq.increment_by) AS increment,
+				CASE WHEN sq.last_number <> sq.min_value THEN TO_CHAR(sq.last_number - sq.increment_by) END AS current_value,
+				TO_CHAR(sq.min_value) AS min_value,
+				TO_CHAR(CASE WHEN tc.data_precision IS NOT NULL
+					THEN LEAST(sq.max_value, POWER(10, tc.data_precision - NVL(tc.data_scale, 0)) - 1)
+					ELSE sq.max_value
+				END) AS max_value
+			FROM all_tab_identity_cols ic
+			INNER JOIN all_tab_columns tc
+				ON tc.owner = ic.owner AND tc.table_name = ic.table_name AND tc.column_name = ic.column_name
+			INNER JOIN all_sequences sq
+				ON sq.sequence_owner = ic.owner AND sq.sequence_name = ic.sequence_name
+			WHERE ic.owner NOT IN ('SYS', 'SYSTEM')`,
+		IdentitySchemaFilter: " AND ic.owner = %s",
+		IdentityTableFilter:  " AND ic.table_name = %s",
+		IdentityOrderBy:      " ORDER BY ic.owner, ic.table_name, ic.column_name",
+
+		// Virtual columns are never stored; hidden ones back function-based indexes
+		ComputedColumns: `
+			SELECT
+				owner AS schema_name,
+				table_name,
+				column_name,
+				data_type,
+				data_default AS expression,
+				0 AS persisted
+			FROM all_tab_cols
+			WHERE virtual_column = 'YES'
+				AND hidden_column = 'NO'
+				AND owner NOT IN ('SYS', 'SYSTEM')`,
+		ComputedSchemaFilter: " AND owner = %s",
+		ComputedTableFilter:  " AND table_name = %s",
+		ComputedOrderBy:      " ORDER BY owner, table_name, column_id",
+
+		// DML counts are flushed to all_tab_modifications periodically, not on every change
+		Statistics: `
+			SELECT
+				s.owner,
+				s.table_name,
+				NULL,
+				NULL,
+				s.last_analyzed,
+				s.num_rows,
+				s.sample_size,
+				m.inserts + m.updates + m.deletes,
+				NULL
+			FROM all_tab_statistics s
+			LEFT JOIN all_tab_modifications m
+				ON m.table_owner = s.owner AND m.table_name = s.table_name AND m.partition_name IS NULL
+			WHERE s.object_type = 'TABLE'
+				AND s.owner NOT IN ('SYS', 'SYSTEM')`,
+		StatisticsSchemaFilter: " AND s.owner = %s",
+		StatisticsTableFilter:  " AND s.table_name = %s",
+		StatisticsOrderBy:      " ORDER BY s.owner, s.table_name",
+
+		ListPartitions: `
+			SELECT
+				tp.table_owner AS schema_name,
+				tp.partition_name,
+				tp.partition_position AS position,
+				tp.high_value AS bound,
+				tp.num_rows AS row_count,
+				NULL AS scheme_name,
+				NULL AS function_name,
+				pt.partitioning_type AS strategy
+			FROM all_tab_partitions tp
+			JOIN all_part_tables pt ON tp.table_owner = pt.owner AND tp.table_name = pt.table_name
+			WHERE 1=1`,
+		PartitionSchemaFilter: " AND tp.table_owner = %s",
+		PartitionTableFilter:  " AND tp.table_name = %s",
+		PartitionOrderBy:      " ORDER BY tp.table_owner, tp.partition_position",
+
+		PartitionKeys: `
+			SELECT
+				owner AS schema_name,
+				column_name
+			FROM all_part_key_columns
+			WHERE object_type = 'TABLE'`,
+		PartitionKeySchemaFilter: " AND owner = %s",
+		PartitionKeyTableFilter:  " AND name = %s",
+		PartitionKeyOrderBy:      " ORDER BY owner, column_position",
 	}
 }
 
@@ -217,6 +503,22 @@ func (d *OracleDialect) FunctionMetadata() FunctionMetadataSQL {
 			FROM all_source
 			WHERE owner = :1 AND name = :2 AND type = 'FUNCTION'
 			ORDER BY line`,
+
+		// Standalone functions only; DEFAULT_VALUE is a LONG, so only the presence of a default is reported
+		GetParameters: `
+			SELECT
+				owner || '.' || object_name || NVL2(overload, '#' || overload, '') AS signature,
+				position,
+				argument_name,
+				data_type,
+				CASE WHEN position = 0 THEN 'RETURN' ELSE REPLACE(in_out, '/', '') END AS direction,
+				CASE WHEN defaulted = 'Y' THEN 'DEFAULT' END AS default_value
+			FROM all_arguments
+			WHERE owner = :1 AND object_name = :2
+			  AND package_name IS NULL
+			  AND data_level = 0
+			  AND (argument_name IS NOT NULL OR position = 0)
+			ORDER BY signature, position`,
 	}
 }
 
@@ -239,6 +541,21 @@ func (d *OracleDialect) ViewMetadata() ViewMetadataSQL {
 			SELECT text
 			FROM all_views
 			WHERE owner = :1 AND view_name = :2`,
+
+		ListMaterializedViews: `
+			SELECT
+				owner AS view_schema,
+				mview_name AS view_name,
+				CASE WHEN last_refresh_date IS NULL THEN 0 ELSE 1 END AS populated,
+				refresh_mode || ' ' || refresh_method AS refresh_mode,
+				last_refresh_date AS last_refresh,
+				staleness,
+				query AS definition
+			FROM all_mviews
+			WHERE owner NOT IN ('SYS', 'SYSTEM')`,
+		MaterializedViewSchemaFilter: " AND owner = %s",
+		MaterializedViewNameFilter:   " AND mview_name LIKE %s",
+		MaterializedViewOrderBy:      " ORDER BY owner, mview_name",
 	}
 }
 
@@ -268,6 +585,390 @@ func (d *OracleDialect) TriggerMetadata() TriggerMetadataSQL {
 	}
 }
 
+// SynonymMetadata returns Oracle synonym metadata queries
+func (d *OracleDialect) SynonymMetadata() SynonymMetadataSQL {
+	return SynonymMetadataSQL{
+		ListSynonyms: `
+			SELECT
+				sy.owner AS schema_name,
+				sy.synonym_name,
+				sy.table_owner || '.' || sy.table_name AS base_object_name,
+				sy.db_link AS server_name,
+				(SELECT MIN(o.object_type) FROM all_objects o
+				 WHERE o.owner = sy.table_owner AND o.object_name = sy.table_name) AS base_object_type
+			FROM all_synonyms sy
+			WHERE sy.owner NOT IN ('SYS', 'SYSTEM')
+				AND sy.table_owner NOT IN ('SYS', 'SYSTEM')`,
+		SchemaFilter: " AND sy.owner = %s",
+		NameFilter:   " AND sy.synonym_name LIKE %s",
+		OrderBy:      " ORDER BY sy.owner, sy.synonym_name",
+	}
+}
+
+// TypeMetadata returns Oracle user-defined type metadata queries
+func (d *OracleDialect) TypeMetadata() TypeMetadataSQL {
+	return TypeMetadataSQL{
+		ListTypes: `
+			SELECT
+				t.owner AS schema_name,
+				t.type_name,
+				t.typecode AS type_kind,
+				CASE
+					WHEN t.typecode = 'COLLECTION' THEN (
+						SELECT ct.coll_type || ' OF ' || ct.elem_type_name
+						FROM all_coll_types ct
+						WHERE ct.owner = t.owner AND ct.type_name = t.type_name)
+					ELSE (
+						SELECT LISTAGG(a.attr_name || ' ' || a.attr_type_name, ', ') WITHIN GROUP (ORDER BY a.attr_no)
+						FROM all_type_attrs a
+						WHERE a.owner = t.owner AND a.type_name = t.type_name)
+				END AS definition,
+				1 AS is_nullable
+			FROM all_types t
+			WHERE t.owner IS NOT NULL
+				AND t.owner NOT IN ('SYS', 'SYSTEM')`,
+		SchemaFilter: " AND t.owner = %s",
+		NameFilter:   " AND t.type_name LIKE %s",
+		OrderBy:      " ORDER BY t.owner, t.type_name",
+		ListColumns: `
+			SELECT
+				t.owner AS schema_name,
+				t.type_name,
+				t.attr_name AS column_name,
+				t.attr_type_name ||
+				CASE
+					WHEN t.length IS NOT NULL THEN '(' || t.length || ')'
+					WHEN t.precision IS NOT NULL THEN '(' || t.precision || NVL2(t.scale, ', ' || t.scale, '') || ')'
+				END AS data_type,
+				1 AS is_nullable
+			FROM all_type_attrs t
+			WHERE t.owner NOT IN ('SYS', 'SYSTEM')`,
+		ColumnsOrderBy: " ORDER BY t.owner, t.type_name, t.attr_no",
+		ListUsage: `
+			SELECT
+				t.owner AS schema_name,
+				t.type_name,
+				a.owner AS routine_schema,
+				NVL2(a.package_name, a.package_name || '.', '') || a.object_name AS routine_name,
+				CASE
+					WHEN a.package_name IS NOT NULL THEN 'PACKAGE'
+					WHEN a.position = 0 THEN 'FUNCTION'
+					ELSE (SELECT MIN(o.object_type) FROM all_objects o
+						  WHERE o.owner = a.owner AND o.object_name = a.object_name
+							AND o.object_type IN ('FUNCTION', 'PROCEDURE'))
+				END AS routine_type,
+				a.argument_name AS parameter_name
+			FROM all_arguments a
+			JOIN all_types t ON t.owner = a.type_owner AND t.type_name = a.type_name
+			WHERE a.data_level = 0
+				AND t.owner NOT IN ('SYS', 'SYSTEM')`,
+		UsageOrderBy: " ORDER BY t.owner, t.type_name, a.owner, routine_name, a.position",
+	}
+}
+
+// DependencyMetadata returns Oracle object dependency queries
+func (d *OracleDialect) DependencyMetadata() DependencyMetadataSQL {
+	// An empty schema resolves to the current user
+	return DependencyMetadataSQL{
+		Upstream: `
+			WITH target AS (SELECT NVL(:1, USER) AS owner, :2 AS name FROM dual)
+			SELECT
+				d.referenced_owner AS schema_name,
+				d.referenced_name AS object_name,
+				d.referenced_type AS object_type,
+				'reference' AS relation
+			FROM target
+			JOIN all_dependencies d ON d.owner = target.owner AND d.name = target.name
+			WHERE d.referenced_owner NOT IN ('SYS', 'SYSTEM', 'PUBLIC')
+				AND NOT (d.referenced_owner = d.owner AND d.referenced_name = d.name)
+			UNION
+			SELECT
+				p.owner,
+				p.table_name,
+				'TABLE',
+				'foreign_key'
+			FROM target
+			JOIN all_constraints c ON c.owner = target.owner AND c.table_name = target.name
+			JOIN all_constraints p ON c.r_owner = p.owner AND c.r_constraint_name = p.constraint_name
+			WHERE c.constraint_type = 'R' AND p.table_name <> c.table_name`,
+
+		Downstream: `
+			WITH target AS (SELECT NVL(:1, USER) AS owner, :2 AS name FROM dual)
+			SELECT
+				d.owner AS schema_name,
+				d.name AS object_name,
+				d.type AS object_type,
+				'reference' AS relation
+			FROM target
+			JOIN all_dependencies d ON d.referenced_owner = target.owner AND d.referenced_name = target.name
+			WHERE NOT (d.referenced_owner = d.owner AND d.referenced_name = d.name)
+			UNION
+			SELECT
+				c.owner,
+				c.table_name,
+				'TABLE',
+				'foreign_key'
+			FROM target
+			JOIN all_constraints p ON p.owner = target.owner AND p.table_name = target.name
+			JOIN all_constraints c ON c.r_owner = p.owner AND c.r_constraint_name = p.constraint_name
+			WHERE c.constraint_type = 'R' AND p.table_name <> c.table_name`,
+	}
+}
+
+// DefinitionSearch returns the Oracle source search over all_source.
+// View text is a LONG column and is not searched.
+func (d *OracleDialect) DefinitionSearch() DefinitionSearchSQL {
+	return DefinitionSearchSQL{
+		Search: `
+			SELECT schema_name, object_name, kind, line, definition
+			FROM (
+				SELECT
+					src.owner AS schema_name,
+					src.name AS object_name,
+					CASE WHEN src.type IN ('PACKAGE', 'PACKAGE BODY') THEN 'package' ELSE LOWER(src.type) END AS kind,
+					src.type AS source_type,
+					src.line,
+					src.text AS definition
+				FROM all_source src
+				WHERE src.owner NOT IN ('SYS', 'SYSTEM')
+				  AND src.type IN ('PROCEDURE', 'FUNCTION', 'TRIGGER', 'PACKAGE', 'PACKAGE BODY')
+				  AND EXISTS (
+					SELECT 1 FROM all_source m
+					WHERE m.owner = src.owner AND m.name = src.name AND m.type = src.type
+					  AND INSTR(UPPER(m.text), UPPER(:1)) > 0)
+			) found
+			WHERE 1=1`,
+		SchemaFilter: " AND schema_name = %s",
+		KindFilter:   " AND kind IN (%s)",
+		OrderBy:      " ORDER BY schema_name, object_name, source_type, line",
+	}
+}
+
+// PermissionMetadata returns Oracle object and column privileges visible to the
+// current user. An empty schema resolves to the current user.
+func (d *OracleDialect) PermissionMetadata() PermissionMetadataSQL {
+	return PermissionMetadataSQL{
+		ListObjectPermissions: `
+			WITH target AS (SELECT NVL(:1, USER) AS owner, :2 AS name FROM dual)
+			SELECT grantee, grantee_type, privilege, state, grantor, column_name, scope
+			FROM (
+				SELECT
+					p.grantee,
+					CASE
+						WHEN p.grantee = 'PUBLIC' THEN 'PUBLIC'
+						WHEN EXISTS (SELECT 1 FROM all_users u WHERE u.username = p.grantee) THEN 'USER'
+						ELSE 'ROLE'
+					END AS grantee_type,
+					p.privilege,
+					CASE WHEN p.grantable = 'YES' THEN 'GRANT_WITH_GRANT_OPTION' ELSE 'GRANT' END AS state,
+					p.grantor,
+					p.column_name,
+					p.scope
+				FROM (
+					SELECT t.grantee, t.privilege, t.grantable, t.grantor,
+						CAST(NULL AS VARCHAR2(128)) AS column_name, 'OBJECT' AS scope
+					FROM target
+					JOIN all_tab_privs t ON t.table_schema = target.owner AND t.table_name = target.name
+					UNION ALL
+					SELECT c.grantee, c.privilege, c.grantable, c.grantor, c.column_name, 'COLUMN'
+					FROM target
+					JOIN all_col_privs c ON c.table_schema = target.owner AND c.table_name = target.name
+				) p
+			) perms
+			WHERE 1=1`,
+		GranteeFilter: " AND grantee = %s",
+		OrderBy:       " ORDER BY grantee, scope, column_name, privilege",
+
+		RLSPolicies: "", // Virtual Private Database policies are PL/SQL functions, not expressions
+	}
+}
+
+// PrincipalMetadata returns the Oracle users visible to the current user and the roles
+// granted to it (directly or through other roles), with their memberships
+func (d *OracleDialect) PrincipalMetadata() PrincipalMetadataSQL {
+	return PrincipalMetadataSQL{
+		ListPrincipals: `
+			SELECT name, principal_type, is_role, is_system, default_schema, create_date
+			FROM (
+				SELECT
+					u.username AS name,
+					'USER' AS principal_type,
+					0 AS is_role,
+					CASE WHEN u.oracle_maintained = 'Y' THEN 1 ELSE 0 END AS is_system,
+					CAST(NULL AS VARCHAR2(128)) AS default_schema,
+					u.created AS create_date
+				FROM all_users u
+				UNION ALL
+				SELECT granted_role, 'ROLE', 1, 0, NULL, NULL
+				FROM (
+					SELECT granted_role FROM user_role_privs
+					UNION
+					SELECT granted_role FROM role_role_privs
+				)
+			)
+			WHERE 1=1`,
+		NameFilter: " AND name LIKE %s",
+		OrderBy:    " ORDER BY is_role DESC, name",
+		ListRoleMembers: `
+			SELECT granted_role AS role_name, username AS member_name FROM user_role_privs
+			UNION
+			SELECT granted_role, role FROM role_role_privs
+			ORDER BY 1, 2`,
+	}
+}
+
+// JobMetadata returns the Oracle Scheduler jobs visible to the current user with the
+// status of their last run
+func (d *OracleDialect) JobMetadata() JobMetadataSQL {
+	return JobMetadataSQL{
+		Schedulers: `SELECT 'dbms_scheduler' AS scheduler FROM dual`,
+		ListJobs: map[string]string{
+			"dbms_scheduler": `
+				SELECT
+					j.owner || '.' || j.job_name AS job_name,
+					CASE j.enabled WHEN 'TRUE' THEN 1 ELSE 0 END AS enabled,
+					COALESCE(j.repeat_interval,
+						CASE WHEN j.schedule_name IS NOT NULL THEN j.schedule_owner || '.' || j.schedule_name END,
+						LOWER(j.schedule_type)) AS schedule,
+					COALESCE(j.job_action,
+						CASE WHEN j.program_name IS NOT NULL THEN j.program_owner || '.' || j.program_name END) AS command,
+					TO_CHAR(j.last_start_date, 'YYYY-MM-DD HH24:MI:SS') AS last_run_at,
+					(
+						SELECT LOWER(d.status)
+						FROM all_scheduler_job_run_details d
+						WHERE d.owner = j.owner AND d.job_name = j.job_name
+						ORDER BY d.log_date DESC
+						FETCH FIRST 1 ROWS ONLY
+					) AS last_run_status,
+					(
+						SELECT d.errors
+						FROM all_scheduler_job_run_details d
+						WHERE d.owner = j.owner AND d.job_name = j.job_name
+						ORDER BY d.log_date DESC
+						FETCH FIRST 1 ROWS ONLY
+					) AS last_run_message,
+					TO_CHAR(j.next_run_date, 'YYYY-MM-DD HH24:MI:SS') AS next_run_at,
+					NULL AS database_name,
+					j.owner,
+					j.comments AS description
+				FROM all_scheduler_jobs j`,
+		},
+	}
+}
+
+// ChangeTrackingMetadata returns nothing (Oracle change capture runs outside the database)
+func (d *OracleDialect) ChangeTrackingMetadata() ChangeTrackingMetadataSQL {
+	return ChangeTrackingMetadataSQL{}
+}
+
+// TemporalMetadata returns nothing (Oracle keeps history in undo and Flashback Data Archives,
+// read with AS OF instead of history tables)
+func (d *OracleDialect) TemporalMetadata() TemporalMetadataSQL {
+	return TemporalMetadataSQL{}
+}
+
+// RemoteServerMetadata returns the Oracle database links visible to the current user
+func (d *OracleDialect) RemoteServerMetadata() RemoteServerMetadataSQL {
+	return RemoteServerMetadataSQL{
+		ListServers: `
+			SELECT
+				l.db_link AS name,
+				'DATABASE_LINK' AS kind,
+				NULL AS provider,
+				NULL AS product,
+				l.host AS data_source,
+				NULL AS catalog,
+				'owner=' || l.owner || NVL2(l.username, ', user=' || l.username, '') AS options
+			FROM all_db_links l
+			WHERE 1=1`,
+		NameFilter: " AND l.db_link LIKE %s",
+		OrderBy:    " ORDER BY l.db_link",
+	}
+}
+
+// ConstraintMetadata returns Oracle constraint metadata queries
+// Oracle has no ON UPDATE referential actions, so on_update is always NO ACTION
+func (d *OracleDialect) ConstraintMetadata() ConstraintMetadataSQL {
+	return ConstraintMetadataSQL{
+		ListForeignKeys: `
+			SELECT
+				ac.owner AS schema_name,
+				ac.table_name,
+				ac.constraint_name,
+				acc.column_name,
+				ac_ref.owner AS referenced_schema,
+				ac_ref.table_name AS referenced_table,
+				acc_ref.column_name AS referenced_column,
+				ac.delete_rule AS on_delete,
+				'NO ACTION' AS on_update
+			FROM all_constraints ac
+			JOIN all_cons_columns acc
+				ON ac.constraint_name = acc.constraint_name
+				AND ac.owner = acc.owner
+			JOIN all_constraints ac_ref
+				ON ac.r_constraint_name = ac_ref.constraint_name
+				AND ac.r_owner = ac_ref.owner
+			JOIN all_cons_columns acc_ref
+				ON ac_ref.constraint_name = acc_ref.constraint_name
+				AND ac_ref.owner = acc_ref.owner
+				AND acc.position = acc_ref.position
+			WHERE ac.constraint_type = 'R'
+				AND ac.owner NOT IN ('SYS', 'SYSTEM')`,
+		ForeignKeySchemaFilter:          " AND ac.owner = %s",
+		ForeignKeyTableFilter:           " AND ac.table_name = %s",
+		ForeignKeyReferencedTableFilter: " AND ac_ref.table_name = %s",
+		ForeignKeyOrderBy:               " ORDER BY ac.owner, ac.table_name, ac.constraint_name, acc.position",
+		ListKeyConstraints: `
+			SELECT
+				ac.owner AS schema_name,
+				ac.table_name,
+				ac.constraint_name,
+				CASE ac.constraint_type WHEN 'P' THEN 'PRIMARY KEY' ELSE 'UNIQUE' END AS constraint_type,
+				acc.column_name,
+				acc.position
+			FROM all_constraints ac
+			JOIN all_cons_columns acc
+				ON ac.constraint_name = acc.constraint_name
+				AND ac.owner = acc.owner
+			WHERE ac.constraint_type IN ('P', 'U')
+				AND ac.owner NOT IN ('SYS', 'SYSTEM')`,
+		KeySchemaFilter: " AND ac.owner = %s",
+		KeyTableFilter:  " AND ac.table_name = %s",
+		KeyOrderBy:      " ORDER BY ac.owner, ac.table_name, ac.constraint_type, ac.constraint_name, acc.position",
+		// System-generated NOT NULL checks are excluded; they are reported as column nullability
+		ListCheckConstraints: `
+			SELECT
+				ac.owner AS schema_name,
+				ac.table_name,
+				ac.constraint_name,
+				(SELECT MIN(acc.column_name) FROM all_cons_columns acc
+					WHERE acc.owner = ac.owner AND acc.constraint_name = ac.constraint_name
+					HAVING COUNT(*) = 1) AS column_name,
+				ac.search_condition_vc AS definition
+			FROM all_constraints ac
+			WHERE ac.constraint_type = 'C'
+				AND NOT (ac.generated = 'GENERATED NAME' AND ac.search_condition_vc LIKE '% IS NOT NULL')
+				AND ac.owner NOT IN ('SYS', 'SYSTEM')`,
+		CheckSchemaFilter: " AND ac.owner = %s",
+		CheckTableFilter:  " AND ac.table_name = %s",
+		CheckOrderBy:      " ORDER BY ac.owner, ac.table_name, ac.constraint_name",
+		ListDefaults: `
+			SELECT
+				c.owner AS schema_name,
+				c.table_name,
+				c.column_name,
+				NULL AS constraint_name,
+				c.data_default AS definition
+			FROM all_tab_columns c
+			JOIN all_tables t ON t.owner = c.owner AND t.table_name = c.table_name
+			WHERE c.data_default IS NOT NULL
+				AND c.owner NOT IN ('SYS', 'SYSTEM')`,
+		DefaultSchemaFilter: " AND c.owner = %s",
+		DefaultTableFilter:  " AND c.table_name = %s",
+		DefaultOrderBy:      " ORDER BY c.owner, c.table_name, c.column_id",
+	}
+}
+
 // DatabaseInfo returns Oracle database info queries
 func (d *OracleDialect) DatabaseInfo() DatabaseInfoSQL {
 	return DatabaseInfoSQL{
@@ -291,6 +992,35 @@ func (d *OracleDialect) DatabaseInfo() DatabaseInfoSQL {
 			WHERE username NOT IN ('SYS', 'SYSTEM', 'OUTLN', 'DBSNMP')
 			ORDER BY username`,
 
+		// Segment sizes of other schemas need DBA views, so only the size of the
+		// current schema is returned
+		SchemaOverview: `
+			SELECT
+				u.username AS schema_name,
+				(SELECT COUNT(*) FROM all_objects o WHERE o.owner = u.username AND o.object_type = 'TABLE') AS tables,
+				(SELECT COUNT(*) FROM all_objects o WHERE o.owner = u.username AND o.object_type IN ('VIEW', 'MATERIALIZED VIEW')) AS views,
+				(SELECT COUNT(*) FROM all_objects o WHERE o.owner = u.username AND o.object_type = 'FUNCTION') AS functions,
+				(SELECT COUNT(*) FROM all_objects o WHERE o.owner = u.username AND o.object_type = 'PROCEDURE') AS procedures,
+				CASE WHEN u.username = USER THEN (SELECT SUM(bytes) FROM user_segments) END AS total_bytes
+			FROM all_users u
+			WHERE u.username NOT IN ('SYS', 'SYSTEM', 'OUTLN', 'DBSNMP')`,
+
+		ListDatabases: `
+			SELECT
+				name,
+				open_mode AS state,
+				NULL AS size_mb,
+				(SELECT value FROM nls_database_parameters WHERE parameter = 'NLS_CHARACTERSET') AS collation_name
+			FROM v$database`,
+
+		// Linguistic sorting and comparison follow NLS_SORT and NLS_COMP
+		Collation: `
+			SELECT
+				(SELECT value FROM nls_database_parameters WHERE parameter = 'NLS_SORT'),
+				(SELECT value FROM nls_database_parameters WHERE parameter = 'NLS_CHARACTERSET'),
+				NULL
+			FROM dual`,
+
 		SearchObjects: `
 			SELECT
 				owner AS schema_name,
@@ -306,3 +1036,106 @@ func (d *OracleDialect) DatabaseInfo() DatabaseInfoSQL {
 			ORDER BY owner, object_name`,
 	}
 }
+
+// Explain returns the Oracle statements for estimated execution plans
+func (d *OracleDialect) Explain() ExplainSQL {
+	return ExplainSQL{
+		// EXPLAIN PLAN stores the plan in PLAN_TABLE, which DBMS_XPLAN formats
+		Prefix:    "EXPLAIN PLAN FOR ",
+		PlanQuery: "SELECT plan_table_output FROM TABLE(DBMS_XPLAN.DISPLAY())",
+		Format:    "text",
+
+		// With statistics_level ALL the cursor keeps the actual rows and timings of its last run
+		AnalyzeSetup:     "ALTER SESSION SET statistics_level = ALL",
+		AnalyzePlanQuery: "SELECT plan_table_output FROM TABLE(DBMS_XPLAN.DISPLAY_CURSOR(NULL, NULL, 'ALLSTATS LAST'))",
+		AnalyzeFormat:    "text",
+	}
+}
+
+// ResultLimit returns no statements: godror closes a cursor without fetching the rows left,
+// and wrapping a query fails on duplicate column names
+func (d *OracleDialect) ResultLimit() ResultLimitSQL {
+	return ResultLimitSQL{}
+}
+
+// ParseCheck returns the Oracle block that parses a query with DBMS_SQL. The driver prepares
+// statements on the client, so preparing checks nothing.
+func (d *OracleDialect) ParseCheck() ParseCheckSQL {
+	return ParseCheckSQL{
+		Statement: `
+			DECLARE
+				c INTEGER := DBMS_SQL.OPEN_CURSOR;
+			BEGIN
+				DBMS_SQL.PARSE(c, :1, DBMS_SQL.NATIVE);
+				DBMS_SQL.CLOSE_CURSOR(c);
+			EXCEPTION
+				WHEN OTHERS THEN
+					DBMS_SQL.CLOSE_CURSOR(c);
+					RAISE;
+			END;`,
+	}
+}
+
+// Sample returns the Oracle queries of sample_table_data
+func (d *OracleDialect) Sample() SampleSQL {
+	return SampleSQL{
+		First:       "SELECT %[1]s FROM %[2]s FETCH FIRST %[3]d ROWS ONLY",
+		Random:      "SELECT %[1]s FROM %[2]s ORDER BY DBMS_RANDOM.VALUE FETCH FIRST %[3]d ROWS ONLY",
+		TableSample: "SELECT %[1]s FROM %[2]s SAMPLE (%[4]s) ORDER BY DBMS_RANDOM.VALUE FETCH FIRST %[3]d ROWS ONLY",
+	}
+}
+
+// ColumnProfile returns the Oracle column profile query, which estimates the distinct count
+// with APPROX_COUNT_DISTINCT
+func (d *OracleDialect) ColumnProfile() ColumnProfileSQL {
+	return ColumnProfileSQL{
+		Query: `
+			SELECT s.row_count, s.null_count, s.distinct_count, s.min_value, s.max_value, s.avg_length, t.top_value, t.frequency
+			FROM (
+				SELECT COUNT(*) AS row_count, COUNT(*) - COUNT(%[1]s) AS null_count, APPROX_COUNT_DISTINCT(%[1]s) AS distinct_count,
+					MIN(%[1]s) AS min_value, MAX(%[1]s) AS max_value, CAST(AVG(LENGTH(%[1]s)) AS BINARY_DOUBLE) AS avg_length
+				FROM %[2]s
+			) s
+			LEFT JOIN (
+				SELECT %[1]s AS top_value, COUNT(*) AS frequency
+				FROM %[2]s
+				WHERE %[1]s IS NOT NULL
+				GROUP BY %[1]s
+				ORDER BY COUNT(*) DESC, %[1]s
+				FETCH FIRST %[3]d ROWS ONLY
+			) t ON 1 = 1
+			ORDER BY t.frequency DESC, t.top_value`,
+		ApproximateDistinct: true,
+	}
+}
+
+// SyntaxReference returns the Oracle syntax quick reference
+func (d *OracleDialect) SyntaxReference() SyntaxReferenceSQL {
+	return SyntaxReferenceSQL{
+		Product: "Oracle",
+
+		FirstRows: "SELECT * FROM t ORDER BY id FETCH FIRST 10 ROWS ONLY (12c; before: WHERE ROWNUM <= 10)",
+		Page:      "SELECT * FROM t ORDER BY id OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY",
+
+		CurrentTimestamp: "SYSTIMESTAMP or SYSDATE",
+		CurrentDate:      "TRUNC(SYSDATE)",
+		AddDays:          "d + 7 or d + INTERVAL '7' DAY",
+		DaysBetween:      "TRUNC(d2) - TRUNC(d1)",
+		Year:             "EXTRACT(YEAR FROM d)",
+		TruncateToMonth:  "TRUNC(d, 'MM')",
+		FormatDate:       "TO_CHAR(d, 'YYYY-MM-DD')",
+
+		Concat:          "a || b (NULL is treated as an empty string)",
+		Substring:       "SUBSTR(s, 1, 3)",
+		Length:          "LENGTH(s) (NULL for an empty string)",
+		Position:        "INSTR(s, 'x')",
+		CaseInsensitive: "UPPER(s) LIKE UPPER('%x%')",
+		Aggregate:       "LISTAGG(s, ', ') WITHIN GROUP (ORDER BY s)",
+
+		QuoteIdentifier:    "\"Order Details\" (unquoted names are folded to upper case)",
+		Coalesce:           "NVL(a, b) or COALESCE(a, b)",
+		CastToText:         "TO_CHAR(a)",
+		SelectWithoutTable: "SELECT SYSDATE FROM dual",
+		Parameter:          "WHERE id = :1 AND status = :2, or :id with named parameters",
+	}
+}
@@ -3,6 +3,7 @@ package mcp
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 // PostgresDialect implements Dialect for PostgreSQL
@@ -22,9 +23,9 @@ func (d *PostgresDialect) Placeholder(index int) string {
 	return fmt.Sprintf("$%d", index)
 }
 
-// QuoteIdentifier returns "name"
+// QuoteIdentifier returns "name", with " doubled
 func (d *PostgresDialect) QuoteIdentifier(name string) string {
-	return fmt.Sprintf(`"%s"`, name)
+	return quoteIdentifier(name, `"`, `"`)
 }
 
 // PaginationClause returns LIMIT/OFFSET syntax
@@ -55,6 +56,23 @@ func (d *PostgresDialect) CurrentDatabase() string {
 	return "current_database()"
 }
 
+// SearchPathStatements returns SET search_path with all entries
+func (d *PostgresDialect) SearchPathStatements(searchPath []string) []string {
+	if len(searchPath) == 0 {
+		return nil
+	}
+	quoted := make([]string, len(searchPath))
+	for i, schema := range searchPath {
+		quoted[i] = d.QuoteIdentifier(d.NormalizeIdentifier(schema))
+	}
+	return []string{"SET search_path TO " + strings.Join(quoted, ", ")}
+}
+
+// AsOfClause returns nothing (PostgreSQL has no time-travel queries)
+func (d *PostgresDialect) AsOfClause(asOf time.Time) string {
+	return ""
+}
+
 // SystemSchemas returns PostgreSQL system schemas
 func (d *PostgresDialect) SystemSchemas() []string {
 	return []string{"pg_catalog", "information_schema", "pg_toast"}
@@ -65,6 +83,10 @@ func (d *PostgresDialect) SupportsFeature(feature DialectFeature) bool {
 	switch feature {
 	case FeatureILike:
 		return true
+	case FeatureCrossDatabase:
+		return false
+	case FeatureNamedParameters:
+		return false // lib/pq binds $n placeholders by position only
 	default:
 		return true
 	}
@@ -160,7 +182,7 @@ func (d *PostgresDialect) TableMetadata() TableMetadataSQL {
 			JOIN pg_am a ON c.relam = a.oid
 			JOIN pg_attribute a2 ON a2.attrelid = t.oid AND a2.attnum = ANY(ix.indkey)
 			WHERE n.nspname = $1 AND t.relname = $2
-			ORDER BY i.indexname`,
+			ORDER BY i.indexname, array_position(ix.indkey::int2[], a2.attnum)`,
 
 		GetForeignKeys: `
 			SELECT
@@ -179,7 +201,296 @@ func (d *PostgresDialect) TableMetadata() TableMetadataSQL {
 			WHERE tc.constraint_type = 'FOREIGN KEY'
 				AND tc.table_schema = $1
 				AND tc.table_name = $2
-			ORDER BY tc.constraint_name`,
+			ORDER BY tc.constraint_name, kcu.ordinal_position, ccu.column_name`,
+
+		// Identity (PostgreSQL 10+) and generated (PostgreSQL 12+) columns get their clause
+		DDLColumns: `
+			SELECT
+				a.attname AS column_name,
+				format_type(a.atttypid, a.atttypmod) AS column_type,
+				CASE WHEN a.attnotnull THEN 'NO' ELSE 'YES' END AS is_nullable,
+				CASE WHEN a.attgenerated = '' THEN pg_get_expr(d.adbin, d.adrelid) END AS column_default,
+				CASE
+					WHEN a.attidentity = 'a' THEN 'GENERATED ALWAYS AS IDENTITY'
+					WHEN a.attidentity = 'd' THEN 'GENERATED BY DEFAULT AS IDENTITY'
+					WHEN a.attgenerated = 's' THEN 'GENERATED ALWAYS AS (' || pg_get_expr(d.adbin, d.adrelid) || ') STORED'
+				END AS column_extra
+			FROM pg_attribute a
+			JOIN pg_class c ON a.attrelid = c.oid
+			JOIN pg_namespace n ON c.relnamespace = n.oid
+			LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+			WHERE n.nspname = $1 AND c.relname = $2
+				AND a.attnum > 0
+				AND NOT a.attisdropped
+			ORDER BY a.attnum`,
+
+		// pg_get_indexdef renders each key column or expression already quoted
+		DDLIndexes: `
+			SELECT
+				ic.relname AS index_name,
+				CASE WHEN ix.indisunique THEN 1 ELSE 0 END AS is_unique,
+				NULL AS index_kind,
+				NULLIF(am.amname, 'btree') AS index_method,
+				pg_get_indexdef(ix.indexrelid, k.ord, true) AS column_name,
+				CASE WHEN ix.indoption[k.ord - 1] & 1 = 1 THEN 1 ELSE 0 END AS is_descending,
+				pg_get_expr(ix.indpred, ix.indrelid, true) AS filter
+			FROM pg_index ix
+			JOIN pg_class ic ON ic.oid = ix.indexrelid
+			JOIN pg_class t ON t.oid = ix.indrelid
+			JOIN pg_namespace n ON n.oid = t.relnamespace
+			JOIN pg_am am ON am.oid = ic.relam
+			CROSS JOIN LATERAL generate_series(1, ix.indnkeyatts) AS k(ord)
+			WHERE n.nspname = $1 AND t.relname = $2
+				AND NOT EXISTS (
+					SELECT 1 FROM pg_constraint con
+					WHERE con.conindid = ix.indexrelid AND con.contype IN ('p', 'u', 'x'))
+			ORDER BY ic.relname, k.ord`,
+
+		DictionaryTables: `
+			SELECT
+				n.nspname AS schema_name,
+				c.relname AS table_name,
+				obj_description(c.oid, 'pg_class') AS table_comment
+			FROM pg_class c
+			JOIN pg_namespace n ON n.oid = c.relnamespace
+			WHERE c.relkind IN ('r', 'p')
+				AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')`,
+		DictionaryTableSchemaFilter: " AND n.nspname = %s",
+		DictionaryTableOrderBy:      " ORDER BY n.nspname, c.relname",
+
+		DictionaryColumns: `
+			SELECT
+				n.nspname AS schema_name,
+				c.relname AS table_name,
+				a.attname AS column_name,
+				format_type(a.atttypid, a.atttypmod) AS data_type,
+				CASE WHEN a.attnotnull THEN 'NO' ELSE 'YES' END AS is_nullable,
+				pg_get_expr(ad.adbin, ad.adrelid) AS column_default,
+				col_description(c.oid, a.attnum) AS column_comment,
+				CASE WHEN EXISTS (
+					SELECT 1 FROM pg_index i
+					WHERE i.indrelid = c.oid AND i.indisprimary AND a.attnum = ANY(i.indkey)
+				) THEN 1 ELSE 0 END AS is_primary_key
+			FROM pg_attribute a
+			JOIN pg_class c ON c.oid = a.attrelid
+			JOIN pg_namespace n ON n.oid = c.relnamespace
+			LEFT JOIN pg_attrdef ad ON ad.adrelid = a.attrelid AND ad.adnum = a.attnum
+			WHERE a.attnum > 0
+				AND NOT a.attisdropped
+				AND c.relkind IN ('r', 'p')
+				AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')`,
+		DictionaryColumnSchemaFilter: " AND n.nspname = %s",
+		DictionaryColumnTableFilter:  " AND c.relname IN (%s)",
+		DictionaryColumnOrderBy:      " ORDER BY n.nspname, c.relname, a.attnum",
+
+		FindColumns: `
+			SELECT
+				c.table_schema,
+				c.table_name,
+				CASE t.table_type WHEN 'VIEW' THEN 'view' ELSE 'table' END AS table_type,
+				c.column_name,
+				CASE
+					WHEN c.data_type = 'USER-DEFINED' THEN c.udt_name
+					WHEN c.data_type = 'ARRAY' THEN substr(c.udt_name, 2) || '[]'
+					WHEN c.character_maximum_length IS NOT NULL
+						THEN c.data_type || '(' || c.character_maximum_length || ')'
+					ELSE c.data_type
+				END AS data_type,
+				c.is_nullable,
+				c.ordinal_position
+			FROM information_schema.columns c
+			JOIN information_schema.tables t
+				ON t.table_schema = c.table_schema AND t.table_name = c.table_name
+			WHERE t.table_type IN ('BASE TABLE', 'VIEW')
+				AND c.table_schema NOT IN ('pg_catalog', 'information_schema')`,
+		FindColumnName:         "c.column_name",
+		FindColumnSchemaFilter: " AND c.table_schema = %s",
+		FindColumnOrderBy:      " ORDER BY c.table_schema, c.table_name, c.ordinal_position",
+
+		ObjectComment: `
+			SELECT
+				CASE WHEN c.relkind IN ('v', 'm') THEN 'view' ELSE 'table' END AS object_type,
+				obj_description(c.oid, 'pg_class') AS comment
+			FROM pg_class c
+			JOIN pg_namespace n ON n.oid = c.relnamespace
+			WHERE c.relkind IN ('r', 'p', 'v', 'm', 'f')
+				AND n.nspname = $1
+				AND c.relname = $2`,
+		ColumnComments: `
+			SELECT a.attname AS column_name, col_description(c.oid, a.attnum) AS comment
+			FROM pg_attribute a
+			JOIN pg_class c ON c.oid = a.attrelid
+			JOIN pg_namespace n ON n.oid = c.relnamespace
+			WHERE a.attnum > 0
+				AND NOT a.attisdropped
+				AND col_description(c.oid, a.attnum) IS NOT NULL
+				AND n.nspname = $1
+				AND c.relname = $2
+			ORDER BY a.attnum`,
+
+		// Columns with the "default" collation inherit the database collation
+		ColumnCollations: `
+			SELECT
+				a.attname,
+				format_type(a.atttypid, a.atttypmod),
+				CASE WHEN co.collname = 'default' THEN NULL ELSE co.collname::text END,
+				pg_encoding_to_char(d.encoding)::text,
+				d.datcollate::text
+			FROM pg_attribute a
+			JOIN pg_class c ON a.attrelid = c.oid
+			JOIN pg_namespace n ON c.relnamespace = n.oid
+			JOIN pg_collation co ON a.attcollation = co.oid
+			CROSS JOIN pg_database d
+			WHERE d.datname = current_database()
+				AND a.attnum > 0
+				AND NOT a.attisdropped
+				AND n.nspname = $1
+				AND c.relname = $2
+			ORDER BY a.attnum`,
+
+		// reltuples is -1 for tables that were never vacuumed or analyzed
+		TableStats: `
+			SELECT
+				n.nspname AS schema_name,
+				c.relname AS table_name,
+				CASE WHEN c.reltuples < 0 THEN NULL ELSE c.reltuples::bigint END AS row_count,
+				pg_table_size(c.oid) AS data_bytes,
+				pg_indexes_size(c.oid) AS index_bytes
+			FROM pg_class c
+			JOIN pg_namespace n ON c.relnamespace = n.oid
+			WHERE c.relkind IN ('r', 'p')
+				AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+				AND n.nspname NOT LIKE 'pg_toast%'`,
+		StatsSchemaFilter: " AND n.nspname = %s",
+		StatsTableFilter:  " AND c.relname = %s",
+		StatsOrderBy:      " ORDER BY n.nspname, c.relname",
+
+		// Identity and serial columns own their sequence; its range is capped by the column type
+		IdentityStatus: `
+			SELECT
+				n.nspname AS schema_name,
+				c.relname AS table_name,
+				a.attname AS column_name,
+				format_type(a.atttypid, a.atttypmod) AS data_type,
+				sq.start_value::text AS seed,
+				sq.increment_by::text AS increment,
+				sq.last_value::text AS current_value,
+				GREATEST(sq.min_value, CASE a.atttypid
+					WHEN 'smallint'::regtype THEN -32768
+					WHEN 'integer'::regtype THEN -2147483648
+					ELSE -9223372036854775808
+				END)::text AS min_value,
+				LEAST(sq.max_value, CASE a.atttypid
+					WHEN 'smallint'::regtype THEN 32767
+					WHEN 'integer'::regtype THEN 2147483647
+					ELSE 9223372036854775807
+				END)::text AS max_value
+			FROM pg_attribute a
+			JOIN pg_class c ON a.attrelid = c.oid
+			JOIN pg_namespace n ON c.relnamespace = n.oid
+			JOIN pg_depend d ON d.refobjid = c.oid AND d.refobjsubid = a.attnum
+				AND d.classid = 'pg_class'::regclass AND d.deptype IN ('a', 'i')
+			JOIN pg_class seq ON d.objid = seq.oid AND seq.relkind = 'S'
+			JOIN pg_namespace sn ON seq.relnamespace = sn.oid
+			JOIN pg_sequences sq ON sq.schemaname = sn.nspname AND sq.sequencename = seq.relname
+			WHERE c.relkind IN ('r', 'p')
+				AND a.attnum > 0
+				AND NOT a.attisdropped`,
+		IdentitySchemaFilter: " AND n.nspname = %s",
+		IdentityTableFilter:  " AND c.relname = %s",
+		IdentityOrderBy:      " ORDER BY n.nspname, c.relname, a.attname",
+
+		// Generated columns are STORED, or VIRTUAL from PostgreSQL 18
+		ComputedColumns: `
+			SELECT
+				n.nspname AS schema_name,
+				c.relname AS table_name,
+				a.attname AS column_name,
+				format_type(a.atttypid, a.atttypmod) AS data_type,
+				pg_get_expr(ad.adbin, ad.adrelid) AS expression,
+				CASE WHEN a.attgenerated = 's' THEN 1 ELSE 0 END AS persisted
+			FROM pg_attribute a
+			JOIN pg_class c ON a.attrelid = c.oid
+			JOIN pg_namespace n ON c.relnamespace = n.oid
+			JOIN pg_attrdef ad ON ad.adrelid = a.attrelid AND ad.adnum = a.attnum
+			WHERE a.attgenerated <> ''
+				AND c.relkind IN ('r', 'p')
+				AND a.attnum > 0
+				AND NOT a.attisdropped`,
+		ComputedSchemaFilter: " AND n.nspname = %s",
+		ComputedTableFilter:  " AND c.relname = %s",
+		ComputedOrderBy:      " ORDER BY n.nspname, c.relname, a.attnum",
+
+		// ANALYZE samples whole tables; extended statistics objects are refreshed with their table
+		Statistics: `
+			SELECT * FROM (
+				SELECT
+					t.schemaname::text AS schema_name,
+					t.relname::text AS table_name,
+					NULL::text AS statistics_name,
+					NULL::text AS columns,
+					GREATEST(t.last_analyze, t.last_autoanalyze) AS last_updated,
+					t.n_live_tup AS row_count,
+					NULL::bigint AS rows_sampled,
+					t.n_mod_since_analyze AS modifications,
+					NULL::int AS auto_created
+				FROM pg_stat_user_tables t
+				UNION ALL
+				SELECT
+					n.nspname::text,
+					c.relname::text,
+					e.stxname::text,
+					(SELECT string_agg(a.attname, ', ' ORDER BY a.attnum)
+						FROM pg_attribute a
+						WHERE a.attrelid = e.stxrelid AND a.attnum = ANY(e.stxkeys)),
+					GREATEST(t.last_analyze, t.last_autoanalyze),
+					t.n_live_tup,
+					NULL::bigint,
+					t.n_mod_since_analyze,
+					0
+				FROM pg_statistic_ext e
+				JOIN pg_class c ON e.stxrelid = c.oid
+				JOIN pg_namespace n ON c.relnamespace = n.oid
+				JOIN pg_stat_user_tables t ON t.relid = c.oid
+			) st
+			WHERE 1 = 1`,
+		StatisticsSchemaFilter: " AND st.schema_name = %s",
+		StatisticsTableFilter:  " AND st.table_name = %s",
+		StatisticsOrderBy:      " ORDER BY st.schema_name, st.table_name, st.statistics_name NULLS FIRST",
+
+		ListPartitions: `
+			SELECT
+				n.nspname AS schema_name,
+				child.relname AS partition_name,
+				ROW_NUMBER() OVER (PARTITION BY parent.oid ORDER BY child.relname)::int AS position,
+				pg_get_expr(child.relpartbound, child.oid) AS bound,
+				CASE WHEN child.reltuples < 0 THEN NULL ELSE child.reltuples::bigint END AS row_count,
+				NULL::text AS scheme_name,
+				NULL::text AS function_name,
+				CASE pt.partstrat WHEN 'r' THEN 'RANGE' WHEN 'l' THEN 'LIST' WHEN 'h' THEN 'HASH' END AS strategy
+			FROM pg_partitioned_table pt
+			JOIN pg_class parent ON pt.partrelid = parent.oid
+			JOIN pg_namespace n ON parent.relnamespace = n.oid
+			JOIN pg_inherits inh ON inh.inhparent = parent.oid
+			JOIN pg_class child ON inh.inhrelid = child.oid
+			WHERE 1=1`,
+		PartitionSchemaFilter: " AND n.nspname = %s",
+		PartitionTableFilter:  " AND parent.relname = %s",
+		PartitionOrderBy:      " ORDER BY n.nspname, child.relname",
+
+		PartitionKeys: `
+			SELECT
+				n.nspname AS schema_name,
+				COALESCE(a.attname::text, pg_get_partkeydef(c.oid)) AS column_name
+			FROM pg_partitioned_table pt
+			JOIN pg_class c ON pt.partrelid = c.oid
+			JOIN pg_namespace n ON c.relnamespace = n.oid
+			CROSS JOIN LATERAL unnest(pt.partattrs::int2[]) WITH ORDINALITY AS k(attnum, ord)
+			LEFT JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum = k.attnum
+			WHERE 1=1`,
+		PartitionKeySchemaFilter: " AND n.nspname = %s",
+		PartitionKeyTableFilter:  " AND c.relname = %s",
+		PartitionKeyOrderBy:      " ORDER BY n.nspname, k.ord",
 	}
 }
 
@@ -197,7 +508,7 @@ func (d *PostgresDialect) ProcedureMetadata() ProcedureMetadataSQL {
 				AND routine_schema NOT IN ('pg_catalog', 'information_schema')`,
 		SchemaFilter: " AND routine_schema = %s",
 		NameFilter:   " AND routine_name ILIKE %s",
-		OrderBy:      " ORDER BY routine_schema, routine_name",
+		OrderBy:      " ORDER BY routine_schema, routine_name, specific_name",
 
 		GetCode: `
 			SELECT pg_get_functiondef(p.oid)
@@ -226,13 +537,42 @@ func (d *PostgresDialect) FunctionMetadata() FunctionMetadataSQL {
 		TypeFilterAll:    "",
 		SchemaFilter:     " AND n.nspname = %s",
 		NameFilter:       " AND p.proname ILIKE %s",
-		OrderBy:          " ORDER BY n.nspname, p.proname",
+		OrderBy:          " ORDER BY n.nspname, p.proname, p.oid",
 
 		GetCode: `
 			SELECT pg_get_functiondef(p.oid)
 			FROM pg_proc p
 			JOIN pg_namespace n ON p.pronamespace = n.oid
 			WHERE n.nspname = $1 AND p.proname = $2 AND prokind = 'f'`,
+
+		GetParameters: `
+			SELECT signature, position, parameter_name, data_type, direction, default_value
+			FROM (
+				SELECT
+					p.oid::regprocedure::text AS signature,
+					0 AS position,
+					NULL AS parameter_name,
+					pg_get_function_result(p.oid) AS data_type,
+					'RETURN' AS direction,
+					NULL AS default_value
+				FROM pg_proc p
+				INNER JOIN pg_namespace n ON p.pronamespace = n.oid
+				WHERE n.nspname = $1 AND p.proname = $2 AND p.prokind = 'f'
+				UNION ALL
+				SELECT
+					p.oid::regprocedure::text,
+					pr.ordinal_position,
+					pr.parameter_name,
+					CASE WHEN pr.data_type IN ('ARRAY', 'USER-DEFINED') THEN pr.udt_name ELSE pr.data_type END,
+					pr.parameter_mode,
+					pr.parameter_default
+				FROM pg_proc p
+				INNER JOIN pg_namespace n ON p.pronamespace = n.oid
+				INNER JOIN information_schema.parameters pr
+					ON pr.specific_schema = n.nspname AND pr.specific_name = p.proname || '_' || p.oid
+				WHERE n.nspname = $1 AND p.proname = $2 AND p.prokind = 'f'
+			) params
+			ORDER BY signature, position`,
 	}
 }
 
@@ -255,6 +595,22 @@ func (d *PostgresDialect) ViewMetadata() ViewMetadataSQL {
 			SELECT view_definition
 			FROM information_schema.views
 			WHERE table_schema = $1 AND table_name = $2`,
+
+		// PostgreSQL does not record refresh times; matviews are refreshed on demand
+		ListMaterializedViews: `
+			SELECT
+				schemaname AS view_schema,
+				matviewname AS view_name,
+				ispopulated AS populated,
+				'ON DEMAND' AS refresh_mode,
+				NULL::timestamp AS last_refresh,
+				CASE WHEN ispopulated THEN NULL ELSE 'UNUSABLE' END AS staleness,
+				definition
+			FROM pg_matviews
+			WHERE schemaname NOT IN ('pg_catalog', 'information_schema')`,
+		MaterializedViewSchemaFilter: " AND schemaname = %s",
+		MaterializedViewNameFilter:   " AND matviewname ILIKE %s",
+		MaterializedViewOrderBy:      " ORDER BY schemaname, matviewname",
 	}
 }
 
@@ -289,6 +645,660 @@ func (d *PostgresDialect) TriggerMetadata() TriggerMetadataSQL {
 	}
 }
 
+// SynonymMetadata returns PostgreSQL synonym metadata queries
+func (d *PostgresDialect) SynonymMetadata() SynonymMetadataSQL {
+	// PostgreSQL has no synonyms; foreign tables are the closest alias to a remote object
+	return SynonymMetadataSQL{
+		ListSynonyms: `
+			SELECT
+				n.nspname AS schema_name,
+				c.relname AS synonym_name,
+				COALESCE(
+					(SELECT option_value FROM pg_options_to_table(ft.ftoptions)
+					 WHERE option_name IN ('schema_name', 'schema', 'dbname') LIMIT 1) || '.', ''
+				) || COALESCE(
+					(SELECT option_value FROM pg_options_to_table(ft.ftoptions)
+					 WHERE option_name IN ('table_name', 'table') LIMIT 1), c.relname
+				) AS base_object_name,
+				srv.srvname AS server_name,
+				'FOREIGN TABLE' AS base_object_type
+			FROM pg_foreign_table ft
+			JOIN pg_class c ON ft.ftrelid = c.oid
+			JOIN pg_namespace n ON c.relnamespace = n.oid
+			JOIN pg_foreign_server srv ON ft.ftserver = srv.oid
+			WHERE 1=1`,
+		SchemaFilter: " AND n.nspname = %s",
+		NameFilter:   " AND c.relname ILIKE %s",
+		OrderBy:      " ORDER BY n.nspname, c.relname",
+	}
+}
+
+// TypeMetadata returns PostgreSQL user-defined type metadata queries
+func (d *PostgresDialect) TypeMetadata() TypeMetadataSQL {
+	return TypeMetadataSQL{
+		ListTypes: `
+			SELECT
+				n.nspname AS schema_name,
+				t.typname AS type_name,
+				CASE t.typtype WHEN 'c' THEN 'COMPOSITE' WHEN 'd' THEN 'DOMAIN' WHEN 'e' THEN 'ENUM' END AS type_kind,
+				CASE t.typtype
+					WHEN 'c' THEN (
+						SELECT string_agg(a.attname || ' ' || format_type(a.atttypid, a.atttypmod), ', ' ORDER BY a.attnum)
+						FROM pg_attribute a
+						WHERE a.attrelid = t.typrelid AND a.attnum > 0 AND NOT a.attisdropped)
+					WHEN 'd' THEN format_type(t.typbasetype, t.typtypmod)
+						|| CASE WHEN t.typdefault IS NOT NULL THEN ' DEFAULT ' || t.typdefault ELSE '' END
+						|| COALESCE(' ' || (
+							SELECT string_agg(pg_get_constraintdef(c.oid), ' ' ORDER BY c.conname)
+							FROM pg_constraint c
+							WHERE c.contypid = t.oid), '')
+					WHEN 'e' THEN (
+						SELECT string_agg(quote_literal(e.enumlabel), ', ' ORDER BY e.enumsortorder)
+						FROM pg_enum e
+						WHERE e.enumtypid = t.oid)
+				END AS definition,
+				NOT t.typnotnull AS is_nullable
+			FROM pg_type t
+			JOIN pg_namespace n ON t.typnamespace = n.oid
+			LEFT JOIN pg_class cl ON cl.oid = t.typrelid
+			WHERE t.typtype IN ('c', 'd', 'e')
+				AND (t.typtype <> 'c' OR cl.relkind = 'c')
+				AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+				AND n.nspname NOT LIKE 'pg_toast%'`,
+		SchemaFilter: " AND n.nspname = %s",
+		NameFilter:   " AND t.typname ILIKE %s",
+		OrderBy:      " ORDER BY n.nspname, t.typname",
+		ListColumns: `
+			SELECT
+				n.nspname AS schema_name,
+				t.typname AS type_name,
+				a.attname AS column_name,
+				format_type(a.atttypid, a.atttypmod) AS data_type,
+				NOT a.attnotnull AS is_nullable
+			FROM pg_type t
+			JOIN pg_namespace n ON t.typnamespace = n.oid
+			JOIN pg_class cl ON cl.oid = t.typrelid AND cl.relkind = 'c'
+			JOIN pg_attribute a ON a.attrelid = t.typrelid AND a.attnum > 0 AND NOT a.attisdropped
+			WHERE n.nspname NOT IN ('pg_catalog', 'information_schema')`,
+		ColumnsOrderBy: " ORDER BY n.nspname, t.typname, a.attnum",
+		// Array arguments (mytype[]) are reported against their element type; the return
+		// type is appended after the arguments and has no name, unnamed arguments are $n
+		ListUsage: `
+			SELECT
+				n.nspname AS schema_name,
+				t.typname AS type_name,
+				pn.nspname AS routine_schema,
+				p.proname AS routine_name,
+				CASE p.prokind WHEN 'p' THEN 'PROCEDURE' ELSE 'FUNCTION' END AS routine_type,
+				CASE WHEN arg.pos > COALESCE(array_length(p.proallargtypes, 1), p.pronargs) THEN NULL
+					ELSE COALESCE(NULLIF(p.proargnames[arg.pos], ''), '$' || arg.pos)
+				END AS parameter_name
+			FROM pg_proc p
+			JOIN pg_namespace pn ON p.pronamespace = pn.oid
+			CROSS JOIN LATERAL unnest(array_append(COALESCE(p.proallargtypes, p.proargtypes::oid[]), p.prorettype))
+				WITH ORDINALITY AS arg(typid, pos)
+			JOIN pg_type at ON at.oid = arg.typid
+			JOIN pg_type t ON t.oid = CASE WHEN at.typelem <> 0 AND at.typlen = -1 THEN at.typelem ELSE at.oid END
+			JOIN pg_namespace n ON t.typnamespace = n.oid
+			LEFT JOIN pg_class cl ON cl.oid = t.typrelid
+			WHERE t.typtype IN ('c', 'd', 'e')
+				AND (t.typtype <> 'c' OR cl.relkind = 'c')
+				AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+				AND pn.nspname NOT IN ('pg_catalog', 'information_schema')`,
+		UsageOrderBy: " ORDER BY n.nspname, t.typname, pn.nspname, p.proname, arg.pos",
+	}
+}
+
+// postgresDependencyTarget resolves the relation or routine named by $1 (schema) and $2 (name)
+const postgresDependencyTarget = `
+			WITH target AS (
+				SELECT c.oid, 'pg_class'::regclass AS classid
+				FROM pg_class c
+				JOIN pg_namespace n ON c.relnamespace = n.oid
+				WHERE n.nspname = $1 AND c.relname = $2
+				UNION ALL
+				SELECT p.oid, 'pg_proc'::regclass
+				FROM pg_proc p
+				JOIN pg_namespace n ON p.pronamespace = n.oid
+				WHERE n.nspname = $1 AND p.proname = $2
+			),`
+
+// postgresDependencyObjects describes the relations and routines collected in deps
+const postgresDependencyObjects = `
+			SELECT
+				n.nspname AS schema_name,
+				c.relname AS object_name,
+				CASE c.relkind
+					WHEN 'r' THEN 'TABLE' WHEN 'p' THEN 'TABLE' WHEN 'v' THEN 'VIEW'
+					WHEN 'm' THEN 'MATERIALIZED VIEW' WHEN 'f' THEN 'FOREIGN TABLE'
+					WHEN 'S' THEN 'SEQUENCE' ELSE 'RELATION'
+				END AS object_type,
+				deps.relation
+			FROM deps
+			JOIN pg_class c ON deps.classid = 'pg_class'::regclass AND c.oid = deps.objid
+			JOIN pg_namespace n ON c.relnamespace = n.oid
+			WHERE n.nspname NOT IN ('pg_catalog', 'information_schema')
+			UNION
+			SELECT
+				n.nspname,
+				p.proname,
+				CASE p.prokind WHEN 'p' THEN 'PROCEDURE' ELSE 'FUNCTION' END,
+				deps.relation
+			FROM deps
+			JOIN pg_proc p ON deps.classid = 'pg_proc'::regclass AND p.oid = deps.objid
+			JOIN pg_namespace n ON p.pronamespace = n.oid
+			WHERE n.nspname NOT IN ('pg_catalog', 'information_schema')`
+
+// DependencyMetadata returns PostgreSQL object dependency queries
+func (d *PostgresDialect) DependencyMetadata() DependencyMetadataSQL {
+	// Views record their dependencies on their rewrite rule; plpgsql function bodies
+	// are not tracked by pg_depend, only SQL-standard bodies are
+	return DependencyMetadataSQL{
+		Upstream: postgresDependencyTarget + `
+			deps AS (
+				SELECT d.refclassid AS classid, d.refobjid AS objid, 'reference' AS relation
+				FROM target t
+				JOIN pg_rewrite r ON t.classid = 'pg_class'::regclass AND r.ev_class = t.oid
+				JOIN pg_depend d ON d.classid = 'pg_rewrite'::regclass AND d.objid = r.oid
+				WHERE d.refobjid <> t.oid
+				UNION
+				SELECT d.refclassid, d.refobjid, 'reference'
+				FROM target t
+				JOIN pg_depend d ON d.classid = t.classid AND d.objid = t.oid
+				WHERE d.deptype = 'n'
+				UNION
+				SELECT 'pg_class'::regclass, con.confrelid, 'foreign_key'
+				FROM target t
+				JOIN pg_constraint con ON t.classid = 'pg_class'::regclass AND con.conrelid = t.oid
+				WHERE con.contype = 'f' AND con.confrelid <> t.oid
+			)` + postgresDependencyObjects,
+
+		Downstream: postgresDependencyTarget + `
+			deps AS (
+				SELECT 'pg_class'::regclass AS classid, r.ev_class AS objid, 'reference' AS relation
+				FROM target t
+				JOIN pg_depend d ON d.refclassid = t.classid AND d.refobjid = t.oid AND d.classid = 'pg_rewrite'::regclass
+				JOIN pg_rewrite r ON r.oid = d.objid
+				WHERE r.ev_class <> t.oid
+				UNION
+				SELECT d.classid, d.objid, 'reference'
+				FROM target t
+				JOIN pg_depend d ON d.refclassid = t.classid AND d.refobjid = t.oid
+				WHERE d.deptype = 'n'
+				UNION
+				SELECT 'pg_class'::regclass, con.conrelid, 'foreign_key'
+				FROM target t
+				JOIN pg_constraint con ON t.classid = 'pg_class'::regclass AND con.confrelid = t.oid
+				WHERE con.contype = 'f' AND con.conrelid <> t.oid
+			)` + postgresDependencyObjects,
+	}
+}
+
+// DefinitionSearch returns the PostgreSQL source search over views, functions, procedures and triggers
+func (d *PostgresDialect) DefinitionSearch() DefinitionSearchSQL {
+	return DefinitionSearchSQL{
+		Search: `
+			SELECT schema_name, object_name, kind, line, definition
+			FROM (
+				SELECT
+					n.nspname AS schema_name,
+					c.relname AS object_name,
+					'view' AS kind,
+					1 AS line,
+					pg_get_viewdef(c.oid) AS definition
+				FROM pg_class c
+				INNER JOIN pg_namespace n ON n.oid = c.relnamespace
+				WHERE c.relkind IN ('v', 'm')
+				  AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+				UNION ALL
+				SELECT
+					n.nspname,
+					p.proname,
+					CASE WHEN p.prokind = 'p' THEN 'procedure' ELSE 'function' END,
+					1,
+					pg_get_functiondef(p.oid)
+				FROM pg_proc p
+				INNER JOIN pg_namespace n ON n.oid = p.pronamespace
+				WHERE p.prokind IN ('f', 'p')
+				  AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+				UNION ALL
+				SELECT
+					n.nspname,
+					tg.tgname,
+					'trigger',
+					1,
+					pg_get_triggerdef(tg.oid, true)
+				FROM pg_trigger tg
+				INNER JOIN pg_class c ON c.oid = tg.tgrelid
+				INNER JOIN pg_namespace n ON n.oid = c.relnamespace
+				WHERE NOT tg.tgisinternal
+				  AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+			) found
+			WHERE strpos(lower(definition), lower($1)) > 0`,
+		SchemaFilter: " AND schema_name = %s",
+		KindFilter:   " AND kind IN (%s)",
+		OrderBy:      " ORDER BY schema_name, object_name, kind, line",
+	}
+}
+
+// PermissionMetadata returns PostgreSQL object permission queries from the ACLs of
+// relations, their columns and functions (owner defaults apply when no ACL is set)
+func (d *PostgresDialect) PermissionMetadata() PermissionMetadataSQL {
+	return PermissionMetadataSQL{
+		ListObjectPermissions: `
+			SELECT grantee, grantee_type, privilege, state, grantor, column_name, scope
+			FROM (
+				SELECT
+					COALESCE(g.rolname, 'PUBLIC') AS grantee,
+					CASE WHEN g.rolname IS NULL THEN 'PUBLIC' WHEN g.rolcanlogin THEN 'USER' ELSE 'ROLE' END AS grantee_type,
+					a.privilege_type AS privilege,
+					CASE WHEN a.is_grantable THEN 'GRANT_WITH_GRANT_OPTION' ELSE 'GRANT' END AS state,
+					gr.rolname::text AS grantor,
+					NULL::text AS column_name,
+					'OBJECT' AS scope
+				FROM pg_class c
+				INNER JOIN pg_namespace n ON n.oid = c.relnamespace
+				CROSS JOIN LATERAL aclexplode(COALESCE(c.relacl, acldefault(CASE WHEN c.relkind = 'S' THEN 's' ELSE 'r' END::"char", c.relowner))) a
+				LEFT JOIN pg_roles g ON g.oid = a.grantee
+				LEFT JOIN pg_roles gr ON gr.oid = a.grantor
+				WHERE n.nspname = $1 AND c.relname = $2
+				UNION ALL
+				SELECT
+					COALESCE(g.rolname, 'PUBLIC'),
+					CASE WHEN g.rolname IS NULL THEN 'PUBLIC' WHEN g.rolcanlogin THEN 'USER' ELSE 'ROLE' END,
+					a.privilege_type,
+					CASE WHEN a.is_grantable THEN 'GRANT_WITH_GRANT_OPTION' ELSE 'GRANT' END,
+					gr.rolname::text,
+					att.attname::text,
+					'COLUMN'
+				FROM pg_class c
+				INNER JOIN pg_namespace n ON n.oid = c.relnamespace
+				INNER JOIN pg_attribute att ON att.attrelid = c.oid AND att.attacl IS NOT NULL
+				CROSS JOIN LATERAL aclexplode(att.attacl) a
+				LEFT JOIN pg_roles g ON g.oid = a.grantee
+				LEFT JOIN pg_roles gr ON gr.oid = a.grantor
+				WHERE n.nspname = $1 AND c.relname = $2
+				UNION ALL
+				SELECT
+					COALESCE(g.rolname, 'PUBLIC'),
+					CASE WHEN g.rolname IS NULL THEN 'PUBLIC' WHEN g.rolcanlogin THEN 'USER' ELSE 'ROLE' END,
+					a.privilege_type,
+					CASE WHEN a.is_grantable THEN 'GRANT_WITH_GRANT_OPTION' ELSE 'GRANT' END,
+					gr.rolname::text,
+					NULL,
+					'OBJECT'
+				FROM pg_proc p
+				INNER JOIN pg_namespace n ON n.oid = p.pronamespace
+				CROSS JOIN LATERAL aclexplode(COALESCE(p.proacl, acldefault('f', p.proowner))) a
+				LEFT JOIN pg_roles g ON g.oid = a.grantee
+				LEFT JOIN pg_roles gr ON gr.oid = a.grantor
+				WHERE n.nspname = $1 AND p.proname = $2
+			) perms
+			WHERE 1=1`,
+		GranteeFilter: " AND grantee = %s",
+		OrderBy:       " ORDER BY grantee, scope, column_name, privilege",
+
+		// Policies only apply while the table has row-level security enabled
+		RLSPolicies: `
+			SELECT
+				n.nspname::text,
+				c.relname::text,
+				p.polname::text,
+				CASE WHEN c.relrowsecurity THEN 1 ELSE 0 END,
+				CASE WHEN c.relforcerowsecurity THEN 1 ELSE 0 END,
+				CASE WHEN p.oid IS NULL THEN NULL WHEN p.polpermissive THEN 'PERMISSIVE' ELSE 'RESTRICTIVE' END,
+				CASE p.polcmd WHEN 'r' THEN 'SELECT' WHEN 'a' THEN 'INSERT' WHEN 'w' THEN 'UPDATE' WHEN 'd' THEN 'DELETE' WHEN '*' THEN 'ALL' END,
+				(SELECT string_agg(COALESCE(r.rolname::text, 'PUBLIC'), ', ' ORDER BY r.rolname NULLS FIRST)
+					FROM unnest(p.polroles) AS pr(oid)
+					LEFT JOIN pg_roles r ON r.oid = pr.oid),
+				pg_get_expr(p.polqual, p.polrelid),
+				pg_get_expr(p.polwithcheck, p.polrelid)
+			FROM pg_class c
+			JOIN pg_namespace n ON c.relnamespace = n.oid
+			LEFT JOIN pg_policy p ON p.polrelid = c.oid
+			WHERE c.relkind IN ('r', 'p')
+				AND (p.oid IS NOT NULL OR c.relrowsecurity)`,
+		RLSSchemaFilter: " AND n.nspname = %s",
+		RLSTableFilter:  " AND c.relname = %s",
+		RLSOrderBy:      " ORDER BY n.nspname, c.relname, p.polname NULLS FIRST",
+	}
+}
+
+// PrincipalMetadata returns PostgreSQL roles (login roles are reported as users) with their
+// role memberships. Predefined pg_* roles are system principals.
+func (d *PostgresDialect) PrincipalMetadata() PrincipalMetadataSQL {
+	return PrincipalMetadataSQL{
+		ListPrincipals: `
+			SELECT
+				r.rolname AS name,
+				CASE WHEN r.rolcanlogin THEN 'USER' ELSE 'ROLE' END AS principal_type,
+				CASE WHEN r.rolcanlogin THEN 0 ELSE 1 END AS is_role,
+				CASE WHEN r.rolname LIKE 'pg\_%' THEN 1 ELSE 0 END AS is_system,
+				NULL::text AS default_schema,
+				NULL::timestamp AS create_date
+			FROM pg_roles r
+			WHERE 1=1`,
+		NameFilter: " AND r.rolname LIKE %s",
+		OrderBy:    " ORDER BY is_role DESC, r.rolname",
+		ListRoleMembers: `
+			SELECT r.rolname AS role_name, m.rolname AS member_name
+			FROM pg_auth_members am
+			INNER JOIN pg_roles r ON am.roleid = r.oid
+			INNER JOIN pg_roles m ON am.member = m.oid
+			ORDER BY r.rolname, m.rolname`,
+	}
+}
+
+// JobMetadata returns the jobs of the pg_cron and pgAgent extensions, when installed.
+// pg_cron keeps its run history in cron.job_run_details (pg_cron 1.3 and later).
+func (d *PostgresDialect) JobMetadata() JobMetadataSQL {
+	return JobMetadataSQL{
+		Schedulers: `
+			SELECT 'pg_cron' AS scheduler
+			WHERE to_regclass('cron.job') IS NOT NULL
+			  AND to_regclass('cron.job_run_details') IS NOT NULL
+			UNION ALL
+			SELECT 'pgagent'
+			WHERE to_regclass('pgagent.pga_job') IS NOT NULL`,
+		ListJobs: map[string]string{
+			"pg_cron": `
+				SELECT
+					COALESCE(j.jobname, 'job ' || j.jobid) AS job_name,
+					CASE WHEN j.active THEN 1 ELSE 0 END AS enabled,
+					j.schedule,
+					j.command,
+					to_char(r.start_time, 'YYYY-MM-DD HH24:MI:SS') AS last_run_at,
+					r.status AS last_run_status,
+					NULLIF(r.return_message, '') AS last_run_message,
+					NULL::text AS next_run_at,
+					j.database AS database_name,
+					j.username AS owner,
+					NULL::text AS description
+				FROM cron.job j
+				LEFT JOIN LATERAL (
+					SELECT d.start_time, d.status, d.return_message
+					FROM cron.job_run_details d
+					WHERE d.jobid = j.jobid
+					ORDER BY d.start_time DESC NULLS LAST
+					LIMIT 1
+				) r ON true`,
+			"pgagent": `
+				SELECT
+					j.jobname AS job_name,
+					CASE WHEN j.jobenabled THEN 1 ELSE 0 END AS enabled,
+					(
+						SELECT string_agg(s.jscname || CASE WHEN s.jscenabled THEN '' ELSE ' (disabled)' END, '; ' ORDER BY s.jscname)
+						FROM pgagent.pga_schedule s
+						WHERE s.jscjobid = j.jobid
+					) AS schedule,
+					(
+						SELECT string_agg(st.jstname || ': ' || COALESCE(st.jstcode, ''), E'\n' ORDER BY st.jstname)
+						FROM pgagent.pga_jobstep st
+						WHERE st.jstjobid = j.jobid
+					) AS command,
+					to_char(j.joblastrun, 'YYYY-MM-DD HH24:MI:SS') AS last_run_at,
+					(
+						SELECT CASE l.jlgstatus
+							WHEN 's' THEN 'succeeded'
+							WHEN 'f' THEN 'failed'
+							WHEN 'r' THEN 'running'
+							WHEN 'i' THEN 'failed'
+							WHEN 'd' THEN 'aborted'
+							ELSE l.jlgstatus::text
+						END
+						FROM pgagent.pga_joblog l
+						WHERE l.jlgjobid = j.jobid
+						ORDER BY l.jlgstart DESC
+						LIMIT 1
+					) AS last_run_status,
+					NULL::text AS last_run_message,
+					to_char(j.jobnextrun, 'YYYY-MM-DD HH24:MI:SS') AS next_run_at,
+					(
+						SELECT string_agg(DISTINCT st.jstdbname, ', ')
+						FROM pgagent.pga_jobstep st
+						WHERE st.jstjobid = j.jobid AND st.jstdbname <> ''
+					) AS database_name,
+					NULL::text AS owner,
+					NULLIF(j.jobdesc, '') AS description
+				FROM pgagent.pga_job j`,
+		},
+	}
+}
+
+// ChangeTrackingMetadata returns the logical replication settings, the tables of each
+// publication with their replica identity, and the replication slots holding WAL back
+func (d *PostgresDialect) ChangeTrackingMetadata() ChangeTrackingMetadataSQL {
+	return ChangeTrackingMetadataSQL{
+		Settings: `
+			SELECT name AS setting,
+				CASE WHEN name = 'max_slot_wal_keep_size' AND setting = '-1' THEN 'unlimited'
+					ELSE setting || COALESCE(' ' || unit, '')
+				END AS value
+			FROM pg_settings
+			WHERE name IN ('wal_level', 'max_replication_slots', 'max_wal_senders', 'wal_keep_size',
+				'max_slot_wal_keep_size', 'track_commit_timestamp')`,
+		ListTables: `
+			SELECT
+				pt.schemaname::text AS schema_name,
+				pt.tablename::text AS table_name,
+				'publication' AS mechanism,
+				pt.pubname::text AS name,
+				concat_ws(', ',
+					CASE WHEN p.pubinsert THEN 'insert' END,
+					CASE WHEN p.pubupdate THEN 'update' END,
+					CASE WHEN p.pubdelete THEN 'delete' END,
+					CASE WHEN p.pubtruncate THEN 'truncate' END,
+					CASE WHEN p.puballtables THEN 'all tables' END,
+					'replica identity ' || CASE c.relreplident
+						WHEN 'd' THEN 'default'
+						WHEN 'n' THEN 'nothing'
+						WHEN 'f' THEN 'full'
+						WHEN 'i' THEN 'index'
+					END) AS details
+			FROM pg_publication_tables pt
+			INNER JOIN pg_publication p ON p.pubname = pt.pubname
+			INNER JOIN pg_namespace n ON n.nspname = pt.schemaname
+			INNER JOIN pg_class c ON c.relnamespace = n.oid AND c.relname = pt.tablename`,
+		ListSlots: `
+			SELECT
+				slot_name,
+				plugin,
+				slot_type,
+				CASE WHEN active THEN 1 ELSE 0 END AS active,
+				database,
+				CASE WHEN pg_is_in_recovery() THEN NULL
+					ELSE pg_wal_lsn_diff(pg_current_wal_lsn(), restart_lsn)::bigint
+				END AS retained_bytes
+			FROM pg_replication_slots
+			ORDER BY slot_name`,
+	}
+}
+
+// TemporalMetadata returns the tables versioned by the temporal_tables extension (or its
+// PL/pgSQL port), whose versioning trigger takes the period range column and the history
+// table, and by the periods extension
+func (d *PostgresDialect) TemporalMetadata() TemporalMetadataSQL {
+	return TemporalMetadataSQL{
+		Sources: `
+			SELECT 'temporal_tables' AS source
+			WHERE EXISTS (SELECT 1 FROM pg_proc WHERE proname = 'versioning')
+			UNION ALL
+			SELECT 'periods'
+			WHERE to_regclass('periods.system_versioning') IS NOT NULL`,
+		ListTables: map[string]string{
+			"temporal_tables": `
+				SELECT DISTINCT
+					n.nspname::text AS schema_name,
+					c.relname::text AS table_name,
+					hn.nspname::text AS history_schema,
+					COALESCE(hc.relname::text, v.history) AS history_table,
+					'lower(' || v.period || ')' AS period_start,
+					'upper(' || v.period || ')' AS period_end,
+					NULL::text AS retention
+				FROM pg_trigger t
+				JOIN pg_proc p ON t.tgfoid = p.oid AND p.proname = 'versioning'
+				JOIN pg_class c ON t.tgrelid = c.oid
+				JOIN pg_namespace n ON c.relnamespace = n.oid
+				CROSS JOIN LATERAL (
+					SELECT
+						split_part(encode(t.tgargs, 'escape'), '\000', 1) AS period,
+						split_part(encode(t.tgargs, 'escape'), '\000', 2) AS history
+				) v
+				LEFT JOIN pg_class hc ON hc.oid = to_regclass(v.history)
+				LEFT JOIN pg_namespace hn ON hc.relnamespace = hn.oid
+				WHERE NOT t.tgisinternal`,
+			"periods": `
+				SELECT
+					n.nspname::text AS schema_name,
+					c.relname::text AS table_name,
+					hn.nspname::text AS history_schema,
+					hc.relname::text AS history_table,
+					pp.start_column_name::text AS period_start,
+					pp.end_column_name::text AS period_end,
+					NULL::text AS retention
+				FROM periods.system_versioning sv
+				JOIN periods.periods pp ON pp.table_name = sv.table_name AND pp.period_name = sv.period_name
+				JOIN pg_class c ON sv.table_name = c.oid
+				JOIN pg_namespace n ON c.relnamespace = n.oid
+				JOIN pg_class hc ON sv.history_table_name = hc.oid
+				JOIN pg_namespace hn ON hc.relnamespace = hn.oid`,
+		},
+	}
+}
+
+// RemoteServerMetadata returns PostgreSQL foreign servers with the foreign tables defined
+// on them. Options whose name contains password are left out.
+func (d *PostgresDialect) RemoteServerMetadata() RemoteServerMetadataSQL {
+	return RemoteServerMetadataSQL{
+		ListServers: `
+			SELECT
+				srv.srvname AS name,
+				'FOREIGN_SERVER' AS kind,
+				fdw.fdwname AS provider,
+				NULLIF(CONCAT_WS(' ', srv.srvtype, srv.srvversion), '') AS product,
+				NULLIF(CONCAT_WS(':',
+					(SELECT option_value FROM pg_options_to_table(srv.srvoptions) WHERE option_name = 'host'),
+					(SELECT option_value FROM pg_options_to_table(srv.srvoptions) WHERE option_name = 'port')
+				), '') AS data_source,
+				(SELECT option_value FROM pg_options_to_table(srv.srvoptions)
+				 WHERE option_name IN ('dbname', 'database') LIMIT 1) AS catalog,
+				(SELECT string_agg(option_name || '=' || option_value, ', ' ORDER BY option_name)
+				 FROM pg_options_to_table(srv.srvoptions)
+				 WHERE option_name NOT ILIKE '%password%') AS options
+			FROM pg_foreign_server srv
+			JOIN pg_foreign_data_wrapper fdw ON srv.srvfdw = fdw.oid
+			WHERE 1=1`,
+		NameFilter: " AND srv.srvname ILIKE %s",
+		OrderBy:    " ORDER BY srv.srvname",
+		ListForeignTables: `
+			SELECT
+				srv.srvname AS server_name,
+				n.nspname AS schema_name,
+				c.relname AS table_name,
+				(SELECT option_value FROM pg_options_to_table(ft.ftoptions)
+				 WHERE option_name IN ('schema_name', 'schema', 'dbname') LIMIT 1) AS remote_schema,
+				(SELECT option_value FROM pg_options_to_table(ft.ftoptions)
+				 WHERE option_name IN ('table_name', 'table') LIMIT 1) AS remote_name,
+				(SELECT string_agg(option_name || '=' || option_value, ', ' ORDER BY option_name)
+				 FROM pg_options_to_table(ft.ftoptions)
+				 WHERE option_name NOT ILIKE '%password%') AS options
+			FROM pg_foreign_table ft
+			JOIN pg_class c ON ft.ftrelid = c.oid
+			JOIN pg_namespace n ON c.relnamespace = n.oid
+			JOIN pg_foreign_server srv ON ft.ftserver = srv.oid
+			ORDER BY srv.srvname, n.nspname, c.relname`,
+	}
+}
+
+// ConstraintMetadata returns PostgreSQL constraint metadata queries
+func (d *PostgresDialect) ConstraintMetadata() ConstraintMetadataSQL {
+	return ConstraintMetadataSQL{
+		ListForeignKeys: `
+			SELECT
+				n.nspname AS schema_name,
+				c.relname AS table_name,
+				con.conname AS constraint_name,
+				a.attname AS column_name,
+				rn.nspname AS referenced_schema,
+				rc.relname AS referenced_table,
+				ra.attname AS referenced_column,
+				CASE con.confdeltype
+					WHEN 'a' THEN 'NO ACTION' WHEN 'r' THEN 'RESTRICT' WHEN 'c' THEN 'CASCADE'
+					WHEN 'n' THEN 'SET NULL' WHEN 'd' THEN 'SET DEFAULT'
+				END AS on_delete,
+				CASE con.confupdtype
+					WHEN 'a' THEN 'NO ACTION' WHEN 'r' THEN 'RESTRICT' WHEN 'c' THEN 'CASCADE'
+					WHEN 'n' THEN 'SET NULL' WHEN 'd' THEN 'SET DEFAULT'
+				END AS on_update
+			FROM pg_constraint con
+			JOIN pg_class c ON con.conrelid = c.oid
+			JOIN pg_namespace n ON c.relnamespace = n.oid
+			JOIN pg_class rc ON con.confrelid = rc.oid
+			JOIN pg_namespace rn ON rc.relnamespace = rn.oid
+			CROSS JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(attnum, refattnum, ord)
+			JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+			JOIN pg_attribute ra ON ra.attrelid = con.confrelid AND ra.attnum = k.refattnum
+			WHERE con.contype = 'f'
+				AND n.nspname NOT IN ('pg_catalog', 'information_schema')`,
+		ForeignKeySchemaFilter:          " AND n.nspname = %s",
+		ForeignKeyTableFilter:           " AND c.relname = %s",
+		ForeignKeyReferencedTableFilter: " AND rc.relname = %s",
+		ForeignKeyOrderBy:               " ORDER BY n.nspname, c.relname, con.conname, k.ord",
+		ListKeyConstraints: `
+			SELECT
+				n.nspname AS schema_name,
+				c.relname AS table_name,
+				con.conname AS constraint_name,
+				CASE con.contype WHEN 'p' THEN 'PRIMARY KEY' ELSE 'UNIQUE' END AS constraint_type,
+				a.attname AS column_name,
+				k.ord AS position
+			FROM pg_constraint con
+			JOIN pg_class c ON con.conrelid = c.oid
+			JOIN pg_namespace n ON c.relnamespace = n.oid
+			CROSS JOIN LATERAL unnest(con.conkey) WITH ORDINALITY AS k(attnum, ord)
+			JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+			WHERE con.contype IN ('p', 'u')
+				AND n.nspname NOT IN ('pg_catalog', 'information_schema')`,
+		KeySchemaFilter: " AND n.nspname = %s",
+		KeyTableFilter:  " AND c.relname = %s",
+		KeyOrderBy:      " ORDER BY n.nspname, c.relname, con.contype, con.conname, k.ord",
+		ListCheckConstraints: `
+			SELECT
+				n.nspname AS schema_name,
+				c.relname AS table_name,
+				con.conname AS constraint_name,
+				(SELECT a.attname FROM pg_attribute a
+					WHERE a.attrelid = con.conrelid AND a.attnum = con.conkey[1]
+						AND array_length(con.conkey, 1) = 1) AS column_name,
+				pg_get_constraintdef(con.oid) AS definition
+			FROM pg_constraint con
+			JOIN pg_class c ON con.conrelid = c.oid
+			JOIN pg_namespace n ON c.relnamespace = n.oid
+			WHERE con.contype = 'c'
+				AND n.nspname NOT IN ('pg_catalog', 'information_schema')`,
+		CheckSchemaFilter: " AND n.nspname = %s",
+		CheckTableFilter:  " AND c.relname = %s",
+		CheckOrderBy:      " ORDER BY n.nspname, c.relname, con.conname",
+		ListDefaults: `
+			SELECT
+				n.nspname AS schema_name,
+				c.relname AS table_name,
+				a.attname AS column_name,
+				NULL AS constraint_name,
+				pg_get_expr(d.adbin, d.adrelid) AS definition
+			FROM pg_attrdef d
+			JOIN pg_class c ON d.adrelid = c.oid
+			JOIN pg_namespace n ON c.relnamespace = n.oid
+			JOIN pg_attribute a ON a.attrelid = d.adrelid AND a.attnum = d.adnum
+			WHERE c.relkind IN ('r', 'p')
+				AND NOT a.attisdropped
+				AND n.nspname NOT IN ('pg_catalog', 'information_schema')`,
+		DefaultSchemaFilter: " AND n.nspname = %s",
+		DefaultTableFilter:  " AND c.relname = %s",
+		DefaultOrderBy:      " ORDER BY n.nspname, c.relname, a.attnum",
+	}
+}
+
 // DatabaseInfo returns PostgreSQL database info queries
 func (d *PostgresDialect) DatabaseInfo() DatabaseInfoSQL {
 	return DatabaseInfoSQL{
@@ -321,6 +1331,51 @@ func (d *PostgresDialect) DatabaseInfo() DatabaseInfoSQL {
 			WHERE schema_name NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
 			ORDER BY schema_name`,
 
+		SchemaOverview: `
+			SELECT
+				n.nspname AS schema_name,
+				(SELECT COUNT(*) FROM pg_class c WHERE c.relnamespace = n.oid AND c.relkind IN ('r', 'p') AND NOT c.relispartition) AS tables,
+				(SELECT COUNT(*) FROM pg_class c WHERE c.relnamespace = n.oid AND c.relkind IN ('v', 'm')) AS views,
+				(SELECT COUNT(*) FROM pg_proc p WHERE p.pronamespace = n.oid AND p.prokind = 'f') AS functions,
+				(SELECT COUNT(*) FROM pg_proc p WHERE p.pronamespace = n.oid AND p.prokind = 'p') AS procedures,
+				(SELECT SUM(pg_total_relation_size(c.oid))::bigint
+					FROM pg_class c
+					WHERE c.relnamespace = n.oid AND c.relkind IN ('r', 'p', 'm')) AS total_bytes
+			FROM pg_namespace n
+			WHERE n.nspname NOT IN ('pg_catalog', 'information_schema')
+				AND n.nspname NOT LIKE 'pg_toast%'
+				AND n.nspname NOT LIKE 'pg_temp%'`,
+
+		ListDatabases: `
+			SELECT
+				datname,
+				CASE WHEN datallowconn THEN 'ONLINE' ELSE 'NO CONNECTIONS' END AS state,
+				CASE WHEN has_database_privilege(datname, 'CONNECT')
+					THEN ROUND(pg_database_size(datname) / 1048576.0, 2)
+				END AS size_mb,
+				datcollate
+			FROM pg_database
+			WHERE NOT datistemplate
+			ORDER BY datname`,
+
+		ListExtensions: `
+			SELECT
+				e.extname,
+				e.extversion,
+				n.nspname AS schema_name,
+				a.default_version,
+				d.description
+			FROM pg_extension e
+			JOIN pg_namespace n ON e.extnamespace = n.oid
+			LEFT JOIN pg_available_extensions a ON a.name = e.extname
+			LEFT JOIN pg_description d ON d.objoid = e.oid AND d.classoid = 'pg_extension'::regclass
+			ORDER BY e.extname`,
+
+		Collation: `
+			SELECT datcollate::text, pg_encoding_to_char(encoding)::text, NULL::text
+			FROM pg_database
+			WHERE datname = current_database()`,
+
 		SearchObjects: `
 			SELECT
 				table_schema AS schema_name,
@@ -336,3 +1391,89 @@ func (d *PostgresDialect) DatabaseInfo() DatabaseInfoSQL {
 			ORDER BY table_schema, table_name`,
 	}
 }
+
+// Explain returns the PostgreSQL statements for estimated execution plans
+func (d *PostgresDialect) Explain() ExplainSQL {
+	return ExplainSQL{
+		Prefix: "EXPLAIN (FORMAT JSON) ",
+		Format: "json",
+
+		AnalyzePrefix: "EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) ",
+		AnalyzeFormat: "json",
+	}
+}
+
+// ResultLimit returns the PostgreSQL statements that cap the rows of a query
+func (d *PostgresDialect) ResultLimit() ResultLimitSQL {
+	return ResultLimitSQL{
+		WrapLimit: "SELECT * FROM (\n%s\n) AS limited_rows LIMIT %d",
+	}
+}
+
+// ParseCheck returns no statements: the driver prepares statements on the server
+func (d *PostgresDialect) ParseCheck() ParseCheckSQL {
+	return ParseCheckSQL{}
+}
+
+// Sample returns the PostgreSQL queries of sample_table_data. TABLESAMPLE SYSTEM reads whole
+// pages, so it is cheap on large tables.
+func (d *PostgresDialect) Sample() SampleSQL {
+	return SampleSQL{
+		First:       "SELECT %[1]s FROM %[2]s LIMIT %[3]d",
+		Random:      "SELECT %[1]s FROM %[2]s ORDER BY random() LIMIT %[3]d",
+		TableSample: "SELECT %[1]s FROM %[2]s TABLESAMPLE SYSTEM (%[4]s) ORDER BY random() LIMIT %[3]d",
+	}
+}
+
+// ColumnProfile returns the PostgreSQL column profile query
+func (d *PostgresDialect) ColumnProfile() ColumnProfileSQL {
+	return ColumnProfileSQL{
+		Query: `
+			SELECT s.row_count, s.null_count, s.distinct_count, s.min_value, s.max_value, s.avg_length, t.top_value, t.frequency
+			FROM (
+				SELECT COUNT(*) AS row_count, COUNT(*) - COUNT(%[1]s) AS null_count, COUNT(DISTINCT %[1]s) AS distinct_count,
+					MIN(%[1]s) AS min_value, MAX(%[1]s) AS max_value, AVG(LENGTH(%[1]s::text))::float8 AS avg_length
+				FROM %[2]s
+			) s
+			LEFT JOIN (
+				SELECT %[1]s AS top_value, COUNT(*) AS frequency
+				FROM %[2]s
+				WHERE %[1]s IS NOT NULL
+				GROUP BY %[1]s
+				ORDER BY COUNT(*) DESC, %[1]s
+				LIMIT %[3]d
+			) t ON 1 = 1
+			ORDER BY t.frequency DESC, t.top_value`,
+	}
+}
+
+// SyntaxReference returns the PostgreSQL syntax quick reference
+func (d *PostgresDialect) SyntaxReference() SyntaxReferenceSQL {
+	return SyntaxReferenceSQL{
+		Product: "PostgreSQL",
+
+		FirstRows: "SELECT * FROM t ORDER BY id LIMIT 10",
+		Page:      "SELECT * FROM t ORDER BY id LIMIT 10 OFFSET 20",
+
+		CurrentTimestamp: "now()",
+		CurrentDate:      "CURRENT_DATE",
+		AddDays:          "d + INTERVAL '7 days'",
+		DaysBetween:      "d2::date - d1::date",
+		Year:             "EXTRACT(YEAR FROM d)",
+		TruncateToMonth:  "date_trunc('month', d)",
+		FormatDate:       "to_char(d, 'YYYY-MM-DD')",
+
+		Concat:          "a || b (NULL if either is NULL) or concat(a, b)",
+		Substring:       "substring(s FROM 1 FOR 3)",
+		Length:          "length(s)",
+		Position:        "position('x' IN s)",
+		CaseInsensitive: "s ILIKE '%x%'",
+		Aggregate:       "string_agg(s, ', ' ORDER BY s)",
+
+		QuoteIdentifier:    "\"Order Details\" (unquoted names are folded to lower case)",
+		Coalesce:           "COALESCE(a, b)",
+		CastToText:         "a::text",
+		SelectWithoutTable: "SELECT now()",
+		Parameter:          "WHERE id = $1 AND status = $2",
+	}
+}
@@ -3,6 +3,7 @@ package mcp
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 // SQLiteDialect implements Dialect for SQLite
@@ -22,9 +23,9 @@ func (d *SQLiteDialect) Placeholder(index int) string {
 	return "?"
 }
 
-// QuoteIdentifier returns "name"
+// QuoteIdentifier returns "name", with " doubled
 func (d *SQLiteDialect) QuoteIdentifier(name string) string {
-	return fmt.Sprintf(`"%s"`, name)
+	return quoteIdentifier(name, `"`, `"`)
 }
 
 // PaginationClause returns LIMIT/OFFSET syntax
@@ -47,6 +48,16 @@ func (d *SQLiteDialect) CurrentDatabase() string {
 	return "'main'"
 }
 
+// SearchPathStatements returns nothing (SQLite has no schemas)
+func (d *SQLiteDialect) SearchPathStatements(searchPath []string) []string {
+	return nil
+}
+
+// AsOfClause returns nothing (SQLite has no time-travel queries)
+func (d *SQLiteDialect) AsOfClause(asOf time.Time) string {
+	return ""
+}
+
 // SystemSchemas returns empty (SQLite has no schemas)
 func (d *SQLiteDialect) SystemSchemas() []string {
 	return []string{}
@@ -61,6 +72,12 @@ func (d *SQLiteDialect) SupportsFeature(feature DialectFeature) bool {
 		return false
 	case FeatureSchemas:
 		return false
+	case FeatureCrossDatabase:
+		return false
+	case FeatureMaterializedViews:
+		return false
+	case FeatureReadOnlyTransactions:
+		return false // the driver ignores them
 	default:
 		return true
 	}
@@ -94,6 +111,76 @@ func (d *SQLiteDialect) TableMetadata() TableMetadataSQL {
 		GetIndexes: "PRAGMA index_list(%s)",
 
 		GetForeignKeys: "PRAGMA foreign_key_list(%s)",
+
+		// sqlite_master keeps the original CREATE statements; automatic indexes have no sql
+		DDLScript: `
+			SELECT sql FROM sqlite_master
+			WHERE tbl_name = ? AND type IN ('table', 'index') AND sql IS NOT NULL
+			ORDER BY CASE type WHEN 'table' THEN 0 ELSE 1 END, name`,
+
+		// SQLite has no schemas or comments
+		DictionaryTables: `
+			SELECT 'main' AS table_schema, name AS table_name, NULL AS table_comment
+			FROM sqlite_master
+			WHERE type = 'table'
+				AND name NOT LIKE 'sqlite_%'`,
+		DictionaryTableOrderBy: " ORDER BY name",
+
+		DictionaryColumns: `
+			SELECT
+				'main' AS table_schema,
+				m.name AS table_name,
+				p.name AS column_name,
+				p.type AS data_type,
+				CASE WHEN p."notnull" = 1 THEN 'NO' ELSE 'YES' END AS is_nullable,
+				p.dflt_value AS column_default,
+				NULL AS column_comment,
+				CASE WHEN p.pk > 0 THEN 1 ELSE 0 END AS is_primary_key
+			FROM sqlite_master m, pragma_table_info(m.name) p
+			WHERE m.type = 'table'
+				AND m.name NOT LIKE 'sqlite_%'`,
+		DictionaryColumnTableFilter: " AND m.name IN (%s)",
+		DictionaryColumnOrderBy:     " ORDER BY m.name, p.cid",
+
+		FindColumns: `
+			SELECT
+				'main' AS table_schema,
+				m.name AS table_name,
+				m.type AS table_type,
+				p.name AS column_name,
+				p.type AS data_type,
+				CASE WHEN p."notnull" = 1 THEN 'NO' ELSE 'YES' END AS is_nullable,
+				p.cid + 1 AS position
+			FROM sqlite_master m, pragma_table_info(m.name) p
+			WHERE m.type IN ('table', 'view')
+				AND m.name NOT LIKE 'sqlite_%'`,
+		FindColumnName:    "p.name",
+		FindColumnOrderBy: " ORDER BY m.name, p.cid",
+
+		TableStats:     "", // SQLite keeps no per-table statistics without ANALYZE/dbstat
+		IdentityStatus: "", // SQLite rowids use the whole 64-bit range
+
+		// Generated columns (SQLite 3.31+) are hidden 2 (VIRTUAL) or 3 (STORED) in table_xinfo;
+		// their expressions are only kept in the CREATE TABLE statement
+		ComputedColumns: `
+			SELECT
+				'main' AS schema_name,
+				m.name AS table_name,
+				p.name AS column_name,
+				p.type AS data_type,
+				m.sql AS expression,
+				CASE WHEN p.hidden = 3 THEN 1 ELSE 0 END AS persisted
+			FROM sqlite_master m, pragma_table_xinfo(m.name) p
+			WHERE m.type = 'table'
+				AND m.name NOT LIKE 'sqlite_%'
+				AND p.hidden IN (2, 3)`,
+		ComputedExpressionIsTableSQL: true,
+		ComputedTableFilter:          " AND m.name = %s",
+		ComputedOrderBy:              " ORDER BY m.name, p.cid",
+
+		Statistics: "", // sqlite_stat1 exists only after ANALYZE and records no update time
+
+		ListPartitions: "", // SQLite has no table partitioning
 	}
 }
 
@@ -155,6 +242,184 @@ func (d *SQLiteDialect) TriggerMetadata() TriggerMetadataSQL {
 	}
 }
 
+// SynonymMetadata returns SQLite synonym metadata queries
+func (d *SQLiteDialect) SynonymMetadata() SynonymMetadataSQL {
+	return SynonymMetadataSQL{
+		ListSynonyms: "", // SQLite doesn't have synonyms
+	}
+}
+
+// TypeMetadata returns SQLite user-defined type metadata queries
+func (d *SQLiteDialect) TypeMetadata() TypeMetadataSQL {
+	return TypeMetadataSQL{
+		ListTypes: "", // SQLite doesn't have user-defined types
+	}
+}
+
+// DependencyMetadata returns SQLite object dependency queries
+func (d *SQLiteDialect) DependencyMetadata() DependencyMetadataSQL {
+	// SQLite keeps no dependency catalog; only foreign keys are reported
+	return DependencyMetadataSQL{
+		Upstream: `
+			WITH target AS (SELECT ? AS schema_name, ? AS object_name)
+			SELECT DISTINCT
+				'main' AS schema_name,
+				f."table" AS object_name,
+				'table' AS object_type,
+				'foreign_key' AS relation
+			FROM target, pragma_foreign_key_list(target.object_name) f
+			WHERE f."table" <> target.object_name`,
+
+		Downstream: `
+			WITH target AS (SELECT ? AS schema_name, ? AS object_name)
+			SELECT DISTINCT
+				'main' AS schema_name,
+				m.name AS object_name,
+				m.type AS object_type,
+				'foreign_key' AS relation
+			FROM target, sqlite_master m, pragma_foreign_key_list(m.name) f
+			WHERE m.type = 'table'
+				AND f."table" = target.object_name
+				AND m.name <> target.object_name`,
+	}
+}
+
+// DefinitionSearch returns the SQLite source search over views and triggers (no schemas)
+func (d *SQLiteDialect) DefinitionSearch() DefinitionSearchSQL {
+	return DefinitionSearchSQL{
+		Search: `
+			SELECT schema_name, object_name, kind, line, definition
+			FROM (
+				SELECT
+					'' AS schema_name,
+					name AS object_name,
+					type AS kind,
+					1 AS line,
+					sql AS definition
+				FROM sqlite_master
+				WHERE type IN ('view', 'trigger')
+			) found
+			WHERE instr(lower(definition), lower(?)) > 0`,
+		KindFilter: " AND kind IN (%s)",
+		OrderBy:    " ORDER BY object_name, kind, line",
+	}
+}
+
+// PermissionMetadata returns nothing (SQLite has no users or grants)
+func (d *SQLiteDialect) PermissionMetadata() PermissionMetadataSQL {
+	return PermissionMetadataSQL{}
+}
+
+// PrincipalMetadata returns nothing (SQLite has no users or roles)
+func (d *SQLiteDialect) PrincipalMetadata() PrincipalMetadataSQL {
+	return PrincipalMetadataSQL{}
+}
+
+// JobMetadata returns nothing (SQLite has no job scheduler)
+func (d *SQLiteDialect) JobMetadata() JobMetadataSQL {
+	return JobMetadataSQL{}
+}
+
+// ChangeTrackingMetadata returns nothing (SQLite has no change capture)
+func (d *SQLiteDialect) ChangeTrackingMetadata() ChangeTrackingMetadataSQL {
+	return ChangeTrackingMetadataSQL{}
+}
+
+// TemporalMetadata returns nothing (SQLite has no system-versioned tables)
+func (d *SQLiteDialect) TemporalMetadata() TemporalMetadataSQL {
+	return TemporalMetadataSQL{}
+}
+
+// RemoteServerMetadata returns nothing (SQLite has no remote servers)
+func (d *SQLiteDialect) RemoteServerMetadata() RemoteServerMetadataSQL {
+	return RemoteServerMetadataSQL{}
+}
+
+// ConstraintMetadata returns SQLite constraint metadata queries
+// SQLite does not name foreign keys, so a name is derived from the table and key id
+func (d *SQLiteDialect) ConstraintMetadata() ConstraintMetadataSQL {
+	return ConstraintMetadataSQL{
+		ListForeignKeys: `
+			SELECT
+				'main' AS schema_name,
+				m.name AS table_name,
+				'fk_' || m.name || '_' || fk.id AS constraint_name,
+				fk."from" AS column_name,
+				'main' AS referenced_schema,
+				fk."table" AS referenced_table,
+				fk."to" AS referenced_column,
+				fk.on_delete,
+				fk.on_update
+			FROM sqlite_master m
+			JOIN pragma_foreign_key_list(m.name) fk
+			WHERE m.type = 'table'`,
+		ForeignKeySchemaFilter:          "", // SQLite doesn't have schemas
+		ForeignKeyTableFilter:           " AND m.name = %s",
+		ForeignKeyReferencedTableFilter: ` AND fk."table" = %s`,
+		ForeignKeyOrderBy:               " ORDER BY m.name, fk.id, fk.seq",
+		// Primary keys come from table_info (rowid tables have no backing index),
+		// unique constraints from the indexes created for them
+		ListKeyConstraints: `
+			SELECT * FROM (
+				SELECT
+					'main' AS schema_name,
+					m.name AS table_name,
+					'pk_' || m.name AS constraint_name,
+					'PRIMARY KEY' AS constraint_type,
+					ti.name AS column_name,
+					ti.pk AS position
+				FROM sqlite_master m
+				JOIN pragma_table_info(m.name) ti
+				WHERE m.type = 'table' AND ti.pk > 0
+				UNION ALL
+				SELECT
+					'main' AS schema_name,
+					m.name AS table_name,
+					il.name AS constraint_name,
+					'UNIQUE' AS constraint_type,
+					ii.name AS column_name,
+					ii.seqno + 1 AS position
+				FROM sqlite_master m
+				JOIN pragma_index_list(m.name) il
+				JOIN pragma_index_info(il.name) ii
+				WHERE m.type = 'table' AND il.origin = 'u'
+			) k
+			WHERE 1=1`,
+		KeySchemaFilter: "", // SQLite doesn't have schemas
+		KeyTableFilter:  " AND k.table_name = %s",
+		KeyOrderBy:      " ORDER BY k.table_name, k.constraint_type, k.constraint_name, k.position",
+		// SQLite keeps CHECK constraints only in the table definition
+		ListCheckConstraints: `
+			SELECT
+				'main' AS schema_name,
+				m.name AS table_name,
+				NULL AS constraint_name,
+				NULL AS column_name,
+				m.sql AS definition
+			FROM sqlite_master m
+			WHERE m.type = 'table'
+				AND m.sql LIKE '%CHECK%'`,
+		CheckDefinitionIsTableSQL: true,
+		CheckSchemaFilter:         "", // SQLite doesn't have schemas
+		CheckTableFilter:          " AND m.name = %s",
+		CheckOrderBy:              " ORDER BY m.name",
+		ListDefaults: `
+			SELECT
+				'main' AS schema_name,
+				m.name AS table_name,
+				ti.name AS column_name,
+				NULL AS constraint_name,
+				ti.dflt_value AS definition
+			FROM sqlite_master m
+			JOIN pragma_table_info(m.name) ti
+			WHERE m.type = 'table'
+				AND ti.dflt_value IS NOT NULL`,
+		DefaultSchemaFilter: "", // SQLite doesn't have schemas
+		DefaultTableFilter:  " AND m.name = %s",
+		DefaultOrderBy:      " ORDER BY m.name, ti.cid",
+	}
+}
+
 // DatabaseInfo returns SQLite database info queries
 func (d *SQLiteDialect) DatabaseInfo() DatabaseInfoSQL {
 	return DatabaseInfoSQL{
@@ -174,6 +439,29 @@ func (d *SQLiteDialect) DatabaseInfo() DatabaseInfoSQL {
 
 		ListSchemas: "", // SQLite doesn't have schemas
 
+		SchemaOverview: `
+			SELECT
+				'main' AS schema_name,
+				(SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%') AS tables,
+				(SELECT COUNT(*) FROM sqlite_master WHERE type = 'view') AS views,
+				0 AS functions,
+				0 AS procedures,
+				(SELECT page_count * page_size FROM pragma_page_count(), pragma_page_size()) AS total_bytes`,
+
+		ListDatabases: `
+			SELECT
+				name,
+				'ONLINE' AS state,
+				NULL AS size_mb,
+				NULL AS collation_name
+			FROM pragma_database_list
+			ORDER BY seq`,
+
+		// Text compares with the BINARY collation unless a column or expression says otherwise
+		Collation: `
+			SELECT 'BINARY', encoding, NULL
+			FROM pragma_encoding`,
+
 		SearchObjects: `
 			SELECT
 				'' AS schema_name,
@@ -189,3 +477,85 @@ func (d *SQLiteDialect) DatabaseInfo() DatabaseInfoSQL {
 			ORDER BY name`,
 	}
 }
+
+// Explain returns the SQLite statements for estimated execution plans
+func (d *SQLiteDialect) Explain() ExplainSQL {
+	return ExplainSQL{
+		Prefix: "EXPLAIN QUERY PLAN ",
+		Format: "rows",
+	}
+}
+
+// ResultLimit returns the SQLite statements that cap the rows of a query
+func (d *SQLiteDialect) ResultLimit() ResultLimitSQL {
+	return ResultLimitSQL{
+		WrapLimit: "SELECT * FROM (\n%s\n) AS limited_rows LIMIT %d",
+	}
+}
+
+// ParseCheck returns no statements: preparing a statement compiles it
+func (d *SQLiteDialect) ParseCheck() ParseCheckSQL {
+	return ParseCheckSQL{}
+}
+
+// Sample returns the SQLite queries of sample_table_data. SQLite has no table sampling, so
+// random rows always sort the whole table.
+func (d *SQLiteDialect) Sample() SampleSQL {
+	return SampleSQL{
+		First:  "SELECT %[1]s FROM %[2]s LIMIT %[3]d",
+		Random: "SELECT %[1]s FROM %[2]s ORDER BY RANDOM() LIMIT %[3]d",
+	}
+}
+
+// ColumnProfile returns the SQLite column profile query
+func (d *SQLiteDialect) ColumnProfile() ColumnProfileSQL {
+	return ColumnProfileSQL{
+		Query: `
+			SELECT s.row_count, s.null_count, s.distinct_count, s.min_value, s.max_value, s.avg_length, t.top_value, t.frequency
+			FROM (
+				SELECT COUNT(*) AS row_count, COUNT(*) - COUNT(%[1]s) AS null_count, COUNT(DISTINCT %[1]s) AS distinct_count,
+					MIN(%[1]s) AS min_value, MAX(%[1]s) AS max_value, AVG(LENGTH(%[1]s)) AS avg_length
+				FROM %[2]s
+			) s
+			LEFT JOIN (
+				SELECT %[1]s AS top_value, COUNT(*) AS frequency
+				FROM %[2]s
+				WHERE %[1]s IS NOT NULL
+				GROUP BY %[1]s
+				ORDER BY COUNT(*) DESC, %[1]s
+				LIMIT %[3]d
+			) t ON 1 = 1
+			ORDER BY t.frequency DESC, t.top_value`,
+	}
+}
+
+// SyntaxReference returns the SQLite syntax quick reference
+func (d *SQLiteDialect) SyntaxReference() SyntaxReferenceSQL {
+	return SyntaxReferenceSQL{
+		Product: "SQLite",
+
+		FirstRows: "SELECT * FROM t ORDER BY id LIMIT 10",
+		Page:      "SELECT * FROM t ORDER BY id LIMIT 10 OFFSET 20",
+
+		CurrentTimestamp: "datetime('now') (UTC)",
+		CurrentDate:      "date('now')",
+		AddDays:          "date(d, '+7 days')",
+		DaysBetween:      "julianday(d2) - julianday(d1)",
+		Year:             "strftime('%Y', d)",
+		TruncateToMonth:  "date(d, 'start of month')",
+		FormatDate:       "strftime('%Y-%m-%d', d)",
+
+		Concat:          "a || b",
+		Substring:       "substr(s, 1, 3)",
+		Length:          "length(s)",
+		Position:        "instr(s, 'x')",
+		CaseInsensitive: "s LIKE '%x%' (case-insensitive for ASCII letters)",
+		Aggregate:       "group_concat(s, ', ')",
+
+		QuoteIdentifier:    "\"Order Details\"",
+		Coalesce:           "COALESCE(a, b) or IFNULL(a, b)",
+		CastToText:         "CAST(a AS TEXT)",
+		SelectWithoutTable: "SELECT date('now')",
+		Parameter:          "WHERE id = ? AND status = ?, or :id with named parameters",
+	}
+}
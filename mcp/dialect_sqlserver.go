@@ -3,6 +3,7 @@ package mcp
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 // SQLServerDialect implements Dialect for Microsoft SQL Server
@@ -22,9 +23,9 @@ func (d *SQLServerDialect) Placeholder(index int) string {
 	return fmt.Sprintf("@p%d", index)
 }
 
-// QuoteIdentifier returns [name]
+// QuoteIdentifier returns [name], with ] doubled
 func (d *SQLServerDialect) QuoteIdentifier(name string) string {
-	return fmt.Sprintf("[%s]", name)
+	return quoteIdentifier(name, "[", "]")
 }
 
 // PaginationClause returns OFFSET/FETCH syntax
@@ -46,6 +47,34 @@ func (d *SQLServerDialect) CurrentDatabase() string {
 	return "DB_NAME()"
 }
 
+// SearchPathStatements returns USE for the first entry.
+// SQL Server resolves unqualified names through the user's default schema, which cannot
+// be changed per session, so the search path selects the default database instead.
+func (d *SQLServerDialect) SearchPathStatements(searchPath []string) []string {
+	if len(searchPath) == 0 {
+		return nil
+	}
+	return []string{"USE " + d.QuoteIdentifier(d.NormalizeIdentifier(searchPath[0]))}
+}
+
+// AsOfClause returns FOR SYSTEM_TIME AS OF, which reads system-versioned temporal tables
+// at a point in time. Period columns are stored in UTC.
+func (d *SQLServerDialect) AsOfClause(asOf time.Time) string {
+	return fmt.Sprintf("FOR SYSTEM_TIME AS OF '%s'", asOf.UTC().Format("2006-01-02T15:04:05.0000000"))
+}
+
+// SupportsFeature checks SQL Server feature support
+func (d *SQLServerDialect) SupportsFeature(feature DialectFeature) bool {
+	switch feature {
+	case FeatureMaterializedViews:
+		return false // indexed views are listed as regular views
+	case FeatureReadOnlyTransactions:
+		return false // the driver rejects them
+	default:
+		return true
+	}
+}
+
 // SystemSchemas returns SQL Server system schemas
 func (d *SQLServerDialect) SystemSchemas() []string {
 	return []string{"sys", "INFORMATION_SCHEMA"}
@@ -152,7 +181,316 @@ func (d *SQLServerDialect) TableMetadata() TableMetadataSQL {
 			INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
 			INNER JOIN sys.tables ref_t ON fkc.referenced_object_id = ref_t.object_id
 			WHERE s.name = @p1 AND t.name = @p2
-			ORDER BY fk.name`,
+			ORDER BY fk.name, fkc.constraint_column_id`,
+
+		DDLColumns: `
+			SELECT
+				c.name AS column_name,
+				CASE
+					WHEN c.is_computed = 1 THEN ''
+					WHEN ty.is_user_defined = 1 THEN QUOTENAME(SCHEMA_NAME(ty.schema_id)) + '.' + QUOTENAME(ty.name)
+					WHEN ty.name IN ('varchar', 'char', 'varbinary', 'binary')
+						THEN ty.name + '(' + CASE WHEN c.max_length = -1 THEN 'max' ELSE CAST(c.max_length AS varchar(10)) END + ')'
+					WHEN ty.name IN ('nvarchar', 'nchar')
+						THEN ty.name + '(' + CASE WHEN c.max_length = -1 THEN 'max' ELSE CAST(c.max_length / 2 AS varchar(10)) END + ')'
+					WHEN ty.name IN ('decimal', 'numeric')
+						THEN ty.name + '(' + CAST(c.precision AS varchar(10)) + ',' + CAST(c.scale AS varchar(10)) + ')'
+					WHEN ty.name IN ('datetime2', 'time', 'datetimeoffset')
+						THEN ty.name + '(' + CAST(c.scale AS varchar(10)) + ')'
+					ELSE ty.name
+				END AS column_type,
+				CASE WHEN c.is_nullable = 1 THEN 'YES' ELSE 'NO' END AS is_nullable,
+				dc.definition AS column_default,
+				CASE
+					WHEN c.is_computed = 1 THEN 'AS ' + cc.definition + CASE WHEN cc.is_persisted = 1 THEN ' PERSISTED' ELSE '' END
+					WHEN c.is_identity = 1 THEN 'IDENTITY(' + CAST(idc.seed_value AS varchar(40)) + ',' + CAST(idc.increment_value AS varchar(40)) + ')'
+				END AS column_extra
+			FROM sys.columns c
+			INNER JOIN sys.tables t ON c.object_id = t.object_id
+			INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
+			INNER JOIN sys.types ty ON c.user_type_id = ty.user_type_id
+			LEFT JOIN sys.default_constraints dc ON dc.object_id = c.default_object_id
+			LEFT JOIN sys.computed_columns cc ON cc.object_id = c.object_id AND cc.column_id = c.column_id
+			LEFT JOIN sys.identity_columns idc ON idc.object_id = c.object_id AND idc.column_id = c.column_id
+			WHERE s.name = @p1 AND t.name = @p2
+			ORDER BY c.column_id`,
+
+		// Only rowstore indexes; columnstore, XML and spatial indexes are left out
+		DDLIndexes: `
+			SELECT
+				i.name AS index_name,
+				CAST(i.is_unique AS int) AS is_unique,
+				i.type_desc AS index_kind,
+				NULL AS index_method,
+				QUOTENAME(c.name) AS column_name,
+				CAST(ic.is_descending_key AS int) AS is_descending,
+				i.filter_definition
+			FROM sys.indexes i
+			INNER JOIN sys.tables t ON i.object_id = t.object_id
+			INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
+			INNER JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+			INNER JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+			WHERE s.name = @p1 AND t.name = @p2
+				AND i.type IN (1, 2)
+				AND i.is_primary_key = 0 AND i.is_unique_constraint = 0 AND i.is_hypothetical = 0
+				AND ic.is_included_column = 0
+			ORDER BY i.name, ic.key_ordinal`,
+
+		DictionaryTables: `
+			SELECT
+				s.name AS schema_name,
+				t.name AS table_name,
+				CAST(ep.value AS nvarchar(4000)) AS table_comment
+			FROM sys.tables t
+			INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
+			LEFT JOIN sys.extended_properties ep
+				ON ep.class = 1 AND ep.major_id = t.object_id AND ep.minor_id = 0 AND ep.name = 'MS_Description'
+			WHERE t.is_ms_shipped = 0`,
+		DictionaryTableSchemaFilter: " AND s.name = %s",
+		DictionaryTableOrderBy:      " ORDER BY s.name, t.name",
+
+		DictionaryColumns: `
+			SELECT
+				s.name AS schema_name,
+				t.name AS table_name,
+				c.name AS column_name,
+				ty.name + CASE
+					WHEN ty.name IN ('varchar', 'char', 'varbinary', 'binary')
+						THEN '(' + CASE WHEN c.max_length = -1 THEN 'max' ELSE CAST(c.max_length AS varchar(10)) END + ')'
+					WHEN ty.name IN ('nvarchar', 'nchar')
+						THEN '(' + CASE WHEN c.max_length = -1 THEN 'max' ELSE CAST(c.max_length / 2 AS varchar(10)) END + ')'
+					WHEN ty.name IN ('decimal', 'numeric')
+						THEN '(' + CAST(c.precision AS varchar(10)) + ',' + CAST(c.scale AS varchar(10)) + ')'
+					ELSE ''
+				END AS data_type,
+				CASE WHEN c.is_nullable = 1 THEN 'YES' ELSE 'NO' END AS is_nullable,
+				dc.definition AS column_default,
+				CAST(ep.value AS nvarchar(4000)) AS column_comment,
+				CASE WHEN EXISTS (
+					SELECT 1 FROM sys.indexes i
+					INNER JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+					WHERE i.object_id = c.object_id AND i.is_primary_key = 1 AND ic.column_id = c.column_id
+				) THEN 1 ELSE 0 END AS is_primary_key
+			FROM sys.columns c
+			INNER JOIN sys.tables t ON c.object_id = t.object_id
+			INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
+			INNER JOIN sys.types ty ON c.user_type_id = ty.user_type_id
+			LEFT JOIN sys.default_constraints dc ON dc.object_id = c.default_object_id
+			LEFT JOIN sys.extended_properties ep
+				ON ep.class = 1 AND ep.major_id = c.object_id AND ep.minor_id = c.column_id AND ep.name = 'MS_Description'
+			WHERE t.is_ms_shipped = 0`,
+		DictionaryColumnSchemaFilter: " AND s.name = %s",
+		DictionaryColumnTableFilter:  " AND t.name IN (%s)",
+		DictionaryColumnOrderBy:      " ORDER BY s.name, t.name, c.column_id",
+
+		FindColumns: `
+			SELECT
+				c.TABLE_SCHEMA,
+				c.TABLE_NAME,
+				CASE t.TABLE_TYPE WHEN 'VIEW' THEN 'view' ELSE 'table' END AS table_type,
+				c.COLUMN_NAME,
+				c.DATA_TYPE + CASE
+					WHEN c.CHARACTER_MAXIMUM_LENGTH = -1 THEN '(max)'
+					WHEN c.CHARACTER_MAXIMUM_LENGTH IS NOT NULL
+						THEN '(' + CAST(c.CHARACTER_MAXIMUM_LENGTH AS varchar(10)) + ')'
+					WHEN c.DATA_TYPE IN ('decimal', 'numeric')
+						THEN '(' + CAST(c.NUMERIC_PRECISION AS varchar(10)) + ',' + CAST(c.NUMERIC_SCALE AS varchar(10)) + ')'
+					ELSE ''
+				END AS data_type,
+				c.IS_NULLABLE,
+				c.ORDINAL_POSITION
+			FROM INFORMATION_SCHEMA.COLUMNS c
+			INNER JOIN INFORMATION_SCHEMA.TABLES t
+				ON t.TABLE_CATALOG = c.TABLE_CATALOG AND t.TABLE_SCHEMA = c.TABLE_SCHEMA AND t.TABLE_NAME = c.TABLE_NAME
+			WHERE t.TABLE_TYPE IN ('BASE TABLE', 'VIEW')`,
+		FindColumnName:         "c.COLUMN_NAME",
+		FindColumnSchemaFilter: " AND c.TABLE_SCHEMA = %s",
+		FindColumnOrderBy:      " ORDER BY c.TABLE_SCHEMA, c.TABLE_NAME, c.ORDINAL_POSITION",
+
+		ObjectComment: `
+			SELECT
+				CASE o.type WHEN 'V' THEN 'view' ELSE 'table' END AS object_type,
+				CAST(ep.value AS nvarchar(4000)) AS comment
+			FROM sys.objects o
+			INNER JOIN sys.schemas s ON o.schema_id = s.schema_id
+			LEFT JOIN sys.extended_properties ep
+				ON ep.class = 1 AND ep.major_id = o.object_id AND ep.minor_id = 0 AND ep.name = 'MS_Description'
+			WHERE o.type IN ('U', 'V')
+			  AND s.name = @p1
+			  AND o.name = @p2`,
+		ColumnComments: `
+			SELECT c.name AS column_name, CAST(ep.value AS nvarchar(4000)) AS comment
+			FROM sys.columns c
+			INNER JOIN sys.objects o ON c.object_id = o.object_id
+			INNER JOIN sys.schemas s ON o.schema_id = s.schema_id
+			INNER JOIN sys.extended_properties ep
+				ON ep.class = 1 AND ep.major_id = c.object_id AND ep.minor_id = c.column_id AND ep.name = 'MS_Description'
+			WHERE s.name = @p1
+			  AND o.name = @p2
+			ORDER BY c.column_id`,
+
+		ColumnCollations: `
+			SELECT
+				c.name,
+				TYPE_NAME(c.user_type_id),
+				c.collation_name,
+				CASE WHEN TYPE_NAME(c.system_type_id) IN ('nchar', 'nvarchar', 'ntext') THEN 'UTF-16'
+					ELSE 'code page ' + CAST(COLLATIONPROPERTY(c.collation_name, 'CodePage') AS varchar(10))
+				END,
+				CAST(DATABASEPROPERTYEX(DB_NAME(), 'Collation') AS nvarchar(128))
+			FROM sys.columns c
+			INNER JOIN sys.tables t ON c.object_id = t.object_id
+			INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
+			WHERE c.collation_name IS NOT NULL
+				AND s.name = @p1
+				AND t.name = @p2
+			ORDER BY c.column_id`,
+
+		TimeTravel: `
+			SELECT
+				CASE WHEN t.temporal_type = 2 THEN 1 ELSE 0 END AS versioned,
+				CASE t.history_retention_period_unit
+					WHEN 3 THEN t.history_retention_period
+					WHEN 4 THEN t.history_retention_period * 7
+					WHEN 5 THEN t.history_retention_period * 30
+					WHEN 6 THEN t.history_retention_period * 365
+				END AS retention_days
+			FROM sys.tables t
+			INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
+			WHERE s.name = @p1
+			  AND t.name = @p2`,
+
+		TableStats: `
+			SELECT
+				s.name AS schema_name,
+				t.name AS table_name,
+				SUM(CASE WHEN ps.index_id IN (0, 1) THEN ps.row_count ELSE 0 END) AS row_count,
+				SUM(CASE WHEN ps.index_id IN (0, 1) THEN ps.used_page_count ELSE 0 END) * 8192 AS data_bytes,
+				SUM(CASE WHEN ps.index_id > 1 THEN ps.used_page_count ELSE 0 END) * 8192 AS index_bytes
+			FROM sys.dm_db_partition_stats ps
+			INNER JOIN sys.tables t ON ps.object_id = t.object_id
+			INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
+			WHERE 1=1`,
+		StatsSchemaFilter: " AND s.name = %s",
+		StatsTableFilter:  " AND t.name = %s",
+		StatsGroupBy:      " GROUP BY s.name, t.name",
+		StatsOrderBy:      " ORDER BY s.name, t.name",
+
+		IdentityStatus: `
+			SELECT
+				s.name AS schema_name,
+				t.name AS table_name,
+				ic.name AS column_name,
+				TYPE_NAME(ic.system_type_id) AS data_type,
+				CAST(ic.seed_value AS varchar(40)) AS seed,
+				CAST(ic.increment_value AS varchar(40)) AS increment,
+				CAST(ic.last_value AS varchar(40)) AS current_value,
+				CASE TYPE_NAME(ic.system_type_id)
+					WHEN 'tinyint' THEN '0'
+					WHEN 'smallint' THEN '-32768'
+					WHEN 'int' THEN '-2147483648'
+					WHEN 'bigint' THEN '-9223372036854775808'
+					ELSE '-' + REPLICATE('9', ic.precision)
+				END AS min_value,
+				CASE TYPE_NAME(ic.system_type_id)
+					WHEN 'tinyint' THEN '255'
+					WHEN 'smallint' THEN '32767'
+					WHEN 'int' THEN '2147483647'
+					WHEN 'bigint' THEN '9223372036854775807'
+					ELSE REPLICATE('9', ic.precision)
+				END AS max_value
+			FROM sys.identity_columns ic
+			INNER JOIN sys.tables t ON ic.object_id = t.object_id
+			INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
+			WHERE t.is_ms_shipped = 0`,
+		IdentitySchemaFilter: " AND s.name = %s",
+		IdentityTableFilter:  " AND t.name = %s",
+		IdentityOrderBy:      " ORDER BY s.name, t.name, ic.name",
+
+		ComputedColumns: `
+			SELECT
+				s.name AS schema_name,
+				t.name AS table_name,
+				cc.name AS column_name,
+				TYPE_NAME(cc.user_type_id) AS data_type,
+				cc.definition AS expression,
+				CAST(cc.is_persisted AS int) AS persisted
+			FROM sys.computed_columns cc
+			INNER JOIN sys.tables t ON cc.object_id = t.object_id
+			INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
+			WHERE t.is_ms_shipped = 0`,
+		ComputedSchemaFilter: " AND s.name = %s",
+		ComputedTableFilter:  " AND t.name = %s",
+		ComputedOrderBy:      " ORDER BY s.name, t.name, cc.column_id",
+
+		Statistics: `
+			SELECT
+				sc.name,
+				t.name,
+				st.name,
+				STUFF((
+					SELECT ', ' + c.name
+					FROM sys.stats_columns stc
+					INNER JOIN sys.columns c ON c.object_id = stc.object_id AND c.column_id = stc.column_id
+					WHERE stc.object_id = st.object_id AND stc.stats_id = st.stats_id
+					ORDER BY stc.stats_column_id
+					FOR XML PATH(''), TYPE).value('.', 'NVARCHAR(MAX)'), 1, 2, ''),
+				sp.last_updated,
+				sp.rows,
+				sp.rows_sampled,
+				sp.modification_counter,
+				CAST(st.auto_created AS int)
+			FROM sys.stats st
+			INNER JOIN sys.tables t ON st.object_id = t.object_id
+			INNER JOIN sys.schemas sc ON t.schema_id = sc.schema_id
+			OUTER APPLY sys.dm_db_stats_properties(st.object_id, st.stats_id) sp
+			WHERE t.is_ms_shipped = 0`,
+		StatisticsSchemaFilter: " AND sc.name = %s",
+		StatisticsTableFilter:  " AND t.name = %s",
+		StatisticsOrderBy:      " ORDER BY sc.name, t.name, st.name",
+
+		ListPartitions: `
+			SELECT
+				s.name AS schema_name,
+				CAST(p.partition_number AS NVARCHAR(20)) AS partition_name,
+				p.partition_number AS position,
+				CASE WHEN pf.function_id IS NULL THEN NULL ELSE
+					CASE WHEN pf.boundary_value_on_right = 1 THEN '[' ELSE '(' END +
+					ISNULL(CAST(lo.value AS NVARCHAR(4000)), '-inf') + ', ' +
+					ISNULL(CAST(hi.value AS NVARCHAR(4000)), '+inf') +
+					CASE WHEN pf.boundary_value_on_right = 1 THEN ')' ELSE ']' END
+				END AS bound,
+				p.rows AS row_count,
+				ps.name AS scheme_name,
+				pf.name AS function_name,
+				CASE WHEN pf.function_id IS NULL THEN NULL
+					WHEN pf.boundary_value_on_right = 1 THEN 'RANGE RIGHT'
+					ELSE 'RANGE LEFT' END AS strategy
+			FROM sys.partitions p
+			INNER JOIN sys.tables t ON p.object_id = t.object_id
+			INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
+			INNER JOIN sys.indexes i ON p.object_id = i.object_id AND p.index_id = i.index_id
+			LEFT JOIN sys.partition_schemes ps ON i.data_space_id = ps.data_space_id
+			LEFT JOIN sys.partition_functions pf ON ps.function_id = pf.function_id
+			LEFT JOIN sys.partition_range_values lo ON lo.function_id = pf.function_id AND lo.boundary_id = p.partition_number - 1
+			LEFT JOIN sys.partition_range_values hi ON hi.function_id = pf.function_id AND hi.boundary_id = p.partition_number
+			WHERE p.index_id IN (0, 1)`,
+		PartitionSchemaFilter: " AND s.name = %s",
+		PartitionTableFilter:  " AND t.name = %s",
+		PartitionOrderBy:      " ORDER BY s.name, p.partition_number",
+
+		PartitionKeys: `
+			SELECT
+				s.name AS schema_name,
+				c.name AS column_name
+			FROM sys.index_columns ic
+			INNER JOIN sys.tables t ON ic.object_id = t.object_id
+			INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
+			INNER JOIN sys.columns c ON ic.object_id = c.object_id AND ic.column_id = c.column_id
+			WHERE ic.index_id IN (0, 1) AND ic.partition_ordinal > 0`,
+		PartitionKeySchemaFilter: " AND s.name = %s",
+		PartitionKeyTableFilter:  " AND t.name = %s",
+		PartitionKeyOrderBy:      " ORDER BY s.name, ic.partition_ordinal",
 	}
 }
 
@@ -207,6 +545,39 @@ func (d *SQLServerDialect) FunctionMetadata() FunctionMetadataSQL {
 			INNER JOIN sys.objects o ON m.object_id = o.object_id
 			INNER JOIN sys.schemas s ON o.schema_id = s.schema_id
 			WHERE o.type IN ('FN', 'IF', 'TF') AND s.name = @p1 AND o.name = @p2`,
+
+		// T-SQL parameter defaults are not recorded in the catalog, only CLR ones
+		GetParameters: `
+			SELECT
+				s.name + '.' + o.name AS signature,
+				p.parameter_id AS position,
+				NULLIF(p.name, '') AS parameter_name,
+				CASE
+					WHEN t.name IN ('varchar', 'char', 'varbinary', 'binary')
+						THEN t.name + '(' + CASE WHEN p.max_length = -1 THEN 'max' ELSE CAST(p.max_length AS VARCHAR(10)) END + ')'
+					WHEN t.name IN ('nvarchar', 'nchar')
+						THEN t.name + '(' + CASE WHEN p.max_length = -1 THEN 'max' ELSE CAST(p.max_length / 2 AS VARCHAR(10)) END + ')'
+					WHEN t.name IN ('decimal', 'numeric')
+						THEN t.name + '(' + CAST(p.precision AS VARCHAR(10)) + ',' + CAST(p.scale AS VARCHAR(10)) + ')'
+					ELSE t.name
+				END AS data_type,
+				CASE
+					WHEN p.parameter_id = 0 THEN 'RETURN'
+					WHEN p.is_output = 1 THEN 'INOUT'
+					ELSE 'IN'
+				END AS direction,
+				CASE WHEN p.has_default_value = 1 THEN CAST(p.default_value AS NVARCHAR(4000)) END AS default_value
+			FROM sys.parameters p
+			INNER JOIN sys.objects o ON p.object_id = o.object_id
+			INNER JOIN sys.schemas s ON o.schema_id = s.schema_id
+			INNER JOIN sys.types t ON p.user_type_id = t.user_type_id
+			WHERE o.type IN ('FN', 'IF', 'TF') AND s.name = @p1 AND o.name = @p2
+			UNION ALL
+			SELECT s.name + '.' + o.name, 0, NULL, 'TABLE', 'RETURN', NULL
+			FROM sys.objects o
+			INNER JOIN sys.schemas s ON o.schema_id = s.schema_id
+			WHERE o.type IN ('IF', 'TF') AND s.name = @p1 AND o.name = @p2
+			ORDER BY signature, position`,
 	}
 }
 
@@ -266,6 +637,519 @@ func (d *SQLServerDialect) TriggerMetadata() TriggerMetadataSQL {
 	}
 }
 
+// SynonymMetadata returns SQL Server synonym metadata queries
+func (d *SQLServerDialect) SynonymMetadata() SynonymMetadataSQL {
+	return SynonymMetadataSQL{
+		ListSynonyms: `
+			SELECT
+				s.name AS schema_name,
+				sy.name AS synonym_name,
+				sy.base_object_name,
+				PARSENAME(sy.base_object_name, 4) AS server_name,
+				o.type_desc AS base_object_type
+			FROM sys.synonyms sy
+			INNER JOIN sys.schemas s ON sy.schema_id = s.schema_id
+			LEFT JOIN sys.objects o ON o.object_id = OBJECT_ID(sy.base_object_name)
+			WHERE 1=1`,
+		SchemaFilter: " AND s.name = %s",
+		NameFilter:   " AND sy.name LIKE %s",
+		OrderBy:      " ORDER BY s.name, sy.name",
+	}
+}
+
+// TypeMetadata returns SQL Server user-defined type metadata queries
+func (d *SQLServerDialect) TypeMetadata() TypeMetadataSQL {
+	return TypeMetadataSQL{
+		ListTypes: `
+			SELECT
+				s.name AS schema_name,
+				t.name AS type_name,
+				CASE WHEN t.is_table_type = 1 THEN 'TABLE TYPE' ELSE 'ALIAS TYPE' END AS type_kind,
+				CASE WHEN t.is_table_type = 1 THEN
+					STUFF((
+						SELECT ', ' + c.name + ' ' + TYPE_NAME(c.user_type_id) +
+							CASE WHEN c.is_nullable = 0 THEN ' NOT NULL' ELSE '' END
+						FROM sys.columns c
+						WHERE c.object_id = tt.type_table_object_id
+						ORDER BY c.column_id
+						FOR XML PATH(''), TYPE).value('.', 'NVARCHAR(MAX)'), 1, 2, '')
+				ELSE
+					TYPE_NAME(t.system_type_id) +
+					CASE
+						WHEN TYPE_NAME(t.system_type_id) IN ('varchar', 'char', 'varbinary', 'binary')
+							THEN '(' + CASE WHEN t.max_length = -1 THEN 'max' ELSE CAST(t.max_length AS VARCHAR(10)) END + ')'
+						WHEN TYPE_NAME(t.system_type_id) IN ('nvarchar', 'nchar')
+							THEN '(' + CASE WHEN t.max_length = -1 THEN 'max' ELSE CAST(t.max_length / 2 AS VARCHAR(10)) END + ')'
+						WHEN TYPE_NAME(t.system_type_id) IN ('decimal', 'numeric')
+							THEN '(' + CAST(t.precision AS VARCHAR(10)) + ', ' + CAST(t.scale AS VARCHAR(10)) + ')'
+						ELSE ''
+					END
+				END AS definition,
+				t.is_nullable
+			FROM sys.types t
+			INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
+			LEFT JOIN sys.table_types tt ON tt.user_type_id = t.user_type_id
+			WHERE t.is_user_defined = 1`,
+		SchemaFilter: " AND s.name = %s",
+		NameFilter:   " AND t.name LIKE %s",
+		OrderBy:      " ORDER BY s.name, t.name",
+		ListColumns: `
+			SELECT
+				s.name AS schema_name,
+				t.name AS type_name,
+				c.name AS column_name,
+				TYPE_NAME(c.user_type_id) +
+				CASE
+					WHEN TYPE_NAME(c.user_type_id) IN ('varchar', 'char', 'varbinary', 'binary')
+						THEN '(' + CASE WHEN c.max_length = -1 THEN 'max' ELSE CAST(c.max_length AS VARCHAR(10)) END + ')'
+					WHEN TYPE_NAME(c.user_type_id) IN ('nvarchar', 'nchar')
+						THEN '(' + CASE WHEN c.max_length = -1 THEN 'max' ELSE CAST(c.max_length / 2 AS VARCHAR(10)) END + ')'
+					WHEN TYPE_NAME(c.user_type_id) IN ('decimal', 'numeric')
+						THEN '(' + CAST(c.precision AS VARCHAR(10)) + ', ' + CAST(c.scale AS VARCHAR(10)) + ')'
+					ELSE ''
+				END AS data_type,
+				c.is_nullable
+			FROM sys.table_types t
+			INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
+			INNER JOIN sys.columns c ON c.object_id = t.type_table_object_id
+			WHERE 1=1`,
+		ColumnsOrderBy: " ORDER BY s.name, t.name, c.column_id",
+		ListUsage: `
+			SELECT
+				s.name AS schema_name,
+				t.name AS type_name,
+				OBJECT_SCHEMA_NAME(p.object_id) AS routine_schema,
+				o.name AS routine_name,
+				o.type_desc AS routine_type,
+				CASE WHEN p.parameter_id = 0 THEN NULL ELSE p.name END AS parameter_name
+			FROM sys.parameters p
+			INNER JOIN sys.types t ON p.user_type_id = t.user_type_id
+			INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
+			INNER JOIN sys.objects o ON o.object_id = p.object_id
+			WHERE t.is_user_defined = 1`,
+		UsageOrderBy: " ORDER BY s.name, t.name, routine_schema, o.name, p.parameter_id",
+	}
+}
+
+// DependencyMetadata returns SQL Server object dependency queries
+func (d *SQLServerDialect) DependencyMetadata() DependencyMetadataSQL {
+	return DependencyMetadataSQL{
+		Upstream: `
+			SELECT
+				COALESCE(d.referenced_schema_name, OBJECT_SCHEMA_NAME(d.referenced_id), '') AS schema_name,
+				d.referenced_entity_name AS object_name,
+				COALESCE(ro.type_desc, 'UNRESOLVED') AS object_type,
+				'reference' AS relation
+			FROM sys.sql_expression_dependencies d
+			INNER JOIN sys.objects o ON d.referencing_id = o.object_id
+			INNER JOIN sys.schemas s ON o.schema_id = s.schema_id
+			LEFT JOIN sys.objects ro ON d.referenced_id = ro.object_id
+			WHERE d.referencing_class = 1 AND s.name = @p1 AND o.name = @p2
+			UNION
+			SELECT
+				SCHEMA_NAME(rt.schema_id),
+				rt.name,
+				rt.type_desc,
+				'foreign_key'
+			FROM sys.foreign_keys fk
+			INNER JOIN sys.tables t ON fk.parent_object_id = t.object_id
+			INNER JOIN sys.tables rt ON fk.referenced_object_id = rt.object_id
+			WHERE SCHEMA_NAME(t.schema_id) = @p1 AND t.name = @p2 AND rt.object_id <> t.object_id`,
+
+		Downstream: `
+			SELECT
+				OBJECT_SCHEMA_NAME(d.referencing_id) AS schema_name,
+				o.name AS object_name,
+				o.type_desc AS object_type,
+				'reference' AS relation
+			FROM sys.sql_expression_dependencies d
+			INNER JOIN sys.objects o ON d.referencing_id = o.object_id
+			WHERE d.referencing_class = 1
+				AND d.referenced_id = OBJECT_ID(QUOTENAME(@p1) + '.' + QUOTENAME(@p2))
+			UNION
+			SELECT
+				SCHEMA_NAME(t.schema_id),
+				t.name,
+				t.type_desc,
+				'foreign_key'
+			FROM sys.foreign_keys fk
+			INNER JOIN sys.tables t ON fk.parent_object_id = t.object_id
+			INNER JOIN sys.tables rt ON fk.referenced_object_id = rt.object_id
+			WHERE SCHEMA_NAME(rt.schema_id) = @p1 AND rt.name = @p2 AND rt.object_id <> t.object_id`,
+	}
+}
+
+// DefinitionSearch returns the SQL Server source search over sys.sql_modules
+func (d *SQLServerDialect) DefinitionSearch() DefinitionSearchSQL {
+	return DefinitionSearchSQL{
+		Search: `
+			SELECT schema_name, object_name, kind, line, definition
+			FROM (
+				SELECT
+					s.name AS schema_name,
+					o.name AS object_name,
+					CASE
+						WHEN o.type = 'V' THEN 'view'
+						WHEN o.type = 'P' THEN 'procedure'
+						WHEN o.type = 'TR' THEN 'trigger'
+						ELSE 'function'
+					END AS kind,
+					1 AS line,
+					m.definition
+				FROM sys.sql_modules m
+				INNER JOIN sys.objects o ON o.object_id = m.object_id
+				INNER JOIN sys.schemas s ON o.schema_id = s.schema_id
+				WHERE o.type IN ('V', 'P', 'FN', 'IF', 'TF', 'TR')
+				  AND o.is_ms_shipped = 0
+			) found
+			WHERE CHARINDEX(@p1, definition) > 0`,
+		SchemaFilter: " AND schema_name = %s",
+		KindFilter:   " AND kind IN (%s)",
+		OrderBy:      " ORDER BY schema_name, object_name, kind, line",
+	}
+}
+
+// PermissionMetadata returns SQL Server object permission queries, including the
+// permissions granted or denied on the object's schema
+func (d *SQLServerDialect) PermissionMetadata() PermissionMetadataSQL {
+	return PermissionMetadataSQL{
+		ListObjectPermissions: `
+			SELECT grantee, grantee_type, privilege, state, grantor, column_name, scope
+			FROM (
+				SELECT
+					pr.name AS grantee,
+					pr.type_desc AS grantee_type,
+					p.permission_name AS privilege,
+					p.state_desc AS state,
+					gp.name AS grantor,
+					c.name AS column_name,
+					CASE WHEN p.minor_id > 0 THEN 'COLUMN' ELSE 'OBJECT' END AS scope
+				FROM sys.database_permissions p
+				INNER JOIN sys.objects o ON p.major_id = o.object_id
+				INNER JOIN sys.schemas s ON o.schema_id = s.schema_id
+				INNER JOIN sys.database_principals pr ON p.grantee_principal_id = pr.principal_id
+				LEFT JOIN sys.database_principals gp ON p.grantor_principal_id = gp.principal_id
+				LEFT JOIN sys.columns c ON c.object_id = p.major_id AND c.column_id = p.minor_id
+				WHERE p.class = 1 AND s.name = @p1 AND o.name = @p2
+				UNION ALL
+				SELECT
+					pr.name,
+					pr.type_desc,
+					p.permission_name,
+					p.state_desc,
+					gp.name,
+					NULL,
+					'SCHEMA'
+				FROM sys.database_permissions p
+				INNER JOIN sys.schemas s ON p.major_id = s.schema_id
+				INNER JOIN sys.database_principals pr ON p.grantee_principal_id = pr.principal_id
+				LEFT JOIN sys.database_principals gp ON p.grantor_principal_id = gp.principal_id
+				WHERE p.class = 3 AND s.name = @p1
+				  AND EXISTS (SELECT 1 FROM sys.objects o WHERE o.schema_id = s.schema_id AND o.name = @p2)
+			) perms
+			WHERE 1=1`,
+		GranteeFilter: " AND grantee = %s",
+		OrderBy:       " ORDER BY grantee, scope, column_name, privilege",
+
+		// Security policies bind inline table-valued functions as filter and block predicates;
+		// the function decides which users see which rows
+		RLSPolicies: `
+			SELECT
+				s.name,
+				t.name,
+				SCHEMA_NAME(sp.schema_id) + '.' + sp.name,
+				CAST(sp.is_enabled AS int),
+				NULL,
+				pr.predicate_type_desc,
+				COALESCE(pr.operation_desc, 'ALL'),
+				NULL,
+				pr.predicate_definition,
+				NULL
+			FROM sys.security_predicates pr
+			INNER JOIN sys.security_policies sp ON pr.object_id = sp.object_id
+			INNER JOIN sys.tables t ON pr.target_object_id = t.object_id
+			INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
+			WHERE 1=1`,
+		RLSSchemaFilter: " AND s.name = %s",
+		RLSTableFilter:  " AND t.name = %s",
+		RLSOrderBy:      " ORDER BY s.name, t.name, sp.name, pr.security_predicate_id",
+	}
+}
+
+// PrincipalMetadata returns SQL Server database users and roles with their role memberships
+func (d *SQLServerDialect) PrincipalMetadata() PrincipalMetadataSQL {
+	return PrincipalMetadataSQL{
+		ListPrincipals: `
+			SELECT
+				p.name,
+				p.type_desc AS principal_type,
+				CASE WHEN p.type IN ('R', 'A') THEN 1 ELSE 0 END AS is_role,
+				CASE WHEN p.is_fixed_role = 1 OR p.principal_id < 5 THEN 1 ELSE 0 END AS is_system,
+				p.default_schema_name,
+				p.create_date
+			FROM sys.database_principals p
+			WHERE p.name NOT LIKE '##%'`,
+		NameFilter: " AND p.name LIKE %s",
+		OrderBy:    " ORDER BY is_role DESC, p.name",
+		ListRoleMembers: `
+			SELECT r.name AS role_name, m.name AS member_name
+			FROM sys.database_role_members rm
+			INNER JOIN sys.database_principals r ON rm.role_principal_id = r.principal_id
+			INNER JOIN sys.database_principals m ON rm.member_principal_id = m.principal_id
+			ORDER BY r.name, m.name`,
+	}
+}
+
+// JobMetadata returns the SQL Server Agent jobs stored in msdb with their schedules, steps
+// and last outcome. Reading them requires access to msdb and one of the SQLAgent roles;
+// Azure SQL Database has no Agent.
+func (d *SQLServerDialect) JobMetadata() JobMetadataSQL {
+	return JobMetadataSQL{
+		Schedulers: `
+			SELECT 'sql_agent' AS scheduler
+			WHERE HAS_DBACCESS('msdb') = 1
+			  AND OBJECT_ID('msdb.dbo.sysjobs') IS NOT NULL`,
+		ListJobs: map[string]string{
+			"sql_agent": `
+				SELECT
+					j.name AS job_name,
+					CAST(j.enabled AS int) AS enabled,
+					STUFF((
+						SELECT '; ' + sc.name + ': ' + CASE sc.freq_type
+								WHEN 1 THEN 'once'
+								WHEN 4 THEN 'every ' + CAST(sc.freq_interval AS varchar(10)) + ' day(s)'
+								WHEN 8 THEN 'every ' + CAST(sc.freq_recurrence_factor AS varchar(10)) + ' week(s), days bitmask ' + CAST(sc.freq_interval AS varchar(10))
+								WHEN 16 THEN 'every ' + CAST(sc.freq_recurrence_factor AS varchar(10)) + ' month(s) on day ' + CAST(sc.freq_interval AS varchar(10))
+								WHEN 32 THEN 'every ' + CAST(sc.freq_recurrence_factor AS varchar(10)) + ' month(s), relative'
+								WHEN 64 THEN 'when SQL Server Agent starts'
+								WHEN 128 THEN 'when the computer is idle'
+								ELSE 'unknown'
+							END
+							+ CASE sc.freq_subday_type
+								WHEN 2 THEN ', every ' + CAST(sc.freq_subday_interval AS varchar(10)) + ' second(s)'
+								WHEN 4 THEN ', every ' + CAST(sc.freq_subday_interval AS varchar(10)) + ' minute(s)'
+								WHEN 8 THEN ', every ' + CAST(sc.freq_subday_interval AS varchar(10)) + ' hour(s)'
+								ELSE ''
+							END
+							+ CASE WHEN sc.freq_type IN (64, 128) THEN ''
+								ELSE ' at ' + STUFF(STUFF(RIGHT('000000' + CAST(sc.active_start_time AS varchar(6)), 6), 5, 0, ':'), 3, 0, ':')
+							END
+							+ CASE WHEN sc.enabled = 0 THEN ' (disabled)' ELSE '' END
+						FROM msdb.dbo.sysjobschedules js
+						INNER JOIN msdb.dbo.sysschedules sc ON sc.schedule_id = js.schedule_id
+						WHERE js.job_id = j.job_id
+						ORDER BY sc.name
+						FOR XML PATH(''), TYPE).value('.', 'NVARCHAR(MAX)'), 1, 2, '') AS schedule,
+					STUFF((
+						SELECT CHAR(10) + CAST(st.step_id AS varchar(10)) + '. ' + st.step_name + ' [' + st.subsystem + ']: ' + ISNULL(st.command, '')
+						FROM msdb.dbo.sysjobsteps st
+						WHERE st.job_id = j.job_id
+						ORDER BY st.step_id
+						FOR XML PATH(''), TYPE).value('.', 'NVARCHAR(MAX)'), 1, 1, '') AS command,
+					CASE WHEN srv.last_run_date > 0
+						THEN CONVERT(varchar(19), msdb.dbo.agent_datetime(srv.last_run_date, srv.last_run_time), 120)
+					END AS last_run_at,
+					CASE WHEN srv.last_run_date > 0 THEN CASE srv.last_run_outcome
+						WHEN 0 THEN 'failed'
+						WHEN 1 THEN 'succeeded'
+						WHEN 3 THEN 'canceled'
+						ELSE 'unknown'
+					END END AS last_run_status,
+					NULLIF(srv.last_outcome_message, '') AS last_run_message,
+					(
+						SELECT CONVERT(varchar(19), MIN(msdb.dbo.agent_datetime(js.next_run_date, js.next_run_time)), 120)
+						FROM msdb.dbo.sysjobschedules js
+						WHERE js.job_id = j.job_id AND js.next_run_date > 0
+					) AS next_run_at,
+					(
+						SELECT TOP 1 st.database_name
+						FROM msdb.dbo.sysjobsteps st
+						WHERE st.job_id = j.job_id AND st.database_name IS NOT NULL
+						ORDER BY st.step_id
+					) AS database_name,
+					SUSER_SNAME(j.owner_sid) AS owner,
+					NULLIF(j.description, 'No description available.') AS description
+				FROM msdb.dbo.sysjobs j
+				LEFT JOIN msdb.dbo.sysjobservers srv ON srv.job_id = j.job_id`,
+		},
+	}
+}
+
+// ChangeTrackingMetadata returns the Change Tracking and change data capture (CDC) settings of
+// the current database and the tables each one captures. The CDC cleanup retention is kept in
+// msdb, which the login may not be able to read.
+func (d *SQLServerDialect) ChangeTrackingMetadata() ChangeTrackingMetadataSQL {
+	return ChangeTrackingMetadataSQL{
+		Settings: `
+			SELECT 'change_tracking' AS setting,
+				CASE WHEN ctd.database_id IS NULL THEN 'disabled' ELSE 'enabled' END AS value
+			FROM sys.databases db
+			LEFT JOIN sys.change_tracking_databases ctd ON ctd.database_id = db.database_id
+			WHERE db.database_id = DB_ID()
+			UNION ALL
+			SELECT 'change_tracking_retention',
+				CAST(retention_period AS varchar(10)) + ' ' + LOWER(retention_period_units_desc)
+			FROM sys.change_tracking_databases
+			WHERE database_id = DB_ID()
+			UNION ALL
+			SELECT 'change_tracking_auto_cleanup', CASE is_auto_cleanup_on WHEN 1 THEN 'on' ELSE 'off' END
+			FROM sys.change_tracking_databases
+			WHERE database_id = DB_ID()
+			UNION ALL
+			SELECT 'cdc', CASE is_cdc_enabled WHEN 1 THEN 'enabled' ELSE 'disabled' END
+			FROM sys.databases
+			WHERE database_id = DB_ID()`,
+		RestrictedSettings: `
+			SELECT 'cdc_retention', CAST(retention AS varchar(20)) + ' minutes'
+			FROM msdb.dbo.cdc_jobs
+			WHERE database_id = DB_ID() AND job_type = 'cleanup'`,
+		ListTables: `
+			SELECT
+				s.name AS schema_name,
+				t.name AS table_name,
+				'change_tracking' AS mechanism,
+				CAST(NULL AS nvarchar(128)) AS name,
+				'track_columns_updated ' + CASE ctt.is_track_columns_updated_on WHEN 1 THEN 'on' ELSE 'off' END
+					+ ', min_valid_version ' + ISNULL(CAST(ctt.min_valid_version AS varchar(20)), '') AS details
+			FROM sys.change_tracking_tables ctt
+			INNER JOIN sys.tables t ON t.object_id = ctt.object_id
+			INNER JOIN sys.schemas s ON s.schema_id = t.schema_id
+			UNION ALL
+			SELECT s.name, t.name, 'cdc', NULL, NULL
+			FROM sys.tables t
+			INNER JOIN sys.schemas s ON s.schema_id = t.schema_id
+			WHERE t.is_tracked_by_cdc = 1`,
+	}
+}
+
+// TemporalMetadata returns the system-versioned temporal tables (SQL Server 2016 and later)
+// with their history table, period columns and history retention
+func (d *SQLServerDialect) TemporalMetadata() TemporalMetadataSQL {
+	return TemporalMetadataSQL{
+		Sources: `
+			SELECT 'system_versioning' AS source
+			WHERE COL_LENGTH('sys.tables', 'history_retention_period') IS NOT NULL`,
+		ListTables: map[string]string{
+			"system_versioning": `
+				SELECT
+					s.name AS schema_name,
+					t.name AS table_name,
+					hs.name AS history_schema,
+					h.name AS history_table,
+					sc.name AS period_start,
+					ec.name AS period_end,
+					CASE WHEN t.history_retention_period > 0
+						THEN CAST(t.history_retention_period AS varchar(10)) + ' ' + LOWER(t.history_retention_period_unit_desc)
+					END AS retention
+				FROM sys.tables t
+				INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
+				INNER JOIN sys.tables h ON t.history_table_id = h.object_id
+				INNER JOIN sys.schemas hs ON h.schema_id = hs.schema_id
+				LEFT JOIN sys.periods p ON p.object_id = t.object_id
+				LEFT JOIN sys.columns sc ON sc.object_id = t.object_id AND sc.column_id = p.start_column_id
+				LEFT JOIN sys.columns ec ON ec.object_id = t.object_id AND ec.column_id = p.end_column_id
+				WHERE t.temporal_type = 2`,
+		},
+	}
+}
+
+// RemoteServerMetadata returns SQL Server linked servers. Remote tables are reached through
+// four-part names or synonyms rather than local objects, so no foreign tables are listed.
+func (d *SQLServerDialect) RemoteServerMetadata() RemoteServerMetadataSQL {
+	return RemoteServerMetadataSQL{
+		ListServers: `
+			SELECT
+				s.name,
+				'LINKED_SERVER' AS kind,
+				s.provider,
+				s.product,
+				s.data_source,
+				s.catalog,
+				'data_access=' + CASE WHEN s.is_data_access_enabled = 1 THEN 'on' ELSE 'off' END
+					+ ', rpc_out=' + CASE WHEN s.is_rpc_out_enabled = 1 THEN 'on' ELSE 'off' END
+					+ ', remote_proc_transaction_promotion='
+					+ CASE WHEN s.is_remote_proc_transaction_promotion_enabled = 1 THEN 'on' ELSE 'off' END
+					AS options
+			FROM sys.servers s
+			WHERE s.is_linked = 1`,
+		NameFilter: " AND s.name LIKE %s",
+		OrderBy:    " ORDER BY s.name",
+	}
+}
+
+// ConstraintMetadata returns SQL Server constraint metadata queries
+func (d *SQLServerDialect) ConstraintMetadata() ConstraintMetadataSQL {
+	return ConstraintMetadataSQL{
+		ListForeignKeys: `
+			SELECT
+				s.name AS schema_name,
+				t.name AS table_name,
+				fk.name AS constraint_name,
+				COL_NAME(fkc.parent_object_id, fkc.parent_column_id) AS column_name,
+				SCHEMA_NAME(ref_t.schema_id) AS referenced_schema,
+				ref_t.name AS referenced_table,
+				COL_NAME(fkc.referenced_object_id, fkc.referenced_column_id) AS referenced_column,
+				REPLACE(fk.delete_referential_action_desc, '_', ' ') AS on_delete,
+				REPLACE(fk.update_referential_action_desc, '_', ' ') AS on_update
+			FROM sys.foreign_keys fk
+			INNER JOIN sys.foreign_key_columns fkc ON fk.object_id = fkc.constraint_object_id
+			INNER JOIN sys.tables t ON fk.parent_object_id = t.object_id
+			INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
+			INNER JOIN sys.tables ref_t ON fkc.referenced_object_id = ref_t.object_id
+			WHERE 1=1`,
+		ForeignKeySchemaFilter:          " AND s.name = %s",
+		ForeignKeyTableFilter:           " AND t.name = %s",
+		ForeignKeyReferencedTableFilter: " AND ref_t.name = %s",
+		ForeignKeyOrderBy:               " ORDER BY s.name, t.name, fk.name, fkc.constraint_column_id",
+		ListKeyConstraints: `
+			SELECT
+				s.name AS schema_name,
+				t.name AS table_name,
+				kc.name AS constraint_name,
+				CASE kc.type WHEN 'PK' THEN 'PRIMARY KEY' ELSE 'UNIQUE' END AS constraint_type,
+				c.name AS column_name,
+				ic.key_ordinal AS position
+			FROM sys.key_constraints kc
+			INNER JOIN sys.tables t ON kc.parent_object_id = t.object_id
+			INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
+			INNER JOIN sys.index_columns ic ON ic.object_id = kc.parent_object_id AND ic.index_id = kc.unique_index_id
+			INNER JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+			WHERE 1=1`,
+		KeySchemaFilter: " AND s.name = %s",
+		KeyTableFilter:  " AND t.name = %s",
+		KeyOrderBy:      " ORDER BY s.name, t.name, kc.type, kc.name, ic.key_ordinal",
+		ListCheckConstraints: `
+			SELECT
+				s.name AS schema_name,
+				t.name AS table_name,
+				cc.name AS constraint_name,
+				COL_NAME(cc.parent_object_id, NULLIF(cc.parent_column_id, 0)) AS column_name,
+				cc.definition
+			FROM sys.check_constraints cc
+			INNER JOIN sys.tables t ON cc.parent_object_id = t.object_id
+			INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
+			WHERE 1=1`,
+		CheckSchemaFilter: " AND s.name = %s",
+		CheckTableFilter:  " AND t.name = %s",
+		CheckOrderBy:      " ORDER BY s.name, t.name, cc.name",
+		ListDefaults: `
+			SELECT
+				s.name AS schema_name,
+				t.name AS table_name,
+				c.name AS column_name,
+				dc.name AS constraint_name,
+				dc.definition
+			FROM sys.default_constraints dc
+			INNER JOIN sys.tables t ON dc.parent_object_id = t.object_id
+			INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
+			INNER JOIN sys.columns c ON c.object_id = dc.parent_object_id AND c.column_id = dc.parent_column_id
+			WHERE 1=1`,
+		DefaultSchemaFilter: " AND s.name = %s",
+		DefaultTableFilter:  " AND t.name = %s",
+		DefaultOrderBy:      " ORDER BY s.name, t.name, c.column_id",
+	}
+}
+
 // DatabaseInfo returns SQL Server database info queries
 func (d *SQLServerDialect) DatabaseInfo() DatabaseInfoSQL {
 	return DatabaseInfoSQL{
@@ -297,6 +1181,55 @@ func (d *SQLServerDialect) DatabaseInfo() DatabaseInfoSQL {
 			WHERE schema_id < 16384
 			ORDER BY name`,
 
+		SchemaOverview: `
+			SELECT
+				s.name AS schema_name,
+				(SELECT COUNT(*) FROM sys.tables t WHERE t.schema_id = s.schema_id AND t.is_ms_shipped = 0) AS tables,
+				(SELECT COUNT(*) FROM sys.views v WHERE v.schema_id = s.schema_id AND v.is_ms_shipped = 0) AS views,
+				(SELECT COUNT(*) FROM sys.objects o WHERE o.schema_id = s.schema_id AND o.type IN ('FN', 'IF', 'TF') AND o.is_ms_shipped = 0) AS functions,
+				(SELECT COUNT(*) FROM sys.procedures p WHERE p.schema_id = s.schema_id AND p.is_ms_shipped = 0) AS procedures,
+				(SELECT SUM(CAST(au.used_pages AS BIGINT)) * 8192
+					FROM sys.tables t
+					INNER JOIN sys.partitions p ON p.object_id = t.object_id
+					INNER JOIN sys.allocation_units au ON au.container_id = p.partition_id
+					WHERE t.schema_id = s.schema_id) AS total_bytes
+			FROM sys.schemas s
+			WHERE s.schema_id < 16384
+				AND s.name NOT IN ('sys', 'INFORMATION_SCHEMA')`,
+
+		ListDatabases: `
+			SELECT
+				d.name,
+				d.state_desc,
+				CAST(SUM(CAST(mf.size AS BIGINT)) * 8 / 1024.0 AS DECIMAL(18, 2)) AS size_mb,
+				d.collation_name
+			FROM sys.databases d
+			LEFT JOIN sys.master_files mf ON d.database_id = mf.database_id
+			GROUP BY d.name, d.state_desc, d.collation_name
+			ORDER BY d.name`,
+
+		ReadOnlyDatabase: `
+			SELECT
+				CASE
+					WHEN d.source_database_id IS NOT NULL THEN 'snapshot'
+					WHEN d.is_in_standby = 1 THEN 'standby'
+					ELSE 'read-only'
+				END AS kind
+			FROM sys.databases d
+			WHERE d.name = @p1
+			  AND d.state_desc = 'ONLINE'
+			  AND (d.source_database_id IS NOT NULL
+			       OR d.is_in_standby = 1
+			       OR d.is_read_only = 1
+			       OR DATABASEPROPERTYEX(d.name, 'Updateability') = 'READ_ONLY')`,
+
+		// tempdb, and so temporary tables, use the server collation
+		Collation: `
+			SELECT
+				CAST(DATABASEPROPERTYEX(DB_NAME(), 'Collation') AS nvarchar(128)),
+				'code page ' + CAST(COLLATIONPROPERTY(CAST(DATABASEPROPERTYEX(DB_NAME(), 'Collation') AS nvarchar(128)), 'CodePage') AS varchar(10)),
+				CAST(SERVERPROPERTY('Collation') AS nvarchar(128))`,
+
 		SearchObjects: `
 			SELECT DISTINCT
 				s.name AS schema_name,
@@ -313,3 +1246,96 @@ func (d *SQLServerDialect) DatabaseInfo() DatabaseInfoSQL {
 			ORDER BY s.name, o.name`,
 	}
 }
+
+// Explain returns the SQL Server statements for estimated execution plans
+func (d *SQLServerDialect) Explain() ExplainSQL {
+	return ExplainSQL{
+		// Under SHOWPLAN_XML statements are compiled, not run, and return their plan
+		Setup:  "SET SHOWPLAN_XML ON",
+		Format: "xml",
+
+		// Under STATISTICS XML the results are followed by the actual plan
+		AnalyzeSetup:  "SET STATISTICS XML ON",
+		AnalyzeFormat: "xml",
+	}
+}
+
+// ResultLimit returns the SQL Server statements that cap the rows of a query
+func (d *SQLServerDialect) ResultLimit() ResultLimitSQL {
+	return ResultLimitSQL{
+		// ROWCOUNT also caps queries with a CTE or ORDER BY, which cannot be wrapped
+		SessionLimit: "SET ROWCOUNT %d",
+		SessionReset: "SET ROWCOUNT 0",
+	}
+}
+
+// ParseCheck returns the SQL Server statement that compiles queries without running them.
+// The driver prepares statements on their first run, so preparing checks nothing.
+func (d *SQLServerDialect) ParseCheck() ParseCheckSQL {
+	return ParseCheckSQL{
+		// Under SHOWPLAN_XML statements are compiled and optimized, which resolves their names
+		Setup: "SET SHOWPLAN_XML ON",
+	}
+}
+
+// Sample returns the SQL Server queries of sample_table_data. TABLESAMPLE reads whole pages.
+func (d *SQLServerDialect) Sample() SampleSQL {
+	return SampleSQL{
+		First:       "SELECT TOP (%[3]d) %[1]s FROM %[2]s",
+		Random:      "SELECT TOP (%[3]d) %[1]s FROM %[2]s ORDER BY NEWID()",
+		TableSample: "SELECT TOP (%[3]d) %[1]s FROM %[2]s TABLESAMPLE (%[4]s PERCENT) ORDER BY NEWID()",
+	}
+}
+
+// ColumnProfile returns the SQL Server column profile query. APPROX_COUNT_DISTINCT needs
+// SQL Server 2019, so distinct values are counted exactly.
+func (d *SQLServerDialect) ColumnProfile() ColumnProfileSQL {
+	return ColumnProfileSQL{
+		Query: `
+			SELECT s.row_count, s.null_count, s.distinct_count, s.min_value, s.max_value, s.avg_length, t.top_value, t.frequency
+			FROM (
+				SELECT COUNT_BIG(*) AS row_count, COUNT_BIG(*) - COUNT_BIG(%[1]s) AS null_count, COUNT_BIG(DISTINCT %[1]s) AS distinct_count,
+					MIN(%[1]s) AS min_value, MAX(%[1]s) AS max_value, AVG(CAST(LEN(CAST(%[1]s AS NVARCHAR(MAX))) AS FLOAT)) AS avg_length
+				FROM %[2]s
+			) s
+			LEFT JOIN (
+				SELECT TOP (%[3]d) %[1]s AS top_value, COUNT_BIG(*) AS frequency
+				FROM %[2]s
+				WHERE %[1]s IS NOT NULL
+				GROUP BY %[1]s
+				ORDER BY COUNT_BIG(*) DESC, %[1]s
+			) t ON 1 = 1
+			ORDER BY t.frequency DESC, t.top_value`,
+	}
+}
+
+// SyntaxReference returns the SQL Server syntax quick reference
+func (d *SQLServerDialect) SyntaxReference() SyntaxReferenceSQL {
+	return SyntaxReferenceSQL{
+		Product: "SQL Server",
+
+		FirstRows: "SELECT TOP (10) * FROM t ORDER BY id",
+		Page:      "SELECT * FROM t ORDER BY id OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY (ORDER BY is required)",
+
+		CurrentTimestamp: "SYSDATETIME() or GETDATE()",
+		CurrentDate:      "CAST(GETDATE() AS date)",
+		AddDays:          "DATEADD(day, 7, d)",
+		DaysBetween:      "DATEDIFF(day, d1, d2)",
+		Year:             "YEAR(d) or DATEPART(year, d)",
+		TruncateToMonth:  "DATEFROMPARTS(YEAR(d), MONTH(d), 1), or DATETRUNC(month, d) on SQL Server 2022",
+		FormatDate:       "CONVERT(char(10), d, 23)",
+
+		Concat:          "CONCAT(a, b) (NULL as empty) or a + b (NULL if either is NULL)",
+		Substring:       "SUBSTRING(s, 1, 3)",
+		Length:          "LEN(s) (ignores trailing spaces), DATALENGTH(s) in bytes",
+		Position:        "CHARINDEX('x', s)",
+		CaseInsensitive: "s LIKE '%x%' (case-insensitive with the default collations)",
+		Aggregate:       "STRING_AGG(s, ', ') WITHIN GROUP (ORDER BY s)",
+
+		QuoteIdentifier:    "[Order Details]",
+		Coalesce:           "COALESCE(a, b) or ISNULL(a, b)",
+		CastToText:         "CAST(a AS nvarchar(100))",
+		SelectWithoutTable: "SELECT GETDATE()",
+		Parameter:          "WHERE id = @p1 AND status = @p2, or @id with named parameters",
+	}
+}
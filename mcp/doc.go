@@ -0,0 +1,14 @@
+// Package mcp implements the Database MCP server: read-only database tools for SQL Server,
+// PostgreSQL, MySQL, Oracle and SQLite served over the Model Context Protocol.
+//
+// Besides the stdio binary, the package can be embedded in other Go services:
+//
+//   - NewDbMCPServerWithDB wraps an existing *sql.DB; MCPServer and Tools expose the
+//     registered tools to serve them over another transport or add them to another server
+//   - ValidateQuery rejects anything but a single read-only statement, and
+//     NewSQLValidator(query).Validate lists every rule a query breaks, with warnings
+//   - NewQueryBuilder and NewDialect build the per-driver metadata queries
+//   - ScanResultSet reads rows into a ResultSet, which serializes to JSON rows
+//
+// The database driver packages are not imported here; import the ones you need.
+package mcp
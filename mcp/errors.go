@@ -4,33 +4,76 @@ import "errors"
 
 // Connection errors
 var (
-	ErrNoConnection             = errors.New("no database connection. Use configure_datasource to connect first")
-	ErrConnectionFailed         = errors.New("failed to connect to database")
-	ErrConnectionTestFailed     = errors.New("connection test failed")
-	ErrDriverRequired           = errors.New("driver is required")
-	ErrConnectionStringRequired = errors.New("connection_string is required")
-	ErrConnecting               = errors.New("error connecting to database")
-	ErrTestingConnection        = errors.New("error testing connection")
+	ErrNoConnection              = errors.New("no database connection. Use configure_datasource to connect first")
+	ErrConnectionFailed          = errors.New("failed to connect to database")
+	ErrConnectionTestFailed      = errors.New("connection test failed")
+	ErrDriverRequired            = errors.New("driver is required")
+	ErrConnectionStringRequired  = errors.New("connection_string is required")
+	ErrConnecting                = errors.New("error connecting to database")
+	ErrNilDatabase               = errors.New("database connection pool is nil")
+	ErrTestingConnection         = errors.New("error testing connection")
+	ErrSessionSetup              = errors.New("error applying session settings")
+	ErrUndetectedDriver          = errors.New("could not detect the database driver from the connection string")
+	ErrInvalidSQLServerAuth      = errors.New("invalid SQL Server authentication - use: sql, ntlm, or integrated")
+	ErrSQLServerAuthOnly         = errors.New("auth, domain and spn only apply to the sqlserver driver")
+	ErrIntegratedAuthUnsupported = errors.New("integrated authentication uses Windows SSPI and is only available when the server runs on Windows - use ntlm with a domain login instead")
+	ErrNTLMUserRequired          = errors.New("ntlm authentication requires a user in the connection string")
+	ErrNTLMDomainRequired        = errors.New("ntlm authentication requires a DOMAIN\\user login or the domain option")
+	ErrDatabaseResuming          = errors.New("database is resuming from auto-pause - retry in a few seconds")
+	ErrSnapshotNotAllowed        = errors.New("database is not listed in DB_SNAPSHOT_DATABASES")
+	ErrSnapshotNotReadOnly       = errors.New("database is not an online snapshot, standby or read-only database")
+	ErrCheckingSnapshot          = errors.New("error checking target database")
+	ErrTxOptionsNotSupported     = errors.New("driver does not support transaction isolation levels or read-only transactions")
+	ErrUnknownConnection         = errors.New("unknown connection - use list_connections to see the connections")
+	ErrInvalidConnectionName     = errors.New("invalid connection name - use letters, digits, underscores and hyphens, other than default")
+	ErrDuplicateConnection       = errors.New("a connection of this name is already listed")
+	ErrInvalidConfigFile         = errors.New("invalid configuration file")
+	ErrTLSUnsupportedDriver      = errors.New("TLS options only apply to the sqlserver, postgres and mysql drivers")
+	ErrTLSOptionUnsupported      = errors.New("TLS option not supported by the driver")
+	ErrTLSClientKeyRequired      = errors.New("tls_cert_file and tls_key_file must be set together")
+	ErrInvalidTLSCertificate     = errors.New("invalid TLS certificate")
+	ErrInvalidSecretSource       = errors.New("invalid DB_SECRET_SOURCE - use: vault, aws, or azure")
+	ErrInvalidSecretTarget       = errors.New("invalid DB_SECRET_TARGET - use: connection_string or password")
+	ErrSecretNameRequired        = errors.New("DB_SECRET_NAME is required with DB_SECRET_SOURCE")
+	ErrSecretBackendConfig       = errors.New("the secrets backend is not configured")
+	ErrSecretFetch               = errors.New("could not read the secret")
+	ErrSecretKeyNotFound         = errors.New("the secret has no such key - set DB_SECRET_KEY")
+	ErrSecretPasswordUnsupported = errors.New("the password cannot be set in this connection string - use DB_SECRET_TARGET=connection_string")
 )
 
 // Argument errors
 var (
-	ErrInvalidArguments  = errors.New("invalid arguments")
-	ErrInvalidIdentifier = errors.New("invalid identifier")
-	ErrMissingRequired   = errors.New("missing required parameter")
+	ErrInvalidArguments   = errors.New("invalid arguments")
+	ErrInvalidIdentifier  = errors.New("invalid identifier")
+	ErrMissingRequired    = errors.New("missing required parameter")
 	ErrSearchTermRequired = errors.New("search_term is required")
+	ErrColumnNameRequired = errors.New("column_name is required")
+	ErrHandleRequired     = errors.New("handle is required")
+	ErrValueRequired      = errors.New("value is required")
+	ErrInvalidTimeout     = errors.New("timeout_seconds must be a positive whole number of seconds")
 )
 
 // Query errors
 var (
-	ErrQueryNotAllowed    = errors.New("query not allowed")
-	ErrQueryEmpty         = errors.New("empty query")
-	ErrQueryTooLong       = errors.New("query too long")
-	ErrQuerySyntax        = errors.New("error executing query - check the syntax")
-	ErrMultipleStatements = errors.New("multiple statements not allowed")
-	ErrQueryRequired      = errors.New("query is required")
-	ErrReadingRow         = errors.New("error reading row")
-	ErrReadingResults     = errors.New("error reading results")
+	ErrQueryNotAllowed             = errors.New("query not allowed")
+	ErrQueryEmpty                  = errors.New("empty query")
+	ErrQueryTooLong                = errors.New("query too long")
+	ErrQuerySyntax                 = errors.New("error executing query - check the syntax")
+	ErrExecutingQuery              = errors.New("error executing query")
+	ErrMultipleStatements          = errors.New("multiple statements not allowed")
+	ErrQueryRequired               = errors.New("query is required")
+	ErrReadingRow                  = errors.New("error reading row")
+	ErrReadingResults              = errors.New("error reading results")
+	ErrQueryKilled                 = errors.New("query killed by watchdog")
+	ErrQueryCancelled              = errors.New("query cancelled with cancel_query")
+	ErrExplainingQuery             = errors.New("error getting the execution plan")
+	ErrQueryRejected               = errors.New("query rejected by the database")
+	ErrQueryTooExpensive           = errors.New("query not allowed: its estimated cost exceeds the limit of the server - narrow it down")
+	ErrLargeTableScan              = errors.New("query not allowed: it reads a large table without a WHERE clause or a row limit")
+	ErrInvalidResultFormat         = errors.New("invalid format - use: json, csv or markdown")
+	ErrInvalidParameters           = errors.New("invalid query parameters")
+	ErrTooManyParameters           = errors.New("too many query parameters")
+	ErrNamedParametersNotSupported = errors.New("named parameters are not supported by this database - pass the parameters as an array")
 )
 
 // Query validation errors
@@ -48,9 +91,32 @@ var (
 	ErrSuspiciousCharacter         = errors.New("suspicious control character detected")
 	ErrExcessiveHexEncoding        = errors.New("excessive use of hexadecimal encoding")
 	ErrExcessiveCharFunction       = errors.New("excessive use of CHAR/NCHAR (possible obfuscation)")
+	ErrNestedComment               = errors.New("nested block comments are not allowed")
+	ErrInvisibleCharacter          = errors.New("invisible unicode character detected")
+	ErrHomoglyphCharacter          = errors.New("word mixing look-alike unicode letters")
+	ErrConcatenatedKeyword         = errors.New("keyword split across concatenated strings")
 	ErrTimeFunctionNotAllowed      = errors.New("time function not allowed")
 	ErrUnbalancedParentheses       = errors.New("unbalanced parentheses")
 	ErrParenthesesTooDeep          = errors.New("parenthesis depth too large")
+	ErrStatementNotAllowed         = errors.New("statement not allowed by the validation policy")
+	ErrReadStatementsOnly          = errors.New("only these read statements are allowed")
+	ErrPragmaNotAllowed            = errors.New("only PRAGMA statements reading the schema are allowed")
+	ErrDataModifyingCTE            = errors.New("data-modifying statement in a WITH query not allowed")
+	ErrLockingClauseNotAllowed     = errors.New("locking clause not allowed")
+	ErrKeywordDenied               = errors.New("keyword denied by the validation policy")
+	ErrInvalidValidationPolicy     = errors.New("invalid DB_VALIDATION_POLICY_FILE or DB_VALIDATION_POLICY - every query is rejected")
+	ErrTableNotAllowed             = errors.New("table not exposed by this server")
+	ErrTableNotQualified           = errors.New("table must be named with its schema, as this server restricts schemas or tables by schema")
+	ErrSchemaNotAllowed            = errors.New("schema not exposed by this server")
+	ErrShowNotAllowed              = errors.New("SHOW statements and PRAGMA statements without a table are not allowed while this server restricts schemas or tables")
+	ErrQueryFunctionNotAllowed     = errors.New("functions running a query or reading a table named in a string are not allowed while this server restricts schemas or tables")
+	ErrInvalidObjectFilter         = errors.New("invalid DB_ALLOWED_SCHEMAS, DB_DENIED_SCHEMAS, DB_ALLOWED_TABLES or DB_DENIED_TABLES - every tool call is rejected")
+)
+
+// Query validation warnings, which validate_query reports without rejecting the query
+var (
+	WarnSelectStar      = errors.New("SELECT * reads every column - list the columns needed")
+	WarnLeadingWildcard = errors.New("LIKE pattern starts with a wildcard, so no index can be used")
 )
 
 // Object errors
@@ -63,25 +129,44 @@ var (
 	ErrObjectNotFound    = errors.New("object not found")
 )
 
+// Permission errors
+var (
+	ErrPermissionDenied = errors.New("permission denied")
+)
+
 // Feature support errors
 var (
-	ErrStoredProceduresNotSupported = errors.New("stored procedures are not supported by this database")
-	ErrFunctionsNotSupported        = errors.New("functions are not supported by this database")
-	ErrFeatureNotSupported          = errors.New("feature not supported by this database")
+	ErrStoredProceduresNotSupported  = errors.New("stored procedures are not supported by this database")
+	ErrFunctionsNotSupported         = errors.New("functions are not supported by this database")
+	ErrFeatureNotSupported           = errors.New("feature not supported by this database")
+	ErrCrossDatabaseNotSupported     = errors.New("cross-database queries are not supported by this database")
+	ErrMaterializedViewsNotSupported = errors.New("materialized views are not supported by this database")
+	ErrTimeTravelNotSupported        = errors.New("point-in-time reads (as_of) are not supported by this database")
 )
 
 // Validation errors
 var (
-	ErrInvalidDriver        = errors.New("invalid database driver")
-	ErrInvalidTableName     = errors.New("invalid table name")
-	ErrInvalidViewName      = errors.New("invalid view name")
-	ErrInvalidProcedureName = errors.New("invalid procedure name")
-	ErrInvalidFunctionName  = errors.New("invalid function name")
-	ErrInvalidTriggerName   = errors.New("invalid trigger name")
-	ErrInvalidSchemaName    = errors.New("invalid schema name")
-	ErrInvalidColumnName    = errors.New("invalid column name")
-	ErrInvalidOperator      = errors.New("invalid operator")
-	ErrInvalidFunctionType  = errors.New("invalid function type - use: scalar, table, or all")
+	ErrInvalidDriver           = errors.New("invalid database driver")
+	ErrInvalidTableName        = errors.New("invalid table name")
+	ErrInvalidViewName         = errors.New("invalid view name")
+	ErrInvalidProcedureName    = errors.New("invalid procedure name")
+	ErrInvalidFunctionName     = errors.New("invalid function name")
+	ErrInvalidTriggerName      = errors.New("invalid trigger name")
+	ErrInvalidSchemaName       = errors.New("invalid schema name")
+	ErrInvalidColumnName       = errors.New("invalid column name")
+	ErrInvalidOperator         = errors.New("invalid operator")
+	ErrInvalidFunctionType     = errors.New("invalid function type - use: scalar, table, or all")
+	ErrInvalidDatabaseName     = errors.New("invalid database name")
+	ErrInvalidObjectName       = errors.New("invalid object name")
+	ErrInvalidDirection        = errors.New("invalid direction - use: upstream, downstream, or both")
+	ErrInvalidGrantee          = errors.New("invalid grantee - must be at most 128 characters")
+	ErrInvalidDictionaryFormat = errors.New("invalid format - use: json or markdown")
+	ErrInvalidSampleMethod     = errors.New("invalid method - use: random or top")
+	ErrInvalidMatchMode        = errors.New("invalid match - use: exact or contains")
+	ErrInvalidAggregate        = errors.New("invalid aggregate function - use: count, count_distinct, sum, avg, min or max")
+	ErrAggregateColumnRequired = errors.New("column is required by every aggregate function but count")
+	ErrInvalidAggregateAlias   = errors.New("invalid aggregate alias")
+	ErrDuplicateAggregateAlias = errors.New("duplicate result column")
 )
 
 // Data errors
@@ -90,6 +175,7 @@ var (
 	ErrDefinitionNotAvailable = errors.New("definition not available")
 	ErrNoColumnsFound         = errors.New("no columns found in the table")
 	ErrColumnNotExists        = errors.New("column does not exist")
+	ErrNoSearchableColumns    = errors.New("the table has no column that can hold the value")
 )
 
 // Serialization errors
@@ -99,21 +185,131 @@ var (
 
 // Operation errors
 var (
-	ErrListingTables      = errors.New("error listing tables")
-	ErrListingViews       = errors.New("error listing views")
-	ErrListingProcedures  = errors.New("error listing procedures")
-	ErrListingFunctions   = errors.New("error listing functions")
-	ErrListingTriggers    = errors.New("error listing triggers")
-	ErrDescribingTable    = errors.New("error describing table")
-	ErrCheckingTable      = errors.New("error checking table")
-	ErrRetrievingColumns  = errors.New("error retrieving columns")
-	ErrCountingRows       = errors.New("error counting rows")
-	ErrFetchingRows       = errors.New("error fetching rows")
-	ErrSearchingObjects   = errors.New("error searching objects")
-	ErrFetchingCode       = errors.New("error fetching code")
-	ErrExecutingProcedure = errors.New("error executing procedure")
-	ErrRetrievingView     = errors.New("error retrieving view definition")
-	ErrRetrievingTrigger  = errors.New("error retrieving trigger code")
+	ErrListingTables            = errors.New("error listing tables")
+	ErrListingViews             = errors.New("error listing views")
+	ErrListingMatViews          = errors.New("error listing materialized views")
+	ErrListingProcedures        = errors.New("error listing procedures")
+	ErrListingFunctions         = errors.New("error listing functions")
+	ErrListingTriggers          = errors.New("error listing triggers")
+	ErrListingSynonyms          = errors.New("error listing synonyms")
+	ErrListingTypes             = errors.New("error listing user-defined types")
+	ErrFetchingDependencies     = errors.New("error fetching object dependencies")
+	ErrListingDatabases         = errors.New("error listing databases")
+	ErrListingExtensions        = errors.New("error listing extensions")
+	ErrListingForeignKeys       = errors.New("error listing foreign keys")
+	ErrListingKeys              = errors.New("error listing key constraints")
+	ErrListingChecks            = errors.New("error listing check constraints")
+	ErrListingComputedColumns   = errors.New("error listing computed columns")
+	ErrListingStatistics        = errors.New("error listing statistics")
+	ErrListingDefaults          = errors.New("error listing column defaults")
+	ErrDescribingTable          = errors.New("error describing table")
+	ErrCheckingTable            = errors.New("error checking table")
+	ErrRetrievingColumns        = errors.New("error retrieving columns")
+	ErrCountingRows             = errors.New("error counting rows")
+	ErrFetchingTableStats       = errors.New("error fetching table statistics")
+	ErrListingPartitions        = errors.New("error listing partitions")
+	ErrFetchingRows             = errors.New("error fetching rows")
+	ErrProfilingColumn          = errors.New("error profiling column")
+	ErrFetchingDistinctValues   = errors.New("error fetching distinct values")
+	ErrSearchingValue           = errors.New("error searching the value")
+	ErrAggregatingTable         = errors.New("error aggregating the table")
+	ErrSearchingObjects         = errors.New("error searching objects")
+	ErrSearchingDefinitions     = errors.New("error searching object definitions")
+	ErrFindingColumns           = errors.New("error finding columns")
+	ErrFetchingCode             = errors.New("error fetching code")
+	ErrFetchingParameters       = errors.New("error fetching parameters")
+	ErrListingPermissions       = errors.New("error listing permissions")
+	ErrListingRLSPolicies       = errors.New("error listing row-level security policies")
+	ErrListingPrincipals        = errors.New("error listing users and roles")
+	ErrListingRemoteServers     = errors.New("error listing remote servers")
+	ErrListingScheduledJobs     = errors.New("error listing scheduled jobs")
+	ErrListingTemporalTables    = errors.New("error listing temporal tables")
+	ErrRetrievingChangeTracking = errors.New("error retrieving change tracking status")
+	ErrExecutingProcedure       = errors.New("error executing procedure")
+	ErrRetrievingView           = errors.New("error retrieving view definition")
+	ErrRetrievingTrigger        = errors.New("error retrieving trigger code")
+	ErrRetrievingTableDDL       = errors.New("error retrieving table DDL")
+	ErrRetrievingComments       = errors.New("error retrieving comments")
+	ErrGeneratingERD            = errors.New("error generating entity relationship diagram")
+	ErrExportingDataDictionary  = errors.New("error exporting data dictionary")
+	ErrBuildingSchemaOverview   = errors.New("error building schema overview")
+	ErrRetrievingIdentityStatus = errors.New("error retrieving identity status")
+	ErrRetrievingCollation      = errors.New("error retrieving collation")
+)
+
+// Time travel errors
+var (
+	ErrInvalidAsOf         = errors.New("invalid as_of - use a timestamp such as 2024-05-01T08:00:00Z")
+	ErrAsOfInFuture        = errors.New("as_of is in the future")
+	ErrAsOfBeyondRetention = errors.New("as_of is older than the history retention period of the table")
+	ErrTableNotVersioned   = errors.New("table is not a system-versioned temporal table - as_of needs its history")
+)
+
+// Access report errors
+var (
+	ErrInvalidWindow = errors.New("invalid window - use a duration such as 30m or 24h")
+)
+
+// Preview errors
+var (
+	ErrResultHandleNotFound = errors.New("result handle not found or expired - call the listing tool again")
+)
+
+// Cursor errors
+var (
+	ErrCursorRequired = errors.New("cursor is required")
+	ErrCursorNotFound = errors.New("cursor not found or expired - run the query again with cursor")
+	ErrTooManyCursors = errors.New("too many open cursors - read them to the end or close them with fetch_more")
+)
+
+// Export errors
+var (
+	ErrExportDirNotConfigured = errors.New("DB_EXPORT_DIR is not set - omit file_name to return the export inline")
+	ErrInvalidExportFileName  = errors.New("invalid file name - use letters, digits, '.', '_' and '-'")
+	ErrExportFileExists       = errors.New("export file already exists")
+	ErrExportTooLarge         = errors.New("export is larger than 8MB - set DB_EXPORT_DIR and pass file_name to write it to a file")
+	ErrExportValue            = errors.New("value cannot be exported with the type of its column")
+	ErrWritingExport          = errors.New("error writing export")
+)
+
+// Saved query errors
+var (
+	ErrSavedQueriesNotConfigured = errors.New("DB_SAVED_QUERIES_FILE is not set - saved queries are disabled")
+	ErrSavedQueriesReadOnly      = errors.New("saved queries are read-only (DB_SAVED_QUERIES_READONLY)")
+	ErrInvalidSavedQueryName     = errors.New("invalid saved query name - use letters, digits and underscores")
+	ErrSavedQueryNotFound        = errors.New("saved query not found - call list_saved_queries")
+	ErrSavedQueryExists          = errors.New("a saved query with this name already exists - pass overwrite to replace it")
+	ErrDuplicateParameter        = errors.New("duplicate parameter")
+	ErrUnknownParameter          = errors.New("unknown parameter")
+	ErrMissingParameter          = errors.New("missing parameter")
+	ErrReadingSavedQueries       = errors.New("error reading saved queries")
+	ErrWritingSavedQueries       = errors.New("error writing saved queries")
+)
+
+// Write mode errors
+var (
+	ErrInsertRowsRequired   = errors.New("rows required - pass at least one row of column values")
+	ErrTooManyInsertRows    = errors.New("too many rows to insert")
+	ErrUpdateValuesRequired = errors.New("values required - pass the new column values")
+	ErrWriteFiltersRequired = errors.New("filters required - updates and deletes must select rows with at least one filter")
+	ErrInvalidColumnValue   = errors.New("invalid column value")
+	ErrAffectedRowsExceeded = errors.New("the statement affected more rows than allowed and was rolled back")
+	ErrExecutingWrite       = errors.New("error executing write statement")
+	ErrWritingAuditLog      = errors.New("error writing write audit log")
+)
+
+// Running query errors
+var (
+	ErrQueryIDRequired      = errors.New("id is required")
+	ErrRunningQueryNotFound = errors.New("no running query with this id - it may have finished, call list_running_queries")
+	ErrInvalidQueryStatus   = errors.New("invalid status - use: ok, error, killed or cancelled")
+)
+
+// Bench errors
+var (
+	ErrReadingBenchTrace = errors.New("error reading bench trace")
+	ErrEmptyBenchTrace   = errors.New("bench trace has no tool calls")
+	ErrUnknownBenchTool  = errors.New("bench trace references an unknown tool")
 )
 
 // Filter errors
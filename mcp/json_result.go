@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// jsonBufferPool reuses serialization buffers across tool calls
+var jsonBufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// getJSONBuffer returns an empty buffer from the pool
+func getJSONBuffer() *bytes.Buffer {
+	buf := jsonBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// putJSONBuffer returns a buffer to the pool, dropping oversized ones so a single
+// large result does not pin its memory
+func putJSONBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > MaxPooledJSONBufferBytes {
+		return
+	}
+	jsonBufferPool.Put(buf)
+}
+
+// jsonToolResult serializes a tool response with a streaming encoder into a pooled buffer.
+// Small payloads are indented for readability; large ones are kept compact.
+func jsonToolResult(response interface{}) *mcp.CallToolResult {
+	buf := getJSONBuffer()
+	defer putJSONBuffer(buf)
+
+	if err := json.NewEncoder(buf).Encode(response); err != nil {
+		return toolErrorResult(ErrSerializingJSON)
+	}
+	compact := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+
+	if len(compact) > MaxIndentedJSONBytes {
+		return mcp.NewToolResultText(string(compact))
+	}
+
+	indented := getJSONBuffer()
+	defer putJSONBuffer(indented)
+
+	if err := json.Indent(indented, compact, "", "  "); err != nil {
+		return mcp.NewToolResultText(string(compact))
+	}
+
+	return mcp.NewToolResultText(indented.String())
+}
@@ -0,0 +1,217 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// The large-table guard rejects queries reading a large table with neither a WHERE clause
+// nor a row limit, which would scan the whole table, or only warns about them with
+// DB_LARGE_TABLE_GUARD_MODE=warn. Tables are large when DB_LARGE_TABLES lists them, or
+// when the catalog estimates at least DB_LARGE_TABLE_ROWS rows for them. Estimates are read
+// once per schema and kept for LargeTableEstimateTTL; databases without catalog statistics
+// (SQLite) are only checked against the list.
+
+// largeTableGuard holds the tables and the row estimate over which unfiltered queries are
+// rejected or warned about
+type largeTableGuard struct {
+	tables   []tablePattern
+	minRows  int64
+	warnOnly bool
+
+	mu        sync.Mutex
+	estimates map[string]rowEstimates // by schema in lower case, "" for every schema
+}
+
+// rowEstimates are the catalog row estimates of the tables of a schema, by table name in
+// lower case, with the time they were read
+type rowEstimates struct {
+	rows map[string]int64
+	read time.Time
+}
+
+// rowLimitWords are the words giving a query a row limit
+var rowLimitWords = []string{"LIMIT", "TOP", "FETCH", "ROWNUM"}
+
+// newLargeTableGuard reads the large-table guard settings from the environment
+func newLargeTableGuard() *largeTableGuard {
+	guard := &largeTableGuard{
+		minRows:   envInt("DB_LARGE_TABLE_ROWS", 0),
+		estimates: make(map[string]rowEstimates),
+	}
+	tables, err := envTablePatterns("DB_LARGE_TABLES")
+	if err != nil {
+		log.Printf("Warning: Ignoring invalid %v", err)
+	}
+	guard.tables = tables
+	switch mode := strings.ToLower(strings.TrimSpace(os.Getenv("DB_LARGE_TABLE_GUARD_MODE"))); mode {
+	case "", "reject":
+	case "warn":
+		guard.warnOnly = true
+	default:
+		log.Printf("Warning: Ignoring invalid DB_LARGE_TABLE_GUARD_MODE=%q", mode)
+	}
+	return guard
+}
+
+// enabled reports whether tables are listed or a row estimate threshold is configured
+func (g *largeTableGuard) enabled() bool {
+	return len(g.tables) > 0 || g.minRows > 0
+}
+
+// listed reports whether DB_LARGE_TABLES lists a table. An empty schema matches the
+// patterns of any schema, as for an unqualified table of a query.
+func (g *largeTableGuard) listed(schema, table string) bool {
+	for _, pattern := range g.tables {
+		if (pattern.schema == "" || schema == "" || matchName(pattern.schema, schema)) && matchName(pattern.table, table) {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutEstimates returns a guard with the same settings and no row estimates, for the
+// view of the server serving a named connection
+func (g *largeTableGuard) withoutEstimates() *largeTableGuard {
+	return &largeTableGuard{
+		tables:    g.tables,
+		minRows:   g.minRows,
+		warnOnly:  g.warnOnly,
+		estimates: make(map[string]rowEstimates),
+	}
+}
+
+// clear drops the row estimates, when the server connects to another database
+func (g *largeTableGuard) clear() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	clear(g.estimates)
+}
+
+// checkLargeTables runs the large-table guard on a query before it is executed. It returns
+// an error result when the query is rejected, or the warning to add to the response when
+// the guard only warns; both are nil when the query filters or limits its rows, or reads
+// no large table.
+func (s *DbMCPServer) checkLargeTables(ctx context.Context, query string) (map[string]interface{}, *mcp.CallToolResult) {
+	guard := s.largeTables
+	if !guard.enabled() || !s.queryBuilder.IsSelectQuery(query) {
+		return nil, nil
+	}
+
+	tokens := lexSQL(query, sqlLexRulesFor(s.queryBuilder.GetDriver())[0])
+	for _, token := range tokens {
+		if token.is("WHERE") {
+			return nil, nil
+		}
+		for _, word := range rowLimitWords {
+			if token.is(word) {
+				return nil, nil
+			}
+		}
+	}
+
+	var large []map[string]interface{}
+	for _, name := range queryTableNames(tokens) {
+		table := map[string]interface{}{"table": name[1]}
+		if name[0] != "" {
+			table["schema"] = name[0]
+		}
+		switch rows, ok := s.estimatedTableRows(ctx, name[0], name[1]); {
+		case guard.listed(name[0], name[1]):
+			if ok {
+				table["estimated_rows"] = rows
+			}
+		case ok && rows >= guard.minRows:
+			table["estimated_rows"] = rows
+		default:
+			continue
+		}
+		large = append(large, table)
+	}
+	if len(large) == 0 {
+		return nil, nil
+	}
+
+	details := map[string]interface{}{
+		"large_tables": large,
+	}
+	if guard.minRows > 0 {
+		details["min_rows"] = guard.minRows
+	}
+	if guard.warnOnly {
+		log.Printf("Large-table guard warning: query reads a large table without WHERE or a row limit\nQuery: %s\n", query)
+		details["message"] = translate("The query reads a large table without a WHERE clause or a row limit; it ran anyway, but consider filtering or limiting its rows")
+		return details, nil
+	}
+
+	log.Printf("Query rejected by the large-table guard: no WHERE clause or row limit\nQuery: %s\n", query)
+	details["error"] = localizeError(ErrLargeTableScan)
+	details["code"] = "large_table_scan"
+	details["hint"] = translate("Add a WHERE clause or a row limit (LIMIT, TOP or FETCH FIRST), or explore the table with sample_table_data and estimate_row_counts")
+	return nil, errorJSONResult(details, ErrLargeTableScan)
+}
+
+// estimatedTableRows returns the catalog row estimate of a table, or false when there is
+// no threshold, the database keeps no statistics or the table has no estimate. Without a
+// schema, the largest estimate of the tables of that name in any schema is used.
+func (s *DbMCPServer) estimatedTableRows(ctx context.Context, schema, table string) (int64, bool) {
+	guard := s.largeTables
+	if guard.minRows <= 0 {
+		return 0, false
+	}
+	schema = strings.ToLower(schema)
+
+	guard.mu.Lock()
+	estimates, ok := guard.estimates[schema]
+	guard.mu.Unlock()
+	if !ok || time.Since(estimates.read) > LargeTableEstimateTTL {
+		// Estimates that could not be read are not asked for again until they expire
+		var err error
+		if estimates, err = s.readRowEstimates(ctx, schema); err != nil {
+			log.Printf("Large-table guard could not read the row estimates: %v", err)
+		}
+		guard.mu.Lock()
+		guard.estimates[schema] = estimates
+		guard.mu.Unlock()
+	}
+
+	rows, ok := estimates.rows[strings.ToLower(table)]
+	return rows, ok
+}
+
+// readRowEstimates reads the catalog row estimates of the tables of a schema, or of every
+// schema when schema is empty
+func (s *DbMCPServer) readRowEstimates(ctx context.Context, schema string) (rowEstimates, error) {
+	estimates := rowEstimates{rows: make(map[string]int64), read: time.Now()}
+	query, args, ok := s.queryBuilder.EstimatedRowCountsQuery(schema)
+	if !ok {
+		return estimates, nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return estimates, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableSchema, name string
+		var rowCount sql.NullInt64
+		if rows.Scan(&tableSchema, &name, &rowCount) != nil || !rowCount.Valid {
+			continue
+		}
+		name = strings.ToLower(name)
+		estimates.rows[name] = max(estimates.rows[name], rowCount.Int64)
+	}
+	return estimates, rows.Err()
+}
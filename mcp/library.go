@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// NewDbMCPServerWithDB creates a server around a connection pool owned by the caller,
+// for services that embed the tools instead of running the stdio binary. The driver is
+// one of sqlserver, postgres, mysql, sqlite (sqlite3) or oracle (godror). Environment
+// options such as DB_MAX_RESULT_BYTES and DB_QUERY_WATERMARK still apply; the pool is
+// used as is, so session statements and watermarking must be set up by the caller.
+// Close closes the pool.
+func NewDbMCPServerWithDB(db *sql.DB, driver string) (*DbMCPServer, error) {
+	if db == nil {
+		return nil, ErrNilDatabase
+	}
+
+	normalizedDriver := normalizeDriver(driver)
+	if normalizedDriver == "" {
+		return nil, fmt.Errorf("%w: '%s'", ErrInvalidDriver, driver)
+	}
+
+	return newDbMCPServer(db, normalizedDriver), nil
+}
+
+// MCPServer returns the underlying MCP server, to serve it over a transport other than
+// stdio (e.g. server.NewStreamableHTTPServer)
+func (s *DbMCPServer) MCPServer() *server.MCPServer {
+	return s.server
+}
+
+// Tools returns the registered tools sorted by name, to add them to another MCP server
+// with AddTools. The handlers keep using this server's connection and watchdog.
+func (s *DbMCPServer) Tools() []server.ServerTool {
+	registered := s.server.ListTools()
+	middlewares := s.toolMiddlewares()
+
+	tools := make([]server.ServerTool, 0, len(registered))
+	for _, tool := range registered {
+		// The middleware of this server does not run for tools added elsewhere
+		handler := tool.Handler
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			handler = middlewares[i](handler)
+		}
+		tools = append(tools, server.ServerTool{Tool: tool.Tool, Handler: handler})
+	}
+	sort.Slice(tools, func(i, j int) bool {
+		return tools[i].Tool.Name < tools[j].Tool.Name
+	})
+	return tools
+}
+
+// ValidateQuery checks that a query is a single read-only statement, as execute_query does
+// before running it. The error is a *ValidationError listing every rule the query breaks;
+// NewSQLValidator(query).Validate() also returns the warnings.
+func ValidateQuery(query string) error {
+	return NewSQLValidator(query).Validate().Err()
+}
+
+// ScanResultSet reads up to maxRows rows (0 for no limit) into a ResultSet, formatting
+// values the way the tools do. It reports whether more rows were available; the caller
+// closes rows.
+func ScanResultSet(rows *sql.Rows, maxRows int) (*ResultSet, bool, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, false, err
+	}
+
+	results := newResultSet(columns)
+	encoder := newResultEncoder(rows)
+
+	values := make([]interface{}, len(columns))
+	valuePtrs := make([]interface{}, len(columns))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if maxRows > 0 && results.Len() >= maxRows {
+			return results, true, rows.Err()
+		}
+		if err = rows.Scan(valuePtrs...); err != nil {
+			return nil, false, err
+		}
+		encoder.encode(values)
+		results.AppendRow(values)
+	}
+
+	return results, false, rows.Err()
+}
@@ -0,0 +1,60 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// TestToolsMiddlewares checks that the tools added to another MCP server run the middlewares
+// of this one: the object filter and the named connections. The calls fail before reaching
+// the database, which is never connected to.
+func TestToolsMiddlewares(t *testing.T) {
+	t.Setenv("DB_DENIED_SCHEMAS", "hidden")
+	db, err := sql.Open("postgres", "host=127.0.0.1 port=1 user=test dbname=test sslmode=disable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewDbMCPServerWithDB(db, "postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	var describeTable server.ToolHandlerFunc
+	for _, tool := range s.Tools() {
+		if tool.Tool.Name == "describe_table" {
+			describeTable = tool.Handler
+		}
+	}
+	if describeTable == nil {
+		t.Fatal("Tools() has no describe_table tool")
+	}
+
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"hidden schema", map[string]interface{}{"table_name": "t", "schema": "hidden"}, ErrSchemaNotAllowed.Error()},
+		{"unknown connection", map[string]interface{}{"table_name": "t", "schema": "public", "connection": "missing"}, ErrUnknownConnection.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var request mcp.CallToolRequest
+			request.Params.Name = "describe_table"
+			request.Params.Arguments = tt.args
+			result, err := describeTable(context.Background(), request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, tt.want) {
+				t.Errorf("describe_table(%v) = %+v, want an error containing %q", tt.args, result.Content, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,314 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/invopop/jsonschema"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Responses are rendered as markdown for chat clients that display it: scalar fields as a
+// bullet list, lists of objects as tables and nested objects as sections. Listing tools
+// accept format markdown through markdownMiddleware, execute_query through its own format.
+
+// jsonObject is a JSON object that keeps the order of its keys, so table columns follow the
+// column order of the rows
+type jsonObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// addFormatProperty adds the format argument of listing tools to their input schema
+func addFormatProperty(schema *jsonschema.Schema) {
+	if schema.Properties == nil {
+		schema.Properties = jsonschema.NewProperties()
+	}
+	schema.Properties.Set("format", &jsonschema.Schema{
+		Type:        "string",
+		Description: "Response format: json or markdown (tables for lists, for chat clients that render markdown) (default: json)",
+		Enum:        []interface{}{"json", "markdown"},
+	})
+}
+
+// markdownMiddleware renders the responses of listing tools called with format markdown
+func markdownMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, request)
+		if err != nil || !isListingTool(request.Params.Name) || !strings.EqualFold(request.GetString("format", ""), "markdown") {
+			return result, err
+		}
+		return markdownResult(result), nil
+	}
+}
+
+// markdownToolResult returns a response rendered as markdown
+func markdownToolResult(response map[string]interface{}) *mcp.CallToolResult {
+	return markdownResult(jsonToolResult(response))
+}
+
+// markdownResult renders the JSON object of a result as markdown. Errors and results that
+// are not a JSON object are returned as they are.
+func markdownResult(result *mcp.CallToolResult) *mcp.CallToolResult {
+	if result == nil || result.IsError || len(result.Content) != 1 {
+		return result
+	}
+	content, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		return result
+	}
+
+	dec := json.NewDecoder(strings.NewReader(content.Text))
+	dec.UseNumber()
+	value, err := decodeOrdered(dec)
+	if err != nil {
+		return result
+	}
+	object, ok := value.(*jsonObject)
+	if !ok {
+		return result
+	}
+
+	w := &markdownWriter{root: object}
+	w.object(object, "", 0)
+	w.footers()
+	return mcp.NewToolResultText(strings.TrimSpace(w.buf.String()))
+}
+
+// decodeOrdered decodes the next JSON value, keeping the key order of objects
+func decodeOrdered(dec *json.Decoder) (interface{}, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		object := &jsonObject{values: make(map[string]interface{})}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			name, _ := key.(string)
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			if _, seen := object.values[name]; !seen {
+				object.keys = append(object.keys, name)
+			}
+			object.values[name] = value
+		}
+		_, err = dec.Token()
+		return object, err
+	case json.Delim('['):
+		items := []interface{}{}
+		for dec.More() {
+			item, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		_, err = dec.Token()
+		return items, err
+	}
+	return token, nil
+}
+
+// markdownWriter renders a decoded response
+type markdownWriter struct {
+	buf  strings.Builder
+	root *jsonObject
+}
+
+// object writes the scalar fields of an object as a bullet list, then its lists of objects
+// as tables and its nested objects as sections
+func (w *markdownWriter) object(object *jsonObject, path string, depth int) {
+	var sections []string
+	for _, key := range object.keys {
+		if depth == 0 && key == "preview" {
+			// Summarized by the elided rows footers
+			continue
+		}
+		switch value := object.values[key].(type) {
+		case *jsonObject:
+			sections = append(sections, key)
+		case []interface{}:
+			if isObjectList(value) {
+				sections = append(sections, key)
+			} else {
+				w.bullet(key, value)
+			}
+		default:
+			w.bullet(key, value)
+		}
+	}
+
+	for _, key := range sections {
+		heading := strings.Repeat("#", min(depth+2, 6))
+		fmt.Fprintf(&w.buf, "\n%s %s\n\n", heading, key)
+		switch value := object.values[key].(type) {
+		case *jsonObject:
+			w.object(value, joinPath(path, key), depth+1)
+		case []interface{}:
+			w.table(value)
+			w.elided(joinPath(path, key), len(value))
+		}
+	}
+}
+
+// bullet writes a field as a bullet list item
+func (w *markdownWriter) bullet(key string, value interface{}) {
+	fmt.Fprintf(&w.buf, "- **%s**:", key)
+	if text := markdownValue(value); text != "" {
+		w.buf.WriteString(" " + text)
+	}
+	w.buf.WriteString("\n")
+}
+
+// table writes a list of objects as a table with a column per key, numeric columns aligned
+// to the right and cells padded to the column width
+func (w *markdownWriter) table(items []interface{}) {
+	if len(items) == 0 {
+		w.buf.WriteString("_" + translate("No rows") + "_\n")
+		return
+	}
+
+	var columns []string
+	seen := make(map[string]bool)
+	for _, item := range items {
+		for _, key := range item.(*jsonObject).keys {
+			if !seen[key] {
+				seen[key] = true
+				columns = append(columns, key)
+			}
+		}
+	}
+
+	cells := make([][]string, len(items))
+	widths := make([]int, len(columns))
+	numeric := make([]bool, len(columns))
+	for i, column := range columns {
+		widths[i] = max(utf8.RuneCountInString(column), 3)
+		numeric[i] = true
+	}
+	for row, item := range items {
+		object := item.(*jsonObject)
+		cells[row] = make([]string, len(columns))
+		for i, column := range columns {
+			value := object.values[column]
+			if _, ok := value.(json.Number); !ok && value != nil {
+				numeric[i] = false
+			}
+			cells[row][i] = markdownValue(value)
+			widths[i] = max(widths[i], min(utf8.RuneCountInString(cells[row][i]), MarkdownMaxColumnWidth))
+		}
+	}
+
+	w.row(columns, widths, numeric)
+	separators := make([]string, len(columns))
+	for i := range columns {
+		if numeric[i] {
+			separators[i] = strings.Repeat("-", widths[i]-1) + ":"
+		} else {
+			separators[i] = strings.Repeat("-", widths[i])
+		}
+	}
+	w.row(separators, widths, numeric)
+	for _, row := range cells {
+		w.row(row, widths, numeric)
+	}
+}
+
+// row writes a table row, padding each cell to its column width
+func (w *markdownWriter) row(cells []string, widths []int, numeric []bool) {
+	w.buf.WriteString("|")
+	for i, cell := range cells {
+		padding := strings.Repeat(" ", max(widths[i]-utf8.RuneCountInString(cell), 0))
+		if numeric[i] {
+			fmt.Fprintf(&w.buf, " %s%s |", padding, cell)
+		} else {
+			fmt.Fprintf(&w.buf, " %s%s |", cell, padding)
+		}
+	}
+	w.buf.WriteString("\n")
+}
+
+// elided writes the footer of a table whose list was cut to a preview
+func (w *markdownWriter) elided(path string, shown int) {
+	preview, ok := w.root.values["preview"].(*jsonObject)
+	if !ok {
+		return
+	}
+	truncated, ok := preview.values["truncated"].(*jsonObject)
+	if !ok {
+		return
+	}
+	list, ok := truncated.values[path].(*jsonObject)
+	if !ok {
+		return
+	}
+	fmt.Fprintf(&w.buf, "\n_"+translate("Showing %d of %s rows. Call fetch_full with handle %s for all of them")+"_\n",
+		shown, markdownValue(list.values["total"]), markdownValue(preview.values["handle"]))
+}
+
+// footers writes why a query result has fewer rows than the query returns
+func (w *markdownWriter) footers() {
+	rows, _ := w.root.values["row_count"].(json.Number)
+	if cursor, ok := w.root.values["cursor"].(string); ok && w.root.values["has_more"] == true {
+		fmt.Fprintf(&w.buf, "\n_"+translate("Showing %s rows; more remain. Call fetch_more with cursor %s for the next chunk")+"_\n", rows, cursor)
+		return
+	}
+	if w.root.values["truncated"] == true && rows != "" {
+		fmt.Fprintf(&w.buf, "\n_"+translate("Showing %s rows; the result has more rows than were returned")+"_\n", rows)
+	}
+}
+
+// isObjectList reports whether a list holds only objects, so it can be written as a table
+func isObjectList(items []interface{}) bool {
+	for _, item := range items {
+		if _, ok := item.(*jsonObject); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// markdownValue returns a value as the text of a table cell or bullet: NULLs are empty,
+// lists of scalars are joined with commas and objects are written as their key: value pairs
+func markdownValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return markdownCell(v)
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = markdownValue(item)
+		}
+		return strings.Join(parts, ", ")
+	case *jsonObject:
+		parts := make([]string, len(v.keys))
+		for i, key := range v.keys {
+			parts[i] = key + ": " + markdownValue(v.values[key])
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	default:
+		return markdownCell(fmt.Sprint(v))
+	}
+}
+
+// joinPath returns the path of a field as recorded by the preview truncation
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
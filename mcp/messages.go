@@ -0,0 +1,125 @@
+package mcp
+
+import (
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/invopop/jsonschema"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Language is the language of error messages, hints and tool descriptions
+type Language string
+
+// Supported languages
+const (
+	LanguageEnglish    Language = "en"
+	LanguagePortuguese Language = "pt"
+	LanguageSpanish    Language = "es"
+)
+
+// messageCatalog maps the English text of a message to its translations. English is the
+// source language: messages are written in English in the code and looked up here.
+var messageCatalog = map[Language]map[string]string{
+	LanguagePortuguese: messagesPortuguese,
+	LanguageSpanish:    messagesSpanish,
+}
+
+var (
+	languageOnce sync.Once
+	language     Language
+)
+
+// getLanguage returns the process-wide language configured by DB_LANGUAGE (default: en)
+func getLanguage() Language {
+	languageOnce.Do(func() {
+		language = LanguageEnglish
+		value := os.Getenv("DB_LANGUAGE")
+		if value == "" {
+			return
+		}
+		if lang, ok := parseLanguage(value); ok {
+			language = lang
+			return
+		}
+		log.Printf("Warning: Ignoring unsupported DB_LANGUAGE=%q (use: en, pt or es)", value)
+	})
+	return language
+}
+
+// parseLanguage accepts a language code or a locale such as pt-BR or es_ES.UTF-8
+func parseLanguage(value string) (Language, bool) {
+	code := strings.ToLower(strings.TrimSpace(value))
+	if i := strings.IndexAny(code, "-_."); i >= 0 {
+		code = code[:i]
+	}
+
+	switch Language(code) {
+	case LanguageEnglish, LanguagePortuguese, LanguageSpanish:
+		return Language(code), true
+	}
+	return "", false
+}
+
+// translate returns a message in the configured language. Messages without a translation
+// are returned unchanged; format strings are translated before they are formatted.
+func translate(message string) string {
+	if translated, ok := messageCatalog[getLanguage()][message]; ok {
+		return translated
+	}
+	return message
+}
+
+// localizeError returns the message of err with the message of every error it wraps
+// translated, so "%w: detail" errors keep their detail and only the known parts change
+func localizeError(err error) string {
+	message := err.Error()
+	catalog := messageCatalog[getLanguage()]
+	if catalog == nil {
+		return message
+	}
+
+	pending := []error{err}
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+
+		text := current.Error()
+		if translated, ok := catalog[text]; ok {
+			message = strings.Replace(message, text, translated, 1)
+		}
+
+		switch wrapped := current.(type) {
+		case interface{ Unwrap() []error }:
+			pending = append(pending, wrapped.Unwrap()...)
+		default:
+			if inner := errors.Unwrap(current); inner != nil {
+				pending = append(pending, inner)
+			}
+		}
+	}
+	return message
+}
+
+// toolErrorResult returns the tool error result for err in the configured language
+func toolErrorResult(err error) *mcp.CallToolResult {
+	return mcp.NewToolResultError(localizeError(err))
+}
+
+// translateSchema translates the descriptions of a generated input schema and its
+// nested properties and items
+func translateSchema(schema *jsonschema.Schema) {
+	if schema == nil {
+		return
+	}
+	schema.Description = translate(schema.Description)
+	if schema.Properties != nil {
+		for pair := schema.Properties.Oldest(); pair != nil; pair = pair.Next() {
+			translateSchema(pair.Value)
+		}
+	}
+	translateSchema(schema.Items)
+}
@@ -0,0 +1,347 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/invopop/jsonschema"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"gopkg.in/yaml.v3"
+)
+
+// Named connections let one server work on several databases. DB_CONNECTIONS_FILE lists
+// them, configure_datasource adds more with connection, and the tools reading a database
+// take a connection argument naming the one they run on; calls without it run on the
+// default connection of DB_CONNECTION_STRING or configure_datasource. A named connection is
+// served by a view of the server with its own connection pool and query builder, and its own
+// tools registered against them, sharing the settings, caches and watchdog of the server.
+
+// noConnectionTools are the tools that read no database, or take the connection they
+// configure as an argument of their own
+var noConnectionTools = map[string]bool{
+	"configure_datasource":  true,
+	"test_connection":       true,
+	"list_database_drivers": true,
+	"list_connections":      true,
+}
+
+// hasConnectionArgument reports whether a tool accepts the connection argument
+func hasConnectionArgument(name string) bool {
+	return !noConnectionTools[name]
+}
+
+// addConnectionProperty adds the connection argument to the input schema of a tool
+func addConnectionProperty(schema *jsonschema.Schema) {
+	if schema.Properties == nil {
+		schema.Properties = jsonschema.NewProperties()
+	}
+	schema.Properties.Set("connection", &jsonschema.Schema{
+		Type:        "string",
+		Description: "Name of the connection to run the call on, as listed by list_connections (default: the default connection)",
+	})
+}
+
+// namedConnection is a database the server reaches by name, besides the default connection
+type namedConnection struct {
+	view   *connectionView
+	info   ConnectionInfo
+	source string // DB_CONNECTIONS_FILE or configure_datasource
+}
+
+// namedConnections holds the named connections of a server, by name in lower case
+type namedConnections struct {
+	mu          sync.RWMutex
+	connections map[string]*namedConnection
+}
+
+func newNamedConnections() *namedConnections {
+	return &namedConnections{connections: make(map[string]*namedConnection)}
+}
+
+// acquire returns the view of the server serving a named connection, and the function
+// ending the call on it
+func (c *namedConnections) acquire(name string) (*DbMCPServer, func(), bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	connection, ok := c.connections[strings.ToLower(name)]
+	if !ok {
+		return nil, nil, false
+	}
+	connection.view.acquire()
+	return connection.view.server, connection.view.release, true
+}
+
+// set adds a named connection, or replaces the one of the same name and retires its view
+func (c *namedConnections) set(connection *namedConnection) {
+	c.mu.Lock()
+	previous := c.connections[strings.ToLower(connection.info.Name)]
+	c.connections[strings.ToLower(connection.info.Name)] = connection
+	c.mu.Unlock()
+
+	if previous != nil {
+		previous.view.retire()
+	}
+}
+
+// remove drops the named connection a view serves and retires the view, unless the
+// connection was replaced meanwhile
+func (c *namedConnections) remove(view *DbMCPServer) {
+	c.mu.Lock()
+	connection := c.connections[strings.ToLower(view.connection)]
+	if connection == nil || connection.view.server != view {
+		c.mu.Unlock()
+		return
+	}
+	delete(c.connections, strings.ToLower(view.connection))
+	c.mu.Unlock()
+
+	connection.view.retire()
+}
+
+// list returns the named connections sorted by name
+func (c *namedConnections) list() []*namedConnection {
+	c.mu.RLock()
+	connections := make([]*namedConnection, 0, len(c.connections))
+	for _, connection := range c.connections {
+		connections = append(connections, connection)
+	}
+	c.mu.RUnlock()
+
+	sort.Slice(connections, func(i, j int) bool {
+		return strings.ToLower(connections[i].info.Name) < strings.ToLower(connections[j].info.Name)
+	})
+	return connections
+}
+
+// closeAll retires the views of the named connections, closing their pools after their
+// last calls
+func (c *namedConnections) closeAll() {
+	for _, connection := range c.list() {
+		connection.view.retire()
+	}
+}
+
+// isValidConnectionName reports whether a name can name a connection: letters, digits,
+// underscores and hyphens, other than the name of the default connection
+func isValidConnectionName(name string) bool {
+	return len(name) <= MaxConnectionNameLength && reConnectionName.MatchString(name) && !strings.EqualFold(name, DefaultConnectionName)
+}
+
+// addConnection serves a named connection with a view of the server on its pool. A
+// connection of the same name is replaced.
+func (s *DbMCPServer) addConnection(db *sql.DB, info ConnectionInfo, source string) {
+	view := s.newView(db, normalizeDriver(info.Driver), info.Name)
+	s.connections.set(&namedConnection{view: &connectionView{server: view}, info: info, source: source})
+}
+
+// connectionsMiddleware runs the calls reading a database on the view of the server serving
+// their connection argument, or the default connection without it, and holds the view until
+// the call returns. The hidden objects of their responses are dropped by the view, which
+// reads the same database as the call.
+func (s *DbMCPServer) connectionsMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if !hasConnectionArgument(request.Params.Name) {
+			return next(ctx, request)
+		}
+		var name string
+		if args, ok := getArgs(request.Params.Arguments); ok && args["connection"] != nil {
+			if name, ok = args["connection"].(string); !ok {
+				return toolErrorResult(fmt.Errorf("%w: "+translate("%s must be of type %s"), ErrInvalidArguments, "connection", "string")), nil
+			}
+		}
+
+		view, release, err := s.acquireConnection(name)
+		if err != nil {
+			return toolErrorResult(err), nil
+		}
+		defer release()
+		tool := view.server.GetTool(request.Params.Name)
+		if tool == nil {
+			return next(ctx, request)
+		}
+		return view.objectsMiddleware(tool.Handler)(ctx, request)
+	}
+}
+
+// connectionConfig is a named connection of DB_CONNECTIONS_FILE. ${VAR} references in the
+// connection string are read from the environment, so passwords stay out of the file.
+type connectionConfig struct {
+	Name             string     `json:"name" yaml:"name"`
+	Driver           string     `json:"driver" yaml:"driver"`
+	ConnectionString string     `json:"connection_string" yaml:"connection_string"`
+	SearchPath       string     `json:"search_path,omitempty" yaml:"search_path"`
+	TLS              *tlsConfig `json:"tls,omitempty" yaml:"tls"`
+}
+
+// connectionsFile is the content of DB_CONNECTIONS_FILE
+type connectionsFile struct {
+	Connections []connectionConfig `json:"connections" yaml:"connections"`
+}
+
+// openNamedConnections opens the connections listed in DB_CONNECTIONS_FILE. Their pools
+// connect on the first call, so an unreachable database does not keep the server from
+// starting; list_connections reports it.
+func (s *DbMCPServer) openNamedConnections() {
+	path := os.Getenv("DB_CONNECTIONS_FILE")
+	if path == "" {
+		return
+	}
+	configs, err := readConnectionsFile(path)
+	if err != nil {
+		log.Printf("Warning: Ignoring DB_CONNECTIONS_FILE: %v", err)
+		return
+	}
+
+	seen := make(map[string]bool)
+	for _, config := range configs {
+		if seen[strings.ToLower(config.Name)] {
+			log.Printf("Warning: Ignoring connection %q of DB_CONNECTIONS_FILE: %v", config.Name, ErrDuplicateConnection)
+			continue
+		}
+		seen[strings.ToLower(config.Name)] = true
+		if err = s.openConnectionConfig(config); err != nil {
+			log.Printf("Warning: Ignoring connection %q of DB_CONNECTIONS_FILE: %v", config.Name, err)
+		}
+	}
+}
+
+// openConnectionConfig opens a connection of DB_CONNECTIONS_FILE and adds it to the server
+func (s *DbMCPServer) openConnectionConfig(config connectionConfig) error {
+	if !isValidConnectionName(config.Name) {
+		return fmt.Errorf("%w: %q", ErrInvalidConnectionName, config.Name)
+	}
+	driver := normalizeDriver(config.Driver)
+	if driver == "" {
+		return fmt.Errorf("%w: '%s'", ErrInvalidDriver, config.Driver)
+	}
+	connString := os.ExpandEnv(config.ConnectionString)
+	if connString == "" {
+		return ErrConnectionStringRequired
+	}
+	connString, auth, err := resolveConnString(driver, connString, sqlServerAuthArgs{})
+	if err != nil {
+		return err
+	}
+	connString, tlsOptions, err := resolveTLS(driver, connString, config.TLS.args())
+	if err != nil {
+		return err
+	}
+	searchPath, err := parseSearchPath(config.SearchPath)
+	if err != nil {
+		return err
+	}
+
+	db, err := openDatabase(driver, connString, searchPath)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrConnectionFailed, err)
+	}
+	s.addConnection(db, ConnectionInfo{
+		ID:               config.Name,
+		Driver:           config.Driver,
+		ConnectionString: connString,
+		Name:             config.Name,
+		SearchPath:       searchPath,
+		Auth:             auth.Mode,
+		TLS:              tlsOptions.enabled(),
+		ConnectedAt:      time.Now(),
+	}, "DB_CONNECTIONS_FILE")
+	return nil
+}
+
+// readConnectionsFile reads the named connections of a file, as YAML when it ends in .yaml
+// or .yml and as JSON otherwise. Unknown fields make the file invalid.
+func readConnectionsFile(path string) ([]connectionConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file connectionsFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&file)
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return file.Connections, nil
+}
+
+// pingStatus returns the status of a connection pool: connected, disconnected, or the
+// status of the error reaching the database
+func pingStatus(ctx context.Context, db *sql.DB) string {
+	if db == nil {
+		return "disconnected"
+	}
+	pingCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	if err := db.PingContext(pingCtx); err != nil {
+		return connectionStatus(err)
+	}
+	return "connected"
+}
+
+// describe returns a named connection as list_connections and get_current_datasource
+// report it
+func (c *namedConnection) describe(ctx context.Context) map[string]interface{} {
+	connection := map[string]interface{}{
+		"name":         c.info.Name,
+		"driver":       c.info.Driver,
+		"status":       pingStatus(ctx, c.view.server.db),
+		"source":       c.source,
+		"connected_at": c.info.ConnectedAt.Format("2006-01-02 15:04:05"),
+	}
+	if len(c.info.SearchPath) > 0 {
+		connection["search_path"] = c.info.SearchPath
+	}
+	if c.info.TLS {
+		connection["tls"] = true
+	}
+	return connection
+}
+
+// Tool: List Connections
+func (s *DbMCPServer) toolListConnections() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("list_connections", "Lists the database connections of this server: the default one and the named connections of DB_CONNECTIONS_FILE and configure_datasource, with their driver, status and search path. Pass the name as the connection argument of any tool to run it on that database", s.handleListConnections)
+}
+
+func (s *DbMCPServer) handleListConnections(ctx context.Context, request mcp.CallToolRequest, _ noArgs) (*mcp.CallToolResult, error) {
+	view, release := s.defaultConn.acquire()
+	defer release()
+	defaultConnection := map[string]interface{}{
+		"name":       DefaultConnectionName,
+		"status":     pingStatus(ctx, view.db),
+		"is_default": true,
+	}
+	if view.queryBuilder != nil && view.db != nil {
+		defaultConnection["driver"] = string(view.queryBuilder.GetDriver())
+	}
+
+	connections := []map[string]interface{}{defaultConnection}
+	for _, connection := range s.connections.list() {
+		connections = append(connections, connection.describe(ctx))
+	}
+
+	response := map[string]interface{}{
+		"connections": connections,
+		"count":       len(connections),
+	}
+	return jsonToolResult(response), nil
+}
@@ -0,0 +1,626 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// objectFilter limits the schemas and tables the server exposes, so it can be pointed at a
+// production database while agents only see approved objects. Lists come from
+// DB_ALLOWED_SCHEMAS, DB_DENIED_SCHEMAS, DB_ALLOWED_TABLES and DB_DENIED_TABLES, as comma
+// separated names with * and ? wildcards; tables are table or schema.table. A denied object
+// is hidden even when allowed, and an allow list hides what it does not name. Queries
+// reading a hidden table are rejected by the validator, tool calls naming one fail and
+// metadata tools drop hidden objects from their responses.
+type objectFilter struct {
+	allowedSchemas []string
+	deniedSchemas  []string
+	allowedTables  []tablePattern
+	deniedTables   []tablePattern
+
+	// err is why the lists could not be loaded. Every tool call then fails, as a list meant
+	// to hide objects must not fall back to exposing them.
+	err error
+}
+
+// tablePattern is an entry of DB_ALLOWED_TABLES or DB_DENIED_TABLES. An empty schema
+// matches tables of any schema.
+type tablePattern struct {
+	schema string
+	table  string
+}
+
+// dataTools are the tools whose responses are rows of data rather than database objects, so
+// their responses are not filtered. Their table arguments and queries are still checked.
+var dataTools = map[string]bool{
+	"execute_query":       true,
+	"export_query":        true,
+	"explain_query":       true,
+	"run_saved_query":     true,
+	"fetch_more":          true,
+	"fetch_full":          true,
+	"list_table_rows":     true,
+	"sample_table_data":   true,
+	"find_value_in_table": true,
+	"aggregate_table":     true,
+	"get_distinct_values": true,
+	"profile_column":      true,
+	"execute_procedure":   true,
+	"execute_insert":      true,
+	"execute_update":      true,
+	"execute_delete":      true,
+	"get_query_history":   true,
+}
+
+// explainOptions are the words that follow EXPLAIN, DESCRIBE or DESC when they explain a
+// query rather than describe a table
+var explainOptions = map[string]bool{
+	"ANALYZE":    true,
+	"ANALYSE":    true,
+	"VERBOSE":    true,
+	"FORMAT":     true,
+	"EXTENDED":   true,
+	"PARTITIONS": true,
+	"QUERY":      true,
+	"PLAN":       true,
+	"FOR":        true,
+}
+
+// clauseEnds are the words ending the FROM clause of a SELECT, after which commas do not
+// separate tables
+var clauseEnds = map[string]bool{
+	"WHERE":     true,
+	"GROUP":     true,
+	"HAVING":    true,
+	"ORDER":     true,
+	"LIMIT":     true,
+	"OFFSET":    true,
+	"FETCH":     true,
+	"FOR":       true,
+	"UNION":     true,
+	"INTERSECT": true,
+	"EXCEPT":    true,
+	"MINUS":     true,
+	"WINDOW":    true,
+	"QUALIFY":   true,
+}
+
+// stringQueryFunctions are the functions that run a query, or read a table or schema,
+// named in a string argument: the XML export functions of Postgres, Oracle's DBMS_XMLGEN,
+// ts_stat, dblink and the SQL Server functions reaching other servers. What their strings
+// read cannot be checked, so they are rejected while the filter hides anything.
+var stringQueryFunctions = map[string]bool{
+	"QUERY_TO_XML":                  true,
+	"QUERY_TO_XMLSCHEMA":            true,
+	"QUERY_TO_XML_AND_XMLSCHEMA":    true,
+	"TABLE_TO_XML":                  true,
+	"TABLE_TO_XMLSCHEMA":            true,
+	"TABLE_TO_XML_AND_XMLSCHEMA":    true,
+	"CURSOR_TO_XML":                 true,
+	"CURSOR_TO_XMLSCHEMA":           true,
+	"SCHEMA_TO_XML":                 true,
+	"SCHEMA_TO_XMLSCHEMA":           true,
+	"SCHEMA_TO_XML_AND_XMLSCHEMA":   true,
+	"DATABASE_TO_XML":               true,
+	"DATABASE_TO_XMLSCHEMA":         true,
+	"DATABASE_TO_XML_AND_XMLSCHEMA": true,
+	"TS_STAT":                       true,
+	"DBLINK":                        true,
+	"DBLINK_EXEC":                   true,
+	"DBLINK_SEND_QUERY":             true,
+	"GETXML":                        true,
+	"GETXMLTYPE":                    true,
+	"OPENQUERY":                     true,
+	"OPENROWSET":                    true,
+	"OPENDATASOURCE":                true,
+}
+
+// newObjectFilter returns the object filter configured from the environment
+func newObjectFilter() *objectFilter {
+	f := &objectFilter{}
+	var err error
+	if f.allowedSchemas, err = envSchemaPatterns("DB_ALLOWED_SCHEMAS"); err == nil {
+		if f.deniedSchemas, err = envSchemaPatterns("DB_DENIED_SCHEMAS"); err == nil {
+			if f.allowedTables, err = envTablePatterns("DB_ALLOWED_TABLES"); err == nil {
+				f.deniedTables, err = envTablePatterns("DB_DENIED_TABLES")
+			}
+		}
+	}
+	if err != nil {
+		log.Printf("Warning: Every tool call is rejected, %v", err)
+		return &objectFilter{err: fmt.Errorf("%w: %w", ErrInvalidObjectFilter, err)}
+	}
+	return f
+}
+
+// envSchemaPatterns reads a comma separated list of schema names from an environment variable
+func envSchemaPatterns(name string) ([]string, error) {
+	var patterns []string
+	for _, entry := range strings.Split(os.Getenv(name), ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if _, err := path.Match(entry, ""); err != nil || strings.Contains(entry, ".") {
+			return nil, fmt.Errorf("%s: %s", name, entry)
+		}
+		patterns = append(patterns, entry)
+	}
+	return patterns, nil
+}
+
+// envTablePatterns reads a comma separated list of table or schema.table names from an
+// environment variable
+func envTablePatterns(name string) ([]tablePattern, error) {
+	var patterns []tablePattern
+	for _, entry := range strings.Split(os.Getenv(name), ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		var pattern tablePattern
+		if dot := strings.LastIndex(entry, "."); dot >= 0 {
+			pattern.schema, pattern.table = entry[:dot], entry[dot+1:]
+		} else {
+			pattern.table = entry
+		}
+		if pattern.table == "" || strings.Contains(pattern.schema, ".") || (strings.Contains(entry, ".") && pattern.schema == "") {
+			return nil, fmt.Errorf("%s: %s", name, entry)
+		}
+		if _, err := path.Match(pattern.schema, ""); err != nil {
+			return nil, fmt.Errorf("%s: %s", name, entry)
+		}
+		if _, err := path.Match(pattern.table, ""); err != nil {
+			return nil, fmt.Errorf("%s: %s", name, entry)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// active reports whether the filter hides anything
+func (f *objectFilter) active() bool {
+	return f != nil && (f.err != nil || len(f.allowedSchemas)+len(f.deniedSchemas)+len(f.allowedTables)+len(f.deniedTables) > 0)
+}
+
+// needsSchema reports whether a table must be named with its schema to be checked: schemas
+// are restricted, or allowed tables are only allowed in some schemas
+func (f *objectFilter) needsSchema() bool {
+	if len(f.allowedSchemas) > 0 || len(f.deniedSchemas) > 0 {
+		return true
+	}
+	for _, pattern := range f.allowedTables {
+		if pattern.schema != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// matchName reports whether a name matches a pattern, in any case
+func matchName(pattern, name string) bool {
+	matched, _ := path.Match(pattern, strings.ToLower(name))
+	return matched
+}
+
+// schemaVisible reports whether a schema is exposed
+func (f *objectFilter) schemaVisible(schema string) bool {
+	for _, pattern := range f.deniedSchemas {
+		if matchName(pattern, schema) {
+			return false
+		}
+	}
+	if len(f.allowedSchemas) == 0 {
+		return true
+	}
+	for _, pattern := range f.allowedSchemas {
+		if matchName(pattern, schema) {
+			return true
+		}
+	}
+	return false
+}
+
+// tableVisible reports whether a table is exposed. An empty schema is unknown: the schema
+// lists are not applied and the table lists match by table name only, as for the items of a
+// response that leave out the schema of the table the call named.
+func (f *objectFilter) tableVisible(schema, table string) bool {
+	if schema != "" && !f.schemaVisible(schema) {
+		return false
+	}
+	for _, pattern := range f.deniedTables {
+		if (pattern.schema == "" || schema == "" || matchName(pattern.schema, schema)) && matchName(pattern.table, table) {
+			return false
+		}
+	}
+	if len(f.allowedTables) == 0 {
+		return true
+	}
+	for _, pattern := range f.allowedTables {
+		if (pattern.schema == "" || schema == "" || matchName(pattern.schema, schema)) && matchName(pattern.table, table) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTable returns why a table read by a query or named by a tool call is not exposed, or
+// nil. A table without a schema is rejected when the schema decides whether it is exposed.
+func (f *objectFilter) checkTable(schema, table string) error {
+	name := table
+	if schema != "" {
+		name = schema + "." + table
+	}
+	if schema == "" && f.needsSchema() {
+		return fmt.Errorf("%w: %s", ErrTableNotQualified, name)
+	}
+	if !f.tableVisible(schema, table) {
+		return fmt.Errorf("%w: %s", ErrTableNotAllowed, name)
+	}
+	return nil
+}
+
+// checkQuery returns why a query reads a table or calls a function of a schema that is not
+// exposed, or nil. SHOW statements name objects in too many ways to be checked, PRAGMA
+// statements without a table list objects of every schema and the functions reading what
+// a string names read any table, so they are rejected. Tables are read wherever they are
+// named, in subqueries of any expression included.
+func (f *objectFilter) checkQuery(tokens []sqlToken) error {
+	if !f.active() {
+		return nil
+	}
+	if f.err != nil {
+		return f.err
+	}
+	if len(tokens) > 0 && tokens[0].is("SHOW") {
+		return ErrShowNotAllowed
+	}
+	if len(tokens) > 0 && tokens[0].is("PRAGMA") {
+		return f.checkPragma(tokens)
+	}
+	for i, token := range tokens {
+		if token.kind == sqlWord && stringQueryFunctions[token.upper()] && i+1 < len(tokens) && tokens[i+1].text == "(" {
+			return fmt.Errorf("%w: %s", ErrQueryFunctionNotAllowed, token.upper())
+		}
+	}
+	for _, name := range queryTableNames(tokens) {
+		if err := f.checkTable(name[0], name[1]); err != nil {
+			return err
+		}
+	}
+	for i := 2; i+1 < len(tokens); i++ {
+		if tokens[i+1].text != "(" || tokens[i-1].text != "." || (tokens[i-2].kind != sqlWord && tokens[i-2].kind != sqlQuotedIdentifier) {
+			continue
+		}
+		if schema := identifierName(tokens[i-2]); !f.schemaVisible(schema) {
+			return fmt.Errorf("%w: %s", ErrSchemaNotAllowed, schema)
+		}
+	}
+	return nil
+}
+
+// checkPragma returns why a SQLite PRAGMA statement reads a table that is not exposed, or
+// nil: PRAGMA [schema.]name(table) for the pragmas taking a table
+func (f *objectFilter) checkPragma(tokens []sqlToken) error {
+	schema, i := "", 1
+	if i+1 < len(tokens) && tokens[i+1].text == "." {
+		schema, i = identifierName(tokens[i]), i+2
+	}
+	if readPragmas[firstWord(tokens, i)] != pragmaTable || i+2 >= len(tokens) {
+		return ErrShowNotAllowed
+	}
+	table := tokens[i+2]
+	if table.kind == sqlString {
+		table.kind = sqlQuotedIdentifier
+	}
+	return f.checkTable(schema, identifierName(table))
+}
+
+// queryTableNames returns the schema and name of the tables a query reads: those after FROM
+// in a SELECT, after JOIN or APPLY and in the comma separated list of a FROM clause, and the
+// table of DESCRIBE, EXPLAIN table (MySQL) and TABLE. Common table expressions, derived
+// tables and table functions are not tables, and names of three or more parts keep their
+// last two.
+func queryTableNames(tokens []sqlToken) [][2]string {
+	ctes := commonTableExpressions(tokens)
+
+	var names [][2]string
+	depth := 0
+	selects := map[int]bool{} // depths with a SELECT, whose FROM lists tables
+	from := map[int]bool{}    // depths in a FROM clause, whose commas separate tables
+	expect := len(tokens) > 1 && (tokens[0].is("DESCRIBE") || tokens[0].is("DESC") || tokens[0].is("EXPLAIN")) &&
+		(tokens[1].kind == sqlWord || tokens[1].kind == sqlQuotedIdentifier) && !explainOptions[tokens[1].upper()]
+	start := 0
+	if expect {
+		start = 1 // the table follows the keyword
+	}
+	for i := start; i < len(tokens); i++ {
+		token := tokens[i]
+		switch {
+		case token.kind == sqlPunctuation && token.text == "(":
+			depth++
+			// FROM (a JOIN b) groups joined tables, whose first one follows the parenthesis
+			from[depth] = expect
+			selects[depth] = false
+			continue
+		case token.kind == sqlPunctuation && token.text == ")":
+			from[depth], selects[depth] = false, false
+			depth--
+			expect = false
+			continue
+		case token.is("SELECT") || token.is("WITH"):
+			selects[depth], from[depth] = true, false
+			expect = false
+			continue
+		case token.is("FROM"):
+			if selects[depth] {
+				from[depth] = true
+				expect = true
+			}
+			continue
+		case token.is("JOIN") || token.is("APPLY"):
+			from[depth] = true
+			expect = true
+			continue
+		case token.is("TABLE"):
+			expect = true
+			continue
+		case token.kind == sqlPunctuation && token.text == ",":
+			expect = from[depth]
+			continue
+		case token.kind == sqlWord && clauseEnds[token.upper()]:
+			from[depth] = false
+			expect = false
+			continue
+		}
+
+		if !expect {
+			continue
+		}
+		expect = false
+		if token.is("LATERAL") || token.is("ONLY") {
+			expect = true
+			continue
+		}
+		if token.kind != sqlWord && token.kind != sqlQuotedIdentifier {
+			continue
+		}
+
+		// The parts of a qualified name; db..table leaves the schema empty
+		parts := []string{identifierName(token)}
+		for i+2 < len(tokens) && tokens[i+1].kind == sqlPunctuation && tokens[i+1].text == "." {
+			next := tokens[i+2]
+			if next.kind == sqlPunctuation && next.text == "." {
+				parts = append(parts, "")
+				i++
+				continue
+			}
+			if next.kind != sqlWord && next.kind != sqlQuotedIdentifier {
+				break
+			}
+			parts = append(parts, identifierName(next))
+			i += 2
+		}
+		if i+1 < len(tokens) && tokens[i+1].kind == sqlPunctuation && tokens[i+1].text == "(" {
+			continue // a table function
+		}
+
+		var name [2]string
+		name[1] = parts[len(parts)-1]
+		if len(parts) > 1 {
+			name[0] = parts[len(parts)-2]
+		}
+		if name[0] == "" && (ctes[strings.ToUpper(name[1])] || strings.EqualFold(name[1], "DUAL") || strings.HasPrefix(name[1], "@")) {
+			continue // a common table expression, Oracle's DUAL or a SQL Server table variable
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// commonTableExpressions returns the names, in upper case, of the common table expressions
+// of a query
+func commonTableExpressions(tokens []sqlToken) map[string]bool {
+	ctes := map[string]bool{}
+	for _, statement := range withStatements(tokens) {
+		if statement.name != "" {
+			ctes[strings.ToUpper(statement.name)] = true
+		}
+	}
+	return ctes
+}
+
+// identifierName returns a word or quoted identifier without its quotes
+func identifierName(token sqlToken) string {
+	if token.kind != sqlQuotedIdentifier || len(token.text) < 2 {
+		return token.text
+	}
+	quote := token.text[len(token.text)-1:]
+	inner := token.text[1 : len(token.text)-1]
+	return strings.ReplaceAll(inner, quote+quote, quote)
+}
+
+// objectsMiddleware rejects the tool calls naming a schema or table the server does not
+// expose and drops hidden objects from the responses of metadata tools
+func (s *DbMCPServer) objectsMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		f := s.objects
+		if !f.active() {
+			return next(ctx, request)
+		}
+		if f.err != nil {
+			return toolErrorResult(f.err), nil
+		}
+		if err := s.checkToolObjects(request.GetArguments()); err != nil {
+			return toolErrorResult(err), nil
+		}
+
+		result, err := next(ctx, request)
+		if err != nil || dataTools[request.Params.Name] {
+			return result, err
+		}
+		return f.filterResult(result, request.Params.Name == "list_databases" && s.queryBuilder != nil && s.queryBuilder.IsMySQL()), nil
+	}
+}
+
+// checkToolObjects returns why the schema or tables named by the arguments of a tool call are
+// not exposed, or nil. MySQL databases are schemas, so there the database is checked too.
+func (s *DbMCPServer) checkToolObjects(args map[string]interface{}) error {
+	f := s.objects
+	schema, _ := args["schema"].(string)
+	if database, _ := args["database"].(string); database != "" && s.queryBuilder != nil && s.queryBuilder.IsMySQL() {
+		schema = database
+	}
+	schema, _ = unquoteIdentifier(schema)
+	if schema != "" && !f.schemaVisible(schema) {
+		return fmt.Errorf("%w: %s", ErrSchemaNotAllowed, schema)
+	}
+
+	names := argumentTables(args)
+	if table, ok := args["referenced_table"].(string); ok && table != "" {
+		names = append(names, argumentTable(schema, table))
+	}
+	for _, name := range names {
+		if name[0] == "" {
+			name[0] = schema
+		}
+		if err := f.checkTable(name[0], name[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filterResult drops the hidden objects from the JSON object of a result: list items whose
+// schema, or schema and table, is hidden and the hidden names of schema lists. With
+// databaseSchemas, the items of a databases list are schemas too (MySQL).
+func (f *objectFilter) filterResult(result *mcp.CallToolResult, databaseSchemas bool) *mcp.CallToolResult {
+	if result == nil || result.IsError || len(result.Content) != 1 {
+		return result
+	}
+	content, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		return result
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(content.Text), &fields); err != nil {
+		return result
+	}
+
+	filtered := false
+	response := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		var changed bool
+		if response[key], changed = f.filterValue(value, key, databaseSchemas); changed {
+			filtered = true
+		}
+	}
+	if !filtered {
+		return result
+	}
+	return jsonToolResult(response)
+}
+
+// filterValue drops the hidden objects from a JSON value and its nested lists and objects,
+// and reports whether it dropped any. Values without hidden objects are kept as they are.
+func (f *objectFilter) filterValue(value json.RawMessage, key string, databaseSchemas bool) (json.RawMessage, bool) {
+	trimmed := bytes.TrimSpace(value)
+	if len(trimmed) == 0 {
+		return value, false
+	}
+
+	switch trimmed[0] {
+	case '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return value, false
+		}
+		kept := make([]json.RawMessage, 0, len(items))
+		changed := false
+		for _, item := range items {
+			if f.hiddenItem(item, key, databaseSchemas) {
+				changed = true
+				continue
+			}
+			item, itemChanged := f.filterValue(item, key, databaseSchemas)
+			changed = changed || itemChanged
+			kept = append(kept, item)
+		}
+		if !changed {
+			return value, false
+		}
+		filtered, err := json.Marshal(kept)
+		if err != nil {
+			return value, false
+		}
+		return filtered, true
+	case '{':
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(trimmed, &fields); err != nil {
+			return value, false
+		}
+		changed := false
+		for name, field := range fields {
+			var fieldChanged bool
+			if fields[name], fieldChanged = f.filterValue(field, name, databaseSchemas); fieldChanged {
+				changed = true
+			}
+		}
+		if !changed {
+			return value, false
+		}
+		filtered, err := json.Marshal(fields)
+		if err != nil {
+			return value, false
+		}
+		return filtered, true
+	}
+	return value, false
+}
+
+// hiddenItem reports whether a list item is a hidden object: a name in a list of schemas, or
+// an object whose schema and table (or name), or whose referenced table, is hidden
+func (f *objectFilter) hiddenItem(item json.RawMessage, key string, databaseSchemas bool) bool {
+	var name string
+	if json.Unmarshal(item, &name) == nil {
+		return key == "schemas" && !f.schemaVisible(name)
+	}
+
+	var object map[string]interface{}
+	if json.Unmarshal(item, &object) != nil {
+		return false
+	}
+	text := func(field string) string {
+		value, _ := object[field].(string)
+		return value
+	}
+
+	if databaseSchemas && key == "databases" {
+		return text("name") != "" && !f.schemaVisible(text("name"))
+	}
+
+	schema, table := text("schema"), text("table")
+	if table == "" && schema != "" {
+		table = text("name")
+	}
+	switch {
+	case table != "" && !f.tableVisible(schema, table):
+		return true
+	case table == "" && schema != "" && !f.schemaVisible(schema):
+		return true
+	}
+	if referenced := text("referenced_table"); referenced != "" && !f.tableVisible(text("referenced_schema"), referenced) {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package mcp
+
+import (
+	"errors"
+	"testing"
+)
+
+// newTestObjectFilter returns the object filter of the lists, as read from the environment
+func newTestObjectFilter(t *testing.T, allowedSchemas, deniedSchemas, allowedTables, deniedTables string) *objectFilter {
+	t.Helper()
+	t.Setenv("DB_ALLOWED_SCHEMAS", allowedSchemas)
+	t.Setenv("DB_DENIED_SCHEMAS", deniedSchemas)
+	t.Setenv("DB_ALLOWED_TABLES", allowedTables)
+	t.Setenv("DB_DENIED_TABLES", deniedTables)
+	return newObjectFilter()
+}
+
+func TestNewObjectFilterInvalid(t *testing.T) {
+	tests := []struct {
+		name                                                       string
+		allowedSchemas, deniedSchemas, allowedTables, deniedTables string
+	}{
+		{"schema with a dot", "a.b", "", "", ""},
+		{"bad schema pattern", "", "[", "", ""},
+		{"table of three parts", "", "", "a.b.c", ""},
+		{"table without a name", "", "", "", "public."},
+		{"bad table pattern", "", "", "", "public.["},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTestObjectFilter(t, tt.allowedSchemas, tt.deniedSchemas, tt.allowedTables, tt.deniedTables)
+			if !errors.Is(f.checkQuery(lexSQL("SELECT 1", sqlLexRules{})), ErrInvalidObjectFilter) {
+				t.Errorf("newObjectFilter() = %+v, want an invalid filter rejecting every query", f)
+			}
+		})
+	}
+}
+
+func TestObjectFilterTableVisible(t *testing.T) {
+	f := newTestObjectFilter(t, "public,sales*", "sales_archive", "", "*.secrets,public.audit_?")
+	tests := []struct {
+		schema, table string
+		want          bool
+	}{
+		{"public", "users", true},
+		{"PUBLIC", "Users", true},
+		{"sales_eu", "orders", true},
+		{"sales_archive", "orders", false},
+		{"hidden", "users", false},
+		{"public", "secrets", false},
+		{"sales_eu", "secrets", false},
+		{"public", "audit_1", false},
+		{"public", "audit_10", true},
+		{"", "secrets", false},
+	}
+	for _, tt := range tests {
+		if got := f.tableVisible(tt.schema, tt.table); got != tt.want {
+			t.Errorf("tableVisible(%q, %q) = %v, want %v", tt.schema, tt.table, got, tt.want)
+		}
+	}
+}
+
+func TestObjectFilterCheckQuery(t *testing.T) {
+	f := newTestObjectFilter(t, "", "hidden", "", "public.secrets")
+	tests := []struct {
+		name   string
+		driver DriverType
+		query  string
+		want   error
+	}{
+		{"visible table", DriverPostgresSQL, "SELECT * FROM public.users", nil},
+		{"join of visible tables", "", "SELECT * FROM public.users u JOIN public.orders o ON o.user_id = u.id", nil},
+		{"common table expression", "", "WITH t AS (SELECT 1 AS x) SELECT x FROM t", nil},
+		{"extract from a column", DriverPostgresSQL, "SELECT EXTRACT(YEAR FROM created) FROM public.users", nil},
+		{"unqualified table", "", "SELECT * FROM users", ErrTableNotQualified},
+		{"hidden schema", "", "SELECT * FROM hidden.t", ErrTableNotAllowed},
+		{"hidden table", "", "SELECT * FROM public.secrets", ErrTableNotAllowed},
+		{"quoted hidden table", DriverPostgresSQL, `SELECT * FROM "public"."secrets"`, ErrTableNotAllowed},
+		{"bracket quoted hidden table", DriverSQLServer, "SELECT * FROM [hidden].[t]", ErrTableNotAllowed},
+		{"comma list", "", "SELECT * FROM public.users, hidden.t", ErrTableNotAllowed},
+		{"join", "", "SELECT * FROM public.users u JOIN hidden.t h ON h.id = u.id", ErrTableNotAllowed},
+		{"subquery in where", "", "SELECT * FROM public.users WHERE id IN (SELECT id FROM hidden.t)", ErrTableNotAllowed},
+		{"subquery in select list", "", "SELECT (SELECT MAX(id) FROM hidden.t) AS m", ErrTableNotAllowed},
+		{"derived table", "", "SELECT * FROM (SELECT * FROM hidden.t) d", ErrTableNotAllowed},
+		{"subquery in an array", DriverPostgresSQL, "SELECT ARRAY[(SELECT id FROM hidden.t)]", ErrTableNotAllowed},
+		{"subquery in an array, unknown driver", "", "SELECT ARRAY[(SELECT id FROM hidden.t)]", ErrTableNotAllowed},
+		{"function of a hidden schema", "", "SELECT hidden.f(1)", ErrSchemaNotAllowed},
+		{"query_to_xml", DriverPostgresSQL, "SELECT query_to_xml('SELECT * FROM hidden.t', true, false, '')", ErrQueryFunctionNotAllowed},
+		{"query_to_xml in an array", DriverPostgresSQL, "SELECT ARRAY[query_to_xml('SELECT * FROM hidden.t', true, false, '')]", ErrQueryFunctionNotAllowed},
+		{"table_to_xml", DriverPostgresSQL, "SELECT table_to_xml('hidden.t', true, false, '')", ErrQueryFunctionNotAllowed},
+		{"ts_stat", DriverPostgresSQL, "SELECT * FROM ts_stat('SELECT v FROM hidden.t')", ErrQueryFunctionNotAllowed},
+		{"dbms_xmlgen", DriverOracle, "SELECT dbms_xmlgen.getxml('SELECT * FROM hidden.t') FROM dual", ErrQueryFunctionNotAllowed},
+		{"openquery", DriverSQLServer, "SELECT * FROM OPENQUERY(srv, 'SELECT * FROM hidden.t')", ErrQueryFunctionNotAllowed},
+		{"show", DriverMySQL, "SHOW TABLES", ErrShowNotAllowed},
+		{"pragma of a hidden table", DriverSQLite, "PRAGMA hidden.table_info(t)", ErrTableNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newDriverSQLValidator(tt.query, tt.driver, defaultValidationPolicy(), f).Validate().Err()
+			switch {
+			case tt.want == nil && err != nil:
+				t.Errorf("Validate(%q) = %v, want it accepted", tt.query, err)
+			case tt.want != nil && !errors.Is(err, tt.want):
+				t.Errorf("Validate(%q) = %v, want %v", tt.query, err, tt.want)
+			}
+		})
+	}
+}
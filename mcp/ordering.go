@@ -0,0 +1,77 @@
+package mcp
+
+import "github.com/mark3labs/mcp-go/mcp"
+
+// Tool responses are deterministic: object keys are serialized in sorted order (maps) or
+// declaration order (structs), result rows keep the column order of the query, and every
+// metadata query ends with an ORDER BY over columns that identify a row. The same call on
+// an unchanged database returns the same bytes, so clients can diff and cache responses.
+
+// toolOrderBy is the default row order of the tools returning lists, published in the
+// _meta.order_by field of each tool
+var toolOrderBy = map[string]string{
+	"list_tables":                "schema, name",
+	"describe_table":             "column position",
+	"list_table_rows":            "primary key, or the first column without one (order_by overrides, the primary key breaks ties)",
+	"sample_table_data":          "random, different on each call; with method top the storage order of the table",
+	"profile_column":             "top values by frequency descending, then value",
+	"get_distinct_values":        "row count descending, then value",
+	"aggregate_table":            "order_by, then the group columns; the group columns without it",
+	"find_value_in_table":        "matched columns by position; rows by primary key, or the first column without one",
+	"get_table_schema_full":      "columns by position; indexes and foreign keys by name, then key position",
+	"get_table_ddl":              "columns by position; constraints by type, then name; indexes by name",
+	"get_object_comments":        "column position",
+	"generate_erd":               "tables by name, columns by position, relationships by table, then constraint",
+	"export_data_dictionary":     "schema, table; columns by position",
+	"get_table_stats":            "schema, table",
+	"estimate_row_counts":        "schema, table",
+	"list_statistics":            "schema, table, statistics name (table-level statistics first)",
+	"get_identity_status":        "used percent descending, then schema, table, column",
+	"list_partitions":            "schema, partition position",
+	"list_temporal_tables":       "source, schema, table",
+	"list_computed_columns":      "schema, table, column position",
+	"list_foreign_keys":          "schema, table, constraint, key position",
+	"list_key_constraints":       "schema, table, constraint type, constraint, key position",
+	"list_check_constraints":     "schema, table, constraint; defaults by schema, table, column position",
+	"list_procedures":            "schema, name",
+	"list_functions":             "schema, name",
+	"get_function_parameters":    "signature, position",
+	"list_views":                 "schema, name",
+	"list_materialized_views":    "schema, name",
+	"list_triggers":              "schema, table, name",
+	"list_synonyms":              "schema, name",
+	"list_types":                 "schema, name",
+	"get_object_dependencies":    "upstream then downstream, breadth-first; each level by schema, name, type",
+	"search_objects":             "schema, name, kind",
+	"search_definitions":         "schema, name, kind",
+	"find_column":                "schema, table, column position",
+	"list_object_permissions":    "grantee, scope, column, privilege",
+	"list_rls_policies":          "schema, table, policy",
+	"list_users_and_roles":       "roles first, then name",
+	"list_remote_servers":        "name; foreign tables by schema, name",
+	"list_scheduled_jobs":        "scheduler, name",
+	"get_change_tracking_status": "tables by schema, table, mechanism, name; replication slots by name",
+	"schema_overview":            "schemas by name; largest tables by total size descending, then schema, table",
+	"get_collation_info":         "column position",
+	"list_databases":             "name",
+	"list_extensions":            "name",
+	"table_access_report":        "reads descending, then schema, table",
+	"list_running_queries":       "oldest first",
+	"get_query_history":          "newest first",
+	"execute_query":              "the ORDER BY of the query; without one the database order is not guaranteed",
+	"fetch_more":                 "continues the order of the execute_query result",
+	"list_saved_queries":         "name",
+	"run_saved_query":            "the ORDER BY of the saved query; without one the database order is not guaranteed",
+	"export_query":               "the ORDER BY of the query; without one the database order is not guaranteed",
+	"explain_query":              "plan steps in the order the database returns them",
+	"execute_procedure":          "as returned by the procedure",
+}
+
+// toolOrderMeta returns the _meta of a tool with its default row order, or nil
+func toolOrderMeta(name string) *mcp.Meta {
+	orderBy, ok := toolOrderBy[name]
+	if !ok {
+		return nil
+	}
+	return mcp.NewMetaFromMap(map[string]any{"order_by": orderBy})
+}
@@ -0,0 +1,536 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Query results are exported as Parquet files written without a Parquet library: every
+// column is OPTIONAL, each row group holds one uncompressed PLAIN data page per column and
+// the metadata is encoded with the Thrift compact protocol, as the format specifies.
+
+// parquetType is the physical and logical type of an exported column
+type parquetType int
+
+const (
+	parquetString parquetType = iota
+	parquetBinary
+	parquetInt64
+	parquetDouble
+	parquetBoolean
+	parquetTimestamp
+)
+
+// Parquet format enums
+const (
+	parquetMagic = "PAR1"
+
+	parquetPhysicalBoolean   = 0
+	parquetPhysicalInt64     = 2
+	parquetPhysicalDouble    = 5
+	parquetPhysicalByteArray = 6
+
+	parquetConvertedUTF8            = 0
+	parquetConvertedTimestampMicros = 10
+
+	parquetRepetitionOptional = 1
+	parquetEncodingPlain      = 0
+	parquetEncodingRLE        = 3
+	parquetCodecUncompressed  = 0
+	parquetPageData           = 0
+)
+
+// parquetColumn is a column of an exported file
+type parquetColumn struct {
+	Name string
+	Type parquetType
+}
+
+// String returns the name of the column type as reported by export_query
+func (t parquetType) String() string {
+	switch t {
+	case parquetBinary:
+		return "binary"
+	case parquetInt64:
+		return "int64"
+	case parquetDouble:
+		return "double"
+	case parquetBoolean:
+		return "boolean"
+	case parquetTimestamp:
+		return "timestamp"
+	default:
+		return "string"
+	}
+}
+
+// parquetTypeOf maps a database column type to the type it is exported as. Numbers
+// without an exact float64 or int64 representation, such as DECIMAL, are exported as text.
+func parquetTypeOf(databaseType string) parquetType {
+	name := strings.ToUpper(databaseType)
+	switch {
+	case strings.Contains(name, "BOOL") || name == "BIT":
+		return parquetBoolean
+	case strings.Contains(name, "INT") && !strings.Contains(name, "POINT") && !strings.Contains(name, "INTERVAL"):
+		return parquetInt64
+	case strings.Contains(name, "FLOAT") || strings.Contains(name, "DOUBLE") || name == "REAL":
+		return parquetDouble
+	case strings.Contains(name, "TIMESTAMP") || strings.Contains(name, "DATETIME") || name == "DATE":
+		return parquetTimestamp
+	case strings.Contains(name, "BINARY") || strings.Contains(name, "BLOB") || name == "BYTEA" || name == "IMAGE" || name == "RAW":
+		return parquetBinary
+	default:
+		return parquetString
+	}
+}
+
+// parquetWriter writes rows to a Parquet file in row groups of rowGroupRows rows
+type parquetWriter struct {
+	w            io.Writer
+	offset       int64
+	columns      []parquetColumn
+	rowGroupRows int
+	pending      [][]interface{} // values of the current row group, by column
+	rowGroups    []parquetRowGroup
+	rows         int64
+}
+
+// parquetRowGroup is the metadata of a written row group
+type parquetRowGroup struct {
+	chunks []parquetChunk
+	rows   int64
+	size   int64
+}
+
+// parquetChunk is the metadata of a written column chunk
+type parquetChunk struct {
+	offset int64
+	size   int64
+	values int64
+}
+
+// newParquetWriter starts a Parquet file with the given columns
+func newParquetWriter(w io.Writer, columns []parquetColumn, rowGroupRows int) (*parquetWriter, error) {
+	pw := &parquetWriter{
+		w:            w,
+		columns:      columns,
+		rowGroupRows: rowGroupRows,
+		pending:      make([][]interface{}, len(columns)),
+	}
+	if err := pw.write([]byte(parquetMagic)); err != nil {
+		return nil, err
+	}
+	return pw, nil
+}
+
+// Append adds a row of raw driver values, converted to the column types
+func (pw *parquetWriter) Append(values []interface{}) error {
+	for i, column := range pw.columns {
+		value, err := parquetValue(column.Type, values[i])
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrExportValue, column.Name, err)
+		}
+		pw.pending[i] = append(pw.pending[i], value)
+	}
+	pw.rows++
+	if len(pw.pending[0]) >= pw.rowGroupRows {
+		return pw.flush()
+	}
+	return nil
+}
+
+// Rows returns the number of rows appended
+func (pw *parquetWriter) Rows() int64 {
+	return pw.rows
+}
+
+// Close writes the last row group and the file metadata
+func (pw *parquetWriter) Close() error {
+	if err := pw.flush(); err != nil {
+		return err
+	}
+
+	metadata := pw.fileMetadata()
+	footer := make([]byte, 4)
+	binary.LittleEndian.PutUint32(footer, uint32(len(metadata)))
+	if err := pw.write(metadata); err != nil {
+		return err
+	}
+	if err := pw.write(footer); err != nil {
+		return err
+	}
+	return pw.write([]byte(parquetMagic))
+}
+
+// write writes bytes and advances the file offset
+func (pw *parquetWriter) write(data []byte) error {
+	n, err := pw.w.Write(data)
+	pw.offset += int64(n)
+	return err
+}
+
+// flush writes the pending rows as a row group with a data page per column
+func (pw *parquetWriter) flush() error {
+	if len(pw.columns) == 0 || len(pw.pending[0]) == 0 {
+		return nil
+	}
+
+	group := parquetRowGroup{rows: int64(len(pw.pending[0]))}
+	for i, column := range pw.columns {
+		page := parquetPage(column.Type, pw.pending[i])
+		header := parquetPageHeader(len(pw.pending[i]), len(page))
+
+		chunk := parquetChunk{offset: pw.offset, values: group.rows}
+		if err := pw.write(header); err != nil {
+			return err
+		}
+		if err := pw.write(page); err != nil {
+			return err
+		}
+		chunk.size = pw.offset - chunk.offset
+		group.size += chunk.size
+		group.chunks = append(group.chunks, chunk)
+		pw.pending[i] = pw.pending[i][:0]
+	}
+	pw.rowGroups = append(pw.rowGroups, group)
+	return nil
+}
+
+// parquetValue converts a raw driver value to the Go type written for the column type:
+// int64, float64, bool, []byte, or int64 microseconds for timestamps. NULLs stay nil.
+func parquetValue(t parquetType, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch t {
+	case parquetInt64:
+		switch v := value.(type) {
+		case int64:
+			return v, nil
+		case int32:
+			return int64(v), nil
+		case int:
+			return int64(v), nil
+		case uint64:
+			if v > math.MaxInt64 {
+				return nil, fmt.Errorf("%d overflows int64", v)
+			}
+			return int64(v), nil
+		case float64:
+			if v == math.Trunc(v) && math.Abs(v) < 1<<63 {
+				return int64(v), nil
+			}
+		case bool:
+			if v {
+				return int64(1), nil
+			}
+			return int64(0), nil
+		case []byte:
+			return strconv.ParseInt(string(v), 10, 64)
+		case string:
+			return strconv.ParseInt(v, 10, 64)
+		}
+	case parquetDouble:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case float32:
+			return float64(v), nil
+		case int64:
+			return float64(v), nil
+		case []byte:
+			return strconv.ParseFloat(string(v), 64)
+		case string:
+			return strconv.ParseFloat(v, 64)
+		}
+	case parquetBoolean:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case int64:
+			return v != 0, nil
+		case []byte:
+			// MySQL returns BIT(1) as a raw byte
+			if len(v) == 1 && v[0] <= 1 {
+				return v[0] == 1, nil
+			}
+			return strconv.ParseBool(string(v))
+		case string:
+			return strconv.ParseBool(v)
+		}
+	case parquetTimestamp:
+		switch v := value.(type) {
+		case time.Time:
+			return v.UnixMicro(), nil
+		case []byte:
+			return parseTimestamp(string(v))
+		case string:
+			return parseTimestamp(v)
+		}
+	default:
+		switch v := value.(type) {
+		case []byte:
+			return append([]byte(nil), v...), nil
+		case string:
+			return []byte(v), nil
+		case time.Time:
+			return []byte(v.Format(time.RFC3339Nano)), nil
+		default:
+			return []byte(fmt.Sprint(v)), nil
+		}
+	}
+	return nil, fmt.Errorf("cannot convert %T to %s", value, t)
+}
+
+// parseTimestamp parses a timestamp stored as text, as SQLite does
+func parseTimestamp(text string) (int64, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02T15:04:05.999999999", "2006-01-02"} {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t.UnixMicro(), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid timestamp %q", text)
+}
+
+// parquetPage returns the data of a data page: the definition levels (1 for values, 0 for
+// NULLs) run-length encoded with a length prefix, followed by the PLAIN encoded values
+func parquetPage(t parquetType, values []interface{}) []byte {
+	var levels bytes.Buffer
+	for i := 0; i < len(values); {
+		defined := values[i] != nil
+		run := 1
+		for i+run < len(values) && (values[i+run] != nil) == defined {
+			run++
+		}
+		levels.Write(binary.AppendUvarint(nil, uint64(run)<<1))
+		if defined {
+			levels.WriteByte(1)
+		} else {
+			levels.WriteByte(0)
+		}
+		i += run
+	}
+
+	page := binary.LittleEndian.AppendUint32(nil, uint32(levels.Len()))
+	page = append(page, levels.Bytes()...)
+
+	var bits byte
+	var count int
+	for _, value := range values {
+		switch v := value.(type) {
+		case int64:
+			page = binary.LittleEndian.AppendUint64(page, uint64(v))
+		case float64:
+			page = binary.LittleEndian.AppendUint64(page, math.Float64bits(v))
+		case []byte:
+			page = binary.LittleEndian.AppendUint32(page, uint32(len(v)))
+			page = append(page, v...)
+		case bool:
+			if v {
+				bits |= 1 << (count % 8)
+			}
+			count++
+			if count%8 == 0 {
+				page = append(page, bits)
+				bits = 0
+			}
+		}
+	}
+	if t == parquetBoolean && count%8 != 0 {
+		page = append(page, bits)
+	}
+	return page
+}
+
+// parquetPageHeader returns the PageHeader of an uncompressed data page
+func parquetPageHeader(values, size int) []byte {
+	var t thriftWriter
+	t.fieldI32(1, parquetPageData)
+	t.fieldI32(2, int32(size))
+	t.fieldI32(3, int32(size))
+	t.fieldStruct(5)
+	t.fieldI32(1, int32(values))
+	t.fieldI32(2, parquetEncodingPlain)
+	t.fieldI32(3, parquetEncodingRLE)
+	t.fieldI32(4, parquetEncodingRLE)
+	t.endStruct()
+	t.endStruct()
+	return t.buf.Bytes()
+}
+
+// fileMetadata returns the FileMetaData of the file: its schema and row groups
+func (pw *parquetWriter) fileMetadata() []byte {
+	var t thriftWriter
+	t.fieldI32(1, 1)
+
+	t.fieldList(2, thriftStruct, len(pw.columns)+1)
+	t.beginStruct()
+	t.fieldString(4, "schema")
+	t.fieldI32(5, int32(len(pw.columns)))
+	t.endStruct()
+	for _, column := range pw.columns {
+		t.beginStruct()
+		t.fieldI32(1, column.physicalType())
+		t.fieldI32(3, parquetRepetitionOptional)
+		t.fieldString(4, column.Name)
+		if converted, ok := column.convertedType(); ok {
+			t.fieldI32(6, converted)
+		}
+		t.endStruct()
+	}
+
+	t.fieldI64(3, pw.rows)
+
+	t.fieldList(4, thriftStruct, len(pw.rowGroups))
+	for _, group := range pw.rowGroups {
+		t.beginStruct()
+		t.fieldList(1, thriftStruct, len(group.chunks))
+		for i, chunk := range group.chunks {
+			column := pw.columns[i]
+			t.beginStruct()
+			t.fieldI64(2, chunk.offset)
+			t.fieldStruct(3)
+			t.fieldI32(1, column.physicalType())
+			t.fieldList(2, thriftI32, 2)
+			t.i32(parquetEncodingPlain)
+			t.i32(parquetEncodingRLE)
+			t.fieldList(3, thriftBinary, 1)
+			t.string(column.Name)
+			t.fieldI32(4, parquetCodecUncompressed)
+			t.fieldI64(5, chunk.values)
+			t.fieldI64(6, chunk.size)
+			t.fieldI64(7, chunk.size)
+			t.fieldI64(9, chunk.offset)
+			t.endStruct()
+			t.endStruct()
+		}
+		t.fieldI64(2, group.size)
+		t.fieldI64(3, group.rows)
+		t.endStruct()
+	}
+
+	t.fieldString(6, "db-mcp")
+	t.endStruct()
+	return t.buf.Bytes()
+}
+
+// physicalType returns the Parquet physical type of the column
+func (c parquetColumn) physicalType() int32 {
+	switch c.Type {
+	case parquetInt64, parquetTimestamp:
+		return parquetPhysicalInt64
+	case parquetDouble:
+		return parquetPhysicalDouble
+	case parquetBoolean:
+		return parquetPhysicalBoolean
+	default:
+		return parquetPhysicalByteArray
+	}
+}
+
+// convertedType returns the Parquet converted type of the column, if it has one
+func (c parquetColumn) convertedType() (int32, bool) {
+	switch c.Type {
+	case parquetString:
+		return parquetConvertedUTF8, true
+	case parquetTimestamp:
+		return parquetConvertedTimestampMicros, true
+	default:
+		return 0, false
+	}
+}
+
+// Thrift compact protocol types
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter encodes structs with the Thrift compact protocol. Fields are written in
+// increasing id order, as the delta encoding of field ids requires.
+type thriftWriter struct {
+	buf     bytes.Buffer
+	lastIDs []int16
+	lastID  int16
+}
+
+// fieldHeader writes the header of field id
+func (t *thriftWriter) fieldHeader(id int16, fieldType byte) {
+	if delta := id - t.lastID; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		t.buf.WriteByte(fieldType)
+		t.varint(int64(id))
+	}
+	t.lastID = id
+}
+
+// varint writes a zigzag encoded integer
+func (t *thriftWriter) varint(v int64) {
+	t.buf.Write(binary.AppendUvarint(nil, uint64((v<<1)^(v>>63))))
+}
+
+func (t *thriftWriter) i32(v int32) {
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) string(v string) {
+	t.buf.Write(binary.AppendUvarint(nil, uint64(len(v))))
+	t.buf.WriteString(v)
+}
+
+func (t *thriftWriter) fieldI32(id int16, v int32) {
+	t.fieldHeader(id, thriftI32)
+	t.i32(v)
+}
+
+func (t *thriftWriter) fieldI64(id int16, v int64) {
+	t.fieldHeader(id, thriftI64)
+	t.varint(v)
+}
+
+func (t *thriftWriter) fieldString(id int16, v string) {
+	t.fieldHeader(id, thriftBinary)
+	t.string(v)
+}
+
+// fieldList writes the header of a list field; its elements follow
+func (t *thriftWriter) fieldList(id int16, elementType byte, size int) {
+	t.fieldHeader(id, thriftList)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | elementType)
+	} else {
+		t.buf.WriteByte(0xF0 | elementType)
+		t.buf.Write(binary.AppendUvarint(nil, uint64(size)))
+	}
+}
+
+// fieldStruct starts a struct field, ended by endStruct
+func (t *thriftWriter) fieldStruct(id int16) {
+	t.fieldHeader(id, thriftStruct)
+	t.beginStruct()
+}
+
+// beginStruct starts a struct, such as a list element
+func (t *thriftWriter) beginStruct() {
+	t.lastIDs = append(t.lastIDs, t.lastID)
+	t.lastID = 0
+}
+
+// endStruct writes the stop field of a struct
+func (t *thriftWriter) endStruct() {
+	t.buf.WriteByte(0)
+	if n := len(t.lastIDs); n > 0 {
+		t.lastID = t.lastIDs[n-1]
+		t.lastIDs = t.lastIDs[:n-1]
+	}
+}
@@ -0,0 +1,529 @@
+package mcp
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// explain_query returns the plan in the format of each database, and next to it the same plan
+// as a tree of operators with their estimated and actual rows and costs, so it can be read the
+// same way on every database. Values a database does not report are left out of its nodes.
+
+// planNode is an operator of a normalized execution plan
+type planNode struct {
+	Operator      string      `json:"operator"`
+	Object        string      `json:"object,omitempty"`
+	Index         string      `json:"index,omitempty"`
+	Detail        string      `json:"detail,omitempty"`
+	EstimatedRows *float64    `json:"estimated_rows,omitempty"`
+	EstimatedCost *float64    `json:"estimated_cost,omitempty"`
+	ActualRows    *float64    `json:"actual_rows,omitempty"`
+	ActualTimeMs  *float64    `json:"actual_time_ms,omitempty"`
+	Loops         *float64    `json:"loops,omitempty"`
+	Children      []*planNode `json:"children,omitempty"`
+}
+
+var (
+	reMySQLTreeCost   = regexp.MustCompile(`\(cost=([0-9.e+-]+)(?:\.\.([0-9.e+-]+))? rows=([0-9.e+-]+)\)`)
+	reMySQLTreeActual = regexp.MustCompile(`\(actual time=[0-9.e+-]+\.\.([0-9.e+-]+) rows=([0-9.e+-]+) loops=([0-9.e+-]+)\)`)
+	reMySQLTreeObject = regexp.MustCompile(` on (\S+)(?: using (\S+))?`)
+	reSQLiteStep      = regexp.MustCompile(`^(SCAN|SEARCH)(?: TABLE)? (\S+)(?: USING (?:COVERING )?INDEX (\S+))?`)
+)
+
+// mysqlAccessTypes names the access types of MySQL JSON plans as operators
+var mysqlAccessTypes = map[string]string{
+	"ALL":             "Table scan",
+	"index":           "Index scan",
+	"range":           "Index range scan",
+	"ref":             "Index lookup",
+	"ref_or_null":     "Index lookup",
+	"eq_ref":          "Unique index lookup",
+	"const":           "Constant row",
+	"system":          "Constant row",
+	"fulltext":        "Fulltext index lookup",
+	"index_merge":     "Index merge",
+	"unique_subquery": "Unique subquery lookup",
+	"index_subquery":  "Index subquery lookup",
+}
+
+// mysqlOperations names the operations of MySQL JSON plans that wrap other operations
+var mysqlOperations = map[string]string{
+	"query_block":        "Query block",
+	"nested_loop":        "Nested loop",
+	"ordering_operation": "Sort",
+	"grouping_operation": "Group",
+	"duplicates_removal": "Distinct",
+	"windowing":          "Window",
+	"union_result":       "Union",
+}
+
+// planTree returns the operator trees of a plan, one per statement, or nil when the plan
+// cannot be read
+func planTree(format string, plan *planRows) []*planNode {
+	if plan == nil {
+		return nil
+	}
+	document := strings.Join(plan.lines, "\n")
+	var roots []*planNode
+	switch format {
+	case "json":
+		roots = postgresPlanTree(document)
+		if roots == nil {
+			roots = mysqlPlanTree(document)
+		}
+	case "xml":
+		roots = showplanTree(document)
+	case "rows":
+		roots = sqlitePlanTree(plan.steps)
+	default:
+		roots = mysqlTextPlanTree(document)
+		if roots == nil {
+			roots = xplanTree(plan.lines)
+		}
+	}
+	if len(roots) == 0 {
+		return nil
+	}
+	return roots
+}
+
+// postgresPlanTree reads a Postgres JSON plan, whose nodes nest in Plans
+func postgresPlanTree(document string) []*planNode {
+	type postgresNode struct {
+		NodeType    string          `json:"Node Type"`
+		JoinType    string          `json:"Join Type"`
+		Relation    string          `json:"Relation Name"`
+		Schema      string          `json:"Schema"`
+		Index       string          `json:"Index Name"`
+		CTE         string          `json:"CTE Name"`
+		Function    string          `json:"Function Name"`
+		Filter      string          `json:"Filter"`
+		IndexCond   string          `json:"Index Cond"`
+		HashCond    string          `json:"Hash Cond"`
+		MergeCond   string          `json:"Merge Cond"`
+		JoinFilter  string          `json:"Join Filter"`
+		SortKey     []string        `json:"Sort Key"`
+		GroupKey    []string        `json:"Group Key"`
+		PlanRows    *float64        `json:"Plan Rows"`
+		TotalCost   *float64        `json:"Total Cost"`
+		ActualRows  *float64        `json:"Actual Rows"`
+		ActualTime  *float64        `json:"Actual Total Time"`
+		ActualLoops *float64        `json:"Actual Loops"`
+		Plans       json.RawMessage `json:"Plans"`
+	}
+	var statements []struct {
+		Plan json.RawMessage `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(document), &statements); err != nil {
+		return nil
+	}
+
+	var convert func(raw json.RawMessage) *planNode
+	convert = func(raw json.RawMessage) *planNode {
+		var source postgresNode
+		if err := json.Unmarshal(raw, &source); err != nil || source.NodeType == "" {
+			return nil
+		}
+		node := &planNode{
+			Operator:      source.NodeType,
+			Index:         source.Index,
+			EstimatedRows: source.PlanRows,
+			EstimatedCost: source.TotalCost,
+			ActualRows:    source.ActualRows,
+			ActualTimeMs:  source.ActualTime,
+			Loops:         source.ActualLoops,
+		}
+		if source.JoinType != "" && source.JoinType != "Inner" {
+			node.Operator += " (" + source.JoinType + ")"
+		}
+		switch {
+		case source.Relation != "" && source.Schema != "":
+			node.Object = source.Schema + "." + source.Relation
+		case source.Relation != "":
+			node.Object = source.Relation
+		case source.CTE != "":
+			node.Object = source.CTE
+		case source.Function != "":
+			node.Object = source.Function
+		}
+		node.Detail = planDetail(source.IndexCond, source.HashCond, source.MergeCond, source.JoinFilter, source.Filter,
+			keyDetail("Sort", source.SortKey), keyDetail("Group", source.GroupKey))
+
+		var children []json.RawMessage
+		if len(source.Plans) > 0 {
+			_ = json.Unmarshal(source.Plans, &children)
+		}
+		for _, child := range children {
+			if childNode := convert(child); childNode != nil {
+				node.Children = append(node.Children, childNode)
+			}
+		}
+		return node
+	}
+
+	var roots []*planNode
+	for _, statement := range statements {
+		if node := convert(statement.Plan); node != nil {
+			roots = append(roots, node)
+		}
+	}
+	return roots
+}
+
+// mysqlPlanTree reads a MySQL JSON plan, whose operations nest under the query block
+func mysqlPlanTree(document string) []*planNode {
+	var plan map[string]interface{}
+	if err := json.Unmarshal([]byte(document), &plan); err != nil {
+		return nil
+	}
+	return mysqlPlanNodes(plan)
+}
+
+// mysqlPlanNodes returns the operations of a MySQL JSON plan value. Keys that are not
+// operations, such as materialized_from_subquery or attached_subqueries, are searched for
+// the operations they hold.
+func mysqlPlanNodes(value interface{}) []*planNode {
+	var nodes []*planNode
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			switch {
+			case key == "table":
+				if table, ok := v[key].(map[string]interface{}); ok {
+					nodes = append(nodes, mysqlTableNode(table))
+				}
+			case key == "nested_loop":
+				node := &planNode{Operator: mysqlOperations[key]}
+				if steps, ok := v[key].([]interface{}); ok {
+					for _, step := range steps {
+						node.Children = append(node.Children, mysqlPlanNodes(step)...)
+					}
+				}
+				nodes = append(nodes, node)
+			case mysqlOperations[key] != "":
+				operation, _ := v[key].(map[string]interface{})
+				node := &planNode{Operator: mysqlOperations[key], Children: mysqlPlanNodes(operation)}
+				costInfo, _ := operation["cost_info"].(map[string]interface{})
+				if cost, ok := planNumber(costInfo["query_cost"]); ok {
+					node.EstimatedCost = &cost
+				}
+				nodes = append(nodes, node)
+			case key == "cost_info":
+			default:
+				nodes = append(nodes, mysqlPlanNodes(v[key])...)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			nodes = append(nodes, mysqlPlanNodes(item)...)
+		}
+	}
+	return nodes
+}
+
+// mysqlTableNode returns the access to a table of a MySQL JSON plan, with the operations
+// of the subqueries it materializes or attaches as its children
+func mysqlTableNode(table map[string]interface{}) *planNode {
+	accessType, _ := table["access_type"].(string)
+	node := &planNode{Operator: mysqlAccessTypes[accessType]}
+	if node.Operator == "" {
+		node.Operator = accessType
+	}
+	node.Object, _ = table["table_name"].(string)
+	node.Index, _ = table["key"].(string)
+	node.Detail, _ = table["attached_condition"].(string)
+	if rows, ok := planNumber(table["rows_examined_per_scan"]); ok {
+		node.EstimatedRows = &rows
+	}
+	costInfo, _ := table["cost_info"].(map[string]interface{})
+	if cost, ok := planNumber(costInfo["prefix_cost"]); ok {
+		node.EstimatedCost = &cost
+	}
+	for _, key := range []string{"materialized_from_subquery", "attached_subqueries"} {
+		node.Children = append(node.Children, mysqlPlanNodes(table[key])...)
+	}
+	return node
+}
+
+// mysqlTextPlanTree reads the tree of MySQL EXPLAIN ANALYZE, one "-> " line per operation
+// indented four spaces per level
+func mysqlTextPlanTree(document string) []*planNode {
+	var roots []*planNode
+	var stack []*planNode
+	for _, line := range strings.Split(document, "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if !strings.HasPrefix(trimmed, "-> ") {
+			continue
+		}
+		depth := (len(line) - len(trimmed)) / 4
+		text := strings.TrimPrefix(trimmed, "-> ")
+
+		node := &planNode{Operator: text}
+		if i := strings.Index(text, "  ("); i >= 0 {
+			node.Operator = text[:i]
+		} else if i = strings.Index(text, " (actual"); i >= 0 {
+			node.Operator = text[:i]
+		}
+		if m := reMySQLTreeObject.FindStringSubmatch(node.Operator); m != nil {
+			node.Object, node.Index = m[1], m[2]
+		}
+		if m := reMySQLTreeCost.FindStringSubmatch(text); m != nil {
+			cost := m[1]
+			if m[2] != "" {
+				cost = m[2]
+			}
+			node.EstimatedCost = planValue(cost)
+			node.EstimatedRows = planValue(m[3])
+		}
+		if m := reMySQLTreeActual.FindStringSubmatch(text); m != nil {
+			node.ActualTimeMs = planValue(m[1])
+			node.ActualRows = planValue(m[2])
+			node.Loops = planValue(m[3])
+		}
+
+		stack = attachPlanNode(&roots, stack, depth, node)
+	}
+	return roots
+}
+
+// xplanTree reads the plan table of Oracle DBMS_XPLAN, whose Operation column is indented
+// one space per level. Actual plans have E-Rows, A-Rows and A-Time instead of Rows.
+func xplanTree(lines []string) []*planNode {
+	columns := map[string]int{}
+	var roots []*planNode
+	var stack []*planNode
+	for _, line := range lines {
+		cells := strings.Split(line, "|")
+		if _, ok := columns["Operation"]; !ok {
+			for i, cell := range cells {
+				name := strings.TrimSpace(cell)
+				if strings.HasPrefix(name, "Cost") {
+					name = "Cost"
+				}
+				columns[name] = i
+			}
+			if _, ok := columns["Id"]; !ok {
+				columns = map[string]int{}
+			} else if _, ok = columns["Operation"]; !ok {
+				columns = map[string]int{}
+			}
+			continue
+		}
+		if len(cells) < len(columns) {
+			continue
+		}
+		if _, err := strconv.Atoi(strings.Trim(cells[columns["Id"]], " *")); err != nil {
+			continue
+		}
+
+		operation := strings.TrimPrefix(cells[columns["Operation"]], " ")
+		trimmed := strings.TrimLeft(operation, " ")
+		depth := len(operation) - len(trimmed)
+		node := &planNode{Operator: strings.TrimSpace(trimmed)}
+		cell := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(cells[i])
+			}
+			return ""
+		}
+		node.Object = cell("Name")
+		if rows := cell("Rows"); rows != "" {
+			node.EstimatedRows = planValue(rows)
+		} else {
+			node.EstimatedRows = planValue(cell("E-Rows"))
+		}
+		if fields := strings.Fields(cell("Cost")); len(fields) > 0 {
+			node.EstimatedCost = planValue(fields[0])
+		}
+		node.ActualRows = planValue(cell("A-Rows"))
+		node.Loops = planValue(cell("Starts"))
+		node.ActualTimeMs = xplanMilliseconds(cell("A-Time"))
+
+		stack = attachPlanNode(&roots, stack, depth, node)
+	}
+	return roots
+}
+
+// xplanMilliseconds reads a DBMS_XPLAN time, HH:MM:SS.FF, in milliseconds
+func xplanMilliseconds(text string) *float64 {
+	parts := strings.Split(text, ":")
+	if len(parts) != 3 {
+		return nil
+	}
+	total := 0.0
+	for _, part := range parts {
+		n, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return nil
+		}
+		total = total*60 + n
+	}
+	total *= 1000
+	return &total
+}
+
+// showplanTree reads a SQL Server SHOWPLAN_XML or STATISTICS XML plan, whose RelOp elements
+// nest within the element of their operator, one tree per statement
+func showplanTree(document string) []*planNode {
+	decoder := xml.NewDecoder(strings.NewReader(document))
+	var roots []*planNode
+	var stack []*planNode
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			attrs := make(map[string]string, len(element.Attr))
+			for _, attr := range element.Attr {
+				attrs[attr.Name.Local] = attr.Value
+			}
+			switch element.Name.Local {
+			case "RelOp":
+				node := &planNode{Operator: attrs["PhysicalOp"]}
+				if logical := attrs["LogicalOp"]; logical != "" && logical != node.Operator {
+					node.Detail = logical
+				}
+				node.EstimatedRows = planValue(attrs["EstimateRows"])
+				node.EstimatedCost = planValue(attrs["EstimatedTotalSubtreeCost"])
+				if len(stack) > 0 {
+					parent := stack[len(stack)-1]
+					parent.Children = append(parent.Children, node)
+				} else {
+					roots = append(roots, node)
+				}
+				stack = append(stack, node)
+			case "Object":
+				// The first object within a RelOp and before its children is its own
+				if len(stack) == 0 {
+					continue
+				}
+				node := stack[len(stack)-1]
+				if node.Object != "" || len(node.Children) > 0 {
+					continue
+				}
+				var names []string
+				for _, name := range []string{attrs["Schema"], attrs["Table"]} {
+					if name != "" {
+						names = append(names, strings.Trim(name, "[]"))
+					}
+				}
+				node.Object = strings.Join(names, ".")
+				node.Index = strings.Trim(attrs["Index"], "[]")
+			case "RunTimeCountersPerThread":
+				// Parallel operators report each thread, whose rows add up
+				if len(stack) == 0 {
+					continue
+				}
+				node := stack[len(stack)-1]
+				if rows := planValue(attrs["ActualRows"]); rows != nil {
+					node.ActualRows = addPlanValue(node.ActualRows, *rows)
+				}
+				if executions := planValue(attrs["ActualExecutions"]); executions != nil {
+					node.Loops = addPlanValue(node.Loops, *executions)
+				}
+				if elapsed := planValue(attrs["ActualElapsedms"]); elapsed != nil && (node.ActualTimeMs == nil || *elapsed > *node.ActualTimeMs) {
+					node.ActualTimeMs = elapsed
+				}
+			}
+		case xml.EndElement:
+			if element.Name.Local == "RelOp" && len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+	return roots
+}
+
+// sqlitePlanTree reads the steps of SQLite EXPLAIN QUERY PLAN, which point to their parent
+// step by id
+func sqlitePlanTree(steps []map[string]interface{}) []*planNode {
+	var roots []*planNode
+	nodes := map[float64]*planNode{}
+	for _, step := range steps {
+		detail, _ := step["detail"].(string)
+		node := &planNode{Operator: detail}
+		if m := reSQLiteStep.FindStringSubmatch(detail); m != nil {
+			node.Operator, node.Object, node.Index = m[1], m[2], m[3]
+			node.Detail = detail
+		}
+		id, _ := planNumber(step["id"])
+		parent, _ := planNumber(step["parent"])
+		nodes[id] = node
+		if parentNode, ok := nodes[parent]; ok && parent != id {
+			parentNode.Children = append(parentNode.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+	return roots
+}
+
+// attachPlanNode adds a node at depth to the tree of an indented plan, under the last node
+// of a lower depth, and returns the stack of its ancestors and the node
+func attachPlanNode(roots *[]*planNode, stack []*planNode, depth int, node *planNode) []*planNode {
+	if depth > len(stack) {
+		depth = len(stack)
+	}
+	stack = stack[:depth]
+	if depth == 0 {
+		*roots = append(*roots, node)
+	} else {
+		parent := stack[depth-1]
+		parent.Children = append(parent.Children, node)
+	}
+	return append(stack, node)
+}
+
+// planValue reads a number of a plan, or returns nil when text is not one
+func planValue(text string) *float64 {
+	if text == "" {
+		return nil
+	}
+	n, ok := planNumber(text)
+	if !ok {
+		return nil
+	}
+	return &n
+}
+
+// addPlanValue adds n to a value that may be missing
+func addPlanValue(value *float64, n float64) *float64 {
+	if value != nil {
+		n += *value
+	}
+	return &n
+}
+
+// planDetail joins the conditions of an operator
+func planDetail(conditions ...string) string {
+	var parts []string
+	for _, condition := range conditions {
+		if condition != "" {
+			parts = append(parts, condition)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// keyDetail describes the sort or group keys of a Postgres operator
+func keyDetail(name string, keys []string) string {
+	if len(keys) == 0 {
+		return ""
+	}
+	return name + " key: " + strings.Join(keys, ", ")
+}
@@ -0,0 +1,205 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// resultCache keeps the full responses of listing tools that exceeded the preview size,
+// so fetch_full can return them by handle. Large lists cost many tokens for little
+// benefit, so those tools answer with the first items of each list and a handle instead.
+type resultCache struct {
+	mu           sync.Mutex
+	entries      map[string]*cachedResult
+	previewBytes int64
+}
+
+// cachedResult is the full response of a tool call returned as a preview
+type cachedResult struct {
+	text    string
+	expires time.Time
+}
+
+// newResultCache returns a cache previewing responses larger than DB_PREVIEW_BYTES
+func newResultCache() *resultCache {
+	return &resultCache{
+		entries:      make(map[string]*cachedResult),
+		previewBytes: envInt("DB_PREVIEW_BYTES", DefaultPreviewBytes),
+	}
+}
+
+// isListingTool reports whether a tool returns lists that are previewed when large
+func isListingTool(name string) bool {
+	return strings.HasPrefix(name, "list_") || strings.HasPrefix(name, "search_") || name == "find_column"
+}
+
+// middleware replaces large responses of listing tools with a preview
+func (c *resultCache) middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, request)
+		if err != nil || c.previewBytes == 0 || !isListingTool(request.Params.Name) {
+			return result, err
+		}
+		return c.preview(result), nil
+	}
+}
+
+// preview returns the result with every list cut to PreviewItems items and a handle to the
+// full result, or the result itself when it is small or has no list to shorten
+func (c *resultCache) preview(result *mcp.CallToolResult) *mcp.CallToolResult {
+	if result == nil || result.IsError || len(result.Content) != 1 {
+		return result
+	}
+	content, ok := result.Content[0].(mcp.TextContent)
+	if !ok || int64(len(content.Text)) <= c.previewBytes {
+		return result
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(content.Text), &fields); err != nil {
+		return result
+	}
+
+	truncated := make(map[string]interface{})
+	response := make(map[string]interface{}, len(fields)+1)
+	for key, value := range fields {
+		response[key] = truncateLists(value, key, truncated)
+	}
+	if len(truncated) == 0 {
+		return result
+	}
+
+	handle, expires := c.put(content.Text)
+	response["preview"] = map[string]interface{}{
+		"handle":     handle,
+		"full_bytes": len(content.Text),
+		"truncated":  truncated,
+		"expires_at": expires.Format(time.RFC3339),
+		"message":    translate("The response is large, so each list shows its first items. Call fetch_full with the handle for the complete response"),
+	}
+	return jsonToolResult(response)
+}
+
+// truncateLists cuts the lists of a JSON value, including those of nested objects, to
+// PreviewItems items and records their path and length in truncated. List items are kept
+// as they are, so rows keep their column order.
+func truncateLists(value json.RawMessage, path string, truncated map[string]interface{}) json.RawMessage {
+	trimmed := bytes.TrimSpace(value)
+	if len(trimmed) == 0 {
+		return value
+	}
+
+	switch trimmed[0] {
+	case '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(trimmed, &items); err != nil || len(items) <= PreviewItems {
+			return value
+		}
+		shortened, err := json.Marshal(items[:PreviewItems])
+		if err != nil {
+			return value
+		}
+		truncated[path] = map[string]interface{}{
+			"shown": PreviewItems,
+			"total": len(items),
+		}
+		return shortened
+	case '{':
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(trimmed, &fields); err != nil {
+			return value
+		}
+		for key, field := range fields {
+			fields[key] = truncateLists(field, path+"."+key, truncated)
+		}
+		shortened, err := json.Marshal(fields)
+		if err != nil {
+			return value
+		}
+		return shortened
+	}
+	return value
+}
+
+// put stores a full response and returns its handle and expiry time. Expired responses
+// are dropped, and the one expiring first when the cache is full.
+func (c *resultCache) put(text string) (string, time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	var oldest string
+	for handle, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, handle)
+			continue
+		}
+		if oldest == "" || entry.expires.Before(c.entries[oldest].expires) {
+			oldest = handle
+		}
+	}
+	if len(c.entries) >= MaxCachedResults && oldest != "" {
+		delete(c.entries, oldest)
+	}
+
+	handle := newResultHandle()
+	expires := now.Add(ResultHandleTTL)
+	c.entries[handle] = &cachedResult{text: text, expires: expires}
+	return handle, expires
+}
+
+// get returns a full response that has not expired
+func (c *resultCache) get(handle string) (*cachedResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[handle]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(c.entries, handle)
+		return nil, false
+	}
+	return entry, true
+}
+
+// newResultHandle returns a random handle for a cached response
+func newResultHandle() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return time.Now().Format("20060102150405.000000000")
+	}
+	return hex.EncodeToString(buf)
+}
+
+// fetchFullArgs are the arguments of fetch_full
+type fetchFullArgs struct {
+	Handle string `json:"handle" jsonschema_description:"Handle from the preview field of a listing tool response"`
+}
+
+func (s *DbMCPServer) toolFetchFull() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("fetch_full", "Returns the complete response of a listing tool that was shortened to a preview, by the handle in its preview field. Handles expire after a few minutes", s.handleFetchFull)
+}
+
+func (s *DbMCPServer) handleFetchFull(ctx context.Context, request mcp.CallToolRequest, args fetchFullArgs) (*mcp.CallToolResult, error) {
+	if args.Handle == "" {
+		return toolErrorResult(ErrHandleRequired), nil
+	}
+
+	entry, ok := s.results.get(args.Handle)
+	if !ok {
+		return toolErrorResult(ErrResultHandleNotFound), nil
+	}
+
+	return mcp.NewToolResultText(entry.text), nil
+}
@@ -2,7 +2,9 @@ package mcp
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // QueryBuilder provides database-agnostic query building using dialects
@@ -57,6 +59,16 @@ func (qb *QueryBuilder) QualifyTable(schema, tableName string) string {
 	return fmt.Sprintf("%s.%s", qb.QuoteIdentifier(schema), qb.QuoteIdentifier(tableName))
 }
 
+// SearchPathStatements returns the session statements applying the given search path
+func (qb *QueryBuilder) SearchPathStatements(searchPath []string) []string {
+	return qb.dialect.SearchPathStatements(searchPath)
+}
+
+// SyntaxReference returns the syntax quick reference of the driver
+func (qb *QueryBuilder) SyntaxReference() SyntaxReferenceSQL {
+	return qb.dialect.SyntaxReference()
+}
+
 // -----------------------------------------------------------------------------
 // Pagination Helper
 // -----------------------------------------------------------------------------
@@ -111,6 +123,26 @@ func (qb *QueryBuilder) SupportsViews() bool {
 	return qb.dialect.SupportsFeature(FeatureViews)
 }
 
+// SupportsMaterializedViews returns true if driver supports materialized views
+func (qb *QueryBuilder) SupportsMaterializedViews() bool {
+	return qb.dialect.SupportsFeature(FeatureMaterializedViews)
+}
+
+// SupportsNamedParameters returns true if the driver binds sql.Named arguments by name
+func (qb *QueryBuilder) SupportsNamedParameters() bool {
+	return qb.dialect.SupportsFeature(FeatureNamedParameters)
+}
+
+// SupportsReadOnlyTransactions returns true if the driver starts transactions that reject writes
+func (qb *QueryBuilder) SupportsReadOnlyTransactions() bool {
+	return qb.dialect.SupportsFeature(FeatureReadOnlyTransactions)
+}
+
+// SupportsCrossDatabase returns true if metadata can be read from other databases on the same connection
+func (qb *QueryBuilder) SupportsCrossDatabase() bool {
+	return qb.dialect.SupportsFeature(FeatureCrossDatabase)
+}
+
 // -----------------------------------------------------------------------------
 // Driver Detection
 // -----------------------------------------------------------------------------
@@ -177,7 +209,7 @@ func (qb *QueryBuilder) DescribeTableQuery(schema, tableName string) (string, []
 	meta := qb.dialect.TableMetadata()
 
 	if qb.driver == DriverSQLite {
-		return fmt.Sprintf(meta.DescribeTable, tableName), []interface{}{}
+		return fmt.Sprintf(meta.DescribeTable, qb.QuoteIdentifier(tableName)), []interface{}{}
 	}
 
 	return meta.DescribeTable, []interface{}{
@@ -191,7 +223,7 @@ func (qb *QueryBuilder) TableExistsQuery(schema, tableName string) (string, []in
 	meta := qb.dialect.TableMetadata()
 
 	if qb.driver == DriverSQLite {
-		return meta.TableExists, []interface{}{tableName}
+		return meta.TableExists, []interface{}{qb.dialect.NormalizeIdentifier(tableName)}
 	}
 
 	return meta.TableExists, []interface{}{
@@ -205,7 +237,7 @@ func (qb *QueryBuilder) GetTableColumnsQuery(schema, tableName string) (string,
 	meta := qb.dialect.TableMetadata()
 
 	if qb.driver == DriverSQLite {
-		return fmt.Sprintf(meta.GetColumns, tableName), []interface{}{}
+		return fmt.Sprintf(meta.GetColumns, qb.QuoteIdentifier(tableName)), []interface{}{}
 	}
 
 	return meta.GetColumns, []interface{}{
@@ -219,7 +251,7 @@ func (qb *QueryBuilder) GetTableSchemaFullQuery(schema, tableName string) (strin
 	meta := qb.dialect.TableMetadata()
 
 	if qb.driver == DriverSQLite {
-		return fmt.Sprintf(meta.GetFullSchema, tableName), []interface{}{}
+		return fmt.Sprintf(meta.GetFullSchema, qb.QuoteIdentifier(tableName)), []interface{}{}
 	}
 
 	return meta.GetFullSchema, []interface{}{
@@ -233,7 +265,7 @@ func (qb *QueryBuilder) GetPrimaryKeyQuery(schema, tableName string) (string, []
 	meta := qb.dialect.TableMetadata()
 
 	if qb.driver == DriverSQLite {
-		return fmt.Sprintf(meta.GetPrimaryKey, tableName), []interface{}{}
+		return fmt.Sprintf(meta.GetPrimaryKey, qb.QuoteIdentifier(tableName)), []interface{}{}
 	}
 
 	return meta.GetPrimaryKey, []interface{}{
@@ -247,7 +279,7 @@ func (qb *QueryBuilder) GetIndexesQuery(schema, tableName string) (string, []int
 	meta := qb.dialect.TableMetadata()
 
 	if qb.driver == DriverSQLite {
-		return fmt.Sprintf(meta.GetIndexes, tableName), []interface{}{}
+		return fmt.Sprintf(meta.GetIndexes, qb.QuoteIdentifier(tableName)), []interface{}{}
 	}
 
 	return meta.GetIndexes, []interface{}{
@@ -261,7 +293,7 @@ func (qb *QueryBuilder) GetForeignKeysQuery(schema, tableName string) (string, [
 	meta := qb.dialect.TableMetadata()
 
 	if qb.driver == DriverSQLite {
-		return fmt.Sprintf(meta.GetForeignKeys, tableName), []interface{}{}
+		return fmt.Sprintf(meta.GetForeignKeys, qb.QuoteIdentifier(tableName)), []interface{}{}
 	}
 
 	return meta.GetForeignKeys, []interface{}{
@@ -270,6 +302,282 @@ func (qb *QueryBuilder) GetForeignKeysQuery(schema, tableName string) (string, [
 	}
 }
 
+// TableDDLColumnsQuery returns the query for the column definitions of a table
+func (qb *QueryBuilder) TableDDLColumnsQuery(schema, tableName string) (string, []interface{}) {
+	return qb.dialect.TableMetadata().DDLColumns, []interface{}{
+		qb.dialect.NormalizeIdentifier(schema),
+		qb.dialect.NormalizeIdentifier(tableName),
+	}
+}
+
+// TableDDLIndexesQuery returns the query for the key columns of the indexes of a table
+// that do not back a primary key or unique constraint
+func (qb *QueryBuilder) TableDDLIndexesQuery(schema, tableName string) (string, []interface{}) {
+	return qb.dialect.TableMetadata().DDLIndexes, []interface{}{
+		qb.dialect.NormalizeIdentifier(schema),
+		qb.dialect.NormalizeIdentifier(tableName),
+	}
+}
+
+// TableDDLScriptQuery returns the query for the stored CREATE statements of a table and
+// its indexes, or false if the driver must rebuild them from catalog metadata
+func (qb *QueryBuilder) TableDDLScriptQuery(tableName string) (string, []interface{}, bool) {
+	meta := qb.dialect.TableMetadata()
+	if meta.DDLScript == "" {
+		return "", nil, false
+	}
+	return meta.DDLScript, []interface{}{qb.dialect.NormalizeIdentifier(tableName)}, true
+}
+
+// DataDictionaryTablesQuery returns the query for a page of the tables of a schema with their comments
+func (qb *QueryBuilder) DataDictionaryTablesQuery(schemaFilter string, limit, offset int) (string, []interface{}) {
+	meta := qb.dialect.TableMetadata()
+	query := meta.DictionaryTables
+	var args []interface{}
+
+	if schemaFilter != "" && meta.DictionaryTableSchemaFilter != "" {
+		query += fmt.Sprintf(meta.DictionaryTableSchemaFilter, qb.Placeholder(1))
+		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
+	}
+
+	return qb.appendPaginationClause(query, meta.DictionaryTableOrderBy, limit, offset), args
+}
+
+// DataDictionaryColumnsQuery returns the query for the columns of the given tables of a
+// schema with their comments
+func (qb *QueryBuilder) DataDictionaryColumnsQuery(schemaFilter string, tableNames []string) (string, []interface{}) {
+	meta := qb.dialect.TableMetadata()
+	query := meta.DictionaryColumns
+	var args []interface{}
+	argIndex := 1
+
+	if schemaFilter != "" && meta.DictionaryColumnSchemaFilter != "" {
+		query += fmt.Sprintf(meta.DictionaryColumnSchemaFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
+		argIndex++
+	}
+
+	if len(tableNames) > 0 {
+		query += fmt.Sprintf(meta.DictionaryColumnTableFilter, strings.Join(BuildPlaceholderList(qb.dialect, argIndex, len(tableNames)), ", "))
+		for _, tableName := range tableNames {
+			args = append(args, qb.dialect.NormalizeIdentifier(tableName))
+		}
+	}
+
+	return query + meta.DictionaryColumnOrderBy, args
+}
+
+// FindColumnsQuery returns the query for a page of the table and view columns whose name
+// matches a pattern, case-insensitively. A pattern with % is compared with LIKE; without
+// it the name must match exactly, so _ in names such as customer_id is not a wildcard.
+func (qb *QueryBuilder) FindColumnsQuery(pattern, schemaFilter string, limit, offset int) (string, []interface{}) {
+	meta := qb.dialect.TableMetadata()
+	operator := "="
+	if strings.Contains(pattern, "%") {
+		operator = "LIKE"
+	}
+
+	query := meta.FindColumns + fmt.Sprintf(" AND UPPER(%s) %s UPPER(%s)", meta.FindColumnName, operator, qb.Placeholder(1))
+	args := []interface{}{pattern}
+
+	if schemaFilter != "" && meta.FindColumnSchemaFilter != "" {
+		query += fmt.Sprintf(meta.FindColumnSchemaFilter, qb.Placeholder(2))
+		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
+	}
+
+	return qb.appendPaginationClause(query, meta.FindColumnOrderBy, limit, offset), args
+}
+
+// ObjectCommentQuery returns the query for the type and comment of a table or view,
+// or false if the database has no comments
+func (qb *QueryBuilder) ObjectCommentQuery(schema, objectName string) (string, []interface{}, bool) {
+	meta := qb.dialect.TableMetadata()
+	if meta.ObjectComment == "" {
+		return "", nil, false
+	}
+	return meta.ObjectComment, []interface{}{
+		qb.dialect.NormalizeIdentifier(schema),
+		qb.dialect.NormalizeIdentifier(objectName),
+	}, true
+}
+
+// ColumnCommentsQuery returns the query for the column comments of a table or view,
+// or false if the database has no comments
+func (qb *QueryBuilder) ColumnCommentsQuery(schema, objectName string) (string, []interface{}, bool) {
+	meta := qb.dialect.TableMetadata()
+	if meta.ColumnComments == "" {
+		return "", nil, false
+	}
+	return meta.ColumnComments, []interface{}{
+		qb.dialect.NormalizeIdentifier(schema),
+		qb.dialect.NormalizeIdentifier(objectName),
+	}, true
+}
+
+// ColumnCollationsQuery returns the query for the collations of the columns of a table,
+// or false if columns have no collation
+func (qb *QueryBuilder) ColumnCollationsQuery(schema, tableName string) (string, []interface{}, bool) {
+	meta := qb.dialect.TableMetadata()
+	if meta.ColumnCollations == "" {
+		return "", nil, false
+	}
+	return meta.ColumnCollations, []interface{}{
+		qb.dialect.NormalizeIdentifier(schema),
+		qb.dialect.NormalizeIdentifier(tableName),
+	}, true
+}
+
+// TableStatsQuery returns the query for approximate table statistics,
+// or false if the driver has no catalog statistics
+func (qb *QueryBuilder) TableStatsQuery(schemaFilter, tableName string, limit, offset int) (string, []interface{}, bool) {
+	meta := qb.dialect.TableMetadata()
+	if meta.TableStats == "" {
+		return "", nil, false
+	}
+
+	query := meta.TableStats
+	var args []interface{}
+	argIndex := 1
+
+	if schemaFilter != "" && meta.StatsSchemaFilter != "" {
+		query += fmt.Sprintf(meta.StatsSchemaFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
+		argIndex++
+	}
+
+	if tableName != "" && meta.StatsTableFilter != "" {
+		query += fmt.Sprintf(meta.StatsTableFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(tableName))
+	}
+
+	query = qb.appendPaginationClause(query+meta.StatsGroupBy, meta.StatsOrderBy, limit, offset)
+
+	return query, args, true
+}
+
+// IdentityStatusQuery returns the query for the identity columns and their current values,
+// or false if the driver does not support it
+func (qb *QueryBuilder) IdentityStatusQuery(schemaFilter, tableName string) (string, []interface{}, bool) {
+	meta := qb.dialect.TableMetadata()
+	if meta.IdentityStatus == "" {
+		return "", nil, false
+	}
+
+	query := meta.IdentityStatus
+	var args []interface{}
+	argIndex := 1
+
+	if schemaFilter != "" {
+		query += fmt.Sprintf(meta.IdentitySchemaFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
+		argIndex++
+	}
+
+	if tableName != "" {
+		query += fmt.Sprintf(meta.IdentityTableFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(tableName))
+	}
+
+	return query + meta.IdentityOrderBy, args, true
+}
+
+// ListComputedColumnsQuery returns the query to list computed and generated columns,
+// or false if the driver does not support them
+func (qb *QueryBuilder) ListComputedColumnsQuery(schemaFilter, tableName string) (string, []interface{}, bool) {
+	meta := qb.dialect.TableMetadata()
+	if meta.ComputedColumns == "" {
+		return "", nil, false
+	}
+
+	query := meta.ComputedColumns
+	var args []interface{}
+	argIndex := 1
+
+	if schemaFilter != "" && meta.ComputedSchemaFilter != "" {
+		query += fmt.Sprintf(meta.ComputedSchemaFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
+		argIndex++
+	}
+
+	if tableName != "" && meta.ComputedTableFilter != "" {
+		query += fmt.Sprintf(meta.ComputedTableFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(tableName))
+	}
+
+	return query + meta.ComputedOrderBy, args, true
+}
+
+// ComputedExpressionIsTableSQL reports whether computed column expressions must be
+// extracted from the CREATE TABLE statement
+func (qb *QueryBuilder) ComputedExpressionIsTableSQL() bool {
+	return qb.dialect.TableMetadata().ComputedExpressionIsTableSQL
+}
+
+// ListStatisticsQuery returns the query to list the optimizer statistics of tables,
+// or false if not supported
+func (qb *QueryBuilder) ListStatisticsQuery(schemaFilter, tableName string) (string, []interface{}, bool) {
+	meta := qb.dialect.TableMetadata()
+	if meta.Statistics == "" {
+		return "", nil, false
+	}
+
+	query := meta.Statistics
+	var args []interface{}
+	argIndex := 1
+
+	if schemaFilter != "" && meta.StatisticsSchemaFilter != "" {
+		query += fmt.Sprintf(meta.StatisticsSchemaFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
+		argIndex++
+	}
+
+	if tableName != "" && meta.StatisticsTableFilter != "" {
+		query += fmt.Sprintf(meta.StatisticsTableFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(tableName))
+	}
+
+	return query + meta.StatisticsOrderBy, args, true
+}
+
+// ListPartitionsQuery returns the query to list the partitions of a table,
+// or false if the driver does not support partitioning
+func (qb *QueryBuilder) ListPartitionsQuery(schema, tableName string) (string, []interface{}, bool) {
+	meta := qb.dialect.TableMetadata()
+	if meta.ListPartitions == "" {
+		return "", nil, false
+	}
+
+	query, args := qb.appendSchemaTableFilters(meta.ListPartitions, meta.PartitionSchemaFilter, meta.PartitionTableFilter, schema, tableName)
+
+	return query + meta.PartitionOrderBy, args, true
+}
+
+// PartitionKeysQuery returns the query for the partition key columns of a table
+func (qb *QueryBuilder) PartitionKeysQuery(schema, tableName string) (string, []interface{}) {
+	meta := qb.dialect.TableMetadata()
+
+	query, args := qb.appendSchemaTableFilters(meta.PartitionKeys, meta.PartitionKeySchemaFilter, meta.PartitionKeyTableFilter, schema, tableName)
+
+	return query + meta.PartitionKeyOrderBy, args
+}
+
+// appendSchemaTableFilters appends the schema filter (when a schema is given) and the table filter
+func (qb *QueryBuilder) appendSchemaTableFilters(query, schemaFilter, tableFilter, schema, tableName string) (string, []interface{}) {
+	var args []interface{}
+	argIndex := 1
+
+	if schema != "" {
+		query += fmt.Sprintf(schemaFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(schema))
+		argIndex++
+	}
+
+	query += fmt.Sprintf(tableFilter, qb.Placeholder(argIndex))
+	args = append(args, qb.dialect.NormalizeIdentifier(tableName))
+
+	return query, args
+}
+
 // -----------------------------------------------------------------------------
 // Procedure Queries
 // -----------------------------------------------------------------------------
@@ -369,114 +677,603 @@ func (qb *QueryBuilder) GetFunctionCodeQuery(schema, functionName string) (strin
 	}
 }
 
-// -----------------------------------------------------------------------------
-// View Queries
-// -----------------------------------------------------------------------------
-
-// ListViewsQuery returns the query to list views
-func (qb *QueryBuilder) ListViewsQuery(schemaFilter, nameFilter string, limit, offset int) (string, []interface{}) {
-	meta := qb.dialect.ViewMetadata()
-	query := meta.ListViews
-	var args []interface{}
-	argIndex := 1
-
-	if schemaFilter != "" && meta.SchemaFilter != "" {
-		query += fmt.Sprintf(meta.SchemaFilter, qb.Placeholder(argIndex))
-		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
-		argIndex++
-	}
-
-	if nameFilter != "" && meta.NameFilter != "" {
-		query += fmt.Sprintf(meta.NameFilter, qb.Placeholder(argIndex))
-		args = append(args, "%"+qb.dialect.NormalizeIdentifier(nameFilter)+"%")
+// GetFunctionParametersQuery returns the query to get the parameters and return type of a function
+func (qb *QueryBuilder) GetFunctionParametersQuery(schema, functionName string) (string, []interface{}, bool) {
+	query := qb.dialect.FunctionMetadata().GetParameters
+	if !qb.SupportsFunctions() || query == "" {
+		return "", nil, false
 	}
 
-	query = qb.appendPaginationClause(query, meta.OrderBy, limit, offset)
-
-	return query, args
+	return query, []interface{}{
+		qb.dialect.NormalizeIdentifier(schema),
+		qb.dialect.NormalizeIdentifier(functionName),
+	}, true
 }
 
-// GetViewDefinitionQuery returns the query to get view definition
-func (qb *QueryBuilder) GetViewDefinitionQuery(schema, viewName string) (string, []interface{}) {
-	meta := qb.dialect.ViewMetadata()
+// -----------------------------------------------------------------------------
+// Permission Queries
+// -----------------------------------------------------------------------------
 
-	if qb.driver == DriverSQLite {
-		return meta.GetDefinition, []interface{}{viewName}
+// ListObjectPermissionsQuery returns the query to list the permissions granted (or denied)
+// on a table, view or routine, optionally for a single grantee
+func (qb *QueryBuilder) ListObjectPermissionsQuery(schema, objectName, grantee string) (string, []interface{}, bool) {
+	meta := qb.dialect.PermissionMetadata()
+	if meta.ListObjectPermissions == "" {
+		return "", nil, false
 	}
 
-	return meta.GetDefinition, []interface{}{
+	query := meta.ListObjectPermissions
+	args := []interface{}{
 		qb.dialect.NormalizeIdentifier(schema),
-		qb.dialect.NormalizeIdentifier(viewName),
+		qb.dialect.NormalizeIdentifier(objectName),
+	}
+	// MySQL placeholders are positional, so each branch of the union binds its own pair
+	if qb.IsMySQL() {
+		args = append(args, args[0], args[1], args[0], args[1])
+	}
+
+	if grantee != "" && meta.GranteeFilter != "" {
+		query += fmt.Sprintf(meta.GranteeFilter, qb.Placeholder(3))
+		args = append(args, qb.dialect.NormalizeIdentifier(grantee))
 	}
+
+	query += meta.OrderBy
+	return query, args, true
 }
 
-// -----------------------------------------------------------------------------
-// Trigger Queries
-// -----------------------------------------------------------------------------
+// ListRLSPoliciesQuery returns the query to list row-level security policies, optionally
+// of one schema or table, or false if the driver has no row-level security
+func (qb *QueryBuilder) ListRLSPoliciesQuery(schemaFilter, tableName string) (string, []interface{}, bool) {
+	meta := qb.dialect.PermissionMetadata()
+	if meta.RLSPolicies == "" {
+		return "", nil, false
+	}
 
-// ListTriggersQuery returns the query to list triggers
-func (qb *QueryBuilder) ListTriggersQuery(schemaFilter, tableName, nameFilter string, includeDisabled bool, limit, offset int) (string, []interface{}) {
-	meta := qb.dialect.TriggerMetadata()
-	query := meta.ListTriggers
+	query := meta.RLSPolicies
 	var args []interface{}
 	argIndex := 1
 
-	if schemaFilter != "" && meta.SchemaFilter != "" {
-		query += fmt.Sprintf(meta.SchemaFilter, qb.Placeholder(argIndex))
+	if schemaFilter != "" && meta.RLSSchemaFilter != "" {
+		query += fmt.Sprintf(meta.RLSSchemaFilter, qb.Placeholder(argIndex))
 		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
 		argIndex++
 	}
 
-	if tableName != "" && meta.TableFilter != "" {
-		query += fmt.Sprintf(meta.TableFilter, qb.Placeholder(argIndex))
+	if tableName != "" && meta.RLSTableFilter != "" {
+		query += fmt.Sprintf(meta.RLSTableFilter, qb.Placeholder(argIndex))
 		args = append(args, qb.dialect.NormalizeIdentifier(tableName))
-		argIndex++
 	}
 
+	return query + meta.RLSOrderBy, args, true
+}
+
+// ListPrincipalsQuery returns the query to list database users and roles, optionally
+// filtered by name
+func (qb *QueryBuilder) ListPrincipalsQuery(nameFilter string) (string, []interface{}, bool) {
+	meta := qb.dialect.PrincipalMetadata()
+	if meta.ListPrincipals == "" {
+		return "", nil, false
+	}
+
+	query := meta.ListPrincipals
+	var args []interface{}
 	if nameFilter != "" && meta.NameFilter != "" {
-		query += fmt.Sprintf(meta.NameFilter, qb.Placeholder(argIndex))
+		query += fmt.Sprintf(meta.NameFilter, qb.Placeholder(1))
 		args = append(args, "%"+qb.dialect.NormalizeIdentifier(nameFilter)+"%")
 	}
 
-	if !includeDisabled && meta.DisabledFilter != "" {
-		query += meta.DisabledFilter
+	query += meta.OrderBy
+	return query, args, true
+}
+
+// ListRoleMembersQuery returns the query to list role memberships
+func (qb *QueryBuilder) ListRoleMembersQuery() (string, bool) {
+	query := qb.dialect.PrincipalMetadata().ListRoleMembers
+	return query, query != ""
+}
+
+// ListRemoteServersQuery returns the query to list linked servers, foreign servers or
+// database links, optionally filtered by name
+func (qb *QueryBuilder) ListRemoteServersQuery(nameFilter string) (string, []interface{}, bool) {
+	meta := qb.dialect.RemoteServerMetadata()
+	if meta.ListServers == "" {
+		return "", nil, false
 	}
 
-	query = qb.appendPaginationClause(query, meta.OrderBy, limit, offset)
+	query := meta.ListServers
+	var args []interface{}
+	if nameFilter != "" && meta.NameFilter != "" {
+		query += fmt.Sprintf(meta.NameFilter, qb.Placeholder(1))
+		args = append(args, "%"+qb.dialect.NormalizeIdentifier(nameFilter)+"%")
+	}
 
-	return query, args
+	query += meta.OrderBy
+	return query, args, true
 }
 
-// GetTriggerCodeQuery returns the query to get trigger source code
-func (qb *QueryBuilder) GetTriggerCodeQuery(schema, triggerName string) (string, []interface{}) {
-	meta := qb.dialect.TriggerMetadata()
+// ListForeignTablesQuery returns the query to list the foreign tables of all remote servers
+func (qb *QueryBuilder) ListForeignTablesQuery() (string, bool) {
+	query := qb.dialect.RemoteServerMetadata().ListForeignTables
+	return query, query != ""
+}
 
-	if qb.driver == DriverSQLite {
-		return meta.GetCode, []interface{}{triggerName}
+// JobSchedulersQuery returns the query for the job schedulers installed on the connection,
+// or false if the database has none
+func (qb *QueryBuilder) JobSchedulersQuery() (string, bool) {
+	query := qb.dialect.JobMetadata().Schedulers
+	return query, query != ""
+}
+
+// ListScheduledJobsQuery returns the query to list the jobs of a scheduler ordered by name,
+// optionally filtered by a case-insensitive name fragment
+func (qb *QueryBuilder) ListScheduledJobsQuery(scheduler, nameFilter string) (string, []interface{}, bool) {
+	jobs, ok := qb.dialect.JobMetadata().ListJobs[scheduler]
+	if !ok {
+		return "", nil, false
 	}
 
-	return meta.GetCode, []interface{}{
-		qb.dialect.NormalizeIdentifier(schema),
-		qb.dialect.NormalizeIdentifier(triggerName),
+	query := "SELECT * FROM (" + jobs + ") jobs"
+	var args []interface{}
+	if nameFilter != "" {
+		query += fmt.Sprintf(" WHERE UPPER(jobs.job_name) LIKE UPPER(%s)", qb.Placeholder(1))
+		args = append(args, "%"+nameFilter+"%")
 	}
+
+	return query + " ORDER BY jobs.job_name", args, true
 }
 
-// -----------------------------------------------------------------------------
-// Database Info Queries
-// -----------------------------------------------------------------------------
+// ChangeTrackingSettingsQuery returns the query for the database-wide change capture settings
+// and the one for those needing extra privileges (possibly empty), or false if the database
+// has no change capture
+func (qb *QueryBuilder) ChangeTrackingSettingsQuery() (string, string, bool) {
+	meta := qb.dialect.ChangeTrackingMetadata()
+	return meta.Settings, meta.RestrictedSettings, meta.Settings != ""
+}
 
-// GetDatabaseInfoQuery returns query for database info
-func (qb *QueryBuilder) GetDatabaseInfoQuery() string {
-	return qb.dialect.DatabaseInfo().Version
+// ChangeTrackingTablesQuery returns the query for the tables captured by each change capture
+// mechanism, optionally filtered by schema and table
+func (qb *QueryBuilder) ChangeTrackingTablesQuery(schemaFilter, tableFilter string) (string, []interface{}) {
+	query := "SELECT * FROM (" + qb.dialect.ChangeTrackingMetadata().ListTables + ") tracked"
+	var conditions []string
+	var args []interface{}
+	if schemaFilter != "" {
+		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
+		conditions = append(conditions, "tracked.schema_name = "+qb.Placeholder(len(args)))
+	}
+	if tableFilter != "" {
+		args = append(args, qb.dialect.NormalizeIdentifier(tableFilter))
+		conditions = append(conditions, "tracked.table_name = "+qb.Placeholder(len(args)))
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	return query + " ORDER BY tracked.schema_name, tracked.table_name, tracked.mechanism, tracked.name", args
 }
 
-// GetDatabaseDetailsQuery returns query for detailed database information
-func (qb *QueryBuilder) GetDatabaseDetailsQuery() (string, bool) {
-	details := qb.dialect.DatabaseInfo().Details
-	return details, details != ""
+// ReplicationSlotsQuery returns the query for the replication slots, or empty if not supported
+func (qb *QueryBuilder) ReplicationSlotsQuery() string {
+	return qb.dialect.ChangeTrackingMetadata().ListSlots
 }
 
-// GetObjectCountsQuery returns query to count database objects
+// TemporalSourcesQuery returns the query for the temporal table implementations available on
+// the connection, or false if the database has none
+func (qb *QueryBuilder) TemporalSourcesQuery() (string, bool) {
+	query := qb.dialect.TemporalMetadata().Sources
+	return query, query != ""
+}
+
+// ListTemporalTablesQuery returns the query to list the temporal tables of an implementation
+// ordered by schema and name, optionally filtered by schema and table
+func (qb *QueryBuilder) ListTemporalTablesQuery(source, schemaFilter, tableFilter string) (string, []interface{}, bool) {
+	tables, ok := qb.dialect.TemporalMetadata().ListTables[source]
+	if !ok {
+		return "", nil, false
+	}
+
+	query := "SELECT * FROM (" + tables + ") temporal"
+	var conditions []string
+	var args []interface{}
+	if schemaFilter != "" {
+		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
+		conditions = append(conditions, "temporal.schema_name = "+qb.Placeholder(len(args)))
+	}
+	if tableFilter != "" {
+		args = append(args, qb.dialect.NormalizeIdentifier(tableFilter))
+		conditions = append(conditions, "temporal.table_name = "+qb.Placeholder(len(args)))
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	return query + " ORDER BY temporal.schema_name, temporal.table_name", args, true
+}
+
+// -----------------------------------------------------------------------------
+// View Queries
+// -----------------------------------------------------------------------------
+
+// ListViewsQuery returns the query to list views
+func (qb *QueryBuilder) ListViewsQuery(schemaFilter, nameFilter string, limit, offset int) (string, []interface{}) {
+	meta := qb.dialect.ViewMetadata()
+	query := meta.ListViews
+	var args []interface{}
+	argIndex := 1
+
+	if schemaFilter != "" && meta.SchemaFilter != "" {
+		query += fmt.Sprintf(meta.SchemaFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
+		argIndex++
+	}
+
+	if nameFilter != "" && meta.NameFilter != "" {
+		query += fmt.Sprintf(meta.NameFilter, qb.Placeholder(argIndex))
+		args = append(args, "%"+qb.dialect.NormalizeIdentifier(nameFilter)+"%")
+	}
+
+	query = qb.appendPaginationClause(query, meta.OrderBy, limit, offset)
+
+	return query, args
+}
+
+// ListMaterializedViewsQuery returns the query to list materialized views
+func (qb *QueryBuilder) ListMaterializedViewsQuery(schemaFilter, nameFilter string, limit, offset int) (string, []interface{}) {
+	meta := qb.dialect.ViewMetadata()
+	query := meta.ListMaterializedViews
+	var args []interface{}
+	argIndex := 1
+
+	if schemaFilter != "" && meta.MaterializedViewSchemaFilter != "" {
+		query += fmt.Sprintf(meta.MaterializedViewSchemaFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
+		argIndex++
+	}
+
+	if nameFilter != "" && meta.MaterializedViewNameFilter != "" {
+		query += fmt.Sprintf(meta.MaterializedViewNameFilter, qb.Placeholder(argIndex))
+		args = append(args, "%"+qb.dialect.NormalizeIdentifier(nameFilter)+"%")
+	}
+
+	query = qb.appendPaginationClause(query, meta.MaterializedViewOrderBy, limit, offset)
+
+	return query, args
+}
+
+// GetViewDefinitionQuery returns the query to get view definition
+func (qb *QueryBuilder) GetViewDefinitionQuery(schema, viewName string) (string, []interface{}) {
+	meta := qb.dialect.ViewMetadata()
+
+	if qb.driver == DriverSQLite {
+		return meta.GetDefinition, []interface{}{viewName}
+	}
+
+	return meta.GetDefinition, []interface{}{
+		qb.dialect.NormalizeIdentifier(schema),
+		qb.dialect.NormalizeIdentifier(viewName),
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Trigger Queries
+// -----------------------------------------------------------------------------
+
+// ListTriggersQuery returns the query to list triggers
+func (qb *QueryBuilder) ListTriggersQuery(schemaFilter, tableName, nameFilter string, includeDisabled bool, limit, offset int) (string, []interface{}) {
+	meta := qb.dialect.TriggerMetadata()
+	query := meta.ListTriggers
+	var args []interface{}
+	argIndex := 1
+
+	if schemaFilter != "" && meta.SchemaFilter != "" {
+		query += fmt.Sprintf(meta.SchemaFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
+		argIndex++
+	}
+
+	if tableName != "" && meta.TableFilter != "" {
+		query += fmt.Sprintf(meta.TableFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(tableName))
+		argIndex++
+	}
+
+	if nameFilter != "" && meta.NameFilter != "" {
+		query += fmt.Sprintf(meta.NameFilter, qb.Placeholder(argIndex))
+		args = append(args, "%"+qb.dialect.NormalizeIdentifier(nameFilter)+"%")
+	}
+
+	if !includeDisabled && meta.DisabledFilter != "" {
+		query += meta.DisabledFilter
+	}
+
+	query = qb.appendPaginationClause(query, meta.OrderBy, limit, offset)
+
+	return query, args
+}
+
+// GetTriggerCodeQuery returns the query to get trigger source code
+func (qb *QueryBuilder) GetTriggerCodeQuery(schema, triggerName string) (string, []interface{}) {
+	meta := qb.dialect.TriggerMetadata()
+
+	if qb.driver == DriverSQLite {
+		return meta.GetCode, []interface{}{triggerName}
+	}
+
+	return meta.GetCode, []interface{}{
+		qb.dialect.NormalizeIdentifier(schema),
+		qb.dialect.NormalizeIdentifier(triggerName),
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Synonym Queries
+// -----------------------------------------------------------------------------
+
+// ListSynonymsQuery returns the query to list synonyms,
+// or false if the driver has no synonyms
+func (qb *QueryBuilder) ListSynonymsQuery(schemaFilter, nameFilter string, limit, offset int) (string, []interface{}, bool) {
+	meta := qb.dialect.SynonymMetadata()
+	if meta.ListSynonyms == "" {
+		return "", nil, false
+	}
+
+	query := meta.ListSynonyms
+	var args []interface{}
+	argIndex := 1
+
+	if schemaFilter != "" && meta.SchemaFilter != "" {
+		query += fmt.Sprintf(meta.SchemaFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
+		argIndex++
+	}
+
+	if nameFilter != "" && meta.NameFilter != "" {
+		query += fmt.Sprintf(meta.NameFilter, qb.Placeholder(argIndex))
+		args = append(args, "%"+qb.dialect.NormalizeIdentifier(nameFilter)+"%")
+	}
+
+	query = qb.appendPaginationClause(query, meta.OrderBy, limit, offset)
+
+	return query, args, true
+}
+
+// -----------------------------------------------------------------------------
+// Type Queries
+// -----------------------------------------------------------------------------
+
+// ListTypesQuery returns the query to list user-defined types,
+// or false if the driver has no user-defined types
+func (qb *QueryBuilder) ListTypesQuery(schemaFilter, nameFilter string, limit, offset int) (string, []interface{}, bool) {
+	meta := qb.dialect.TypeMetadata()
+	if meta.ListTypes == "" {
+		return "", nil, false
+	}
+
+	query := meta.ListTypes
+	var args []interface{}
+	argIndex := 1
+
+	if schemaFilter != "" && meta.SchemaFilter != "" {
+		query += fmt.Sprintf(meta.SchemaFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
+		argIndex++
+	}
+
+	if nameFilter != "" && meta.NameFilter != "" {
+		query += fmt.Sprintf(meta.NameFilter, qb.Placeholder(argIndex))
+		args = append(args, "%"+qb.dialect.NormalizeIdentifier(nameFilter)+"%")
+	}
+
+	query = qb.appendPaginationClause(query, meta.OrderBy, limit, offset)
+
+	return query, args, true
+}
+
+// ListTypeColumnsQuery returns the query to list the columns of the table, composite and
+// object types matching the filters of ListTypesQuery
+func (qb *QueryBuilder) ListTypeColumnsQuery(schemaFilter, nameFilter string) (string, []interface{}, bool) {
+	meta := qb.dialect.TypeMetadata()
+	return qb.typeDetailQuery(meta, meta.ListColumns, meta.ColumnsOrderBy, schemaFilter, nameFilter)
+}
+
+// ListTypeUsageQuery returns the query to list the routine parameters and return values
+// using the types matching the filters of ListTypesQuery
+func (qb *QueryBuilder) ListTypeUsageQuery(schemaFilter, nameFilter string) (string, []interface{}, bool) {
+	meta := qb.dialect.TypeMetadata()
+	return qb.typeDetailQuery(meta, meta.ListUsage, meta.UsageOrderBy, schemaFilter, nameFilter)
+}
+
+// typeDetailQuery applies the type filters to a type detail query
+func (qb *QueryBuilder) typeDetailQuery(meta TypeMetadataSQL, base, orderBy, schemaFilter, nameFilter string) (string, []interface{}, bool) {
+	if base == "" {
+		return "", nil, false
+	}
+
+	query := base
+	var args []interface{}
+	argIndex := 1
+
+	if schemaFilter != "" && meta.SchemaFilter != "" {
+		query += fmt.Sprintf(meta.SchemaFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
+		argIndex++
+	}
+
+	if nameFilter != "" && meta.NameFilter != "" {
+		query += fmt.Sprintf(meta.NameFilter, qb.Placeholder(argIndex))
+		args = append(args, "%"+qb.dialect.NormalizeIdentifier(nameFilter)+"%")
+	}
+
+	query += orderBy
+	return query, args, true
+}
+
+// -----------------------------------------------------------------------------
+// Dependency Queries
+// -----------------------------------------------------------------------------
+
+// dependencyOrderBy sorts dependency rows by schema, name, type and relation, so the
+// graph is built in the same order on every call
+const dependencyOrderBy = " ORDER BY 1, 2, 3, 4"
+
+// UpstreamDependenciesQuery returns query for the objects an object depends on
+func (qb *QueryBuilder) UpstreamDependenciesQuery(schema, objectName string) (string, []interface{}) {
+	return qb.dialect.DependencyMetadata().Upstream + dependencyOrderBy, []interface{}{
+		qb.dialect.NormalizeIdentifier(schema),
+		qb.dialect.NormalizeIdentifier(objectName),
+	}
+}
+
+// DownstreamDependenciesQuery returns query for the objects depending on an object
+func (qb *QueryBuilder) DownstreamDependenciesQuery(schema, objectName string) (string, []interface{}) {
+	return qb.dialect.DependencyMetadata().Downstream + dependencyOrderBy, []interface{}{
+		qb.dialect.NormalizeIdentifier(schema),
+		qb.dialect.NormalizeIdentifier(objectName),
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Definition Search Queries
+// -----------------------------------------------------------------------------
+
+// SearchDefinitionsQuery returns the query to find views, routines and triggers whose
+// source contains searchTerm, optionally restricted to a schema and to some kinds
+func (qb *QueryBuilder) SearchDefinitionsQuery(searchTerm, schemaFilter string, kinds []string) (string, []interface{}) {
+	meta := qb.dialect.DefinitionSearch()
+
+	query := meta.Search
+	args := []interface{}{searchTerm}
+
+	if schemaFilter != "" && meta.SchemaFilter != "" {
+		query += fmt.Sprintf(meta.SchemaFilter, qb.Placeholder(2))
+		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
+	}
+
+	query += fmt.Sprintf(meta.KindFilter, kindInList(kinds, DefinitionKinds))
+	query += meta.OrderBy
+
+	return query, args
+}
+
+// -----------------------------------------------------------------------------
+// Constraint Queries
+// -----------------------------------------------------------------------------
+
+// ListForeignKeysQuery returns the query to list foreign keys, optionally filtered
+// by schema, owning table and referenced table
+func (qb *QueryBuilder) ListForeignKeysQuery(schemaFilter, tableName, referencedTable string) (string, []interface{}) {
+	meta := qb.dialect.ConstraintMetadata()
+	query := meta.ListForeignKeys
+	var args []interface{}
+	argIndex := 1
+
+	if schemaFilter != "" && meta.ForeignKeySchemaFilter != "" {
+		query += fmt.Sprintf(meta.ForeignKeySchemaFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
+		argIndex++
+	}
+
+	if tableName != "" && meta.ForeignKeyTableFilter != "" {
+		query += fmt.Sprintf(meta.ForeignKeyTableFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(tableName))
+		argIndex++
+	}
+
+	if referencedTable != "" && meta.ForeignKeyReferencedTableFilter != "" {
+		query += fmt.Sprintf(meta.ForeignKeyReferencedTableFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(referencedTable))
+	}
+
+	return query + meta.ForeignKeyOrderBy, args
+}
+
+// ListKeyConstraintsQuery returns the query to list primary keys and unique constraints,
+// optionally filtered by schema and table
+func (qb *QueryBuilder) ListKeyConstraintsQuery(schemaFilter, tableName string) (string, []interface{}) {
+	meta := qb.dialect.ConstraintMetadata()
+	query := meta.ListKeyConstraints
+	var args []interface{}
+	argIndex := 1
+
+	if schemaFilter != "" && meta.KeySchemaFilter != "" {
+		query += fmt.Sprintf(meta.KeySchemaFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
+		argIndex++
+	}
+
+	if tableName != "" && meta.KeyTableFilter != "" {
+		query += fmt.Sprintf(meta.KeyTableFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(tableName))
+	}
+
+	return query + meta.KeyOrderBy, args
+}
+
+// ListCheckConstraintsQuery returns the query to list check constraints,
+// optionally filtered by schema and table
+func (qb *QueryBuilder) ListCheckConstraintsQuery(schemaFilter, tableName string) (string, []interface{}) {
+	meta := qb.dialect.ConstraintMetadata()
+	query := meta.ListCheckConstraints
+	var args []interface{}
+	argIndex := 1
+
+	if schemaFilter != "" && meta.CheckSchemaFilter != "" {
+		query += fmt.Sprintf(meta.CheckSchemaFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
+		argIndex++
+	}
+
+	if tableName != "" && meta.CheckTableFilter != "" {
+		query += fmt.Sprintf(meta.CheckTableFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(tableName))
+	}
+
+	return query + meta.CheckOrderBy, args
+}
+
+// CheckDefinitionIsTableSQL reports whether check definitions must be extracted
+// from the CREATE TABLE statement
+func (qb *QueryBuilder) CheckDefinitionIsTableSQL() bool {
+	return qb.dialect.ConstraintMetadata().CheckDefinitionIsTableSQL
+}
+
+// ListDefaultsQuery returns the query to list column default expressions,
+// optionally filtered by schema and table
+func (qb *QueryBuilder) ListDefaultsQuery(schemaFilter, tableName string) (string, []interface{}) {
+	meta := qb.dialect.ConstraintMetadata()
+	query := meta.ListDefaults
+	var args []interface{}
+	argIndex := 1
+
+	if schemaFilter != "" && meta.DefaultSchemaFilter != "" {
+		query += fmt.Sprintf(meta.DefaultSchemaFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
+		argIndex++
+	}
+
+	if tableName != "" && meta.DefaultTableFilter != "" {
+		query += fmt.Sprintf(meta.DefaultTableFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(tableName))
+	}
+
+	return query + meta.DefaultOrderBy, args
+}
+
+// -----------------------------------------------------------------------------
+// Database Info Queries
+// -----------------------------------------------------------------------------
+
+// GetDatabaseInfoQuery returns query for database info
+func (qb *QueryBuilder) GetDatabaseInfoQuery() string {
+	return qb.dialect.DatabaseInfo().Version
+}
+
+// GetDatabaseDetailsQuery returns query for detailed database information
+func (qb *QueryBuilder) GetDatabaseDetailsQuery() (string, bool) {
+	details := qb.dialect.DatabaseInfo().Details
+	return details, details != ""
+}
+
+// GetObjectCountsQuery returns query to count database objects
 func (qb *QueryBuilder) GetObjectCountsQuery() (string, bool) {
 	counts := qb.dialect.DatabaseInfo().ObjectCounts
 	return counts, counts != ""
@@ -488,205 +1285,423 @@ func (qb *QueryBuilder) GetSchemasListQuery() (string, bool) {
 	return schemas, schemas != ""
 }
 
-// SearchObjectsQuery returns the query to search database objects
+// ListDatabasesQuery returns query to list the databases visible to the connection
+func (qb *QueryBuilder) ListDatabasesQuery() (string, bool) {
+	databases := qb.dialect.DatabaseInfo().ListDatabases
+	return databases, databases != ""
+}
+
+// ListExtensionsQuery returns query to list the installed extensions
+func (qb *QueryBuilder) ListExtensionsQuery() (string, bool) {
+	extensions := qb.dialect.DatabaseInfo().ListExtensions
+	return extensions, extensions != ""
+}
+
+// SchemaOverviewQuery returns query for the object counts and size of each schema ordered
+// by name, optionally only for one schema
+func (qb *QueryBuilder) SchemaOverviewQuery(schemaFilter string) (string, []interface{}) {
+	query := "SELECT * FROM (" + qb.dialect.DatabaseInfo().SchemaOverview + ") overview"
+	var args []interface{}
+	if schemaFilter != "" {
+		query += " WHERE overview.schema_name = " + qb.Placeholder(1)
+		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
+	}
+	return query + " ORDER BY overview.schema_name", args
+}
+
+// LargestTablesQuery returns the query for the statistics of the limit largest tables by
+// data and index size, optionally only of one schema, or false if the driver keeps no
+// table statistics
+func (qb *QueryBuilder) LargestTablesQuery(schemaFilter string, limit int) (string, []interface{}, bool) {
+	meta := qb.dialect.TableMetadata()
+	if meta.TableStats == "" {
+		return "", nil, false
+	}
+
+	stats := meta.TableStats
+	var args []interface{}
+	if schemaFilter != "" && meta.StatsSchemaFilter != "" {
+		stats += fmt.Sprintf(meta.StatsSchemaFilter, qb.Placeholder(1))
+		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
+	}
+
+	query := "SELECT * FROM (" + stats + meta.StatsGroupBy + ") stats"
+	orderBy := "COALESCE(stats.data_bytes, 0) + COALESCE(stats.index_bytes, 0) DESC, stats.schema_name, stats.table_name"
+	return qb.appendPaginationClause(query, orderBy, limit, 0), args, true
+}
+
+// EstimatedRowCountsQuery returns the query for the catalog row count estimates of the
+// tables, optionally only of one schema, or false if the driver keeps no table statistics
+func (qb *QueryBuilder) EstimatedRowCountsQuery(schemaFilter string) (string, []interface{}, bool) {
+	meta := qb.dialect.TableMetadata()
+	if meta.TableStats == "" {
+		return "", nil, false
+	}
+
+	stats := meta.TableStats
+	var args []interface{}
+	if schemaFilter != "" && meta.StatsSchemaFilter != "" {
+		stats += fmt.Sprintf(meta.StatsSchemaFilter, qb.Placeholder(1))
+		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
+	}
+
+	query := "SELECT stats.schema_name, stats.table_name, stats.row_count FROM (" + stats + meta.StatsGroupBy + ") stats" +
+		" ORDER BY stats.schema_name, stats.table_name"
+	return query, args, true
+}
+
+// ExplainQuery returns the statement that returns or stores the estimated plan of query,
+// with the setup statement to run before it and the query that reads a stored plan
+func (qb *QueryBuilder) ExplainQuery(query string) (string, string, string) {
+	meta := qb.dialect.Explain()
+	query = strings.TrimRight(strings.TrimSpace(query), ";")
+	return meta.Prefix + query, meta.Setup, meta.PlanQuery
+}
+
+// ExplainAnalyzeQuery returns the statement that runs query and returns its plan with
+// actual row counts, with the setup statement to run before it and the query that reads
+// the plan afterwards, or false if the driver has no actual plans
+func (qb *QueryBuilder) ExplainAnalyzeQuery(query string) (string, string, string, bool) {
+	meta := qb.dialect.Explain()
+	if meta.AnalyzeFormat == "" {
+		return "", "", "", false
+	}
+	query = strings.TrimRight(strings.TrimSpace(query), ";")
+	return meta.AnalyzePrefix + query, meta.AnalyzeSetup, meta.AnalyzePlanQuery, true
+}
+
+// ExplainFormat returns the format of the plans of the driver, estimated or actual:
+// xml, json, text or rows
+func (qb *QueryBuilder) ExplainFormat(analyze bool) string {
+	if analyze {
+		return qb.dialect.Explain().AnalyzeFormat
+	}
+	return qb.dialect.Explain().Format
+}
+
+// ParseCheck returns the statements that check query against the database without running it
+func (qb *QueryBuilder) ParseCheck() ParseCheckSQL {
+	return qb.dialect.ParseCheck()
+}
+
+// BuildSampleQuery builds the query of sample_table_data for limit rows of a table: random
+// rows, or its first rows when random is false. A positive percent reads random rows from
+// that percentage of the table, when the database supports table sampling.
+func (qb *QueryBuilder) BuildSampleQuery(schema, table string, columns []string, limit int, random bool, percent float64) string {
+	quotedColumns := make([]string, len(columns))
+	for i, col := range columns {
+		quotedColumns[i] = qb.QuoteIdentifier(col)
+	}
+
+	meta := qb.dialect.Sample()
+	template := meta.First
+	if random {
+		template = meta.Random
+		if percent > 0 && meta.TableSample != "" {
+			template = meta.TableSample
+		}
+	}
+	return fmt.Sprintf(template, strings.Join(quotedColumns, ", "), qb.QualifyTable(schema, table), limit, strconv.FormatFloat(percent, 'f', -1, 64))
+}
+
+// SupportsTableSample reports whether random rows can be read from a sample of a table
+// instead of sorting all of it
+func (qb *QueryBuilder) SupportsTableSample() bool {
+	return qb.dialect.Sample().TableSample != ""
+}
+
+// ColumnProfileQuery builds the profile_column query of a column with its top most frequent
+// values, and reports whether its distinct count is an estimate
+func (qb *QueryBuilder) ColumnProfileQuery(schema, table, column string, top int) (string, bool) {
+	meta := qb.dialect.ColumnProfile()
+	return fmt.Sprintf(meta.Query, qb.QuoteIdentifier(column), qb.QualifyTable(schema, table), top), meta.ApproximateDistinct
+}
+
+// LimitQuery returns query wrapped to produce at most limit rows, or query unchanged when
+// the driver caps rows on the session (see SessionRowLimit) or needs no cap, or when the
+// query cannot be a subquery (SHOW, EXPLAIN, PRAGMA), whose rows the caller stops reading
+func (qb *QueryBuilder) LimitQuery(query string, limit int) string {
+	wrap := qb.dialect.ResultLimit().WrapLimit
+	if wrap == "" || !qb.IsSelectQuery(query) {
+		return query
+	}
+	return fmt.Sprintf(wrap, query, limit)
+}
+
+// IsSelectQuery reports whether a query is a SELECT, WITH, VALUES or TABLE query, which can
+// be a subquery and has an estimated plan, rather than a statement with rows of its own
+func (qb *QueryBuilder) IsSelectQuery(query string) bool {
+	tokens := lexSQL(query, sqlLexRulesFor(qb.driver)[0])
+	if len(tokens) == 0 {
+		return false
+	}
+	first := tokens[0]
+	return first.text == "(" || first.is("SELECT") || first.is("WITH") || first.is("VALUES") || first.is("TABLE")
+}
+
+// SessionRowLimit returns the statements that cap the rows of the queries of a session at
+// limit and lift the cap, or empty strings if the driver has no session cap
+func (qb *QueryBuilder) SessionRowLimit(limit int) (string, string) {
+	meta := qb.dialect.ResultLimit()
+	if meta.SessionLimit == "" {
+		return "", ""
+	}
+	return fmt.Sprintf(meta.SessionLimit, limit), meta.SessionReset
+}
+
+// CollationQuery returns query for the default collation of the current database
+func (qb *QueryBuilder) CollationQuery() string {
+	return qb.dialect.DatabaseInfo().Collation
+}
+
+// ReadOnlyDatabaseQuery returns query to check that a database is a snapshot, standby or read-only copy
+func (qb *QueryBuilder) ReadOnlyDatabaseQuery(database string) (string, []interface{}, bool) {
+	query := qb.dialect.DatabaseInfo().ReadOnlyDatabase
+	if query == "" {
+		return "", nil, false
+	}
+	return query, []interface{}{qb.dialect.NormalizeIdentifier(database)}, true
+}
+
+// InDatabase rewrites a metadata query so it reads the catalog of another database.
+// SQL Server catalog views are prefixed with the database name; MySQL databases are
+// schemas, so the query is returned unchanged and the database is used as schema filter.
+func (qb *QueryBuilder) InDatabase(query, database string) string {
+	if database == "" || qb.driver != DriverSQLServer {
+		return query
+	}
+	prefix := qb.QuoteIdentifier(qb.dialect.NormalizeIdentifier(database)) + ".$1."
+	return reCatalogViews.ReplaceAllString(query, prefix)
+}
+
+// SearchObjectsQuery returns the query to search tables, views, procedures, functions and
+// triggers across all schemas. Every row has schema_name, object_name, object_type (as
+// reported by the database), create_date, modify_date, has_code and kind (table, view,
+// procedure, function or trigger).
 func (qb *QueryBuilder) SearchObjectsQuery(searchTerm string, searchInCode bool, objectTypes []string) (string, []interface{}) {
+	kinds := kindInList(objectTypes, SearchObjectKinds)
 	switch qb.driver {
 	case DriverSQLServer:
-		return qb.buildSQLServerSearchQuery(searchTerm, searchInCode, objectTypes)
+		return qb.buildSQLServerSearchQuery(searchTerm, searchInCode, kinds)
 	case DriverPostgresSQL:
-		return qb.buildPostgresSearchQuery(searchTerm, searchInCode, objectTypes)
+		return qb.buildPostgresSearchQuery(searchTerm, searchInCode, kinds)
 	case DriverMySQL:
-		return qb.buildMySQLSearchQuery(searchTerm, objectTypes)
+		return qb.buildMySQLSearchQuery(searchTerm, searchInCode, kinds)
 	case DriverOracle:
-		return qb.buildOracleSearchQuery(searchTerm, objectTypes)
+		return qb.buildOracleSearchQuery(searchTerm, searchInCode, kinds)
 	case DriverSQLite:
-		return qb.buildSQLiteSearchQuery(searchTerm, searchInCode, objectTypes)
+		return qb.buildSQLiteSearchQuery(searchTerm, searchInCode, kinds)
 	}
 	return "", nil
 }
 
-// -----------------------------------------------------------------------------
-// Search Query Builders (driver-specific due to complexity)
-// -----------------------------------------------------------------------------
-
-func (qb *QueryBuilder) buildSQLServerSearchQuery(searchTerm string, searchInCode bool, objectTypes []string) (string, []interface{}) {
-	typeMap := map[string]string{
-		"table":     "U",
-		"view":      "V",
-		"procedure": "P",
-		"function":  "FN",
-	}
-
-	var sqlTypes []string
-	if len(objectTypes) > 0 {
-		for _, ot := range objectTypes {
-			if sqlType, exists := typeMap[ot]; exists {
-				sqlTypes = append(sqlTypes, sqlType)
+// SearchObjectKinds are the object kinds returned by search_objects
+var SearchObjectKinds = []string{"table", "view", "procedure", "function", "trigger"}
+
+// DefinitionKinds are the object kinds whose source search_definitions reads
+// (packages are Oracle only)
+var DefinitionKinds = []string{"view", "procedure", "function", "trigger", "package"}
+
+// kindInList returns the quoted IN list of the requested kinds found in allowed,
+// ignoring unknown kinds and defaulting to all allowed kinds
+func kindInList(requested, allowed []string) string {
+	var kinds []string
+	for _, kind := range allowed {
+		for _, r := range requested {
+			if strings.EqualFold(r, kind) {
+				kinds = append(kinds, kind)
+				break
 			}
 		}
 	}
-	if len(sqlTypes) == 0 {
-		for _, sqlType := range typeMap {
-			sqlTypes = append(sqlTypes, sqlType)
-		}
+	if len(kinds) == 0 {
+		kinds = allowed
 	}
+	return "'" + strings.Join(kinds, "', '") + "'"
+}
 
-	typeInClause := "'" + strings.Join(sqlTypes, "', '") + "'"
+// -----------------------------------------------------------------------------
+// Search Query Builders (driver-specific due to complexity)
+// -----------------------------------------------------------------------------
 
+func (qb *QueryBuilder) buildSQLServerSearchQuery(searchTerm string, searchInCode bool, kinds string) (string, []interface{}) {
 	searchInCodeClause := ""
 	if searchInCode {
 		searchInCodeClause = "OR (m.definition IS NOT NULL AND m.definition LIKE '%' + @p1 + '%')"
 	}
 
 	query := fmt.Sprintf(`
-		SELECT DISTINCT
-			s.name AS schema_name,
-			o.name AS object_name,
-			o.type_desc AS object_type,
-			o.create_date,
-			o.modify_date,
-			CASE WHEN m.definition IS NOT NULL THEN 1 ELSE 0 END AS has_code
-		FROM sys.objects o
-		INNER JOIN sys.schemas s ON o.schema_id = s.schema_id
-		LEFT JOIN sys.sql_modules m ON o.object_id = m.object_id
-		WHERE o.type IN (%s)
-		  AND (o.name LIKE '%%' + @p1 + '%%' %s)
-		ORDER BY s.name, o.name`, typeInClause, searchInCodeClause)
+		SELECT schema_name, object_name, object_type, create_date, modify_date, has_code, kind
+		FROM (
+			SELECT
+				s.name AS schema_name,
+				o.name AS object_name,
+				o.type_desc AS object_type,
+				o.create_date,
+				o.modify_date,
+				CASE WHEN m.definition IS NOT NULL THEN 1 ELSE 0 END AS has_code,
+				CASE
+					WHEN o.type = 'U' THEN 'table'
+					WHEN o.type = 'V' THEN 'view'
+					WHEN o.type IN ('P', 'PC') THEN 'procedure'
+					WHEN o.type IN ('TR', 'TA') THEN 'trigger'
+					ELSE 'function'
+				END AS kind
+			FROM sys.objects o
+			INNER JOIN sys.schemas s ON o.schema_id = s.schema_id
+			LEFT JOIN sys.sql_modules m ON o.object_id = m.object_id
+			WHERE o.type IN ('U', 'V', 'P', 'PC', 'FN', 'IF', 'TF', 'FS', 'FT', 'TR', 'TA')
+			  AND o.is_ms_shipped = 0
+			  AND (o.name LIKE '%%' + @p1 + '%%' %s)
+		) found
+		WHERE kind IN (%s)
+		ORDER BY schema_name, object_name, kind`, searchInCodeClause, kinds)
 
 	return query, []interface{}{searchTerm}
 }
 
-func (qb *QueryBuilder) buildPostgresSearchQuery(searchTerm string, searchInCode bool, objectTypes []string) (string, []interface{}) {
-	objectTypeFilter := ""
-	if len(objectTypes) > 0 {
-		var types []string
-		for _, ot := range objectTypes {
-			switch ot {
-			case "table":
-				types = append(types, "'BASE TABLE'")
-			case "view":
-				types = append(types, "'VIEW'")
-			}
-		}
-		if len(types) > 0 {
-			objectTypeFilter = " AND table_type IN (" + strings.Join(types, ",") + ")"
-		}
-	}
-
+func (qb *QueryBuilder) buildPostgresSearchQuery(searchTerm string, searchInCode bool, kinds string) (string, []interface{}) {
 	searchInCodeClause := ""
 	if searchInCode {
-		searchInCodeClause = " OR view_definition LIKE '%' || $1 || '%'"
+		searchInCodeClause = " OR code ILIKE '%' || $1 || '%'"
 	}
 
 	query := fmt.Sprintf(`
-		SELECT
-			table_schema AS schema_name,
-			table_name AS object_name,
-			table_type AS object_type,
-			NULL AS create_date,
-			NULL AS modify_date,
-			CASE WHEN view_definition IS NOT NULL THEN 1 ELSE 0 END AS has_code
-		FROM information_schema.tables
-		LEFT JOIN information_schema.views USING (table_schema, table_name)
-		WHERE table_schema NOT IN ('pg_catalog', 'information_schema')
-		  AND (table_name LIKE '%%' || $1 || '%%' %s)
-		  %s
-		ORDER BY table_schema, table_name`, searchInCodeClause, objectTypeFilter)
+		SELECT schema_name, object_name, object_type, create_date, modify_date, has_code, kind
+		FROM (
+			SELECT
+				t.table_schema AS schema_name,
+				t.table_name AS object_name,
+				t.table_type AS object_type,
+				NULL::timestamp AS create_date,
+				NULL::timestamp AS modify_date,
+				CASE WHEN v.view_definition IS NOT NULL THEN 1 ELSE 0 END AS has_code,
+				CASE WHEN t.table_type = 'VIEW' THEN 'view' ELSE 'table' END AS kind,
+				v.view_definition AS code
+			FROM information_schema.tables t
+			LEFT JOIN information_schema.views v ON v.table_schema = t.table_schema AND v.table_name = t.table_name
+			UNION ALL
+			SELECT
+				n.nspname,
+				p.proname,
+				CASE WHEN p.prokind = 'p' THEN 'PROCEDURE' ELSE 'FUNCTION' END,
+				NULL::timestamp,
+				NULL::timestamp,
+				1,
+				CASE WHEN p.prokind = 'p' THEN 'procedure' ELSE 'function' END,
+				p.prosrc
+			FROM pg_proc p
+			INNER JOIN pg_namespace n ON n.oid = p.pronamespace
+			WHERE p.prokind IN ('f', 'p')
+			UNION ALL
+			SELECT
+				n.nspname,
+				tg.tgname,
+				'TRIGGER',
+				NULL::timestamp,
+				NULL::timestamp,
+				1,
+				'trigger',
+				pg_get_triggerdef(tg.oid)
+			FROM pg_trigger tg
+			INNER JOIN pg_class c ON c.oid = tg.tgrelid
+			INNER JOIN pg_namespace n ON n.oid = c.relnamespace
+			WHERE NOT tg.tgisinternal
+		) found
+		WHERE schema_name NOT IN ('pg_catalog', 'information_schema')
+		  AND (object_name ILIKE '%%' || $1 || '%%' %s)
+		  AND kind IN (%s)
+		ORDER BY schema_name, object_name, kind`, searchInCodeClause, kinds)
 
 	return query, []interface{}{searchTerm}
 }
 
-func (qb *QueryBuilder) buildMySQLSearchQuery(searchTerm string, objectTypes []string) (string, []interface{}) {
-	objectTypeFilter := ""
-	if len(objectTypes) > 0 {
-		var types []string
-		for _, ot := range objectTypes {
-			switch ot {
-			case "table":
-				types = append(types, "'BASE TABLE'")
-			case "view":
-				types = append(types, "'VIEW'")
-			}
-		}
-		if len(types) > 0 {
-			objectTypeFilter = " AND TABLE_TYPE IN (" + strings.Join(types, ",") + ")"
-		}
+func (qb *QueryBuilder) buildMySQLSearchQuery(searchTerm string, searchInCode bool, kinds string) (string, []interface{}) {
+	searchInCodeClause := ""
+	args := []interface{}{searchTerm}
+	if searchInCode {
+		searchInCodeClause = " OR code LIKE CONCAT('%', ?, '%')"
+		args = append(args, searchTerm)
 	}
 
 	query := fmt.Sprintf(`
-		SELECT
-			TABLE_SCHEMA AS schema_name,
-			TABLE_NAME AS object_name,
-			TABLE_TYPE AS object_type,
-			CREATE_TIME AS create_date,
-			UPDATE_TIME AS modify_date,
-			0 AS has_code
-		FROM INFORMATION_SCHEMA.TABLES
-		WHERE TABLE_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')
-		  AND TABLE_NAME LIKE CONCAT('%%', ?, '%%')
-		  %s
-		ORDER BY TABLE_SCHEMA, TABLE_NAME`, objectTypeFilter)
+		SELECT schema_name, object_name, object_type, create_date, modify_date, has_code, kind
+		FROM (
+			SELECT
+				t.TABLE_SCHEMA AS schema_name,
+				t.TABLE_NAME AS object_name,
+				t.TABLE_TYPE AS object_type,
+				t.CREATE_TIME AS create_date,
+				t.UPDATE_TIME AS modify_date,
+				CASE WHEN v.VIEW_DEFINITION IS NOT NULL THEN 1 ELSE 0 END AS has_code,
+				CASE WHEN t.TABLE_TYPE = 'VIEW' THEN 'view' ELSE 'table' END AS kind,
+				v.VIEW_DEFINITION AS code
+			FROM INFORMATION_SCHEMA.TABLES t
+			LEFT JOIN INFORMATION_SCHEMA.VIEWS v ON v.TABLE_SCHEMA = t.TABLE_SCHEMA AND v.TABLE_NAME = t.TABLE_NAME
+			UNION ALL
+			SELECT
+				ROUTINE_SCHEMA,
+				ROUTINE_NAME,
+				ROUTINE_TYPE,
+				CREATED,
+				LAST_ALTERED,
+				1,
+				LOWER(ROUTINE_TYPE),
+				ROUTINE_DEFINITION
+			FROM INFORMATION_SCHEMA.ROUTINES
+			UNION ALL
+			SELECT
+				TRIGGER_SCHEMA,
+				TRIGGER_NAME,
+				'TRIGGER',
+				CREATED,
+				NULL,
+				1,
+				'trigger',
+				ACTION_STATEMENT
+			FROM INFORMATION_SCHEMA.TRIGGERS
+		) found
+		WHERE schema_name NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')
+		  AND (object_name LIKE CONCAT('%%', ?, '%%') %s)
+		  AND kind IN (%s)
+		ORDER BY schema_name, object_name, kind`, searchInCodeClause, kinds)
 
-	return query, []interface{}{searchTerm}
+	return query, args
 }
 
-func (qb *QueryBuilder) buildOracleSearchQuery(searchTerm string, objectTypes []string) (string, []interface{}) {
-	objectTypeFilter := ""
-	if len(objectTypes) > 0 {
-		var types []string
-		for _, ot := range objectTypes {
-			switch ot {
-			case "table":
-				types = append(types, "'TABLE'")
-			case "view":
-				types = append(types, "'VIEW'")
-			case "procedure":
-				types = append(types, "'PROCEDURE'")
-			case "function":
-				types = append(types, "'FUNCTION'")
-			}
-		}
-		if len(types) > 0 {
-			objectTypeFilter = " AND object_type IN (" + strings.Join(types, ",") + ")"
-		}
+func (qb *QueryBuilder) buildOracleSearchQuery(searchTerm string, searchInCode bool, kinds string) (string, []interface{}) {
+	searchTerm = strings.ToUpper(searchTerm)
+
+	searchInCodeClause := ""
+	args := []interface{}{searchTerm}
+	if searchInCode {
+		// PL/SQL units keep their source in all_source; view text is a LONG and is not searched
+		searchInCodeClause = ` OR EXISTS (
+			SELECT 1 FROM all_source src
+			WHERE src.owner = o.owner AND src.name = o.object_name AND src.type = o.object_type
+			  AND UPPER(src.text) LIKE '%' || :2 || '%')`
+		args = append(args, searchTerm)
 	}
 
 	query := fmt.Sprintf(`
 		SELECT
-			owner AS schema_name,
-			object_name,
-			object_type,
-			created AS create_date,
-			last_ddl_time AS modify_date,
-			0 AS has_code
-		FROM all_objects
-		WHERE owner NOT IN ('SYS', 'SYSTEM')
-		  AND object_name LIKE '%%' || :1 || '%%'
-		  %s
-		ORDER BY owner, object_name`, objectTypeFilter)
-
-	return query, []interface{}{strings.ToUpper(searchTerm)}
-}
-
-func (qb *QueryBuilder) buildSQLiteSearchQuery(searchTerm string, searchInCode bool, objectTypes []string) (string, []interface{}) {
-	objectTypeFilter := ""
-	if len(objectTypes) > 0 {
-		var types []string
-		for _, ot := range objectTypes {
-			switch ot {
-			case "table":
-				types = append(types, "'table'")
-			case "view":
-				types = append(types, "'view'")
-			case "trigger":
-				types = append(types, "'trigger'")
-			}
-		}
-		if len(types) > 0 {
-			objectTypeFilter = " AND type IN (" + strings.Join(types, ",") + ")"
-		}
-	}
+			o.owner AS schema_name,
+			o.object_name,
+			o.object_type,
+			o.created AS create_date,
+			o.last_ddl_time AS modify_date,
+			CASE WHEN o.object_type = 'TABLE' THEN 0 ELSE 1 END AS has_code,
+			LOWER(o.object_type) AS kind
+		FROM all_objects o
+		WHERE o.owner NOT IN ('SYS', 'SYSTEM')
+		  AND LOWER(o.object_type) IN (%s)
+		  AND (o.object_name LIKE '%%' || :1 || '%%' %s)
+		ORDER BY o.owner, o.object_name, o.object_type`, kinds, searchInCodeClause)
 
+	return query, args
+}
+
+func (qb *QueryBuilder) buildSQLiteSearchQuery(searchTerm string, searchInCode bool, kinds string) (string, []interface{}) {
 	searchInCodeClause := ""
 	if searchInCode {
 		searchInCodeClause = " OR sql LIKE '%' || ? || '%'"
@@ -699,12 +1714,13 @@ func (qb *QueryBuilder) buildSQLiteSearchQuery(searchTerm string, searchInCode b
 			type AS object_type,
 			NULL AS create_date,
 			NULL AS modify_date,
-			CASE WHEN sql IS NOT NULL THEN 1 ELSE 0 END AS has_code
+			CASE WHEN sql IS NOT NULL THEN 1 ELSE 0 END AS has_code,
+			type AS kind
 		FROM sqlite_master
 		WHERE name NOT LIKE 'sqlite_%%'
+		  AND type IN (%s)
 		  AND (name LIKE '%%' || ? || '%%' %s)
-		  %s
-		ORDER BY name`, searchInCodeClause, objectTypeFilter)
+		ORDER BY name`, kinds, searchInCodeClause)
 
 	if searchInCode {
 		return query, []interface{}{searchTerm, searchTerm}
@@ -718,7 +1734,7 @@ func (qb *QueryBuilder) buildSQLiteSearchQuery(searchTerm string, searchInCode b
 
 // BuildSelectQuery builds a SELECT query with pagination based on the driver
 func (qb *QueryBuilder) BuildSelectQuery(params SelectQueryParams) string {
-	qualifiedTable := qb.QualifyTable(params.Schema, params.Table)
+	qualifiedTable := qb.tableAsOf(params.Schema, params.Table, params.AsOf)
 
 	var quotedColumns []string
 	for _, col := range params.Columns {
@@ -726,15 +1742,124 @@ func (qb *QueryBuilder) BuildSelectQuery(params SelectQueryParams) string {
 	}
 	columnsStr := strings.Join(quotedColumns, ", ")
 
-	quotedOrderBy := qb.QuoteIdentifier(params.OrderBy)
-	orderClause := fmt.Sprintf("%s %s", quotedOrderBy, params.OrderDirection)
+	orderColumns := []string{fmt.Sprintf("%s %s", qb.QuoteIdentifier(params.OrderBy), params.OrderDirection)}
+	for _, col := range params.TieBreakers {
+		if !strings.EqualFold(col, params.OrderBy) {
+			orderColumns = append(orderColumns, fmt.Sprintf("%s %s", qb.QuoteIdentifier(col), params.OrderDirection))
+		}
+	}
+	orderClause := strings.Join(orderColumns, ", ")
 
 	baseQuery := fmt.Sprintf(`SELECT %s FROM %s %s`, columnsStr, qualifiedTable, params.WhereClause)
 	return qb.appendPaginationClause(baseQuery, orderClause, params.Limit, params.Offset)
 }
 
+// BuildDistinctValuesQuery builds the query of a page of the distinct values of a column with
+// their row counts, most frequent first. NULL is one of the values.
+func (qb *QueryBuilder) BuildDistinctValuesQuery(schema, table, column string, limit, offset int) string {
+	quoted := qb.QuoteIdentifier(column)
+	baseQuery := fmt.Sprintf("SELECT %s AS distinct_value, COUNT(*) AS frequency FROM %s GROUP BY %s", quoted, qb.QualifyTable(schema, table), quoted)
+	return qb.appendPaginationClause(baseQuery, "COUNT(*) DESC, "+quoted, limit, offset)
+}
+
+// BuildDistinctCountQuery builds the query counting the distinct values of a column,
+// counting NULL as a value
+func (qb *QueryBuilder) BuildDistinctCountQuery(schema, table, column string) string {
+	quoted := qb.QuoteIdentifier(column)
+	return fmt.Sprintf("SELECT COUNT(*) FROM (SELECT %s FROM %s GROUP BY %s) distinct_values", quoted, qb.QualifyTable(schema, table), quoted)
+}
+
+// BuildAggregateQuery builds a GROUP BY query of the aggregates of a table, one row per
+// group, or a single row without group columns
+func (qb *QueryBuilder) BuildAggregateQuery(params AggregateQueryParams) string {
+	var selectColumns, groupColumns []string
+	for _, col := range params.GroupBy {
+		groupColumns = append(groupColumns, qb.QuoteIdentifier(col))
+	}
+	selectColumns = append(selectColumns, groupColumns...)
+
+	// Sorted by the expression rather than the alias, which not every dialect accepts
+	var orderColumns []string
+	for _, agg := range params.Aggregates {
+		expression := qb.AggregateExpression(agg)
+		selectColumns = append(selectColumns, fmt.Sprintf("%s AS %s", expression, qb.QuoteIdentifier(agg.Alias)))
+		if strings.EqualFold(agg.Alias, params.OrderBy) {
+			orderColumns = append(orderColumns, fmt.Sprintf("%s %s", expression, params.OrderDirection))
+		}
+	}
+	for _, col := range params.GroupBy {
+		if strings.EqualFold(col, params.OrderBy) {
+			orderColumns = append([]string{fmt.Sprintf("%s %s", qb.QuoteIdentifier(col), params.OrderDirection)}, orderColumns...)
+		}
+	}
+	for _, col := range params.GroupBy {
+		if !strings.EqualFold(col, params.OrderBy) {
+			orderColumns = append(orderColumns, fmt.Sprintf("%s %s", qb.QuoteIdentifier(col), params.OrderDirection))
+		}
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s %s", strings.Join(selectColumns, ", "), qb.QualifyTable(params.Schema, params.Table), params.WhereClause)
+	if len(groupColumns) > 0 {
+		query = strings.TrimSpace(query) + " GROUP BY " + strings.Join(groupColumns, ", ")
+	}
+	return qb.appendPaginationClause(strings.TrimSpace(query), strings.Join(orderColumns, ", "), params.Limit, 0)
+}
+
+// AggregateExpression returns the SQL expression of an aggregate function of a column
+func (qb *QueryBuilder) AggregateExpression(agg AggregateColumn) string {
+	if agg.Column == "" {
+		return "COUNT(*)"
+	}
+	quoted := qb.QuoteIdentifier(agg.Column)
+	switch agg.Function {
+	case "count_distinct":
+		return fmt.Sprintf("COUNT(DISTINCT %s)", quoted)
+	case "avg":
+		// SQL Server averages integers as integers, truncating the result
+		if qb.IsSQLServer() {
+			return fmt.Sprintf("AVG(%s * 1.0)", quoted)
+		}
+		return fmt.Sprintf("AVG(%s)", quoted)
+	default:
+		return fmt.Sprintf("%s(%s)", strings.ToUpper(agg.Function), quoted)
+	}
+}
+
 // BuildCountQuery builds a COUNT query
 func (qb *QueryBuilder) BuildCountQuery(schema, table, whereClause string) string {
-	qualifiedTable := qb.QualifyTable(schema, table)
+	return qb.BuildCountQueryAsOf(schema, table, whereClause, nil)
+}
+
+// BuildCountQueryAsOf builds a COUNT query reading the table as it was at asOf, or as it is
+// now when asOf is nil
+func (qb *QueryBuilder) BuildCountQueryAsOf(schema, table, whereClause string, asOf *time.Time) string {
+	qualifiedTable := qb.tableAsOf(schema, table, asOf)
 	return fmt.Sprintf("SELECT COUNT(*) FROM %s %s", qualifiedTable, whereClause)
 }
+
+// tableAsOf returns the qualified table name followed by the time-travel clause when asOf is given
+func (qb *QueryBuilder) tableAsOf(schema, table string, asOf *time.Time) string {
+	qualifiedTable := qb.QualifyTable(schema, table)
+	if asOf == nil {
+		return qualifiedTable
+	}
+	return qualifiedTable + " " + qb.dialect.AsOfClause(*asOf)
+}
+
+// SupportsTimeTravel reports whether tables can be read as they were at a point in time
+func (qb *QueryBuilder) SupportsTimeTravel() bool {
+	return qb.dialect.AsOfClause(time.Time{}) != ""
+}
+
+// TimeTravelQuery returns the query for whether a table is versioned and its history
+// retention, or false if every table can be read at a point in time
+func (qb *QueryBuilder) TimeTravelQuery(schema, tableName string) (string, []interface{}, bool) {
+	meta := qb.dialect.TableMetadata()
+	if meta.TimeTravel == "" {
+		return "", nil, false
+	}
+	return meta.TimeTravel, []interface{}{
+		qb.dialect.NormalizeIdentifier(schema),
+		qb.dialect.NormalizeIdentifier(tableName),
+	}, true
+}
@@ -0,0 +1,176 @@
+package mcp
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// queryCache keeps the responses of recent execute_query calls, so the same query run
+// again within DB_QUERY_CACHE_TTL is answered from memory instead of the database. Once
+// DB_QUERY_CACHE_SIZE responses are kept, the least recently used one is evicted. The
+// cache is disabled unless DB_QUERY_CACHE_TTL is set.
+type queryCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	size    int
+	entries map[string]*list.Element
+	order   *list.List // most recently used first
+	hits    int64
+	misses  int64
+}
+
+// cachedQuery is the response of an execute_query call kept in the query cache
+type cachedQuery struct {
+	key      string
+	response map[string]interface{}
+	stored   time.Time
+}
+
+// newQueryCache returns a query cache configured from the environment
+func newQueryCache() *queryCache {
+	return &queryCache{
+		ttl:     envDuration("DB_QUERY_CACHE_TTL", 0),
+		size:    int(envInt("DB_QUERY_CACHE_SIZE", DefaultQueryCacheSize)),
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// enabled reports whether responses are cached
+func (c *queryCache) enabled() bool {
+	return c.ttl > 0 && c.size > 0
+}
+
+// get returns a cached response that has not expired, with the time it was stored
+func (c *queryCache) get(key string) (map[string]interface{}, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if ok && time.Since(element.Value.(*cachedQuery).stored) > c.ttl {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		ok = false
+	}
+	if !ok {
+		c.misses++
+		return nil, time.Time{}, false
+	}
+
+	c.hits++
+	c.order.MoveToFront(element)
+	entry := element.Value.(*cachedQuery)
+	return entry.response, entry.stored, true
+}
+
+// put stores a response, evicting the least recently used one when the cache is full
+func (c *queryCache) put(key string, response map[string]interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+	for c.order.Len() >= c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedQuery).key)
+	}
+	c.entries[key] = c.order.PushFront(&cachedQuery{key: key, response: response, stored: time.Now()})
+}
+
+// clear removes every cached response and returns how many there were
+func (c *queryCache) clear() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cleared := c.order.Len()
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+	return cleared
+}
+
+// stats returns the size and hit counts of the cache
+func (c *queryCache) stats() map[string]interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return map[string]interface{}{
+		"enabled":     c.enabled(),
+		"ttl_seconds": int(c.ttl / time.Second),
+		"entries":     c.order.Len(),
+		"max_entries": c.size,
+		"hits":        c.hits,
+		"misses":      c.misses,
+	}
+}
+
+// queryCacheKey returns the cache key of an execute_query call: the query with its spacing
+// normalized, the bound values, the connection and every argument that changes the response
+func queryCacheKey(query string, params []interface{}, maxRows int, connection, database, format string, header bool) string {
+	raw, _ := json.Marshal([]interface{}{normalizeCacheSQL(query), params, maxRows, connection, database, format, header})
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeCacheSQL collapses runs of whitespace and drops a trailing semicolon, leaving
+// quoted literals and identifiers untouched so queries that differ in them never share a key
+func normalizeCacheSQL(query string) string {
+	var b strings.Builder
+	var quote rune
+	space := false
+	for _, r := range strings.TrimRight(strings.TrimSpace(query), "; \t\r\n") {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '[':
+			quote = ']'
+		case unicode.IsSpace(r):
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// cachedQueryResponse returns a copy of a cached response marked as served from the cache
+func cachedQueryResponse(response map[string]interface{}, stored time.Time) map[string]interface{} {
+	cached := make(map[string]interface{}, len(response)+2)
+	for key, value := range response {
+		cached[key] = value
+	}
+	cached["cached"] = true
+	cached["cached_at"] = stored.Format(time.RFC3339)
+	return cached
+}
+
+func (s *DbMCPServer) toolClearQueryCache() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("clear_query_cache", "Clears the execute_query result cache, so the next queries read the database again. Use it after data changed, or pass cache: false to execute_query to refresh a single query", s.handleClearQueryCache)
+}
+
+func (s *DbMCPServer) handleClearQueryCache(ctx context.Context, request mcp.CallToolRequest, _ noArgs) (*mcp.CallToolResult, error) {
+	return jsonToolResult(map[string]interface{}{
+		"cleared": s.queryCache.clear(),
+		"enabled": s.queryCache.enabled(),
+	}), nil
+}
@@ -0,0 +1,182 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// queryHistory records the queries the watchdog tracked once they finish, so
+// get_query_history can show after the fact what an agent ran, how long it took and how it
+// ended. It is kept in memory and holds the last DB_QUERY_HISTORY_SIZE queries since the
+// server started; a size of 0 disables it.
+type queryHistory struct {
+	mu      sync.Mutex
+	size    int
+	entries []queryHistoryEntry
+	next    int
+}
+
+// queryHistoryEntry is a finished query. Query is normalized as for its fingerprint, so
+// the literals it held are not kept.
+type queryHistoryEntry struct {
+	ID          uint64    `json:"id"`
+	Tool        string    `json:"tool"`
+	Fingerprint string    `json:"fingerprint"`
+	Query       string    `json:"query"`
+	StartedAt   time.Time `json:"started_at"`
+	DurationMs  int64     `json:"duration_ms"`
+	Rows        int64     `json:"rows"`
+	Status      string    `json:"status"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// newQueryHistory returns an empty query history of size entries
+func newQueryHistory(size int) *queryHistory {
+	return &queryHistory{size: size}
+}
+
+// add appends a query, overwriting the oldest once the history is full
+func (h *queryHistory) add(entry queryHistoryEntry) {
+	if h == nil || h.size <= 0 {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.entries) < h.size {
+		h.entries = append(h.entries, entry)
+		return
+	}
+	h.entries[h.next] = entry
+	h.next = (h.next + 1) % h.size
+}
+
+// newestFirst returns a copy of the recorded queries, newest first
+func (h *queryHistory) newestFirst() []queryHistoryEntry {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entries := make([]queryHistoryEntry, 0, len(h.entries))
+	for i := len(h.entries) - 1; i >= 0; i-- {
+		entries = append(entries, h.entries[(h.next+i)%len(h.entries)])
+	}
+	return entries
+}
+
+// historyEntry returns the history entry of a finished query
+func (q *watchedQuery) historyEntry() queryHistoryEntry {
+	normalized := normalizeQuery(q.query)
+	if len(normalized) > MaxQueryHistoryQueryBytes {
+		normalized = normalized[:MaxQueryHistoryQueryBytes] + "..."
+	}
+
+	entry := queryHistoryEntry{
+		ID:          q.id,
+		Tool:        q.tool,
+		Fingerprint: queryFingerprint(q.query),
+		Query:       normalized,
+		StartedAt:   q.started.UTC(),
+		DurationMs:  time.Since(q.started).Milliseconds(),
+		Rows:        q.rows.Load(),
+		Status:      "ok",
+	}
+	if err := q.Err(); err != nil {
+		entry.Status = "killed"
+		if q.cancelled.Load() {
+			entry.Status = "cancelled"
+		}
+		entry.Error = err.Error()
+	} else if err, ok := q.failure.Load().(error); ok {
+		entry.Status = "error"
+		entry.Error = err.Error()
+	}
+	return entry
+}
+
+// getQueryHistoryArgs are the arguments of get_query_history
+type getQueryHistoryArgs struct {
+	Tool          string `json:"tool,omitempty" jsonschema_description:"Only the queries of this tool, e.g. execute_query (optional)"`
+	Status        string `json:"status,omitempty" jsonschema_description:"Only the queries that ended this way: ok, error, killed or cancelled (optional)" jsonschema:"enum=ok,enum=error,enum=killed,enum=cancelled"`
+	Fingerprint   string `json:"fingerprint,omitempty" jsonschema_description:"Only the queries with this fingerprint (optional)"`
+	Contains      string `json:"contains,omitempty" jsonschema_description:"Only the queries whose normalized text contains this text, case-insensitive (optional)"`
+	Window        string `json:"window,omitempty" jsonschema_description:"Only the queries started in this last period, as a duration such as 30m or 24h (optional, default: since the server started)"`
+	MinDurationMs int64  `json:"min_duration_ms,omitempty" jsonschema_description:"Only the queries that ran at least this many milliseconds (optional)"`
+	Limit         int    `json:"limit,omitempty" jsonschema_description:"Maximum queries to return (default: 50)"`
+}
+
+func (s *DbMCPServer) toolGetQueryHistory() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("get_query_history", "Returns the queries this server ran and finished, newest first: the tool, the normalized SQL without its literals, a fingerprint shared by queries that differ only in literals, the start time, duration, rows streamed and status (ok, error, killed or cancelled) with the error. Use it to review what was run after the fact. Keeps the last DB_QUERY_HISTORY_SIZE queries since the server started", s.handleGetQueryHistory)
+}
+
+func (s *DbMCPServer) handleGetQueryHistory(ctx context.Context, request mcp.CallToolRequest, args getQueryHistoryArgs) (*mcp.CallToolResult, error) {
+	status := strings.ToLower(args.Status)
+	switch status {
+	case "", "ok", "error", "killed", "cancelled":
+	default:
+		return toolErrorResult(ErrInvalidQueryStatus), nil
+	}
+
+	from := time.Time{}
+	if args.Window != "" {
+		window, err := time.ParseDuration(args.Window)
+		if err != nil || window <= 0 {
+			return toolErrorResult(ErrInvalidWindow), nil
+		}
+		from = time.Now().Add(-window)
+	}
+
+	limit := args.Limit
+	if limit < 1 {
+		limit = DefaultQueryHistoryLimit
+	}
+
+	contains := strings.ToLower(args.Contains)
+	queries := []queryHistoryEntry{}
+	matched := 0
+	for _, entry := range s.watchdog.historyNewestFirst() {
+		if (args.Tool != "" && !strings.EqualFold(entry.Tool, args.Tool)) ||
+			(status != "" && entry.Status != status) ||
+			(args.Fingerprint != "" && !strings.EqualFold(entry.Fingerprint, args.Fingerprint)) ||
+			(contains != "" && !strings.Contains(strings.ToLower(entry.Query), contains)) ||
+			entry.StartedAt.Before(from) ||
+			entry.DurationMs < args.MinDurationMs {
+			continue
+		}
+		matched++
+		if len(queries) < limit {
+			queries = append(queries, entry)
+		}
+	}
+
+	return jsonToolResult(map[string]interface{}{
+		"queries":      queries,
+		"count":        len(queries),
+		"matched":      matched,
+		"truncated":    matched > len(queries),
+		"history_size": s.watchdog.historySize(),
+	}), nil
+}
+
+// historyNewestFirst returns the finished queries of the watchdog, newest first
+func (w *queryWatchdog) historyNewestFirst() []queryHistoryEntry {
+	if w == nil {
+		return nil
+	}
+	return w.history.newestFirst()
+}
+
+// historySize returns the number of queries the history keeps
+func (w *queryWatchdog) historySize() int {
+	if w == nil || w.history == nil {
+		return 0
+	}
+	return w.history.size
+}
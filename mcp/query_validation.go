@@ -1,325 +1,953 @@
 package mcp
 
 import (
+	"errors"
 	"fmt"
-	"regexp"
+	"slices"
 	"strings"
+	"unicode"
 )
 
+// NewSQLValidator returns the validator of a query for any database, which must pass the
+// built-in checks with the quoting and comment rules of each one
 func NewSQLValidator(query string) *SQLValidator {
-	return &SQLValidator{
-		query:      query,
-		normalized: normalizeSQL(query),
+	return newDriverSQLValidator(query, "", defaultValidationPolicy(), nil)
+}
+
+// newDriverSQLValidator returns the validator of a query for a database, which reads its
+// strings and comments as that database does and allows its read statements, with the
+// checks of a policy and, when objects is not nil, only reading the tables it exposes
+func newDriverSQLValidator(query string, driver DriverType, policy *validationPolicy, objects *objectFilter) *SQLValidator {
+	return &SQLValidator{query: query, driver: driver, rules: sqlLexRulesFor(driver), policy: policy, objects: objects}
+}
+
+// driverStatements are the read statements of each database allowed besides SELECT and WITH,
+// unless the validation policy lists the allowed statements
+var driverStatements = map[DriverType][]string{
+	DriverPostgresSQL: {"SHOW", "EXPLAIN"},
+	DriverMySQL:       {"SHOW", "DESCRIBE", "DESC", "EXPLAIN"},
+	DriverSQLite:      {"EXPLAIN", "PRAGMA"},
+}
+
+// pragmaArgument is what a SQLite PRAGMA reading the schema takes between parentheses
+type pragmaArgument int
+
+const (
+	pragmaNoArgument pragmaArgument = iota // PRAGMA user_version
+	pragmaTable                            // PRAGMA table_info(name)
+	pragmaIndex                            // PRAGMA index_info(name)
+)
+
+// readPragmas are the SQLite pragmas a query may run. With an argument or a value, most
+// other pragmas change the database or the connection, and so do these: only their reading
+// form is accepted.
+var readPragmas = map[string]pragmaArgument{
+	"TABLE_INFO":       pragmaTable,
+	"TABLE_XINFO":      pragmaTable,
+	"INDEX_LIST":       pragmaTable,
+	"FOREIGN_KEY_LIST": pragmaTable,
+	"INDEX_INFO":       pragmaIndex,
+	"INDEX_XINFO":      pragmaIndex,
+	"TABLE_LIST":       pragmaNoArgument,
+	"DATABASE_LIST":    pragmaNoArgument,
+	"COLLATION_LIST":   pragmaNoArgument,
+	"FUNCTION_LIST":    pragmaNoArgument,
+	"MODULE_LIST":      pragmaNoArgument,
+	"PRAGMA_LIST":      pragmaNoArgument,
+	"COMPILE_OPTIONS":  pragmaNoArgument,
+	"USER_VERSION":     pragmaNoArgument,
+	"SCHEMA_VERSION":   pragmaNoArgument,
+	"APPLICATION_ID":   pragmaNoArgument,
+	"ENCODING":         pragmaNoArgument,
+	"PAGE_SIZE":        pragmaNoArgument,
+	"PAGE_COUNT":       pragmaNoArgument,
+	"FREELIST_COUNT":   pragmaNoArgument,
+	"DATA_VERSION":     pragmaNoArgument,
+}
+
+// allowsStatement reports whether a query may start with the keyword: a statement of the
+// policy or, while the policy does not list them, a read statement of the database
+func (v *SQLValidator) allowsStatement(keyword string) bool {
+	if v.policy.allowsStatement(keyword) {
+		return true
 	}
+	if len(v.policy.AllowedStatements) > 0 {
+		return false
+	}
+	for _, statement := range driverStatements[v.driver] {
+		if strings.EqualFold(statement, keyword) {
+			return true
+		}
+	}
+	return false
 }
 
-// Normalizes SQL by removing extra spaces and comments while maintaining structure.
-func normalizeSQL(sql string) string {
-	// Remove line comments (-- )
-	sql = reLineComments.ReplaceAllString(sql, " ")
+// allowedStatements returns the statements a query may start with, for errors
+func (v *SQLValidator) allowedStatements() []string {
+	var statements []string
+	for _, statement := range append(append([]string{}, defaultPolicyStatements...), driverStatements[v.driver]...) {
+		if v.allowsStatement(statement) {
+			statements = append(statements, statement)
+		}
+	}
+	return statements
+}
 
-	// Remove block comments (/* */)
-	sql = reBlockComments.ReplaceAllString(sql, " ")
+// validatePragma checks that a PRAGMA statement only reads the schema: PRAGMA [schema.]name,
+// with a table or index name between parentheses for the pragmas taking one
+func validatePragma(tokens []sqlToken) error {
+	i := 1
+	if i+1 < len(tokens) && tokens[i+1].text == "." {
+		i += 2
+	}
+	name := firstWord(tokens, i)
+	argument, ok := readPragmas[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrPragmaNotAllowed, name)
+	}
+	rest := tokens[i+1:]
+	switch {
+	case argument == pragmaNoArgument && len(rest) == 0:
+		return nil
+	case argument != pragmaNoArgument && len(rest) == 3 && rest[0].text == "(" && rest[2].text == ")" && rest[1].kind != sqlPunctuation && rest[1].kind != sqlParameter:
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrPragmaNotAllowed, name)
+}
 
-	// Normalize multiple spaces
-	sql = reMultipleSpaces.ReplaceAllString(sql, " ")
+// newSQLValidator returns the validator of a query for the connected database, or for any
+// database without a connection, with the validation policy and exposed objects of the server
+func (s *DbMCPServer) newSQLValidator(query string) *SQLValidator {
+	if s.queryBuilder == nil {
+		return newDriverSQLValidator(query, "", s.validationPolicy, s.objects)
+	}
+	return newDriverSQLValidator(query, s.queryBuilder.GetDriver(), s.validationPolicy, s.objects)
+}
 
-	// Remove spaces before/after parentheses and commas
-	sql = reParensAndCommas.ReplaceAllString(sql, "$1")
+// blockedKeywords are the words of the statements a query must not run, by the error they
+// report. Words are matched outside strings, quoted identifiers and comments, and a pair of
+// words matches when they follow each other.
+var blockedKeywords = []struct {
+	err      error
+	keywords []string
+}{
+	{ErrCommandNotAllowed, []string{"INSERT", "UPDATE", "DELETE", "TRUNCATE", "MERGE"}},
+	{ErrCommandNotAllowed, []string{"DROP", "CREATE", "ALTER", "RENAME"}},
+	{ErrCommandNotAllowed, []string{"EXEC", "EXECUTE", "SP_EXECUTESQL", "XP_CMDSHELL"}},
+	{ErrTransactionNotAllowed, []string{"BEGIN TRANSACTION", "BEGIN TRAN", "COMMIT", "ROLLBACK", "SAVE TRANSACTION", "SAVE TRAN"}},
+	{ErrCommandNotAllowed, []string{"BACKUP", "RESTORE", "DUMP"}},
+	{ErrAdminCommandNotAllowed, []string{"SHUTDOWN", "RECONFIGURE", "DBCC", "KILL"}},
+	{ErrSecurityCommandNotAllowed, []string{"GRANT", "REVOKE", "DENY"}},
+}
 
-	return strings.TrimSpace(strings.ToUpper(sql))
+// lockingClauses are the clauses that make a SELECT lock the rows it reads: FOR UPDATE and
+// FOR SHARE with their Postgres variants, and the LOCK IN SHARE MODE of MySQL
+var lockingClauses = [][]string{
+	{"FOR", "UPDATE"},
+	{"FOR", "NO", "KEY", "UPDATE"},
+	{"FOR", "SHARE"},
+	{"FOR", "KEY", "SHARE"},
+	{"LOCK", "IN", "SHARE", "MODE"},
 }
 
-// Remove literal strings for command parsing
-func removeStringLiterals(sql string) string {
-	// Remove strings enclosed in single quotes
-	sql = reSingleQuotes.ReplaceAllString(sql, "''")
+// lockingHints are the SQL Server table hints that take update or exclusive locks, or shared,
+// page or table locks held until the end of the transaction: queries run in one, so these
+// would block writers until it is rolled back
+var lockingHints = map[string]bool{
+	"UPDLOCK":        true,
+	"XLOCK":          true,
+	"TABLOCKX":       true,
+	"HOLDLOCK":       true,
+	"SERIALIZABLE":   true,
+	"REPEATABLEREAD": true,
+	"TABLOCK":        true,
+	"PAGLOCK":        true,
+}
 
-	// Remove strings enclosed in double quotes
-	sql = reDoubleQuotes.ReplaceAllString(sql, `""`)
+// matchWords reports whether the tokens from i are the words, in any case
+func matchWords(tokens []sqlToken, i int, words []string) bool {
+	if i+len(words) > len(tokens) {
+		return false
+	}
+	for j, word := range words {
+		if !tokens[i+j].is(word) {
+			return false
+		}
+	}
+	return true
+}
 
-	// Remove strings enclosed in square brackets (SQL Server identifiers)
-	sql = reSquareBrackets.ReplaceAllString(sql, "[]")
+// dataModifyingKeywords are the statements that change data, which a WITH query must not run
+var dataModifyingKeywords = map[string]bool{
+	"INSERT": true,
+	"UPDATE": true,
+	"DELETE": true,
+	"MERGE":  true,
+}
 
-	return sql
+// withStatement is a statement of a WITH query: the body of a common table expression, or the
+// statement after them when name is empty. keyword is its first word, in upper case, and at
+// its index in the tokens.
+type withStatement struct {
+	name    string
+	keyword string
+	at      int
 }
 
-// Verifies if the consultation is secure.
-func (v *SQLValidator) Validate() error {
-	// 1. Check if it's not empty
-	if strings.TrimSpace(v.query) == "" {
-		return ErrQueryEmpty
+// withStatements returns the statements of the WITH queries of a query, nested ones
+// included: WITH [RECURSIVE] name [(columns)] AS [[NOT] MATERIALIZED] (body), ... statement
+func withStatements(tokens []sqlToken) []withStatement {
+	var statements []withStatement
+	for i, token := range tokens {
+		if !token.is("WITH") {
+			continue
+		}
+		j := i + 1
+		if j < len(tokens) && tokens[j].is("RECURSIVE") {
+			j++
+		}
+		for j < len(tokens) && (tokens[j].kind == sqlWord || tokens[j].kind == sqlQuotedIdentifier) {
+			name := identifierName(tokens[j])
+			j++
+			if j < len(tokens) && tokens[j].text == "(" {
+				j = skipParentheses(tokens, j)
+			}
+			if j >= len(tokens) || !tokens[j].is("AS") {
+				break
+			}
+			j++
+			if j < len(tokens) && tokens[j].is("NOT") {
+				j++
+			}
+			if j < len(tokens) && tokens[j].is("MATERIALIZED") {
+				j++
+			}
+			if j >= len(tokens) || tokens[j].text != "(" {
+				break
+			}
+			statements = append(statements, withStatement{name: name, keyword: firstWord(tokens, j+1), at: min(j+1, len(tokens)-1)})
+			j = skipParentheses(tokens, j)
+			if j < len(tokens) && tokens[j].text == "," {
+				j++
+				continue
+			}
+			statements = append(statements, withStatement{keyword: firstWord(tokens, j), at: min(j, len(tokens)-1)})
+			break
+		}
 	}
+	return statements
+}
 
-	// 2. Check maximum size (prevent DoS)
-	if len(v.query) > MaxQueryLength {
-		return fmt.Errorf("%w (maximum %d characters)", ErrQueryTooLong, MaxQueryLength)
+// firstWord returns the word at i in upper case, or "" when it is not a word
+func firstWord(tokens []sqlToken, i int) string {
+	if i >= len(tokens) || tokens[i].kind != sqlWord {
+		return ""
 	}
+	return tokens[i].upper()
+}
 
-	// 3. Check if it starts with SELECT or WITH
-	if !strings.HasPrefix(v.normalized, "SELECT") && !strings.HasPrefix(v.normalized, "WITH") {
-		return ErrOnlySelectAllowed
+// skipParentheses returns the position after the parenthesis closing the one at i
+func skipParentheses(tokens []sqlToken, i int) int {
+	depth := 0
+	for ; i < len(tokens); i++ {
+		if tokens[i].kind != sqlPunctuation {
+			continue
+		}
+		switch tokens[i].text {
+		case "(":
+			depth++
+		case ")":
+			if depth--; depth == 0 {
+				return i + 1
+			}
+		}
 	}
+	return i
+}
 
-	// 4. Removing literals for command parsing
-	sqlWithoutLiterals := removeStringLiterals(v.normalized)
+// dangerousNames are the names a query of a database must not use, as they reach the files
+// or the operating system of the server or other servers, run a query passed as a string,
+// which the validator does not read, end other sessions, or take session locks, which outlive
+// the rollback of the read-only transaction and stay on the pooled connection
+type dangerousNames struct {
+	words      []string // matched wherever they appear, alone or as a pair of words
+	functions  []string // matched when called, so a column of the same name is accepted
+	statements []string // matched as the first word of the query
+}
 
-	// 5. Dangerous DML commands (outside of strings)
-	dangerousDML := []string{
-		"INSERT", "UPDATE", "DELETE", "TRUNCATE", "MERGE",
+// dangerousFunctions are the dangerous names of each database, which a validation policy can
+// allow along with the xp_ procedures of SQL Server. SQL Server runs procedures without
+// parentheses and Oracle reaches the network and files through packages, so their names are
+// words.
+var dangerousFunctions = map[DriverType]dangerousNames{
+	DriverSQLServer: {
+		words: []string{"SP_CONFIGURE", "SP_ADDSRVROLEMEMBER", "SP_ADDLOGIN", "OPENROWSET", "OPENDATASOURCE", "OPENQUERY", "BCP"},
+	},
+	DriverPostgresSQL: {
+		functions: []string{
+			"DBLINK", "DBLINK_EXEC", "DBLINK_CONNECT", "DBLINK_CONNECT_U", "DBLINK_SEND_QUERY",
+			"LO_IMPORT", "LO_EXPORT", "PG_READ_FILE", "PG_READ_BINARY_FILE", "PG_LS_DIR", "PG_STAT_FILE", "PG_FILE_WRITE",
+			"QUERY_TO_XML", "QUERY_TO_XML_AND_XMLSCHEMA", "TABLE_TO_XML", "CURSOR_TO_XML", "TS_STAT",
+			"PG_TERMINATE_BACKEND", "PG_CANCEL_BACKEND",
+			"PG_ADVISORY_LOCK", "PG_ADVISORY_LOCK_SHARED", "PG_TRY_ADVISORY_LOCK", "PG_TRY_ADVISORY_LOCK_SHARED",
+		},
+		statements: []string{"COPY"},
+	},
+	DriverMySQL: {
+		words:      []string{"INTO OUTFILE", "INTO DUMPFILE"},
+		functions:  []string{"LOAD_FILE", "SYS_EXEC", "SYS_EVAL", "GET_LOCK"},
+		statements: []string{"SYSTEM"},
+	},
+	DriverOracle: {
+		words: []string{"UTL_FILE", "UTL_HTTP", "UTL_TCP", "UTL_SMTP"},
+	},
+	DriverSQLite: {
+		functions: []string{"LOAD_EXTENSION", "READFILE", "WRITEFILE"},
+	},
+}
+
+// timingFunctions are the functions of each database that make a query wait, when called.
+// Oracle sleeps with DBMS_LOCK.SLEEP and DBMS_SESSION.SLEEP; SQL Server with WAITFOR, which
+// is always rejected.
+var timingFunctions = map[DriverType][]string{
+	DriverPostgresSQL: {"PG_SLEEP", "PG_SLEEP_FOR", "PG_SLEEP_UNTIL"},
+	DriverMySQL:       {"SLEEP", "BENCHMARK"},
+	DriverOracle:      {"SLEEP"},
+}
+
+// validationDrivers returns the driver whose names a query is checked against, or every
+// driver when the driver is unknown
+func validationDrivers(driver DriverType) []DriverType {
+	if _, ok := sqlLexRulesByDriver[driver]; ok {
+		return []DriverType{driver}
 	}
-	for _, cmd := range dangerousDML {
-		if containsKeyword(sqlWithoutLiterals, cmd) {
-			return fmt.Errorf("%w: %s", ErrCommandNotAllowed, cmd)
+	return []DriverType{DriverSQLServer, DriverPostgresSQL, DriverMySQL, DriverOracle, DriverSQLite}
+}
+
+// The name tables below are built once, when the package is initialized, and only read
+// afterwards, so concurrent tool calls validate queries without locking or allocating them.
+// The "" key holds the names of every database, for an unknown driver.
+var (
+	dangerousNamesByDriver  = buildDangerousNames()
+	timingFunctionsByDriver = buildTimingFunctions()
+	blockedNames            = buildBlockedNames()
+)
+
+// buildDangerousNames returns the dangerous names of each driver and of every database
+func buildDangerousNames() map[DriverType]dangerousNames {
+	table := make(map[DriverType]dangerousNames, len(sqlLexRulesByDriver)+1)
+	for _, driver := range []DriverType{DriverSQLServer, DriverPostgresSQL, DriverMySQL, DriverOracle, DriverSQLite, ""} {
+		var names dangerousNames
+		for _, d := range validationDrivers(driver) {
+			names.words = append(names.words, dangerousFunctions[d].words...)
+			names.functions = append(names.functions, dangerousFunctions[d].functions...)
+			names.statements = append(names.statements, dangerousFunctions[d].statements...)
 		}
+		table[driver] = names
 	}
+	return table
+}
 
-	// 6. Dangerous DDL commands
-	dangerousDDL := []string{
-		"DROP", "CREATE", "ALTER", "RENAME",
+// buildTimingFunctions returns the timing functions of each driver and of every database
+func buildTimingFunctions() map[DriverType][]string {
+	table := make(map[DriverType][]string, len(sqlLexRulesByDriver)+1)
+	for _, driver := range []DriverType{DriverSQLServer, DriverPostgresSQL, DriverMySQL, DriverOracle, DriverSQLite, ""} {
+		var functions []string
+		for _, d := range validationDrivers(driver) {
+			functions = append(functions, timingFunctions[d]...)
+		}
+		table[driver] = functions
 	}
-	for _, cmd := range dangerousDDL {
-		if containsKeyword(sqlWithoutLiterals, cmd) {
-			return fmt.Errorf("%w: %s", ErrCommandNotAllowed, cmd)
+	return table
+}
+
+// buildBlockedNames returns the dangerous and timing names of every database
+func buildBlockedNames() map[string]bool {
+	names := dangerousNamesByDriver[""]
+	set := make(map[string]bool)
+	for _, list := range [][]string{names.words, names.functions, names.statements, timingFunctionsByDriver[""]} {
+		for _, name := range list {
+			set[name] = true
 		}
 	}
+	return set
+}
 
-	// 7. Execution commands
-	dangerousExec := []string{
-		"EXEC", "EXECUTE", "SP_EXECUTESQL", "XP_CMDSHELL",
+// dangerousNamesFor returns the dangerous names of a driver, or those of every database when
+// the driver is unknown
+func dangerousNamesFor(driver DriverType) dangerousNames {
+	if names, ok := dangerousNamesByDriver[driver]; ok {
+		return names
 	}
-	for _, cmd := range dangerousExec {
-		if containsKeyword(sqlWithoutLiterals, cmd) {
-			return fmt.Errorf("%w: %s", ErrCommandNotAllowed, cmd)
-		}
+	return dangerousNamesByDriver[""]
+}
+
+// timingFunctionsFor returns the timing functions of a driver, or those of every database
+// when the driver is unknown
+func timingFunctionsFor(driver DriverType) []string {
+	if functions, ok := timingFunctionsByDriver[driver]; ok {
+		return functions
 	}
+	return timingFunctionsByDriver[""]
+}
 
-	// 8. Transaction control commands
-	transactionCmds := []string{
-		"BEGIN TRANSACTION", "BEGIN TRAN", "COMMIT", "ROLLBACK", "SAVE TRANSACTION",
+// isBlockedFunction reports whether a name is rejected on some database unless a validation
+// policy allows it. XP_CMDSHELL runs commands, so it is a command and cannot be allowed.
+func isBlockedFunction(name string) bool {
+	if strings.HasPrefix(name, "XP_") {
+		return name != "XP_CMDSHELL"
 	}
-	for _, cmd := range transactionCmds {
-		if strings.Contains(sqlWithoutLiterals, cmd) {
-			return fmt.Errorf("%w: %s", ErrTransactionNotAllowed, cmd)
+	return blockedNames[name]
+}
+
+// Validate checks that the query is a single read-only statement and returns every rule it
+// breaks. The checks run on its tokens as each set of lexical rules of the validator splits
+// it, so it must pass them all. Err on the result rejects the query when any error was found.
+func (v *SQLValidator) Validate() *ValidationResult {
+	result := &ValidationResult{}
+
+	switch {
+	// A policy file that could not be loaded rejects every query
+	case v.policy.err != nil:
+		result.add(SeverityError, v.policy.err, -1)
+
+	// 1. Check if it's not empty
+	case strings.TrimSpace(v.query) == "":
+		result.add(SeverityError, ErrQueryEmpty, -1)
+
+	// 2. Check maximum size (prevent DoS); longer queries are not read any further
+	case len(v.query) > v.policy.MaxQueryLength:
+		result.add(SeverityError, fmt.Errorf("%w "+translate("(maximum %d characters)"), ErrQueryTooLong, v.policy.MaxQueryLength), -1)
+
+	default:
+		for _, rules := range v.rules {
+			v.validateTokens(lexSQL(v.query, rules), result)
 		}
 	}
 
-	// 9. Backup/restore commands
-	backupCmds := []string{
-		"BACKUP", "RESTORE", "DUMP",
+	result.locate(v.query)
+	return result
+}
+
+// validateTokens checks the tokens of the query as one database splits it
+func (v *SQLValidator) validateTokens(tokens []sqlToken, result *ValidationResult) {
+	fail := func(err error, offset int) {
+		result.add(SeverityError, err, offset)
 	}
-	for _, cmd := range backupCmds {
-		if containsKeyword(sqlWithoutLiterals, cmd) {
-			return fmt.Errorf("%w: %s", ErrCommandNotAllowed, cmd)
+
+	// 3. Check if it starts with SELECT or WITH, a read statement of the database or a
+	// statement the policy allows
+	if len(tokens) == 0 || tokens[0].kind != sqlWord || !v.allowsStatement(tokens[0].text) {
+		offset := -1
+		if len(tokens) > 0 {
+			offset = tokens[0].pos
+		}
+		switch {
+		case len(v.policy.AllowedStatements) > 0 && len(tokens) > 0:
+			fail(fmt.Errorf("%w: %s", ErrStatementNotAllowed, tokens[0].upper()), offset)
+		case len(driverStatements[v.driver]) > 0:
+			fail(fmt.Errorf("%w: %s", ErrReadStatementsOnly, strings.Join(v.allowedStatements(), ", ")), offset)
+		default:
+			fail(ErrOnlySelectAllowed, offset)
+		}
+	} else if tokens[0].is("PRAGMA") {
+		if err := validatePragma(tokens); err != nil {
+			fail(err, tokens[0].pos)
 		}
 	}
 
-	// 10. Administration commands
-	adminCmds := []string{
-		"SHUTDOWN", "RECONFIGURE", "DBCC", "KILL",
+	// The words of the query, alone and in pairs, with the position of their first use, and
+	// the functions it calls with the position of their first call. A quoted name calls the
+	// function as its bare name does ("pg_sleep"(1)).
+	words := map[string]int{}
+	calls := map[string]int{}
+	var previous string
+	var previousPos int
+	for i, token := range tokens {
+		call := i+1 < len(tokens) && tokens[i+1].kind == sqlPunctuation && tokens[i+1].text == "("
+		if token.kind == sqlQuotedIdentifier && call {
+			name := strings.ToUpper(identifierName(token))
+			if _, ok := calls[name]; !ok {
+				calls[name] = token.pos
+			}
+		}
+		if token.kind != sqlWord {
+			previous = ""
+			continue
+		}
+		word := token.upper()
+		if _, ok := words[word]; !ok {
+			words[word] = token.pos
+		}
+		if _, ok := words[previous+" "+word]; previous != "" && !ok {
+			words[previous+" "+word] = previousPos
+		}
+		previous, previousPos = word, token.pos
+		if _, ok := calls[word]; !ok && call {
+			calls[word] = token.pos
+		}
 	}
-	for _, cmd := range adminCmds {
-		if containsKeyword(sqlWithoutLiterals, cmd) {
-			return fmt.Errorf("%w: %s", ErrAdminCommandNotAllowed, cmd)
+
+	// 4. Data-modifying statements of WITH queries, which Postgres runs in the body of a
+	// common table expression and SQL Server and MySQL after them
+	for _, statement := range withStatements(tokens) {
+		if !dataModifyingKeywords[statement.keyword] {
+			continue
+		}
+		if statement.name == "" {
+			fail(fmt.Errorf("%w: %s", ErrDataModifyingCTE, statement.keyword), tokens[statement.at].pos)
+		} else {
+			fail(fmt.Errorf("%w: %s AS (%s", ErrDataModifyingCTE, statement.name, statement.keyword), tokens[statement.at].pos)
 		}
 	}
 
-	// 11. Security commands
-	securityCmds := []string{
-		"GRANT", "REVOKE", "DENY",
+	// 5. Commands that write, change the schema, run code, control transactions or
+	// administer the server
+	for _, group := range blockedKeywords {
+		for _, keyword := range group.keywords {
+			if pos, ok := words[keyword]; ok {
+				fail(fmt.Errorf("%w: %s", group.err, keyword), pos)
+			}
+		}
 	}
-	for _, cmd := range securityCmds {
-		if containsKeyword(sqlWithoutLiterals, cmd) {
-			return fmt.Errorf("%w: %s", ErrSecurityCommandNotAllowed, cmd)
+
+	// 6. Keywords the policy denies
+	for _, keyword := range v.policy.denied {
+		if pos, ok := words[keyword]; ok {
+			fail(fmt.Errorf("%w: %s", ErrKeywordDenied, keyword), pos)
 		}
 	}
 
-	// 12. Dangerous functions of the system
-	dangerousFunctions := []string{
-		"XP_", "SP_CONFIGURE", "SP_ADDSRVROLEMEMBER", "SP_ADDLOGIN",
-		"OPENROWSET", "OPENDATASOURCE", "OPENQUERY",
-		"BULK INSERT", "BCP",
+	// 7. Functions, procedures and statements of the database that reach its files, the
+	// operating system or other servers, and the extended stored procedures of SQL Server,
+	// unless the policy allows them
+	dangerous := dangerousNamesFor(v.driver)
+	for _, name := range dangerous.statements {
+		if len(tokens) > 0 && tokens[0].is(name) && !v.policy.allowsFunction(name) {
+			fail(fmt.Errorf("%w: %s", ErrDangerousFunctionNotAllowed, name), tokens[0].pos)
+		}
 	}
-	for _, fn := range dangerousFunctions {
-		if strings.Contains(sqlWithoutLiterals, fn) {
-			return fmt.Errorf("%w: %s", ErrDangerousFunctionNotAllowed, fn)
+	for _, name := range dangerous.words {
+		if pos, ok := words[name]; ok && !v.policy.allowsFunction(name) {
+			fail(fmt.Errorf("%w: %s", ErrDangerousFunctionNotAllowed, name), pos)
 		}
 	}
-
-	// 13. Detect multiple statements (separated by semicolon)
-	if err := v.validateMultipleStatements(); err != nil {
-		return err
+	for _, name := range dangerous.functions {
+		if pos, ok := calls[name]; ok && !v.policy.allowsFunction(name) {
+			fail(fmt.Errorf("%w: %s", ErrDangerousFunctionNotAllowed, name), pos)
+		}
+	}
+	if slices.Contains(validationDrivers(v.driver), DriverSQLServer) {
+		for _, token := range tokens {
+			if word := token.upper(); token.kind == sqlWord && strings.HasPrefix(word, "XP_") && !v.policy.allowsFunction(word) {
+				fail(fmt.Errorf("%w: %s", ErrDangerousFunctionNotAllowed, word), words[word])
+			}
+		}
+	}
+	if pos, ok := words["BULK INSERT"]; ok {
+		fail(fmt.Errorf("%w: %s", ErrDangerousFunctionNotAllowed, "BULK INSERT"), pos)
 	}
 
-	// 14. Check INTO clause (SELECT INTO)
-	if err := v.validateNoIntoClause(sqlWithoutLiterals); err != nil {
-		return err
+	// 8. Detect multiple statements: no semicolon is accepted, not even a trailing one
+	for _, token := range tokens {
+		if token.kind == sqlPunctuation && token.text == ";" {
+			fail(ErrMultipleCommandsNotAllowed, token.pos)
+			break
+		}
 	}
 
-	// 15. Check for attempts at stacked queries.
-	if strings.Count(sqlWithoutLiterals, ";") > 0 {
-		return ErrMultipleCommandsNotAllowed
+	// 9. Check INTO clause (SELECT INTO)
+	if pos, ok := words["INTO"]; ok {
+		fail(ErrSelectIntoNotAllowed, pos)
 	}
 
-	// 16. Check use of UNION for bypass
-	if err := v.validateUnionUsage(sqlWithoutLiterals); err != nil {
-		return err
+	// 10. Locking clauses and hints, which would take locks that block writers
+	for _, token := range tokens {
+		if token.kind == sqlWord && lockingHints[token.upper()] {
+			fail(fmt.Errorf("%w: %s", ErrLockingClauseNotAllowed, token.upper()), token.pos)
+		}
+	}
+	for i := range tokens {
+		for _, clause := range lockingClauses {
+			if matchWords(tokens, i, clause) {
+				fail(fmt.Errorf("%w: %s", ErrLockingClauseNotAllowed, strings.Join(clause, " ")), tokens[i].pos)
+			}
+		}
 	}
 
-	// 17.Check encoding and suspicious special characters
-	if err := v.validateEncoding(); err != nil {
-		return err
+	// 11. Check use of UNION for bypass
+	if pos, ok := nthWord(tokens, "UNION", v.policy.MaxUnionCount+1); ok {
+		fail(fmt.Errorf("%w "+translate("(maximum %d)"), ErrTooManyUnions, v.policy.MaxUnionCount), pos)
 	}
 
-	// 18. Check for time-based blind SQL injection attempts
-	if err := v.validateNoTimingAttacks(sqlWithoutLiterals); err != nil {
-		return err
+	// 12. Check encoding and suspicious special characters
+	v.validateEncoding(tokens, result)
+
+	// 13. Check for time-based blind SQL injection attempts
+	if pos, ok := words["WAITFOR"]; ok {
+		fail(fmt.Errorf("%w: %s", ErrTimeFunctionNotAllowed, "WAITFOR"), pos)
+	}
+	for _, name := range timingFunctionsFor(v.driver) {
+		if pos, ok := calls[name]; ok && !v.policy.allowsFunction(name) {
+			fail(fmt.Errorf("%w: %s", ErrTimeFunctionNotAllowed, name), pos)
+		}
 	}
 
-	// 19. Check number of subqueries (prevent DoS)
-	if strings.Count(sqlWithoutLiterals, "SELECT") > MaxSubqueryCount {
-		return fmt.Errorf("%w (maximum %d)", ErrTooManySubqueries, MaxSubqueryCount)
+	// 14. Check number of subqueries (prevent DoS)
+	if pos, ok := nthWord(tokens, "SELECT", v.policy.MaxSubqueryCount+1); ok {
+		fail(fmt.Errorf("%w "+translate("(maximum %d)"), ErrTooManySubqueries, v.policy.MaxSubqueryCount), pos)
 	}
 
-	// 20. Check parenthesis depth (prevent DoS)
-	if err := v.validateParenthesesDepth(); err != nil {
-		return err
+	// 15. Check parenthesis depth (prevent DoS)
+	v.validateParenthesesDepth(tokens, result)
+
+	// 16. Tables outside the schemas and tables the server exposes
+	if err := v.objects.checkQuery(tokens); err != nil {
+		fail(err, -1)
 	}
 
-	return nil
+	// Warnings: the query runs, but reads more than it likely needs
+	v.warnTokens(tokens, result)
 }
 
-// keywordPatterns caches compiled regex patterns for keyword matching
-var keywordPatterns = make(map[string]*regexp.Regexp)
+// warnTokens records the warnings of the query: SELECT *, which reads every column, and a
+// LIKE pattern starting with a wildcard, which no index can serve
+func (v *SQLValidator) warnTokens(tokens []sqlToken, result *ValidationResult) {
+	for i, token := range tokens {
+		switch {
+		case token.is("SELECT"):
+			j := i + 1
+			if j < len(tokens) && (tokens[j].is("DISTINCT") || tokens[j].is("ALL")) {
+				j++
+			}
+			if j < len(tokens) && tokens[j].kind == sqlPunctuation && tokens[j].text == "*" {
+				result.add(SeverityWarning, WarnSelectStar, tokens[j].pos)
+			}
+		case token.is("LIKE") || token.is("ILIKE"):
+			if i+1 < len(tokens) && tokens[i+1].kind == sqlString && isLeadingWildcard(tokens[i+1].text) {
+				result.add(SeverityWarning, WarnLeadingWildcard, tokens[i+1].pos)
+			}
+		}
+	}
+}
 
-// Checks if a keyword exists as a complete word (not part of another word)
-func containsKeyword(sql string, keyword string) bool {
-	pattern, exists := keywordPatterns[keyword]
-	if !exists {
-		pattern = regexp.MustCompile(`\b` + keyword + `\b`)
-		keywordPatterns[keyword] = pattern
+// isLeadingWildcard reports whether a string literal starts with a LIKE wildcard, past its
+// prefix and opening quote
+func isLeadingWildcard(literal string) bool {
+	quote := strings.IndexByte(literal, '\'')
+	return quote >= 0 && quote+1 < len(literal) && (literal[quote+1] == '%' || literal[quote+1] == '_')
+}
+
+// nthWord returns the position of the nth use of the word keyword in tokens, if it is used
+// that many times
+func nthWord(tokens []sqlToken, keyword string, n int) (int, bool) {
+	count := 0
+	for _, token := range tokens {
+		if token.is(keyword) {
+			if count++; count == n {
+				return token.pos, true
+			}
+		}
 	}
-	return pattern.MatchString(sql)
+	return 0, false
 }
 
-// Validates multiple statements
-func (v *SQLValidator) validateMultipleStatements() error {
-	// Search for semicolons outside of strings
-	inString := false
-	escapeNext := false
+// Validates encoding and special characters
+func (v *SQLValidator) validateEncoding(tokens []sqlToken, result *ValidationResult) {
+	src := []rune(v.query)
 
-	for i, char := range v.query {
-		if escapeNext {
-			escapeNext = false
-			continue
+	// Checking for suspicious control characters
+	for i, char := range src {
+		if char < 32 && char != '\n' && char != '\r' && char != '\t' {
+			result.add(SeverityError, ErrSuspiciousCharacter, i)
+			break
 		}
+	}
 
-		if char == '\\' {
-			escapeNext = true
-			continue
+	// Nested block comments, which databases disagree on where they end
+	if pos, ok := nestedComment(src, tokens); ok {
+		result.add(SeverityError, ErrNestedComment, pos)
+	}
+
+	// Zero-width and other invisible characters outside strings and comments, and words
+	// mixing Latin letters with look-alike letters of other scripts (SЕLECT with a
+	// Cyrillic Е) or written in fullwidth forms
+	for _, token := range tokens {
+		switch {
+		case token.kind == sqlPunctuation && isInvisible([]rune(token.text)[0]):
+			result.add(SeverityError, ErrInvisibleCharacter, token.pos)
+		case token.kind == sqlWord && isHomoglyphWord(token.text):
+			result.add(SeverityError, fmt.Errorf("%w: %s", ErrHomoglyphCharacter, token.text), token.pos)
 		}
+	}
 
-		if char == '\'' {
-			inString = !inString
-			continue
+	// Keywords split across concatenated strings ('DR' || 'OP', 'EX' + 'EC')
+	for _, parts := range concatenatedStrings(tokens) {
+		if keyword, ok := splitKeyword(parts); ok {
+			result.add(SeverityError, fmt.Errorf("%w: %s", ErrConcatenatedKeyword, keyword), parts[0].pos)
 		}
+	}
 
-		if !inString && char == ';' {
-			// Check that it is not the last character (allowed at the end)
-			if i < len(v.query)-1 && strings.TrimSpace(v.query[i+1:]) != "" {
-				return ErrMultipleCommandsNotAllowed
+	// Check for hexadecimal encoding attempts (0x...), allowed only in small numbers
+	hexLiterals, charCalls := 0, 0
+	for i, token := range tokens {
+		switch {
+		case token.kind == sqlNumber && len(token.text) > 2 && (token.text[:2] == "0x" || token.text[:2] == "0X"):
+			if hexLiterals++; hexLiterals == v.policy.MaxHexEncodingCount+1 {
+				result.add(SeverityError, ErrExcessiveHexEncoding, token.pos)
+			}
+		case (token.is("CHAR") || token.is("NCHAR")) && i+1 < len(tokens) && tokens[i+1].text == "(":
+			// Check CHAR / NCHAR used to obfuscate commands
+			if charCalls++; charCalls == v.policy.MaxCharFunctionCount+1 {
+				result.add(SeverityError, ErrExcessiveCharFunction, token.pos)
 			}
 		}
 	}
-
-	return nil
 }
 
-// Validates that there is no SELECT INTO statement.
-func (v *SQLValidator) validateNoIntoClause(sql string) error {
-	// Search for pattern SELECT ... INTO
-	if reSelectInto.MatchString(sql) {
-		return ErrSelectIntoNotAllowed
+// nestedComment returns the position of the first block comment opened inside another one.
+// Comments are only found between tokens, so the gaps between them are scanned.
+func nestedComment(src []rune, tokens []sqlToken) (int, bool) {
+	start := 0
+	for i := 0; i <= len(tokens); i++ {
+		end := len(src)
+		if i < len(tokens) {
+			end = tokens[i].pos
+		}
+		open := false
+		for j := start; j+1 < end; j++ {
+			switch {
+			case !open && ((src[j] == '-' && src[j+1] == '-') || src[j] == '#'):
+				for j < end && src[j] != '\n' {
+					j++
+				}
+			case src[j] == '/' && src[j+1] == '*':
+				if open {
+					return j, true
+				}
+				open = true
+				j++
+			case open && src[j] == '*' && src[j+1] == '/':
+				open = false
+				j++
+			}
+		}
+		if i < len(tokens) {
+			start = tokens[i].pos + len([]rune(tokens[i].text))
+		}
 	}
-	return nil
+	return 0, false
 }
 
-// validateUnionUsage validates UNION clause usage (allows only legitimate queries)
-func (v *SQLValidator) validateUnionUsage(sql string) error {
-	// Count UNIONs
-	unionCount := strings.Count(sql, "UNION")
-	if unionCount > MaxUnionCount {
-		return fmt.Errorf("%w (maximum %d)", ErrTooManyUnions, MaxUnionCount)
-	}
-
-	return nil
+// isInvisible reports whether c is a zero-width or other invisible formatting character
+func isInvisible(c rune) bool {
+	return unicode.Is(unicode.Cf, c)
 }
 
-// Validates encoding and special characters
-func (v *SQLValidator) validateEncoding() error {
-	// Checking for suspicious control characters
-	for _, char := range v.query {
-		if char < 32 && char != '\n' && char != '\r' && char != '\t' {
-			return ErrSuspiciousCharacter
+// homoglyphScripts are the scripts whose letters look like Latin ones
+var homoglyphScripts = []*unicode.RangeTable{unicode.Cyrillic, unicode.Greek, unicode.Armenian}
+
+// isHomoglyphWord reports whether a word mixes Latin letters with letters of a script that
+// look like them, or uses fullwidth forms, which read as a keyword but do not match it
+func isHomoglyphWord(word string) bool {
+	latin, other := false, false
+	for _, c := range word {
+		switch {
+		case c >= '\uFF01' && c <= '\uFF5E':
+			return true
+		case c < unicode.MaxASCII && unicode.IsLetter(c):
+			latin = true
+		case unicode.In(c, homoglyphScripts...):
+			other = true
 		}
 	}
+	return latin && other
+}
 
-	// Check for hexadecimal encoding attempts (0x...)
-	if strings.Contains(v.normalized, "0X") {
-		// Allow only in safe contexts (simple comparisons)
-		matches := reHexPattern.FindAllString(v.normalized, -1)
-		if len(matches) > MaxHexEncodingCount {
-			return ErrExcessiveHexEncoding
+// concatenatedStrings returns the runs of string literals joined into one: with || or +,
+// written next to each other, or passed one after the other to CONCAT
+func concatenatedStrings(tokens []sqlToken) [][]sqlToken {
+	var runs [][]sqlToken
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i].is("CONCAT") && i+1 < len(tokens) && tokens[i+1].text == "(" {
+			var parts []sqlToken
+			j := i + 2
+			for ; j < len(tokens) && tokens[j].kind == sqlString; j += 2 {
+				parts = append(parts, tokens[j])
+				if j+1 >= len(tokens) || tokens[j+1].text != "," {
+					j++
+					break
+				}
+			}
+			if len(parts) > 1 {
+				runs = append(runs, parts)
+			}
+			continue
+		}
+		if tokens[i].kind != sqlString {
+			continue
+		}
+		parts := []sqlToken{tokens[i]}
+		for {
+			j := i + 1
+			switch {
+			case j < len(tokens) && tokens[j].kind == sqlPunctuation && tokens[j].text == "+":
+				j++
+			case j+1 < len(tokens) && tokens[j].text == "|" && tokens[j+1].text == "|":
+				j += 2
+			}
+			if j >= len(tokens) || tokens[j].kind != sqlString {
+				break
+			}
+			parts = append(parts, tokens[j])
+			i = j
+		}
+		if len(parts) > 1 {
+			runs = append(runs, parts)
 		}
 	}
+	return runs
+}
 
-	// Check CHAR / NCHAR used to obfuscate commands
-	matches := reCharNCharPattern.FindAllString(v.normalized, -1)
-	if len(matches) > MaxCharFunctionCount {
-		return ErrExcessiveCharFunction
+// splitKeyword returns the blocked keyword, function or procedure that concatenating string
+// literals spells across two of them, none of which holds it alone
+func splitKeyword(parts []sqlToken) (string, bool) {
+	var text []rune
+	var bounds []int // positions in text where a part ends
+	for _, part := range parts {
+		text = append(text, []rune(strings.ToUpper(stringLiteralText(part.text)))...)
+		bounds = append(bounds, len(text))
+	}
+	for i := 0; i < len(text); {
+		if !isWordPart(text[i], sqlLexRules{}) {
+			i++
+			continue
+		}
+		end := i + 1
+		for end < len(text) && isWordPart(text[end], sqlLexRules{}) {
+			end++
+		}
+		word := string(text[i:end])
+		for _, bound := range bounds {
+			if bound > i && bound < end && isBlockedWord(word) {
+				return word, true
+			}
+		}
+		i = end
 	}
-
-	return nil
+	return "", false
 }
 
-// Validates timing attack attempts.
-func (v *SQLValidator) validateNoTimingAttacks(sql string) error {
-	timingFunctions := []string{
-		"WAITFOR", "DELAY", "SLEEP", "BENCHMARK",
+// isBlockedWord reports whether a word is a blocked command, a dangerous or timing function
+// of any database, or WAITFOR
+func isBlockedWord(word string) bool {
+	for _, group := range blockedKeywords {
+		if slices.Contains(group.keywords, word) {
+			return true
+		}
 	}
+	return word == "WAITFOR" || isBlockedFunction(word)
+}
 
-	for _, fn := range timingFunctions {
-		if containsKeyword(sql, fn) {
-			return fmt.Errorf("%w: %s", ErrTimeFunctionNotAllowed, fn)
+// stringLiteralText returns the text of a string literal without its prefix and quotes
+func stringLiteralText(literal string) string {
+	if strings.HasPrefix(literal, "$") {
+		if tag := strings.Index(literal[1:], "$"); tag >= 0 {
+			return strings.TrimSuffix(literal[tag+2:], literal[:tag+2])
 		}
+		return literal
 	}
-
-	return nil
+	quote := strings.IndexByte(literal, '\'')
+	if quote < 0 {
+		return literal
+	}
+	prefix, text := strings.ToUpper(literal[:quote]), literal[quote+1:]
+	if (prefix == "Q" || prefix == "NQ") && len(text) >= 3 {
+		return text[1 : len(text)-2]
+	}
+	return strings.ReplaceAll(strings.TrimSuffix(text, "'"), "''", "'")
 }
 
 // Validate parenthesis depth (prevent DoS)
-func (v *SQLValidator) validateParenthesesDepth() error {
-	depth := 0
-	maxDepth := 0
+func (v *SQLValidator) validateParenthesesDepth(tokens []sqlToken, result *ValidationResult) {
+	var open []int // positions of the parentheses not closed yet
+	tooDeep := false
 
-	for _, char := range v.query {
-		if char == '(' {
-			depth++
-			if depth > maxDepth {
-				maxDepth = depth
+	for _, token := range tokens {
+		if token.kind != sqlPunctuation {
+			continue
+		}
+		switch token.text {
+		case "(":
+			open = append(open, token.pos)
+			if len(open) > v.policy.MaxParenthesesDepth && !tooDeep {
+				tooDeep = true
+				result.add(SeverityError, fmt.Errorf("%w "+translate("(maximum %d)"), ErrParenthesesTooDeep, v.policy.MaxParenthesesDepth), token.pos)
+			}
+		case ")":
+			if len(open) == 0 {
+				result.add(SeverityError, ErrUnbalancedParentheses, token.pos)
+				return
 			}
-		} else if char == ')' {
-			depth--
+			open = open[:len(open)-1]
 		}
 	}
 
-	if depth != 0 {
-		return ErrUnbalancedParentheses
+	if len(open) > 0 {
+		result.add(SeverityError, ErrUnbalancedParentheses, open[len(open)-1])
 	}
+}
 
-	if maxDepth > MaxParenthesesDepth {
-		return fmt.Errorf("%w (maximum %d)", ErrParenthesesTooDeep, MaxParenthesesDepth)
-	}
+// validationRules names the rule behind each validation error, for validate_query
+var validationRules = []struct {
+	err  error
+	rule string
+}{
+	{ErrQueryEmpty, "empty_query"},
+	{ErrQueryTooLong, "max_length"},
+	{ErrInvalidValidationPolicy, "validation_policy"},
+	{ErrOnlySelectAllowed, "select_only"},
+	{ErrReadStatementsOnly, "select_only"},
+	{ErrStatementNotAllowed, "statement_type"},
+	{ErrPragmaNotAllowed, "statement_type"},
+	{ErrKeywordDenied, "denied_keyword"},
+	{ErrDataModifyingCTE, "writable_cte"},
+	{ErrCommandNotAllowed, "blocked_command"},
+	{ErrLockingClauseNotAllowed, "locking_clause"},
+	{ErrTransactionNotAllowed, "transaction_command"},
+	{ErrAdminCommandNotAllowed, "admin_command"},
+	{ErrSecurityCommandNotAllowed, "security_command"},
+	{ErrDangerousFunctionNotAllowed, "dangerous_function"},
+	{ErrMultipleCommandsNotAllowed, "single_statement"},
+	{ErrTooManySubqueries, "max_subqueries"},
+	{ErrSelectIntoNotAllowed, "select_into"},
+	{ErrTooManyUnions, "max_unions"},
+	{ErrSuspiciousCharacter, "control_character"},
+	{ErrExcessiveHexEncoding, "hex_encoding"},
+	{ErrExcessiveCharFunction, "char_obfuscation"},
+	{ErrNestedComment, "nested_comment"},
+	{ErrInvisibleCharacter, "invisible_character"},
+	{ErrHomoglyphCharacter, "homoglyph"},
+	{ErrConcatenatedKeyword, "concatenated_keyword"},
+	{ErrTimeFunctionNotAllowed, "time_function"},
+	{ErrUnbalancedParentheses, "balanced_parentheses"},
+	{ErrParenthesesTooDeep, "max_parentheses_depth"},
+	{ErrInvalidObjectFilter, "object_filter"},
+	{ErrTableNotAllowed, "table_access"},
+	{ErrTableNotQualified, "table_access"},
+	{ErrShowNotAllowed, "table_access"},
+	{ErrQueryFunctionNotAllowed, "table_access"},
+	{ErrSchemaNotAllowed, "table_access"},
+	{WarnSelectStar, "select_star"},
+	{WarnLeadingWildcard, "leading_wildcard"},
+}
 
-	return nil
+// validationRule returns the name of the rule a validation error reports
+func validationRule(err error) string {
+	for _, r := range validationRules {
+		if errors.Is(err, r.err) {
+			return r.rule
+		}
+	}
+	return "unknown"
 }
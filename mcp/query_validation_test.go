@@ -0,0 +1,191 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// validateFor validates a query for a driver with the built-in checks
+func validateFor(query string, driver DriverType) error {
+	return newDriverSQLValidator(query, driver, defaultValidationPolicy(), nil).Validate().Err()
+}
+
+// validationCase is a query and the error its validation must report, or nil when it is
+// accepted
+type validationCase struct {
+	name   string
+	driver DriverType
+	query  string
+	want   error
+}
+
+// runValidationCases validates each query for its driver and checks the error reported
+func runValidationCases(t *testing.T, tests []validationCase) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFor(tt.query, tt.driver)
+			switch {
+			case tt.want == nil && err != nil:
+				t.Errorf("Validate(%q) on %q = %v, want it accepted", tt.query, tt.driver, err)
+			case tt.want != nil && !errors.Is(err, tt.want):
+				t.Errorf("Validate(%q) on %q = %v, want %v", tt.query, tt.driver, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	runValidationCases(t, []validationCase{
+		{"select", "", "SELECT id, name FROM users WHERE id = 1", nil},
+		{"with", "", "WITH t AS (SELECT 1 AS x) SELECT x FROM t", nil},
+		{"string holding a keyword", "", "SELECT 'DROP TABLE users' AS note", nil},
+		{"comment holding a keyword", DriverPostgresSQL, "SELECT 1 -- DROP TABLE users", nil},
+		{"postgres show", DriverPostgresSQL, "SHOW search_path", nil},
+		{"mysql describe", DriverMySQL, "DESCRIBE users", nil},
+		{"sqlite pragma", DriverSQLite, "PRAGMA table_info(users)", nil},
+		{"empty", "", "   ", ErrQueryEmpty},
+		{"insert", "", "INSERT INTO users VALUES (1)", ErrOnlySelectAllowed},
+		{"drop after select", "", "SELECT 1; DROP TABLE users", ErrCommandNotAllowed},
+		{"trailing semicolon", "", "SELECT 1;", ErrMultipleCommandsNotAllowed},
+		{"select into", "", "SELECT * INTO backup FROM users", ErrSelectIntoNotAllowed},
+		{"writable cte", DriverPostgresSQL, "WITH d AS (DELETE FROM users RETURNING *) SELECT * FROM d", ErrDataModifyingCTE},
+		{"for update", "", "SELECT * FROM users FOR UPDATE", ErrLockingClauseNotAllowed},
+		{"updlock hint", DriverSQLServer, "SELECT * FROM users WITH (UPDLOCK)", ErrLockingClauseNotAllowed},
+		{"holdlock hint", DriverSQLServer, "SELECT * FROM users WITH (HOLDLOCK)", ErrLockingClauseNotAllowed},
+		{"serializable hint", DriverSQLServer, "SELECT * FROM users WITH (SERIALIZABLE)", ErrLockingClauseNotAllowed},
+		{"repeatableread hint", DriverSQLServer, "SELECT * FROM users u WITH (REPEATABLEREAD) JOIN orders o ON o.user_id = u.id", ErrLockingClauseNotAllowed},
+		{"tablock hint", DriverSQLServer, "SELECT * FROM users WITH (NOLOCK, TABLOCK)", ErrLockingClauseNotAllowed},
+		{"paglock hint without with", DriverSQLServer, "SELECT * FROM users (PAGLOCK)", ErrLockingClauseNotAllowed},
+		{"nolock hint", DriverSQLServer, "SELECT * FROM users WITH (NOLOCK)", nil},
+		{"waitfor", DriverSQLServer, "SELECT 1 WAITFOR DELAY '0:0:5'", ErrTimeFunctionNotAllowed},
+		{"xp procedure", DriverSQLServer, "SELECT * FROM xp_dirtree('c:\\')", ErrDangerousFunctionNotAllowed},
+		{"sqlite writing pragma", DriverSQLite, "PRAGMA journal_mode = DELETE", ErrPragmaNotAllowed},
+		{"mysql executable comment", DriverMySQL, "SELECT /*!50000 SLEEP(5) */ 1", ErrTimeFunctionNotAllowed},
+		{"mysql backslash escape", DriverMySQL, `SELECT 'a\' UNION SELECT LOAD_FILE('/etc/passwd') -- '`, ErrDangerousFunctionNotAllowed},
+		{"postgres dollar quote", DriverPostgresSQL, "SELECT $$ a $$, pg_sleep(5)", ErrTimeFunctionNotAllowed},
+		{"nested comment", "", "SELECT /* a /* b */ 1 */ 1", ErrNestedComment},
+		{"concatenated keyword", "", "SELECT 'DR' || 'OP'", ErrConcatenatedKeyword},
+		{"homoglyph", "", "SЕLECT 1", ErrOnlySelectAllowed},
+		{"unbalanced parentheses", "", "SELECT (1", ErrUnbalancedParentheses},
+	})
+}
+
+func TestValidateBrackets(t *testing.T) {
+	runValidationCases(t, []validationCase{
+		// Postgres builds arrays with brackets, whose elements are run
+		{"postgres array file read", DriverPostgresSQL, "SELECT ARRAY[pg_read_file('/etc/passwd')]", ErrDangerousFunctionNotAllowed},
+		{"postgres array sleep", DriverPostgresSQL, "SELECT ARRAY[pg_sleep(30)]", ErrTimeFunctionNotAllowed},
+		{"postgres array dblink", DriverPostgresSQL, "SELECT ARRAY[(SELECT dblink_exec('host=x', 'DROP TABLE x'))]", ErrDangerousFunctionNotAllowed},
+		{"postgres subscript", DriverPostgresSQL, "SELECT tags[pg_sleep(30)] FROM posts", ErrTimeFunctionNotAllowed},
+		{"postgres array", DriverPostgresSQL, "SELECT ARRAY[1, 2][1]", nil},
+		{"unknown driver array file read", "", "SELECT ARRAY[pg_read_file('/etc/passwd')]", ErrDangerousFunctionNotAllowed},
+		{"unknown driver array sleep", "", "SELECT ARRAY[pg_sleep(30)]", ErrTimeFunctionNotAllowed},
+		{"unknown driver array dblink", "", "SELECT ARRAY[(SELECT dblink_exec('host=x', 'DROP TABLE x'))]", ErrDangerousFunctionNotAllowed},
+
+		// SQL Server and SQLite quote identifiers with brackets
+		{"sqlserver bracket identifier", DriverSQLServer, "SELECT [pg_sleep(30)], [drop] FROM [dbo].[users]", nil},
+		{"sqlite bracket identifier", DriverSQLite, "SELECT [pg_sleep(30)], [drop] FROM [users]", nil},
+		{"unknown driver bracket identifier", "", "SELECT [drop] FROM [users]", ErrCommandNotAllowed},
+
+		// MySQL and Oracle have no bracket quoting, so what brackets hold is checked
+		{"mysql brackets", DriverMySQL, "SELECT a[sleep(5)] FROM t", ErrTimeFunctionNotAllowed},
+		{"oracle brackets", DriverOracle, "SELECT a[utl_http.request('http://x')] FROM dual", ErrDangerousFunctionNotAllowed},
+	})
+}
+
+func TestValidateDangerousFunctions(t *testing.T) {
+	runValidationCases(t, []validationCase{
+		// Postgres functions running a query or reading a table named in a string
+		{"query_to_xml", DriverPostgresSQL, "SELECT query_to_xml('SELECT pg_read_file(''/etc/passwd'')', true, false, '')", ErrDangerousFunctionNotAllowed},
+		{"query_to_xml_and_xmlschema", DriverPostgresSQL, "SELECT query_to_xml_and_xmlschema('SELECT 1', true, false, '')", ErrDangerousFunctionNotAllowed},
+		{"table_to_xml", DriverPostgresSQL, "SELECT table_to_xml('pg_authid', true, false, '')", ErrDangerousFunctionNotAllowed},
+		{"cursor_to_xml", DriverPostgresSQL, "SELECT cursor_to_xml('c', 10, true, false, '')", ErrDangerousFunctionNotAllowed},
+		{"ts_stat", DriverPostgresSQL, "SELECT * FROM ts_stat('SELECT pg_sleep(30)::text::tsvector')", ErrDangerousFunctionNotAllowed},
+		{"query_to_xml, unknown driver", "", "SELECT query_to_xml('SELECT 1', true, false, '')", ErrDangerousFunctionNotAllowed},
+
+		// Functions ending other sessions or taking locks that outlive the transaction
+		{"pg_terminate_backend", DriverPostgresSQL, "SELECT pg_terminate_backend(pid) FROM pg_stat_activity", ErrDangerousFunctionNotAllowed},
+		{"pg_cancel_backend", DriverPostgresSQL, "SELECT pg_cancel_backend(1234)", ErrDangerousFunctionNotAllowed},
+		{"pg_advisory_lock", DriverPostgresSQL, "SELECT pg_advisory_lock(1)", ErrDangerousFunctionNotAllowed},
+		{"pg_advisory_lock_shared", DriverPostgresSQL, "SELECT pg_advisory_lock_shared(1)", ErrDangerousFunctionNotAllowed},
+		{"pg_try_advisory_lock", DriverPostgresSQL, "SELECT pg_try_advisory_lock(1)", ErrDangerousFunctionNotAllowed},
+		{"pg_advisory_xact_lock", DriverPostgresSQL, "SELECT pg_advisory_xact_lock(1)", nil},
+		{"mysql get_lock", DriverMySQL, "SELECT GET_LOCK('l', 10)", ErrDangerousFunctionNotAllowed},
+		{"pg_terminate_backend, unknown driver", "", "SELECT pg_terminate_backend(1234)", ErrDangerousFunctionNotAllowed},
+
+		// Denied functions wrapped in other expressions
+		{"in an array", DriverPostgresSQL, "SELECT ARRAY[query_to_xml('SELECT 1', true, false, '')]", ErrDangerousFunctionNotAllowed},
+		{"in a subquery", DriverPostgresSQL, "SELECT (SELECT pg_read_file('/etc/passwd'))", ErrDangerousFunctionNotAllowed},
+		{"in an array subquery", DriverPostgresSQL, "SELECT ARRAY[(SELECT table_to_xml('pg_authid', true, false, ''))]", ErrDangerousFunctionNotAllowed},
+		{"in a derived table", DriverPostgresSQL, "SELECT * FROM (SELECT lo_export(1, '/tmp/x')) t", ErrDangerousFunctionNotAllowed},
+		{"in a where clause", DriverPostgresSQL, "SELECT 1 WHERE EXISTS (SELECT dblink('host=x', 'SELECT 1'))", ErrDangerousFunctionNotAllowed},
+		{"in a cte", DriverPostgresSQL, "WITH t AS (SELECT pg_ls_dir('/') AS f) SELECT f FROM t", ErrDangerousFunctionNotAllowed},
+		{"nested in a call", DriverPostgresSQL, "SELECT length(pg_read_file('/etc/passwd'))", ErrDangerousFunctionNotAllowed},
+		{"schema qualified", DriverPostgresSQL, "SELECT pg_catalog.pg_read_file('/etc/passwd')", ErrDangerousFunctionNotAllowed},
+		{"quoted name", DriverPostgresSQL, `SELECT "pg_read_file"('/etc/passwd')`, ErrDangerousFunctionNotAllowed},
+		{"comment before the parenthesis", DriverPostgresSQL, "SELECT pg_read_file/**/('/etc/passwd')", ErrDangerousFunctionNotAllowed},
+		{"mixed case", DriverPostgresSQL, "SELECT Query_To_Xml('SELECT 1', true, false, '')", ErrDangerousFunctionNotAllowed},
+		{"quoted timing function", DriverPostgresSQL, `SELECT "pg_sleep"(30)`, ErrTimeFunctionNotAllowed},
+		{"mysql load_file in a subquery", DriverMySQL, "SELECT (SELECT LOAD_FILE('/etc/passwd'))", ErrDangerousFunctionNotAllowed},
+		{"mysql backquoted sleep", DriverMySQL, "SELECT `sleep`(5)", ErrTimeFunctionNotAllowed},
+		{"sqlite load_extension", DriverSQLite, "SELECT load_extension('x')", ErrDangerousFunctionNotAllowed},
+		{"sqlserver openrowset", DriverSQLServer, "SELECT * FROM OPENROWSET('SQLNCLI', 'x', 'SELECT 1')", ErrDangerousFunctionNotAllowed},
+		{"oracle utl_http", DriverOracle, "SELECT utl_http.request('http://x') FROM dual", ErrDangerousFunctionNotAllowed},
+
+		// Columns named as the functions are not calls
+		{"column named query_to_xml", DriverPostgresSQL, "SELECT query_to_xml FROM reports", nil},
+		{"quoted column", DriverPostgresSQL, `SELECT "pg_sleep" FROM jobs`, nil},
+	})
+}
+
+// TestValidateConcurrent validates queries from many goroutines, as concurrent tool calls
+// do, for go test -race to check that the validator shares no state between them
+func TestValidateConcurrent(t *testing.T) {
+	queries := []validationCase{
+		{"select", "", "SELECT id FROM users WHERE name LIKE '%a'", nil},
+		{"postgres sleep", DriverPostgresSQL, "SELECT ARRAY[pg_sleep(30)]", ErrTimeFunctionNotAllowed},
+		{"mysql file", DriverMySQL, "SELECT LOAD_FILE('/etc/passwd')", ErrDangerousFunctionNotAllowed},
+		{"sqlserver drop", DriverSQLServer, "SELECT 1; DROP TABLE [users]", ErrCommandNotAllowed},
+	}
+	var wg sync.WaitGroup
+	errs := make(chan error, 32*len(queries))
+	for g := 0; g < 32; g++ {
+		for _, tt := range queries {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result := newDriverSQLValidator(tt.query, tt.driver, defaultValidationPolicy(), nil).Validate()
+				if err := result.Err(); (tt.want == nil) != (err == nil) || (tt.want != nil && !errors.Is(err, tt.want)) {
+					errs <- fmt.Errorf("Validate(%q) on %q = %v, want %v", tt.query, tt.driver, err, tt.want)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+// benchmarkQuery is a query of the size and shape tool calls send
+const benchmarkQuery = `WITH recent AS (SELECT o.id, o.customer_id, o.total FROM sales.orders o WHERE o.created_at > '2024-01-01')
+SELECT c.name, SUM(r.total) AS total FROM recent r JOIN sales.customers c ON c.id = r.customer_id
+WHERE c.country IN ('PT', 'ES') GROUP BY c.name HAVING SUM(r.total) > 100 ORDER BY total DESC`
+
+func BenchmarkValidate(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		NewSQLValidator(benchmarkQuery).Validate()
+	}
+}
+
+func BenchmarkValidateParallel(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			NewSQLValidator(benchmarkQuery).Validate()
+		}
+	})
+}
@@ -0,0 +1,24 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+)
+
+// beginReadOnly starts the transaction a client query runs in, so a statement that slips
+// past the validator still cannot change data. Postgres, MySQL and Oracle start a read-only
+// transaction that rejects writes; SQL Server and SQLite have none, so the transaction is
+// only rolled back. The transaction runs on conn when it is set, on the pool otherwise.
+func (s *DbMCPServer) beginReadOnly(ctx context.Context, conn *sql.Conn) (*sql.Tx, error) {
+	opts := &sql.TxOptions{ReadOnly: s.queryBuilder.SupportsReadOnlyTransactions()}
+	if conn != nil {
+		return conn.BeginTx(ctx, opts)
+	}
+	return s.db.BeginTx(ctx, opts)
+}
+
+// endReadOnly rolls back a transaction started by beginReadOnly, discarding any change a
+// statement made in it
+func endReadOnly(tx *sql.Tx) {
+	_ = tx.Rollback()
+}
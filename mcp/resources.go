@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// syntaxReferenceURI is the URI of the SQL syntax quick reference of the connected database
+const syntaxReferenceURI = "db://syntax-reference"
+
+// syntaxSection is a titled group of tasks of the syntax reference, each with its snippet.
+// Titles and tasks are translated; snippets are SQL.
+type syntaxSection struct {
+	title   string
+	entries [][2]string
+}
+
+func (s *DbMCPServer) registerResources() {
+	// SQL syntax quick reference of the connected database
+	s.server.AddResource(mcp.NewResource(syntaxReferenceURI, "syntax_reference",
+		mcp.WithResourceDescription(translate("SQL syntax quick reference of the connected database: pagination, date and string functions, identifier quoting. Read it before writing queries for execute_query")),
+		mcp.WithMIMEType("text/markdown"),
+	), s.handleSyntaxReference)
+}
+
+func (s *DbMCPServer) handleSyntaxReference(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	view, release := s.defaultConn.acquire()
+	defer release()
+	if err := view.requireConnection(); err != nil {
+		return nil, errors.New(localizeError(err))
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      syntaxReferenceURI,
+			MIMEType: "text/markdown",
+			Text:     formatSyntaxReference(view.queryBuilder.SyntaxReference()),
+		},
+	}, nil
+}
+
+// formatSyntaxReference returns the syntax reference of a dialect as a markdown document
+func formatSyntaxReference(ref SyntaxReferenceSQL) string {
+	sections := []syntaxSection{
+		{translate("Pagination"), [][2]string{
+			{translate("First 10 rows"), ref.FirstRows},
+			{translate("Rows 21 to 30"), ref.Page},
+		}},
+		{translate("Dates"), [][2]string{
+			{translate("Current date and time"), ref.CurrentTimestamp},
+			{translate("Current date"), ref.CurrentDate},
+			{translate("Add 7 days"), ref.AddDays},
+			{translate("Days between two dates"), ref.DaysBetween},
+			{translate("Year of a date"), ref.Year},
+			{translate("First day of the month"), ref.TruncateToMonth},
+			{translate("Format as YYYY-MM-DD"), ref.FormatDate},
+		}},
+		{translate("Strings"), [][2]string{
+			{translate("Concatenate"), ref.Concat},
+			{translate("Substring"), ref.Substring},
+			{translate("Length"), ref.Length},
+			{translate("Position of a substring"), ref.Position},
+			{translate("Case-insensitive match"), ref.CaseInsensitive},
+			{translate("Aggregate values into a list"), ref.Aggregate},
+		}},
+		{translate("Other"), [][2]string{
+			{translate("Quote an identifier"), ref.QuoteIdentifier},
+			{translate("First non-null value"), ref.Coalesce},
+			{translate("Convert to text"), ref.CastToText},
+			{translate("Select without a table"), ref.SelectWithoutTable},
+			{translate("Bind execute_query parameters"), ref.Parameter},
+		}},
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s: %s\n\n", translate("SQL syntax quick reference"), ref.Product)
+	b.WriteString(translate("execute_query runs a single SELECT or WITH statement. In the examples t is a table, d a date, s a string and a, b any values.") + "\n")
+	for _, section := range sections {
+		fmt.Fprintf(&b, "\n## %s\n\n", section.title)
+		for _, entry := range section.entries {
+			fmt.Fprintf(&b, "- %s: %s\n", entry[0], entry[1])
+		}
+	}
+	return b.String()
+}
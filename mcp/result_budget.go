@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// resultBudget tracks the approximate memory held by result rows being built,
+// so a single wide or large query cannot exhaust the server's memory
+type resultBudget struct {
+	limit    int64
+	used     int64
+	exceeded bool
+}
+
+// newResultBudget creates a budget with the server's result memory cap
+func (s *DbMCPServer) newResultBudget() *resultBudget {
+	return &resultBudget{limit: s.maxResultBytes}
+}
+
+// Add accounts for a row of formatted values and reports whether it still fits in the
+// budget. Once the cap is exceeded the row must be discarded and reading stopped.
+func (b *resultBudget) Add(values []interface{}) bool {
+	if b == nil || b.limit <= 0 {
+		return true
+	}
+
+	size := int64(ResultRowOverhead)
+	for _, value := range values {
+		size += ResultEntryOverhead + estimateValueSize(value)
+	}
+
+	if b.used+size > b.limit {
+		b.exceeded = true
+		return false
+	}
+	b.used += size
+	return true
+}
+
+// Exceeded reports whether rows were dropped because of the memory cap
+func (b *resultBudget) Exceeded() bool {
+	return b != nil && b.exceeded
+}
+
+// Annotate marks a response as truncated by the memory cap, keeping the partial rows
+func (b *resultBudget) Annotate(response map[string]interface{}) {
+	if !b.Exceeded() {
+		return
+	}
+	response["truncated"] = true
+	response["memory_limit_exceeded"] = true
+	response["result_bytes"] = b.used
+	response["max_result_bytes"] = b.limit
+}
+
+// estimateValueSize approximates the memory held by a formatted column value
+func estimateValueSize(value interface{}) int64 {
+	switch v := value.(type) {
+	case nil:
+		return 0
+	case string:
+		return int64(len(v))
+	case []byte:
+		return int64(len(v))
+	case bool:
+		return 1
+	case time.Time:
+		return 24
+	case binaryValue:
+		return int64(len(v.Base64)) + 32
+	default:
+		return 8
+	}
+}
+
+// getEnvMaxResultRows reads the maximum number of rows of a result from DB_MAX_RESULT_ROWS
+func getEnvMaxResultRows() int {
+	value := os.Getenv("DB_MAX_RESULT_ROWS")
+	if value == "" {
+		return DefaultMaxResultRows
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		log.Printf("Warning: Ignoring invalid DB_MAX_RESULT_ROWS=%q", value)
+		return DefaultMaxResultRows
+	}
+	return n
+}
+
+// getEnvMaxResultBytes reads the result memory cap from DB_MAX_RESULT_BYTES (0 disables it)
+func getEnvMaxResultBytes() int64 {
+	value := os.Getenv("DB_MAX_RESULT_BYTES")
+	if value == "" {
+		return DefaultMaxResultBytes
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		log.Printf("Warning: Ignoring invalid DB_MAX_RESULT_BYTES=%q", value)
+		return DefaultMaxResultBytes
+	}
+	return n
+}
@@ -0,0 +1,271 @@
+package mcp
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxSafeInteger is the largest integer a JSON client reading numbers as doubles keeps exact
+const maxSafeInteger = 1<<53 - 1
+
+// valueKind is how the values of a result column are encoded, from its database type
+type valueKind int
+
+const (
+	valueKindDefault valueKind = iota // by the type of the driver value
+	valueKindDecimal                  // exact numerics, as strings so no digit is lost to float64
+	valueKindInteger                  // integers, which some drivers return as text
+	valueKindBool
+	valueKindUUID
+	valueKindGUID   // SQL Server UNIQUEIDENTIFIER, whose first three groups are little-endian
+	valueKindText   // character data, which MySQL returns as bytes
+	valueKindBinary // binary data, as base64 even when it happens to be valid UTF-8
+)
+
+// binaryValue is a binary value encoded as base64. Only the first MaxBinaryValueBytes are
+// encoded, so a large BLOB cannot blow up the response; size is the full length in bytes.
+type binaryValue struct {
+	Base64    string `json:"base64"`
+	Size      int    `json:"size"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+// String returns the base64 data, as written in CSV and markdown
+func (b binaryValue) String() string {
+	return b.Base64
+}
+
+// newBinaryValue encodes up to MaxBinaryValueBytes of data
+func newBinaryValue(data []byte) binaryValue {
+	value := binaryValue{Size: len(data), Truncated: len(data) > MaxBinaryValueBytes}
+	if value.Truncated {
+		data = data[:MaxBinaryValueBytes]
+	}
+	value.Base64 = base64.StdEncoding.EncodeToString(data)
+	return value
+}
+
+// resultEncoder converts the values scanned from a result to the values the tools return.
+// The driver value alone is ambiguous: MySQL returns every column as text without bound
+// parameters, SQL Server returns decimals as text and GUIDs as raw bytes, and SQLite
+// returns declared BOOLEAN columns as integers. The database type of each column decides.
+type resultEncoder struct {
+	kinds []valueKind
+}
+
+// newResultEncoder returns the encoder of the columns of rows. Without column types the
+// values are encoded by their driver type alone.
+func newResultEncoder(rows *sql.Rows) *resultEncoder {
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return &resultEncoder{}
+	}
+	kinds := make([]valueKind, len(columnTypes))
+	for i, columnType := range columnTypes {
+		kinds[i] = valueKindOf(columnType.DatabaseTypeName())
+	}
+	return &resultEncoder{kinds: kinds}
+}
+
+// encode replaces the scanned values of a row by their encoded values
+func (e *resultEncoder) encode(values []interface{}) {
+	for i, value := range values {
+		values[i] = e.value(i, value)
+	}
+}
+
+// value returns the encoded value of column i
+func (e *resultEncoder) value(i int, value interface{}) interface{} {
+	kind := valueKindDefault
+	if i < len(e.kinds) {
+		kind = e.kinds[i]
+	}
+	return encodeValue(kind, value)
+}
+
+// valueKindOf returns the encoding of a column from its database type name
+func valueKindOf(databaseType string) valueKind {
+	name := strings.ToUpper(strings.TrimSpace(databaseType))
+	if i := strings.IndexByte(name, '('); i >= 0 {
+		name = strings.TrimSpace(name[:i])
+	}
+	switch {
+	case name == "UNIQUEIDENTIFIER":
+		return valueKindGUID
+	case name == "UUID":
+		return valueKindUUID
+	case strings.Contains(name, "BINARY") || strings.Contains(name, "BLOB") || name == "BYTEA" || name == "IMAGE" || name == "RAW" || name == "LONG RAW":
+		return valueKindBinary
+	case strings.Contains(name, "CHAR") || strings.Contains(name, "TEXT") || strings.Contains(name, "CLOB") || name == "JSON" || name == "XML":
+		return valueKindText
+	case strings.Contains(name, "BOOL") || name == "BIT":
+		return valueKindBool
+	case strings.Contains(name, "DECIMAL") || strings.Contains(name, "NUMERIC") || strings.Contains(name, "MONEY") || name == "NUMBER" || name == "DEC":
+		return valueKindDecimal
+	case strings.Contains(name, "INT") && !strings.Contains(name, "POINT") && !strings.Contains(name, "INTERVAL"):
+		return valueKindInteger
+	default:
+		return valueKindDefault
+	}
+}
+
+// encodeValue converts a scanned value to its JSON value for a column of the given kind,
+// falling back to formatValue for values the kind does not apply to
+func encodeValue(kind valueKind, value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	switch kind {
+	case valueKindDecimal:
+		switch v := value.(type) {
+		case []byte:
+			return string(v)
+		case string:
+			return v
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		case int64:
+			return strconv.FormatInt(v, 10)
+		case fmt.Stringer:
+			return v.String()
+		}
+	case valueKindInteger:
+		switch v := value.(type) {
+		case []byte:
+			return parseInteger(string(v))
+		case string:
+			return parseInteger(v)
+		}
+	case valueKindBool:
+		switch v := value.(type) {
+		case bool:
+			return v
+		case int64:
+			return v != 0
+		case []byte:
+			// MySQL returns BIT(1) as a raw byte, Postgres as the digit
+			if len(v) == 1 {
+				switch v[0] {
+				case 0, '0':
+					return false
+				case 1, '1':
+					return true
+				}
+			}
+			if b, err := strconv.ParseBool(string(v)); err == nil {
+				return b
+			}
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b
+			}
+		}
+	case valueKindText:
+		if v, ok := value.([]byte); ok && utf8.Valid(v) {
+			return string(v)
+		}
+	case valueKindBinary:
+		switch v := value.(type) {
+		case []byte:
+			return newBinaryValue(v)
+		case string:
+			return newBinaryValue([]byte(v))
+		}
+	case valueKindUUID, valueKindGUID:
+		if v, ok := value.([]byte); ok && len(v) == 16 {
+			return formatUUID(v, kind == valueKindGUID)
+		}
+	}
+
+	return formatValue(value)
+}
+
+// parseInteger returns an integer read as text as a number, or the text itself when it is
+// not an integer JSON clients keep exact
+func parseInteger(text string) interface{} {
+	n, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return text
+	}
+	return safeInteger(n)
+}
+
+// safeInteger returns n as a number within the exact range of a double, as text past it
+func safeInteger(n int64) interface{} {
+	if n > maxSafeInteger || n < -maxSafeInteger {
+		return strconv.FormatInt(n, 10)
+	}
+	return n
+}
+
+// formatUUID formats 16 bytes as a UUID. SQL Server stores the first three groups of a
+// GUID little-endian, so they are swapped back when mixedEndian is set.
+func formatUUID(b []byte, mixedEndian bool) string {
+	u := make([]byte, 16)
+	copy(u, b)
+	if mixedEndian {
+		u[0], u[1], u[2], u[3] = u[3], u[2], u[1], u[0]
+		u[4], u[5] = u[5], u[4]
+		u[6], u[7] = u[7], u[6]
+	}
+	s := hex.EncodeToString(u)
+	return s[0:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:32]
+}
+
+// formatValue converts database values to JSON-safe formats: integers as int64, or as text
+// past the exact range of a double; floats that JSON cannot represent as text; timestamps
+// as RFC 3339 with their offset; and bytes as text when they are short valid UTF-8, as
+// base64 otherwise
+func formatValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case []byte:
+		if len(v) <= MaxBinaryValueBytes && utf8.Valid(v) {
+			return string(v)
+		}
+		return newBinaryValue(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case int64:
+		return safeInteger(v)
+	case int:
+		return safeInteger(int64(v))
+	case int32:
+		return int64(v)
+	case int16:
+		return int64(v)
+	case int8:
+		return int64(v)
+	case uint32:
+		return int64(v)
+	case uint16:
+		return int64(v)
+	case uint8:
+		return int64(v)
+	case uint64:
+		if v > maxSafeInteger {
+			return strconv.FormatUint(v, 10)
+		}
+		return int64(v)
+	case float32:
+		// Formatted at single precision, so 0.1 stays 0.1
+		f, _ := strconv.ParseFloat(strconv.FormatFloat(float64(v), 'g', -1, 32), 64)
+		return formatValue(f)
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return strconv.FormatFloat(v, 'g', -1, 64)
+		}
+		return v
+	case nil:
+		return nil
+	default:
+		return v
+	}
+}
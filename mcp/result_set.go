@@ -0,0 +1,293 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// columnKind is the storage type of a result column
+type columnKind int
+
+const (
+	columnKindUnknown columnKind = iota // only NULLs seen so far
+	columnKindInt
+	columnKindFloat
+	columnKindString
+	columnKindBool
+	columnKindAny // mixed or uncommon types
+)
+
+// resultColumn stores the values of one column in a slice of its type
+type resultColumn struct {
+	name   string
+	kind   columnKind
+	nulls  []bool
+	ints   []int64
+	floats []float64
+	texts  []string
+	bools  []bool
+	values []interface{}
+}
+
+// ResultSet holds query results column by column. Compared to a slice of row maps it
+// allocates per column instead of per cell, and serializes to the same JSON rows.
+type ResultSet struct {
+	columns []*resultColumn
+	rows    int
+}
+
+// newResultSet creates an empty result set with the given column names
+func newResultSet(names []string) *ResultSet {
+	rs := &ResultSet{columns: make([]*resultColumn, len(names))}
+	for i, name := range names {
+		rs.columns[i] = &resultColumn{name: name}
+	}
+	return rs
+}
+
+// AppendRow adds a row of formatted values, in column order
+func (rs *ResultSet) AppendRow(values []interface{}) {
+	for i, col := range rs.columns {
+		col.append(values[i], rs.rows)
+	}
+	rs.rows++
+}
+
+// Len returns the number of rows
+func (rs *ResultSet) Len() int {
+	if rs == nil {
+		return 0
+	}
+	return rs.rows
+}
+
+// ColumnNames returns the column names in order
+func (rs *ResultSet) ColumnNames() []string {
+	names := make([]string, len(rs.columns))
+	for i, col := range rs.columns {
+		names[i] = col.name
+	}
+	return names
+}
+
+// Value returns the value at the given row and column index
+func (rs *ResultSet) Value(row, col int) interface{} {
+	return rs.columns[col].value(row)
+}
+
+// MarshalJSON writes the rows as an array of objects keyed by column name,
+// without materializing a map per row
+func (rs *ResultSet) MarshalJSON() ([]byte, error) {
+	if rs == nil || rs.rows == 0 {
+		return []byte("null"), nil
+	}
+
+	keys := make([][]byte, len(rs.columns))
+	for i, col := range rs.columns {
+		key, err := json.Marshal(col.name)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = append(key, ':')
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for row := 0; row < rs.rows; row++ {
+		if row > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('{')
+		for i, col := range rs.columns {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.Write(keys[i])
+			if err := col.writeJSON(&buf, row); err != nil {
+				return nil, err
+			}
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(']')
+
+	return buf.Bytes(), nil
+}
+
+// CSV writes the rows as RFC 4180 CSV, optionally preceded by a header line with the
+// column names. NULLs are written as empty fields.
+func (rs *ResultSet) CSV(header bool) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	record := make([]string, len(rs.columns))
+	if header {
+		if err := w.Write(rs.ColumnNames()); err != nil {
+			return "", err
+		}
+	}
+	for row := 0; row < rs.rows; row++ {
+		for i, col := range rs.columns {
+			record[i] = col.csvField(row)
+		}
+		if err := w.Write(record); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// append stores a value at index row
+func (c *resultColumn) append(v interface{}, row int) {
+	if v == nil {
+		c.nulls = append(c.nulls, true)
+		c.appendZero()
+		return
+	}
+
+	kind := kindOf(v)
+	if c.kind == columnKindUnknown {
+		c.kind = kind
+		// Back-fill the NULLs seen before the first value
+		for i := 0; i < row; i++ {
+			c.appendZero()
+		}
+	} else if c.kind != kind && c.kind != columnKindAny {
+		c.toAny(row)
+	}
+
+	c.nulls = append(c.nulls, false)
+	switch c.kind {
+	case columnKindInt:
+		c.ints = append(c.ints, v.(int64))
+	case columnKindFloat:
+		c.floats = append(c.floats, v.(float64))
+	case columnKindString:
+		c.texts = append(c.texts, v.(string))
+	case columnKindBool:
+		c.bools = append(c.bools, v.(bool))
+	default:
+		c.values = append(c.values, v)
+	}
+}
+
+// appendZero keeps the typed slice aligned for a NULL
+func (c *resultColumn) appendZero() {
+	switch c.kind {
+	case columnKindInt:
+		c.ints = append(c.ints, 0)
+	case columnKindFloat:
+		c.floats = append(c.floats, 0)
+	case columnKindString:
+		c.texts = append(c.texts, "")
+	case columnKindBool:
+		c.bools = append(c.bools, false)
+	case columnKindAny:
+		c.values = append(c.values, nil)
+	}
+}
+
+// toAny converts the first rows of a typed column to generic storage
+func (c *resultColumn) toAny(rows int) {
+	values := make([]interface{}, rows)
+	for i := 0; i < rows; i++ {
+		values[i] = c.value(i)
+	}
+	c.ints, c.floats, c.texts, c.bools = nil, nil, nil, nil
+	c.values = values
+	c.kind = columnKindAny
+}
+
+// value returns the value at index row
+func (c *resultColumn) value(row int) interface{} {
+	if c.nulls[row] {
+		return nil
+	}
+	switch c.kind {
+	case columnKindInt:
+		return c.ints[row]
+	case columnKindFloat:
+		return c.floats[row]
+	case columnKindString:
+		return c.texts[row]
+	case columnKindBool:
+		return c.bools[row]
+	default:
+		return c.values[row]
+	}
+}
+
+// writeJSON writes the value at index row
+func (c *resultColumn) writeJSON(buf *bytes.Buffer, row int) error {
+	if c.nulls[row] {
+		buf.WriteString("null")
+		return nil
+	}
+	switch c.kind {
+	case columnKindInt:
+		buf.Write(strconv.AppendInt(buf.AvailableBuffer(), c.ints[row], 10))
+		return nil
+	case columnKindBool:
+		buf.Write(strconv.AppendBool(buf.AvailableBuffer(), c.bools[row]))
+		return nil
+	}
+
+	data, err := json.Marshal(c.value(row))
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	return nil
+}
+
+// csvField returns the value at index row as a CSV field
+func (c *resultColumn) csvField(row int) string {
+	if c.nulls[row] {
+		return ""
+	}
+	switch c.kind {
+	case columnKindInt:
+		return strconv.FormatInt(c.ints[row], 10)
+	case columnKindFloat:
+		return strconv.FormatFloat(c.floats[row], 'f', -1, 64)
+	case columnKindString:
+		return c.texts[row]
+	case columnKindBool:
+		return strconv.FormatBool(c.bools[row])
+	}
+
+	switch v := c.values[row].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// kindOf returns the storage kind for a formatted value
+func kindOf(v interface{}) columnKind {
+	switch v.(type) {
+	case int64:
+		return columnKindInt
+	case float64:
+		return columnKindFloat
+	case string:
+		return columnKindString
+	case bool:
+		return columnKindBool
+	default:
+		return columnKindAny
+	}
+}
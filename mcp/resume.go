@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	mssql "github.com/denisenkom/go-mssqldb"
+)
+
+// sqlServerDatabaseUnavailable is returned by Azure SQL while a serverless database is
+// paused or resuming ("Database '%s' on server '%s' is not currently available")
+const sqlServerDatabaseUnavailable = 40613
+
+// resumeConnector wraps a SQL Server connector and retries logins rejected because an
+// Azure SQL serverless database is auto-paused. The first login wakes the database up,
+// so retrying with backoff lets the query continue once it is online.
+type resumeConnector struct {
+	driver.Connector
+}
+
+// Connect opens a connection, retrying while the database is resuming and the context allows
+func (c *resumeConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	backoff := AzureResumeInitialBackoff
+	for attempt := 1; ; attempt++ {
+		conn, err := c.Connector.Connect(ctx)
+		if err == nil || !isDatabaseResuming(err) {
+			return conn, err
+		}
+
+		log.Printf("Database is paused or resuming (Azure SQL serverless auto-pause), retrying in %s (attempt %d)", backoff, attempt)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("%w: %w", ErrDatabaseResuming, err)
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if backoff > AzureResumeMaxBackoff {
+			backoff = AzureResumeMaxBackoff
+		}
+	}
+}
+
+// isDatabaseResuming reports whether err is a login rejected by a paused or resuming database
+func isDatabaseResuming(err error) bool {
+	if errors.Is(err, ErrDatabaseResuming) {
+		return true
+	}
+
+	var msErr mssql.Error
+	return errors.As(err, &msErr) && msErr.Number == sqlServerDatabaseUnavailable
+}
+
+// pingDatabase verifies a new connection pool. A database that is resuming from
+// auto-pause gets up to AzureResumeTimeout to come online instead of DBPingTimeout.
+func pingDatabase(ctx context.Context, db *sql.DB) error {
+	pingCtx, cancel := context.WithTimeout(ctx, DBPingTimeout)
+	err := db.PingContext(pingCtx)
+	cancel()
+	if err == nil || !isDatabaseResuming(err) {
+		return err
+	}
+
+	log.Printf("Waiting up to %s for the database to resume", AzureResumeTimeout)
+
+	resumeCtx, cancel := context.WithTimeout(ctx, AzureResumeTimeout)
+	defer cancel()
+
+	return db.PingContext(resumeCtx)
+}
+
+// connectionStatus describes a connection whose ping failed
+func connectionStatus(err error) string {
+	if isDatabaseResuming(err) {
+		return "resuming"
+	}
+	return "disconnected"
+}
@@ -0,0 +1,360 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// savedQueryStore keeps named query templates in the JSON file DB_SAVED_QUERIES_FILE, so a
+// team can bless a set of vetted queries that clients run with parameters only. The file
+// is read on every call, so edits made by hand apply without a restart, and replaced
+// atomically on every save. DB_SAVED_QUERIES_READONLY rejects saves, leaving the file to
+// its owners.
+type savedQueryStore struct {
+	mu       sync.Mutex
+	path     string
+	readOnly bool
+}
+
+// savedQueryFile is the content of DB_SAVED_QUERIES_FILE
+type savedQueryFile struct {
+	Queries []savedQuery `json:"queries"`
+}
+
+// savedQuery is a named query template
+type savedQuery struct {
+	Name        string                `json:"name"`
+	Description string                `json:"description,omitempty"`
+	Query       string                `json:"query"`
+	Parameters  []savedQueryParameter `json:"parameters,omitempty"`
+	SavedAt     time.Time             `json:"saved_at"`
+}
+
+// savedQueryParameter is a parameter of a query template, bound to the positional
+// placeholder of the query at its position
+type savedQueryParameter struct {
+	Name        string      `json:"name" jsonschema_description:"Parameter name"`
+	Description string      `json:"description,omitempty" jsonschema_description:"What the parameter means (optional)"`
+	Default     interface{} `json:"default,omitempty" jsonschema_description:"Value bound when run_saved_query does not pass the parameter (optional; without it the parameter is required)"`
+}
+
+// newSavedQueryStore returns the saved query store configured from the environment
+func newSavedQueryStore() *savedQueryStore {
+	return &savedQueryStore{
+		path:     strings.TrimSpace(os.Getenv("DB_SAVED_QUERIES_FILE")),
+		readOnly: getEnvSavedQueriesReadOnly(),
+	}
+}
+
+// getEnvSavedQueriesReadOnly reports whether DB_SAVED_QUERIES_READONLY rejects save_query
+func getEnvSavedQueriesReadOnly() bool {
+	value := os.Getenv("DB_SAVED_QUERIES_READONLY")
+	if value == "" {
+		return false
+	}
+	readOnly, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Warning: Ignoring invalid DB_SAVED_QUERIES_READONLY=%q", value)
+		return false
+	}
+	return readOnly
+}
+
+// enabled reports whether a file is configured
+func (st *savedQueryStore) enabled() bool {
+	return st.path != ""
+}
+
+// list returns the saved queries by name
+func (st *savedQueryStore) list() ([]savedQuery, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.load()
+}
+
+// get returns the saved query named name
+func (st *savedQueryStore) get(name string) (savedQuery, error) {
+	queries, err := st.list()
+	if err != nil {
+		return savedQuery{}, err
+	}
+	for _, query := range queries {
+		if strings.EqualFold(query.Name, name) {
+			return query, nil
+		}
+	}
+	return savedQuery{}, fmt.Errorf("%w: %s", ErrSavedQueryNotFound, name)
+}
+
+// save stores a query, replacing the one of the same name only when overwrite is set
+func (st *savedQueryStore) save(query savedQuery, overwrite bool) error {
+	if st.readOnly {
+		return ErrSavedQueriesReadOnly
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	queries, err := st.load()
+	if err != nil {
+		return err
+	}
+	replaced := false
+	for i := range queries {
+		if strings.EqualFold(queries[i].Name, query.Name) {
+			if !overwrite {
+				return fmt.Errorf("%w: %s", ErrSavedQueryExists, query.Name)
+			}
+			queries[i] = query
+			replaced = true
+		}
+	}
+	if !replaced {
+		queries = append(queries, query)
+	}
+	sortSavedQueries(queries)
+
+	data, err := json.MarshalIndent(savedQueryFile{Queries: queries}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrWritingSavedQueries, err)
+	}
+
+	// Written next to the file and renamed over it, so a reader never sees half a file
+	tmp, err := os.CreateTemp(filepath.Dir(st.path), filepath.Base(st.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrWritingSavedQueries, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(append(data, '\n')); err == nil {
+		err = tmp.Close()
+	} else {
+		tmp.Close()
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), st.path)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrWritingSavedQueries, err)
+	}
+	return nil
+}
+
+// load reads the saved queries from the file; a missing file holds none
+func (st *savedQueryStore) load() ([]savedQuery, error) {
+	data, err := os.ReadFile(st.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrReadingSavedQueries, err)
+	}
+
+	var file savedQueryFile
+	if err = json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrReadingSavedQueries, err)
+	}
+	sortSavedQueries(file.Queries)
+	return file.Queries, nil
+}
+
+// sortSavedQueries sorts saved queries by name
+func sortSavedQueries(queries []savedQuery) {
+	sort.Slice(queries, func(i, j int) bool {
+		return strings.ToLower(queries[i].Name) < strings.ToLower(queries[j].Name)
+	})
+}
+
+// validateSavedQuery checks a query template as it is saved and again before it runs, since
+// the file may have been edited by hand
+func (s *DbMCPServer) validateSavedQuery(query savedQuery) error {
+	if !isPlainIdentifier(query.Name) {
+		return fmt.Errorf("%w: %s", ErrInvalidSavedQueryName, query.Name)
+	}
+	if query.Query == "" {
+		return ErrQueryRequired
+	}
+	if err := newDriverSQLValidator(query.Query, "", s.validationPolicy, s.objects).Validate().Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrQueryNotAllowed, err)
+	}
+
+	if len(query.Parameters) > MaxQueryParameters {
+		return fmt.Errorf("%w "+translate("(maximum %d)"), ErrTooManyParameters, MaxQueryParameters)
+	}
+	seen := map[string]bool{}
+	for _, param := range query.Parameters {
+		if !isPlainIdentifier(param.Name) {
+			return fmt.Errorf("%w: "+translate("invalid parameter name %q"), ErrInvalidParameters, param.Name)
+		}
+		if seen[strings.ToLower(param.Name)] {
+			return fmt.Errorf("%w: %s", ErrDuplicateParameter, param.Name)
+		}
+		seen[strings.ToLower(param.Name)] = true
+		if _, ok := queryParameterValue(param.Default); !ok {
+			return fmt.Errorf("%w: "+translate("parameter %s must be a string, number, boolean or null"), ErrInvalidParameters, param.Name)
+		}
+	}
+	return nil
+}
+
+// bindSavedQuery returns the values of the parameters of a saved query in their declared
+// order: the value passed by name, or the default of the parameter
+func bindSavedQuery(query savedQuery, values map[string]interface{}) ([]interface{}, error) {
+	for name := range values {
+		known := false
+		for _, param := range query.Parameters {
+			if strings.EqualFold(param.Name, name) {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownParameter, name)
+		}
+	}
+
+	params := make([]interface{}, 0, len(query.Parameters))
+	for _, param := range query.Parameters {
+		value, ok := savedQueryValue(values, param.Name)
+		if !ok {
+			if param.Default == nil {
+				return nil, fmt.Errorf("%w: %s", ErrMissingParameter, param.Name)
+			}
+			value = param.Default
+		}
+		params = append(params, value)
+	}
+	return params, nil
+}
+
+// savedQueryValue returns the value passed for a parameter, matching its name
+// case-insensitively
+func savedQueryValue(values map[string]interface{}, name string) (interface{}, bool) {
+	for key, value := range values {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// saveQueryArgs are the arguments of save_query
+type saveQueryArgs struct {
+	Name        string                `json:"name" jsonschema_description:"Name of the saved query (letters, digits and underscores)"`
+	Query       string                `json:"query" jsonschema_description:"SQL query to save (SELECT only), with a positional placeholder for each parameter (@p1, $1, ? or :1, see db://syntax-reference)"`
+	Description string                `json:"description,omitempty" jsonschema_description:"What the query answers (optional)"`
+	Parameters  []savedQueryParameter `json:"parameters,omitempty" jsonschema_description:"Parameters of the query, in the order of its placeholders (optional)"`
+	Overwrite   bool                  `json:"overwrite,omitempty" jsonschema_description:"Replace a saved query of the same name (default: false)"`
+}
+
+func (s *DbMCPServer) toolSaveQuery() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("save_query", "Saves a named, parameterized SELECT query to DB_SAVED_QUERIES_FILE, so it can be run later with run_saved_query by passing only its parameters. The query is validated as in execute_query before it is saved", s.handleSaveQuery)
+}
+
+func (s *DbMCPServer) handleSaveQuery(ctx context.Context, request mcp.CallToolRequest, args saveQueryArgs) (*mcp.CallToolResult, error) {
+	if !s.savedQueries.enabled() {
+		return toolErrorResult(ErrSavedQueriesNotConfigured), nil
+	}
+
+	query := savedQuery{
+		Name:        args.Name,
+		Description: args.Description,
+		Query:       strings.TrimSpace(args.Query),
+		Parameters:  args.Parameters,
+		SavedAt:     time.Now().UTC(),
+	}
+	if err := s.validateSavedQuery(query); err != nil {
+		return toolErrorResult(err), nil
+	}
+	if err := s.savedQueries.save(query, args.Overwrite); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	return jsonToolResult(map[string]interface{}{
+		"saved": query,
+	}), nil
+}
+
+// listSavedQueriesArgs are the arguments of list_saved_queries
+type listSavedQueriesArgs struct {
+	NameFilter string `json:"name_filter,omitempty" jsonschema_description:"Filter by query name (optional)"`
+}
+
+func (s *DbMCPServer) toolListSavedQueries() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("list_saved_queries", "Lists the saved queries of DB_SAVED_QUERIES_FILE with their description, SQL and parameters. Prefer running a saved query with run_saved_query over writing SQL for the same question", s.handleListSavedQueries)
+}
+
+func (s *DbMCPServer) handleListSavedQueries(ctx context.Context, request mcp.CallToolRequest, args listSavedQueriesArgs) (*mcp.CallToolResult, error) {
+	if !s.savedQueries.enabled() {
+		return toolErrorResult(ErrSavedQueriesNotConfigured), nil
+	}
+
+	queries, err := s.savedQueries.list()
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	filter := strings.ToLower(args.NameFilter)
+	matched := []savedQuery{}
+	for _, query := range queries {
+		if strings.Contains(strings.ToLower(query.Name), filter) {
+			matched = append(matched, query)
+		}
+	}
+
+	return jsonToolResult(map[string]interface{}{
+		"queries":   matched,
+		"count":     len(matched),
+		"read_only": s.savedQueries.readOnly,
+	}), nil
+}
+
+// runSavedQueryArgs are the arguments of run_saved_query
+type runSavedQueryArgs struct {
+	Name       string                 `json:"name" jsonschema_description:"Name of the saved query"`
+	Parameters map[string]interface{} `json:"parameters,omitempty" jsonschema_description:"Values of the parameters of the saved query by name, e.g. {\"customer_id\": 42} (optional for parameters with a default)"`
+	MaxRows    int                    `json:"max_rows,omitempty" jsonschema_description:"Maximum number of rows to be returned (default: 100, maximum: DB_MAX_RESULT_ROWS, 10000 unless configured)"`
+	Format     string                 `json:"format,omitempty" jsonschema_description:"Rows format: json (an object per row), csv (CSV text in the csv field, NULLs as empty fields) or markdown (the whole response as a markdown table) (default: json)" jsonschema:"enum=json,enum=csv,enum=markdown"`
+}
+
+func (s *DbMCPServer) toolRunSavedQuery() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("run_saved_query", "Runs a saved query of DB_SAVED_QUERIES_FILE with the given parameters and returns its results as execute_query does. The SQL of the query cannot be changed, only its parameters", s.handleRunSavedQuery)
+}
+
+func (s *DbMCPServer) handleRunSavedQuery(ctx context.Context, request mcp.CallToolRequest, args runSavedQueryArgs) (*mcp.CallToolResult, error) {
+	if !s.savedQueries.enabled() {
+		return toolErrorResult(ErrSavedQueriesNotConfigured), nil
+	}
+
+	query, err := s.savedQueries.get(args.Name)
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+	if err = s.validateSavedQuery(query); err != nil {
+		return toolErrorResult(err), nil
+	}
+	params, err := bindSavedQuery(query, args.Parameters)
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	// Runs as execute_query does, so the query is limited, watched and cached the same way
+	return s.handleExecuteQuery(ctx, request, executeQueryArgs{
+		Query:      query.Query,
+		Parameters: params,
+		MaxRows:    args.MaxRows,
+		Format:     args.Format,
+	})
+}
@@ -0,0 +1,237 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The secrets backends are read through their REST APIs, with the credentials their own
+// tools read from the environment:
+//   - vault: VAULT_ADDR, VAULT_TOKEN and the optional VAULT_NAMESPACE. DB_SECRET_NAME is
+//     the path of the secret under /v1, e.g. secret/data/db-mcp for a KV version 2 engine
+//   - aws: AWS_REGION (or AWS_DEFAULT_REGION), AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+//     the optional AWS_SESSION_TOKEN. DB_SECRET_NAME is the name or ARN of the secret
+//   - azure: AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_SECRET of a service
+//     principal, or else the managed identity of the host (of AZURE_CLIENT_ID when set).
+//     DB_SECRET_NAME is vault-name/secret-name, or the URL of the secret
+
+// secretHTTPClient is the HTTP client of the secrets backends
+var secretHTTPClient = &http.Client{Timeout: SecretFetchTimeout}
+
+// doSecretRequest sends a request to a secrets backend and decodes its JSON response
+func doSecretRequest(request *http.Request, target interface{}) error {
+	response, err := secretHTTPClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		message := strings.TrimSpace(string(body))
+		if len(message) > 200 {
+			message = message[:200]
+		}
+		return fmt.Errorf("%s: %s", response.Status, message)
+	}
+	return json.Unmarshal(body, target)
+}
+
+// fetchVaultSecret reads a secret of HashiCorp Vault. Secrets of the KV version 2 engine
+// hold their values under data.data, and those of version 1 under data.
+func fetchVaultSecret(ctx context.Context, path string) (interface{}, error) {
+	addr, token := os.Getenv("VAULT_ADDR"), os.Getenv("VAULT_TOKEN")
+	if addr == "" || token == "" {
+		return nil, fmt.Errorf("%w: VAULT_ADDR and VAULT_TOKEN are required", ErrSecretBackendConfig)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(addr, "/")+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("X-Vault-Token", token)
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		request.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err = doSecretRequest(request, &secret); err != nil {
+		return nil, err
+	}
+	if data, ok := secret.Data["data"].(map[string]interface{}); ok {
+		return data, nil
+	}
+	return secret.Data, nil
+}
+
+// fetchAWSSecret reads the string of a secret of AWS Secrets Manager, with a request
+// signed with Signature Version 4
+func fetchAWSSecret(ctx context.Context, secretID string) (interface{}, error) {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	accessKey, secretKey := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if region == "" || accessKey == "" || secretKey == "" {
+		return nil, fmt.Errorf("%w: AWS_REGION, AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required", ErrSecretBackendConfig)
+	}
+
+	body, _ := json.Marshal(map[string]string{"SecretId": secretID})
+	host := "secretsmanager." + region + ".amazonaws.com"
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://"+host+"/", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	headers := map[string]string{
+		"content-type": "application/x-amz-json-1.1",
+		"host":         host,
+		"x-amz-date":   time.Now().UTC().Format("20060102T150405Z"),
+		"x-amz-target": "secretsmanager.GetSecretValue",
+	}
+	if token := os.Getenv("AWS_SESSION_TOKEN"); token != "" {
+		headers["x-amz-security-token"] = token
+	}
+	for name, value := range headers {
+		if name != "host" {
+			request.Header.Set(name, value)
+		}
+	}
+	request.Header.Set("Authorization", signAWSRequest(headers, body, region, "secretsmanager", accessKey, secretKey))
+
+	var secret struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err = doSecretRequest(request, &secret); err != nil {
+		return nil, err
+	}
+	return secret.SecretString, nil
+}
+
+// signAWSRequest returns the Authorization header of a POST request to / signed with
+// Signature Version 4, over all of its headers
+func signAWSRequest(headers map[string]string, body []byte, region, service, accessKey, secretKey string) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+	bodyHash := sha256.Sum256(body)
+	canonicalRequest := strings.Join([]string{"POST", "/", "", canonicalHeaders.String(), signedHeaders, hex.EncodeToString(bodyHash[:])}, "\n")
+
+	amzDate := headers["x-amz-date"]
+	scope := amzDate[:8] + "/" + region + "/" + service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := []byte("AWS4" + secretKey)
+	for _, part := range []string{amzDate[:8], region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	return "AWS4-HMAC-SHA256 Credential=" + accessKey + "/" + scope + ", SignedHeaders=" + signedHeaders + ", Signature=" + signature
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data with key
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// fetchAzureSecret reads the value of a secret of Azure Key Vault
+func fetchAzureSecret(ctx context.Context, name string) (interface{}, error) {
+	secretURL := name
+	if !strings.HasPrefix(strings.ToLower(name), "https://") {
+		vault, secretName, ok := strings.Cut(name, "/")
+		if !ok || vault == "" || secretName == "" {
+			return nil, fmt.Errorf("%w: DB_SECRET_NAME must be vault-name/secret-name or the URL of the secret", ErrSecretBackendConfig)
+		}
+		secretURL = "https://" + vault + ".vault.azure.net/secrets/" + secretName
+	}
+
+	token, err := azureKeyVaultToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, secretURL+"?api-version="+AzureKeyVaultAPIVersion, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Authorization", "Bearer "+token)
+
+	var secret struct {
+		Value string `json:"value"`
+	}
+	if err = doSecretRequest(request, &secret); err != nil {
+		return nil, err
+	}
+	return secret.Value, nil
+}
+
+// azureKeyVaultToken returns an access token to Azure Key Vault, of the service principal
+// of AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_SECRET, or else of the managed
+// identity of the host
+func azureKeyVaultToken(ctx context.Context) (string, error) {
+	tenant, clientID, clientSecret := os.Getenv("AZURE_TENANT_ID"), os.Getenv("AZURE_CLIENT_ID"), os.Getenv("AZURE_CLIENT_SECRET")
+
+	var request *http.Request
+	var err error
+	if tenant != "" && clientID != "" && clientSecret != "" {
+		form := url.Values{
+			"grant_type":    {"client_credentials"},
+			"client_id":     {clientID},
+			"client_secret": {clientSecret},
+			"scope":         {"https://vault.azure.net/.default"},
+		}
+		request, err = http.NewRequestWithContext(ctx, http.MethodPost, "https://login.microsoftonline.com/"+url.PathEscape(tenant)+"/oauth2/v2.0/token", strings.NewReader(form.Encode()))
+		if err != nil {
+			return "", err
+		}
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		query := url.Values{
+			"api-version": {"2018-02-01"},
+			"resource":    {"https://vault.azure.net"},
+		}
+		if clientID != "" {
+			query.Set("client_id", clientID)
+		}
+		request, err = http.NewRequestWithContext(ctx, http.MethodGet, "http://169.254.169.254/metadata/identity/oauth2/token?"+query.Encode(), nil)
+		if err != nil {
+			return "", err
+		}
+		request.Header.Set("Metadata", "true")
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err = doSecretRequest(request, &token); err != nil {
+		return "", fmt.Errorf("azure token: %w", err)
+	}
+	return token.AccessToken, nil
+}
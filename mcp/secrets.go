@@ -0,0 +1,274 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// A secrets backend keeps the credentials of the connection of the environment out of
+// plaintext environment variables. DB_SECRET_SOURCE selects HashiCorp Vault, AWS Secrets
+// Manager or Azure Key Vault, DB_SECRET_NAME the secret, and DB_SECRET_TARGET whether it
+// holds the whole connection string or the password set into DB_CONNECTION_STRING. The
+// secret is read again every DB_SECRET_REFRESH_INTERVAL, and the server reconnects when it
+// rotates. Named connections and configure_datasource take their credentials as they are.
+
+// secretSettings are the settings of the secrets backend
+type secretSettings struct {
+	source  string
+	name    string
+	key     string
+	target  string
+	refresh time.Duration
+}
+
+// getEnvSecretSettings reads the secrets backend settings from DB_SECRET_SOURCE,
+// DB_SECRET_NAME, DB_SECRET_KEY, DB_SECRET_TARGET and DB_SECRET_REFRESH_INTERVAL. The
+// source is empty when no secrets backend is configured.
+func getEnvSecretSettings() (secretSettings, error) {
+	settings := secretSettings{
+		source:  strings.ToLower(strings.TrimSpace(os.Getenv("DB_SECRET_SOURCE"))),
+		name:    strings.TrimSpace(os.Getenv("DB_SECRET_NAME")),
+		key:     strings.TrimSpace(os.Getenv("DB_SECRET_KEY")),
+		target:  strings.ToLower(strings.TrimSpace(os.Getenv("DB_SECRET_TARGET"))),
+		refresh: envDuration("DB_SECRET_REFRESH_INTERVAL", DefaultSecretRefreshInterval),
+	}
+	switch settings.source {
+	case "":
+		return settings, nil
+	case SecretSourceVault, SecretSourceAWS, SecretSourceAzure:
+	default:
+		return secretSettings{}, fmt.Errorf("%w: '%s'", ErrInvalidSecretSource, settings.source)
+	}
+	switch settings.target {
+	case "":
+		settings.target = SecretTargetConnectionString
+	case SecretTargetConnectionString, SecretTargetPassword:
+	default:
+		return secretSettings{}, fmt.Errorf("%w: '%s'", ErrInvalidSecretTarget, settings.target)
+	}
+	if settings.name == "" {
+		return secretSettings{}, ErrSecretNameRequired
+	}
+	// Secrets holding several values are read at the key named after the target
+	if settings.key == "" {
+		settings.key = settings.target
+	}
+	return settings, nil
+}
+
+// fetch reads the secret from the backend. A secret holding a JSON object, as Vault
+// secrets always do, is read at the key of the settings.
+func (c secretSettings) fetch(ctx context.Context) (string, error) {
+	var value interface{}
+	var err error
+	switch c.source {
+	case SecretSourceVault:
+		value, err = fetchVaultSecret(ctx, c.name)
+	case SecretSourceAWS:
+		value, err = fetchAWSSecret(ctx, c.name)
+	case SecretSourceAzure:
+		value, err = fetchAzureSecret(ctx, c.name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrSecretFetch, err)
+	}
+
+	if text, ok := value.(string); ok {
+		var object map[string]interface{}
+		if !strings.HasPrefix(strings.TrimSpace(text), "{") || json.Unmarshal([]byte(text), &object) != nil {
+			return text, nil
+		}
+		value = object
+	}
+	object, _ := value.(map[string]interface{})
+	text, ok := object[c.key].(string)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrSecretKeyNotFound, c.key)
+	}
+	return text, nil
+}
+
+// secretRotation reads the connection string of the environment through the secrets
+// backend, and follows the rotation of the secret
+type secretRotation struct {
+	settings secretSettings
+	driver   string
+
+	mu       sync.Mutex
+	current  string // connection string of the secret the server last connected with
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// newSecretRotation reads the secrets backend settings from the environment, and returns
+// nil when no secrets backend is configured
+func newSecretRotation() (*secretRotation, error) {
+	settings, err := getEnvSecretSettings()
+	if err != nil || settings.source == "" {
+		return nil, err
+	}
+	return &secretRotation{
+		settings: settings,
+		driver:   getEnvDriver(),
+		stop:     make(chan struct{}),
+	}, nil
+}
+
+// resolve reads the secret and returns the connection string it gives, recording it as the
+// one the server connects with
+func (r *secretRotation) resolve(ctx context.Context) (string, error) {
+	connString, err := r.connString(ctx)
+	if err != nil {
+		return "", err
+	}
+	r.setCurrent(connString)
+	return connString, nil
+}
+
+// connString reads the secret and returns the connection string it gives: the secret
+// itself, or DB_CONNECTION_STRING with the secret as its password
+func (r *secretRotation) connString(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, SecretFetchTimeout)
+	defer cancel()
+
+	secret, err := r.settings.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	if r.settings.target == SecretTargetConnectionString {
+		return secret, nil
+	}
+	return setConnStringPassword(r.driver, os.Getenv("DB_CONNECTION_STRING"), secret)
+}
+
+// Close stops following the rotation of the secret
+func (r *secretRotation) Close() {
+	if r == nil {
+		return
+	}
+	r.stopOnce.Do(func() { close(r.stop) })
+}
+
+// rotateSecret reads the secret every refresh interval until the server is closed
+func (s *DbMCPServer) rotateSecret() {
+	r := s.secrets
+	if r == nil || r.settings.refresh <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(r.settings.refresh)
+	defer ticker.Stop()
+	serving := s
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			serving = s.refreshSecret(serving)
+		}
+	}
+}
+
+// refreshSecret reads the secret again and, when it rotated, serves the default connection
+// with a view on a pool using the new secret, returning the view serving the connection of
+// the environment. The new pool is checked before it replaces the view of the old one, whose
+// pool is closed once its calls return; a connection configured with configure_datasource
+// since is left as it is.
+func (s *DbMCPServer) refreshSecret(serving *DbMCPServer) *DbMCPServer {
+	r := s.secrets
+	connString, err := r.connString(context.Background())
+	if err != nil {
+		log.Printf("Warning: Could not read the secret of DB_SECRET_SOURCE: %v", err)
+		return serving
+	}
+	r.mu.Lock()
+	rotated := connString != r.current
+	r.mu.Unlock()
+	if !rotated {
+		return serving
+	}
+
+	if !s.defaultConn.serves(serving) {
+		r.setCurrent(connString)
+		return serving
+	}
+
+	db, err := openEnvDatabase(r.driver, connString)
+	if err == nil {
+		if err = pingDatabase(context.Background(), db); err != nil {
+			db.Close()
+		}
+	}
+	if err != nil {
+		// The old pool is kept, and the new secret is tried again on the next refresh
+		log.Printf("Warning: Could not reconnect with the rotated secret of DB_SECRET_SOURCE: %v", err)
+		return serving
+	}
+
+	view := s.newView(db, r.driver, "")
+	r.setCurrent(connString)
+	if !s.defaultConn.swap(serving, view) {
+		// configure_datasource replaced the connection while the new pool was opened
+		db.Close()
+		return serving
+	}
+	log.Printf("The secret of DB_SECRET_SOURCE rotated: reconnected to the database")
+	return view
+}
+
+// setCurrent records the connection string of the secret the server last read
+func (r *secretRotation) setCurrent(connString string) {
+	r.mu.Lock()
+	r.current = connString
+	r.mu.Unlock()
+}
+
+// setConnStringPassword sets the password of a connection string in the form of its driver
+func setConnStringPassword(driver, connString, password string) (string, error) {
+	switch DriverType(normalizeDriver(driver)) {
+	case DriverSQLServer:
+		params := parseSQLServerConnString(connString)
+		if params.url != nil && params.get("user id") == "" {
+			return "", ErrSecretPasswordUnsupported
+		}
+		params.set("password", password)
+		return params.String(), nil
+	case DriverMySQL:
+		config, err := mysql.ParseDSN(connString)
+		if err != nil {
+			return "", err
+		}
+		config.Passwd = password
+		return config.FormatDSN(), nil
+	case DriverPostgresSQL:
+		if !isPostgresURL(connString) {
+			return setPostgresParams(connString, [][2]string{{"password", password}})
+		}
+	case DriverOracle:
+		if !strings.Contains(connString, "://") {
+			return "", ErrSecretPasswordUnsupported
+		}
+	default:
+		return "", ErrSecretPasswordUnsupported
+	}
+
+	// PostgreSQL and Oracle URLs take the password in their user information
+	u, err := url.Parse(connString)
+	if err != nil {
+		return "", err
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return "", ErrSecretPasswordUnsupported
+	}
+	u.User = url.UserPassword(u.User.Username(), password)
+	return u.String(), nil
+}
@@ -0,0 +1,66 @@
+package mcp
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestSetConnStringPassword(t *testing.T) {
+	const password = "p@ss w'rd"
+	tests := []struct {
+		name       string
+		driver     DriverType
+		connString string
+		want       string
+		wantErr    error
+	}{
+		{"postgres url", DriverPostgresSQL, "postgres://u:old@h/db", "postgres://u:p%40ss%20w%27rd@h/db", nil},
+		{"postgres settings", DriverPostgresSQL, "host=h user=u", `host=h user=u password='p@ss w\'rd'`, nil},
+		{"postgres url without a user", DriverPostgresSQL, "postgres://h/db", "", ErrSecretPasswordUnsupported},
+		{"sqlserver url", DriverSQLServer, "sqlserver://u@h?database=d", "sqlserver://u:p%40ss%20w%27rd@h?database=d", nil},
+		{"sqlserver settings", DriverSQLServer, "server=h;user id=u", "server=h;user id=u;password=p@ss w'rd", nil},
+		{"mysql", DriverMySQL, "u:old@tcp(h:3306)/db", "u:p@ss w'rd@tcp(h:3306)/db", nil},
+		{"oracle url", DriverOracle, "oracle://u@h:1521/s", "oracle://u:p%40ss%20w%27rd@h:1521/s", nil},
+		{"oracle easy connect", DriverOracle, "h:1521/s", "", ErrSecretPasswordUnsupported},
+		{"sqlite", DriverSQLite, "file.db", "", ErrSecretPasswordUnsupported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := setConnStringPassword(string(tt.driver), tt.connString, password)
+			switch {
+			case tt.wantErr != nil && !errors.Is(err, tt.wantErr):
+				t.Errorf("setConnStringPassword() error = %v, want %v", err, tt.wantErr)
+			case tt.wantErr == nil && err != nil:
+				t.Errorf("setConnStringPassword() error = %v", err)
+			case got != tt.want:
+				t.Errorf("setConnStringPassword() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSignAWSRequest checks the signature against the post-vanilla request of the AWS
+// Signature Version 4 test suite
+func TestSignAWSRequest(t *testing.T) {
+	headers := map[string]string{
+		"host":       "example.amazonaws.com",
+		"x-amz-date": "20150830T123600Z",
+	}
+	got := signAWSRequest(headers, nil, "us-east-1", "service", "AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY")
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b"
+	if got != want {
+		t.Errorf("signAWSRequest() = %q, want %q", got, want)
+	}
+}
+
+// TestAWSSigningKey checks the key derivation against the example of the AWS documentation
+func TestAWSSigningKey(t *testing.T) {
+	key := []byte("AWS4wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY")
+	for _, part := range []string{"20120215", "us-east-1", "iam", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	if got, want := hex.EncodeToString(key), "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d"; got != want {
+		t.Errorf("signing key = %s, want %s", got, want)
+	}
+}
@@ -1,37 +1,106 @@
 package mcp
 
 import (
+	"database/sql"
+	"time"
+
 	"github.com/mark3labs/mcp-go/server"
 )
 
-// NewMcpServer creates a new MCP server instance.
+// NewMcpServer creates a new MCP server instance, with the settings of the environment and
+// of the configuration file of DB_CONFIG_FILE.
 // If DB_CONNECTION_STRING is not set, the server starts without a database connection.
 // Use the configure_datasource tool to connect to a database dynamically.
+// With DB_SECRET_SOURCE, the credentials are read from a secrets backend and followed as
+// they rotate.
 func NewMcpServer() (*DbMCPServer, error) {
-	db, driver, err := newDbConnection()
+	if err := loadConfigFile(); err != nil {
+		return nil, err
+	}
+
+	secrets, err := newSecretRotation()
 	if err != nil {
 		return nil, err
 	}
 
+	db, driver, err := newDbConnection(secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	dbMCPServer := newDbMCPServer(db, driver)
+	dbMCPServer.openNamedConnections()
+
+	dbMCPServer.secrets = secrets
+	go dbMCPServer.rotateSecret()
+
+	// Optional pprof/expvar endpoints
+	dbMCPServer.startDebugServer()
+
+	return dbMCPServer, nil
+}
+
+// newDbMCPServer creates a server with its tools registered around a connection pool,
+// which may be nil until a datasource is configured
+func newDbMCPServer(db *sql.DB, driver string) *DbMCPServer {
 	var queryBuilder *QueryBuilder
 	if driver != "" {
 		queryBuilder = NewQueryBuilder(driver)
 	}
 
 	dbMCPServer := &DbMCPServer{
-		server: server.NewMCPServer(
-			"Database MCP",
-			"1.0.0",
-			server.WithToolCapabilities(true),
-		),
-		db:           db,
-		queryBuilder: queryBuilder,
+		db:               db,
+		queryBuilder:     queryBuilder,
+		watchdog:         newQueryWatchdog(),
+		maxResultBytes:   getEnvMaxResultBytes(),
+		maxResultRows:    getEnvMaxResultRows(),
+		maxQueryTimeout:  getEnvMaxQueryTimeout(),
+		costGuard:        newCostGuard(),
+		largeTables:      newLargeTableGuard(),
+		queryCache:       newQueryCache(),
+		results:          newResultCache(),
+		cursors:          newCursorStore(),
+		accesses:         newAccessLog(),
+		savedQueries:     newSavedQueryStore(),
+		validationPolicy: newValidationPolicy(),
+		objects:          newObjectFilter(),
+		writes:           newWriteMode(),
+		connections:      newNamedConnections(),
+		started:          time.Now(),
+	}
+	// The server serves the default connection until it is replaced
+	dbMCPServer.defaultConn = &defaultConnection{view: &connectionView{server: dbMCPServer}}
+
+	options := []server.ServerOption{
+		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(false, false),
+	}
+	for _, middleware := range dbMCPServer.toolMiddlewares() {
+		options = append(options, server.WithToolHandlerMiddleware(middleware))
 	}
+	dbMCPServer.server = server.NewMCPServer("Database MCP", "1.0.0", options...)
 
-	// Register tools
+	// Register tools and resources
 	dbMCPServer.registerTools()
+	dbMCPServer.registerResources()
 
-	return dbMCPServer, nil
+	return dbMCPServer
+}
+
+// toolMiddlewares returns the middlewares of the tool calls, outermost first, for the MCP
+// server and for the tools added to another one. Hidden objects are dropped before the other
+// middlewares cache, log or render a response. Calls on a named connection are handed to the
+// view serving it, which drops them itself.
+func (s *DbMCPServer) toolMiddlewares() []server.ToolHandlerMiddleware {
+	return []server.ToolHandlerMiddleware{
+		watermarkMiddleware,
+		markdownMiddleware,
+		s.results.middleware,
+		s.accesses.middleware,
+		timeoutMiddleware(s.maxQueryTimeout),
+		s.connectionsMiddleware,
+		s.objectsMiddleware,
+	}
 }
 
 // Start starts the MCP server in stdio mode
@@ -39,15 +108,20 @@ func (s *DbMCPServer) Start() error {
 	return server.ServeStdio(s.server)
 }
 
-// Close closes the database connection if it exists
+// Close stops the query watchdog, secret rotation and debug server, closes the open
+// cursors, the named connections and the database connection if it exists
 func (s *DbMCPServer) Close() error {
-	if s.db != nil {
-		return s.db.Close()
-	}
-	return nil
+	s.watchdog.Close()
+	s.secrets.Close()
+	s.cursors.closeAll()
+	s.connections.closeAll()
+	s.stopDebugServer()
+	return s.defaultConn.close()
 }
 
 // IsConnected returns true if a database connection is established
 func (s *DbMCPServer) IsConnected() bool {
-	return s.db != nil
+	view, release := s.defaultConn.acquire()
+	defer release()
+	return view.db != nil
 }
@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// sessionConnector wraps a driver connector and runs setup statements on every new
+// physical connection, so session settings survive connection pool recycling
+type sessionConnector struct {
+	driver.Connector
+	statements []string
+}
+
+// Connect opens a new connection and applies the session setup statements
+func (c *sessionConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stmt := range c.statements {
+		if err = execOnConn(ctx, conn, stmt); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("%w: %s: %v", ErrSessionSetup, stmt, err)
+		}
+	}
+
+	return conn, nil
+}
+
+// dsnConnector adapts drivers that do not implement driver.DriverContext
+type dsnConnector struct {
+	dsn string
+	drv driver.Driver
+}
+
+// Connect opens a connection using the driver's Open method
+func (c *dsnConnector) Connect(_ context.Context) (driver.Conn, error) {
+	return c.drv.Open(c.dsn)
+}
+
+// Driver returns the underlying driver
+func (c *dsnConnector) Driver() driver.Driver {
+	return c.drv
+}
+
+// newConnector returns a connector for the given driver and connection string
+func newConnector(drv driver.Driver, connString string) (driver.Connector, error) {
+	if driverCtx, ok := drv.(driver.DriverContext); ok {
+		return driverCtx.OpenConnector(connString)
+	}
+	return &dsnConnector{dsn: connString, drv: drv}, nil
+}
+
+// execOnConn executes a statement directly on a driver connection
+func execOnConn(ctx context.Context, conn driver.Conn, stmt string) error {
+	if execer, ok := conn.(driver.ExecerContext); ok {
+		_, err := execer.ExecContext(ctx, stmt, nil)
+		return err
+	}
+
+	prepared, err := conn.Prepare(stmt)
+	if err != nil {
+		return err
+	}
+	defer prepared.Close()
+
+	_, err = prepared.Exec(nil) //nolint:staticcheck // fallback for drivers without ExecerContext
+	return err
+}
+
+// parseSearchPath splits a comma-separated schema list and validates each entry
+func parseSearchPath(value string) ([]string, error) {
+	var searchPath []string
+	for _, part := range strings.Split(value, ",") {
+		name := strings.TrimSpace(part)
+		if name == "" {
+			continue
+		}
+		if !isValidIdentifier(name) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSchemaName, name)
+		}
+		searchPath = append(searchPath, name)
+	}
+	return searchPath, nil
+}
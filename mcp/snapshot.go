@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// getEnvSnapshotDatabases reads the databases execute_query may target from
+// DB_SNAPSHOT_DATABASES. An empty list allows any read-only database.
+func getEnvSnapshotDatabases() []string {
+	var databases []string
+	for _, part := range strings.Split(os.Getenv("DB_SNAPSHOT_DATABASES"), ",") {
+		if name := strings.TrimSpace(part); name != "" {
+			databases = append(databases, name)
+		}
+	}
+	return databases
+}
+
+// snapshotConn returns a dedicated connection switched to a database snapshot, standby or
+// read-only copy, together with the kind of database. The database must be listed in
+// DB_SNAPSHOT_DATABASES when the variable is set, and the catalog must report it as
+// not writable. The connection must be released with discardConn.
+func (s *DbMCPServer) snapshotConn(ctx context.Context, database string) (*sql.Conn, string, error) {
+	if !isValidIdentifier(database) {
+		return nil, "", fmt.Errorf("%w: %s", ErrInvalidDatabaseName, database)
+	}
+
+	if allowed := getEnvSnapshotDatabases(); len(allowed) > 0 && !containsFold(allowed, database) {
+		return nil, "", fmt.Errorf("%w: %s", ErrSnapshotNotAllowed, database)
+	}
+
+	query, args, ok := s.queryBuilder.ReadOnlyDatabaseQuery(database)
+	statements := s.queryBuilder.SearchPathStatements([]string{database})
+	if !ok || len(statements) == 0 {
+		return nil, "", ErrFeatureNotSupported
+	}
+
+	var kind string
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(&kind)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", fmt.Errorf("%w: %s", ErrSnapshotNotReadOnly, database)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %w", ErrCheckingSnapshot, err)
+	}
+
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %w", ErrCheckingSnapshot, err)
+	}
+	for _, stmt := range statements {
+		if _, err = conn.ExecContext(ctx, stmt); err != nil {
+			discardConn(conn)
+			return nil, "", fmt.Errorf("%w: %w", ErrCheckingSnapshot, err)
+		}
+	}
+
+	return conn, kind, nil
+}
+
+// discardConn closes a connection instead of returning it to the pool, so session state
+// set on it (a database switch, SHOWPLAN) never leaks into other queries
+func discardConn(conn *sql.Conn) {
+	_ = conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+	_ = conn.Close()
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,349 @@
+package mcp
+
+import (
+	"strings"
+	"unicode"
+)
+
+// The query validator reads queries as tokens rather than text, so keywords are only matched
+// as whole words outside strings, quoted identifiers and comments. Databases disagree on
+// where a string or comment ends (backslash escapes, nested comments, dollar quotes, MySQL
+// executable comments), so a query is split with the rules of the database it runs on, and
+// with those of every database when that is unknown (ValidateQuery, saved queries). It must
+// pass validation with each: text one reading would run cannot hide in what another reads as
+// a string or comment. [name] is a quoted identifier only in SQL Server and SQLite: in
+// Postgres brackets build and subscript arrays, whose elements are expressions that are
+// checked as the rest of the query. `name` is a quoted identifier with every rule set: where
+// it is not, it is a syntax error.
+
+// sqlTokenKind is the kind of a token of a query
+type sqlTokenKind int
+
+const (
+	sqlWord             sqlTokenKind = iota // keywords and unquoted identifiers
+	sqlQuotedIdentifier                     // "name", [name] (SQL Server, SQLite), `name`
+	sqlString                               // string literals, whatever their quoting
+	sqlNumber                               // numeric literals, including 0x hexadecimal
+	sqlParameter                            // $1 positional parameters
+	sqlPunctuation                          // operators, parentheses, commas and semicolons
+)
+
+// sqlToken is a token of a query, with its text as written and the position of its first
+// character in the query, counted in characters
+type sqlToken struct {
+	kind sqlTokenKind
+	text string
+	pos  int
+}
+
+// upper returns the text of the token in upper case
+func (t sqlToken) upper() string {
+	return strings.ToUpper(t.text)
+}
+
+// is reports whether the token is the word keyword, in any case
+func (t sqlToken) is(keyword string) bool {
+	return t.kind == sqlWord && strings.EqualFold(t.text, keyword)
+}
+
+// sqlLexRules are the lexical rules of a database that decide where strings, quoted
+// identifiers and comments end
+type sqlLexRules struct {
+	nestedComments     bool // /* /* */ */ is one comment (SQL Server, Postgres)
+	backslashEscapes   bool // \' does not end a string (MySQL)
+	doubleQuoteEscapes bool // \" does not end a "..." string either (MySQL without ANSI_QUOTES)
+	hashComments       bool // # starts a line comment (MySQL)
+	dashCommentSpace   bool // -- only starts a comment before whitespace (MySQL)
+	executableComments bool // /*! ... */ is run, not skipped (MySQL)
+	dollarQuotes       bool // $tag$ ... $tag$ strings and E'' escape strings (Postgres)
+	alternativeQuotes  bool // q'[ ... ]' strings (Oracle)
+	bracketIdentifiers bool // [name] is a quoted identifier (SQL Server, SQLite)
+}
+
+// sqlLexRulesByDriver are the lexical rules of each database. MySQL strings lose their
+// backslash escapes with NO_BACKSLASH_ESCAPES, and Postgres strings gain them with
+// standard_conforming_strings off, so both readings are checked. Backslashes never escape in
+// a "name" identifier: MySQL reads "..." as one with ANSI_QUOTES, and as a string otherwise.
+var sqlLexRulesByDriver = map[DriverType][]sqlLexRules{
+	DriverSQLServer: {
+		{nestedComments: true, bracketIdentifiers: true},
+	},
+	DriverPostgresSQL: {
+		{nestedComments: true, dollarQuotes: true},
+		{nestedComments: true, dollarQuotes: true, backslashEscapes: true},
+	},
+	DriverMySQL: {
+		{backslashEscapes: true, doubleQuoteEscapes: true, hashComments: true, dashCommentSpace: true, executableComments: true},
+		{backslashEscapes: true, hashComments: true, dashCommentSpace: true, executableComments: true},
+		{hashComments: true, dashCommentSpace: true, executableComments: true},
+	},
+	DriverOracle: {
+		{alternativeQuotes: true},
+	},
+	DriverSQLite: {
+		{bracketIdentifiers: true},
+	},
+}
+
+// sqlLexRulesFor returns the lexical rules of a driver, or those of every database when the
+// driver is unknown
+func sqlLexRulesFor(driver DriverType) []sqlLexRules {
+	if rules, ok := sqlLexRulesByDriver[driver]; ok {
+		return rules
+	}
+	var rules []sqlLexRules
+	for _, d := range []DriverType{DriverSQLServer, DriverPostgresSQL, DriverMySQL, DriverOracle, DriverSQLite} {
+		rules = append(rules, sqlLexRulesByDriver[d]...)
+	}
+	return rules
+}
+
+// lexSQL splits a query into tokens with the rules of a database, dropping whitespace and
+// comments. A string, identifier or comment left open runs to the end of the query, as the
+// database would reject the query before running it.
+func lexSQL(query string, rules sqlLexRules) []sqlToken {
+	src := []rune(query)
+	n := len(src)
+	var tokens []sqlToken
+	executable := false // inside a MySQL /*! */ comment, whose closing */ is skipped
+
+	at := func(i int, c rune) bool {
+		return i < n && src[i] == c
+	}
+
+	for i := 0; i < n; {
+		c := src[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+
+		// Comments
+		case c == '-' && at(i+1, '-') && (!rules.dashCommentSpace || i+2 >= n || unicode.IsSpace(src[i+2]) || unicode.IsControl(src[i+2])):
+			i = skipLine(src, i)
+		case c == '#' && rules.hashComments:
+			i = skipLine(src, i)
+		case c == '/' && at(i+1, '*') && rules.executableComments && (at(i+2, '!') || (at(i+2, 'M') && at(i+3, '!'))):
+			// The content of MySQL and MariaDB executable comments is part of the query
+			i += 3
+			if src[i-1] == 'M' {
+				i++
+			}
+			for i < n && unicode.IsDigit(src[i]) {
+				i++
+			}
+			executable = true
+		case c == '*' && at(i+1, '/') && executable:
+			i += 2
+			executable = false
+		case c == '/' && at(i+1, '*'):
+			i = skipBlockComment(src, i, rules.nestedComments)
+
+		// Strings and quoted identifiers
+		case c == '\'':
+			end := skipQuoted(src, i, '\'', rules.backslashEscapes)
+			tokens = append(tokens, sqlToken{kind: sqlString, text: string(src[i:end]), pos: i})
+			i = end
+		case c == '"':
+			end := skipQuoted(src, i, '"', rules.doubleQuoteEscapes)
+			tokens = append(tokens, sqlToken{kind: sqlQuotedIdentifier, text: string(src[i:end]), pos: i})
+			i = end
+		case c == '[' && rules.bracketIdentifiers:
+			end := skipQuoted(src, i, ']', false)
+			tokens = append(tokens, sqlToken{kind: sqlQuotedIdentifier, text: string(src[i:end]), pos: i})
+			i = end
+		case c == '`':
+			end := skipQuoted(src, i, '`', false)
+			tokens = append(tokens, sqlToken{kind: sqlQuotedIdentifier, text: string(src[i:end]), pos: i})
+			i = end
+		case c == '$' && rules.dollarQuotes && dollarTag(src, i) != nil:
+			end := skipDollarQuoted(src, i, dollarTag(src, i))
+			tokens = append(tokens, sqlToken{kind: sqlString, text: string(src[i:end]), pos: i})
+			i = end
+		case c == '$' && i+1 < n && unicode.IsDigit(src[i+1]):
+			end := i + 1
+			for end < n && unicode.IsDigit(src[end]) {
+				end++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlParameter, text: string(src[i:end]), pos: i})
+			i = end
+
+		// Numbers: digits with an optional fraction and exponent, or 0x hexadecimal. A letter
+		// after them starts a new word, as SQL Server reads 1DELETE as 1 DELETE.
+		case unicode.IsDigit(c):
+			end := skipNumber(src, i)
+			tokens = append(tokens, sqlToken{kind: sqlNumber, text: string(src[i:end]), pos: i})
+			i = end
+
+		// Words, with the prefixed strings of Postgres (E'') and Oracle (q'[]', nq'[]')
+		case isWordStart(c, rules):
+			end := i + 1
+			for end < n && isWordPart(src[end], rules) {
+				end++
+			}
+			word := strings.ToUpper(string(src[i:end]))
+			switch {
+			case rules.dollarQuotes && word == "E" && at(end, '\''):
+				stop := skipQuoted(src, end, '\'', true)
+				tokens = append(tokens, sqlToken{kind: sqlString, text: string(src[i:stop]), pos: i})
+				i = stop
+			case rules.alternativeQuotes && (word == "Q" || word == "NQ") && at(end, '\'') && end+1 < n:
+				stop := skipAlternativeQuote(src, end)
+				tokens = append(tokens, sqlToken{kind: sqlString, text: string(src[i:stop]), pos: i})
+				i = stop
+			default:
+				tokens = append(tokens, sqlToken{kind: sqlWord, text: string(src[i:end]), pos: i})
+				i = end
+			}
+
+		default:
+			tokens = append(tokens, sqlToken{kind: sqlPunctuation, text: string(c), pos: i})
+			i++
+		}
+	}
+	return tokens
+}
+
+// isWordStart reports whether c starts a keyword or unquoted identifier. SQL Server names
+// variables @name and temporary tables #name.
+func isWordStart(c rune, rules sqlLexRules) bool {
+	return c == '_' || c == '@' || (c == '#' && !rules.hashComments) || unicode.IsLetter(c)
+}
+
+// isWordPart reports whether c continues a keyword or unquoted identifier
+func isWordPart(c rune, rules sqlLexRules) bool {
+	return isWordStart(c, rules) || c == '$' || unicode.IsDigit(c)
+}
+
+// skipLine returns the position after the line comment at i
+func skipLine(src []rune, i int) int {
+	for i < len(src) && src[i] != '\n' {
+		i++
+	}
+	return i
+}
+
+// skipBlockComment returns the position after the block comment at i. Nested comments
+// count their /* */ pairs.
+func skipBlockComment(src []rune, i int, nested bool) int {
+	depth := 0
+	for i < len(src) {
+		switch {
+		case src[i] == '/' && i+1 < len(src) && src[i+1] == '*':
+			if depth == 0 || nested {
+				depth++
+			}
+			i += 2
+		case src[i] == '*' && i+1 < len(src) && src[i+1] == '/':
+			depth--
+			i += 2
+			if depth == 0 {
+				return i
+			}
+		default:
+			i++
+		}
+	}
+	return i
+}
+
+// skipQuoted returns the position after the quoted string or identifier at i, which ends at
+// close unless doubled, or escaped by a backslash with backslashEscapes
+func skipQuoted(src []rune, i int, close rune, backslashEscapes bool) int {
+	for i++; i < len(src); i++ {
+		switch {
+		case backslashEscapes && src[i] == '\\':
+			i++
+		case src[i] == close:
+			if i+1 < len(src) && src[i+1] == close {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(src)
+}
+
+// skipAlternativeQuote returns the position after an Oracle q'[...]' string whose opening
+// quote is at i. Brackets close with their pair, other delimiters with themselves.
+func skipAlternativeQuote(src []rune, i int) int {
+	open := src[i+1]
+	close := open
+	switch open {
+	case '[':
+		close = ']'
+	case '(':
+		close = ')'
+	case '{':
+		close = '}'
+	case '<':
+		close = '>'
+	}
+	for j := i + 2; j+1 < len(src); j++ {
+		if src[j] == close && src[j+1] == '\'' {
+			return j + 2
+		}
+	}
+	return len(src)
+}
+
+// skipNumber returns the position after the number at i
+func skipNumber(src []rune, i int) int {
+	n := len(src)
+	isHex := func(c rune) bool {
+		return unicode.IsDigit(c) || strings.ContainsRune("abcdefABCDEF", c)
+	}
+	if src[i] == '0' && i+2 < n && (src[i+1] == 'x' || src[i+1] == 'X') && isHex(src[i+2]) {
+		i += 2
+		for i < n && isHex(src[i]) {
+			i++
+		}
+		return i
+	}
+	for i < n && unicode.IsDigit(src[i]) {
+		i++
+	}
+	if i+1 < n && src[i] == '.' && unicode.IsDigit(src[i+1]) {
+		i++
+		for i < n && unicode.IsDigit(src[i]) {
+			i++
+		}
+	}
+	if i+1 < n && (src[i] == 'e' || src[i] == 'E') {
+		j := i + 1
+		if src[j] == '+' || src[j] == '-' {
+			j++
+		}
+		if j < n && unicode.IsDigit(src[j]) {
+			for i = j; i < n && unicode.IsDigit(src[i]); i++ {
+			}
+		}
+	}
+	return i
+}
+
+// skipDollarQuoted returns the position after the Postgres string at i, which opens and
+// closes with tag
+func skipDollarQuoted(src []rune, i int, tag []rune) int {
+	for j := i + len(tag); j+len(tag) <= len(src); j++ {
+		if string(src[j:j+len(tag)]) == string(tag) {
+			return j + len(tag)
+		}
+	}
+	return len(src)
+}
+
+// dollarTag returns the $tag$ opening a Postgres dollar-quoted string at i, or ""
+func dollarTag(src []rune, i int) []rune {
+	for j := i + 1; j < len(src); j++ {
+		c := src[j]
+		switch {
+		case c == '$':
+			return src[i : j+1]
+		case c == '_' || unicode.IsLetter(c) || (j > i+1 && unicode.IsDigit(c)):
+		default:
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package mcp
+
+import (
+	"testing"
+)
+
+// lexKinds returns the kind and text of each token of a query
+func lexKinds(query string, rules sqlLexRules) [][2]string {
+	names := map[sqlTokenKind]string{
+		sqlWord:             "word",
+		sqlQuotedIdentifier: "identifier",
+		sqlString:           "string",
+		sqlNumber:           "number",
+		sqlParameter:        "parameter",
+		sqlPunctuation:      "punctuation",
+	}
+	var kinds [][2]string
+	for _, token := range lexSQL(query, rules) {
+		kinds = append(kinds, [2]string{names[token.kind], token.text})
+	}
+	return kinds
+}
+
+func TestLexSQLRulesByDriver(t *testing.T) {
+	tests := []struct {
+		name   string
+		driver DriverType
+		query  string
+		want   [][2]string
+	}{
+		{
+			name:   "sqlserver bracket identifier",
+			driver: DriverSQLServer,
+			query:  "SELECT [drop table] FROM t",
+			want:   [][2]string{{"word", "SELECT"}, {"identifier", "[drop table]"}, {"word", "FROM"}, {"word", "t"}},
+		},
+		{
+			name:   "sqlite bracket identifier",
+			driver: DriverSQLite,
+			query:  "SELECT [a]]b]",
+			want:   [][2]string{{"word", "SELECT"}, {"identifier", "[a]]b]"}},
+		},
+		{
+			name:   "postgres array brackets",
+			driver: DriverPostgresSQL,
+			query:  "SELECT ARRAY[pg_sleep(1)]",
+			want: [][2]string{
+				{"word", "SELECT"}, {"word", "ARRAY"}, {"punctuation", "["}, {"word", "pg_sleep"},
+				{"punctuation", "("}, {"number", "1"}, {"punctuation", ")"}, {"punctuation", "]"},
+			},
+		},
+		{
+			name:   "mysql brackets",
+			driver: DriverMySQL,
+			query:  "SELECT [x]",
+			want:   [][2]string{{"word", "SELECT"}, {"punctuation", "["}, {"word", "x"}, {"punctuation", "]"}},
+		},
+		{
+			name:   "oracle brackets",
+			driver: DriverOracle,
+			query:  "SELECT [x]",
+			want:   [][2]string{{"word", "SELECT"}, {"punctuation", "["}, {"word", "x"}, {"punctuation", "]"}},
+		},
+		{
+			name:   "backquoted identifier",
+			driver: DriverMySQL,
+			query:  "SELECT `a b`",
+			want:   [][2]string{{"word", "SELECT"}, {"identifier", "`a b`"}},
+		},
+		{
+			name:   "postgres dollar quoted string",
+			driver: DriverPostgresSQL,
+			query:  "SELECT $x$ ; DROP $x$, $1",
+			want:   [][2]string{{"word", "SELECT"}, {"string", "$x$ ; DROP $x$"}, {"punctuation", ","}, {"parameter", "$1"}},
+		},
+		{
+			name:   "mysql hash comment",
+			driver: DriverMySQL,
+			query:  "SELECT 1 # DROP\n, 2",
+			want:   [][2]string{{"word", "SELECT"}, {"number", "1"}, {"punctuation", ","}, {"number", "2"}},
+		},
+		{
+			name:   "mysql executable comment",
+			driver: DriverMySQL,
+			query:  "SELECT /*! SLEEP(1) */ 1",
+			want: [][2]string{
+				{"word", "SELECT"}, {"word", "SLEEP"}, {"punctuation", "("}, {"number", "1"}, {"punctuation", ")"}, {"number", "1"},
+			},
+		},
+		{
+			name:   "sqlserver nested comment",
+			driver: DriverSQLServer,
+			query:  "SELECT /* a /* b */ DROP */ 1",
+			want:   [][2]string{{"word", "SELECT"}, {"number", "1"}},
+		},
+		{
+			name:   "oracle alternative quote",
+			driver: DriverOracle,
+			query:  "SELECT q'[it's]' FROM dual",
+			want:   [][2]string{{"word", "SELECT"}, {"string", "q'[it's]'"}, {"word", "FROM"}, {"word", "dual"}},
+		},
+		{
+			name:   "hexadecimal number",
+			driver: DriverSQLServer,
+			query:  "SELECT 0x1F",
+			want:   [][2]string{{"word", "SELECT"}, {"number", "0x1F"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := lexKinds(tt.query, sqlLexRulesFor(tt.driver)[0])
+			if len(got) != len(tt.want) {
+				t.Fatalf("lexSQL(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("lexSQL(%q) = %v, want %v", tt.query, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestLexSQLBackslashEscapes(t *testing.T) {
+	query := `SELECT 'a\' , DROP'`
+	for _, rules := range sqlLexRulesFor(DriverMySQL) {
+		tokens := lexSQL(query, rules)
+		if rules.backslashEscapes && len(tokens) != 2 {
+			t.Errorf("with backslash escapes, lexSQL(%q) = %d tokens, want 2", query, len(tokens))
+		}
+		if !rules.backslashEscapes && len(tokens) != 5 {
+			t.Errorf("without backslash escapes, lexSQL(%q) = %d tokens, want 5", query, len(tokens))
+		}
+	}
+}
+
+func TestSQLLexRulesForUnknownDriver(t *testing.T) {
+	count := 0
+	for _, rules := range sqlLexRulesByDriver {
+		count += len(rules)
+	}
+	if got := len(sqlLexRulesFor("")); got != count {
+		t.Errorf("sqlLexRulesFor(\"\") returned %d rule sets, want %d", got, count)
+	}
+}
+
+// TestLexSQLDoubleQuotes checks that a backslash does not escape the closing quote of a
+// "name" identifier, as it does in a MySQL "..." string
+func TestLexSQLDoubleQuotes(t *testing.T) {
+	query := `SELECT "\" , 'a\' ' ; DELETE FROM t -- ' "`
+	for _, driver := range []DriverType{DriverPostgresSQL, DriverMySQL, ""} {
+		hidden := true
+		for _, rules := range sqlLexRulesFor(driver) {
+			for _, token := range lexSQL(query, rules) {
+				if token.is("DELETE") {
+					hidden = false
+				}
+			}
+		}
+		if hidden {
+			t.Errorf("lexSQL(%q) with the rules of %q hides DELETE in every reading", query, driver)
+		}
+		if err := validateFor(query, driver); err == nil {
+			t.Errorf("Validate(%q) for %q = nil, want it rejected", query, driver)
+		}
+	}
+}
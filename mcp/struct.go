@@ -2,6 +2,7 @@ package mcp
 
 import (
 	"database/sql"
+	"net/http"
 	"regexp"
 	"sync"
 	"time"
@@ -11,9 +12,30 @@ import (
 
 // DbMCPServer is the main struct for the MCP server
 type DbMCPServer struct {
-	server       *server.MCPServer
-	db           *sql.DB
-	queryBuilder *QueryBuilder
+	server           *server.MCPServer
+	db               *sql.DB
+	queryBuilder     *QueryBuilder
+	watchdog         *queryWatchdog
+	maxResultBytes   int64
+	maxResultRows    int
+	maxQueryTimeout  time.Duration
+	costGuard        costGuard
+	largeTables      *largeTableGuard
+	queryCache       *queryCache
+	results          *resultCache
+	cursors          *cursorStore
+	accesses         *accessLog
+	savedQueries     *savedQueryStore
+	validationPolicy *validationPolicy
+	objects          *objectFilter
+	writes           *writeMode
+	connections      *namedConnections
+	connection       string // name of the connection a view of the server serves, "" for the default one
+	defaultConn      *defaultConnection
+	view             bool // serves a connection for the server, which registers the same tools
+	started          time.Time
+	debugServer      *http.Server
+	secrets          *secretRotation
 }
 
 // ConnectionManager handles dynamic database connections
@@ -29,6 +51,9 @@ type ConnectionInfo struct {
 	Driver           string    `json:"driver"`
 	ConnectionString string    `json:"-"` // Hidden from JSON output
 	Name             string    `json:"name"`
+	SearchPath       []string  `json:"search_path,omitempty"`
+	Auth             string    `json:"auth,omitempty"`
+	TLS              bool      `json:"tls,omitempty"`
 	ConnectedAt      time.Time `json:"connected_at"`
 	IsActive         bool      `json:"is_active"`
 }
@@ -39,29 +64,48 @@ var connManager = &ConnectionManager{
 
 // Precompiled regexes for performance
 var (
-	reLineComments             = regexp.MustCompile(`--[^\n]*`)
-	reBlockComments            = regexp.MustCompile(`/\*.*?\*/`)
-	reMultipleSpaces           = regexp.MustCompile(`\s+`)
-	reParensAndCommas          = regexp.MustCompile(`\s*([(),;])\s*`)
-	reSingleQuotes             = regexp.MustCompile(`'[^']*'`)
-	reDoubleQuotes             = regexp.MustCompile(`"[^"]*"`)
-	reSquareBrackets           = regexp.MustCompile(`\[[^\]]*\]`)
-	reSelectInto               = regexp.MustCompile(`SELECT\s+.*\s+INTO\s+`)
-	reHexPattern               = regexp.MustCompile(`0X[0-9A-F]+`)
-	reCharNCharPattern         = regexp.MustCompile(`(CHAR|NCHAR)\s*\(`)
-	reValidIdentifier          = regexp.MustCompile(`^[a-zA-Z0-9_#@$]+$`)
-	reValidIdentifierBracketed = regexp.MustCompile(`^[a-zA-Z0-9_#@$*\- ]+$`) // Allows more chars inside brackets
+	reLineComments    = regexp.MustCompile(`--[^\n]*`)
+	reBlockComments   = regexp.MustCompile(`/\*.*?\*/`)
+	reSingleQuotes    = regexp.MustCompile(`'[^']*'`)
+	reValidIdentifier = regexp.MustCompile(`^[\p{L}\p{M}\p{Nd}_#@$]+( [\p{L}\p{M}\p{Nd}_#@$]+)*$`)
+	rePlainIdentifier = regexp.MustCompile(`^[a-zA-Z0-9_#@$]+$`)
+	reConnectionName  = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	reCatalogViews    = regexp.MustCompile(`(?i)\b(INFORMATION_SCHEMA|sys)\.`)
+	reCheckKeyword    = regexp.MustCompile(`(?i)\bCHECK\s*\(`)
+	reGeneratedAs     = regexp.MustCompile(`(?i)\bAS\s*\(`)
+	reQueryTables     = regexp.MustCompile("(?i)\\b(?:FROM|JOIN)\\s+((?:[\\w$#@]+|\"[^\"]+\"|\\[[^\\]]+\\]|`[^`]+`)(?:\\s*\\.\\s*(?:[\\w$#@]+|\"[^\"]+\"|\\[[^\\]]+\\]|`[^`]+`))*)")
+	reNumberLiterals  = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+	reConstraintName  = regexp.MustCompile("(?i)CONSTRAINT\\s+(\"[^\"]+\"|\\[[^\\]]+\\]|`[^`]+`|\\w+)\\s*$")
+
+	// Driver permission error messages
+	reMSSQLObjectPermission = regexp.MustCompile(`(?i)The (\w[\w ]*?) permission was denied on the (?:column '[^']*' of the )?object '([^']*)', database '([^']*)', schema '([^']*)'`)
+	reMSSQLServerPermission = regexp.MustCompile(`(?i)^(\w[\w ]*?) permission was denied on object '([^']*)'`)
+	reMSSQLDatabaseAccess   = regexp.MustCompile(`(?i)server principal "([^"]*)" is not able to access the database "([^"]*)"`)
+	rePostgresPermission    = regexp.MustCompile(`(?i)permission denied for (materialized view|foreign table|table|relation|view|sequence|schema|function|procedure|database) (\S+)`)
+	reMySQLCommandDenied    = regexp.MustCompile(`(?i)^(\S+) command denied to user ('[^']*'@'[^']*') for (?:column '[^']*' in )?(table|routine) (\S+)`)
+	reMySQLDatabaseDenied   = regexp.MustCompile(`(?i)Access denied for user ('[^']*'@'[^']*') to database '([^']*)'`)
+	reMySQLPrivilegeNeeded  = regexp.MustCompile(`(?i)you need (?:\(at least one of\) )?the (\w[\w ]*?) privilege`)
+
+	// SQL Server estimated plan of the cost guard
+	reShowplanCost = regexp.MustCompile(`StatementSubTreeCost="([^"]+)"`)
+	reShowplanRows = regexp.MustCompile(`StatementEstRows="([^"]+)"`)
 )
 
 // Supported database drivers
 type DriverType string
 
+// ErrorCategory is a driver-independent classification of database errors
+type ErrorCategory string
+
 // QueryBuilder is defined in query_builder.go with dialect support
 
 // SQLValidator structure for SQL analysis
 type SQLValidator struct {
-	query      string
-	normalized string
+	query   string
+	driver  DriverType
+	rules   []sqlLexRules
+	policy  *validationPolicy
+	objects *objectFilter
 }
 
 // SelectQueryParams holds parameters for building a SELECT query
@@ -74,6 +118,32 @@ type SelectQueryParams struct {
 	OrderDirection string
 	Limit          int
 	Offset         int
+	// TieBreakers are appended to ORDER BY so rows with equal OrderBy values keep a stable order
+	TieBreakers []string
+	// AsOf reads the table as it was at this time (time-travel query) when set
+	AsOf *time.Time
+}
+
+// AggregateQueryParams holds parameters for building a GROUP BY query
+type AggregateQueryParams struct {
+	Schema      string
+	Table       string
+	GroupBy     []string
+	Aggregates  []AggregateColumn
+	WhereClause string
+	// OrderBy is a group column or the alias of an aggregate; the groups are sorted by the
+	// group columns after it
+	OrderBy        string
+	OrderDirection string
+	Limit          int
+}
+
+// AggregateColumn is an aggregate function of a column, returned as Alias. Column is empty
+// for COUNT(*).
+type AggregateColumn struct {
+	Function string
+	Column   string
+	Alias    string
 }
 
 // PaginationParams holds pagination parameters
@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// asOfLayouts are the accepted formats of the as_of argument; values without an offset are UTC
+var asOfLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// parseAsOf parses the as_of argument of a point-in-time read, returning nil when it is empty
+func (s *DbMCPServer) parseAsOf(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	if !s.queryBuilder.SupportsTimeTravel() {
+		return nil, ErrTimeTravelNotSupported
+	}
+
+	for _, layout := range asOfLayouts {
+		if asOf, err := time.Parse(layout, value); err == nil {
+			return &asOf, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: '%s'", ErrInvalidAsOf, value)
+}
+
+// fetchTimeTravel returns whether a table keeps the history needed for point-in-time reads
+// and its retention in days (NULL when unlimited). Databases reading any table from undo
+// data report every table as versioned.
+func (s *DbMCPServer) fetchTimeTravel(ctx context.Context, schema, tableName string) (bool, sql.NullInt64, error) {
+	var retentionDays sql.NullInt64
+	query, args, ok := s.queryBuilder.TimeTravelQuery(schema, tableName)
+	if !ok {
+		return true, retentionDays, nil
+	}
+
+	var versioned int
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(&versioned, &retentionDays)
+	if err == sql.ErrNoRows {
+		return false, retentionDays, nil
+	}
+	return versioned == 1, retentionDays, err
+}
+
+// validateAsOf checks that a point in time lies within the history kept for a table
+func validateAsOf(asOf time.Time, versioned bool, retentionDays sql.NullInt64) error {
+	if !versioned {
+		return ErrTableNotVersioned
+	}
+
+	now := time.Now()
+	if asOf.After(now) {
+		return fmt.Errorf("%w: %s", ErrAsOfInFuture, asOf.UTC().Format(time.RFC3339))
+	}
+	if retentionDays.Valid && asOf.Before(now.AddDate(0, 0, -int(retentionDays.Int64))) {
+		return fmt.Errorf("%w (%d days)", ErrAsOfBeyondRetention, retentionDays.Int64)
+	}
+	return nil
+}
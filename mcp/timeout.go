@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"context"
+	"time"
+
+	"github.com/invopop/jsonschema"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Query and metadata tools accept timeout_seconds to replace the timeout of the statements
+// they run, bounded by DB_MAX_QUERY_TIMEOUT. timeoutMiddleware records it in the context of
+// the call and withQueryTimeout applies it instead of the default of the tool.
+
+type callTimeoutKey struct{}
+
+// noTimeoutTools are the tools that run no statement, or bound it on their own
+var noTimeoutTools = map[string]bool{
+	"configure_datasource":   true,
+	"get_current_datasource": true,
+	"test_connection":        true,
+	"disconnect_datasource":  true,
+	"list_database_drivers":  true,
+	"explain_query":          true, // timeout_seconds of analyze, up to MaxAnalyzeTimeout
+	"analyze_query":          true,
+	"list_connections":       true,
+	"fetch_full":             true,
+	"get_runtime_stats":      true,
+	"table_access_report":    true,
+	"list_running_queries":   true,
+	"cancel_query":           true,
+	"get_query_history":      true,
+	"clear_query_cache":      true,
+	"save_query":             true,
+	"list_saved_queries":     true,
+}
+
+// hasTimeoutArgument reports whether a tool accepts timeout_seconds
+func hasTimeoutArgument(name string) bool {
+	return !noTimeoutTools[name]
+}
+
+// getEnvMaxQueryTimeout reads the maximum timeout_seconds of a call from DB_MAX_QUERY_TIMEOUT
+func getEnvMaxQueryTimeout() time.Duration {
+	if d := envDuration("DB_MAX_QUERY_TIMEOUT", DefaultMaxQueryTimeout); d > 0 {
+		return d
+	}
+	return DefaultMaxQueryTimeout
+}
+
+// addTimeoutProperty adds the timeout_seconds argument to the input schema of a tool
+func addTimeoutProperty(schema *jsonschema.Schema) {
+	if schema.Properties == nil {
+		schema.Properties = jsonschema.NewProperties()
+	}
+	schema.Properties.Set("timeout_seconds", &jsonschema.Schema{
+		Type:        "integer",
+		Description: "Seconds the statements of the call may run before they are cancelled, up to DB_MAX_QUERY_TIMEOUT (default: 30 for queries, 10 for metadata lookups, 300 for export_query)",
+	})
+}
+
+// timeoutMiddleware records the timeout_seconds of a call in its context, capped at
+// maxTimeout
+func timeoutMiddleware(maxTimeout time.Duration) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if !hasTimeoutArgument(request.Params.Name) {
+				return next(ctx, request)
+			}
+			args, ok := getArgs(request.Params.Arguments)
+			if !ok || args["timeout_seconds"] == nil {
+				return next(ctx, request)
+			}
+
+			seconds, ok := args["timeout_seconds"].(float64)
+			if !ok || seconds <= 0 || seconds != float64(int64(seconds)) {
+				return toolErrorResult(ErrInvalidTimeout), nil
+			}
+			timeout := maxTimeout
+			if seconds < timeout.Seconds() {
+				timeout = time.Duration(seconds) * time.Second
+			}
+			return next(context.WithValue(ctx, callTimeoutKey{}, timeout), request)
+		}
+	}
+}
+
+// queryTimeout returns the timeout_seconds of the call, or defaultTimeout without it
+func queryTimeout(ctx context.Context, defaultTimeout time.Duration) time.Duration {
+	if timeout, ok := ctx.Value(callTimeoutKey{}).(time.Duration); ok {
+		return timeout
+	}
+	return defaultTimeout
+}
+
+// withQueryTimeout returns a context cancelled after the timeout of the call
+func withQueryTimeout(ctx context.Context, defaultTimeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, queryTimeout(ctx, defaultTimeout))
+}
@@ -0,0 +1,265 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// aggregateSpec is an aggregate function of aggregate_table
+type aggregateSpec struct {
+	Function string `json:"function" jsonschema_description:"Function: count, count_distinct, sum, avg, min, max" jsonschema:"enum=count,enum=count_distinct,enum=sum,enum=avg,enum=min,enum=max"`
+	Column   string `json:"column,omitempty" jsonschema_description:"Column to aggregate (optional for count, which counts rows without it)"`
+	Alias    string `json:"alias,omitempty" jsonschema_description:"Name of the result column (optional, default: function_column, or count)"`
+}
+
+// aggregateTableArgs are the arguments of aggregate_table
+type aggregateTableArgs struct {
+	TableName      string          `json:"table_name" jsonschema_description:"Table name"`
+	Schema         string          `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	GroupBy        []string        `json:"group_by,omitempty" jsonschema_description:"Columns to group by (optional, default: a single row for the whole table)"`
+	Aggregates     []aggregateSpec `json:"aggregates,omitempty" jsonschema_description:"Aggregates to compute (default: count), e.g.: [{\"function\": \"sum\", \"column\": \"amount\"}]"`
+	Filters        []rowFilter     `json:"filters,omitempty" jsonschema_description:"Filters applied before grouping (e.g.: [{\"column\": \"status\", \"operator\": \"eq\", \"value\": \"paid\"}])"`
+	OrderBy        string          `json:"order_by,omitempty" jsonschema_description:"Group column or aggregate alias to sort by (optional, default: the group columns)"`
+	OrderDirection string          `json:"order_direction,omitempty" jsonschema_description:"Sorting direction: ASC or DESC (default: ASC)"`
+	MaxGroups      int             `json:"max_groups,omitempty" jsonschema_description:"Maximum groups to return (default: 100, maximum: 1000)"`
+}
+
+// aggregateFunctions are the functions aggregate_table accepts
+var aggregateFunctions = map[string]bool{
+	"count":          true,
+	"count_distinct": true,
+	"sum":            true,
+	"avg":            true,
+	"min":            true,
+	"max":            true,
+}
+
+func (s *DbMCPServer) toolAggregateTable() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("aggregate_table", "Counts, sums, averages and other aggregates of a table, optionally grouped by columns and filtered, without writing SQL: the server builds the GROUP BY query from validated column names. Use it for group-by answers such as rows per status or totals per month", s.handleAggregateTable)
+}
+
+func (s *DbMCPServer) handleAggregateTable(ctx context.Context, request mcp.CallToolRequest, args aggregateTableArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	tableName := args.TableName
+	if !isValidIdentifier(tableName) {
+		return toolErrorResult(ErrInvalidTableName), nil
+	}
+
+	defaultSchema := getDefaultSchema(s.queryBuilder.GetDriver())
+	schema, err := getValidSchema(args.Schema, defaultSchema)
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	orderDirection := "ASC"
+	if dir := strings.ToUpper(args.OrderDirection); dir == "DESC" || dir == "ASC" {
+		orderDirection = dir
+	}
+
+	maxGroups := args.MaxGroups
+	if maxGroups < 1 {
+		maxGroups = DefaultAggregateGroups
+	}
+	maxGroups = min(maxGroups, MaxAggregateGroups)
+
+	ctx, cancel := withQueryTimeout(ctx, DefaultQueryTimeout)
+	defer cancel()
+
+	if exists, err := s.tableExists(ctx, schema, tableName); err != nil {
+		return s.dbErrorResult(ErrCheckingTable, err), nil
+	} else if !exists {
+		return toolErrorResult(fmt.Errorf("%w: %s.%s", ErrTableNotFound, schema, tableName)), nil
+	}
+
+	tableColumns, err := s.getTableColumns(ctx, schema, tableName)
+	if err != nil {
+		return s.dbErrorResult(ErrRetrievingColumns, err), nil
+	}
+	if len(tableColumns) == 0 {
+		return toolErrorResult(ErrNoColumnsFound), nil
+	}
+
+	// Group columns take the spelling of the table, so they are quoted as declared
+	groupBy := make([]string, 0, len(args.GroupBy))
+	for _, name := range args.GroupBy {
+		column, ok := tableColumn(tableColumns, name)
+		if !ok {
+			return toolErrorResult(fmt.Errorf("%w: %s", ErrColumnNotExists, name)), nil
+		}
+		groupBy = append(groupBy, column)
+	}
+
+	aggregates, err := aggregateColumns(args.Aggregates, tableColumns, groupBy)
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	// The order is a group column or an aggregate alias
+	orderBy := args.OrderBy
+	if orderBy != "" {
+		if _, ok := tableColumn(groupBy, orderBy); !ok && !hasAggregateAlias(aggregates, orderBy) {
+			return toolErrorResult(fmt.Errorf("%w: %s", ErrColumnNotExists, orderBy)), nil
+		}
+	}
+
+	whereClauses, queryParams, err := s.buildWhereClause(args.Filters, tableColumns)
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+	whereClause := ""
+	if len(whereClauses) > 0 {
+		whereClause = "WHERE " + strings.Join(whereClauses, " AND ")
+	}
+
+	// One group more than asked tells whether the groups were truncated
+	query := s.queryBuilder.BuildAggregateQuery(AggregateQueryParams{
+		Schema:         schema,
+		Table:          tableName,
+		GroupBy:        groupBy,
+		Aggregates:     aggregates,
+		WhereClause:    whereClause,
+		OrderBy:        orderBy,
+		OrderDirection: orderDirection,
+		Limit:          maxGroups + 1,
+	})
+	ctx, watch := s.watchdog.Watch(ctx, "aggregate_table", query)
+	defer watch.Done()
+
+	dbRows, err := s.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return s.dbErrorResult(ErrAggregatingTable, watch.Cause(err)), nil
+	}
+	defer dbRows.Close()
+
+	columns := append(append([]string{}, groupBy...), aggregateAliases(aggregates)...)
+	budget := s.newResultBudget()
+	rows := newResultSet(columns)
+	encoder := newResultEncoder(dbRows)
+
+	values := make([]interface{}, len(columns))
+	valuePtrs := make([]interface{}, len(columns))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+
+	truncated := false
+	for dbRows.Next() {
+		if rows.Len() >= maxGroups {
+			truncated = true
+			break
+		}
+		if err = dbRows.Scan(valuePtrs...); err != nil {
+			return toolErrorResult(ErrReadingRow), nil
+		}
+		encoder.encode(values)
+		if !budget.Add(values) {
+			break
+		}
+		rows.AppendRow(values)
+		watch.Row()
+	}
+	if err = watch.Err(); err != nil {
+		return toolErrorResult(err), nil
+	}
+	if err = dbRows.Err(); err != nil {
+		return s.dbErrorResult(ErrAggregatingTable, err), nil
+	}
+
+	aggregateList := make([]map[string]interface{}, len(aggregates))
+	for i, agg := range aggregates {
+		aggregateList[i] = map[string]interface{}{
+			"function": agg.Function,
+			"column":   agg.Column,
+			"alias":    agg.Alias,
+		}
+	}
+
+	response := map[string]interface{}{
+		"rows":       rows,
+		"columns":    columns,
+		"row_count":  rows.Len(),
+		"truncated":  truncated,
+		"group_by":   groupBy,
+		"aggregates": aggregateList,
+		"table": map[string]interface{}{
+			"schema": schema,
+			"name":   tableName,
+		},
+	}
+	budget.Annotate(response)
+
+	return jsonToolResult(response), nil
+}
+
+// aggregateColumns validates the aggregates of aggregate_table and names their result
+// columns, which must not repeat each other or the group columns. No aggregate is a count
+// of the rows.
+func aggregateColumns(specs []aggregateSpec, tableColumns, groupBy []string) ([]AggregateColumn, error) {
+	if len(specs) == 0 {
+		specs = []aggregateSpec{{Function: "count"}}
+	}
+
+	used := append([]string{}, groupBy...)
+	aggregates := make([]AggregateColumn, 0, len(specs))
+	for _, spec := range specs {
+		function := strings.ToLower(spec.Function)
+		if !aggregateFunctions[function] {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidAggregate, spec.Function)
+		}
+
+		column := spec.Column
+		if column == "*" && function == "count" {
+			column = ""
+		}
+		if column == "" && function != "count" {
+			return nil, fmt.Errorf("%w: %s", ErrAggregateColumnRequired, function)
+		}
+		if column != "" {
+			name, ok := tableColumn(tableColumns, column)
+			if !ok {
+				return nil, fmt.Errorf("%w: %s", ErrColumnNotExists, column)
+			}
+			column = name
+		}
+
+		alias := spec.Alias
+		if alias == "" {
+			alias = function
+			if column != "" {
+				alias = function + "_" + column
+			}
+		}
+		if !isValidIdentifier(alias) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidAggregateAlias, alias)
+		}
+		if _, ok := tableColumn(used, alias); ok {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateAggregateAlias, alias)
+		}
+		used = append(used, alias)
+
+		aggregates = append(aggregates, AggregateColumn{Function: function, Column: column, Alias: alias})
+	}
+	return aggregates, nil
+}
+
+// aggregateAliases returns the result column names of the aggregates
+func aggregateAliases(aggregates []AggregateColumn) []string {
+	aliases := make([]string, len(aggregates))
+	for i, agg := range aggregates {
+		aliases[i] = agg.Alias
+	}
+	return aliases
+}
+
+// hasAggregateAlias reports whether an aggregate is returned as name
+func hasAggregateAlias(aggregates []AggregateColumn, name string) bool {
+	_, ok := tableColumn(aggregateAliases(aggregates), name)
+	return ok
+}
@@ -0,0 +1,171 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// analyzeQueryArgs are the arguments of analyze_query
+type analyzeQueryArgs struct {
+	Query  string `json:"query" jsonschema_description:"SQL query to be analyzed; it is never executed"`
+	Driver string `json:"driver,omitempty" jsonschema_description:"Database whose SQL dialect the query is read in: 'sqlserver', 'postgres', 'mysql', 'sqlite', 'oracle' (default: that of the connected database, or the rules of every database when not connected)" jsonschema:"enum=sqlserver,enum=postgres,enum=mysql,enum=sqlite,enum=oracle"`
+}
+
+// QueryRisk is the risk analyze_query assesses for a query
+type QueryRisk string
+
+const (
+	RiskNone   QueryRisk = "none"   // the query breaks no rule
+	RiskLow    QueryRisk = "low"    // the query runs, but may read more than needed
+	RiskMedium QueryRisk = "medium" // the query breaks a limit or an access rule of the server
+	RiskHigh   QueryRisk = "high"   // the query writes, runs commands or hides what it does
+)
+
+// highRiskRules are the validation rules broken by queries that would change the database,
+// run commands, stall it or hide what they do; the other errors are of medium risk
+var highRiskRules = map[string]bool{
+	"writable_cte":         true,
+	"blocked_command":      true,
+	"locking_clause":       true,
+	"transaction_command":  true,
+	"admin_command":        true,
+	"security_command":     true,
+	"dangerous_function":   true,
+	"single_statement":     true,
+	"select_into":          true,
+	"control_character":    true,
+	"hex_encoding":         true,
+	"char_obfuscation":     true,
+	"nested_comment":       true,
+	"invisible_character":  true,
+	"homoglyph":            true,
+	"concatenated_keyword": true,
+	"time_function":        true,
+}
+
+// callKeywords are the words followed by a parenthesis that are not function calls
+var callKeywords = map[string]bool{
+	"AS": true, "IN": true, "EXISTS": true, "VALUES": true, "OVER": true, "ON": true,
+	"USING": true, "FILTER": true, "WITHIN": true, "AND": true, "OR": true, "NOT": true,
+	"ANY": true, "ALL": true, "SOME": true, "FROM": true, "JOIN": true, "WHERE": true,
+	"SELECT": true, "WITH": true, "LATERAL": true, "APPLY": true, "UNION": true,
+	"INTERSECT": true, "EXCEPT": true, "MINUS": true, "BY": true, "HAVING": true,
+	"THEN": true, "ELSE": true, "WHEN": true, "CASE": true, "IS": true, "LIKE": true,
+	"BETWEEN": true, "RETURNING": true, "INTO": true, "TABLE": true, "SET": true,
+}
+
+func (s *DbMCPServer) toolAnalyzeQuery() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("analyze_query", "Analyzes SQL pasted from anywhere without executing it or touching the database, as a risk report for security reviews: the risk (none, low, medium or high), whether execute_query would accept it, every rule it triggers with its line and column, the warnings, the query normalized with its literals replaced by ? and its fingerprint, and the tables and functions it references. Works without a connection", s.handleAnalyzeQuery)
+}
+
+func (s *DbMCPServer) handleAnalyzeQuery(ctx context.Context, request mcp.CallToolRequest, args analyzeQueryArgs) (*mcp.CallToolResult, error) {
+	query := args.Query
+	if query == "" {
+		return toolErrorResult(ErrQueryRequired), nil
+	}
+
+	var driver DriverType
+	switch {
+	case args.Driver != "":
+		if driver = DriverType(normalizeDriver(args.Driver)); driver == "" {
+			return toolErrorResult(fmt.Errorf("%w: '%s'", ErrInvalidDriver, args.Driver)), nil
+		}
+	case s.db != nil && s.queryBuilder != nil:
+		driver = s.queryBuilder.GetDriver()
+	}
+
+	// Every rule the query triggers is reported, as validate_query does, and the risk is
+	// that of the worst of them
+	result := newDriverSQLValidator(query, driver, s.validationPolicy, s.objects).Validate()
+	errs, warnings := result.Errors(), result.Warnings()
+
+	risk := RiskNone
+	rules := []string{}
+	seen := make(map[string]bool)
+	for _, violation := range result.Violations {
+		switch {
+		case violation.Severity == SeverityWarning:
+			if risk == RiskNone {
+				risk = RiskLow
+			}
+		case highRiskRules[violation.Rule]:
+			risk = RiskHigh
+		case risk != RiskHigh:
+			risk = RiskMedium
+		}
+		if !seen[violation.Rule] {
+			seen[violation.Rule] = true
+			rules = append(rules, violation.Rule)
+		}
+	}
+
+	response := map[string]interface{}{
+		"risk":             risk,
+		"allowed":          len(errs) == 0,
+		"rules":            rules,
+		"normalized_query": normalizeQuery(query),
+		"fingerprint":      queryFingerprint(query),
+	}
+	if len(errs) > 0 {
+		response["errors"] = errs
+	}
+	if len(warnings) > 0 {
+		response["warnings"] = warnings
+	}
+	if driver != "" {
+		response["driver"] = string(driver)
+	}
+
+	// Objects are read with the rules of the first database, as the object filter does
+	tokens := lexSQL(query, sqlLexRulesFor(driver)[0])
+	if len(tokens) > 0 && tokens[0].kind == sqlWord {
+		response["statement"] = tokens[0].upper()
+	}
+	tables := []map[string]interface{}{}
+	for _, name := range queryTableNames(tokens) {
+		table := map[string]interface{}{"table": name[1]}
+		if name[0] != "" {
+			table["schema"] = name[0]
+		}
+		tables = append(tables, table)
+	}
+	response["tables"] = tables
+	response["functions"] = queryFunctionNames(tokens)
+
+	return jsonToolResult(response), nil
+}
+
+// queryFunctionNames returns the functions a query calls, once each in the order they are
+// first called, with their schema when it is qualified. Column lists of common table
+// expressions and inserted tables are not calls.
+func queryFunctionNames(tokens []sqlToken) []string {
+	ctes := commonTableExpressions(tokens)
+
+	names := []string{}
+	seen := make(map[string]bool)
+	for i := 0; i+1 < len(tokens); i++ {
+		token := tokens[i]
+		if tokens[i+1].text != "(" || (token.kind != sqlWord && token.kind != sqlQuotedIdentifier) {
+			continue
+		}
+		// The column list of INSERT INTO table (...) follows a table
+		if token.kind == sqlWord && callKeywords[token.upper()] || i > 0 && tokens[i-1].is("INTO") {
+			continue
+		}
+		name := identifierName(token)
+		if i >= 2 && tokens[i-1].text == "." && (tokens[i-2].kind == sqlWord || tokens[i-2].kind == sqlQuotedIdentifier) {
+			name = identifierName(tokens[i-2]) + "." + name
+		} else if ctes[strings.ToUpper(name)] {
+			continue
+		}
+		if !seen[strings.ToUpper(name)] {
+			seen[strings.ToUpper(name)] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
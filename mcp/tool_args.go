@@ -0,0 +1,207 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/invopop/jsonschema"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Tool arguments are declared as structs: the json tag names a property (omitempty marks it
+// optional), jsonschema_description documents it and jsonschema tags add keywords such as
+// enum. The input schema is generated from the struct and calls are decoded into it.
+
+// typedToolHandler handles a tool call whose arguments were decoded into T
+type typedToolHandler[T any] func(ctx context.Context, request mcp.CallToolRequest, args T) (*mcp.CallToolResult, error)
+
+// newTypedTool returns a tool whose input schema is generated from T, and a handler that
+// checks the required arguments and decodes them into T before calling handler
+func newTypedTool[T any](name, description string, handler typedToolHandler[T]) (mcp.Tool, server.ToolHandlerFunc) {
+	schema := inputSchema[T](name)
+	tool := mcp.NewTool(name, mcp.WithDescription(translate(description)), mcp.WithRawInputSchema(schema.raw))
+	tool.InputSchema.Type = ""
+	// NewTool defaults to destructive hints, which would be wrong for the read-only tools
+	tool.Annotations = mcp.ToolAnnotation{}
+	tool.Meta = toolOrderMeta(name)
+
+	return tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args T
+		if err := bindToolArgs(request, schema.required, &args); err != nil {
+			return toolErrorResult(err), nil
+		}
+		return handler(ctx, request, args)
+	}
+}
+
+// toolInputSchema is the generated input schema of a tool
+type toolInputSchema struct {
+	raw      json.RawMessage
+	required []string
+}
+
+// inputSchema generates the input schema of T with the settings of mcp.WithInputSchema,
+// translating the property descriptions. Listing tools also get the format argument, and
+// the tools reading a database the timeout_seconds and connection arguments.
+func inputSchema[T any](name string) toolInputSchema {
+	var zero T
+	reflector := jsonschema.Reflector{
+		DoNotReference:            true,
+		Anonymous:                 true,
+		AllowAdditionalProperties: true,
+	}
+	schema := reflector.Reflect(zero)
+	schema.Version = ""
+	if isListingTool(name) {
+		addFormatProperty(schema)
+	}
+	if hasTimeoutArgument(name) {
+		addTimeoutProperty(schema)
+	}
+	if hasConnectionArgument(name) {
+		addConnectionProperty(schema)
+	}
+	translateSchema(schema)
+
+	raw, _ := json.Marshal(schema)
+	return toolInputSchema{raw: raw, required: schema.Required}
+}
+
+// bindToolArgs decodes the arguments of a call into target, rejecting calls that miss a
+// required argument or pass a value of the wrong type
+func bindToolArgs(request mcp.CallToolRequest, required []string, target interface{}) error {
+	if request.Params.Arguments == nil {
+		request.Params.Arguments = map[string]interface{}{}
+	}
+
+	args, ok := getArgs(request.Params.Arguments)
+	if !ok {
+		return ErrInvalidArguments
+	}
+	for _, name := range required {
+		if value, ok := args[name]; !ok || value == nil || value == "" {
+			return fmt.Errorf("%w: "+translate("%s is required"), ErrInvalidArguments, name)
+		}
+	}
+
+	if err := request.BindArguments(target); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			return fmt.Errorf("%w: "+translate("%s must be of type %s"), ErrInvalidArguments, typeErr.Field, jsonTypeName(typeErr.Type))
+		}
+		return fmt.Errorf("%w: %v", ErrInvalidArguments, err)
+	}
+	splitQualifiedArgs(target)
+	return nil
+}
+
+// splitQualifiedArgs splits a table_name or object_name argument written as schema.name
+// into the schema argument and the name, when the call does not pass the schema
+func splitQualifiedArgs(target interface{}) {
+	v := reflect.ValueOf(target).Elem()
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	var schema reflect.Value
+	var names []reflect.Value
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.Type.Kind() != reflect.String || !v.Field(i).CanSet() {
+			continue
+		}
+		switch tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag {
+		case "schema":
+			schema = v.Field(i)
+		case "table_name", "object_name":
+			names = append(names, v.Field(i))
+		}
+	}
+	if !schema.IsValid() || schema.String() != "" {
+		return
+	}
+	for _, name := range names {
+		if schemaName, objectName, ok := splitQualifiedName(name.String()); ok {
+			schema.SetString(schemaName)
+			name.SetString(objectName)
+			return
+		}
+	}
+}
+
+// jsonTypeName returns the JSON type of a Go type, for error messages
+func jsonTypeName(t reflect.Type) string {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// setEnum restricts a string property, or the items of an array property, to values.
+// Used by JSONSchemaExtend for enums kept in variables.
+func setEnum(schema *jsonschema.Schema, property string, values []string) {
+	prop, ok := schema.Properties.Get(property)
+	if !ok {
+		return
+	}
+	if prop.Items != nil {
+		prop = prop.Items
+	}
+	prop.Enum = make([]interface{}, len(values))
+	for i, value := range values {
+		prop.Enum[i] = value
+	}
+}
+
+// boolArg returns the value of an optional boolean argument
+func boolArg(value *bool, defaultVal bool) bool {
+	if value == nil {
+		return defaultVal
+	}
+	return *value
+}
+
+// intArg returns the value of an optional integer argument whose zero value is meaningful
+func intArg(value *int, defaultVal int) int {
+	if value == nil {
+		return defaultVal
+	}
+	return *value
+}
+
+// pageArgs are the pagination arguments of list tools
+type pageArgs struct {
+	Page     int `json:"page,omitempty" jsonschema_description:"Page number (default: 1)"`
+	PageSize int `json:"page_size,omitempty" jsonschema_description:"Items per page (default: 100, maximum: 500)"`
+}
+
+// pagination returns the validated pagination of a list tool
+func (a pageArgs) pagination() PaginationParams {
+	return newPaginationParams(a.Page, a.PageSize, DefaultPageSize, MaxPageSize)
+}
+
+// crossDatabaseArgs is the database argument of metadata tools that can read another database
+type crossDatabaseArgs struct {
+	Database string `json:"database,omitempty" jsonschema_description:"Database name for cross-database queries (optional, SQL Server and MySQL only)"`
+}
+
+// noArgs are the arguments of tools without parameters
+type noArgs struct{}
@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// trackedTable is a table captured by a change capture mechanism
+type trackedTable struct {
+	Schema    string `json:"schema"`
+	Table     string `json:"table"`
+	Mechanism string `json:"mechanism"`
+	Name      string `json:"name,omitempty"`
+	Details   string `json:"details,omitempty"`
+}
+
+// replicationSlot is a replication slot holding back the changes its consumer has not read
+type replicationSlot struct {
+	Name          string `json:"name"`
+	Plugin        string `json:"plugin,omitempty"`
+	Type          string `json:"type"`
+	Active        bool   `json:"active"`
+	Database      string `json:"database,omitempty"`
+	RetainedBytes *int64 `json:"retained_bytes,omitempty"`
+}
+
+// getChangeTrackingStatusArgs are the arguments of get_change_tracking_status
+type getChangeTrackingStatusArgs struct {
+	Schema    string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	TableName string `json:"table_name,omitempty" jsonschema_description:"Filter by table name (optional)"`
+}
+
+func (s *DbMCPServer) toolGetChangeTrackingStatus() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("get_change_tracking_status", "Reports whether Change Tracking and CDC (SQL Server) or logical replication publications (PostgreSQL) are enabled, with their retention settings, the tables each one captures and the replication slots. Use before building a sync pipeline on the database", s.handleGetChangeTrackingStatus)
+}
+
+func (s *DbMCPServer) handleGetChangeTrackingStatus(ctx context.Context, request mcp.CallToolRequest, args getChangeTrackingStatusArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	schema, err := getValidSchema(args.Schema, "")
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	tableName := args.TableName
+	if tableName != "" && !isValidIdentifier(tableName) {
+		return toolErrorResult(ErrInvalidTableName), nil
+	}
+
+	settingsQuery, restrictedQuery, ok := s.queryBuilder.ChangeTrackingSettingsQuery()
+	if !ok {
+		return toolErrorResult(ErrFeatureNotSupported), nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	settings := map[string]string{}
+	if err := s.fetchChangeTrackingSettings(ctx, settingsQuery, settings); err != nil {
+		return s.dbErrorResult(ErrRetrievingChangeTracking, err), nil
+	}
+	// Settings needing extra privileges are left out when they cannot be read
+	if restrictedQuery != "" {
+		_ = s.fetchChangeTrackingSettings(ctx, restrictedQuery, settings)
+	}
+
+	tables, err := s.fetchTrackedTables(ctx, schema, tableName)
+	if err != nil {
+		return s.dbErrorResult(ErrRetrievingChangeTracking, err), nil
+	}
+
+	response := map[string]interface{}{
+		"settings": settings,
+		"tables":   tables,
+		"count":    len(tables),
+	}
+
+	if slotsQuery := s.queryBuilder.ReplicationSlotsQuery(); slotsQuery != "" {
+		slots, err := s.fetchReplicationSlots(ctx, slotsQuery)
+		if err != nil {
+			return s.dbErrorResult(ErrRetrievingChangeTracking, err), nil
+		}
+		response["replication_slots"] = slots
+	}
+
+	if schema != "" || tableName != "" {
+		response["filter"] = map[string]interface{}{
+			"schema":     schema,
+			"table_name": tableName,
+		}
+	}
+
+	return jsonToolResult(response), nil
+}
+
+// fetchChangeTrackingSettings adds the settings returned by query to settings
+func (s *DbMCPServer) fetchChangeTrackingSettings(ctx context.Context, query string, settings map[string]string) error {
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		var value sql.NullString
+		if err := rows.Scan(&name, &value); err != nil {
+			continue
+		}
+		settings[name] = value.String
+	}
+	return rows.Err()
+}
+
+// fetchTrackedTables returns the tables captured by each change capture mechanism
+func (s *DbMCPServer) fetchTrackedTables(ctx context.Context, schema, tableName string) ([]trackedTable, error) {
+	query, args := s.queryBuilder.ChangeTrackingTablesQuery(schema, tableName)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := []trackedTable{}
+	for rows.Next() {
+		var tableSchema, table, mechanism string
+		var name, details sql.NullString
+		if err := rows.Scan(&tableSchema, &table, &mechanism, &name, &details); err != nil {
+			continue
+		}
+		tables = append(tables, trackedTable{
+			Schema:    tableSchema,
+			Table:     table,
+			Mechanism: mechanism,
+			Name:      name.String,
+			Details:   details.String,
+		})
+	}
+	return tables, rows.Err()
+}
+
+// fetchReplicationSlots returns the replication slots and the bytes of changes each retains
+func (s *DbMCPServer) fetchReplicationSlots(ctx context.Context, query string) ([]replicationSlot, error) {
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	slots := []replicationSlot{}
+	for rows.Next() {
+		var name, slotType string
+		var plugin, database sql.NullString
+		var active int
+		var retained sql.NullInt64
+		if err := rows.Scan(&name, &plugin, &slotType, &active, &database, &retained); err != nil {
+			continue
+		}
+		slot := replicationSlot{
+			Name:     name,
+			Plugin:   plugin.String,
+			Type:     slotType,
+			Active:   active == 1,
+			Database: database.String,
+		}
+		if retained.Valid {
+			slot.RetainedBytes = &retained.Int64
+		}
+		slots = append(slots, slot)
+	}
+	return slots, rows.Err()
+}
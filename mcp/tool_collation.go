@@ -0,0 +1,122 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// columnCollation is a collatable column of a table. Overridden columns declare a collation
+// other than the one they inherit from the table or database.
+type columnCollation struct {
+	Column           string `json:"column"`
+	DataType         string `json:"data_type"`
+	Collation        string `json:"collation,omitempty"`
+	CharacterSet     string `json:"character_set,omitempty"`
+	DefaultCollation string `json:"default_collation,omitempty"`
+	Overridden       bool   `json:"overridden"`
+}
+
+// getCollationInfoArgs are the arguments of get_collation_info
+type getCollationInfoArgs struct {
+	TableName string `json:"table_name,omitempty" jsonschema_description:"Table whose column collations to return (optional)"`
+	Schema    string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+}
+
+func (s *DbMCPServer) toolGetCollationInfo() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("get_collation_info", "Returns the default collation and character set of the database and, for a table, the collation of each text column and whether it overrides the default. Explains case or accent sensitive comparisons and collation conflicts in joins", s.handleGetCollationInfo)
+}
+
+func (s *DbMCPServer) handleGetCollationInfo(ctx context.Context, request mcp.CallToolRequest, args getCollationInfoArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	tableName := args.TableName
+	if tableName != "" && !isValidIdentifier(tableName) {
+		return toolErrorResult(ErrInvalidTableName), nil
+	}
+
+	defaultSchema := getDefaultSchema(s.queryBuilder.GetDriver())
+	schema, err := getValidSchema(args.Schema, defaultSchema)
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	var collation, charset, serverCollation sql.NullString
+	if err := s.db.QueryRowContext(ctx, s.queryBuilder.CollationQuery()).Scan(&collation, &charset, &serverCollation); err != nil {
+		return s.dbErrorResult(ErrRetrievingCollation, err), nil
+	}
+
+	database := map[string]interface{}{
+		"collation":     collation.String,
+		"character_set": charset.String,
+	}
+	if serverCollation.Valid {
+		database["server_collation"] = serverCollation.String
+	}
+	response := map[string]interface{}{
+		"database": database,
+	}
+
+	if tableName == "" {
+		return jsonToolResult(response), nil
+	}
+
+	if exists, err := s.tableExists(ctx, schema, tableName); err != nil {
+		return s.dbErrorResult(ErrCheckingTable, err), nil
+	} else if !exists {
+		return toolErrorResult(fmt.Errorf("%w: %s.%s", ErrTableNotFound, schema, tableName)), nil
+	}
+
+	columns := []columnCollation{}
+	if query, queryArgs, ok := s.queryBuilder.ColumnCollationsQuery(schema, tableName); ok {
+		rows, err := s.db.QueryContext(ctx, query, queryArgs...)
+		if err != nil {
+			return s.dbErrorResult(ErrRetrievingCollation, err), nil
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var column columnCollation
+			var declared, columnCharset, inherited sql.NullString
+			if err = rows.Scan(&column.Column, &column.DataType, &declared, &columnCharset, &inherited); err != nil {
+				continue
+			}
+			column.Collation = declared.String
+			column.CharacterSet = columnCharset.String
+			column.DefaultCollation = inherited.String
+			if column.DefaultCollation == "" {
+				column.DefaultCollation = collation.String
+			}
+			column.Overridden = column.Collation != "" && column.Collation != column.DefaultCollation
+			columns = append(columns, column)
+		}
+		if err = rows.Err(); err != nil {
+			return s.dbErrorResult(ErrRetrievingCollation, err), nil
+		}
+	}
+
+	overridden := 0
+	for _, column := range columns {
+		if column.Overridden {
+			overridden++
+		}
+	}
+
+	response["table"] = map[string]interface{}{
+		"schema":     schema,
+		"name":       tableName,
+		"columns":    columns,
+		"count":      len(columns),
+		"overridden": overridden,
+	}
+
+	return jsonToolResult(response), nil
+}
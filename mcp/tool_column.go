@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// findColumnArgs are the arguments of find_column
+type findColumnArgs struct {
+	ColumnName string `json:"column_name" jsonschema_description:"Column name, matched case-insensitively; use % as wildcard (e.g. %customer%), otherwise the name must match exactly"`
+	Schema     string `json:"schema,omitempty" jsonschema_description:"Schema name (optional, searches all schemas)"`
+	pageArgs
+}
+
+func (s *DbMCPServer) toolFindColumn() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("find_column", "Finds the tables and views having a column with the given name across all schemas, with the column type, nullability and position. Answers questions such as which tables have a customer_id column", s.handleFindColumn)
+}
+
+func (s *DbMCPServer) handleFindColumn(ctx context.Context, request mcp.CallToolRequest, args findColumnArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	columnName := strings.TrimSpace(args.ColumnName)
+	if columnName == "" {
+		return toolErrorResult(ErrColumnNameRequired), nil
+	}
+
+	schema, err := getValidSchema(args.Schema, "")
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	pagination := args.pagination()
+
+	query, queryArgs := s.queryBuilder.FindColumnsQuery(columnName, schema, pagination.PageSize, pagination.Offset)
+
+	ctx, cancel := withQueryTimeout(ctx, DefaultQueryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return s.dbErrorResult(ErrFindingColumns, err), nil
+	}
+	defer rows.Close()
+
+	var columns []map[string]interface{}
+	for rows.Next() {
+		var tableSchema, tableName, tableType, name, isNullable string
+		var dataType sql.NullString
+		var position int
+		if err = rows.Scan(&tableSchema, &tableName, &tableType, &name, &dataType, &isNullable, &position); err != nil {
+			continue
+		}
+		columns = append(columns, map[string]interface{}{
+			"schema":     tableSchema,
+			"table":      tableName,
+			"table_type": tableType,
+			"column":     name,
+			"data_type":  dataType.String,
+			"nullable":   strings.EqualFold(isNullable, "YES"),
+			"position":   position,
+		})
+	}
+
+	response := map[string]interface{}{
+		"columns": columns,
+		"pagination": map[string]interface{}{
+			"page":      pagination.Page,
+			"page_size": pagination.PageSize,
+			"count":     len(columns),
+		},
+		"filter": map[string]interface{}{
+			"column_name": columnName,
+			"schema":      schema,
+		},
+	}
+
+	return jsonToolResult(response), nil
+}
@@ -0,0 +1,119 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// objectComments are the comments of a table or view and of its columns
+type objectComments struct {
+	objectType string
+	comment    string
+	columns    []map[string]interface{}
+}
+
+// getObjectCommentsArgs are the arguments of get_object_comments
+type getObjectCommentsArgs struct {
+	ObjectName string `json:"object_name" jsonschema_description:"Table or view name"`
+	Schema     string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	crossDatabaseArgs
+}
+
+func (s *DbMCPServer) toolGetObjectComments() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("get_object_comments", "Returns the description of a table or view and of its columns, as stored in COMMENT ON (PostgreSQL, Oracle), table and column comments (MySQL) or MS_Description extended properties (SQL Server). Comments often hold the business meaning of tables and columns", s.handleGetObjectComments)
+}
+
+func (s *DbMCPServer) handleGetObjectComments(ctx context.Context, request mcp.CallToolRequest, args getObjectCommentsArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	objectName := args.ObjectName
+	if !isValidIdentifier(objectName) {
+		return toolErrorResult(ErrInvalidObjectName), nil
+	}
+
+	defaultSchema := getDefaultSchema(s.queryBuilder.GetDriver())
+	schema, err := getValidSchema(args.Schema, defaultSchema)
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	database, schema, err := getTargetDatabase(args.Database, s.queryBuilder, schema)
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	comments, err := s.fetchObjectComments(ctx, database, schema, objectName)
+	if err == ErrFeatureNotSupported || err == ErrObjectNotFound {
+		return toolErrorResult(err), nil
+	}
+	if err != nil {
+		return s.dbErrorResult(ErrRetrievingComments, err), nil
+	}
+
+	response := map[string]interface{}{
+		"schema":  schema,
+		"name":    objectName,
+		"type":    comments.objectType,
+		"comment": comments.comment,
+		"columns": comments.columns,
+	}
+	if database != "" {
+		response["database"] = database
+	}
+
+	return jsonToolResult(response), nil
+}
+
+// fetchObjectComments returns the comments of a table or view and of its commented columns.
+// It fails with ErrFeatureNotSupported when the database has no comments and with
+// ErrObjectNotFound when there is no such table or view.
+func (s *DbMCPServer) fetchObjectComments(ctx context.Context, database, schema, objectName string) (*objectComments, error) {
+	query, queryArgs, ok := s.queryBuilder.ObjectCommentQuery(schema, objectName)
+	if !ok {
+		return nil, ErrFeatureNotSupported
+	}
+
+	var objectType string
+	var comment sql.NullString
+	err := s.db.QueryRowContext(ctx, s.queryBuilder.InDatabase(query, database), queryArgs...).Scan(&objectType, &comment)
+	if err == sql.ErrNoRows {
+		return nil, ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	comments := &objectComments{
+		objectType: objectType,
+		comment:    comment.String,
+		columns:    []map[string]interface{}{},
+	}
+
+	query, queryArgs, _ = s.queryBuilder.ColumnCommentsQuery(schema, objectName)
+	rows, err := s.db.QueryContext(ctx, s.queryBuilder.InDatabase(query, database), queryArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var columnName string
+		var columnComment sql.NullString
+		if err := rows.Scan(&columnName, &columnComment); err != nil {
+			continue
+		}
+		comments.columns = append(comments.columns, map[string]interface{}{
+			"column":  columnName,
+			"comment": columnComment.String,
+		})
+	}
+	return comments, rows.Err()
+}
@@ -0,0 +1,156 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// listComputedColumnsArgs are the arguments of list_computed_columns
+type listComputedColumnsArgs struct {
+	TableName string `json:"table_name,omitempty" jsonschema_description:"Filter by table name (optional)"`
+	Schema    string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+}
+
+func (s *DbMCPServer) toolListComputedColumns() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("list_computed_columns", "List computed and generated columns with their expressions and whether the value is persisted (stored) or computed on read. The expression often explains an unexpected value", s.handleListComputedColumns)
+}
+
+func (s *DbMCPServer) handleListComputedColumns(ctx context.Context, request mcp.CallToolRequest, args listComputedColumnsArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	schema, err := getValidSchema(args.Schema, "")
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	tableName := args.TableName
+	if tableName != "" && !isValidIdentifier(tableName) {
+		return toolErrorResult(ErrInvalidTableName), nil
+	}
+
+	query, queryArgs, ok := s.queryBuilder.ListComputedColumnsQuery(schema, tableName)
+	if !ok {
+		return toolErrorResult(ErrFeatureNotSupported), nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return s.dbErrorResult(ErrListingComputedColumns, err), nil
+	}
+	defer rows.Close()
+
+	fromTableSQL := s.queryBuilder.ComputedExpressionIsTableSQL()
+	columns := []map[string]interface{}{}
+	for rows.Next() {
+		var columnSchema, table, column string
+		var dataType, expression sql.NullString
+		var persisted int
+		if err = rows.Scan(&columnSchema, &table, &column, &dataType, &expression, &persisted); err != nil {
+			continue
+		}
+
+		definition := strings.TrimSpace(expression.String)
+		if fromTableSQL {
+			definition = extractGeneratedExpression(expression.String, column)
+		}
+
+		columns = append(columns, map[string]interface{}{
+			"schema":     columnSchema,
+			"table":      table,
+			"column":     column,
+			"data_type":  dataType.String,
+			"expression": definition,
+			"persisted":  persisted == 1,
+		})
+	}
+
+	response := map[string]interface{}{
+		"columns": columns,
+		"count":   len(columns),
+		"filter": map[string]interface{}{
+			"schema": schema,
+			"table":  tableName,
+		},
+	}
+
+	return jsonToolResult(response), nil
+}
+
+// extractGeneratedExpression returns the expression of the AS (...) clause of a column in a
+// CREATE TABLE statement, or empty if the column has none
+func extractGeneratedExpression(createSQL, column string) string {
+	masked := maskQuoted(createSQL)
+
+	// Column definitions are separated by the commas at depth 1 of the column list
+	depth, start := 0, 0
+	for i, c := range masked {
+		switch {
+		case c == '(':
+			depth++
+			if depth == 1 {
+				start = i + 1
+			}
+		case depth == 1 && (c == ',' || c == ')'):
+			if expression, ok := generatedExpression(createSQL[start:i], masked[start:i], column); ok {
+				return expression
+			}
+			if c == ')' {
+				return ""
+			}
+			start = i + 1
+		case c == ')':
+			depth--
+		}
+	}
+	return ""
+}
+
+// generatedExpression returns the expression of a column definition when it defines the column
+func generatedExpression(definition string, masked []byte, column string) (string, bool) {
+	trimmed := strings.TrimLeft(definition, " \t\r\n")
+	offset := len(definition) - len(trimmed)
+	if trimmed == "" {
+		return "", false
+	}
+
+	// The column name is the first token, possibly quoted
+	name := strings.Fields(trimmed)[0]
+	if quote := strings.IndexByte("\"`[", trimmed[0]); quote >= 0 {
+		end := strings.IndexByte(trimmed[1:], "\"`]"[quote])
+		if end < 0 {
+			return "", false
+		}
+		name = trimmed[1 : end+1]
+	}
+	if !strings.EqualFold(name, column) {
+		return "", false
+	}
+
+	loc := reGeneratedAs.FindIndex(masked[offset:])
+	if loc == nil {
+		return "", true
+	}
+	open := offset + loc[1] - 1
+	depth := 0
+	for i := open; i < len(masked); i++ {
+		switch masked[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return strings.TrimSpace(definition[open+1 : i]), true
+			}
+		}
+	}
+	return "", true
+}
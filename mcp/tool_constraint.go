@@ -0,0 +1,350 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// listForeignKeysArgs are the arguments of list_foreign_keys
+type listForeignKeysArgs struct {
+	TableName       string `json:"table_name,omitempty" jsonschema_description:"Only foreign keys defined on this table (optional)"`
+	ReferencedTable string `json:"referenced_table,omitempty" jsonschema_description:"Only foreign keys referencing this table (optional)"`
+	Schema          string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+}
+
+func (s *DbMCPServer) toolListForeignKeys() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("list_foreign_keys", "List foreign key relationships with source and referenced columns and ON DELETE/ON UPDATE rules", s.handleListForeignKeys)
+}
+
+func (s *DbMCPServer) handleListForeignKeys(ctx context.Context, request mcp.CallToolRequest, args listForeignKeysArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	schema, err := getValidSchema(args.Schema, "")
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	tableName := args.TableName
+	if tableName != "" && !isValidIdentifier(tableName) {
+		return toolErrorResult(ErrInvalidTableName), nil
+	}
+
+	referencedTable := args.ReferencedTable
+	if referencedTable != "" && !isValidIdentifier(referencedTable) {
+		return toolErrorResult(ErrInvalidTableName), nil
+	}
+
+	query, queryArgs := s.queryBuilder.ListForeignKeysQuery(schema, tableName, referencedTable)
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return s.dbErrorResult(ErrListingForeignKeys, err), nil
+	}
+	defer rows.Close()
+
+	// Rows come one per column pair, ordered by constraint; group them into constraints
+	var foreignKeys []map[string]interface{}
+	byKey := make(map[string]map[string]interface{})
+	for rows.Next() {
+		var fkSchema, fkTable, constraintName, columnName string
+		var refSchema, refTable string
+		var refColumn, onDelete, onUpdate sql.NullString
+
+		if err = rows.Scan(&fkSchema, &fkTable, &constraintName, &columnName, &refSchema, &refTable, &refColumn, &onDelete, &onUpdate); err != nil {
+			continue
+		}
+
+		key := fkSchema + "." + fkTable + "." + constraintName
+		fk, exists := byKey[key]
+		if !exists {
+			fk = map[string]interface{}{
+				"name":               constraintName,
+				"schema":             fkSchema,
+				"table":              fkTable,
+				"columns":            []string{},
+				"referenced_schema":  refSchema,
+				"referenced_table":   refTable,
+				"referenced_columns": []string{},
+				"on_delete":          onDelete.String,
+				"on_update":          onUpdate.String,
+			}
+			byKey[key] = fk
+			foreignKeys = append(foreignKeys, fk)
+		}
+
+		fk["columns"] = append(fk["columns"].([]string), columnName)
+		fk["referenced_columns"] = append(fk["referenced_columns"].([]string), refColumn.String)
+	}
+
+	response := map[string]interface{}{
+		"foreign_keys": foreignKeys,
+		"count":        len(foreignKeys),
+		"filter": map[string]interface{}{
+			"schema":           schema,
+			"table":            tableName,
+			"referenced_table": referencedTable,
+		},
+	}
+
+	return jsonToolResult(response), nil
+}
+
+// listKeyConstraintsArgs are the arguments of list_key_constraints
+type listKeyConstraintsArgs struct {
+	TableName string `json:"table_name,omitempty" jsonschema_description:"Only key constraints of this table (optional)"`
+	Schema    string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+}
+
+func (s *DbMCPServer) toolListKeyConstraints() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("list_key_constraints", "List primary keys and unique constraints with their columns, to identify what makes a row unique", s.handleListKeyConstraints)
+}
+
+func (s *DbMCPServer) handleListKeyConstraints(ctx context.Context, request mcp.CallToolRequest, args listKeyConstraintsArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	schema, err := getValidSchema(args.Schema, "")
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	tableName := args.TableName
+	if tableName != "" && !isValidIdentifier(tableName) {
+		return toolErrorResult(ErrInvalidTableName), nil
+	}
+
+	query, queryArgs := s.queryBuilder.ListKeyConstraintsQuery(schema, tableName)
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return s.dbErrorResult(ErrListingKeys, err), nil
+	}
+	defer rows.Close()
+
+	// Rows come one per column, ordered by constraint; group them into constraints
+	var constraints []map[string]interface{}
+	byKey := make(map[string]map[string]interface{})
+	for rows.Next() {
+		var keySchema, keyTable, constraintName, constraintType, columnName string
+		var position int
+
+		if err = rows.Scan(&keySchema, &keyTable, &constraintName, &constraintType, &columnName, &position); err != nil {
+			continue
+		}
+
+		key := keySchema + "." + keyTable + "." + constraintName
+		constraint, exists := byKey[key]
+		if !exists {
+			constraint = map[string]interface{}{
+				"name":    constraintName,
+				"schema":  keySchema,
+				"table":   keyTable,
+				"type":    constraintType,
+				"columns": []string{},
+			}
+			byKey[key] = constraint
+			constraints = append(constraints, constraint)
+		}
+
+		constraint["columns"] = append(constraint["columns"].([]string), columnName)
+	}
+
+	response := map[string]interface{}{
+		"key_constraints": constraints,
+		"count":           len(constraints),
+		"filter": map[string]interface{}{
+			"schema": schema,
+			"table":  tableName,
+		},
+	}
+
+	return jsonToolResult(response), nil
+}
+
+// listCheckConstraintsArgs are the arguments of list_check_constraints
+type listCheckConstraintsArgs struct {
+	TableName       string `json:"table_name,omitempty" jsonschema_description:"Only constraints of this table (optional)"`
+	Schema          string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	IncludeDefaults *bool  `json:"include_defaults,omitempty" jsonschema_description:"Include column default expressions (default: true)"`
+}
+
+func (s *DbMCPServer) toolListCheckConstraints() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("list_check_constraints", "List check constraints with their definition expressions and the default value expressions of columns", s.handleListCheckConstraints)
+}
+
+func (s *DbMCPServer) handleListCheckConstraints(ctx context.Context, request mcp.CallToolRequest, args listCheckConstraintsArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	schema, err := getValidSchema(args.Schema, "")
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	tableName := args.TableName
+	if tableName != "" && !isValidIdentifier(tableName) {
+		return toolErrorResult(ErrInvalidTableName), nil
+	}
+
+	includeDefaults := boolArg(args.IncludeDefaults, true)
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	query, queryArgs := s.queryBuilder.ListCheckConstraintsQuery(schema, tableName)
+	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return s.dbErrorResult(ErrListingChecks, err), nil
+	}
+	defer rows.Close()
+
+	fromTableSQL := s.queryBuilder.CheckDefinitionIsTableSQL()
+	checks := []map[string]interface{}{}
+	for rows.Next() {
+		var checkSchema, checkTable string
+		var constraintName, columnName, definition sql.NullString
+
+		if err = rows.Scan(&checkSchema, &checkTable, &constraintName, &columnName, &definition); err != nil {
+			continue
+		}
+
+		if !fromTableSQL {
+			checks = append(checks, map[string]interface{}{
+				"name":       constraintName.String,
+				"schema":     checkSchema,
+				"table":      checkTable,
+				"column":     columnName.String,
+				"definition": definition.String,
+			})
+			continue
+		}
+
+		for _, clause := range extractCheckClauses(definition.String) {
+			checks = append(checks, map[string]interface{}{
+				"name":       clause.name,
+				"schema":     checkSchema,
+				"table":      checkTable,
+				"column":     "",
+				"definition": clause.definition,
+			})
+		}
+	}
+
+	response := map[string]interface{}{
+		"check_constraints": checks,
+		"count":             len(checks),
+		"filter": map[string]interface{}{
+			"schema": schema,
+			"table":  tableName,
+		},
+	}
+
+	if includeDefaults {
+		query, queryArgs = s.queryBuilder.ListDefaultsQuery(schema, tableName)
+		defaultRows, err := s.db.QueryContext(ctx, query, queryArgs...)
+		if err != nil {
+			return s.dbErrorResult(ErrListingDefaults, err), nil
+		}
+		defer defaultRows.Close()
+
+		defaults := []map[string]interface{}{}
+		for defaultRows.Next() {
+			var defaultSchema, defaultTable, columnName string
+			var constraintName, definition sql.NullString
+
+			if err = defaultRows.Scan(&defaultSchema, &defaultTable, &columnName, &constraintName, &definition); err != nil {
+				continue
+			}
+
+			defaults = append(defaults, map[string]interface{}{
+				"schema":     defaultSchema,
+				"table":      defaultTable,
+				"column":     columnName,
+				"name":       constraintName.String,
+				"definition": definition.String,
+			})
+		}
+
+		response["defaults"] = defaults
+	}
+
+	return jsonToolResult(response), nil
+}
+
+// checkClause is a CHECK constraint extracted from a CREATE TABLE statement
+type checkClause struct {
+	name       string
+	definition string
+}
+
+// extractCheckClauses finds the CHECK (...) clauses of a CREATE TABLE statement,
+// ignoring text inside quoted literals and identifiers
+func extractCheckClauses(createSQL string) []checkClause {
+	masked := maskQuoted(createSQL)
+
+	var clauses []checkClause
+	for _, loc := range reCheckKeyword.FindAllIndex(masked, -1) {
+		open := loc[1] - 1
+		depth := 0
+		end := -1
+		for i := open; i < len(masked) && end < 0; i++ {
+			switch masked[i] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+				if depth == 0 {
+					end = i
+				}
+			}
+		}
+		if end < 0 {
+			continue
+		}
+
+		clause := checkClause{definition: createSQL[open : end+1]}
+		if m := reConstraintName.FindStringSubmatch(createSQL[:loc[0]]); m != nil {
+			clause.name = strings.Trim(m[1], "\"`[]")
+		}
+		clauses = append(clauses, clause)
+	}
+
+	return clauses
+}
+
+// maskQuoted returns a copy of a statement with the text inside quoted literals and
+// identifiers replaced by spaces, so keywords and parentheses can be found by offset
+func maskQuoted(statement string) []byte {
+	masked := []byte(statement)
+	var quote byte
+	for i := 0; i < len(masked); i++ {
+		c := masked[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				masked[i] = ' '
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '[':
+			quote = ']'
+		}
+	}
+	return masked
+}
@@ -3,92 +3,66 @@ package mcp
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
-	"fmt"
 	"time"
 
+	"github.com/invopop/jsonschema"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// searchObjectsArgs are the arguments of search_objects
+type searchObjectsArgs struct {
+	SearchTerm   string   `json:"search_term" jsonschema_description:"Text contained in the object name; may use the LIKE wildcards % and _"`
+	SearchInCode bool     `json:"search_in_code,omitempty" jsonschema_description:"If true, also search in the source code of procedures/functions/views (default: false)"`
+	ObjectTypes  []string `json:"object_types,omitempty" jsonschema_description:"Object kinds: 'table', 'view', 'procedure', 'function', 'trigger' (default: all)"`
+}
+
+// JSONSchemaExtend lists the object kinds in the schema
+func (searchObjectsArgs) JSONSchemaExtend(schema *jsonschema.Schema) {
+	setEnum(schema, "object_types", SearchObjectKinds)
+}
+
 func (s *DbMCPServer) toolSearchObjects() (mcp.Tool, server.ToolHandlerFunc) {
-	return mcp.Tool{
-		Name:        "search_objects",
-		Description: "Search for objects (tables, views, procedures, functions) by name or in the source code",
-		InputSchema: mcp.ToolInputSchema{
-			Type: "object",
-			Properties: map[string]interface{}{
-				"search_term": map[string]interface{}{
-					"type":        "string",
-					"description": "Search term",
-				},
-				"search_in_code": map[string]interface{}{
-					"type":        "boolean",
-					"description": "If true, also search in the source code of procedures/functions/views (default: false)",
-				},
-				"object_types": map[string]interface{}{
-					"type":        "array",
-					"description": "Object types: 'table', 'view', 'procedure', 'function' (default: all)",
-					"items": map[string]interface{}{
-						"type": "string",
-					},
-				},
-			},
-			Required: []string{"search_term"},
-		},
-	}, s.handleSearchObjects
+	return newTypedTool("search_objects", "Search tables, views, procedures, functions and triggers across all schemas in one call, by name or in the source code. Returns the schema, kind and database type of each object", s.handleSearchObjects)
 }
 
-func (s *DbMCPServer) handleSearchObjects(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *DbMCPServer) handleSearchObjects(ctx context.Context, request mcp.CallToolRequest, args searchObjectsArgs) (*mcp.CallToolResult, error) {
 	if err := s.requireConnection(); err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
-	}
-
-	args, ok := getArgs(request.Params.Arguments)
-	if !ok {
-		return mcp.NewToolResultError(ErrInvalidArguments.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
-	searchTerm, ok := getStringArg(args, "search_term")
-	if !ok || searchTerm == "" {
-		return mcp.NewToolResultError(ErrSearchTermRequired.Error()), nil
+	searchTerm := args.SearchTerm
+	if searchTerm == "" {
+		return toolErrorResult(ErrSearchTermRequired), nil
 	}
 
-	searchInCode := getBoolArg(args, "search_in_code", false)
+	searchInCode := args.SearchInCode
 
-	var objectTypes []string
-	if objectTypesArg, ok := args["object_types"].([]interface{}); ok && len(objectTypesArg) > 0 {
-		for _, ot := range objectTypesArg {
-			if otStr, ok := ot.(string); ok {
-				objectTypes = append(objectTypes, otStr)
-			}
-		}
-	}
-
-	query, queryArgs := s.queryBuilder.SearchObjectsQuery(searchTerm, searchInCode, objectTypes)
+	query, queryArgs := s.queryBuilder.SearchObjectsQuery(searchTerm, searchInCode, args.ObjectTypes)
 
-	ctx, cancel := context.WithTimeout(ctx, DefaultQueryTimeout)
+	ctx, cancel := withQueryTimeout(ctx, DefaultQueryTimeout)
 	defer cancel()
 
 	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrSearchingObjects, err).Error()), nil
+		return s.dbErrorResult(ErrSearchingObjects, err), nil
 	}
 	defer rows.Close()
 
 	var results []map[string]interface{}
 	for rows.Next() {
-		var schemaName, objectName, objectType string
+		var schemaName, objectName, objectType, kind string
 		var createDate, modifyDate sql.NullTime
 		var hasCode bool
 
-		if err = rows.Scan(&schemaName, &objectName, &objectType, &createDate, &modifyDate, &hasCode); err != nil {
+		if err = rows.Scan(&schemaName, &objectName, &objectType, &createDate, &modifyDate, &hasCode, &kind); err != nil {
 			continue
 		}
 
 		result := map[string]interface{}{
 			"schema":   schemaName,
 			"name":     objectName,
+			"kind":     kind,
 			"type":     objectType,
 			"has_code": hasCode,
 		}
@@ -110,31 +84,19 @@ func (s *DbMCPServer) handleSearchObjects(ctx context.Context, request mcp.CallT
 		},
 	}
 
-	jsonData, err := json.MarshalIndent(response, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
-	}
-
-	return mcp.NewToolResultText(string(jsonData)), nil
+	return jsonToolResult(response), nil
 }
 
 func (s *DbMCPServer) toolGetDatabaseInfo() (mcp.Tool, server.ToolHandlerFunc) {
-	return mcp.Tool{
-		Name:        "get_database_info",
-		Description: "Returns general information about the database",
-		InputSchema: mcp.ToolInputSchema{
-			Type:       "object",
-			Properties: map[string]interface{}{},
-		},
-	}, s.handleGetDatabaseInfo
+	return newTypedTool("get_database_info", "Returns general information about the database", s.handleGetDatabaseInfo)
 }
 
-func (s *DbMCPServer) handleGetDatabaseInfo(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *DbMCPServer) handleGetDatabaseInfo(ctx context.Context, request mcp.CallToolRequest, _ noArgs) (*mcp.CallToolResult, error) {
 	if err := s.requireConnection(); err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, ShortQueryTimeout)
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
 	defer cancel()
 
 	response := map[string]interface{}{
@@ -205,12 +167,200 @@ func (s *DbMCPServer) handleGetDatabaseInfo(ctx context.Context, request mcp.Cal
 		}
 	}
 
-	jsonData, err := json.MarshalIndent(response, "", "  ")
+	return jsonToolResult(response), nil
+}
+
+// schemaOverviewArgs are the arguments of schema_overview
+type schemaOverviewArgs struct {
+	Schema        string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	LargestTables int    `json:"largest_tables,omitempty" jsonschema_description:"Number of largest tables to return (default: 10, max: 50)"`
+}
+
+func (s *DbMCPServer) toolSchemaOverview() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("schema_overview", "Returns a summary per schema: number of tables, views, functions and procedures and total size, with the largest tables of the database. Call it first to get oriented instead of paging through the list tools", s.handleSchemaOverview)
+}
+
+func (s *DbMCPServer) handleSchemaOverview(ctx context.Context, request mcp.CallToolRequest, args schemaOverviewArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	schema, err := getValidSchema(args.Schema, "")
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	largest := args.LargestTables
+	if largest <= 0 {
+		largest = DefaultOverviewLargestTables
+	}
+	if largest > MaxOverviewLargestTables {
+		largest = MaxOverviewLargestTables
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	query, queryArgs := s.queryBuilder.SchemaOverviewQuery(schema)
+	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return s.dbErrorResult(ErrBuildingSchemaOverview, err), nil
+	}
+	defer rows.Close()
+
+	var schemas []map[string]interface{}
+	for rows.Next() {
+		var schemaName string
+		var tables, views, functions, procedures, totalBytes sql.NullInt64
+		if err = rows.Scan(&schemaName, &tables, &views, &functions, &procedures, &totalBytes); err != nil {
+			continue
+		}
+		summary := map[string]interface{}{
+			"schema":      schemaName,
+			"tables":      nullInt64ToInt(tables),
+			"views":       nullInt64ToInt(views),
+			"functions":   nullInt64ToInt(functions),
+			"procedures":  nullInt64ToInt(procedures),
+			"total_bytes": nil,
+		}
+		if totalBytes.Valid {
+			summary["total_bytes"] = totalBytes.Int64
+		}
+		schemas = append(schemas, summary)
+	}
+	if err = rows.Err(); err != nil {
+		return s.dbErrorResult(ErrBuildingSchemaOverview, err), nil
+	}
+
+	response := map[string]interface{}{
+		"schemas": schemas,
+		"count":   len(schemas),
+	}
+	if schema != "" {
+		response["schema"] = schema
+	}
+
+	// Table sizes come from catalog statistics, which the user may not be allowed to read
+	if statsQuery, statsArgs, ok := s.queryBuilder.LargestTablesQuery(schema, largest); ok {
+		if tables, err := s.fetchTableStats(ctx, statsQuery, statsArgs); err == nil {
+			response["largest_tables"] = tables
+			response["approximate"] = true
+		}
+	}
+
+	return jsonToolResult(response), nil
+}
+
+func (s *DbMCPServer) toolListDatabases() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("list_databases", "List the databases visible to the current connection with state, size and collation", s.handleListDatabases)
+}
+
+func (s *DbMCPServer) handleListDatabases(ctx context.Context, request mcp.CallToolRequest, _ noArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	query, supported := s.queryBuilder.ListDatabasesQuery()
+	if !supported {
+		return toolErrorResult(ErrFeatureNotSupported), nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return s.dbErrorResult(ErrListingDatabases, err), nil
+	}
+	defer rows.Close()
+
+	var databases []map[string]interface{}
+	for rows.Next() {
+		var name string
+		var state, collation sql.NullString
+		var sizeMB sql.NullFloat64
+
+		if err = rows.Scan(&name, &state, &sizeMB, &collation); err != nil {
+			continue
+		}
+
+		database := map[string]interface{}{
+			"name": name,
+		}
+		if state.Valid {
+			database["state"] = state.String
+		}
+		if sizeMB.Valid {
+			database["size_mb"] = sizeMB.Float64
+		}
+		if collation.Valid {
+			database["collation"] = collation.String
+		}
+		databases = append(databases, database)
+	}
+
+	response := map[string]interface{}{
+		"databases":              databases,
+		"count":                  len(databases),
+		"cross_database_queries": s.queryBuilder.SupportsCrossDatabase(),
+	}
+
+	return jsonToolResult(response), nil
+}
+
+func (s *DbMCPServer) toolListExtensions() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("list_extensions", "List the installed extensions (postgis, pg_trgm, etc.) with their versions and available updates (PostgreSQL only)", s.handleListExtensions)
+}
+
+func (s *DbMCPServer) handleListExtensions(ctx context.Context, request mcp.CallToolRequest, _ noArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	query, supported := s.queryBuilder.ListExtensionsQuery()
+	if !supported {
+		return toolErrorResult(ErrFeatureNotSupported), nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query)
 	if err != nil {
-		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
+		return s.dbErrorResult(ErrListingExtensions, err), nil
+	}
+	defer rows.Close()
+
+	var extensions []map[string]interface{}
+	for rows.Next() {
+		var name, version, schema string
+		var defaultVersion, description sql.NullString
+
+		if err = rows.Scan(&name, &version, &schema, &defaultVersion, &description); err != nil {
+			continue
+		}
+
+		extension := map[string]interface{}{
+			"name":             name,
+			"version":          version,
+			"schema":           schema,
+			"update_available": defaultVersion.Valid && defaultVersion.String != version,
+		}
+		if defaultVersion.Valid {
+			extension["default_version"] = defaultVersion.String
+		}
+		if description.Valid {
+			extension["description"] = description.String
+		}
+		extensions = append(extensions, extension)
+	}
+
+	response := map[string]interface{}{
+		"extensions": extensions,
+		"count":      len(extensions),
 	}
 
-	return mcp.NewToolResultText(string(jsonData)), nil
+	return jsonToolResult(response), nil
 }
 
 func nullInt64ToInt(n sql.NullInt64) int {
@@ -0,0 +1,354 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ddlIndentation indents the column and constraint lines of a CREATE TABLE statement
+const ddlIndentation = "    "
+
+// ddlConstraint is a primary key, unique or foreign key constraint with its columns in key order
+type ddlConstraint struct {
+	name    string
+	kind    string
+	columns []string
+
+	// Foreign keys only
+	refTable   string
+	refColumns []string
+	onDelete   string
+	onUpdate   string
+}
+
+// ddlIndex is an index that does not back a key constraint
+type ddlIndex struct {
+	name    string
+	unique  bool
+	kind    string
+	method  string
+	columns []string
+	filter  string
+}
+
+// getTableDDLArgs are the arguments of get_table_ddl
+type getTableDDLArgs struct {
+	TableName string `json:"table_name" jsonschema_description:"Table name"`
+	Schema    string `json:"schema,omitempty" jsonschema_description:"Schema name (optional, uses the default schema)"`
+}
+
+func (s *DbMCPServer) toolGetTableDDL() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("get_table_ddl", "Returns the CREATE TABLE statement of a table, rebuilt from the catalog with its columns, defaults, primary key, unique, foreign key and check constraints, followed by the CREATE INDEX statements of its other indexes", s.handleGetTableDDL)
+}
+
+func (s *DbMCPServer) handleGetTableDDL(ctx context.Context, request mcp.CallToolRequest, args getTableDDLArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	tableName := args.TableName
+	if !isValidIdentifier(tableName) {
+		return toolErrorResult(ErrInvalidTableName), nil
+	}
+
+	defaultSchema := getDefaultSchema(s.queryBuilder.GetDriver())
+	schema, err := getValidSchema(args.Schema, defaultSchema)
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, DefaultQueryTimeout)
+	defer cancel()
+
+	var statements []string
+	if query, queryArgs, ok := s.queryBuilder.TableDDLScriptQuery(tableName); ok {
+		statements, err = s.fetchStoredDDL(ctx, query, queryArgs)
+	} else {
+		statements, err = s.buildTableDDL(ctx, schema, tableName)
+	}
+	if err != nil {
+		return s.dbErrorResult(ErrRetrievingTableDDL, err), nil
+	}
+
+	if len(statements) == 0 {
+		return toolErrorResult(fmt.Errorf("%w: %s.%s", ErrTableNotFound, schema, tableName)), nil
+	}
+
+	response := map[string]interface{}{
+		"schema": schema,
+		"table":  tableName,
+		"ddl":    strings.Join(statements, "\n\n"),
+	}
+
+	return jsonToolResult(response), nil
+}
+
+// fetchStoredDDL returns the CREATE statements the database kept for a table and its indexes
+func (s *DbMCPServer) fetchStoredDDL(ctx context.Context, query string, args []interface{}) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var statements []string
+	for rows.Next() {
+		var statement string
+		if err := rows.Scan(&statement); err != nil {
+			continue
+		}
+		statements = append(statements, strings.TrimRight(statement, "; \t\r\n")+";")
+	}
+	return statements, rows.Err()
+}
+
+// buildTableDDL rebuilds the CREATE TABLE and CREATE INDEX statements of a table from
+// catalog metadata. It returns no statements when the table has no columns.
+func (s *DbMCPServer) buildTableDDL(ctx context.Context, schema, tableName string) ([]string, error) {
+	columns, err := s.fetchDDLColumns(ctx, schema, tableName)
+	if err != nil || len(columns) == 0 {
+		return nil, err
+	}
+
+	keys, err := s.fetchDDLKeyConstraints(ctx, schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	foreignKeys, err := s.fetchDDLForeignKeys(ctx, schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	checks, err := s.fetchDDLChecks(ctx, schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes, err := s.fetchDDLIndexes(ctx, schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	qualified := s.queryBuilder.QualifyTable(schema, tableName)
+
+	lines := columns
+	for _, key := range append(keys, foreignKeys...) {
+		lines = append(lines, s.formatDDLConstraint(key))
+	}
+	lines = append(lines, checks...)
+
+	statements := []string{fmt.Sprintf("CREATE TABLE %s (\n%s%s\n);", qualified, ddlIndentation, strings.Join(lines, ",\n"+ddlIndentation))}
+	for _, index := range indexes {
+		statements = append(statements, s.formatDDLIndex(index, qualified))
+	}
+	return statements, nil
+}
+
+// fetchDDLColumns returns the column definitions of a table
+func (s *DbMCPServer) fetchDDLColumns(ctx context.Context, schema, tableName string) ([]string, error) {
+	query, args := s.queryBuilder.TableDDLColumnsQuery(schema, tableName)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var name, columnType, isNullable string
+		var defaultValue, extra sql.NullString
+		if err := rows.Scan(&name, &columnType, &isNullable, &defaultValue, &extra); err != nil {
+			continue
+		}
+
+		// Computed columns have no declared type and take their nullability from the expression
+		definition := s.queryBuilder.QuoteIdentifier(name)
+		if columnType != "" {
+			definition += " " + columnType
+		}
+		if extra.String != "" {
+			definition += " " + extra.String
+		}
+		if defaultValue.String != "" {
+			definition += " DEFAULT " + strings.TrimSpace(defaultValue.String)
+		}
+		if columnType != "" && strings.EqualFold(isNullable, "NO") {
+			definition += " NOT NULL"
+		}
+		columns = append(columns, definition)
+	}
+	return columns, rows.Err()
+}
+
+// fetchDDLKeyConstraints returns the primary key and unique constraints of a table
+func (s *DbMCPServer) fetchDDLKeyConstraints(ctx context.Context, schema, tableName string) ([]*ddlConstraint, error) {
+	query, args := s.queryBuilder.ListKeyConstraintsQuery(schema, tableName)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Rows come one per column, ordered by constraint
+	var keys []*ddlConstraint
+	for rows.Next() {
+		var keySchema, keyTable, name, kind, column string
+		var position int
+		if err := rows.Scan(&keySchema, &keyTable, &name, &kind, &column, &position); err != nil {
+			continue
+		}
+
+		if n := len(keys); n == 0 || keys[n-1].name != name {
+			keys = append(keys, &ddlConstraint{name: name, kind: kind})
+		}
+		key := keys[len(keys)-1]
+		key.columns = append(key.columns, s.queryBuilder.QuoteIdentifier(column))
+	}
+	return keys, rows.Err()
+}
+
+// fetchDDLForeignKeys returns the foreign keys defined on a table
+func (s *DbMCPServer) fetchDDLForeignKeys(ctx context.Context, schema, tableName string) ([]*ddlConstraint, error) {
+	query, args := s.queryBuilder.ListForeignKeysQuery(schema, tableName, "")
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Rows come one per column pair, ordered by constraint
+	var foreignKeys []*ddlConstraint
+	for rows.Next() {
+		var fkSchema, fkTable, name, column, refSchema, refTable string
+		var refColumn, onDelete, onUpdate sql.NullString
+		if err := rows.Scan(&fkSchema, &fkTable, &name, &column, &refSchema, &refTable, &refColumn, &onDelete, &onUpdate); err != nil {
+			continue
+		}
+
+		if n := len(foreignKeys); n == 0 || foreignKeys[n-1].name != name {
+			foreignKeys = append(foreignKeys, &ddlConstraint{
+				name:     name,
+				kind:     "FOREIGN KEY",
+				refTable: s.queryBuilder.QualifyTable(refSchema, refTable),
+				onDelete: onDelete.String,
+				onUpdate: onUpdate.String,
+			})
+		}
+		fk := foreignKeys[len(foreignKeys)-1]
+		fk.columns = append(fk.columns, s.queryBuilder.QuoteIdentifier(column))
+		fk.refColumns = append(fk.refColumns, s.queryBuilder.QuoteIdentifier(refColumn.String))
+	}
+	return foreignKeys, rows.Err()
+}
+
+// fetchDDLChecks returns the check constraint clauses of a table
+func (s *DbMCPServer) fetchDDLChecks(ctx context.Context, schema, tableName string) ([]string, error) {
+	query, args := s.queryBuilder.ListCheckConstraintsQuery(schema, tableName)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var checks []string
+	for rows.Next() {
+		var checkSchema, checkTable string
+		var name, column, definition sql.NullString
+		if err := rows.Scan(&checkSchema, &checkTable, &name, &column, &definition); err != nil {
+			continue
+		}
+
+		// PostgreSQL definitions already start with CHECK
+		clause := strings.TrimSpace(definition.String)
+		if !strings.HasPrefix(strings.ToUpper(clause), "CHECK") {
+			clause = "CHECK (" + clause + ")"
+		}
+		if name.String != "" {
+			clause = "CONSTRAINT " + s.queryBuilder.QuoteIdentifier(name.String) + " " + clause
+		}
+		checks = append(checks, clause)
+	}
+	return checks, rows.Err()
+}
+
+// fetchDDLIndexes returns the indexes of a table that do not back a key constraint
+func (s *DbMCPServer) fetchDDLIndexes(ctx context.Context, schema, tableName string) ([]*ddlIndex, error) {
+	query, args := s.queryBuilder.TableDDLIndexesQuery(schema, tableName)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Rows come one per key column, ordered by index
+	var indexes []*ddlIndex
+	for rows.Next() {
+		var name, column string
+		var unique, descending int
+		var kind, method, filter sql.NullString
+		if err := rows.Scan(&name, &unique, &kind, &method, &column, &descending, &filter); err != nil {
+			continue
+		}
+
+		if n := len(indexes); n == 0 || indexes[n-1].name != name {
+			indexes = append(indexes, &ddlIndex{
+				name:   name,
+				unique: unique == 1,
+				kind:   kind.String,
+				method: method.String,
+				filter: filter.String,
+			})
+		}
+		if descending == 1 {
+			column += " DESC"
+		}
+		index := indexes[len(indexes)-1]
+		index.columns = append(index.columns, column)
+	}
+	return indexes, rows.Err()
+}
+
+// formatDDLConstraint returns the table constraint clause of a key or foreign key
+func (s *DbMCPServer) formatDDLConstraint(c *ddlConstraint) string {
+	clause := fmt.Sprintf("CONSTRAINT %s %s (%s)", s.queryBuilder.QuoteIdentifier(c.name), c.kind, strings.Join(c.columns, ", "))
+	if c.kind != "FOREIGN KEY" {
+		return clause
+	}
+
+	clause += fmt.Sprintf(" REFERENCES %s (%s)", c.refTable, strings.Join(c.refColumns, ", "))
+	if c.onDelete != "" && c.onDelete != "NO ACTION" {
+		clause += " ON DELETE " + c.onDelete
+	}
+	if c.onUpdate != "" && c.onUpdate != "NO ACTION" {
+		clause += " ON UPDATE " + c.onUpdate
+	}
+	return clause
+}
+
+// formatDDLIndex returns the CREATE INDEX statement of an index
+func (s *DbMCPServer) formatDDLIndex(index *ddlIndex, qualifiedTable string) string {
+	var b strings.Builder
+	b.WriteString("CREATE ")
+	if index.unique {
+		b.WriteString("UNIQUE ")
+	}
+	if index.kind != "" {
+		b.WriteString(index.kind + " ")
+	}
+	fmt.Fprintf(&b, "INDEX %s ON %s", s.queryBuilder.QuoteIdentifier(index.name), qualifiedTable)
+	if index.method != "" {
+		b.WriteString(" USING " + index.method)
+	}
+	fmt.Fprintf(&b, " (%s)", strings.Join(index.columns, ", "))
+	if index.filter != "" {
+		b.WriteString(" WHERE " + index.filter)
+	}
+	b.WriteString(";")
+	return b.String()
+}
@@ -0,0 +1,176 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/invopop/jsonschema"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// definitionMatch is an object whose source contains the search term
+type definitionMatch struct {
+	Schema     string           `json:"schema,omitempty"`
+	Name       string           `json:"name"`
+	Kind       string           `json:"kind"`
+	MatchCount int              `json:"match_count"`
+	Hunks      []definitionHunk `json:"hunks"`
+	Truncated  bool             `json:"truncated,omitempty"`
+
+	definition strings.Builder
+}
+
+// definitionHunk is a block of consecutive source lines around one or more matches
+type definitionHunk struct {
+	StartLine  int      `json:"start_line"`
+	EndLine    int      `json:"end_line"`
+	MatchLines []int    `json:"match_lines"`
+	Lines      []string `json:"lines"`
+}
+
+// searchDefinitionsArgs are the arguments of search_definitions
+type searchDefinitionsArgs struct {
+	SearchTerm   string   `json:"search_term" jsonschema_description:"Text to find in the definitions (case-insensitive, no wildcards)"`
+	Schema       string   `json:"schema,omitempty" jsonschema_description:"Only search objects in this schema (optional, default: all schemas)"`
+	ObjectTypes  []string `json:"object_types,omitempty" jsonschema_description:"Object kinds: 'view', 'procedure', 'function', 'trigger', 'package' (Oracle) (default: all)"`
+	ContextLines *int     `json:"context_lines,omitempty" jsonschema_description:"Lines of context before and after each match (default: 2, maximum: 10)"`
+}
+
+// JSONSchemaExtend lists the object kinds in the schema
+func (searchDefinitionsArgs) JSONSchemaExtend(schema *jsonschema.Schema) {
+	setEnum(schema, "object_types", DefinitionKinds)
+}
+
+func (s *DbMCPServer) toolSearchDefinitions() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("search_definitions", "Search the source code of views, procedures, functions and triggers for a text (e.g. a table or column name) and return the matching objects with the matching lines and surrounding context. Use for impact analysis before changing a table or column", s.handleSearchDefinitions)
+}
+
+func (s *DbMCPServer) handleSearchDefinitions(ctx context.Context, request mcp.CallToolRequest, args searchDefinitionsArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	searchTerm := args.SearchTerm
+	if strings.TrimSpace(searchTerm) == "" {
+		return toolErrorResult(ErrSearchTermRequired), nil
+	}
+
+	schema, err := getValidSchema(args.Schema, "")
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	contextLines := intArg(args.ContextLines, DefaultDefinitionContextLines)
+	if contextLines < 0 {
+		contextLines = 0
+	}
+	if contextLines > MaxDefinitionContextLines {
+		contextLines = MaxDefinitionContextLines
+	}
+
+	query, queryArgs := s.queryBuilder.SearchDefinitionsQuery(searchTerm, schema, args.ObjectTypes)
+
+	ctx, cancel := withQueryTimeout(ctx, DefaultQueryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return s.dbErrorResult(ErrSearchingDefinitions, err), nil
+	}
+	defer rows.Close()
+
+	// Sources stored line by line arrive as consecutive rows of the same object
+	var results []*definitionMatch
+	truncated := false
+	for rows.Next() {
+		var schemaName, objectName, kind, definition string
+		var line int
+		if err = rows.Scan(&schemaName, &objectName, &kind, &line, &definition); err != nil {
+			continue
+		}
+
+		if n := len(results); n > 0 && line > 1 {
+			last := results[n-1]
+			if last.Schema == schemaName && last.Name == objectName && last.Kind == kind {
+				last.definition.WriteString(definition)
+				continue
+			}
+		}
+
+		if len(results) >= MaxDefinitionObjects {
+			truncated = true
+			break
+		}
+		result := &definitionMatch{Schema: schemaName, Name: objectName, Kind: kind}
+		result.definition.WriteString(definition)
+		results = append(results, result)
+	}
+	if err = rows.Err(); err != nil {
+		return s.dbErrorResult(ErrSearchingDefinitions, err), nil
+	}
+
+	for _, result := range results {
+		result.findHunks(searchTerm, contextLines)
+	}
+
+	response := map[string]interface{}{
+		"results": results,
+		"search": map[string]interface{}{
+			"term":          searchTerm,
+			"context_lines": contextLines,
+			"count":         len(results),
+			"truncated":     truncated,
+		},
+	}
+
+	return jsonToolResult(response), nil
+}
+
+// findHunks locates the lines containing term and groups them with their context into
+// hunks, merging matches whose context overlaps
+func (m *definitionMatch) findHunks(term string, contextLines int) {
+	source := strings.ReplaceAll(m.definition.String(), "\r\n", "\n")
+	lines := strings.Split(strings.TrimRight(source, "\n"), "\n")
+	term = strings.ToLower(term)
+
+	m.Hunks = []definitionHunk{}
+	for i, line := range lines {
+		if !strings.Contains(strings.ToLower(line), term) {
+			continue
+		}
+
+		m.MatchCount++
+		if m.MatchCount > MaxDefinitionMatchesPerObject {
+			m.Truncated = true
+			continue
+		}
+
+		start, end := max(i-contextLines, 0), min(i+contextLines, len(lines)-1)
+		if n := len(m.Hunks); n > 0 && start <= m.Hunks[n-1].EndLine {
+			hunk := &m.Hunks[n-1]
+			hunk.MatchLines = append(hunk.MatchLines, i+1)
+			for j := hunk.EndLine; j <= end; j++ {
+				hunk.Lines = append(hunk.Lines, formatDefinitionLine(j+1, lines[j]))
+			}
+			hunk.EndLine = max(hunk.EndLine, end+1)
+			continue
+		}
+
+		hunk := definitionHunk{StartLine: start + 1, EndLine: end + 1, MatchLines: []int{i + 1}}
+		for j := start; j <= end; j++ {
+			hunk.Lines = append(hunk.Lines, formatDefinitionLine(j+1, lines[j]))
+		}
+		m.Hunks = append(m.Hunks, hunk)
+	}
+}
+
+// formatDefinitionLine prefixes a source line with its number, shortening very long lines
+func formatDefinitionLine(number int, text string) string {
+	text = strings.TrimRight(text, " \t\r")
+	if runes := []rune(text); len(runes) > MaxDefinitionLineLength {
+		text = string(runes[:MaxDefinitionLineLength]) + "..."
+	}
+	return fmt.Sprintf("%d: %s", number, text)
+}
@@ -0,0 +1,197 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// dependencyNode is an object in the dependency graph
+type dependencyNode struct {
+	ID     string `json:"id"`
+	Schema string `json:"schema"`
+	Name   string `json:"name"`
+	Type   string `json:"type,omitempty"`
+	Depth  int    `json:"depth"`
+}
+
+// dependencyEdge states that From depends on To
+type dependencyEdge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Relation string `json:"relation"`
+}
+
+// dependencyGraph accumulates the nodes and edges found while walking dependencies
+type dependencyGraph struct {
+	nodes     []*dependencyNode
+	byID      map[string]*dependencyNode
+	edges     []dependencyEdge
+	edgeSeen  map[dependencyEdge]bool
+	truncated bool
+}
+
+// getObjectDependenciesArgs are the arguments of get_object_dependencies
+type getObjectDependenciesArgs struct {
+	ObjectName string `json:"object_name" jsonschema_description:"Table, view, function or procedure name"`
+	Schema     string `json:"schema,omitempty" jsonschema_description:"Schema name (optional, uses the default schema)"`
+	Direction  string `json:"direction,omitempty" jsonschema_description:"Dependencies to follow: upstream, downstream or both (default: both)" jsonschema:"enum=upstream,enum=downstream,enum=both"`
+	Depth      int    `json:"depth,omitempty" jsonschema_description:"Number of levels to follow (default: 1, maximum: 5)"`
+}
+
+func (s *DbMCPServer) toolGetObjectDependencies() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("get_object_dependencies", "Returns the upstream (objects it uses) and downstream (objects using it) dependencies of a table, view, function or procedure as a graph of nodes and edges. Use before schema changes to assess impact", s.handleGetObjectDependencies)
+}
+
+func (s *DbMCPServer) handleGetObjectDependencies(ctx context.Context, request mcp.CallToolRequest, args getObjectDependenciesArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	objectName := args.ObjectName
+	if !isValidIdentifier(objectName) {
+		return toolErrorResult(ErrInvalidObjectName), nil
+	}
+
+	defaultSchema := getDefaultSchema(s.queryBuilder.GetDriver())
+	schema, err := getValidSchema(args.Schema, defaultSchema)
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	direction := args.Direction
+	if direction == "" {
+		direction = "both"
+	}
+	if direction != "upstream" && direction != "downstream" && direction != "both" {
+		return toolErrorResult(ErrInvalidDirection), nil
+	}
+
+	depth := args.Depth
+	if depth < 1 {
+		depth = DefaultDependencyDepth
+	}
+	if depth > MaxDependencyDepth {
+		depth = MaxDependencyDepth
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, DefaultQueryTimeout)
+	defer cancel()
+
+	graph := &dependencyGraph{
+		byID:     make(map[string]*dependencyNode),
+		edgeSeen: make(map[dependencyEdge]bool),
+	}
+	root := graph.addNode(schema, objectName, "", 0)
+
+	if direction == "upstream" || direction == "both" {
+		if err = s.walkDependencies(ctx, graph, root, depth, true); err != nil {
+			return s.dbErrorResult(ErrFetchingDependencies, err), nil
+		}
+	}
+	if direction == "downstream" || direction == "both" {
+		if err = s.walkDependencies(ctx, graph, root, depth, false); err != nil {
+			return s.dbErrorResult(ErrFetchingDependencies, err), nil
+		}
+	}
+
+	response := map[string]interface{}{
+		"object":    root.ID,
+		"direction": direction,
+		"depth":     depth,
+		"nodes":     graph.nodes,
+		"edges":     graph.edges,
+		"truncated": graph.truncated,
+	}
+
+	return jsonToolResult(response), nil
+}
+
+// walkDependencies follows dependencies breadth-first from root in one direction,
+// up to depth levels or MaxDependencyNodes nodes
+func (s *DbMCPServer) walkDependencies(ctx context.Context, graph *dependencyGraph, root *dependencyNode, depth int, upstream bool) error {
+	frontier := []*dependencyNode{root}
+	visited := map[string]bool{root.ID: true}
+
+	for level := 1; level <= depth && len(frontier) > 0; level++ {
+		var next []*dependencyNode
+		for _, node := range frontier {
+			query, queryArgs := s.queryBuilder.DownstreamDependenciesQuery(node.Schema, node.Name)
+			if upstream {
+				query, queryArgs = s.queryBuilder.UpstreamDependenciesQuery(node.Schema, node.Name)
+			}
+
+			rows, err := s.db.QueryContext(ctx, query, queryArgs...)
+			if err != nil {
+				return err
+			}
+
+			for rows.Next() {
+				var schema, name, objectType, relation string
+				if err = rows.Scan(&schema, &name, &objectType, &relation); err != nil {
+					continue
+				}
+
+				if len(graph.nodes) >= MaxDependencyNodes && graph.byID[dependencyID(schema, name)] == nil {
+					graph.truncated = true
+					continue
+				}
+				dep := graph.addNode(schema, name, objectType, level)
+
+				if upstream {
+					graph.addEdge(node.ID, dep.ID, relation)
+				} else {
+					graph.addEdge(dep.ID, node.ID, relation)
+				}
+
+				if !visited[dep.ID] {
+					visited[dep.ID] = true
+					next = append(next, dep)
+				}
+			}
+			err = rows.Err()
+			rows.Close()
+			if err != nil {
+				return err
+			}
+		}
+		frontier = next
+	}
+
+	return nil
+}
+
+// addNode returns the node for an object, creating it on first sight
+func (g *dependencyGraph) addNode(schema, name, objectType string, depth int) *dependencyNode {
+	id := dependencyID(schema, name)
+	if node, ok := g.byID[id]; ok {
+		if node.Type == "" {
+			node.Type = objectType
+		}
+		return node
+	}
+
+	node := &dependencyNode{ID: id, Schema: schema, Name: name, Type: objectType, Depth: depth}
+	g.nodes = append(g.nodes, node)
+	g.byID[id] = node
+	return node
+}
+
+// addEdge records that from depends on to, ignoring duplicates
+func (g *dependencyGraph) addEdge(from, to, relation string) {
+	edge := dependencyEdge{From: from, To: to, Relation: relation}
+	if g.edgeSeen[edge] {
+		return
+	}
+	g.edgeSeen[edge] = true
+	g.edges = append(g.edges, edge)
+}
+
+// dependencyID returns the graph identifier of an object
+func dependencyID(schema, name string) string {
+	if schema == "" {
+		return name
+	}
+	return schema + "." + name
+}
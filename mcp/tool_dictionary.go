@@ -0,0 +1,215 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// dictionaryTable is a table of the data dictionary with its columns in position order
+type dictionaryTable struct {
+	Schema  string             `json:"schema"`
+	Name    string             `json:"name"`
+	Comment string             `json:"comment,omitempty"`
+	Columns []dictionaryColumn `json:"columns"`
+}
+
+// dictionaryColumn is a column of the data dictionary
+type dictionaryColumn struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Nullable   bool   `json:"nullable"`
+	Default    string `json:"default,omitempty"`
+	PrimaryKey bool   `json:"primary_key,omitempty"`
+	Comment    string `json:"comment,omitempty"`
+}
+
+// exportDataDictionaryArgs are the arguments of export_data_dictionary
+type exportDataDictionaryArgs struct {
+	Schema string `json:"schema,omitempty" jsonschema_description:"Schema name (optional, uses the default schema)"`
+	Format string `json:"format,omitempty" jsonschema_description:"Document format: json or markdown (default: json)" jsonschema:"enum=json,enum=markdown"`
+	pageArgs
+}
+
+func (s *DbMCPServer) toolExportDataDictionary() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("export_data_dictionary", "Exports the data dictionary of a schema as one JSON or markdown document: every table with its comment and its columns with type, nullability, default, primary key and comment. Tables are paginated; request the next page while has_more is true", s.handleExportDataDictionary)
+}
+
+func (s *DbMCPServer) handleExportDataDictionary(ctx context.Context, request mcp.CallToolRequest, args exportDataDictionaryArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	defaultSchema := getDefaultSchema(s.queryBuilder.GetDriver())
+	schema, err := getValidSchema(args.Schema, defaultSchema)
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	format := strings.ToLower(args.Format)
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "markdown" {
+		return toolErrorResult(ErrInvalidDictionaryFormat), nil
+	}
+
+	pagination := args.pagination()
+
+	ctx, cancel := withQueryTimeout(ctx, DefaultQueryTimeout)
+	defer cancel()
+
+	// One table more than the page tells whether there is a next page
+	tables, err := s.fetchDictionaryTables(ctx, schema, pagination.PageSize+1, pagination.Offset)
+	if err != nil {
+		return s.dbErrorResult(ErrExportingDataDictionary, err), nil
+	}
+	hasMore := len(tables) > pagination.PageSize
+	if hasMore {
+		tables = tables[:pagination.PageSize]
+	}
+
+	if err := s.fetchDictionaryColumns(ctx, schema, tables); err != nil {
+		return s.dbErrorResult(ErrExportingDataDictionary, err), nil
+	}
+
+	response := map[string]interface{}{
+		"schema": schema,
+		"format": format,
+		"pagination": map[string]interface{}{
+			"page":      pagination.Page,
+			"page_size": pagination.PageSize,
+			"count":     len(tables),
+			"has_more":  hasMore,
+		},
+	}
+	if format == "markdown" {
+		response["document"] = formatDataDictionary(schema, pagination.Page, tables)
+	} else {
+		response["tables"] = tables
+	}
+
+	return jsonToolResult(response), nil
+}
+
+// fetchDictionaryTables returns a page of the tables of a schema with their comments
+func (s *DbMCPServer) fetchDictionaryTables(ctx context.Context, schema string, limit, offset int) ([]*dictionaryTable, error) {
+	query, args := s.queryBuilder.DataDictionaryTablesQuery(schema, limit, offset)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := []*dictionaryTable{}
+	for rows.Next() {
+		var tableSchema, tableName string
+		var comment sql.NullString
+		if err := rows.Scan(&tableSchema, &tableName, &comment); err != nil {
+			continue
+		}
+		tables = append(tables, &dictionaryTable{
+			Schema:  tableSchema,
+			Name:    tableName,
+			Comment: comment.String,
+			Columns: []dictionaryColumn{},
+		})
+	}
+	return tables, rows.Err()
+}
+
+// fetchDictionaryColumns adds their columns to tables, with one query for the whole page
+func (s *DbMCPServer) fetchDictionaryColumns(ctx context.Context, schema string, tables []*dictionaryTable) error {
+	if len(tables) == 0 {
+		return nil
+	}
+
+	byKey := make(map[string]*dictionaryTable, len(tables))
+	seen := make(map[string]bool, len(tables))
+	var names []string
+	for _, table := range tables {
+		byKey[table.Schema+"."+table.Name] = table
+		if !seen[table.Name] {
+			seen[table.Name] = true
+			names = append(names, table.Name)
+		}
+	}
+
+	query, args := s.queryBuilder.DataDictionaryColumnsQuery(schema, names)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableSchema, tableName, columnName, isNullable string
+		var dataType, defaultValue, comment sql.NullString
+		var isPrimaryKey int
+		if err := rows.Scan(&tableSchema, &tableName, &columnName, &dataType, &isNullable, &defaultValue, &comment, &isPrimaryKey); err != nil {
+			continue
+		}
+
+		// A table name can repeat across schemas when no schema is given
+		table := byKey[tableSchema+"."+tableName]
+		if table == nil {
+			continue
+		}
+		table.Columns = append(table.Columns, dictionaryColumn{
+			Name:       columnName,
+			Type:       dataType.String,
+			Nullable:   strings.EqualFold(isNullable, "YES"),
+			Default:    strings.TrimSpace(defaultValue.String),
+			PrimaryKey: isPrimaryKey == 1,
+			Comment:    comment.String,
+		})
+	}
+	return rows.Err()
+}
+
+// formatDataDictionary returns a page of the data dictionary as a markdown document with
+// a section per table
+func formatDataDictionary(schema string, page int, tables []*dictionaryTable) string {
+	var b strings.Builder
+	b.WriteString("# " + translate("Data dictionary"))
+	if schema != "" {
+		b.WriteString(": " + schema)
+	}
+	if page > 1 {
+		fmt.Fprintf(&b, " ("+translate("page %d")+")", page)
+	}
+	b.WriteString("\n")
+
+	for _, table := range tables {
+		fmt.Fprintf(&b, "\n## %s.%s\n\n", table.Schema, table.Name)
+		if table.Comment != "" {
+			b.WriteString(markdownCell(table.Comment) + "\n\n")
+		}
+
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n", translate("Column"), translate("Type"), translate("Nullable"), translate("Default"), translate("Key"), translate("Description"))
+		b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+		for _, column := range table.Columns {
+			nullable, key := translate("no"), ""
+			if column.Nullable {
+				nullable = translate("yes")
+			}
+			if column.PrimaryKey {
+				key = "PK"
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+				markdownCell(column.Name), markdownCell(column.Type), nullable,
+				markdownCell(column.Default), key, markdownCell(column.Comment))
+		}
+	}
+	return b.String()
+}
+
+// markdownCell escapes a value for a markdown table cell, keeping it on one line
+func markdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", `\|`)
+	return strings.Join(strings.Fields(value), " ")
+}
@@ -0,0 +1,263 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// erdTable is a table of the diagram with its columns in position order
+type erdTable struct {
+	name    string
+	columns []map[string]interface{}
+}
+
+// erdRelationship is a foreign key drawn as an edge from the owning to the referenced table
+type erdRelationship struct {
+	name     string
+	table    string
+	refTable string
+	columns  []string
+}
+
+// generateERDArgs are the arguments of generate_erd
+type generateERDArgs struct {
+	Schema string   `json:"schema,omitempty" jsonschema_description:"Schema name (optional, uses the default schema)"`
+	Tables []string `json:"tables,omitempty" jsonschema_description:"Tables to include (optional, default: all tables of the schema, up to 100)"`
+}
+
+func (s *DbMCPServer) toolGenerateERD() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("generate_erd", "Generates a Mermaid erDiagram of a schema or a list of tables, with their columns, primary and foreign keys and the relationships between them, ready to render in chat. Foreign keys to tables outside the diagram are left out", s.handleGenerateERD)
+}
+
+func (s *DbMCPServer) handleGenerateERD(ctx context.Context, request mcp.CallToolRequest, args generateERDArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	defaultSchema := getDefaultSchema(s.queryBuilder.GetDriver())
+	schema, err := getValidSchema(args.Schema, defaultSchema)
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	for _, tableName := range args.Tables {
+		if !isValidIdentifier(tableName) {
+			return toolErrorResult(ErrInvalidTableName), nil
+		}
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, DefaultQueryTimeout)
+	defer cancel()
+
+	tableNames := args.Tables
+	if len(tableNames) == 0 {
+		tableNames, err = s.fetchERDTableNames(ctx, schema)
+		if err != nil {
+			return s.dbErrorResult(ErrGeneratingERD, err), nil
+		}
+	}
+	tableNames = uniqueSortedNames(tableNames)
+
+	truncated := len(tableNames) > MaxERDTables
+	if truncated {
+		tableNames = tableNames[:MaxERDTables]
+	}
+
+	var tables []*erdTable
+	for _, tableName := range tableNames {
+		query, queryArgs := s.queryBuilder.GetTableSchemaFullQuery(schema, tableName)
+		columns, err := s.fetchSchemaColumns(ctx, query, queryArgs)
+		if err != nil {
+			return s.dbErrorResult(ErrGeneratingERD, err), nil
+		}
+		if len(columns) == 0 {
+			if len(args.Tables) > 0 {
+				return toolErrorResult(fmt.Errorf("%w: %s.%s", ErrTableNotFound, schema, tableName)), nil
+			}
+			continue
+		}
+		tables = append(tables, &erdTable{name: tableName, columns: columns})
+	}
+
+	relationships, err := s.fetchERDRelationships(ctx, schema, tables)
+	if err != nil {
+		return s.dbErrorResult(ErrGeneratingERD, err), nil
+	}
+
+	response := map[string]interface{}{
+		"schema":        schema,
+		"tables":        len(tables),
+		"relationships": len(relationships),
+		"truncated":     truncated,
+		"mermaid":       formatERD(tables, relationships),
+	}
+
+	return jsonToolResult(response), nil
+}
+
+// fetchERDTableNames returns the names of the tables of a schema, one more than
+// MaxERDTables at most so the caller can tell the diagram was truncated. Tables whose
+// names are not plain identifiers are skipped, as their metadata cannot be queried safely.
+func (s *DbMCPServer) fetchERDTableNames(ctx context.Context, schema string) ([]string, error) {
+	query, args := s.queryBuilder.ListTablesQuery(schema, "", MaxERDTables+1, 0)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var tableSchema, tableName, tableType string
+		if err := rows.Scan(&tableSchema, &tableName, &tableType); err != nil || !isValidIdentifier(tableName) {
+			continue
+		}
+		names = append(names, tableName)
+	}
+	return names, rows.Err()
+}
+
+// fetchERDRelationships returns the foreign keys of the schema between tables of the
+// diagram, marking their columns as foreign keys
+func (s *DbMCPServer) fetchERDRelationships(ctx context.Context, schema string, tables []*erdTable) ([]*erdRelationship, error) {
+	byName := make(map[string]*erdTable, len(tables))
+	for _, table := range tables {
+		byName[strings.ToLower(table.name)] = table
+	}
+
+	query, args := s.queryBuilder.ListForeignKeysQuery(schema, "", "")
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Rows come one per column pair, ordered by constraint
+	var relationships []*erdRelationship
+	byKey := make(map[string]*erdRelationship)
+	for rows.Next() {
+		var fkSchema, fkTable, constraintName, columnName string
+		var refSchema, refTable string
+		var refColumn, onDelete, onUpdate sql.NullString
+
+		if err := rows.Scan(&fkSchema, &fkTable, &constraintName, &columnName, &refSchema, &refTable, &refColumn, &onDelete, &onUpdate); err != nil {
+			continue
+		}
+
+		table, refTableInDiagram := byName[strings.ToLower(fkTable)], byName[strings.ToLower(refTable)]
+		if table == nil || refTableInDiagram == nil || !strings.EqualFold(refSchema, fkSchema) {
+			continue
+		}
+
+		key := fkTable + "." + constraintName
+		relationship, exists := byKey[key]
+		if !exists {
+			relationship = &erdRelationship{name: constraintName, table: table.name, refTable: refTableInDiagram.name}
+			byKey[key] = relationship
+			relationships = append(relationships, relationship)
+		}
+		relationship.columns = append(relationship.columns, columnName)
+	}
+	return relationships, rows.Err()
+}
+
+// formatERD returns the Mermaid erDiagram of tables and the relationships between them.
+// A relationship is optional on the referenced side when one of its columns is nullable.
+func formatERD(tables []*erdTable, relationships []*erdRelationship) string {
+	foreignKeyColumns := make(map[string]bool)
+	for _, relationship := range relationships {
+		for _, column := range relationship.columns {
+			foreignKeyColumns[relationship.table+"."+column] = true
+		}
+	}
+
+	nullable := make(map[string]bool)
+	var b strings.Builder
+	b.WriteString("erDiagram\n")
+	for _, table := range tables {
+		fmt.Fprintf(&b, "    %s {\n", erdEntityName(table.name))
+		for _, column := range table.columns {
+			name, _ := column["name"].(string)
+			dataType, _ := column["type"].(string)
+			if dataType == "" {
+				dataType = "unknown"
+			}
+			if isNullable, _ := column["nullable"].(bool); isNullable {
+				nullable[table.name+"."+name] = true
+			}
+
+			var keys []string
+			if isPrimaryKey, _ := column["is_primary_key"].(bool); isPrimaryKey {
+				keys = append(keys, "PK")
+			}
+			if foreignKeyColumns[table.name+"."+name] {
+				keys = append(keys, "FK")
+			}
+
+			fmt.Fprintf(&b, "        %s %s", erdToken(dataType), erdToken(name))
+			if len(keys) > 0 {
+				b.WriteString(" " + strings.Join(keys, ", "))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("    }\n")
+	}
+
+	for _, relationship := range relationships {
+		cardinality := "}o--||"
+		for _, column := range relationship.columns {
+			if nullable[relationship.table+"."+column] {
+				cardinality = "}o--o|"
+				break
+			}
+		}
+		fmt.Fprintf(&b, "    %s %s %s : \"%s\"\n", erdEntityName(relationship.table), cardinality, erdEntityName(relationship.refTable), strings.ReplaceAll(relationship.name, `"`, "'"))
+	}
+	return b.String()
+}
+
+// erdEntityName returns a table name as a Mermaid entity, quoted when it is not a plain word
+func erdEntityName(name string) string {
+	if erdToken(name) == name {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, "'") + `"`
+}
+
+// erdToken returns a column name or type as a Mermaid attribute token, replacing the
+// characters Mermaid does not accept with underscores
+func erdToken(value string) string {
+	token := []rune(value)
+	for i, r := range token {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+		case r >= '0' && r <= '9', r == '-', r == '(', r == ')', r == '[', r == ']':
+		default:
+			token[i] = '_'
+		}
+	}
+	if len(token) == 0 || !(token[0] == '_' || token[0] >= 'a' && token[0] <= 'z' || token[0] >= 'A' && token[0] <= 'Z') {
+		return "_" + string(token)
+	}
+	return string(token)
+}
+
+// uniqueSortedNames returns names sorted, without duplicates
+func uniqueSortedNames(names []string) []string {
+	unique := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
@@ -0,0 +1,201 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// explainQueryArgs are the arguments of explain_query
+type explainQueryArgs struct {
+	Query          string `json:"query" jsonschema_description:"SELECT query whose plan to return; it is not executed unless analyze is true"`
+	Analyze        bool   `json:"analyze,omitempty" jsonschema_description:"Run the query and return the plan with actual row counts and timings (EXPLAIN ANALYZE, SQL Server STATISTICS XML) instead of the estimated plan; the results are discarded (default: false, not on SQLite)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema_description:"Seconds the query may run when analyze is true (default: 10, maximum: 30)"`
+}
+
+func (s *DbMCPServer) toolExplainQuery() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("explain_query", "Returns the estimated execution plan of a SELECT query without executing it: SHOWPLAN XML on SQL Server, EXPLAIN JSON on Postgres and MySQL, DBMS_XPLAN on Oracle, EXPLAIN QUERY PLAN on SQLite, plus the same plan as a tree of operators with their object, estimated rows and cost (and actual rows and time with analyze) that reads the same on every database. Use it to find scans, missing indexes and costly joins before running a heavy query. With analyze it runs the query and returns the actual row counts and timings to compare with the estimates", s.handleExplainQuery)
+}
+
+func (s *DbMCPServer) handleExplainQuery(ctx context.Context, request mcp.CallToolRequest, args explainQueryArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	query := args.Query
+	if query == "" {
+		return toolErrorResult(ErrQueryRequired), nil
+	}
+
+	// The plan statement wraps the query, so it must pass the same validation
+	validator := s.newSQLValidator(query)
+	if err := validator.Validate().Err(); err != nil {
+		log.Printf("Query blocked: %s\nReason: %v\n", query, err)
+		return toolErrorResult(fmt.Errorf("%w: %w", ErrQueryNotAllowed, err)), nil
+	}
+
+	timeout := DefaultQueryTimeout
+	explain, setup, planQuery := s.queryBuilder.ExplainQuery(query)
+	if args.Analyze {
+		var ok bool
+		explain, setup, planQuery, ok = s.queryBuilder.ExplainAnalyzeQuery(query)
+		if !ok {
+			return toolErrorResult(ErrFeatureNotSupported), nil
+		}
+		timeout = DefaultAnalyzeTimeout
+		if args.TimeoutSeconds > 0 {
+			timeout = time.Duration(args.TimeoutSeconds) * time.Second
+		}
+		if timeout > MaxAnalyzeTimeout {
+			timeout = MaxAnalyzeTimeout
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// An actual plan runs the query, so the watchdog guards it like execute_query
+	var watch *watchedQuery
+	if args.Analyze {
+		ctx, watch = s.watchdog.Watch(ctx, "explain_query", query)
+		defer watch.Done()
+	}
+
+	// The setup statement changes the session, so its connection never returns to the pool
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return s.dbErrorResult(ErrExplainingQuery, err), nil
+	}
+	if setup != "" {
+		defer discardConn(conn)
+		if _, err = conn.ExecContext(ctx, setup); err != nil {
+			return s.dbErrorResult(ErrExplainingQuery, err), nil
+		}
+	} else {
+		defer conn.Close()
+	}
+
+	// An actual plan runs the query in a read-only transaction, as execute_query does
+	var querier planQuerier = conn
+	if args.Analyze {
+		tx, err := s.beginReadOnly(ctx, conn)
+		if err != nil {
+			return s.dbErrorResult(ErrExplainingQuery, err), nil
+		}
+		defer endReadOnly(tx)
+		querier = tx
+	}
+
+	var plan *planRows
+	if planQuery != "" && !args.Analyze {
+		_, err = conn.ExecContext(ctx, explain)
+	} else {
+		plan, err = queryPlan(ctx, querier, explain, watch)
+	}
+	if err == nil && planQuery != "" {
+		plan, err = queryPlan(ctx, querier, planQuery, nil)
+	}
+	if err != nil {
+		return s.dbErrorResult(ErrExplainingQuery, watch.Cause(err)), nil
+	}
+
+	format := s.queryBuilder.ExplainFormat(args.Analyze)
+	response := map[string]interface{}{
+		"plan":      plan.format(format),
+		"format":    format,
+		"estimated": !args.Analyze,
+	}
+	if tree := planTree(format, plan); tree != nil {
+		response["tree"] = tree
+	}
+	if args.Analyze {
+		response["timeout_seconds"] = int(timeout / time.Second)
+	}
+
+	return jsonToolResult(response), nil
+}
+
+// planRows are the rows of the last result set of a plan statement, with the first
+// column of each row as a line of text
+type planRows struct {
+	steps []map[string]interface{}
+	lines []string
+}
+
+// planQuerier runs plan statements, on a connection or in the transaction of an actual plan
+type planQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// queryPlan runs a plan statement with args bound to its placeholders and keeps the rows of
+// its last result set. Rows of earlier result sets, the query results of an actual plan,
+// are discarded but counted by the watchdog.
+func queryPlan(ctx context.Context, querier planQuerier, statement string, watch *watchedQuery, args ...interface{}) (*planRows, error) {
+	rows, err := querier.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var plan *planRows
+	for {
+		columns, err := rows.Columns()
+		if err != nil {
+			return nil, err
+		}
+
+		values := make([]interface{}, len(columns))
+		valuePtrs := make([]interface{}, len(columns))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+
+		encoder := newResultEncoder(rows)
+		plan = &planRows{steps: []map[string]interface{}{}}
+		for rows.Next() {
+			if err = rows.Scan(valuePtrs...); err != nil {
+				return nil, err
+			}
+			encoder.encode(values)
+			step := make(map[string]interface{}, len(columns))
+			for i, column := range columns {
+				step[column] = values[i]
+			}
+			plan.steps = append(plan.steps, step)
+			if len(columns) > 0 {
+				plan.lines = append(plan.lines, fmt.Sprint(values[0]))
+			}
+			watch.Row()
+		}
+		if err = rows.Err(); err != nil {
+			return nil, err
+		}
+		if !rows.NextResultSet() {
+			break
+		}
+	}
+	return plan, rows.Err()
+}
+
+// format returns the plan as the steps (rows), one JSON document (json) or text (xml, text)
+func (p *planRows) format(format string) interface{} {
+	switch format {
+	case "rows":
+		return p.steps
+	case "json":
+		document := strings.Join(p.lines, "\n")
+		if json.Valid([]byte(document)) {
+			return json.RawMessage(document)
+		}
+		return document
+	default:
+		return strings.Join(p.lines, "\n")
+	}
+}
@@ -0,0 +1,279 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// reExportFileName matches the file names export_query may write in DB_EXPORT_DIR
+var reExportFileName = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9_.-]*$`)
+
+// getEnvExportDir reads the directory export_query writes files to from DB_EXPORT_DIR
+func getEnvExportDir() string {
+	return strings.TrimSpace(os.Getenv("DB_EXPORT_DIR"))
+}
+
+// exportQueryArgs are the arguments of export_query
+type exportQueryArgs struct {
+	Query      string      `json:"query" jsonschema_description:"SQL query to be exported (SELECT only)"`
+	Parameters interface{} `json:"parameters,omitempty" jsonschema_description:"Values bound to the placeholders of the query, as in execute_query (optional)"`
+	FileName   string      `json:"file_name,omitempty" jsonschema_description:"Name of the Parquet file written to DB_EXPORT_DIR; without it the file is returned as a base64 embedded resource of at most 8MB (optional)"`
+	MaxRows    int         `json:"max_rows,omitempty" jsonschema_description:"Maximum number of rows to be exported (default and maximum: 1000000)"`
+}
+
+func (s *DbMCPServer) toolExportQuery() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("export_query", "Exports the results of a SELECT query to a Parquet file, streamed row group by row group, for handing large extracts to analytic tools. The file is written to DB_EXPORT_DIR when file_name is given, or returned as a base64 embedded resource. Integer, floating point, boolean and timestamp columns keep their types; other columns are exported as text or binary", s.handleExportQuery)
+}
+
+func (s *DbMCPServer) handleExportQuery(ctx context.Context, request mcp.CallToolRequest, args exportQueryArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	query := args.Query
+	if query == "" {
+		return toolErrorResult(ErrQueryRequired), nil
+	}
+
+	validator := s.newSQLValidator(query)
+	if err := validator.Validate().Err(); err != nil {
+		log.Printf("Query blocked: %s\nReason: %v\n", query, err)
+		return toolErrorResult(fmt.Errorf("%w: %w", ErrQueryNotAllowed, err)), nil
+	}
+
+	params, err := s.bindQueryParameters(args.Parameters)
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	maxRows := args.MaxRows
+	if maxRows <= 0 || maxRows > MaxExportRows {
+		maxRows = MaxExportRows
+	}
+
+	// Check the destination before running the query
+	path := ""
+	if args.FileName != "" {
+		if path, err = exportPath(args.FileName); err != nil {
+			return toolErrorResult(err), nil
+		}
+	}
+
+	largeTableWarning, rejected := s.checkLargeTables(ctx, query)
+	if rejected != nil {
+		return rejected, nil
+	}
+	costWarning, rejected := s.checkQueryCost(ctx, query, params)
+	if rejected != nil {
+		return rejected, nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, ExportQueryTimeout)
+	defer cancel()
+
+	tx, err := s.beginReadOnly(ctx, nil)
+	if err != nil {
+		return s.dbErrorResult(ErrExecutingQuery, err), nil
+	}
+	defer endReadOnly(tx)
+
+	ctx, watch := s.watchdog.Watch(ctx, "export_query", query)
+	defer watch.Done()
+
+	rows, err := tx.QueryContext(ctx, s.queryBuilder.LimitQuery(query, maxRows+1), params...)
+	if err != nil {
+		return s.queryErrorResult(query, watch, err), nil
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return toolErrorResult(ErrRetrievingColumns), nil
+	}
+	columns := exportColumns(columnTypes)
+
+	// Files are written under a temporary name, so a failed export leaves no partial file
+	var out io.Writer
+	var file *os.File
+	var inline *limitedBuffer
+	if path != "" {
+		if file, err = os.CreateTemp(filepath.Dir(path), ".export-*"); err != nil {
+			return toolErrorResult(fmt.Errorf("%w: %w", ErrWritingExport, err)), nil
+		}
+		defer func() {
+			_ = file.Close()
+			_ = os.Remove(file.Name())
+		}()
+		out = file
+	} else {
+		inline = &limitedBuffer{limit: MaxInlineExportBytes}
+		out = inline
+	}
+
+	writer, err := newParquetWriter(out, columns, ExportRowGroupRows)
+	if err != nil {
+		return toolErrorResult(fmt.Errorf("%w: %w", ErrWritingExport, err)), nil
+	}
+
+	values := make([]interface{}, len(columns))
+	valuePtrs := make([]interface{}, len(columns))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+
+	truncated := false
+	for rows.Next() {
+		if writer.Rows() >= int64(maxRows) {
+			truncated = true
+			break
+		}
+		if err = rows.Scan(valuePtrs...); err != nil {
+			return toolErrorResult(ErrReadingRow), nil
+		}
+		if err = writer.Append(values); err != nil {
+			return exportErrorResult(err), nil
+		}
+		watch.Row()
+	}
+
+	if err = watch.Err(); err != nil {
+		return toolErrorResult(err), nil
+	}
+	if err = rows.Err(); err != nil {
+		log.Printf("Error during export: %v\n", err)
+		return toolErrorResult(ErrReadingResults), nil
+	}
+	if err = writer.Close(); err != nil {
+		return exportErrorResult(err), nil
+	}
+
+	exported := make([]map[string]interface{}, len(columns))
+	for i, column := range columns {
+		exported[i] = map[string]interface{}{
+			"name": column.Name,
+			"type": column.Type.String(),
+		}
+	}
+	response := map[string]interface{}{
+		"format":    "parquet",
+		"row_count": writer.Rows(),
+		"columns":   exported,
+		"truncated": truncated,
+		"max_rows":  maxRows,
+		"bytes":     writer.offset,
+	}
+	for key, warning := range queryWarnings(costWarning, largeTableWarning) {
+		response[key] = warning
+	}
+
+	if file != nil {
+		if err = file.Close(); err != nil {
+			return toolErrorResult(fmt.Errorf("%w: %w", ErrWritingExport, err)), nil
+		}
+		if err = linkExport(file.Name(), path); err != nil {
+			return toolErrorResult(err), nil
+		}
+		response["file"] = path
+		return jsonToolResult(response), nil
+	}
+
+	result := jsonToolResult(response)
+	result.Content = append(result.Content, mcp.NewEmbeddedResource(mcp.BlobResourceContents{
+		URI:      "export://query.parquet",
+		MIMEType: "application/vnd.apache.parquet",
+		Blob:     base64.StdEncoding.EncodeToString(inline.Bytes()),
+	}))
+	return result, nil
+}
+
+// exportPath returns the path in DB_EXPORT_DIR of a new export file, adding the .parquet
+// extension. Names cannot leave the directory or replace an existing file.
+func exportPath(name string) (string, error) {
+	dir := getEnvExportDir()
+	if dir == "" {
+		return "", ErrExportDirNotConfigured
+	}
+	if !reExportFileName.MatchString(name) {
+		return "", ErrInvalidExportFileName
+	}
+	if !strings.EqualFold(filepath.Ext(name), ".parquet") {
+		name += ".parquet"
+	}
+
+	path := filepath.Join(dir, name)
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("%w: %s", ErrExportFileExists, name)
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("%w: %w", ErrWritingExport, err)
+	}
+	return path, nil
+}
+
+// linkExport gives the written temporary file of an export its name. Unlike a rename, the
+// link fails when a file of that name was created since exportPath checked it, so an export
+// never replaces a file; the temporary name is removed by the caller.
+func linkExport(tempPath, path string) error {
+	if err := os.Link(tempPath, path); err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return fmt.Errorf("%w: %s", ErrExportFileExists, filepath.Base(path))
+		}
+		return fmt.Errorf("%w: %w", ErrWritingExport, err)
+	}
+	return nil
+}
+
+// exportColumns returns the exported columns of a result. Parquet column names must be
+// unique, so repeated names get a numeric suffix and unnamed columns a positional name.
+func exportColumns(columnTypes []*sql.ColumnType) []parquetColumn {
+	columns := make([]parquetColumn, len(columnTypes))
+	seen := make(map[string]bool, len(columnTypes))
+	for i, columnType := range columnTypes {
+		name := columnType.Name()
+		if name == "" {
+			name = fmt.Sprintf("column_%d", i+1)
+		}
+		unique := name
+		for n := 2; seen[strings.ToLower(unique)]; n++ {
+			unique = fmt.Sprintf("%s_%d", name, n)
+		}
+		seen[strings.ToLower(unique)] = true
+		columns[i] = parquetColumn{Name: unique, Type: parquetTypeOf(columnType.DatabaseTypeName())}
+	}
+	return columns
+}
+
+// exportErrorResult reports an export that failed while writing the file
+func exportErrorResult(err error) *mcp.CallToolResult {
+	if errors.Is(err, ErrExportValue) || errors.Is(err, ErrExportTooLarge) {
+		return toolErrorResult(err)
+	}
+	return toolErrorResult(fmt.Errorf("%w: %w", ErrWritingExport, err))
+}
+
+// limitedBuffer is an in-memory export that fails once it grows past limit bytes
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+// Write appends data to the buffer, or fails with ErrExportTooLarge past the limit
+func (b *limitedBuffer) Write(data []byte) (int, error) {
+	if b.Len()+len(data) > b.limit {
+		return 0, ErrExportTooLarge
+	}
+	return b.Buffer.Write(data)
+}
@@ -0,0 +1,37 @@
+package mcp
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLinkExport checks that an export never replaces a file created after its name was
+// checked
+func TestLinkExport(t *testing.T) {
+	dir := t.TempDir()
+	temp := filepath.Join(dir, ".export-1")
+	if err := os.WriteFile(temp, []byte("export"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "report.parquet")
+	if err := linkExport(temp, path); err != nil {
+		t.Fatalf("linkExport() error = %v", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "export" {
+		t.Errorf("exported file = %q, %v, want the temporary file", data, err)
+	}
+
+	existing := filepath.Join(dir, "existing.parquet")
+	if err := os.WriteFile(existing, []byte("kept"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := linkExport(temp, existing); !errors.Is(err, ErrExportFileExists) {
+		t.Errorf("linkExport() over an existing file error = %v, want %v", err, ErrExportFileExists)
+	}
+	if data, _ := os.ReadFile(existing); string(data) != "kept" {
+		t.Errorf("existing file = %q, want it kept", data)
+	}
+}
@@ -0,0 +1,261 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// findValueInTableArgs are the arguments of find_value_in_table
+type findValueInTableArgs struct {
+	TableName      string `json:"table_name" jsonschema_description:"Table name"`
+	Schema         string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	Value          string `json:"value" jsonschema_description:"Value to search for"`
+	Match          string `json:"match,omitempty" jsonschema_description:"exact to match whole values, or contains to match part of a value (default: exact)"`
+	IncludeNumeric bool   `json:"include_numeric,omitempty" jsonschema_description:"Also search numeric columns when the value is a number (default: false)"`
+	MaxRows        int    `json:"max_rows,omitempty" jsonschema_description:"Maximum matching rows to return (default: 20, maximum: 1000)"`
+}
+
+func (s *DbMCPServer) toolFindValueInTable() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("find_value_in_table", "Searches a value across all text columns of a table, and optionally its numeric columns, and returns which columns hold it with their number of matching rows, plus the first matching rows. Use it to answer which column contains a value without writing SQL. It reads the whole table", s.handleFindValueInTable)
+}
+
+func (s *DbMCPServer) handleFindValueInTable(ctx context.Context, request mcp.CallToolRequest, args findValueInTableArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	tableName := args.TableName
+	if !isValidIdentifier(tableName) {
+		return toolErrorResult(ErrInvalidTableName), nil
+	}
+	if args.Value == "" {
+		return toolErrorResult(ErrValueRequired), nil
+	}
+
+	match := strings.ToLower(args.Match)
+	switch match {
+	case "":
+		match = "exact"
+	case "exact", "contains":
+	default:
+		return toolErrorResult(ErrInvalidMatchMode), nil
+	}
+
+	defaultSchema := getDefaultSchema(s.queryBuilder.GetDriver())
+	schema, err := getValidSchema(args.Schema, defaultSchema)
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	maxRows := args.MaxRows
+	if maxRows < 1 {
+		maxRows = DefaultFindValueRows
+	}
+	maxRows = min(maxRows, MaxFindValueRows)
+
+	ctx, cancel := withQueryTimeout(ctx, DefaultQueryTimeout)
+	defer cancel()
+
+	if exists, err := s.tableExists(ctx, schema, tableName); err != nil {
+		return s.dbErrorResult(ErrCheckingTable, err), nil
+	} else if !exists {
+		return toolErrorResult(fmt.Errorf("%w: %s.%s", ErrTableNotFound, schema, tableName)), nil
+	}
+
+	columnTypes, err := s.getTableColumnTypes(ctx, schema, tableName)
+	if err != nil {
+		return s.dbErrorResult(ErrRetrievingColumns, err), nil
+	}
+	if len(columnTypes) == 0 {
+		return toolErrorResult(ErrNoColumnsFound), nil
+	}
+
+	searched, predicates, params := s.valuePredicates(columnTypes, args.Value, match == "contains", args.IncludeNumeric)
+	if len(predicates) == 0 {
+		return toolErrorResult(ErrNoSearchableColumns), nil
+	}
+
+	searchedNames := make([]string, len(searched))
+	for i, column := range searched {
+		searchedNames[i] = column.Name
+	}
+
+	qualifiedTable := s.queryBuilder.QualifyTable(schema, tableName)
+	ctx, watch := s.watchdog.Watch(ctx, "find_value_in_table", qualifiedTable+" "+strings.Join(searchedNames, ", "))
+	defer watch.Done()
+
+	// The number of matching rows of each column, in one scan of the table
+	counts := make([]string, len(predicates))
+	for i, predicate := range predicates {
+		counts[i] = fmt.Sprintf("SUM(CASE WHEN %s THEN 1 ELSE 0 END)", predicate)
+	}
+	matches := make([]sql.NullInt64, len(predicates))
+	matchPtrs := make([]interface{}, len(matches))
+	for i := range matches {
+		matchPtrs[i] = &matches[i]
+	}
+	countQuery := fmt.Sprintf("SELECT %s FROM %s", strings.Join(counts, ", "), qualifiedTable)
+	if err = s.db.QueryRowContext(ctx, countQuery, params...).Scan(matchPtrs...); err != nil {
+		return s.dbErrorResult(ErrSearchingValue, watch.Cause(err)), nil
+	}
+
+	matchedColumns := []map[string]interface{}{}
+	for i, column := range searched {
+		if matches[i].Int64 > 0 {
+			matchedColumns = append(matchedColumns, map[string]interface{}{
+				"column":    column.Name,
+				"data_type": column.DataType,
+				"rows":      matches[i].Int64,
+			})
+		}
+	}
+
+	response := map[string]interface{}{
+		"value":            args.Value,
+		"match":            match,
+		"searched_columns": searchedNames,
+		"matched_columns":  matchedColumns,
+		"table": map[string]interface{}{
+			"schema": schema,
+			"name":   tableName,
+		},
+	}
+	if len(matchedColumns) == 0 {
+		response["rows"] = []interface{}{}
+		response["row_count"] = 0
+		response["truncated"] = false
+		return jsonToolResult(response), nil
+	}
+
+	// The first matching rows, in primary key order so repeated calls agree
+	columns := make([]string, len(columnTypes))
+	for i, column := range columnTypes {
+		columns[i] = column.Name
+	}
+	pkQuery, pkArgs := s.queryBuilder.GetPrimaryKeyQuery(schema, tableName)
+	primaryKey, err := s.fetchPrimaryKey(ctx, pkQuery, pkArgs)
+	if err != nil {
+		return s.dbErrorResult(ErrRetrievingColumns, err), nil
+	}
+	orderBy := columns[0]
+	if len(primaryKey) > 0 {
+		orderBy = primaryKey[0]
+	}
+	rowsQuery := s.queryBuilder.BuildSelectQuery(SelectQueryParams{
+		Schema:         schema,
+		Table:          tableName,
+		Columns:        columns,
+		WhereClause:    "WHERE (" + strings.Join(predicates, " OR ") + ")",
+		OrderBy:        orderBy,
+		OrderDirection: "ASC",
+		TieBreakers:    primaryKey,
+		Limit:          maxRows + 1,
+	})
+
+	dbRows, err := s.db.QueryContext(ctx, rowsQuery, params...)
+	if err != nil {
+		return s.dbErrorResult(ErrSearchingValue, watch.Cause(err)), nil
+	}
+	defer dbRows.Close()
+
+	budget := s.newResultBudget()
+	rows := newResultSet(columns)
+	encoder := newResultEncoder(dbRows)
+
+	values := make([]interface{}, len(columns))
+	valuePtrs := make([]interface{}, len(columns))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+
+	truncated := false
+	for dbRows.Next() {
+		if rows.Len() >= maxRows {
+			truncated = true
+			break
+		}
+		if err = dbRows.Scan(valuePtrs...); err != nil {
+			return toolErrorResult(ErrReadingRow), nil
+		}
+		encoder.encode(values)
+		if !budget.Add(values) {
+			break
+		}
+		rows.AppendRow(values)
+		watch.Row()
+	}
+	if err = watch.Err(); err != nil {
+		return toolErrorResult(err), nil
+	}
+	if err = dbRows.Err(); err != nil {
+		return s.dbErrorResult(ErrSearchingValue, err), nil
+	}
+
+	response["rows"] = rows
+	response["columns"] = columns
+	response["row_count"] = rows.Len()
+	response["truncated"] = truncated
+	budget.Annotate(response)
+
+	return jsonToolResult(response), nil
+}
+
+// valuePredicates returns the columns that can hold value, with the predicate matching it
+// in each one and the values bound to their placeholders. Text columns are compared with
+// LIKE, whole or as a substring with contains; numeric columns are compared for equality
+// when numeric is set and value is a number.
+func (s *DbMCPServer) valuePredicates(columnTypes []tableColumnType, value string, contains, numeric bool) ([]tableColumnType, []string, []interface{}) {
+	pattern := value
+	if contains {
+		pattern = "%" + value + "%"
+	}
+	number, numberErr := strconv.ParseFloat(value, 64)
+	var bound interface{} = number
+	if numberErr == nil && number == math.Trunc(number) && math.Abs(number) < 1<<53 {
+		bound = int64(number)
+	}
+	likeOp := s.queryBuilder.LikeOperator(false)
+
+	var columns []tableColumnType
+	var predicates []string
+	var params []interface{}
+	for _, column := range columnTypes {
+		quotedColumn := s.queryBuilder.QuoteIdentifier(column.Name)
+		placeholder := s.queryBuilder.Placeholder(len(params) + 1)
+		switch {
+		case isSearchableText(column.DataType):
+			predicates = append(predicates, fmt.Sprintf("%s %s %s", quotedColumn, likeOp, placeholder))
+			params = append(params, pattern)
+		case numeric && numberErr == nil && isSearchableNumber(column.DataType):
+			predicates = append(predicates, fmt.Sprintf("%s = %s", quotedColumn, placeholder))
+			params = append(params, bound)
+		default:
+			continue
+		}
+		columns = append(columns, column)
+	}
+	return columns, predicates, params
+}
+
+// isSearchableText reports whether a column of the data type holds text that LIKE can match
+func isSearchableText(dataType string) bool {
+	name := strings.ToUpper(dataType)
+	return strings.Contains(name, "CHAR") || strings.Contains(name, "TEXT") || strings.Contains(name, "CLOB")
+}
+
+// isSearchableNumber reports whether a column of the data type holds numbers
+func isSearchableNumber(dataType string) bool {
+	switch valueKindOf(dataType) {
+	case valueKindInteger, valueKindDecimal:
+		return true
+	}
+	name := strings.ToUpper(dataType)
+	return strings.Contains(name, "FLOAT") || strings.Contains(name, "DOUBLE") || strings.Contains(name, "REAL")
+}
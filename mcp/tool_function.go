@@ -3,65 +3,44 @@ package mcp
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
-	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// listFunctionsArgs are the arguments of list_functions
+type listFunctionsArgs struct {
+	Schema     string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	Type       string `json:"type,omitempty" jsonschema_description:"Function type: 'scalar', 'table' or 'all' (default: all)"`
+	NameFilter string `json:"name_filter,omitempty" jsonschema_description:"Filter by function name (optional)"`
+	crossDatabaseArgs
+	pageArgs
+}
+
 func (s *DbMCPServer) toolListFunctions() (mcp.Tool, server.ToolHandlerFunc) {
-	return mcp.Tool{
-		Name:        "list_functions",
-		Description: "List database functions (scalar, table-valued) with pagination",
-		InputSchema: mcp.ToolInputSchema{
-			Type: "object",
-			Properties: map[string]interface{}{
-				"schema": map[string]interface{}{
-					"type":        "string",
-					"description": "Schema name (optional)",
-				},
-				"type": map[string]interface{}{
-					"type":        "string",
-					"description": "Function type: 'scalar', 'table' or 'all' (default: all)",
-				},
-				"name_filter": map[string]interface{}{
-					"type":        "string",
-					"description": "Filter by function name (optional)",
-				},
-				"page": map[string]interface{}{
-					"type":        "number",
-					"description": "Page number (default: 1)",
-				},
-				"page_size": map[string]interface{}{
-					"type":        "number",
-					"description": "Items per page (default: 100, maximum: 500)",
-				},
-			},
-		},
-	}, s.handleListFunctions
+	return newTypedTool("list_functions", "List database functions (scalar, table-valued) with pagination", s.handleListFunctions)
 }
 
-func (s *DbMCPServer) handleListFunctions(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *DbMCPServer) handleListFunctions(ctx context.Context, request mcp.CallToolRequest, args listFunctionsArgs) (*mcp.CallToolResult, error) {
 	if err := s.requireConnection(); err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
 	if !s.queryBuilder.SupportsFunctions() {
-		return mcp.NewToolResultError(ErrFunctionsNotSupported.Error()), nil
+		return toolErrorResult(ErrFunctionsNotSupported), nil
 	}
 
-	args, ok := getArgs(request.Params.Arguments)
-	if !ok {
-		return mcp.NewToolResultError(ErrInvalidArguments.Error()), nil
+	schema, err := getValidSchema(args.Schema, "")
+	if err != nil {
+		return toolErrorResult(err), nil
 	}
 
-	schema, err := getValidSchema(args, "")
+	database, schema, err := getTargetDatabase(args.Database, s.queryBuilder, schema)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
-	funcType, _ := getStringArg(args, "type")
+	funcType := args.Type
 	if funcType == "" {
 		funcType = "all"
 	}
@@ -70,23 +49,24 @@ func (s *DbMCPServer) handleListFunctions(ctx context.Context, request mcp.CallT
 	case "scalar", "table", "all":
 		// valid
 	default:
-		return mcp.NewToolResultError(ErrInvalidFunctionType.Error()), nil
+		return toolErrorResult(ErrInvalidFunctionType), nil
 	}
 
-	nameFilter, _ := getStringArg(args, "name_filter")
-	pagination := GetPaginationParams(args, DefaultPageSize, MaxPageSize)
+	nameFilter := args.NameFilter
+	pagination := args.pagination()
 
 	query, queryArgs := s.queryBuilder.ListFunctionsQuery(schema, nameFilter, funcType, pagination.PageSize, pagination.Offset)
+	query = s.queryBuilder.InDatabase(query, database)
 	if query == "" {
-		return mcp.NewToolResultError(ErrFunctionsNotSupported.Error()), nil
+		return toolErrorResult(ErrFunctionsNotSupported), nil
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, ShortQueryTimeout)
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
 	defer cancel()
 
 	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrListingFunctions, err).Error()), nil
+		return s.dbErrorResult(ErrListingFunctions, err), nil
 	}
 	defer rows.Close()
 
@@ -121,69 +101,49 @@ func (s *DbMCPServer) handleListFunctions(ctx context.Context, request mcp.CallT
 			"count":     len(functions),
 		},
 		"filter": map[string]interface{}{
+			"database":    database,
 			"schema":      schema,
 			"type":        funcType,
 			"name_filter": nameFilter,
 		},
 	}
 
-	jsonData, err := json.MarshalIndent(response, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
-	}
+	return jsonToolResult(response), nil
+}
 
-	return mcp.NewToolResultText(string(jsonData)), nil
+// getFunctionCodeArgs are the arguments of get_function_code
+type getFunctionCodeArgs struct {
+	FunctionName string `json:"function_name" jsonschema_description:"Function name"`
+	Schema       string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
 }
 
 func (s *DbMCPServer) toolGetFunctionCode() (mcp.Tool, server.ToolHandlerFunc) {
-	return mcp.Tool{
-		Name:        "get_function_code",
-		Description: "Returns the full source code of a function",
-		InputSchema: mcp.ToolInputSchema{
-			Type: "object",
-			Properties: map[string]interface{}{
-				"function_name": map[string]interface{}{
-					"type":        "string",
-					"description": "Function name",
-				},
-				"schema": map[string]interface{}{
-					"type":        "string",
-					"description": "Schema name (optional)",
-				},
-			},
-			Required: []string{"function_name"},
-		},
-	}, s.handleGetFunctionCode
+	return newTypedTool("get_function_code", "Returns the full source code of a function", s.handleGetFunctionCode)
 }
 
-func (s *DbMCPServer) handleGetFunctionCode(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *DbMCPServer) handleGetFunctionCode(ctx context.Context, request mcp.CallToolRequest, args getFunctionCodeArgs) (*mcp.CallToolResult, error) {
 	if err := s.requireConnection(); err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
 	if !s.queryBuilder.SupportsFunctions() {
-		return mcp.NewToolResultError(ErrFunctionsNotSupported.Error()), nil
+		return toolErrorResult(ErrFunctionsNotSupported), nil
 	}
 
-	args, ok := getArgs(request.Params.Arguments)
-	if !ok {
-		return mcp.NewToolResultError(ErrInvalidArguments.Error()), nil
-	}
-
-	functionName, ok := getStringArg(args, "function_name")
-	if !ok || !isValidIdentifier(functionName) {
-		return mcp.NewToolResultError(ErrInvalidFunctionName.Error()), nil
+	functionName := args.FunctionName
+	if !isValidIdentifier(functionName) {
+		return toolErrorResult(ErrInvalidFunctionName), nil
 	}
 
 	defaultSchema := getDefaultSchema(s.queryBuilder.GetDriver())
-	schema, err := getValidSchema(args, defaultSchema)
+	schema, err := getValidSchema(args.Schema, defaultSchema)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
 	query, queryArgs := s.queryBuilder.GetFunctionCodeQuery(schema, functionName)
 
-	ctx, cancel := context.WithTimeout(ctx, ShortQueryTimeout)
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
 	defer cancel()
 
 	// For Oracle, we need to collect all lines
@@ -194,14 +154,14 @@ func (s *DbMCPServer) handleGetFunctionCode(ctx context.Context, request mcp.Cal
 	var definition sql.NullString
 	err = s.db.QueryRowContext(ctx, query, queryArgs...).Scan(&definition)
 	if err == sql.ErrNoRows {
-		return mcp.NewToolResultError(ErrFunctionNotFound.Error()), nil
+		return toolErrorResult(ErrFunctionNotFound), nil
 	}
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrFetchingCode, err).Error()), nil
+		return s.dbErrorResult(ErrFetchingCode, err), nil
 	}
 
 	if !definition.Valid || definition.String == "" {
-		return mcp.NewToolResultError(ErrCodeNotAvailable.Error()), nil
+		return toolErrorResult(ErrCodeNotAvailable), nil
 	}
 
 	response := map[string]interface{}{
@@ -210,10 +170,105 @@ func (s *DbMCPServer) handleGetFunctionCode(ctx context.Context, request mcp.Cal
 		"definition": definition.String,
 	}
 
-	jsonData, err := json.MarshalIndent(response, "", "  ")
+	return jsonToolResult(response), nil
+}
+
+// functionSignature is one overload of a function with its parameters and return type
+type functionSignature struct {
+	Signature  string              `json:"signature"`
+	ReturnType string              `json:"return_type,omitempty"`
+	Parameters []functionParameter `json:"parameters"`
+}
+
+// functionParameter is a parameter of a function
+type functionParameter struct {
+	Position  int    `json:"position"`
+	Name      string `json:"name,omitempty"`
+	DataType  string `json:"data_type"`
+	Direction string `json:"direction"`
+	Default   string `json:"default,omitempty"`
+}
+
+// getFunctionParametersArgs are the arguments of get_function_parameters
+type getFunctionParametersArgs struct {
+	FunctionName string `json:"function_name" jsonschema_description:"Function name"`
+	Schema       string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+}
+
+func (s *DbMCPServer) toolGetFunctionParameters() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("get_function_parameters", "Returns the parameters (name, type, direction, default) and return type of a function, one entry per overload. SQL Server does not record defaults of T-SQL parameters", s.handleGetFunctionParameters)
+}
+
+func (s *DbMCPServer) handleGetFunctionParameters(ctx context.Context, request mcp.CallToolRequest, args getFunctionParametersArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	functionName := args.FunctionName
+	if !isValidIdentifier(functionName) {
+		return toolErrorResult(ErrInvalidFunctionName), nil
+	}
+
+	defaultSchema := getDefaultSchema(s.queryBuilder.GetDriver())
+	schema, err := getValidSchema(args.Schema, defaultSchema)
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	query, queryArgs, ok := s.queryBuilder.GetFunctionParametersQuery(schema, functionName)
+	if !ok {
+		return toolErrorResult(ErrFunctionsNotSupported), nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
 	if err != nil {
-		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
+		return s.dbErrorResult(ErrFetchingParameters, err), nil
+	}
+	defer rows.Close()
+
+	var overloads []*functionSignature
+	for rows.Next() {
+		var signature, dataType, direction string
+		var position int
+		var name, defaultValue sql.NullString
+		if err = rows.Scan(&signature, &position, &name, &dataType, &direction, &defaultValue); err != nil {
+			continue
+		}
+
+		if len(overloads) == 0 || overloads[len(overloads)-1].Signature != signature {
+			overloads = append(overloads, &functionSignature{Signature: signature, Parameters: []functionParameter{}})
+		}
+		current := overloads[len(overloads)-1]
+
+		if direction == "RETURN" {
+			current.ReturnType = dataType
+			continue
+		}
+		current.Parameters = append(current.Parameters, functionParameter{
+			Position:  position,
+			Name:      name.String,
+			DataType:  dataType,
+			Direction: direction,
+			Default:   defaultValue.String,
+		})
+	}
+	if err = rows.Err(); err != nil {
+		return s.dbErrorResult(ErrFetchingParameters, err), nil
+	}
+
+	if len(overloads) == 0 {
+		return toolErrorResult(ErrFunctionNotFound), nil
+	}
+
+	response := map[string]interface{}{
+		"schema":    schema,
+		"name":      functionName,
+		"overloads": overloads,
+		"count":     len(overloads),
 	}
 
-	return mcp.NewToolResultText(string(jsonData)), nil
+	return jsonToolResult(response), nil
 }
@@ -0,0 +1,171 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"math"
+	"math/big"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// identityStatus is an identity column with how much of its range is used. Values are
+// JSON numbers kept exact beyond int64 (MySQL unsigned BIGINT, DECIMAL and NUMBER identities).
+type identityStatus struct {
+	Schema          string      `json:"schema"`
+	Table           string      `json:"table"`
+	Column          string      `json:"column"`
+	DataType        string      `json:"data_type"`
+	Seed            json.Number `json:"seed,omitempty"`
+	Increment       json.Number `json:"increment"`
+	CurrentValue    json.Number `json:"current_value,omitempty"`
+	MinValue        json.Number `json:"min_value"`
+	MaxValue        json.Number `json:"max_value"`
+	RemainingValues json.Number `json:"remaining_values"`
+	UsedPercent     float64     `json:"used_percent"`
+}
+
+// getIdentityStatusArgs are the arguments of get_identity_status
+type getIdentityStatusArgs struct {
+	Schema    string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	TableName string `json:"table_name,omitempty" jsonschema_description:"Filter by table name (optional)"`
+}
+
+func (s *DbMCPServer) toolGetIdentityStatus() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("get_identity_status", "Returns the identity and auto-increment columns with their seed, increment, current value and the values left before the column type or sequence overflows, most used first. Answers whether an INT identity is about to run out", s.handleGetIdentityStatus)
+}
+
+func (s *DbMCPServer) handleGetIdentityStatus(ctx context.Context, request mcp.CallToolRequest, args getIdentityStatusArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	schema, err := getValidSchema(args.Schema, "")
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	tableName := args.TableName
+	if tableName != "" && !isValidIdentifier(tableName) {
+		return toolErrorResult(ErrInvalidTableName), nil
+	}
+
+	query, queryArgs, ok := s.queryBuilder.IdentityStatusQuery(schema, tableName)
+	if !ok {
+		return toolErrorResult(ErrFeatureNotSupported), nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return s.dbErrorResult(ErrRetrievingIdentityStatus, err), nil
+	}
+	defer rows.Close()
+
+	columns := []identityStatus{}
+	for rows.Next() {
+		var columnSchema, table, column, dataType string
+		var seed, increment, current, minValue, maxValue sql.NullString
+		if err = rows.Scan(&columnSchema, &table, &column, &dataType, &seed, &increment, &current, &minValue, &maxValue); err != nil {
+			continue
+		}
+		status, ok := newIdentityStatus(seed.String, increment.String, current.String, minValue.String, maxValue.String)
+		if !ok {
+			continue
+		}
+		status.Schema = columnSchema
+		status.Table = table
+		status.Column = column
+		status.DataType = dataType
+		columns = append(columns, status)
+	}
+	if err = rows.Err(); err != nil {
+		return s.dbErrorResult(ErrRetrievingIdentityStatus, err), nil
+	}
+
+	// Rows come by schema, table and column; the stable sort keeps that order between equals
+	sort.SliceStable(columns, func(i, j int) bool {
+		return columns[i].UsedPercent > columns[j].UsedPercent
+	})
+
+	response := map[string]interface{}{
+		"columns": columns,
+		"count":   len(columns),
+		"filter": map[string]interface{}{
+			"schema": schema,
+			"table":  tableName,
+		},
+	}
+
+	return jsonToolResult(response), nil
+}
+
+// newIdentityStatus returns the values of an identity column with the values left before
+// it reaches the end of its range in the direction of the increment, and the percentage of
+// the range from the seed already used. A column without a current value has used nothing.
+func newIdentityStatus(seed, increment, current, minValue, maxValue string) (identityStatus, bool) {
+	inc, ok := new(big.Int).SetString(increment, 10)
+	if !ok || inc.Sign() == 0 {
+		return identityStatus{}, false
+	}
+	lowest, ok := new(big.Int).SetString(minValue, 10)
+	if !ok {
+		return identityStatus{}, false
+	}
+	highest, ok := new(big.Int).SetString(maxValue, 10)
+	if !ok {
+		return identityStatus{}, false
+	}
+
+	limit := highest
+	if inc.Sign() < 0 {
+		limit = lowest
+	}
+	start, ok := new(big.Int).SetString(seed, 10)
+	if !ok {
+		start = new(big.Int).Set(lowest)
+		if inc.Sign() < 0 {
+			start = new(big.Int).Set(highest)
+		}
+	}
+
+	status := identityStatus{
+		Increment: json.Number(inc.String()),
+		MinValue:  json.Number(lowest.String()),
+		MaxValue:  json.Number(highest.String()),
+	}
+	if seed != "" {
+		status.Seed = json.Number(start.String())
+	}
+
+	// Before the first insert the next value is the seed
+	next := start
+	if value, ok := new(big.Int).SetString(current, 10); ok {
+		status.CurrentValue = json.Number(value.String())
+		next = new(big.Int).Add(value, inc)
+	}
+
+	// Values left: next, next+inc, ... up to the limit
+	remaining := new(big.Int).Sub(limit, next)
+	remaining.Quo(remaining, inc)
+	remaining.Add(remaining, big.NewInt(1))
+	if remaining.Sign() < 0 {
+		remaining.SetInt64(0)
+	}
+	status.RemainingValues = json.Number(remaining.String())
+
+	rangeSize := new(big.Float).SetInt(new(big.Int).Sub(limit, start))
+	if rangeSize.Sign() != 0 {
+		used := new(big.Float).SetInt(new(big.Int).Sub(next, start))
+		percent, _ := new(big.Float).Quo(used, rangeSize).Float64()
+		// Rounded down, so 100 means no value is left
+		status.UsedPercent = math.Floor(math.Min(math.Max(percent, 0), 1)*10000) / 100
+	}
+
+	return status, true
+}
@@ -0,0 +1,135 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// scheduledJob is a job run by a scheduler of the database server
+type scheduledJob struct {
+	Scheduler      string `json:"scheduler"`
+	Name           string `json:"name"`
+	Enabled        bool   `json:"enabled"`
+	Schedule       string `json:"schedule,omitempty"`
+	Command        string `json:"command,omitempty"`
+	LastRunAt      string `json:"last_run_at,omitempty"`
+	LastRunStatus  string `json:"last_run_status,omitempty"`
+	LastRunMessage string `json:"last_run_message,omitempty"`
+	NextRunAt      string `json:"next_run_at,omitempty"`
+	Database       string `json:"database,omitempty"`
+	Owner          string `json:"owner,omitempty"`
+	Description    string `json:"description,omitempty"`
+}
+
+// listScheduledJobsArgs are the arguments of list_scheduled_jobs
+type listScheduledJobsArgs struct {
+	NameFilter string `json:"name_filter,omitempty" jsonschema_description:"Filter by job name (optional)"`
+}
+
+func (s *DbMCPServer) toolListScheduledJobs() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("list_scheduled_jobs", "Lists the scheduled jobs of SQL Server Agent, pg_cron, pgAgent, MySQL events and Oracle Scheduler with their schedule, command text and last run status. Use to find out what changes data at night or on a timer", s.handleListScheduledJobs)
+}
+
+func (s *DbMCPServer) handleListScheduledJobs(ctx context.Context, request mcp.CallToolRequest, args listScheduledJobsArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	nameFilter := args.NameFilter
+
+	schedulersQuery, ok := s.queryBuilder.JobSchedulersQuery()
+	if !ok {
+		return toolErrorResult(ErrFeatureNotSupported), nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	schedulers, err := s.fetchJobSchedulers(ctx, schedulersQuery)
+	if err != nil {
+		return s.dbErrorResult(ErrListingScheduledJobs, err), nil
+	}
+
+	jobs := []scheduledJob{}
+	for _, scheduler := range schedulers {
+		query, queryArgs, ok := s.queryBuilder.ListScheduledJobsQuery(scheduler, nameFilter)
+		if !ok {
+			continue
+		}
+		schedulerJobs, err := s.fetchScheduledJobs(ctx, scheduler, query, queryArgs)
+		if err != nil {
+			return s.dbErrorResult(ErrListingScheduledJobs, err), nil
+		}
+		jobs = append(jobs, schedulerJobs...)
+	}
+
+	response := map[string]interface{}{
+		"jobs":       jobs,
+		"count":      len(jobs),
+		"schedulers": schedulers,
+	}
+	if len(schedulers) == 0 {
+		response["message"] = translate("No job scheduler is installed or readable on this connection")
+	}
+	if nameFilter != "" {
+		response["name_filter"] = nameFilter
+	}
+
+	return jsonToolResult(response), nil
+}
+
+// fetchJobSchedulers returns the job schedulers installed on the connection
+func (s *DbMCPServer) fetchJobSchedulers(ctx context.Context, query string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedulers := []string{}
+	for rows.Next() {
+		var scheduler string
+		if err := rows.Scan(&scheduler); err != nil {
+			continue
+		}
+		schedulers = append(schedulers, scheduler)
+	}
+	return schedulers, rows.Err()
+}
+
+// fetchScheduledJobs returns the jobs of a scheduler
+func (s *DbMCPServer) fetchScheduledJobs(ctx context.Context, scheduler, query string, args []interface{}) ([]scheduledJob, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []scheduledJob
+	for rows.Next() {
+		var name string
+		var enabled int
+		var schedule, command, lastRunAt, lastRunStatus, lastRunMessage, nextRunAt, database, owner, description sql.NullString
+		if err := rows.Scan(&name, &enabled, &schedule, &command, &lastRunAt, &lastRunStatus, &lastRunMessage, &nextRunAt, &database, &owner, &description); err != nil {
+			continue
+		}
+		jobs = append(jobs, scheduledJob{
+			Scheduler:      scheduler,
+			Name:           name,
+			Enabled:        enabled == 1,
+			Schedule:       schedule.String,
+			Command:        command.String,
+			LastRunAt:      lastRunAt.String,
+			LastRunStatus:  lastRunStatus.String,
+			LastRunMessage: lastRunMessage.String,
+			NextRunAt:      nextRunAt.String,
+			Database:       database.String,
+			Owner:          owner.String,
+			Description:    description.String,
+		})
+	}
+	return jobs, rows.Err()
+}
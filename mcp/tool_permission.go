@@ -0,0 +1,322 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// objectPermission is a privilege granted (or denied) to a user or role on an object,
+// one of its columns or its schema
+type objectPermission struct {
+	Grantee     string `json:"grantee"`
+	GranteeType string `json:"grantee_type"`
+	Privilege   string `json:"privilege"`
+	State       string `json:"state"`
+	Grantor     string `json:"grantor,omitempty"`
+	Column      string `json:"column,omitempty"`
+	Scope       string `json:"scope"`
+}
+
+// listObjectPermissionsArgs are the arguments of list_object_permissions
+type listObjectPermissionsArgs struct {
+	ObjectName string `json:"object_name" jsonschema_description:"Table, view, procedure or function name"`
+	Schema     string `json:"schema,omitempty" jsonschema_description:"Schema name (optional, uses the default schema)"`
+	Grantee    string `json:"grantee,omitempty" jsonschema_description:"Only return permissions of this user or role (optional; on MySQL the user name without host)"`
+}
+
+func (s *DbMCPServer) toolListObjectPermissions() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("list_object_permissions", "Lists which users and roles have SELECT, INSERT, UPDATE, DELETE, EXECUTE, etc. on a table, view or routine, including column-level and schema-level grants and denies. Use to audit access to an object", s.handleListObjectPermissions)
+}
+
+func (s *DbMCPServer) handleListObjectPermissions(ctx context.Context, request mcp.CallToolRequest, args listObjectPermissionsArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	objectName := args.ObjectName
+	if !isValidIdentifier(objectName) {
+		return toolErrorResult(ErrInvalidObjectName), nil
+	}
+
+	defaultSchema := getDefaultSchema(s.queryBuilder.GetDriver())
+	schema, err := getValidSchema(args.Schema, defaultSchema)
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	// The grantee is bound as a parameter, so names such as DOMAIN\user are allowed
+	grantee := args.Grantee
+	if len(grantee) > 128 {
+		return toolErrorResult(ErrInvalidGrantee), nil
+	}
+
+	query, queryArgs, ok := s.queryBuilder.ListObjectPermissionsQuery(schema, objectName, grantee)
+	if !ok {
+		return toolErrorResult(ErrFeatureNotSupported), nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return s.dbErrorResult(ErrListingPermissions, err), nil
+	}
+	defer rows.Close()
+
+	permissions := []objectPermission{}
+	for rows.Next() {
+		var p objectPermission
+		var grantor, column sql.NullString
+		if err = rows.Scan(&p.Grantee, &p.GranteeType, &p.Privilege, &p.State, &grantor, &column, &p.Scope); err != nil {
+			continue
+		}
+		p.Grantor = grantor.String
+		p.Column = column.String
+		permissions = append(permissions, p)
+	}
+	if err = rows.Err(); err != nil {
+		return s.dbErrorResult(ErrListingPermissions, err), nil
+	}
+
+	response := map[string]interface{}{
+		"schema":      schema,
+		"name":        objectName,
+		"permissions": permissions,
+		"count":       len(permissions),
+	}
+
+	return jsonToolResult(response), nil
+}
+
+// rlsPolicy is a row-level security policy of a table. Enforced is false while the table
+// has row-level security (or the policy) disabled.
+type rlsPolicy struct {
+	Schema          string   `json:"schema"`
+	Table           string   `json:"table"`
+	Policy          string   `json:"policy"`
+	Enforced        bool     `json:"enforced"`
+	Forced          *bool    `json:"forced,omitempty"`
+	Kind            string   `json:"kind,omitempty"`
+	Command         string   `json:"command,omitempty"`
+	Roles           []string `json:"roles,omitempty"`
+	UsingExpression string   `json:"using_expression,omitempty"`
+	CheckExpression string   `json:"check_expression,omitempty"`
+}
+
+// listRLSPoliciesArgs are the arguments of list_rls_policies
+type listRLSPoliciesArgs struct {
+	TableName string `json:"table_name,omitempty" jsonschema_description:"Filter by table name (optional)"`
+	Schema    string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+}
+
+func (s *DbMCPServer) toolListRLSPolicies() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("list_rls_policies", "Lists the row-level security policies of tables with their filter expressions and the roles they apply to (Postgres policies, SQL Server security policies). Row-level security silently hides rows, so check it when a query returns fewer rows than expected", s.handleListRLSPolicies)
+}
+
+func (s *DbMCPServer) handleListRLSPolicies(ctx context.Context, request mcp.CallToolRequest, args listRLSPoliciesArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	schema, err := getValidSchema(args.Schema, "")
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	tableName := args.TableName
+	if tableName != "" && !isValidIdentifier(tableName) {
+		return toolErrorResult(ErrInvalidTableName), nil
+	}
+
+	query, queryArgs, ok := s.queryBuilder.ListRLSPoliciesQuery(schema, tableName)
+	if !ok {
+		return toolErrorResult(ErrFeatureNotSupported), nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return s.dbErrorResult(ErrListingRLSPolicies, err), nil
+	}
+	defer rows.Close()
+
+	policies := []rlsPolicy{}
+	// Tables with row-level security enabled and no policy return no rows to anyone
+	// subject to it
+	withoutPolicies := []string{}
+	for rows.Next() {
+		var p rlsPolicy
+		var policy, kind, command, roles, using, check sql.NullString
+		var enforced int
+		var forced sql.NullInt64
+		if err = rows.Scan(&p.Schema, &p.Table, &policy, &enforced, &forced, &kind, &command, &roles, &using, &check); err != nil {
+			continue
+		}
+		if !policy.Valid {
+			withoutPolicies = append(withoutPolicies, p.Schema+"."+p.Table)
+			continue
+		}
+		p.Policy = policy.String
+		p.Enforced = enforced == 1
+		if forced.Valid {
+			isForced := forced.Int64 == 1
+			p.Forced = &isForced
+		}
+		p.Kind = kind.String
+		p.Command = command.String
+		if roles.Valid {
+			p.Roles = strings.Split(roles.String, ", ")
+		}
+		p.UsingExpression = using.String
+		p.CheckExpression = check.String
+		policies = append(policies, p)
+	}
+	if err = rows.Err(); err != nil {
+		return s.dbErrorResult(ErrListingRLSPolicies, err), nil
+	}
+
+	response := map[string]interface{}{
+		"policies": policies,
+		"count":    len(policies),
+		"filter": map[string]interface{}{
+			"schema": schema,
+			"table":  tableName,
+		},
+	}
+	if len(withoutPolicies) > 0 {
+		response["tables_without_policies"] = withoutPolicies
+		response["message"] = translate("Tables with row-level security enabled and no policy return no rows, except to their owner and to roles that bypass row-level security")
+	}
+
+	return jsonToolResult(response), nil
+}
+
+// databasePrincipal is a database user or role with the roles it is a member of
+type databasePrincipal struct {
+	Name          string   `json:"name"`
+	Type          string   `json:"type"`
+	IsRole        bool     `json:"is_role"`
+	IsSystem      bool     `json:"is_system,omitempty"`
+	DefaultSchema string   `json:"default_schema,omitempty"`
+	Created       string   `json:"created,omitempty"`
+	MemberOf      []string `json:"member_of"`
+	Members       []string `json:"members,omitempty"`
+}
+
+// listUsersAndRolesArgs are the arguments of list_users_and_roles
+type listUsersAndRolesArgs struct {
+	NameFilter    string `json:"name_filter,omitempty" jsonschema_description:"Filter by user or role name (optional)"`
+	IncludeSystem bool   `json:"include_system,omitempty" jsonschema_description:"Include built-in principals such as fixed roles and predefined pg_* roles (default: false)"`
+}
+
+func (s *DbMCPServer) toolListUsersAndRoles() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("list_users_and_roles", "Lists database users and roles with their role memberships. Combine with list_object_permissions to review who can access what", s.handleListUsersAndRoles)
+}
+
+func (s *DbMCPServer) handleListUsersAndRoles(ctx context.Context, request mcp.CallToolRequest, args listUsersAndRolesArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	nameFilter := args.NameFilter
+	includeSystem := args.IncludeSystem
+
+	query, queryArgs, ok := s.queryBuilder.ListPrincipalsQuery(nameFilter)
+	if !ok {
+		return toolErrorResult(ErrFeatureNotSupported), nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return s.dbErrorResult(ErrListingPrincipals, err), nil
+	}
+	defer rows.Close()
+
+	principals := []*databasePrincipal{}
+	byName := make(map[string]*databasePrincipal)
+	for rows.Next() {
+		var name, principalType string
+		var isRole, isSystem int
+		var defaultSchema sql.NullString
+		var created sql.NullTime
+		if err = rows.Scan(&name, &principalType, &isRole, &isSystem, &defaultSchema, &created); err != nil {
+			continue
+		}
+		if isSystem == 1 && !includeSystem {
+			continue
+		}
+
+		principal := &databasePrincipal{
+			Name:          name,
+			Type:          principalType,
+			IsRole:        isRole == 1,
+			IsSystem:      isSystem == 1,
+			DefaultSchema: defaultSchema.String,
+			MemberOf:      []string{},
+		}
+		if created.Valid {
+			principal.Created = created.Time.Format("2006-01-02 15:04:05")
+		}
+		principals = append(principals, principal)
+		byName[name] = principal
+	}
+	if err = rows.Err(); err != nil {
+		return s.dbErrorResult(ErrListingPrincipals, err), nil
+	}
+
+	// Memberships are attached to the principals listed above
+	if membersQuery, ok := s.queryBuilder.ListRoleMembersQuery(); ok {
+		memberRows, err := s.db.QueryContext(ctx, membersQuery)
+		if err != nil {
+			return s.dbErrorResult(ErrListingPrincipals, err), nil
+		}
+		defer memberRows.Close()
+
+		for memberRows.Next() {
+			var roleName, memberName string
+			if err = memberRows.Scan(&roleName, &memberName); err != nil {
+				continue
+			}
+			if member, ok := byName[memberName]; ok {
+				member.MemberOf = append(member.MemberOf, roleName)
+			}
+			if role, ok := byName[roleName]; ok {
+				role.Members = append(role.Members, memberName)
+			}
+		}
+		if err = memberRows.Err(); err != nil {
+			return s.dbErrorResult(ErrListingPrincipals, err), nil
+		}
+	}
+
+	users, roles := 0, 0
+	for _, principal := range principals {
+		if principal.IsRole {
+			roles++
+		} else {
+			users++
+		}
+	}
+
+	response := map[string]interface{}{
+		"principals": principals,
+		"count":      len(principals),
+		"users":      users,
+		"roles":      roles,
+	}
+	if nameFilter != "" {
+		response["name_filter"] = nameFilter
+	}
+
+	return jsonToolResult(response), nil
+}
@@ -3,75 +3,60 @@ package mcp
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// listProceduresArgs are the arguments of list_procedures
+type listProceduresArgs struct {
+	Schema     string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	NameFilter string `json:"name_filter,omitempty" jsonschema_description:"Filter by procedure name (optional)"`
+	crossDatabaseArgs
+	pageArgs
+}
+
 func (s *DbMCPServer) toolListProcedures() (mcp.Tool, server.ToolHandlerFunc) {
-	return mcp.Tool{
-		Name:        "list_procedures",
-		Description: "List database stored procedures with pagination",
-		InputSchema: mcp.ToolInputSchema{
-			Type: "object",
-			Properties: map[string]interface{}{
-				"schema": map[string]interface{}{
-					"type":        "string",
-					"description": "Schema name (optional)",
-				},
-				"name_filter": map[string]interface{}{
-					"type":        "string",
-					"description": "Filter by procedure name (optional)",
-				},
-				"page": map[string]interface{}{
-					"type":        "number",
-					"description": "Page number (default: 1)",
-				},
-				"page_size": map[string]interface{}{
-					"type":        "number",
-					"description": "Items per page (default: 100, maximum: 500)",
-				},
-			},
-		},
-	}, s.handleListProcedures
+	return newTypedTool("list_procedures", "List database stored procedures with pagination", s.handleListProcedures)
 }
 
-func (s *DbMCPServer) handleListProcedures(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *DbMCPServer) handleListProcedures(ctx context.Context, request mcp.CallToolRequest, args listProceduresArgs) (*mcp.CallToolResult, error) {
 	if err := s.requireConnection(); err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
 	if !s.queryBuilder.SupportsStoredProcedures() {
-		return mcp.NewToolResultError(ErrStoredProceduresNotSupported.Error()), nil
+		return toolErrorResult(ErrStoredProceduresNotSupported), nil
 	}
 
-	args, ok := getArgs(request.Params.Arguments)
-	if !ok {
-		return mcp.NewToolResultError(ErrInvalidArguments.Error()), nil
+	schema, err := getValidSchema(args.Schema, "")
+	if err != nil {
+		return toolErrorResult(err), nil
 	}
 
-	schema, err := getValidSchema(args, "")
+	database, schema, err := getTargetDatabase(args.Database, s.queryBuilder, schema)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
-	nameFilter, _ := getStringArg(args, "name_filter")
-	pagination := GetPaginationParams(args, DefaultPageSize, MaxPageSize)
+	nameFilter := args.NameFilter
+	pagination := args.pagination()
 
 	query, queryArgs := s.queryBuilder.ListProceduresQuery(schema, nameFilter, pagination.PageSize, pagination.Offset)
+	query = s.queryBuilder.InDatabase(query, database)
 	if query == "" {
-		return mcp.NewToolResultError(ErrStoredProceduresNotSupported.Error()), nil
+		return toolErrorResult(ErrStoredProceduresNotSupported), nil
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, ShortQueryTimeout)
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
 	defer cancel()
 
 	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrListingProcedures, err).Error()), nil
+		return s.dbErrorResult(ErrListingProcedures, err), nil
 	}
 	defer rows.Close()
 
@@ -105,68 +90,48 @@ func (s *DbMCPServer) handleListProcedures(ctx context.Context, request mcp.Call
 			"count":     len(procedures),
 		},
 		"filter": map[string]interface{}{
+			"database":    database,
 			"schema":      schema,
 			"name_filter": nameFilter,
 		},
 	}
 
-	jsonData, err := json.MarshalIndent(response, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
-	}
+	return jsonToolResult(response), nil
+}
 
-	return mcp.NewToolResultText(string(jsonData)), nil
+// getProcedureCodeArgs are the arguments of get_procedure_code
+type getProcedureCodeArgs struct {
+	ProcedureName string `json:"procedure_name" jsonschema_description:"Stored procedure name"`
+	Schema        string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
 }
 
 func (s *DbMCPServer) toolGetProcedureCode() (mcp.Tool, server.ToolHandlerFunc) {
-	return mcp.Tool{
-		Name:        "get_procedure_code",
-		Description: "Returns the full source code of a stored procedure",
-		InputSchema: mcp.ToolInputSchema{
-			Type: "object",
-			Properties: map[string]interface{}{
-				"procedure_name": map[string]interface{}{
-					"type":        "string",
-					"description": "Stored procedure name",
-				},
-				"schema": map[string]interface{}{
-					"type":        "string",
-					"description": "Schema name (optional)",
-				},
-			},
-			Required: []string{"procedure_name"},
-		},
-	}, s.handleGetProcedureCode
+	return newTypedTool("get_procedure_code", "Returns the full source code of a stored procedure", s.handleGetProcedureCode)
 }
 
-func (s *DbMCPServer) handleGetProcedureCode(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *DbMCPServer) handleGetProcedureCode(ctx context.Context, request mcp.CallToolRequest, args getProcedureCodeArgs) (*mcp.CallToolResult, error) {
 	if err := s.requireConnection(); err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
 	if !s.queryBuilder.SupportsStoredProcedures() {
-		return mcp.NewToolResultError(ErrStoredProceduresNotSupported.Error()), nil
+		return toolErrorResult(ErrStoredProceduresNotSupported), nil
 	}
 
-	args, ok := getArgs(request.Params.Arguments)
-	if !ok {
-		return mcp.NewToolResultError(ErrInvalidArguments.Error()), nil
-	}
-
-	procedureName, ok := getStringArg(args, "procedure_name")
-	if !ok || !isValidIdentifier(procedureName) {
-		return mcp.NewToolResultError(ErrInvalidProcedureName.Error()), nil
+	procedureName := args.ProcedureName
+	if !isValidIdentifier(procedureName) {
+		return toolErrorResult(ErrInvalidProcedureName), nil
 	}
 
 	defaultSchema := getDefaultSchema(s.queryBuilder.GetDriver())
-	schema, err := getValidSchema(args, defaultSchema)
+	schema, err := getValidSchema(args.Schema, defaultSchema)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
 	query, queryArgs := s.queryBuilder.GetProcedureCodeQuery(schema, procedureName)
 
-	ctx, cancel := context.WithTimeout(ctx, ShortQueryTimeout)
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
 	defer cancel()
 
 	// For Oracle, we need to collect all lines
@@ -177,14 +142,14 @@ func (s *DbMCPServer) handleGetProcedureCode(ctx context.Context, request mcp.Ca
 	var definition sql.NullString
 	err = s.db.QueryRowContext(ctx, query, queryArgs...).Scan(&definition)
 	if err == sql.ErrNoRows {
-		return mcp.NewToolResultError(ErrProcedureNotFound.Error()), nil
+		return toolErrorResult(ErrProcedureNotFound), nil
 	}
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrFetchingCode, err).Error()), nil
+		return s.dbErrorResult(ErrFetchingCode, err), nil
 	}
 
 	if !definition.Valid || definition.String == "" {
-		return mcp.NewToolResultError(ErrCodeNotAvailable.Error()), nil
+		return toolErrorResult(ErrCodeNotAvailable), nil
 	}
 
 	response := map[string]interface{}{
@@ -193,70 +158,46 @@ func (s *DbMCPServer) handleGetProcedureCode(ctx context.Context, request mcp.Ca
 		"definition": definition.String,
 	}
 
-	jsonData, err := json.MarshalIndent(response, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
-	}
+	return jsonToolResult(response), nil
+}
 
-	return mcp.NewToolResultText(string(jsonData)), nil
+// executeProcedureArgs are the arguments of execute_procedure
+type executeProcedureArgs struct {
+	ProcedureName string                 `json:"procedure_name" jsonschema_description:"Stored procedure name"`
+	Parameters    map[string]interface{} `json:"parameters,omitempty" jsonschema_description:"Procedure parameters as a JSON object"`
+	Schema        string                 `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
 }
 
 func (s *DbMCPServer) toolExecuteProcedure() (mcp.Tool, server.ToolHandlerFunc) {
-	return mcp.Tool{
-		Name:        "execute_procedure",
-		Description: "Execute a stored procedure with parameters",
-		InputSchema: mcp.ToolInputSchema{
-			Type: "object",
-			Properties: map[string]interface{}{
-				"procedure_name": map[string]interface{}{
-					"type":        "string",
-					"description": "Stored procedure name",
-				},
-				"parameters": map[string]interface{}{
-					"type":        "object",
-					"description": "Procedure parameters as a JSON object",
-				},
-				"schema": map[string]interface{}{
-					"type":        "string",
-					"description": "Schema name (optional)",
-				},
-			},
-			Required: []string{"procedure_name"},
-		},
-	}, s.handleExecuteProcedure
+	return newTypedTool("execute_procedure", "Execute a stored procedure with parameters", s.handleExecuteProcedure)
 }
 
-func (s *DbMCPServer) handleExecuteProcedure(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *DbMCPServer) handleExecuteProcedure(ctx context.Context, request mcp.CallToolRequest, args executeProcedureArgs) (*mcp.CallToolResult, error) {
 	if err := s.requireConnection(); err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
 	if !s.queryBuilder.SupportsStoredProcedures() {
-		return mcp.NewToolResultError(ErrStoredProceduresNotSupported.Error()), nil
-	}
-
-	args, ok := getArgs(request.Params.Arguments)
-	if !ok {
-		return mcp.NewToolResultError(ErrInvalidArguments.Error()), nil
+		return toolErrorResult(ErrStoredProceduresNotSupported), nil
 	}
 
-	procedureName, ok := getStringArg(args, "procedure_name")
-	if !ok || !isValidIdentifier(procedureName) {
-		return mcp.NewToolResultError(ErrInvalidProcedureName.Error()), nil
+	procedureName := args.ProcedureName
+	if !isValidIdentifier(procedureName) {
+		return toolErrorResult(ErrInvalidProcedureName), nil
 	}
 
 	defaultSchema := getDefaultSchema(s.queryBuilder.GetDriver())
-	schema, err := getValidSchema(args, defaultSchema)
+	schema, err := getValidSchema(args.Schema, defaultSchema)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
-	userParams := make(map[string]interface{})
-	if p, ok := args["parameters"].(map[string]interface{}); ok {
-		userParams = p
+	userParams := args.Parameters
+	if userParams == nil {
+		userParams = make(map[string]interface{})
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, DefaultQueryTimeout)
+	ctx, cancel := withQueryTimeout(ctx, DefaultQueryTimeout)
 	defer cancel()
 
 	// Build and execute the procedure call based on driver
@@ -275,37 +216,48 @@ func (s *DbMCPServer) handleExecuteProcedure(ctx context.Context, request mcp.Ca
 	case DriverOracle:
 		execSQL, paramValues = s.buildOracleProcedureCall(qualifiedName, userParams)
 	default:
-		return mcp.NewToolResultError(ErrFeatureNotSupported.Error()), nil
+		return toolErrorResult(ErrFeatureNotSupported), nil
 	}
 
+	ctx, watch := s.watchdog.Watch(ctx, "execute_procedure", execSQL)
+	defer watch.Done()
+
 	resultRows, err := s.db.QueryContext(ctx, execSQL, paramValues...)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrExecutingProcedure, err).Error()), nil
+		return s.dbErrorResult(ErrExecutingProcedure, watch.Cause(err)), nil
 	}
 	defer resultRows.Close()
 
 	columns, err := resultRows.Columns()
 	if err != nil {
-		return mcp.NewToolResultText("Procedure executed successfully (no results)"), nil
+		return mcp.NewToolResultText(translate("Procedure executed successfully (no results)")), nil
 	}
 
-	var results []map[string]interface{}
-	for resultRows.Next() {
-		values := make([]interface{}, len(columns))
-		valuePtrs := make([]interface{}, len(columns))
-		for i := range values {
-			valuePtrs[i] = &values[i]
-		}
+	results := newResultSet(columns)
+	encoder := newResultEncoder(resultRows)
+	budget := s.newResultBudget()
+
+	values := make([]interface{}, len(columns))
+	valuePtrs := make([]interface{}, len(columns))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
 
+	for resultRows.Next() {
 		if err = resultRows.Scan(valuePtrs...); err != nil {
 			continue
 		}
 
-		row := make(map[string]interface{})
-		for i, col := range columns {
-			row[col] = formatValue(values[i])
+		encoder.encode(values)
+		if !budget.Add(values) {
+			break
 		}
-		results = append(results, row)
+		results.AppendRow(values)
+		watch.Row()
+	}
+
+	if err = watch.Err(); err != nil {
+		return toolErrorResult(err), nil
 	}
 
 	response := map[string]interface{}{
@@ -313,15 +265,22 @@ func (s *DbMCPServer) handleExecuteProcedure(ctx context.Context, request mcp.Ca
 		"procedure": procedureName,
 		"schema":    schema,
 		"results":   results,
-		"row_count": len(results),
+		"row_count": results.Len(),
 	}
+	budget.Annotate(response)
 
-	jsonData, err := json.MarshalIndent(response, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
-	}
+	return jsonToolResult(response), nil
+}
 
-	return mcp.NewToolResultText(string(jsonData)), nil
+// sortedParamNames returns the parameter names in alphabetical order, so the same
+// parameters always produce the same call
+func sortedParamNames(params map[string]interface{}) []string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 func (s *DbMCPServer) buildSQLServerProcedureCall(qualifiedName string, params map[string]interface{}) (string, []interface{}) {
@@ -329,9 +288,9 @@ func (s *DbMCPServer) buildSQLServerProcedureCall(qualifiedName string, params m
 	var paramValues []interface{}
 	i := 1
 
-	for name, value := range params {
+	for _, name := range sortedParamNames(params) {
 		paramPlaceholders = append(paramPlaceholders, fmt.Sprintf("@%s = @p%d", name, i))
-		paramValues = append(paramValues, value)
+		paramValues = append(paramValues, params[name])
 		i++
 	}
 
@@ -348,9 +307,9 @@ func (s *DbMCPServer) buildPostgresProcedureCall(qualifiedName string, params ma
 	var paramValues []interface{}
 	i := 1
 
-	for _, value := range params {
+	for _, name := range sortedParamNames(params) {
 		placeholders = append(placeholders, fmt.Sprintf("$%d", i))
-		paramValues = append(paramValues, value)
+		paramValues = append(paramValues, params[name])
 		i++
 	}
 
@@ -362,9 +321,9 @@ func (s *DbMCPServer) buildMySQLProcedureCall(qualifiedName string, params map[s
 	var placeholders []string
 	var paramValues []interface{}
 
-	for _, value := range params {
+	for _, name := range sortedParamNames(params) {
 		placeholders = append(placeholders, "?")
-		paramValues = append(paramValues, value)
+		paramValues = append(paramValues, params[name])
 	}
 
 	execSQL := fmt.Sprintf("CALL %s(%s)", qualifiedName, strings.Join(placeholders, ", "))
@@ -376,9 +335,9 @@ func (s *DbMCPServer) buildOracleProcedureCall(qualifiedName string, params map[
 	var paramValues []interface{}
 	i := 1
 
-	for _, value := range params {
+	for _, name := range sortedParamNames(params) {
 		placeholders = append(placeholders, fmt.Sprintf(":%d", i))
-		paramValues = append(paramValues, value)
+		paramValues = append(paramValues, params[name])
 		i++
 	}
 
@@ -390,7 +349,7 @@ func (s *DbMCPServer) buildOracleProcedureCall(qualifiedName string, params map[
 func (s *DbMCPServer) getOracleSourceCode(ctx context.Context, query string, args []interface{}, objectType string) (*mcp.CallToolResult, error) {
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrFetchingCode, err).Error()), nil
+		return s.dbErrorResult(ErrFetchingCode, err), nil
 	}
 	defer rows.Close()
 
@@ -404,17 +363,12 @@ func (s *DbMCPServer) getOracleSourceCode(ctx context.Context, query string, arg
 	}
 
 	if len(lines) == 0 {
-		return mcp.NewToolResultError(ErrCodeNotAvailable.Error()), nil
+		return toolErrorResult(ErrCodeNotAvailable), nil
 	}
 
 	response := map[string]interface{}{
 		"definition": strings.Join(lines, ""),
 	}
 
-	jsonData, err := json.MarshalIndent(response, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
-	}
-
-	return mcp.NewToolResultText(string(jsonData)), nil
+	return jsonToolResult(response), nil
 }
@@ -0,0 +1,244 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// profileColumnArgs are the arguments of profile_column
+type profileColumnArgs struct {
+	TableName  string `json:"table_name" jsonschema_description:"Table name"`
+	ColumnName string `json:"column_name" jsonschema_description:"Column name"`
+	Schema     string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	Top        int    `json:"top,omitempty" jsonschema_description:"Number of most frequent values to return (default: 10, maximum: 100)"`
+}
+
+func (s *DbMCPServer) toolProfileColumn() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("profile_column", "Profiles the values of a column in a single query: row count, null count, distinct count (approximate on Oracle), minimum, maximum, average text length and the most frequent values with their frequency. Use it to triage data quality without writing aggregate queries. It reads the whole table", s.handleProfileColumn)
+}
+
+func (s *DbMCPServer) handleProfileColumn(ctx context.Context, request mcp.CallToolRequest, args profileColumnArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	tableName := args.TableName
+	if !isValidIdentifier(tableName) {
+		return toolErrorResult(ErrInvalidTableName), nil
+	}
+	if !isValidIdentifier(args.ColumnName) {
+		return toolErrorResult(ErrInvalidColumnName), nil
+	}
+
+	defaultSchema := getDefaultSchema(s.queryBuilder.GetDriver())
+	schema, err := getValidSchema(args.Schema, defaultSchema)
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	top := args.Top
+	if top < 1 {
+		top = DefaultProfileTopValues
+	}
+	top = min(top, MaxProfileTopValues)
+
+	ctx, cancel := withQueryTimeout(ctx, DefaultQueryTimeout)
+	defer cancel()
+
+	if exists, err := s.tableExists(ctx, schema, tableName); err != nil {
+		return s.dbErrorResult(ErrCheckingTable, err), nil
+	} else if !exists {
+		return toolErrorResult(fmt.Errorf("%w: %s.%s", ErrTableNotFound, schema, tableName)), nil
+	}
+
+	columns, err := s.getTableColumns(ctx, schema, tableName)
+	if err != nil {
+		return s.dbErrorResult(ErrRetrievingColumns, err), nil
+	}
+	column, ok := tableColumn(columns, args.ColumnName)
+	if !ok {
+		return toolErrorResult(fmt.Errorf("%w: %s", ErrColumnNotExists, args.ColumnName)), nil
+	}
+
+	query, approximate := s.queryBuilder.ColumnProfileQuery(schema, tableName, column, top)
+	ctx, watch := s.watchdog.Watch(ctx, "profile_column", query)
+	defer watch.Done()
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return s.dbErrorResult(ErrProfilingColumn, watch.Cause(err)), nil
+	}
+	defer rows.Close()
+
+	// Counts are scanned as integers, values through the encoder of their column type
+	encoder := newResultEncoder(rows)
+	var rowCount, nullCount, distinctCount int64
+	var avgLength sql.NullFloat64
+	var minValue, maxValue, topValue interface{}
+	var frequency sql.NullInt64
+
+	profile := map[string]interface{}{}
+	topValues := []map[string]interface{}{}
+	for rows.Next() {
+		if err = rows.Scan(&rowCount, &nullCount, &distinctCount, &minValue, &maxValue, &avgLength, &topValue, &frequency); err != nil {
+			return toolErrorResult(ErrReadingRow), nil
+		}
+		watch.Row()
+		if len(profile) == 0 {
+			profile["row_count"] = rowCount
+			profile["null_count"] = nullCount
+			profile["null_percent"] = percentOf(nullCount, rowCount)
+			profile["distinct_count"] = distinctCount
+			profile["distinct_approximate"] = approximate
+			profile["min"] = encoder.value(3, minValue)
+			profile["max"] = encoder.value(4, maxValue)
+			profile["avg_length"] = nil
+			if avgLength.Valid {
+				profile["avg_length"] = math.Round(avgLength.Float64*100) / 100
+			}
+		}
+		if frequency.Valid {
+			topValues = append(topValues, map[string]interface{}{
+				"value":     encoder.value(6, topValue),
+				"frequency": frequency.Int64,
+				"percent":   percentOf(frequency.Int64, rowCount),
+			})
+		}
+	}
+	if err = watch.Err(); err != nil {
+		return toolErrorResult(err), nil
+	}
+	if err = rows.Err(); err != nil {
+		return s.dbErrorResult(ErrProfilingColumn, err), nil
+	}
+
+	profile["top_values"] = topValues
+	profile["table"] = map[string]interface{}{
+		"schema": schema,
+		"name":   tableName,
+	}
+	profile["column"] = column
+
+	return jsonToolResult(profile), nil
+}
+
+// getDistinctValuesArgs are the arguments of get_distinct_values
+type getDistinctValuesArgs struct {
+	TableName  string `json:"table_name" jsonschema_description:"Table name"`
+	ColumnName string `json:"column_name" jsonschema_description:"Column name"`
+	Schema     string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	Page       int    `json:"page,omitempty" jsonschema_description:"Page number (default: 1)"`
+	PageSize   int    `json:"page_size,omitempty" jsonschema_description:"Items per page (default: 50, maximum: 1000)"`
+}
+
+func (s *DbMCPServer) toolGetDistinctValues() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("get_distinct_values", "Returns the distinct values of a column with the number of rows holding each one, most frequent first and paginated. Use it to discover enumerations and status codes without writing SQL; NULL is listed as a value", s.handleGetDistinctValues)
+}
+
+func (s *DbMCPServer) handleGetDistinctValues(ctx context.Context, request mcp.CallToolRequest, args getDistinctValuesArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	tableName := args.TableName
+	if !isValidIdentifier(tableName) {
+		return toolErrorResult(ErrInvalidTableName), nil
+	}
+	if !isValidIdentifier(args.ColumnName) {
+		return toolErrorResult(ErrInvalidColumnName), nil
+	}
+
+	defaultSchema := getDefaultSchema(s.queryBuilder.GetDriver())
+	schema, err := getValidSchema(args.Schema, defaultSchema)
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	pagination := newPaginationParams(args.Page, args.PageSize, 50, MaxRowsPageSize)
+
+	ctx, cancel := withQueryTimeout(ctx, DefaultQueryTimeout)
+	defer cancel()
+
+	if exists, err := s.tableExists(ctx, schema, tableName); err != nil {
+		return s.dbErrorResult(ErrCheckingTable, err), nil
+	} else if !exists {
+		return toolErrorResult(fmt.Errorf("%w: %s.%s", ErrTableNotFound, schema, tableName)), nil
+	}
+
+	columns, err := s.getTableColumns(ctx, schema, tableName)
+	if err != nil {
+		return s.dbErrorResult(ErrRetrievingColumns, err), nil
+	}
+	column, ok := tableColumn(columns, args.ColumnName)
+	if !ok {
+		return toolErrorResult(fmt.Errorf("%w: %s", ErrColumnNotExists, args.ColumnName)), nil
+	}
+
+	query := s.queryBuilder.BuildDistinctValuesQuery(schema, tableName, column, pagination.PageSize, pagination.Offset)
+	ctx, watch := s.watchdog.Watch(ctx, "get_distinct_values", query)
+	defer watch.Done()
+
+	var totalCount int
+	if err = s.db.QueryRowContext(ctx, s.queryBuilder.BuildDistinctCountQuery(schema, tableName, column)).Scan(&totalCount); err != nil {
+		return s.dbErrorResult(ErrFetchingDistinctValues, watch.Cause(err)), nil
+	}
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return s.dbErrorResult(ErrFetchingDistinctValues, watch.Cause(err)), nil
+	}
+	defer rows.Close()
+
+	encoder := newResultEncoder(rows)
+	values := []map[string]interface{}{}
+	for rows.Next() {
+		var value interface{}
+		var count int64
+		if err = rows.Scan(&value, &count); err != nil {
+			return toolErrorResult(ErrReadingRow), nil
+		}
+		values = append(values, map[string]interface{}{
+			"value": encoder.value(0, value),
+			"count": count,
+		})
+		watch.Row()
+	}
+	if err = watch.Err(); err != nil {
+		return toolErrorResult(err), nil
+	}
+	if err = rows.Err(); err != nil {
+		return s.dbErrorResult(ErrFetchingDistinctValues, err), nil
+	}
+
+	totalPages := (totalCount + pagination.PageSize - 1) / pagination.PageSize
+
+	return jsonToolResult(map[string]interface{}{
+		"values": values,
+		"column": column,
+		"pagination": map[string]interface{}{
+			"page":         pagination.Page,
+			"page_size":    pagination.PageSize,
+			"total_count":  totalCount,
+			"total_pages":  totalPages,
+			"has_next":     pagination.Page < totalPages,
+			"has_previous": pagination.Page > 1,
+		},
+		"table": map[string]interface{}{
+			"schema": schema,
+			"name":   tableName,
+		},
+	}), nil
+}
+
+// percentOf returns part as a percentage of total, rounded to two decimals
+func percentOf(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)*10000/float64(total)) / 100
+}
@@ -2,141 +2,312 @@ package mcp
 
 import (
 	"context"
-	"encoding/json"
+	"database/sql"
 	"fmt"
 	"log"
-	"time"
-	"unicode/utf8"
+	"math"
+	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// executeQueryArgs are the arguments of execute_query
+type executeQueryArgs struct {
+	Query      string      `json:"query" jsonschema_description:"SQL query to be executed (SELECT only)"`
+	Parameters interface{} `json:"parameters,omitempty" jsonschema_description:"Values bound to the placeholders of the query instead of literals in the SQL text: an array for positional placeholders (@p1, $1, ? or :1, see db://syntax-reference) or an object for named ones (@name or :name, not on Postgres or MySQL) (optional)"`
+	MaxRows    int         `json:"max_rows,omitempty" jsonschema_description:"Maximum number of rows to be returned (default: 100, maximum: DB_MAX_RESULT_ROWS, 10000 unless configured)"`
+	Database   string      `json:"database,omitempty" jsonschema_description:"Run the query against this database snapshot, standby or read-only copy instead of the current database (optional, SQL Server and MySQL, restricted to DB_SNAPSHOT_DATABASES when set)"`
+	Cursor     bool        `json:"cursor,omitempty" jsonschema_description:"Keep the result open when it has more than max_rows rows and return a cursor to read the next chunks with fetch_more (default: false)"`
+	Format     string      `json:"format,omitempty" jsonschema_description:"Rows format: json (an object per row), csv (CSV text in the csv field, NULLs as empty fields) or markdown (the whole response as a markdown table) (default: json)" jsonschema:"enum=json,enum=csv,enum=markdown"`
+	Header     *bool       `json:"header,omitempty" jsonschema_description:"Start csv output with a line of column names (default: true)"`
+	Cache      *bool       `json:"cache,omitempty" jsonschema_description:"Answer from the query cache when the same query ran within DB_QUERY_CACHE_TTL; false runs it again and refreshes the cache (default: true, ignored with cursor)"`
+}
+
 func (s *DbMCPServer) toolExecuteQuery() (mcp.Tool, server.ToolHandlerFunc) {
-	return mcp.Tool{
-		Name:        "execute_query",
-		Description: "Executes a SELECT query and returns the results. Only read-only queries are allowed.",
-		InputSchema: mcp.ToolInputSchema{
-			Type: "object",
-			Properties: map[string]interface{}{
-				"query": map[string]interface{}{
-					"type":        "string",
-					"description": "SQL query to be executed (SELECT only)",
-				},
-				"max_rows": map[string]interface{}{
-					"type":        "number",
-					"description": "Maximum number of rows to be returned (default: 100, max: 10000)",
-				},
-			},
-			Required: []string{"query"},
-		},
-	}, s.handleExecuteQuery
+	return newTypedTool("execute_query", "Executes a SELECT query, or a read statement of the database (SHOW, DESCRIBE and EXPLAIN on MySQL, SHOW and EXPLAIN on PostgreSQL, EXPLAIN and schema PRAGMAs on SQLite), and returns the results. Only read-only queries are allowed.", s.handleExecuteQuery)
 }
 
-func (s *DbMCPServer) handleExecuteQuery(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *DbMCPServer) handleExecuteQuery(ctx context.Context, request mcp.CallToolRequest, args executeQueryArgs) (*mcp.CallToolResult, error) {
 	if err := s.requireConnection(); err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
-	}
-
-	args, ok := getArgs(request.Params.Arguments)
-	if !ok {
-		return mcp.NewToolResultError(ErrInvalidArguments.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
-	query, ok := getStringArg(args, "query")
-	if !ok || query == "" {
-		return mcp.NewToolResultError(ErrQueryRequired.Error()), nil
+	query := args.Query
+	if query == "" {
+		return toolErrorResult(ErrQueryRequired), nil
 	}
 
 	// Complete validation
-	validator := NewSQLValidator(query)
-	if err := validator.Validate(); err != nil {
+	validator := s.newSQLValidator(query)
+	if err := validator.Validate().Err(); err != nil {
 		log.Printf("Query blocked: %s\nReason: %v\n", query, err)
-		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrQueryNotAllowed, err).Error()), nil
+		return toolErrorResult(fmt.Errorf("%w: %w", ErrQueryNotAllowed, err)), nil
+	}
+
+	// Bound values are data, never SQL, so they are not validated
+	params, err := s.bindQueryParameters(args.Parameters)
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	format := strings.ToLower(args.Format)
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" && format != "markdown" {
+		return toolErrorResult(ErrInvalidResultFormat), nil
 	}
+	header := boolArg(args.Header, true)
 
-	maxRows := getIntArg(args, "max_rows", 100)
+	maxRows := args.MaxRows
 	if maxRows <= 0 {
-		maxRows = 100
+		maxRows = DefaultQueryRows
+	}
+	if maxRows > s.maxResultRows {
+		maxRows = s.maxResultRows
+	}
+
+	// Repeated exploratory queries are answered from memory
+	cacheKey := ""
+	if s.queryCache.enabled() && !args.Cursor {
+		cacheKey = queryCacheKey(query, params, maxRows, s.connection, args.Database, format, header)
+		if boolArg(args.Cache, true) {
+			if response, stored, ok := s.queryCache.get(cacheKey); ok {
+				return queryToolResult(cachedQueryResponse(response, stored), format), nil
+			}
+		}
+	}
+
+	largeTableWarning, rejected := s.checkLargeTables(ctx, query)
+	if rejected != nil {
+		return rejected, nil
 	}
-	if maxRows > 10000 {
-		maxRows = 10000
+	costWarning, rejected := s.checkQueryCost(ctx, query, params)
+	if rejected != nil {
+		return rejected, nil
 	}
+	warnings := queryWarnings(costWarning, largeTableWarning)
 
-	ctx, cancel := context.WithTimeout(ctx, DefaultQueryTimeout)
+	// Large exports are read in chunks, so the database is not asked to cap the rows
+	if args.Cursor {
+		return s.openQueryCursor(query, params, maxRows, queryTimeout(ctx, DefaultQueryTimeout), args.Database, format, header, warnings)
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, DefaultQueryTimeout)
 	defer cancel()
 
-	rows, err := s.db.QueryContext(ctx, query)
+	// Heavy exploratory queries can target a point-in-time copy on a dedicated connection
+	var conn *sql.Conn
+	database := args.Database
+	databaseKind := ""
+	if database != "" {
+		conn, databaseKind, err = s.snapshotConn(ctx, database)
+		if err != nil {
+			return toolErrorResult(err), nil
+		}
+		defer discardConn(conn)
+	}
+
+	// The database stops after one row more than max_rows, enough to tell whether the
+	// result was truncated, instead of streaming rows that would be discarded
+	limit := maxRows + 1
+	if setLimit, resetLimit := s.queryBuilder.SessionRowLimit(limit); setLimit != "" {
+		if conn == nil {
+			if conn, err = s.db.Conn(ctx); err != nil {
+				return s.dbErrorResult(ErrExecutingQuery, err), nil
+			}
+			defer releaseLimitedConn(conn, resetLimit)
+		}
+		if _, err = conn.ExecContext(ctx, setLimit); err != nil {
+			return s.dbErrorResult(ErrExecutingQuery, err), nil
+		}
+	}
+	limitedQuery := s.queryBuilder.LimitQuery(query, limit)
+
+	tx, err := s.beginReadOnly(ctx, conn)
+	if err != nil {
+		return s.dbErrorResult(ErrExecutingQuery, err), nil
+	}
+	defer endReadOnly(tx)
+
+	ctx, watch := s.watchdog.Watch(ctx, "execute_query", query)
+	defer watch.Done()
+
+	rows, err := tx.QueryContext(ctx, limitedQuery, params...)
 	if err != nil {
-		log.Printf("Error in query: %v\nQuery: %s\n", err, query)
-		return mcp.NewToolResultError(ErrQuerySyntax.Error()), nil
+		return s.queryErrorResult(query, watch, err), nil
 	}
 	defer rows.Close()
 
 	columns, err := rows.Columns()
 	if err != nil {
-		return mcp.NewToolResultError(ErrRetrievingColumns.Error()), nil
+		return toolErrorResult(ErrRetrievingColumns), nil
 	}
 
-	var results []map[string]interface{}
-	count := 0
+	results := newResultSet(columns)
+	encoder := newResultEncoder(rows)
+	budget := s.newResultBudget()
 
-	for rows.Next() && count < maxRows {
-		values := make([]interface{}, len(columns))
-		valuePtrs := make([]interface{}, len(columns))
-		for i := range values {
-			valuePtrs[i] = &values[i]
-		}
+	values := make([]interface{}, len(columns))
+	valuePtrs := make([]interface{}, len(columns))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
 
+	truncated := false
+	for rows.Next() {
+		if results.Len() >= maxRows {
+			truncated = true
+			break
+		}
 		if err = rows.Scan(valuePtrs...); err != nil {
-			return mcp.NewToolResultError(ErrReadingRow.Error()), nil
+			return toolErrorResult(ErrReadingRow), nil
 		}
 
-		row := make(map[string]interface{})
-		for i, col := range columns {
-			row[col] = formatValue(values[i])
+		encoder.encode(values)
+		if !budget.Add(values) {
+			break
 		}
-		results = append(results, row)
-		count++
+		results.AppendRow(values)
+		watch.Row()
+	}
+
+	if err = watch.Err(); err != nil {
+		return toolErrorResult(err), nil
 	}
 
 	if err = rows.Err(); err != nil {
 		log.Printf("Error during iteration: %v\n", err)
-		return mcp.NewToolResultError(ErrReadingResults.Error()), nil
+		return toolErrorResult(ErrReadingResults), nil
 	}
 
 	response := map[string]interface{}{
-		"rows":      results,
-		"row_count": len(results),
+		"row_count": results.Len(),
 		"columns":   columns,
-		"truncated": count >= maxRows,
+		"truncated": truncated,
 		"max_rows":  maxRows,
 	}
+	if err = setResultRows(response, results, format, header); err != nil {
+		return toolErrorResult(ErrReadingResults), nil
+	}
+	if database != "" {
+		response["database"] = database
+		response["database_kind"] = databaseKind
+	}
+	for key, warning := range warnings {
+		response[key] = warning
+	}
+	budget.Annotate(response)
+	if cacheKey != "" {
+		s.queryCache.put(cacheKey, response)
+	}
+
+	return queryToolResult(response, format), nil
+}
 
-	jsonData, err := json.MarshalIndent(response, "", "  ")
+// setResultRows adds the rows to a query response, as an object per row or in csv format
+// as CSV text. Markdown responses are rendered from the rows objects.
+func setResultRows(response map[string]interface{}, results *ResultSet, format string, header bool) error {
+	if format != "csv" {
+		response["rows"] = results
+		return nil
+	}
+	text, err := results.CSV(header)
 	if err != nil {
-		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
+		return err
+	}
+	response["format"] = format
+	response["csv"] = text
+	return nil
+}
+
+// queryToolResult returns a query response as JSON or rendered as markdown
+func queryToolResult(response map[string]interface{}, format string) *mcp.CallToolResult {
+	if format == "markdown" {
+		return markdownToolResult(response)
 	}
+	return jsonToolResult(response)
+}
 
-	return mcp.NewToolResultText(string(jsonData)), nil
+// queryErrorResult reports a query that failed to run: killed by the watchdog, with the
+// details of the driver error when they can be parsed, or as a syntax error
+func (s *DbMCPServer) queryErrorResult(query string, watch *watchedQuery, err error) *mcp.CallToolResult {
+	log.Printf("Error in query: %v\nQuery: %s\n", err, query)
+	if cause := watch.Cause(err); cause != err {
+		return toolErrorResult(cause)
+	}
+	if result, ok := s.structuredErrorResult(ErrExecutingQuery, err); ok {
+		return result
+	}
+	return toolErrorResult(ErrQuerySyntax)
 }
 
-// formatValue converts database values to JSON-safe formats
-func formatValue(val interface{}) interface{} {
-	switch v := val.(type) {
-	case []byte:
-		if len(v) > 1000 {
-			return fmt.Sprintf("<binary data: %d bytes>", len(v))
+// releaseLimitedConn lifts the session row cap of a connection and returns it to the pool,
+// or discards it if the cap could not be lifted
+func releaseLimitedConn(conn *sql.Conn, resetLimit string) {
+	ctx, cancel := context.WithTimeout(context.Background(), ShortQueryTimeout)
+	defer cancel()
+	if _, err := conn.ExecContext(ctx, resetLimit); err != nil {
+		discardConn(conn)
+		return
+	}
+	_ = conn.Close()
+}
+
+// bindQueryParameters returns the arguments of the parameters of execute_query: an array
+// binds positional placeholders in order, an object binds named placeholders
+func (s *DbMCPServer) bindQueryParameters(parameters interface{}) ([]interface{}, error) {
+	var params []interface{}
+	switch p := parameters.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		for i, value := range p {
+			arg, ok := queryParameterValue(value)
+			if !ok {
+				return nil, fmt.Errorf("%w: "+translate("parameter %d must be a string, number, boolean or null"), ErrInvalidParameters, i+1)
+			}
+			params = append(params, arg)
 		}
-		if utf8.Valid(v) {
-			return string(v)
+	case map[string]interface{}:
+		if len(p) > 0 && !s.queryBuilder.SupportsNamedParameters() {
+			return nil, ErrNamedParametersNotSupported
 		}
-		return fmt.Sprintf("<binary data: %d bytes>", len(v))
-	case time.Time:
-		return v.Format("2006-01-02 15:04:05")
-	case nil:
-		return nil
+		for name, value := range p {
+			// The placeholder prefix is accepted, as in {"@id": 1}
+			name = strings.TrimLeft(name, "@:$")
+			if !isPlainIdentifier(name) {
+				return nil, fmt.Errorf("%w: "+translate("invalid parameter name %q"), ErrInvalidParameters, name)
+			}
+			arg, ok := queryParameterValue(value)
+			if !ok {
+				return nil, fmt.Errorf("%w: "+translate("parameter %s must be a string, number, boolean or null"), ErrInvalidParameters, name)
+			}
+			params = append(params, sql.Named(name, arg))
+		}
+	default:
+		return nil, fmt.Errorf("%w: "+translate("parameters must be an array or an object"), ErrInvalidParameters)
+	}
+
+	if len(params) > MaxQueryParameters {
+		return nil, fmt.Errorf("%w "+translate("(maximum %d)"), ErrTooManyParameters, MaxQueryParameters)
+	}
+	return params, nil
+}
+
+// queryParameterValue returns the driver value of a JSON parameter value. Whole numbers
+// are bound as integers, so they compare with integer columns without a cast.
+func queryParameterValue(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case nil, string, bool:
+		return v, true
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int64(v), true
+		}
+		return v, true
 	default:
-		return v
+		return nil, false
 	}
 }
@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// remoteServer is a linked server, foreign server or database link with the foreign
+// tables stored on it
+type remoteServer struct {
+	Name                   string         `json:"name"`
+	Kind                   string         `json:"kind"`
+	Provider               string         `json:"provider,omitempty"`
+	Product                string         `json:"product,omitempty"`
+	DataSource             string         `json:"data_source,omitempty"`
+	Catalog                string         `json:"catalog,omitempty"`
+	Options                string         `json:"options,omitempty"`
+	ForeignTables          []foreignTable `json:"foreign_tables,omitempty"`
+	ForeignTableCount      int            `json:"foreign_table_count,omitempty"`
+	ForeignTablesTruncated bool           `json:"foreign_tables_truncated,omitempty"`
+}
+
+// foreignTable is a local table whose rows live on a remote server
+type foreignTable struct {
+	Schema       string `json:"schema"`
+	Name         string `json:"name"`
+	RemoteSchema string `json:"remote_schema,omitempty"`
+	RemoteName   string `json:"remote_name,omitempty"`
+	Options      string `json:"options,omitempty"`
+}
+
+// listRemoteServersArgs are the arguments of list_remote_servers
+type listRemoteServersArgs struct {
+	NameFilter string `json:"name_filter,omitempty" jsonschema_description:"Filter by server name (optional)"`
+}
+
+func (s *DbMCPServer) toolListRemoteServers() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("list_remote_servers", "Lists SQL Server linked servers, PostgreSQL foreign servers with their foreign tables, MySQL federated servers and Oracle database links, with the host and database each one points to. Use to find out where data behind remote objects actually lives", s.handleListRemoteServers)
+}
+
+func (s *DbMCPServer) handleListRemoteServers(ctx context.Context, request mcp.CallToolRequest, args listRemoteServersArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	nameFilter := args.NameFilter
+
+	query, queryArgs, ok := s.queryBuilder.ListRemoteServersQuery(nameFilter)
+	if !ok {
+		return toolErrorResult(ErrFeatureNotSupported), nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return s.dbErrorResult(ErrListingRemoteServers, err), nil
+	}
+	defer rows.Close()
+
+	servers := []*remoteServer{}
+	byName := make(map[string]*remoteServer)
+	for rows.Next() {
+		var name, kind string
+		var provider, product, dataSource, catalog, options sql.NullString
+		if err = rows.Scan(&name, &kind, &provider, &product, &dataSource, &catalog, &options); err != nil {
+			continue
+		}
+
+		srv := &remoteServer{
+			Name:       name,
+			Kind:       kind,
+			Provider:   provider.String,
+			Product:    product.String,
+			DataSource: dataSource.String,
+			Catalog:    catalog.String,
+			Options:    options.String,
+		}
+		servers = append(servers, srv)
+		byName[name] = srv
+	}
+	if err = rows.Err(); err != nil {
+		return s.dbErrorResult(ErrListingRemoteServers, err), nil
+	}
+
+	// Foreign tables are attached to the servers listed above
+	foreignTables := 0
+	if tablesQuery, ok := s.queryBuilder.ListForeignTablesQuery(); ok && len(servers) > 0 {
+		tableRows, err := s.db.QueryContext(ctx, tablesQuery)
+		if err != nil {
+			return s.dbErrorResult(ErrListingRemoteServers, err), nil
+		}
+		defer tableRows.Close()
+
+		for tableRows.Next() {
+			var serverName string
+			var table foreignTable
+			var remoteSchema, remoteName, options sql.NullString
+			if err = tableRows.Scan(&serverName, &table.Schema, &table.Name, &remoteSchema, &remoteName, &options); err != nil {
+				continue
+			}
+			srv, ok := byName[serverName]
+			if !ok {
+				continue
+			}
+
+			foreignTables++
+			srv.ForeignTableCount++
+			if len(srv.ForeignTables) >= MaxForeignTablesPerServer {
+				srv.ForeignTablesTruncated = true
+				continue
+			}
+			table.RemoteSchema = remoteSchema.String
+			table.RemoteName = remoteName.String
+			table.Options = options.String
+			srv.ForeignTables = append(srv.ForeignTables, table)
+		}
+		if err = tableRows.Err(); err != nil {
+			return s.dbErrorResult(ErrListingRemoteServers, err), nil
+		}
+	}
+
+	response := map[string]interface{}{
+		"servers":        servers,
+		"count":          len(servers),
+		"foreign_tables": foreignTables,
+	}
+	if nameFilter != "" {
+		response["name_filter"] = nameFilter
+	}
+
+	return jsonToolResult(response), nil
+}
@@ -0,0 +1,61 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func (s *DbMCPServer) toolGetRuntimeStats() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("get_runtime_stats", "Returns runtime statistics of the MCP server process: goroutines, heap, GC pauses, connection pool and in-flight queries", s.handleGetRuntimeStats)
+}
+
+func (s *DbMCPServer) handleGetRuntimeStats(ctx context.Context, request mcp.CallToolRequest, _ noArgs) (*mcp.CallToolResult, error) {
+	response := s.runtimeStats()
+	response["connected"] = s.db != nil
+	response["pool"] = s.poolStats()
+	response["queries_in_flight"] = s.watchdog.InFlight()
+	response["query_cache"] = s.queryCache.stats()
+	response["debug_endpoints"] = s.debugServer != nil
+
+	return jsonToolResult(response), nil
+}
+
+func (s *DbMCPServer) toolListRunningQueries() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("list_running_queries", "Lists the queries this server is running: execute_query, list_table_rows, sample_table_data, profile_column, get_distinct_values, find_value_in_table, aggregate_table, execute_procedure, export_query, explain_query with analyze and open execute_query cursors. Each has an id for cancel_query, the tool, a fingerprint shared by queries that differ only in literals, the start time, elapsed time and rows streamed so far", s.handleListRunningQueries)
+}
+
+func (s *DbMCPServer) handleListRunningQueries(ctx context.Context, request mcp.CallToolRequest, _ noArgs) (*mcp.CallToolResult, error) {
+	queries := s.watchdog.Running()
+	return jsonToolResult(map[string]interface{}{
+		"queries": queries,
+		"count":   len(queries),
+	}), nil
+}
+
+// cancelQueryArgs are the arguments of cancel_query
+type cancelQueryArgs struct {
+	ID uint64 `json:"id" jsonschema_description:"Id of the query, from list_running_queries"`
+}
+
+func (s *DbMCPServer) toolCancelQuery() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("cancel_query", "Cancels a running query by its id from list_running_queries. The driver asks the database to stop it and the tool call that ran it returns an error; the rest of the server keeps running", s.handleCancelQuery)
+}
+
+func (s *DbMCPServer) handleCancelQuery(ctx context.Context, request mcp.CallToolRequest, args cancelQueryArgs) (*mcp.CallToolResult, error) {
+	if args.ID == 0 {
+		return toolErrorResult(ErrQueryIDRequired), nil
+	}
+
+	query, ok := s.watchdog.Cancel(args.ID)
+	if !ok {
+		return toolErrorResult(fmt.Errorf("%w: %d", ErrRunningQueryNotFound, args.ID)), nil
+	}
+
+	return jsonToolResult(map[string]interface{}{
+		"cancelled": true,
+		"query":     query,
+	}), nil
+}
@@ -0,0 +1,188 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// sampleTableDataArgs are the arguments of sample_table_data
+type sampleTableDataArgs struct {
+	TableName string   `json:"table_name" jsonschema_description:"Table name"`
+	Schema    string   `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	Rows      int      `json:"rows,omitempty" jsonschema_description:"Number of rows to return (default: 10, maximum: 1000)"`
+	Columns   []string `json:"columns,omitempty" jsonschema_description:"Columns to return (optional, default: all)"`
+	Method    string   `json:"method,omitempty" jsonschema_description:"random for random rows, or top for the first rows in storage order, which is faster (default: random)"`
+}
+
+func (s *DbMCPServer) toolSampleTableData() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("sample_table_data", "Returns a few random rows, or the top rows, of a table without writing SQL. It is the fastest way to learn what a table actually contains; large tables are read from a table sample instead of being sorted", s.handleSampleTableData)
+}
+
+func (s *DbMCPServer) handleSampleTableData(ctx context.Context, request mcp.CallToolRequest, args sampleTableDataArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	tableName := args.TableName
+	if !isValidIdentifier(tableName) {
+		return toolErrorResult(ErrInvalidTableName), nil
+	}
+
+	defaultSchema := getDefaultSchema(s.queryBuilder.GetDriver())
+	schema, err := getValidSchema(args.Schema, defaultSchema)
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	method := strings.ToLower(args.Method)
+	switch method {
+	case "":
+		method = "random"
+	case "random", "top":
+	default:
+		return toolErrorResult(ErrInvalidSampleMethod), nil
+	}
+
+	limit := args.Rows
+	if limit < 1 {
+		limit = DefaultSampleRows
+	}
+	limit = min(limit, MaxSampleRows)
+
+	ctx, cancel := withQueryTimeout(ctx, DefaultQueryTimeout)
+	defer cancel()
+
+	if exists, err := s.tableExists(ctx, schema, tableName); err != nil {
+		return s.dbErrorResult(ErrCheckingTable, err), nil
+	} else if !exists {
+		return toolErrorResult(fmt.Errorf("%w: %s.%s", ErrTableNotFound, schema, tableName)), nil
+	}
+
+	tableColumns, err := s.getTableColumns(ctx, schema, tableName)
+	if err != nil {
+		return s.dbErrorResult(ErrRetrievingColumns, err), nil
+	}
+	if len(tableColumns) == 0 {
+		return toolErrorResult(ErrNoColumnsFound), nil
+	}
+
+	// Requested columns take the spelling of the table, so they are quoted as declared
+	columns := tableColumns
+	if len(args.Columns) > 0 {
+		columns = make([]string, 0, len(args.Columns))
+		for _, name := range args.Columns {
+			column, ok := tableColumn(tableColumns, name)
+			if !ok {
+				return toolErrorResult(fmt.Errorf("%w: %s", ErrColumnNotExists, name)), nil
+			}
+			columns = append(columns, column)
+		}
+	}
+
+	// Sorting a large table for a few random rows would scan and sort all of it
+	var percent float64
+	estimatedRows := -1.0
+	if method == "random" && s.queryBuilder.SupportsTableSample() {
+		estimate, ok, err := s.estimateQuery(ctx, "SELECT * FROM "+s.queryBuilder.QualifyTable(schema, tableName), nil)
+		if err != nil {
+			log.Printf("Could not estimate the rows of %s.%s: %v\n", schema, tableName, err)
+		}
+		if ok {
+			estimatedRows = estimate.Rows
+			percent = samplePercent(limit, estimate.Rows)
+		}
+	}
+
+	query := s.queryBuilder.BuildSampleQuery(schema, tableName, columns, limit, method == "random", percent)
+	ctx, watch := s.watchdog.Watch(ctx, "sample_table_data", query)
+	defer watch.Done()
+
+	dbRows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return s.dbErrorResult(ErrFetchingRows, watch.Cause(err)), nil
+	}
+	defer dbRows.Close()
+
+	budget := s.newResultBudget()
+	rows := newResultSet(columns)
+	encoder := newResultEncoder(dbRows)
+
+	values := make([]interface{}, len(columns))
+	valuePtrs := make([]interface{}, len(columns))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+
+	for dbRows.Next() {
+		if err = dbRows.Scan(valuePtrs...); err != nil {
+			return toolErrorResult(ErrReadingRow), nil
+		}
+		encoder.encode(values)
+		if !budget.Add(values) {
+			break
+		}
+		rows.AppendRow(values)
+		watch.Row()
+	}
+	if err = watch.Err(); err != nil {
+		return toolErrorResult(err), nil
+	}
+	if err = dbRows.Err(); err != nil {
+		return s.dbErrorResult(ErrFetchingRows, err), nil
+	}
+
+	sample := map[string]interface{}{
+		"method":         method,
+		"requested_rows": limit,
+	}
+	if percent > 0 {
+		sample["table_sample_percent"] = percent
+	}
+	if estimatedRows >= 0 {
+		sample["estimated_table_rows"] = int64(estimatedRows)
+	}
+
+	response := map[string]interface{}{
+		"rows":      rows,
+		"columns":   columns,
+		"row_count": rows.Len(),
+		"sample":    sample,
+		"table": map[string]interface{}{
+			"schema": schema,
+			"name":   tableName,
+		},
+	}
+	budget.Annotate(response)
+
+	return jsonToolResult(response), nil
+}
+
+// tableColumn returns the column of a table matching name case-insensitively
+func tableColumn(columns []string, name string) (string, bool) {
+	for _, column := range columns {
+		if strings.EqualFold(column, name) {
+			return column, true
+		}
+	}
+	return "", false
+}
+
+// samplePercent returns the percentage of a table of estimatedRows rows to sample for limit
+// random rows, or 0 when the table is small enough to sort
+func samplePercent(limit int, estimatedRows float64) float64 {
+	if estimatedRows <= SampleScanRows {
+		return 0
+	}
+	percent := 100 * float64(limit*SampleOversampling) / estimatedRows
+	if percent >= 100 {
+		return 0
+	}
+	// Rounded to a short literal, but never down to an empty sample
+	return math.Max(math.Round(percent*10000)/10000, 0.0001)
+}
@@ -0,0 +1,134 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"math"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// listStatisticsArgs are the arguments of list_statistics
+type listStatisticsArgs struct {
+	TableName string `json:"table_name,omitempty" jsonschema_description:"Filter by table name (optional)"`
+	Schema    string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	StaleOnly bool   `json:"stale_only,omitempty" jsonschema_description:"Return only statistics never updated or with more than 10% of the rows modified since the last update (default: false)"`
+}
+
+func (s *DbMCPServer) toolListStatistics() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("list_statistics", "List the optimizer statistics of tables with when they were last updated, the rows sampled and the rows modified since, flagging stale statistics. Stale statistics are a common cause of bad query plans", s.handleListStatistics)
+}
+
+func (s *DbMCPServer) handleListStatistics(ctx context.Context, request mcp.CallToolRequest, args listStatisticsArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	schema, err := getValidSchema(args.Schema, "")
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	tableName := args.TableName
+	if tableName != "" && !isValidIdentifier(tableName) {
+		return toolErrorResult(ErrInvalidTableName), nil
+	}
+
+	query, queryArgs, ok := s.queryBuilder.ListStatisticsQuery(schema, tableName)
+	if !ok {
+		return toolErrorResult(ErrFeatureNotSupported), nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return s.dbErrorResult(ErrListingStatistics, err), nil
+	}
+	defer rows.Close()
+
+	statistics := []map[string]interface{}{}
+	stale := 0
+	for rows.Next() {
+		var statsSchema, table string
+		var name, columns sql.NullString
+		var lastUpdated sql.NullTime
+		var rowCount, sampled, modifications, autoCreated sql.NullInt64
+		if err = rows.Scan(&statsSchema, &table, &name, &columns, &lastUpdated, &rowCount, &sampled, &modifications, &autoCreated); err != nil {
+			continue
+		}
+
+		isStale := statisticsStale(lastUpdated.Valid, rowCount, modifications)
+		if isStale {
+			stale++
+		} else if args.StaleOnly {
+			continue
+		}
+
+		entry := map[string]interface{}{
+			"schema":       statsSchema,
+			"table":        table,
+			"last_updated": nil,
+			"stale":        isStale,
+		}
+		if name.Valid {
+			entry["name"] = name.String
+		}
+		if columns.Valid {
+			entry["columns"] = columns.String
+		}
+		if lastUpdated.Valid {
+			entry["last_updated"] = lastUpdated.Time.Format("2006-01-02 15:04:05")
+		}
+		if rowCount.Valid {
+			entry["rows"] = rowCount.Int64
+		}
+		if sampled.Valid {
+			entry["rows_sampled"] = sampled.Int64
+			if rowCount.Int64 > 0 {
+				entry["sample_percent"] = math.Round(float64(sampled.Int64)*10000/float64(rowCount.Int64)) / 100
+			}
+		}
+		if modifications.Valid {
+			entry["modifications"] = modifications.Int64
+		}
+		if autoCreated.Valid {
+			entry["auto_created"] = autoCreated.Int64 == 1
+		}
+		statistics = append(statistics, entry)
+	}
+	if err = rows.Err(); err != nil {
+		return s.dbErrorResult(ErrListingStatistics, err), nil
+	}
+
+	response := map[string]interface{}{
+		"statistics": statistics,
+		"count":      len(statistics),
+		"stale":      stale,
+		"filter": map[string]interface{}{
+			"schema":     schema,
+			"table":      tableName,
+			"stale_only": args.StaleOnly,
+		},
+	}
+
+	return jsonToolResult(response), nil
+}
+
+// statisticsStale reports whether statistics were never updated or have more than
+// StaleStatisticsPercent of their rows modified since the last update. Without a
+// modification count only never updated statistics are stale.
+func statisticsStale(updated bool, rowCount, modifications sql.NullInt64) bool {
+	if !updated {
+		return true
+	}
+	if !modifications.Valid || modifications.Int64 == 0 {
+		return false
+	}
+	if !rowCount.Valid || rowCount.Int64 == 0 {
+		return true
+	}
+	return modifications.Int64*100 > rowCount.Int64*StaleStatisticsPercent
+}
@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// listSynonymsArgs are the arguments of list_synonyms
+type listSynonymsArgs struct {
+	Schema     string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	NameFilter string `json:"name_filter,omitempty" jsonschema_description:"Filter by synonym name (optional)"`
+	pageArgs
+}
+
+func (s *DbMCPServer) toolListSynonyms() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("list_synonyms", "List synonyms with the base object each one resolves to (SQL Server and Oracle synonyms, PostgreSQL foreign tables)", s.handleListSynonyms)
+}
+
+func (s *DbMCPServer) handleListSynonyms(ctx context.Context, request mcp.CallToolRequest, args listSynonymsArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	schema, err := getValidSchema(args.Schema, "")
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	nameFilter := args.NameFilter
+	pagination := args.pagination()
+
+	query, queryArgs, ok := s.queryBuilder.ListSynonymsQuery(schema, nameFilter, pagination.PageSize, pagination.Offset)
+	if !ok {
+		return toolErrorResult(ErrFeatureNotSupported), nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return s.dbErrorResult(ErrListingSynonyms, err), nil
+	}
+	defer rows.Close()
+
+	var synonyms []map[string]interface{}
+	for rows.Next() {
+		var schemaName, synonymName, baseObject string
+		var serverName, baseType sql.NullString
+
+		if err = rows.Scan(&schemaName, &synonymName, &baseObject, &serverName, &baseType); err != nil {
+			continue
+		}
+
+		synonym := map[string]interface{}{
+			"schema":      schemaName,
+			"name":        synonymName,
+			"base_object": baseObject,
+			"resolved":    baseType.Valid,
+		}
+		if serverName.Valid {
+			synonym["server"] = serverName.String
+		}
+		if baseType.Valid {
+			synonym["base_object_type"] = baseType.String
+		}
+		synonyms = append(synonyms, synonym)
+	}
+
+	response := map[string]interface{}{
+		"synonyms": synonyms,
+		"pagination": map[string]interface{}{
+			"page":      pagination.Page,
+			"page_size": pagination.PageSize,
+			"count":     len(synonyms),
+		},
+		"filter": map[string]interface{}{
+			"schema":      schema,
+			"name_filter": nameFilter,
+		},
+	}
+
+	return jsonToolResult(response), nil
+}
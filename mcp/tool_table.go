@@ -3,68 +3,53 @@ package mcp
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// listTablesArgs are the arguments of list_tables
+type listTablesArgs struct {
+	Schema     string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	NameFilter string `json:"name_filter,omitempty" jsonschema_description:"Filter by table name (optional)"`
+	crossDatabaseArgs
+	pageArgs
+}
+
 func (s *DbMCPServer) toolListTables() (mcp.Tool, server.ToolHandlerFunc) {
-	return mcp.Tool{
-		Name:        "list_tables",
-		Description: "List database tables with pagination",
-		InputSchema: mcp.ToolInputSchema{
-			Type: "object",
-			Properties: map[string]interface{}{
-				"schema": map[string]interface{}{
-					"type":        "string",
-					"description": "Schema name (optional)",
-				},
-				"name_filter": map[string]interface{}{
-					"type":        "string",
-					"description": "Filter by table name (optional)",
-				},
-				"page": map[string]interface{}{
-					"type":        "number",
-					"description": "Page number (default: 1)",
-				},
-				"page_size": map[string]interface{}{
-					"type":        "number",
-					"description": "Items per page (default: 100, maximum: 500)",
-				},
-			},
-		},
-	}, s.handleListTables
+	return newTypedTool("list_tables", "List database tables with pagination", s.handleListTables)
 }
 
-func (s *DbMCPServer) handleListTables(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *DbMCPServer) handleListTables(ctx context.Context, request mcp.CallToolRequest, args listTablesArgs) (*mcp.CallToolResult, error) {
 	if err := s.requireConnection(); err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
-	args, ok := getArgs(request.Params.Arguments)
-	if !ok {
-		return mcp.NewToolResultError(ErrInvalidArguments.Error()), nil
+	schema, err := getValidSchema(args.Schema, "")
+	if err != nil {
+		return toolErrorResult(err), nil
 	}
 
-	schema, err := getValidSchema(args, "")
+	database, schema, err := getTargetDatabase(args.Database, s.queryBuilder, schema)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
-	nameFilter, _ := getStringArg(args, "name_filter")
-	pagination := GetPaginationParams(args, DefaultPageSize, MaxPageSize)
+	nameFilter := args.NameFilter
+	pagination := args.pagination()
 
 	query, queryArgs := s.queryBuilder.ListTablesQuery(schema, nameFilter, pagination.PageSize, pagination.Offset)
+	query = s.queryBuilder.InDatabase(query, database)
 
-	ctx, cancel := context.WithTimeout(ctx, ShortQueryTimeout)
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
 	defer cancel()
 
 	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrListingTables, err).Error()), nil
+		return s.dbErrorResult(ErrListingTables, err), nil
 	}
 	defer rows.Close()
 
@@ -89,69 +74,56 @@ func (s *DbMCPServer) handleListTables(ctx context.Context, request mcp.CallTool
 			"count":     len(tables),
 		},
 		"filter": map[string]interface{}{
+			"database":    database,
 			"schema":      schema,
 			"name_filter": nameFilter,
 		},
 	}
 
-	jsonData, err := json.MarshalIndent(response, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
-	}
+	return jsonToolResult(response), nil
+}
 
-	return mcp.NewToolResultText(string(jsonData)), nil
+// describeTableArgs are the arguments of describe_table
+type describeTableArgs struct {
+	TableName string `json:"table_name" jsonschema_description:"Table name"`
+	Schema    string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	crossDatabaseArgs
 }
 
 func (s *DbMCPServer) toolDescribeTable() (mcp.Tool, server.ToolHandlerFunc) {
-	return mcp.Tool{
-		Name:        "describe_table",
-		Description: "Returns the structure of a table (columns, types, constraints)",
-		InputSchema: mcp.ToolInputSchema{
-			Type: "object",
-			Properties: map[string]interface{}{
-				"table_name": map[string]interface{}{
-					"type":        "string",
-					"description": "Table name",
-				},
-				"schema": map[string]interface{}{
-					"type":        "string",
-					"description": "Schema name (optional)",
-				},
-			},
-			Required: []string{"table_name"},
-		},
-	}, s.handleDescribeTable
+	return newTypedTool("describe_table", "Returns the structure of a table (columns, types, constraints) with the table and column comments", s.handleDescribeTable)
 }
 
-func (s *DbMCPServer) handleDescribeTable(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *DbMCPServer) handleDescribeTable(ctx context.Context, request mcp.CallToolRequest, args describeTableArgs) (*mcp.CallToolResult, error) {
 	if err := s.requireConnection(); err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
-	args, ok := getArgs(request.Params.Arguments)
-	if !ok {
-		return mcp.NewToolResultError(ErrInvalidArguments.Error()), nil
+	tableName := args.TableName
+	if !isValidIdentifier(tableName) {
+		return toolErrorResult(ErrInvalidTableName), nil
 	}
 
-	tableName, ok := getStringArg(args, "table_name")
-	if !ok || !isValidIdentifier(tableName) {
-		return mcp.NewToolResultError(ErrInvalidTableName.Error()), nil
+	defaultSchema := getDefaultSchema(s.queryBuilder.GetDriver())
+	schema, err := getValidSchema(args.Schema, defaultSchema)
+	if err != nil {
+		return toolErrorResult(err), nil
 	}
 
-	defaultSchema := getDefaultSchema(s.queryBuilder.GetDriver())
-	schema, err := getValidSchema(args, defaultSchema)
+	database, schema, err := getTargetDatabase(args.Database, s.queryBuilder, schema)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
 	query, queryArgs := s.queryBuilder.DescribeTableQuery(schema, tableName)
+	query = s.queryBuilder.InDatabase(query, database)
 
-	ctx, cancel := context.WithTimeout(ctx, ShortQueryTimeout)
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
 	defer cancel()
 
 	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrDescribingTable, err).Error()), nil
+		return s.dbErrorResult(ErrDescribingTable, err), nil
 	}
 	defer rows.Close()
 
@@ -165,7 +137,7 @@ func (s *DbMCPServer) handleDescribeTable(ctx context.Context, request mcp.CallT
 	}
 
 	if len(columns) == 0 {
-		return mcp.NewToolResultError(ErrTableNotFound.Error()), nil
+		return toolErrorResult(ErrTableNotFound), nil
 	}
 
 	response := map[string]interface{}{
@@ -173,13 +145,32 @@ func (s *DbMCPServer) handleDescribeTable(ctx context.Context, request mcp.CallT
 		"table":   tableName,
 		"columns": columns,
 	}
+	if database != "" {
+		response["database"] = database
+	}
 
-	jsonData, err := json.MarshalIndent(response, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
+	// Comments are optional: a database without them or a failed lookup keeps the structure
+	if comments, err := s.fetchObjectComments(ctx, database, schema, tableName); err == nil {
+		if comments.comment != "" {
+			response["comment"] = comments.comment
+		}
+		addColumnComments(columns, comments.columns)
 	}
 
-	return mcp.NewToolResultText(string(jsonData)), nil
+	return jsonToolResult(response), nil
+}
+
+// addColumnComments sets the comment of the described columns that have one
+func addColumnComments(columns, comments []map[string]interface{}) {
+	byName := make(map[string]interface{}, len(comments))
+	for _, comment := range comments {
+		byName[comment["column"].(string)] = comment["comment"]
+	}
+	for _, column := range columns {
+		if comment, ok := byName[column["name"].(string)]; ok {
+			column["comment"] = comment
+		}
+	}
 }
 
 func (s *DbMCPServer) parseStandardDescribeTable(rows *sql.Rows) []map[string]interface{} {
@@ -235,131 +226,112 @@ func (s *DbMCPServer) parseSQLitePragmaTableInfo(rows *sql.Rows) []map[string]in
 	return columns
 }
 
+// rowFilter is a condition on a column of list_table_rows
+type rowFilter struct {
+	Column   string      `json:"column" jsonschema_description:"Column name"`
+	Operator string      `json:"operator" jsonschema_description:"Operator: eq, neq, gt, gte, lt, lte, contains, starts_with, ends_with, is_null, is_not_null" jsonschema:"enum=eq,enum=neq,enum=gt,enum=gte,enum=lt,enum=lte,enum=contains,enum=starts_with,enum=ends_with,enum=is_null,enum=is_not_null"`
+	Value    interface{} `json:"value,omitempty" jsonschema_description:"Value to compare (not required for is_null/is_not_null)"`
+}
+
+// listTableRowsArgs are the arguments of list_table_rows
+type listTableRowsArgs struct {
+	TableName      string      `json:"table_name" jsonschema_description:"Table name"`
+	Schema         string      `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	Filters        []rowFilter `json:"filters,omitempty" jsonschema_description:"Filters (e.g.: [{\"column\": \"name\", \"operator\": \"contains\", \"value\": \"john\"}])"`
+	Page           int         `json:"page,omitempty" jsonschema_description:"Page number (default: 1)"`
+	PageSize       int         `json:"page_size,omitempty" jsonschema_description:"Items per page (default: 50, maximum: 1000)"`
+	OrderBy        string      `json:"order_by,omitempty" jsonschema_description:"Column for sorting (optional, default: the primary key, or the first column)"`
+	OrderDirection string      `json:"order_direction,omitempty" jsonschema_description:"Sorting direction: ASC or DESC (default: ASC)"`
+	AsOf           string      `json:"as_of,omitempty" jsonschema_description:"Read the rows as they were at this time, e.g. 2024-05-01T08:00:00Z (UTC without offset). SQL Server system-versioned temporal tables and Oracle flashback query only"`
+}
+
 func (s *DbMCPServer) toolListTableRows() (mcp.Tool, server.ToolHandlerFunc) {
-	return mcp.Tool{
-		Name:        "list_table_rows",
-		Description: "List the rows of a database table with pagination and advanced filters",
-		InputSchema: mcp.ToolInputSchema{
-			Type: "object",
-			Properties: map[string]interface{}{
-				"table_name": map[string]interface{}{
-					"type":        "string",
-					"description": "Table name",
-				},
-				"schema": map[string]interface{}{
-					"type":        "string",
-					"description": "Schema name (optional)",
-				},
-				"filters": map[string]interface{}{
-					"type":        "array",
-					"description": "Filters (e.g.: [{\"column\": \"name\", \"operator\": \"contains\", \"value\": \"john\"}])",
-					"items": map[string]interface{}{
-						"type": "object",
-						"properties": map[string]interface{}{
-							"column": map[string]interface{}{
-								"type":        "string",
-								"description": "Column name",
-							},
-							"operator": map[string]interface{}{
-								"type":        "string",
-								"description": "Operator: eq, neq, gt, gte, lt, lte, contains, starts_with, ends_with, is_null, is_not_null",
-								"enum":        []string{"eq", "neq", "gt", "gte", "lt", "lte", "contains", "starts_with", "ends_with", "is_null", "is_not_null"},
-							},
-							"value": map[string]interface{}{
-								"description": "Value to compare (not required for is_null/is_not_null)",
-							},
-						},
-						"required": []string{"column", "operator"},
-					},
-				},
-				"page": map[string]interface{}{
-					"type":        "number",
-					"description": "Page number (default: 1)",
-				},
-				"page_size": map[string]interface{}{
-					"type":        "number",
-					"description": "Items per page (default: 50, maximum: 1000)",
-				},
-				"order_by": map[string]interface{}{
-					"type":        "string",
-					"description": "Column for sorting (optional)",
-				},
-				"order_direction": map[string]interface{}{
-					"type":        "string",
-					"description": "Sorting direction: ASC or DESC (default: ASC)",
-				},
-			},
-			Required: []string{"table_name"},
-		},
-	}, s.handleListTableRows
+	return newTypedTool("list_table_rows", "List the rows of a database table with pagination and advanced filters", s.handleListTableRows)
 }
 
-func (s *DbMCPServer) handleListTableRows(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *DbMCPServer) handleListTableRows(ctx context.Context, request mcp.CallToolRequest, args listTableRowsArgs) (*mcp.CallToolResult, error) {
 	if err := s.requireConnection(); err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
-	args, ok := getArgs(request.Params.Arguments)
-	if !ok {
-		return mcp.NewToolResultError(ErrInvalidArguments.Error()), nil
+	tableName := args.TableName
+	if !isValidIdentifier(tableName) {
+		return toolErrorResult(ErrInvalidTableName), nil
 	}
 
-	tableName, ok := getStringArg(args, "table_name")
-	if !ok || !isValidIdentifier(tableName) {
-		return mcp.NewToolResultError(ErrInvalidTableName.Error()), nil
+	defaultSchema := getDefaultSchema(s.queryBuilder.GetDriver())
+	schema, err := getValidSchema(args.Schema, defaultSchema)
+	if err != nil {
+		return toolErrorResult(err), nil
 	}
 
-	defaultSchema := getDefaultSchema(s.queryBuilder.GetDriver())
-	schema, err := getValidSchema(args, defaultSchema)
+	asOf, err := s.parseAsOf(args.AsOf)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, DefaultQueryTimeout)
+	ctx, cancel := withQueryTimeout(ctx, DefaultQueryTimeout)
 	defer cancel()
 
 	// Check if table exists
 	if exists, err := s.tableExists(ctx, schema, tableName); err != nil {
-		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrCheckingTable, err).Error()), nil
+		return s.dbErrorResult(ErrCheckingTable, err), nil
 	} else if !exists {
-		return mcp.NewToolResultError(fmt.Errorf("%w: %s.%s", ErrTableNotFound, schema, tableName).Error()), nil
+		return toolErrorResult(fmt.Errorf("%w: %s.%s", ErrTableNotFound, schema, tableName)), nil
+	}
+
+	// A point-in-time read needs a versioned table with history back to asOf
+	if asOf != nil {
+		versioned, retentionDays, err := s.fetchTimeTravel(ctx, schema, tableName)
+		if err != nil {
+			return s.dbErrorResult(ErrCheckingTable, err), nil
+		}
+		if err := validateAsOf(*asOf, versioned, retentionDays); err != nil {
+			return toolErrorResult(err), nil
+		}
 	}
 
 	// Get columns
 	columns, err := s.getTableColumns(ctx, schema, tableName)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrRetrievingColumns, err).Error()), nil
+		return s.dbErrorResult(ErrRetrievingColumns, err), nil
 	}
 	if len(columns) == 0 {
-		return mcp.NewToolResultError(ErrNoColumnsFound.Error()), nil
+		return toolErrorResult(ErrNoColumnsFound), nil
 	}
 
 	// Pagination
-	pagination := GetPaginationParams(args, 50, MaxRowsPageSize)
+	pagination := newPaginationParams(args.Page, args.PageSize, 50, MaxRowsPageSize)
 
 	// Sorting
-	orderBy, _ := getStringArg(args, "order_by")
+	orderBy := args.OrderBy
 	orderDirection := "ASC"
-	if dir, ok := getStringArg(args, "order_direction"); ok {
-		dir = strings.ToUpper(dir)
-		if dir == "DESC" || dir == "ASC" {
-			orderDirection = dir
-		}
+	if dir := strings.ToUpper(args.OrderDirection); dir == "DESC" || dir == "ASC" {
+		orderDirection = dir
 	}
 
-	// Validate orderBy column
+	// The primary key breaks ties, so pages do not overlap or skip rows between calls
+	pkQuery, pkArgs := s.queryBuilder.GetPrimaryKeyQuery(schema, tableName)
+	primaryKey, err := s.fetchPrimaryKey(ctx, pkQuery, pkArgs)
+	if err != nil {
+		return s.dbErrorResult(ErrRetrievingColumns, err), nil
+	}
+
+	// Validate orderBy column; the default is the primary key, or the first column without one
 	if orderBy != "" {
 		if !s.columnExists(columns, orderBy) {
-			return mcp.NewToolResultError(fmt.Errorf("%w: %s", ErrColumnNotExists, orderBy).Error()), nil
+			return toolErrorResult(fmt.Errorf("%w: %s", ErrColumnNotExists, orderBy)), nil
 		}
-	} else if len(columns) > 0 {
+	} else if len(primaryKey) > 0 {
+		orderBy = primaryKey[0]
+	} else {
 		orderBy = columns[0]
 	}
 
 	// Build WHERE clause from filters
-	whereClauses, queryParams, err := s.buildWhereClause(args, columns)
+	whereClauses, queryParams, err := s.buildWhereClause(args.Filters, columns)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
 	whereClause := ""
@@ -367,16 +339,23 @@ func (s *DbMCPServer) handleListTableRows(ctx context.Context, request mcp.CallT
 		whereClause = "WHERE " + strings.Join(whereClauses, " AND ")
 	}
 
+	ctx, watch := s.watchdog.Watch(ctx, "list_table_rows", s.queryBuilder.QualifyTable(schema, tableName)+" "+whereClause)
+	defer watch.Done()
+
 	// Count total rows
-	totalCount, err := s.countRows(ctx, schema, tableName, whereClause, queryParams)
+	totalCount, err := s.countRows(ctx, schema, tableName, whereClause, asOf, queryParams)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrCountingRows, err).Error()), nil
+		return s.dbErrorResult(ErrCountingRows, watch.Cause(err)), nil
 	}
 
 	// Fetch rows
-	rows, err := s.fetchRows(ctx, schema, tableName, columns, whereClause, orderBy, orderDirection, pagination, queryParams)
+	budget := s.newResultBudget()
+	rows, err := s.fetchRows(ctx, schema, tableName, columns, whereClause, orderBy, orderDirection, primaryKey, asOf, pagination, queryParams, budget)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrFetchingRows, err).Error()), nil
+		return s.dbErrorResult(ErrFetchingRows, watch.Cause(err)), nil
+	}
+	if err = watch.Err(); err != nil {
+		return toolErrorResult(err), nil
 	}
 
 	totalPages := (totalCount + pagination.PageSize - 1) / pagination.PageSize
@@ -400,15 +379,15 @@ func (s *DbMCPServer) handleListTableRows(ctx context.Context, request mcp.CallT
 			"name":            tableName,
 			"order_by":        orderBy,
 			"order_direction": orderDirection,
+			"tie_breakers":    primaryKey,
 		},
 	}
-
-	jsonData, err := json.MarshalIndent(response, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
+	if asOf != nil {
+		response["table"].(map[string]interface{})["as_of"] = asOf.UTC().Format(time.RFC3339Nano)
 	}
+	budget.Annotate(response)
 
-	return mcp.NewToolResultText(string(jsonData)), nil
+	return jsonToolResult(response), nil
 }
 
 func (s *DbMCPServer) tableExists(ctx context.Context, schema, tableName string) (bool, error) {
@@ -419,6 +398,25 @@ func (s *DbMCPServer) tableExists(ctx context.Context, schema, tableName string)
 }
 
 func (s *DbMCPServer) getTableColumns(ctx context.Context, schema, tableName string) ([]string, error) {
+	columnTypes, err := s.getTableColumnTypes(ctx, schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+	var columns []string
+	for _, column := range columnTypes {
+		columns = append(columns, column.Name)
+	}
+	return columns, nil
+}
+
+// tableColumnType is a column of a table with its declared data type
+type tableColumnType struct {
+	Name     string
+	DataType string
+}
+
+// getTableColumnTypes returns the columns of a table with their data types, in order
+func (s *DbMCPServer) getTableColumnTypes(ctx context.Context, schema, tableName string) ([]tableColumnType, error) {
 	query, args := s.queryBuilder.GetTableColumnsQuery(schema, tableName)
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -426,7 +424,7 @@ func (s *DbMCPServer) getTableColumns(ctx context.Context, schema, tableName str
 	}
 	defer rows.Close()
 
-	var columns []string
+	var columns []tableColumnType
 	if s.queryBuilder.IsSQLite() {
 		for rows.Next() {
 			var cid int
@@ -436,7 +434,7 @@ func (s *DbMCPServer) getTableColumns(ctx context.Context, schema, tableName str
 			if err := rows.Scan(&cid, &name, &dataType, &notNull, &dfltValue, &pk); err != nil {
 				continue
 			}
-			columns = append(columns, name)
+			columns = append(columns, tableColumnType{Name: name, DataType: dataType})
 		}
 	} else {
 		for rows.Next() {
@@ -447,7 +445,7 @@ func (s *DbMCPServer) getTableColumns(ctx context.Context, schema, tableName str
 			if err := rows.Scan(&columnName, &dataType, &maxLength, &isNullable, &colDefault); err != nil {
 				continue
 			}
-			columns = append(columns, columnName)
+			columns = append(columns, tableColumnType{Name: columnName, DataType: dataType})
 		}
 	}
 	return columns, nil
@@ -462,24 +460,18 @@ func (s *DbMCPServer) columnExists(columns []string, name string) bool {
 	return false
 }
 
-func (s *DbMCPServer) buildWhereClause(args map[string]interface{}, columns []string) ([]string, []interface{}, error) {
+func (s *DbMCPServer) buildWhereClause(filters []rowFilter, columns []string) ([]string, []interface{}, error) {
+	return s.buildWhereClauseFrom(filters, columns, 1)
+}
+
+// buildWhereClauseFrom builds the conditions of the filters with placeholders numbered from
+// paramIndex, after the placeholders that precede them in the statement
+func (s *DbMCPServer) buildWhereClauseFrom(filters []rowFilter, columns []string, paramIndex int) ([]string, []interface{}, error) {
 	var whereClauses []string
 	var queryParams []interface{}
-	paramIndex := 1
-
-	filters, ok := args["filters"].([]interface{})
-	if !ok {
-		return whereClauses, queryParams, nil
-	}
-
-	for _, filterInterface := range filters {
-		filter, ok := filterInterface.(map[string]interface{})
-		if !ok {
-			continue
-		}
 
-		column, _ := filter["column"].(string)
-		operator, _ := filter["operator"].(string)
+	for _, filter := range filters {
+		column, operator := filter.Column, filter.Operator
 
 		if column == "" || operator == "" {
 			continue
@@ -498,30 +490,30 @@ func (s *DbMCPServer) buildWhereClause(args map[string]interface{}, columns []st
 		switch operator {
 		case "eq":
 			whereClauses = append(whereClauses, fmt.Sprintf("%s = %s", quotedColumn, placeholder))
-			queryParams = append(queryParams, filter["value"])
+			queryParams = append(queryParams, filter.Value)
 			paramIndex++
 		case "neq":
 			whereClauses = append(whereClauses, fmt.Sprintf("%s != %s", quotedColumn, placeholder))
-			queryParams = append(queryParams, filter["value"])
+			queryParams = append(queryParams, filter.Value)
 			paramIndex++
 		case "gt":
 			whereClauses = append(whereClauses, fmt.Sprintf("%s > %s", quotedColumn, placeholder))
-			queryParams = append(queryParams, filter["value"])
+			queryParams = append(queryParams, filter.Value)
 			paramIndex++
 		case "gte":
 			whereClauses = append(whereClauses, fmt.Sprintf("%s >= %s", quotedColumn, placeholder))
-			queryParams = append(queryParams, filter["value"])
+			queryParams = append(queryParams, filter.Value)
 			paramIndex++
 		case "lt":
 			whereClauses = append(whereClauses, fmt.Sprintf("%s < %s", quotedColumn, placeholder))
-			queryParams = append(queryParams, filter["value"])
+			queryParams = append(queryParams, filter.Value)
 			paramIndex++
 		case "lte":
 			whereClauses = append(whereClauses, fmt.Sprintf("%s <= %s", quotedColumn, placeholder))
-			queryParams = append(queryParams, filter["value"])
+			queryParams = append(queryParams, filter.Value)
 			paramIndex++
 		case "contains":
-			value, ok := filter["value"].(string)
+			value, ok := filter.Value.(string)
 			if !ok {
 				return nil, nil, ErrContainsRequiresString
 			}
@@ -529,7 +521,7 @@ func (s *DbMCPServer) buildWhereClause(args map[string]interface{}, columns []st
 			queryParams = append(queryParams, "%"+value+"%")
 			paramIndex++
 		case "starts_with":
-			value, ok := filter["value"].(string)
+			value, ok := filter.Value.(string)
 			if !ok {
 				return nil, nil, ErrStartsWithRequiresString
 			}
@@ -537,7 +529,7 @@ func (s *DbMCPServer) buildWhereClause(args map[string]interface{}, columns []st
 			queryParams = append(queryParams, value+"%")
 			paramIndex++
 		case "ends_with":
-			value, ok := filter["value"].(string)
+			value, ok := filter.Value.(string)
 			if !ok {
 				return nil, nil, ErrEndsWithRequiresString
 			}
@@ -556,15 +548,15 @@ func (s *DbMCPServer) buildWhereClause(args map[string]interface{}, columns []st
 	return whereClauses, queryParams, nil
 }
 
-func (s *DbMCPServer) countRows(ctx context.Context, schema, tableName, whereClause string, params []interface{}) (int, error) {
-	query := s.queryBuilder.BuildCountQuery(schema, tableName, whereClause)
+func (s *DbMCPServer) countRows(ctx context.Context, schema, tableName, whereClause string, asOf *time.Time, params []interface{}) (int, error) {
+	query := s.queryBuilder.BuildCountQueryAsOf(schema, tableName, whereClause, asOf)
 
 	var count int
 	err := s.db.QueryRowContext(ctx, query, params...).Scan(&count)
 	return count, err
 }
 
-func (s *DbMCPServer) fetchRows(ctx context.Context, schema, tableName string, columns []string, whereClause, orderBy, orderDirection string, pagination PaginationParams, params []interface{}) ([]map[string]interface{}, error) {
+func (s *DbMCPServer) fetchRows(ctx context.Context, schema, tableName string, columns []string, whereClause, orderBy, orderDirection string, tieBreakers []string, asOf *time.Time, pagination PaginationParams, params []interface{}, budget *resultBudget) (*ResultSet, error) {
 	query := s.queryBuilder.BuildSelectQuery(SelectQueryParams{
 		Schema:         schema,
 		Table:          tableName,
@@ -572,6 +564,8 @@ func (s *DbMCPServer) fetchRows(ctx context.Context, schema, tableName string, c
 		WhereClause:    whereClause,
 		OrderBy:        orderBy,
 		OrderDirection: orderDirection,
+		TieBreakers:    tieBreakers,
+		AsOf:           asOf,
 		Limit:          pagination.PageSize,
 		Offset:         pagination.Offset,
 	})
@@ -582,82 +576,70 @@ func (s *DbMCPServer) fetchRows(ctx context.Context, schema, tableName string, c
 	}
 	defer dbRows.Close()
 
-	var rows []map[string]interface{}
-	for dbRows.Next() {
-		values := make([]interface{}, len(columns))
-		valuePtrs := make([]interface{}, len(columns))
-		for i := range values {
-			valuePtrs[i] = &values[i]
-		}
+	watch := watchedQueryFrom(ctx)
+	rows := newResultSet(columns)
+	encoder := newResultEncoder(dbRows)
 
+	values := make([]interface{}, len(columns))
+	valuePtrs := make([]interface{}, len(columns))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+
+	for dbRows.Next() {
 		if err = dbRows.Scan(valuePtrs...); err != nil {
 			continue
 		}
 
-		row := make(map[string]interface{})
-		for i, col := range columns {
-			row[col] = formatValue(values[i])
+		encoder.encode(values)
+		if !budget.Add(values) {
+			break
 		}
-		rows = append(rows, row)
+		rows.AppendRow(values)
+		watch.Row()
 	}
 
 	return rows, nil
 }
 
+// getTableSchemaFullArgs are the arguments of get_table_schema_full
+type getTableSchemaFullArgs struct {
+	TableName string `json:"table_name" jsonschema_description:"Table name"`
+	Schema    string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+}
+
 func (s *DbMCPServer) toolGetTableSchemaFull() (mcp.Tool, server.ToolHandlerFunc) {
-	return mcp.Tool{
-		Name:        "get_table_schema_full",
-		Description: "Returns complete information about a table's structure, including columns, indexes, foreign keys, and constraints",
-		InputSchema: mcp.ToolInputSchema{
-			Type: "object",
-			Properties: map[string]interface{}{
-				"table_name": map[string]interface{}{
-					"type":        "string",
-					"description": "Table name",
-				},
-				"schema": map[string]interface{}{
-					"type":        "string",
-					"description": "Schema name (optional)",
-				},
-			},
-			Required: []string{"table_name"},
-		},
-	}, s.handleGetTableSchemaFull
+	return newTypedTool("get_table_schema_full", "Returns complete information about a table's structure, including columns, indexes, foreign keys, and constraints", s.handleGetTableSchemaFull)
 }
 
-func (s *DbMCPServer) handleGetTableSchemaFull(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *DbMCPServer) handleGetTableSchemaFull(ctx context.Context, request mcp.CallToolRequest, args getTableSchemaFullArgs) (*mcp.CallToolResult, error) {
 	if err := s.requireConnection(); err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
-	args, ok := getArgs(request.Params.Arguments)
-	if !ok {
-		return mcp.NewToolResultError(ErrInvalidArguments.Error()), nil
-	}
-
-	tableName, ok := getStringArg(args, "table_name")
-	if !ok || !isValidIdentifier(tableName) {
-		return mcp.NewToolResultError(ErrInvalidTableName.Error()), nil
+	tableName := args.TableName
+	if !isValidIdentifier(tableName) {
+		return toolErrorResult(ErrInvalidTableName), nil
 	}
 
 	defaultSchema := getDefaultSchema(s.queryBuilder.GetDriver())
-	schema, err := getValidSchema(args, defaultSchema)
+	schema, err := getValidSchema(args.Schema, defaultSchema)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, DefaultQueryTimeout)
+	ctx, cancel := withQueryTimeout(ctx, DefaultQueryTimeout)
 	defer cancel()
 
 	// Get columns
 	columnsQuery, columnsArgs := s.queryBuilder.GetTableSchemaFullQuery(schema, tableName)
 	columns, err := s.fetchSchemaColumns(ctx, columnsQuery, columnsArgs)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrRetrievingColumns, err).Error()), nil
+		return s.dbErrorResult(ErrRetrievingColumns, err), nil
 	}
 
 	if len(columns) == 0 {
-		return mcp.NewToolResultError(fmt.Errorf("%w: %s.%s", ErrTableNotFound, schema, tableName).Error()), nil
+		return toolErrorResult(fmt.Errorf("%w: %s.%s", ErrTableNotFound, schema, tableName)), nil
 	}
 
 	// Get indexes
@@ -681,12 +663,7 @@ func (s *DbMCPServer) handleGetTableSchemaFull(ctx context.Context, request mcp.
 		"foreign_keys": foreignKeys,
 	}
 
-	jsonData, err := json.MarshalIndent(response, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
-	}
-
-	return mcp.NewToolResultText(string(jsonData)), nil
+	return jsonToolResult(response), nil
 }
 
 func (s *DbMCPServer) fetchSchemaColumns(ctx context.Context, query string, args []interface{}) ([]map[string]interface{}, error) {
@@ -891,3 +868,290 @@ func (s *DbMCPServer) fetchPrimaryKey(ctx context.Context, query string, args []
 
 	return pkColumns, nil
 }
+
+// getTableStatsArgs are the arguments of get_table_stats
+type getTableStatsArgs struct {
+	TableName string `json:"table_name,omitempty" jsonschema_description:"Only statistics of this table (optional)"`
+	Schema    string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	pageArgs
+}
+
+func (s *DbMCPServer) toolGetTableStats() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("get_table_stats", "Returns approximate row count, data size and index size per table from catalog statistics, without scanning the tables", s.handleGetTableStats)
+}
+
+func (s *DbMCPServer) handleGetTableStats(ctx context.Context, request mcp.CallToolRequest, args getTableStatsArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	schema, err := getValidSchema(args.Schema, "")
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	tableName := args.TableName
+	if tableName != "" && !isValidIdentifier(tableName) {
+		return toolErrorResult(ErrInvalidTableName), nil
+	}
+
+	pagination := args.pagination()
+
+	query, queryArgs, ok := s.queryBuilder.TableStatsQuery(schema, tableName, pagination.PageSize, pagination.Offset)
+	if !ok {
+		return toolErrorResult(ErrFeatureNotSupported), nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	tables, err := s.fetchTableStats(ctx, query, queryArgs)
+	if err != nil {
+		return s.dbErrorResult(ErrFetchingTableStats, err), nil
+	}
+
+	response := map[string]interface{}{
+		"tables":      tables,
+		"approximate": true,
+		"pagination": map[string]interface{}{
+			"page":      pagination.Page,
+			"page_size": pagination.PageSize,
+			"count":     len(tables),
+		},
+		"filter": map[string]interface{}{
+			"schema": schema,
+			"table":  tableName,
+		},
+	}
+
+	return jsonToolResult(response), nil
+}
+
+// fetchTableStats returns the rows of a table statistics query. Sizes missing from the
+// catalog statistics are null.
+func (s *DbMCPServer) fetchTableStats(ctx context.Context, query string, args []interface{}) ([]map[string]interface{}, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []map[string]interface{}
+	for rows.Next() {
+		var tableSchema, name string
+		var rowCount, dataBytes, indexBytes sql.NullInt64
+		if err := rows.Scan(&tableSchema, &name, &rowCount, &dataBytes, &indexBytes); err != nil {
+			continue
+		}
+
+		table := map[string]interface{}{
+			"schema":      tableSchema,
+			"name":        name,
+			"row_count":   nil,
+			"data_bytes":  nil,
+			"index_bytes": nil,
+			"total_bytes": nil,
+		}
+		if rowCount.Valid {
+			table["row_count"] = rowCount.Int64
+		}
+		if dataBytes.Valid {
+			table["data_bytes"] = dataBytes.Int64
+		}
+		if indexBytes.Valid {
+			table["index_bytes"] = indexBytes.Int64
+		}
+		if dataBytes.Valid && indexBytes.Valid {
+			table["total_bytes"] = dataBytes.Int64 + indexBytes.Int64
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
+
+// listPartitionsArgs are the arguments of list_partitions
+type listPartitionsArgs struct {
+	TableName string `json:"table_name" jsonschema_description:"Table name"`
+	Schema    string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+}
+
+func (s *DbMCPServer) toolListPartitions() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("list_partitions", "Lists the partitions of a table with their bounds, partition scheme/function, approximate row counts and the partition key columns", s.handleListPartitions)
+}
+
+func (s *DbMCPServer) handleListPartitions(ctx context.Context, request mcp.CallToolRequest, args listPartitionsArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	tableName := args.TableName
+	if !isValidIdentifier(tableName) {
+		return toolErrorResult(ErrInvalidTableName), nil
+	}
+
+	schema, err := getValidSchema(args.Schema, "")
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	query, queryArgs, ok := s.queryBuilder.ListPartitionsQuery(schema, tableName)
+	if !ok {
+		return toolErrorResult(ErrFeatureNotSupported), nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return s.dbErrorResult(ErrListingPartitions, err), nil
+	}
+	defer rows.Close()
+
+	var partitions []map[string]interface{}
+	var strategy, scheme, function interface{}
+	for rows.Next() {
+		var tableSchema, name string
+		var position, rowCount sql.NullInt64
+		var bound, schemeName, functionName, partStrategy sql.NullString
+		if err = rows.Scan(&tableSchema, &name, &position, &bound, &rowCount, &schemeName, &functionName, &partStrategy); err != nil {
+			continue
+		}
+
+		partition := map[string]interface{}{
+			"schema":    tableSchema,
+			"name":      name,
+			"position":  nil,
+			"bound":     nil,
+			"row_count": nil,
+		}
+		if position.Valid {
+			partition["position"] = position.Int64
+		}
+		if bound.Valid {
+			partition["bound"] = bound.String
+		}
+		if rowCount.Valid {
+			partition["row_count"] = rowCount.Int64
+		}
+		if partStrategy.Valid {
+			strategy = partStrategy.String
+		}
+		if schemeName.Valid {
+			scheme = schemeName.String
+		}
+		if functionName.Valid {
+			function = functionName.String
+		}
+		partitions = append(partitions, partition)
+	}
+	rows.Close()
+
+	keyQuery, keyArgs := s.queryBuilder.PartitionKeysQuery(schema, tableName)
+	keyRows, err := s.db.QueryContext(ctx, keyQuery, keyArgs...)
+	if err != nil {
+		return s.dbErrorResult(ErrListingPartitions, err), nil
+	}
+	defer keyRows.Close()
+
+	var keyColumns []string
+	for keyRows.Next() {
+		var tableSchema, column string
+		if err = keyRows.Scan(&tableSchema, &column); err != nil {
+			continue
+		}
+		keyColumns = append(keyColumns, column)
+	}
+
+	response := map[string]interface{}{
+		"table":       tableName,
+		"schema":      schema,
+		"partitioned": strategy != nil,
+		"strategy":    strategy,
+		"scheme":      scheme,
+		"function":    function,
+		"key_columns": keyColumns,
+		"partitions":  partitions,
+		"count":       len(partitions),
+	}
+
+	return jsonToolResult(response), nil
+}
+
+// estimateRowCountsArgs are the arguments of estimate_row_counts
+type estimateRowCountsArgs struct {
+	Schema string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+}
+
+func (s *DbMCPServer) toolEstimateRowCounts() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("estimate_row_counts", "Returns the approximate row count of every table of a schema from catalog statistics in a single query. Use it instead of running COUNT(*) on each table, which scans them and can time out", s.handleEstimateRowCounts)
+}
+
+func (s *DbMCPServer) handleEstimateRowCounts(ctx context.Context, request mcp.CallToolRequest, args estimateRowCountsArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	schema, err := getValidSchema(args.Schema, "")
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	query, queryArgs, ok := s.queryBuilder.EstimatedRowCountsQuery(schema)
+	if !ok {
+		return toolErrorResult(ErrFeatureNotSupported), nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return s.dbErrorResult(ErrFetchingTableStats, err), nil
+	}
+	defer rows.Close()
+
+	tables := []map[string]interface{}{}
+	var totalRows int64
+	unknown := 0
+	for rows.Next() {
+		var tableSchema, name string
+		var rowCount sql.NullInt64
+		if err = rows.Scan(&tableSchema, &name, &rowCount); err != nil {
+			continue
+		}
+
+		table := map[string]interface{}{
+			"schema":    tableSchema,
+			"name":      name,
+			"row_count": nil,
+		}
+		if rowCount.Valid {
+			table["row_count"] = rowCount.Int64
+			totalRows += rowCount.Int64
+		} else {
+			unknown++
+		}
+		tables = append(tables, table)
+	}
+	if err = rows.Err(); err != nil {
+		return s.dbErrorResult(ErrFetchingTableStats, err), nil
+	}
+
+	response := map[string]interface{}{
+		"tables":      tables,
+		"count":       len(tables),
+		"total_rows":  totalRows,
+		"approximate": true,
+		"filter": map[string]interface{}{
+			"schema": schema,
+		},
+	}
+	// Tables never analyzed have no estimate
+	if unknown > 0 {
+		response["unknown"] = unknown
+		response["message"] = translate("Some tables have no row count estimate until their statistics are gathered")
+	}
+
+	return jsonToolResult(response), nil
+}
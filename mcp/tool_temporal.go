@@ -0,0 +1,140 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// temporalTable is a system-versioned table with the history table keeping its old rows
+type temporalTable struct {
+	Source        string `json:"source"`
+	Schema        string `json:"schema"`
+	Table         string `json:"table"`
+	HistorySchema string `json:"history_schema,omitempty"`
+	HistoryTable  string `json:"history_table"`
+	PeriodStart   string `json:"period_start,omitempty"`
+	PeriodEnd     string `json:"period_end,omitempty"`
+	Retention     string `json:"retention"`
+}
+
+// listTemporalTablesArgs are the arguments of list_temporal_tables
+type listTemporalTablesArgs struct {
+	Schema    string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	TableName string `json:"table_name,omitempty" jsonschema_description:"Filter by table name (optional)"`
+}
+
+func (s *DbMCPServer) toolListTemporalTables() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("list_temporal_tables", "Lists the system-versioned (temporal) tables with their history table, period columns and history retention: SQL Server temporal tables, and PostgreSQL tables versioned by the temporal_tables or periods extensions. Use before answering point-in-time questions", s.handleListTemporalTables)
+}
+
+func (s *DbMCPServer) handleListTemporalTables(ctx context.Context, request mcp.CallToolRequest, args listTemporalTablesArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	schema, err := getValidSchema(args.Schema, "")
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	tableName := args.TableName
+	if tableName != "" && !isValidIdentifier(tableName) {
+		return toolErrorResult(ErrInvalidTableName), nil
+	}
+
+	sourcesQuery, ok := s.queryBuilder.TemporalSourcesQuery()
+	if !ok {
+		return toolErrorResult(ErrFeatureNotSupported), nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	sources, err := s.fetchTemporalSources(ctx, sourcesQuery)
+	if err != nil {
+		return s.dbErrorResult(ErrListingTemporalTables, err), nil
+	}
+
+	tables := []temporalTable{}
+	for _, source := range sources {
+		query, queryArgs, ok := s.queryBuilder.ListTemporalTablesQuery(source, schema, tableName)
+		if !ok {
+			continue
+		}
+		sourceTables, err := s.fetchTemporalTables(ctx, source, query, queryArgs)
+		if err != nil {
+			return s.dbErrorResult(ErrListingTemporalTables, err), nil
+		}
+		tables = append(tables, sourceTables...)
+	}
+
+	response := map[string]interface{}{
+		"tables":  tables,
+		"count":   len(tables),
+		"sources": sources,
+		"filter": map[string]interface{}{
+			"schema": schema,
+			"table":  tableName,
+		},
+	}
+	if len(sources) == 0 {
+		response["message"] = translate("No temporal table implementation is available on this connection")
+	}
+
+	return jsonToolResult(response), nil
+}
+
+// fetchTemporalSources returns the temporal table implementations available on the connection
+func (s *DbMCPServer) fetchTemporalSources(ctx context.Context, query string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sources := []string{}
+	for rows.Next() {
+		var source string
+		if err := rows.Scan(&source); err != nil {
+			continue
+		}
+		sources = append(sources, source)
+	}
+	return sources, rows.Err()
+}
+
+// fetchTemporalTables returns the temporal tables of an implementation
+func (s *DbMCPServer) fetchTemporalTables(ctx context.Context, source, query string, args []interface{}) ([]temporalTable, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []temporalTable
+	for rows.Next() {
+		var tableSchema, name string
+		var historySchema, historyTable, periodStart, periodEnd, retention sql.NullString
+		if err := rows.Scan(&tableSchema, &name, &historySchema, &historyTable, &periodStart, &periodEnd, &retention); err != nil {
+			continue
+		}
+		table := temporalTable{
+			Source:        source,
+			Schema:        tableSchema,
+			Table:         name,
+			HistorySchema: historySchema.String,
+			HistoryTable:  historyTable.String,
+			PeriodStart:   periodStart.String,
+			PeriodEnd:     periodEnd.String,
+			Retention:     retention.String,
+		}
+		if !retention.Valid {
+			table.Retention = "unlimited"
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
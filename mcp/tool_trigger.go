@@ -3,77 +3,47 @@ package mcp
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
-	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// listTriggersArgs are the arguments of list_triggers
+type listTriggersArgs struct {
+	TableName  string `json:"table_name,omitempty" jsonschema_description:"Table name (optional, if not specified, lists all)"`
+	Schema     string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	NameFilter string `json:"name_filter,omitempty" jsonschema_description:"Filter by trigger name (optional)"`
+	pageArgs
+	IncludeDisabled *bool `json:"include_disabled,omitempty" jsonschema_description:"Include disabled triggers (default: true)"`
+}
+
 func (s *DbMCPServer) toolListTriggers() (mcp.Tool, server.ToolHandlerFunc) {
-	return mcp.Tool{
-		Name:        "list_triggers",
-		Description: "List database triggers with pagination",
-		InputSchema: mcp.ToolInputSchema{
-			Type: "object",
-			Properties: map[string]interface{}{
-				"table_name": map[string]interface{}{
-					"type":        "string",
-					"description": "Table name (optional, if not specified, lists all)",
-				},
-				"schema": map[string]interface{}{
-					"type":        "string",
-					"description": "Schema name (optional)",
-				},
-				"name_filter": map[string]interface{}{
-					"type":        "string",
-					"description": "Filter by trigger name (optional)",
-				},
-				"page": map[string]interface{}{
-					"type":        "number",
-					"description": "Page number (default: 1)",
-				},
-				"page_size": map[string]interface{}{
-					"type":        "number",
-					"description": "Items per page (default: 100, maximum: 500)",
-				},
-				"include_disabled": map[string]interface{}{
-					"type":        "boolean",
-					"description": "Include disabled triggers (default: true)",
-				},
-			},
-		},
-	}, s.handleListTriggers
+	return newTypedTool("list_triggers", "List database triggers with pagination", s.handleListTriggers)
 }
 
-func (s *DbMCPServer) handleListTriggers(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *DbMCPServer) handleListTriggers(ctx context.Context, request mcp.CallToolRequest, args listTriggersArgs) (*mcp.CallToolResult, error) {
 	if err := s.requireConnection(); err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
-	args, ok := getArgs(request.Params.Arguments)
-	if !ok {
-		return mcp.NewToolResultError(ErrInvalidArguments.Error()), nil
-	}
-
-	schema, err := getValidSchema(args, "")
+	schema, err := getValidSchema(args.Schema, "")
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
-	tableName, _ := getStringArg(args, "table_name")
-	nameFilter, _ := getStringArg(args, "name_filter")
-	includeDisabled := getBoolArg(args, "include_disabled", true)
-	pagination := GetPaginationParams(args, DefaultPageSize, MaxPageSize)
+	tableName := args.TableName
+	nameFilter := args.NameFilter
+	includeDisabled := boolArg(args.IncludeDisabled, true)
+	pagination := args.pagination()
 
 	query, queryArgs := s.queryBuilder.ListTriggersQuery(schema, tableName, nameFilter, includeDisabled, pagination.PageSize, pagination.Offset)
 
-	ctx, cancel := context.WithTimeout(ctx, ShortQueryTimeout)
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
 	defer cancel()
 
 	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrListingTriggers, err).Error()), nil
+		return s.dbErrorResult(ErrListingTriggers, err), nil
 	}
 	defer rows.Close()
 
@@ -117,73 +87,52 @@ func (s *DbMCPServer) handleListTriggers(ctx context.Context, request mcp.CallTo
 		},
 	}
 
-	jsonData, err := json.MarshalIndent(response, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
-	}
+	return jsonToolResult(response), nil
+}
 
-	return mcp.NewToolResultText(string(jsonData)), nil
+// getTriggerCodeArgs are the arguments of get_trigger_code
+type getTriggerCodeArgs struct {
+	TriggerName string `json:"trigger_name" jsonschema_description:"Trigger name"`
+	Schema      string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
 }
 
 func (s *DbMCPServer) toolGetTriggerCode() (mcp.Tool, server.ToolHandlerFunc) {
-	return mcp.Tool{
-		Name:        "get_trigger_code",
-		Description: "Returns the complete source code of a trigger",
-		InputSchema: mcp.ToolInputSchema{
-			Type: "object",
-			Properties: map[string]interface{}{
-				"trigger_name": map[string]interface{}{
-					"type":        "string",
-					"description": "Trigger name",
-				},
-				"schema": map[string]interface{}{
-					"type":        "string",
-					"description": "Schema name (optional)",
-				},
-			},
-			Required: []string{"trigger_name"},
-		},
-	}, s.handleGetTriggerCode
+	return newTypedTool("get_trigger_code", "Returns the complete source code of a trigger", s.handleGetTriggerCode)
 }
 
-func (s *DbMCPServer) handleGetTriggerCode(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *DbMCPServer) handleGetTriggerCode(ctx context.Context, request mcp.CallToolRequest, args getTriggerCodeArgs) (*mcp.CallToolResult, error) {
 	if err := s.requireConnection(); err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
-	}
-
-	args, ok := getArgs(request.Params.Arguments)
-	if !ok {
-		return mcp.NewToolResultError(ErrInvalidArguments.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
-	triggerName, ok := getStringArg(args, "trigger_name")
-	if !ok || !isValidIdentifier(triggerName) {
-		return mcp.NewToolResultError(ErrInvalidTriggerName.Error()), nil
+	triggerName := args.TriggerName
+	if !isValidIdentifier(triggerName) {
+		return toolErrorResult(ErrInvalidTriggerName), nil
 	}
 
 	defaultSchema := getDefaultSchema(s.queryBuilder.GetDriver())
-	schema, err := getValidSchema(args, defaultSchema)
+	schema, err := getValidSchema(args.Schema, defaultSchema)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
 	query, queryArgs := s.queryBuilder.GetTriggerCodeQuery(schema, triggerName)
 
-	ctx, cancel := context.WithTimeout(ctx, ShortQueryTimeout)
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
 	defer cancel()
 
 	var definition sql.NullString
 	err = s.db.QueryRowContext(ctx, query, queryArgs...).Scan(&definition)
 
 	if err == sql.ErrNoRows {
-		return mcp.NewToolResultError(ErrTriggerNotFound.Error()), nil
+		return toolErrorResult(ErrTriggerNotFound), nil
 	}
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrRetrievingTrigger, err).Error()), nil
+		return s.dbErrorResult(ErrRetrievingTrigger, err), nil
 	}
 
 	if !definition.Valid || definition.String == "" {
-		return mcp.NewToolResultError(ErrCodeNotAvailable.Error()), nil
+		return toolErrorResult(ErrCodeNotAvailable), nil
 	}
 
 	response := map[string]interface{}{
@@ -192,10 +141,5 @@ func (s *DbMCPServer) handleGetTriggerCode(ctx context.Context, request mcp.Call
 		"definition": definition.String,
 	}
 
-	jsonData, err := json.MarshalIndent(response, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
-	}
-
-	return mcp.NewToolResultText(string(jsonData)), nil
+	return jsonToolResult(response), nil
 }
@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// typeColumn is a column of a table, composite or object type
+type typeColumn struct {
+	Name       string `json:"name"`
+	DataType   string `json:"data_type"`
+	IsNullable bool   `json:"is_nullable"`
+}
+
+// typeUsage is a routine parameter or return value declared with a type
+type typeUsage struct {
+	Schema    string `json:"schema"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Parameter string `json:"parameter,omitempty"`
+	IsReturn  bool   `json:"is_return,omitempty"`
+}
+
+// listTypesArgs are the arguments of list_types
+type listTypesArgs struct {
+	Schema     string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	NameFilter string `json:"name_filter,omitempty" jsonschema_description:"Filter by type name (optional)"`
+	pageArgs
+}
+
+func (s *DbMCPServer) toolListTypes() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("list_types", "List user-defined types with their definitions, the columns of table-valued parameter, composite and object types, and the functions and procedures whose parameters or return values use them: SQL Server table and alias types, PostgreSQL composite types, domains and enums, Oracle object and collection types", s.handleListTypes)
+}
+
+func (s *DbMCPServer) handleListTypes(ctx context.Context, request mcp.CallToolRequest, args listTypesArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	schema, err := getValidSchema(args.Schema, "")
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	nameFilter := args.NameFilter
+	pagination := args.pagination()
+
+	query, queryArgs, ok := s.queryBuilder.ListTypesQuery(schema, nameFilter, pagination.PageSize, pagination.Offset)
+	if !ok {
+		return toolErrorResult(ErrFeatureNotSupported), nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return s.dbErrorResult(ErrListingTypes, err), nil
+	}
+	defer rows.Close()
+
+	var types []map[string]interface{}
+	byName := make(map[string]map[string]interface{})
+	for rows.Next() {
+		var schemaName, typeName, kind string
+		var definition sql.NullString
+		var isNullable bool
+
+		if err = rows.Scan(&schemaName, &typeName, &kind, &definition, &isNullable); err != nil {
+			continue
+		}
+
+		userType := map[string]interface{}{
+			"schema":      schemaName,
+			"name":        typeName,
+			"kind":        kind,
+			"is_nullable": isNullable,
+		}
+		if definition.Valid {
+			userType["definition"] = definition.String
+		}
+		types = append(types, userType)
+		byName[schemaName+"."+typeName] = userType
+	}
+	if err = rows.Err(); err != nil {
+		return s.dbErrorResult(ErrListingTypes, err), nil
+	}
+
+	// Columns and usages are attached to the types of the current page
+	if len(types) > 0 {
+		if err = s.attachTypeColumns(ctx, byName, schema, nameFilter); err != nil {
+			return s.dbErrorResult(ErrListingTypes, err), nil
+		}
+		if err = s.attachTypeUsage(ctx, byName, schema, nameFilter); err != nil {
+			return s.dbErrorResult(ErrListingTypes, err), nil
+		}
+	}
+
+	response := map[string]interface{}{
+		"types": types,
+		"pagination": map[string]interface{}{
+			"page":      pagination.Page,
+			"page_size": pagination.PageSize,
+			"count":     len(types),
+		},
+		"filter": map[string]interface{}{
+			"schema":      schema,
+			"name_filter": nameFilter,
+		},
+	}
+
+	return jsonToolResult(response), nil
+}
+
+// attachTypeColumns adds the columns of table, composite and object types to the listed types
+func (s *DbMCPServer) attachTypeColumns(ctx context.Context, byName map[string]map[string]interface{}, schema, nameFilter string) error {
+	query, queryArgs, ok := s.queryBuilder.ListTypeColumnsQuery(schema, nameFilter)
+	if !ok {
+		return nil
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var schemaName, typeName string
+		var column typeColumn
+		if err = rows.Scan(&schemaName, &typeName, &column.Name, &column.DataType, &column.IsNullable); err != nil {
+			continue
+		}
+		userType, ok := byName[schemaName+"."+typeName]
+		if !ok {
+			continue
+		}
+		columns, _ := userType["columns"].([]typeColumn)
+		userType["columns"] = append(columns, column)
+	}
+	return rows.Err()
+}
+
+// attachTypeUsage adds the routine parameters and return values declared with each type
+func (s *DbMCPServer) attachTypeUsage(ctx context.Context, byName map[string]map[string]interface{}, schema, nameFilter string) error {
+	query, queryArgs, ok := s.queryBuilder.ListTypeUsageQuery(schema, nameFilter)
+	if !ok {
+		return nil
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var schemaName, typeName string
+		var usage typeUsage
+		var parameter sql.NullString
+		if err = rows.Scan(&schemaName, &typeName, &usage.Schema, &usage.Name, &usage.Type, &parameter); err != nil {
+			continue
+		}
+		userType, ok := byName[schemaName+"."+typeName]
+		if !ok {
+			continue
+		}
+		usage.Parameter = parameter.String
+		usage.IsReturn = !parameter.Valid
+		usages, _ := userType["used_by"].([]typeUsage)
+		userType["used_by"] = append(usages, usage)
+	}
+	return rows.Err()
+}
@@ -0,0 +1,124 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// validateQueryArgs are the arguments of validate_query
+type validateQueryArgs struct {
+	Query      string      `json:"query" jsonschema_description:"SQL query to be checked"`
+	Parameters interface{} `json:"parameters,omitempty" jsonschema_description:"Values bound to the placeholders of the query, as in execute_query; SQL Server needs them to compile a query with placeholders (optional)"`
+}
+
+func (s *DbMCPServer) toolValidateQuery() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("validate_query", "Checks a query without executing it: whether execute_query would accept it, every rule it breaks with its line and column, warnings about queries that read more than needed, and whether the database can parse it and resolve its tables and columns, with the database error if not. Use it to iterate on SQL cheaply before running it", s.handleValidateQuery)
+}
+
+func (s *DbMCPServer) handleValidateQuery(ctx context.Context, request mcp.CallToolRequest, args validateQueryArgs) (*mcp.CallToolResult, error) {
+	query := args.Query
+	if query == "" {
+		return toolErrorResult(ErrQueryRequired), nil
+	}
+
+	response := map[string]interface{}{
+		"valid":            false,
+		"database_checked": false,
+	}
+
+	// Every rule the query breaks is listed, so it can be repaired at once; rule and error
+	// are those of the first error
+	result := s.newSQLValidator(query).Validate()
+	if len(result.Violations) > 0 {
+		response["violations"] = result.Violations
+	}
+	if errs := result.Errors(); len(errs) > 0 {
+		response["stage"] = "validator"
+		response["rule"] = errs[0].Rule
+		response["error"] = localizeError(fmt.Errorf("%w: %w", ErrQueryNotAllowed, result.Err()))
+		return jsonToolResult(response), nil
+	}
+
+	// Without a connection only the validator can check the query
+	if s.db == nil || s.queryBuilder == nil {
+		response["valid"] = true
+		response["message"] = translate("The query passes the validator; connect to a database to check it against the database as well")
+		return jsonToolResult(response), nil
+	}
+
+	params, err := s.bindQueryParameters(args.Parameters)
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	response["database_checked"] = true
+	response["driver"] = string(s.queryBuilder.GetDriver())
+	if err = s.parseCheck(ctx, query, params); err != nil {
+		driverType := s.queryBuilder.GetDriver()
+		response["stage"] = "database"
+		response["error"] = localizeError(fmt.Errorf("%w: %w", ErrQueryRejected, err))
+		response["category"] = string(classifyError(driverType, err))
+		if vendorErr, ok := extractVendorError(err); ok {
+			response["vendor_code"] = vendorErr.Code
+			response["message"] = vendorErr.Message
+		}
+		return jsonToolResult(response), nil
+	}
+
+	response["valid"] = true
+	return jsonToolResult(response), nil
+}
+
+// parseCheck has the database parse a query and resolve its names without running it, on
+// a dedicated connection in a read-only transaction
+func (s *DbMCPServer) parseCheck(ctx context.Context, query string, params []interface{}) error {
+	check := s.queryBuilder.ParseCheck()
+
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	// The setup statement changes the session, so its connection never returns to the pool
+	if check.Setup != "" {
+		defer discardConn(conn)
+		if _, err = conn.ExecContext(ctx, check.Setup); err != nil {
+			return err
+		}
+	} else {
+		defer conn.Close()
+	}
+
+	tx, err := s.beginReadOnly(ctx, conn)
+	if err != nil {
+		return err
+	}
+	defer endReadOnly(tx)
+
+	switch {
+	case check.Statement != "":
+		_, err = tx.ExecContext(ctx, check.Statement, query)
+	case check.Setup != "":
+		// Compile errors may follow the plan in the results
+		rows, queryErr := tx.QueryContext(ctx, query, params...)
+		if queryErr != nil {
+			return queryErr
+		}
+		for rows.Next() {
+		}
+		err = rows.Err()
+		_ = rows.Close()
+	default:
+		stmt, prepareErr := tx.PrepareContext(ctx, query)
+		if prepareErr != nil {
+			return prepareErr
+		}
+		err = stmt.Close()
+	}
+	return err
+}
@@ -3,67 +3,50 @@ package mcp
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
-	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// listViewsArgs are the arguments of list_views
+type listViewsArgs struct {
+	Schema     string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	NameFilter string `json:"name_filter,omitempty" jsonschema_description:"Filter by view name (optional)"`
+	crossDatabaseArgs
+	pageArgs
+}
+
 func (s *DbMCPServer) toolListViews() (mcp.Tool, server.ToolHandlerFunc) {
-	return mcp.Tool{
-		Name:        "list_views",
-		Description: "List database views with pagination",
-		InputSchema: mcp.ToolInputSchema{
-			Type: "object",
-			Properties: map[string]interface{}{
-				"schema": map[string]interface{}{
-					"type":        "string",
-					"description": "Schema name (optional)",
-				},
-				"name_filter": map[string]interface{}{
-					"type":        "string",
-					"description": "Filter by view name (optional)",
-				},
-				"page": map[string]interface{}{
-					"type":        "number",
-					"description": "Page number (default: 1)",
-				},
-				"page_size": map[string]interface{}{
-					"type":        "number",
-					"description": "Items per page (default: 100, maximum: 500)",
-				},
-			},
-		},
-	}, s.handleListViews
+	return newTypedTool("list_views", "List database views with pagination", s.handleListViews)
 }
 
-func (s *DbMCPServer) handleListViews(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *DbMCPServer) handleListViews(ctx context.Context, request mcp.CallToolRequest, args listViewsArgs) (*mcp.CallToolResult, error) {
 	if err := s.requireConnection(); err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
-	args, ok := getArgs(request.Params.Arguments)
-	if !ok {
-		return mcp.NewToolResultError(ErrInvalidArguments.Error()), nil
+	schema, err := getValidSchema(args.Schema, "")
+	if err != nil {
+		return toolErrorResult(err), nil
 	}
 
-	schema, err := getValidSchema(args, "")
+	database, schema, err := getTargetDatabase(args.Database, s.queryBuilder, schema)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
-	nameFilter, _ := getStringArg(args, "name_filter")
-	pagination := GetPaginationParams(args, DefaultPageSize, MaxPageSize)
+	nameFilter := args.NameFilter
+	pagination := args.pagination()
 
 	query, queryArgs := s.queryBuilder.ListViewsQuery(schema, nameFilter, pagination.PageSize, pagination.Offset)
+	query = s.queryBuilder.InDatabase(query, database)
 
-	ctx, cancel := context.WithTimeout(ctx, ShortQueryTimeout)
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
 	defer cancel()
 
 	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrListingViews, err).Error()), nil
+		return s.dbErrorResult(ErrListingViews, err), nil
 	}
 	defer rows.Close()
 
@@ -97,78 +80,58 @@ func (s *DbMCPServer) handleListViews(ctx context.Context, request mcp.CallToolR
 			"count":     len(views),
 		},
 		"filter": map[string]interface{}{
+			"database":    database,
 			"schema":      schema,
 			"name_filter": nameFilter,
 		},
 	}
 
-	jsonData, err := json.MarshalIndent(response, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
-	}
+	return jsonToolResult(response), nil
+}
 
-	return mcp.NewToolResultText(string(jsonData)), nil
+// getViewDefinitionArgs are the arguments of get_view_definition
+type getViewDefinitionArgs struct {
+	ViewName string `json:"view_name" jsonschema_description:"View name"`
+	Schema   string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
 }
 
 func (s *DbMCPServer) toolGetViewDefinition() (mcp.Tool, server.ToolHandlerFunc) {
-	return mcp.Tool{
-		Name:        "get_view_definition",
-		Description: "Returns the SQL definition of a view",
-		InputSchema: mcp.ToolInputSchema{
-			Type: "object",
-			Properties: map[string]interface{}{
-				"view_name": map[string]interface{}{
-					"type":        "string",
-					"description": "View name",
-				},
-				"schema": map[string]interface{}{
-					"type":        "string",
-					"description": "Schema name (optional)",
-				},
-			},
-			Required: []string{"view_name"},
-		},
-	}, s.handleGetViewDefinition
+	return newTypedTool("get_view_definition", "Returns the SQL definition of a view", s.handleGetViewDefinition)
 }
 
-func (s *DbMCPServer) handleGetViewDefinition(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *DbMCPServer) handleGetViewDefinition(ctx context.Context, request mcp.CallToolRequest, args getViewDefinitionArgs) (*mcp.CallToolResult, error) {
 	if err := s.requireConnection(); err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
-	args, ok := getArgs(request.Params.Arguments)
-	if !ok {
-		return mcp.NewToolResultError(ErrInvalidArguments.Error()), nil
-	}
-
-	viewName, ok := getStringArg(args, "view_name")
-	if !ok || !isValidIdentifier(viewName) {
-		return mcp.NewToolResultError(ErrInvalidViewName.Error()), nil
+	viewName := args.ViewName
+	if !isValidIdentifier(viewName) {
+		return toolErrorResult(ErrInvalidViewName), nil
 	}
 
 	defaultSchema := getDefaultSchema(s.queryBuilder.GetDriver())
-	schema, err := getValidSchema(args, defaultSchema)
+	schema, err := getValidSchema(args.Schema, defaultSchema)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return toolErrorResult(err), nil
 	}
 
 	query, queryArgs := s.queryBuilder.GetViewDefinitionQuery(schema, viewName)
 
-	ctx, cancel := context.WithTimeout(ctx, ShortQueryTimeout)
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
 	defer cancel()
 
 	var definition sql.NullString
 	err = s.db.QueryRowContext(ctx, query, queryArgs...).Scan(&definition)
 
 	if err == sql.ErrNoRows {
-		return mcp.NewToolResultError(ErrViewNotFound.Error()), nil
+		return toolErrorResult(ErrViewNotFound), nil
 	}
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrRetrievingView, err).Error()), nil
+		return s.dbErrorResult(ErrRetrievingView, err), nil
 	}
 
 	if !definition.Valid || definition.String == "" {
-		return mcp.NewToolResultError(ErrDefinitionNotAvailable.Error()), nil
+		return toolErrorResult(ErrDefinitionNotAvailable), nil
 	}
 
 	response := map[string]interface{}{
@@ -177,10 +140,92 @@ func (s *DbMCPServer) handleGetViewDefinition(ctx context.Context, request mcp.C
 		"definition": definition.String,
 	}
 
-	jsonData, err := json.MarshalIndent(response, "", "  ")
+	return jsonToolResult(response), nil
+}
+
+// listMaterializedViewsArgs are the arguments of list_materialized_views
+type listMaterializedViewsArgs struct {
+	Schema            string `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	NameFilter        string `json:"name_filter,omitempty" jsonschema_description:"Filter by materialized view name (optional)"`
+	IncludeDefinition bool   `json:"include_definition,omitempty" jsonschema_description:"Include the SQL definition of each materialized view (default: false)"`
+	pageArgs
+}
+
+func (s *DbMCPServer) toolListMaterializedViews() (mcp.Tool, server.ToolHandlerFunc) {
+	return newTypedTool("list_materialized_views", "List materialized views with refresh information and optionally their definitions (PostgreSQL and Oracle)", s.handleListMaterializedViews)
+}
+
+func (s *DbMCPServer) handleListMaterializedViews(ctx context.Context, request mcp.CallToolRequest, args listMaterializedViewsArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	if !s.queryBuilder.SupportsMaterializedViews() {
+		return toolErrorResult(ErrMaterializedViewsNotSupported), nil
+	}
+
+	schema, err := getValidSchema(args.Schema, "")
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	nameFilter := args.NameFilter
+	includeDefinition := args.IncludeDefinition
+	pagination := args.pagination()
+
+	query, queryArgs := s.queryBuilder.ListMaterializedViewsQuery(schema, nameFilter, pagination.PageSize, pagination.Offset)
+
+	ctx, cancel := withQueryTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
 	if err != nil {
-		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
+		return s.dbErrorResult(ErrListingMatViews, err), nil
+	}
+	defer rows.Close()
+
+	var views []map[string]interface{}
+	for rows.Next() {
+		var viewSchema, viewName string
+		var populated sql.NullBool
+		var refreshMode, staleness, definition sql.NullString
+		var lastRefresh sql.NullTime
+
+		if err = rows.Scan(&viewSchema, &viewName, &populated, &refreshMode, &lastRefresh, &staleness, &definition); err != nil {
+			continue
+		}
+
+		view := map[string]interface{}{
+			"schema":       viewSchema,
+			"name":         viewName,
+			"populated":    populated.Bool,
+			"refresh_mode": refreshMode.String,
+			"last_refresh": nil,
+		}
+		if lastRefresh.Valid {
+			view["last_refresh"] = lastRefresh.Time.Format("2006-01-02 15:04:05")
+		}
+		if staleness.Valid {
+			view["staleness"] = staleness.String
+		}
+		if includeDefinition {
+			view["definition"] = definition.String
+		}
+		views = append(views, view)
+	}
+
+	response := map[string]interface{}{
+		"materialized_views": views,
+		"pagination": map[string]interface{}{
+			"page":      pagination.Page,
+			"page_size": pagination.PageSize,
+			"count":     len(views),
+		},
+		"filter": map[string]interface{}{
+			"schema":      schema,
+			"name_filter": nameFilter,
+		},
 	}
 
-	return mcp.NewToolResultText(string(jsonData)), nil
+	return jsonToolResult(response), nil
 }
@@ -1,5 +1,12 @@
 package mcp
 
+import (
+	"log"
+	"os"
+	"path"
+	"strings"
+)
+
 func (s *DbMCPServer) registerTools() {
 	// ===== DataSource Management =====
 	// Configure DataSource (connect to a database)
@@ -8,6 +15,9 @@ func (s *DbMCPServer) registerTools() {
 	// Get Current DataSource
 	s.server.AddTool(s.toolGetCurrentDataSource())
 
+	// List Connections
+	s.server.AddTool(s.toolListConnections())
+
 	// Test Connection
 	s.server.AddTool(s.toolTestConnection())
 
@@ -21,6 +31,45 @@ func (s *DbMCPServer) registerTools() {
 	// Execute Query
 	s.server.AddTool(s.toolExecuteQuery())
 
+	// Fetch More Rows of a Cursor
+	s.server.AddTool(s.toolFetchMore())
+
+	// Export Query Results
+	s.server.AddTool(s.toolExportQuery())
+
+	// Explain Query
+	s.server.AddTool(s.toolExplainQuery())
+
+	// Validate Query
+	s.server.AddTool(s.toolValidateQuery())
+
+	// Analyze Query
+	s.server.AddTool(s.toolAnalyzeQuery())
+
+	// Clear Query Cache
+	s.server.AddTool(s.toolClearQueryCache())
+
+	// Save Query
+	s.server.AddTool(s.toolSaveQuery())
+
+	// List Saved Queries
+	s.server.AddTool(s.toolListSavedQueries())
+
+	// Run Saved Query
+	s.server.AddTool(s.toolRunSavedQuery())
+
+	// Write tools, only with DB_ALLOW_WRITES
+	if s.writes != nil {
+		// Execute Insert
+		s.server.AddTool(s.toolExecuteInsert())
+
+		// Execute Update
+		s.server.AddTool(s.toolExecuteUpdate())
+
+		// Execute Delete
+		s.server.AddTool(s.toolExecuteDelete())
+	}
+
 	// ===== Tables =====
 	// List Tables
 	s.server.AddTool(s.toolListTables())
@@ -31,9 +80,67 @@ func (s *DbMCPServer) registerTools() {
 	// List Table Rows
 	s.server.AddTool(s.toolListTableRows())
 
+	// Sample Table Data
+	s.server.AddTool(s.toolSampleTableData())
+
+	// Profile Column
+	s.server.AddTool(s.toolProfileColumn())
+
+	// Get Distinct Values
+	s.server.AddTool(s.toolGetDistinctValues())
+
+	// Find Value in Table
+	s.server.AddTool(s.toolFindValueInTable())
+
+	// Aggregate Table
+	s.server.AddTool(s.toolAggregateTable())
+
 	// Get Full Table Schema
 	s.server.AddTool(s.toolGetTableSchemaFull())
 
+	// Get Table and Column Comments
+	s.server.AddTool(s.toolGetObjectComments())
+
+	// Get Table DDL
+	s.server.AddTool(s.toolGetTableDDL())
+
+	// Generate ERD
+	s.server.AddTool(s.toolGenerateERD())
+
+	// Export Data Dictionary
+	s.server.AddTool(s.toolExportDataDictionary())
+
+	// Get Table Statistics
+	s.server.AddTool(s.toolGetTableStats())
+
+	// Estimate Row Counts
+	s.server.AddTool(s.toolEstimateRowCounts())
+
+	// List Statistics
+	s.server.AddTool(s.toolListStatistics())
+
+	// List Table Partitions
+	s.server.AddTool(s.toolListPartitions())
+
+	// Get Identity Status
+	s.server.AddTool(s.toolGetIdentityStatus())
+
+	// List Temporal Tables
+	s.server.AddTool(s.toolListTemporalTables())
+
+	// List Computed Columns
+	s.server.AddTool(s.toolListComputedColumns())
+
+	// ===== Constraints =====
+	// List Foreign Keys
+	s.server.AddTool(s.toolListForeignKeys())
+
+	// List Key Constraints
+	s.server.AddTool(s.toolListKeyConstraints())
+
+	// List Check Constraints
+	s.server.AddTool(s.toolListCheckConstraints())
+
 	// ===== Stored Procedures =====
 	// List Stored Procedures
 	s.server.AddTool(s.toolListProcedures())
@@ -51,6 +158,9 @@ func (s *DbMCPServer) registerTools() {
 	// Get Function Source Code
 	s.server.AddTool(s.toolGetFunctionCode())
 
+	// Get Function Parameters
+	s.server.AddTool(s.toolGetFunctionParameters())
+
 	// ===== Views =====
 	// List Views
 	s.server.AddTool(s.toolListViews())
@@ -58,6 +168,9 @@ func (s *DbMCPServer) registerTools() {
 	// Get View Definition
 	s.server.AddTool(s.toolGetViewDefinition())
 
+	// List Materialized Views
+	s.server.AddTool(s.toolListMaterializedViews())
+
 	// ===== Triggers =====
 	// List Triggers
 	s.server.AddTool(s.toolListTriggers())
@@ -65,10 +178,137 @@ func (s *DbMCPServer) registerTools() {
 	// Get Trigger Source Code
 	s.server.AddTool(s.toolGetTriggerCode())
 
+	// ===== Synonyms =====
+	// List Synonyms
+	s.server.AddTool(s.toolListSynonyms())
+
+	// ===== Types =====
+	// List Types
+	s.server.AddTool(s.toolListTypes())
+
 	// ===== Database Info =====
 	// Search Object
 	s.server.AddTool(s.toolSearchObjects())
 
+	// Search Object Definitions
+	s.server.AddTool(s.toolSearchDefinitions())
+
+	// Find Column
+	s.server.AddTool(s.toolFindColumn())
+
 	// Get Database Information
 	s.server.AddTool(s.toolGetDatabaseInfo())
+
+	// Schema Overview
+	s.server.AddTool(s.toolSchemaOverview())
+
+	// Get Collation Information
+	s.server.AddTool(s.toolGetCollationInfo())
+
+	// List Databases
+	s.server.AddTool(s.toolListDatabases())
+
+	// List Extensions
+	s.server.AddTool(s.toolListExtensions())
+
+	// List Remote Servers
+	s.server.AddTool(s.toolListRemoteServers())
+
+	// List Scheduled Jobs
+	s.server.AddTool(s.toolListScheduledJobs())
+
+	// Get Change Tracking Status
+	s.server.AddTool(s.toolGetChangeTrackingStatus())
+
+	// Get Object Dependencies
+	s.server.AddTool(s.toolGetObjectDependencies())
+
+	// List Object Permissions
+	s.server.AddTool(s.toolListObjectPermissions())
+
+	// List Row-Level Security Policies
+	s.server.AddTool(s.toolListRLSPolicies())
+
+	// List Users and Roles
+	s.server.AddTool(s.toolListUsersAndRoles())
+
+	// Fetch Full Result of a Preview
+	s.server.AddTool(s.toolFetchFull())
+
+	// ===== Diagnostics =====
+	// Get Runtime Statistics
+	s.server.AddTool(s.toolGetRuntimeStats())
+
+	// Table Access Report
+	s.server.AddTool(s.toolTableAccessReport())
+
+	// List Running Queries
+	s.server.AddTool(s.toolListRunningQueries())
+
+	// Cancel Query
+	s.server.AddTool(s.toolCancelQuery())
+
+	// Get Query History
+	s.server.AddTool(s.toolGetQueryHistory())
+
+	// Tools left out by DB_ENABLED_TOOLS or DB_DISABLED_TOOLS are not offered
+	s.removeDisabledTools()
+}
+
+// removeDisabledTools removes the tools that DB_ENABLED_TOOLS does not list, when set, and
+// those DB_DISABLED_TOOLS lists. Both are comma separated tool names, with * wildcards.
+func (s *DbMCPServer) removeDisabledTools() {
+	enabled, disabled := envToolPatterns("DB_ENABLED_TOOLS"), envToolPatterns("DB_DISABLED_TOOLS")
+	if len(enabled) == 0 && len(disabled) == 0 {
+		return
+	}
+
+	tools := s.server.ListTools()
+	matched := make(map[string]bool)
+	var removed []string
+	for name := range tools {
+		keep := len(enabled) == 0
+		for _, pattern := range enabled {
+			if matchName(pattern, name) {
+				keep, matched[pattern] = true, true
+			}
+		}
+		for _, pattern := range disabled {
+			if matchName(pattern, name) {
+				keep, matched[pattern] = false, true
+			}
+		}
+		if !keep {
+			removed = append(removed, name)
+		}
+	}
+	s.server.DeleteTools(removed...)
+
+	// The views of the connections register the same tools, so only the server warns
+	if s.view {
+		return
+	}
+	for _, pattern := range append(enabled, disabled...) {
+		if !matched[pattern] {
+			log.Printf("Warning: No tool matches %q of DB_ENABLED_TOOLS or DB_DISABLED_TOOLS", pattern)
+		}
+	}
+}
+
+// envToolPatterns reads a comma separated list of tool names from an environment variable.
+// Invalid patterns are skipped with a warning.
+func envToolPatterns(name string) []string {
+	var patterns []string
+	for _, entry := range strings.Split(os.Getenv(name), ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if _, err := path.Match(entry, ""); err != nil {
+			log.Printf("Warning: Ignoring invalid %s entry %q", name, entry)
+			continue
+		}
+		patterns = append(patterns, entry)
+	}
+	return patterns
 }
@@ -1,27 +1,29 @@
 package mcp
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
 
 // GetPaginationParams extracts and validates pagination parameters from args
 func GetPaginationParams(args map[string]interface{}, defaultPageSize, maxPageSize int) PaginationParams {
-	page := DefaultPage
-	pageSize := defaultPageSize
+	page, _ := args["page"].(float64)
+	pageSize, _ := args["page_size"].(float64)
+	return newPaginationParams(int(page), int(pageSize), defaultPageSize, maxPageSize)
+}
 
-	if pageVal, ok := args["page"].(float64); ok {
-		page = int(pageVal)
-		if page < 1 {
-			page = 1
-		}
+// newPaginationParams validates a page and page size, where 0 means not set
+func newPaginationParams(page, pageSize, defaultPageSize, maxPageSize int) PaginationParams {
+	if page < 1 {
+		page = DefaultPage
 	}
-
-	if pageSizeVal, ok := args["page_size"].(float64); ok {
-		pageSize = int(pageSizeVal)
-		if pageSize < 1 {
-			pageSize = defaultPageSize
-		}
-		if pageSize > maxPageSize {
-			pageSize = maxPageSize
-		}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
 	}
 
 	return PaginationParams{
@@ -31,52 +33,110 @@ func GetPaginationParams(args map[string]interface{}, defaultPageSize, maxPageSi
 	}
 }
 
-// isValidIdentifier validates SQL identifiers to prevent SQL injection
+// isValidIdentifier validates SQL identifiers to prevent SQL injection: names of letters of
+// any script, digits, _#@$ and single spaces between them (Order Details), or [name],
+// "name" and `name` identifiers of any printable characters, with the closing quote doubled
+// inside. Identifiers are quoted with QuoteIdentifier wherever they are interpolated.
 func isValidIdentifier(name string) bool {
-	if name == "" || len(name) >= 128 {
+	inner, quoted := unquoteIdentifier(name)
+	if inner == "" || utf8.RuneCountInString(inner) >= 128 {
 		return false
 	}
-	// Allow bracketed identifiers [name] - SQL Server style escaping
-	if len(name) >= 2 && name[0] == '[' && name[len(name)-1] == ']' {
-		// Extract name inside brackets and validate it allows more chars
-		innerName := name[1 : len(name)-1]
-		return len(innerName) > 0 && reValidIdentifierBracketed.MatchString(innerName)
+	if quoted {
+		return !strings.ContainsFunc(inner, unicode.IsControl)
 	}
 	return reValidIdentifier.MatchString(name)
 }
 
-// getValidSchema sanitizes and validates schema name
-func getValidSchema(args map[string]interface{}, defaultSchema string) (string, error) {
-	schema := defaultSchema
-	if sc, ok := args["schema"].(string); ok && sc != "" {
-		schema = sc
+// isPlainIdentifier validates names that are written unquoted in a query or a template,
+// such as parameter names: ASCII letters, digits and _#@$
+func isPlainIdentifier(name string) bool {
+	return len(name) < 128 && rePlainIdentifier.MatchString(name)
+}
+
+// identifierQuotes are the closing quotes of the quoted identifier forms, by opening quote
+var identifierQuotes = map[byte]byte{'[': ']', '"': '"', '`': '`'}
+
+// unquoteIdentifier returns the name a [name], "name" or `name` identifier stands for, with
+// the doubled closing quotes in it undone, and whether it was quoted that way
+func unquoteIdentifier(name string) (string, bool) {
+	if len(name) < 2 {
+		return name, false
 	}
-	if schema != "" && !isValidIdentifier(schema) {
-		return "", fmt.Errorf("%w: %s", ErrInvalidIdentifier, schema)
+	closing, ok := identifierQuotes[name[0]]
+	if !ok || name[len(name)-1] != closing {
+		return name, false
 	}
-	return schema, nil
+	quote := string(closing)
+	inner := name[1 : len(name)-1]
+	if strings.Contains(strings.ReplaceAll(inner, quote+quote, ""), quote) {
+		return name, false
+	}
+	return strings.ReplaceAll(inner, quote+quote, quote), true
 }
 
-// getStringArg safely extracts a string argument
-func getStringArg(args map[string]interface{}, key string) (string, bool) {
-	val, ok := args[key].(string)
-	return val, ok
+// quoteIdentifier quotes a name between open and closing, doubling the closing quotes in
+// it. A name already quoted in one of the forms of unquoteIdentifier is quoted as its name.
+func quoteIdentifier(name, open, closing string) string {
+	name, _ = unquoteIdentifier(name)
+	return open + strings.ReplaceAll(name, closing, closing+closing) + closing
 }
 
-// getIntArg safely extracts an integer argument with default value
-func getIntArg(args map[string]interface{}, key string, defaultVal int) int {
-	if val, ok := args[key].(float64); ok {
-		return int(val)
+// splitQualifiedName splits a schema.name identifier at its dot outside quotes, as in
+// dbo.[Order Details], reporting false when it has no such dot or more than one
+func splitQualifiedName(name string) (string, string, bool) {
+	dot := -1
+	var closing byte // closing quote of the quoted part being read
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case closing != 0 && c == closing && i+1 < len(name) && name[i+1] == closing:
+			i++
+		case closing != 0 && c == closing:
+			closing = 0
+		case closing != 0:
+		case identifierQuotes[c] != 0:
+			closing = identifierQuotes[c]
+		case c == '.':
+			if dot >= 0 {
+				return "", "", false
+			}
+			dot = i
+		}
+	}
+	if dot <= 0 || dot == len(name)-1 {
+		return "", "", false
+	}
+	return name[:dot], name[dot+1:], true
+}
+
+// getValidSchema validates the schema argument, falling back to defaultSchema when empty
+func getValidSchema(schema, defaultSchema string) (string, error) {
+	if schema == "" {
+		schema = defaultSchema
+	}
+	if schema != "" && !isValidIdentifier(schema) {
+		return "", fmt.Errorf("%w: %s", ErrInvalidIdentifier, schema)
 	}
-	return defaultVal
+	return schema, nil
 }
 
-// getBoolArg safely extracts a boolean argument with default value
-func getBoolArg(args map[string]interface{}, key string, defaultVal bool) bool {
-	if val, ok := args[key].(bool); ok {
-		return val
+// getTargetDatabase validates the optional database argument used for cross-database metadata queries.
+// MySQL databases are schemas, so there the database replaces the schema filter.
+func getTargetDatabase(database string, qb *QueryBuilder, schema string) (string, string, error) {
+	if database == "" {
+		return "", schema, nil
+	}
+	if !isValidIdentifier(database) {
+		return "", "", fmt.Errorf("%w: %s", ErrInvalidDatabaseName, database)
+	}
+	if !qb.SupportsCrossDatabase() {
+		return "", "", ErrCrossDatabaseNotSupported
+	}
+	if qb.IsMySQL() {
+		return database, database, nil
 	}
-	return defaultVal
+	return database, schema, nil
 }
 
 // getArgs safely extracts arguments map from request
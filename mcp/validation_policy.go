@@ -0,0 +1,225 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// validationPolicy adjusts the query validator to a deployment, from the JSON or YAML file
+// DB_VALIDATION_POLICY_FILE, or the JSON of DB_VALIDATION_POLICY: the statements a query may be, keywords denied on top of the
+// built-in ones, blocked functions it may call and the limits of the validator. Fields left
+// out keep the built-in checks. Writes, schema changes, transaction control and stacked
+// statements are rejected whatever the policy says.
+type validationPolicy struct {
+	AllowedStatements    []string `json:"allowed_statements,omitempty" yaml:"allowed_statements"`
+	DeniedKeywords       []string `json:"denied_keywords,omitempty" yaml:"denied_keywords"`
+	AllowedFunctions     []string `json:"allowed_functions,omitempty" yaml:"allowed_functions"`
+	MaxQueryLength       int      `json:"max_query_length,omitempty" yaml:"max_query_length"`
+	MaxSubqueryCount     int      `json:"max_subquery_count,omitempty" yaml:"max_subquery_count"`
+	MaxUnionCount        int      `json:"max_union_count,omitempty" yaml:"max_union_count"`
+	MaxParenthesesDepth  int      `json:"max_parentheses_depth,omitempty" yaml:"max_parentheses_depth"`
+	MaxHexEncodingCount  int      `json:"max_hex_encoding_count,omitempty" yaml:"max_hex_encoding_count"`
+	MaxCharFunctionCount int      `json:"max_char_function_count,omitempty" yaml:"max_char_function_count"`
+
+	// err is why the policy file could not be loaded. The validator then rejects every
+	// query, as a policy meant to be stricter must not fall back to the built-in one.
+	err error
+
+	statements map[string]bool // allowed statements, in upper case
+	denied     []string        // denied keywords, in upper case
+	functions  map[string]bool // allowed functions, in upper case
+}
+
+// policyStatements are the statements a policy can allow, all of which only read (PRAGMA is
+// limited to the pragmas reading the schema)
+var policyStatements = map[string]bool{
+	"SELECT":   true,
+	"WITH":     true,
+	"VALUES":   true,
+	"TABLE":    true,
+	"SHOW":     true,
+	"DESCRIBE": true,
+	"DESC":     true,
+	"EXPLAIN":  true,
+	"PRAGMA":   true,
+}
+
+// defaultPolicyStatements are the statements allowed without a policy
+var defaultPolicyStatements = []string{"SELECT", "WITH"}
+
+// validationLimit is a limit of the validator, which a policy file sets by name and the
+// environment variable env sets for the policies leaving it out, between 1 and max
+type validationLimit struct {
+	name  string
+	env   string
+	def   int
+	max   int
+	value func(p *validationPolicy) *int
+}
+
+// validationLimits are the limits of the validator
+var validationLimits = []validationLimit{
+	{"max_query_length", "DB_MAX_QUERY_LENGTH", MaxQueryLength, MaxQueryLengthBound, func(p *validationPolicy) *int { return &p.MaxQueryLength }},
+	{"max_subquery_count", "DB_MAX_SUBQUERY_COUNT", MaxSubqueryCount, MaxSubqueryCountBound, func(p *validationPolicy) *int { return &p.MaxSubqueryCount }},
+	{"max_union_count", "DB_MAX_UNION_COUNT", MaxUnionCount, MaxUnionCountBound, func(p *validationPolicy) *int { return &p.MaxUnionCount }},
+	{"max_parentheses_depth", "DB_MAX_PARENTHESES_DEPTH", MaxParenthesesDepth, MaxParenthesesDepthBound, func(p *validationPolicy) *int { return &p.MaxParenthesesDepth }},
+	{"max_hex_encoding_count", "DB_MAX_HEX_ENCODING_COUNT", MaxHexEncodingCount, MaxHexEncodingCountBound, func(p *validationPolicy) *int { return &p.MaxHexEncodingCount }},
+	{"max_char_function_count", "DB_MAX_CHAR_FUNCTION_COUNT", MaxCharFunctionCount, MaxCharFunctionCountBound, func(p *validationPolicy) *int { return &p.MaxCharFunctionCount }},
+}
+
+// getEnvValidationLimits reads the limits of the validator from the environment, by name,
+// keeping the built-in value of those unset or out of bounds
+func getEnvValidationLimits() map[string]int {
+	limits := make(map[string]int, len(validationLimits))
+	for _, limit := range validationLimits {
+		limits[limit.name] = limit.def
+		value := os.Getenv(limit.env)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || n < 1 || n > limit.max {
+			log.Printf("Warning: Ignoring invalid %s=%q (between 1 and %d)", limit.env, value, limit.max)
+			continue
+		}
+		limits[limit.name] = n
+	}
+	return limits
+}
+
+// newValidationPolicy returns the validation policy configured from the environment
+func newValidationPolicy() *validationPolicy {
+	limits := getEnvValidationLimits()
+	path := strings.TrimSpace(os.Getenv("DB_VALIDATION_POLICY_FILE"))
+	inline := strings.TrimSpace(os.Getenv("DB_VALIDATION_POLICY"))
+	if path == "" && inline == "" {
+		policy := &validationPolicy{}
+		_ = policy.resolve(limits)
+		return policy
+	}
+
+	// The file wins over the inline policy, as the environment does over a configuration file
+	source := "DB_VALIDATION_POLICY_FILE"
+	var policy *validationPolicy
+	var err error
+	if path != "" {
+		policy, err = readValidationPolicy(path, limits)
+	} else {
+		source = "DB_VALIDATION_POLICY"
+		policy, err = decodeValidationPolicy([]byte(inline), false, limits)
+	}
+	if err != nil {
+		log.Printf("Warning: Every query is rejected, %s could not be loaded: %v", source, err)
+		return &validationPolicy{err: fmt.Errorf("%w: %w", ErrInvalidValidationPolicy, err)}
+	}
+	return policy
+}
+
+// defaultValidationPolicy returns the built-in checks of the validator
+func defaultValidationPolicy() *validationPolicy {
+	policy := &validationPolicy{}
+	_ = policy.resolve(nil)
+	return policy
+}
+
+// readValidationPolicy reads a policy file, as YAML when its extension is .yaml or .yml and
+// as JSON otherwise. Unknown fields are rejected, so a misspelled limit is not ignored. The
+// limits it leaves out take their value in limits.
+func readValidationPolicy(path string, limits map[string]int) (*validationPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	return decodeValidationPolicy(data, ext == ".yaml" || ext == ".yml", limits)
+}
+
+// decodeValidationPolicy decodes a policy from YAML or JSON, rejecting unknown fields
+func decodeValidationPolicy(data []byte, asYAML bool, limits map[string]int) (*validationPolicy, error) {
+	var err error
+	policy := &validationPolicy{}
+	switch {
+	case asYAML:
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(policy)
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(policy)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = policy.resolve(limits); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// resolve checks the policy and fills the limits it leaves out with their value in limits,
+// or with the built-in ones
+func (p *validationPolicy) resolve(limits map[string]int) error {
+	statements := p.AllowedStatements
+	if len(statements) == 0 {
+		statements = defaultPolicyStatements
+	}
+	p.statements = make(map[string]bool, len(statements))
+	for _, statement := range statements {
+		name := strings.ToUpper(strings.TrimSpace(statement))
+		if !policyStatements[name] {
+			return fmt.Errorf("allowed_statements: %s", statement)
+		}
+		p.statements[name] = true
+	}
+
+	p.denied = make([]string, 0, len(p.DeniedKeywords))
+	for _, keyword := range p.DeniedKeywords {
+		words := strings.Fields(strings.ToUpper(keyword))
+		if len(words) == 0 || len(words) > 2 || !isPlainIdentifier(words[0]) || (len(words) == 2 && !isPlainIdentifier(words[1])) {
+			return fmt.Errorf("denied_keywords: %s", keyword)
+		}
+		p.denied = append(p.denied, strings.Join(words, " "))
+	}
+
+	p.functions = make(map[string]bool, len(p.AllowedFunctions))
+	for _, function := range p.AllowedFunctions {
+		name := strings.ToUpper(strings.TrimSpace(function))
+		if !isBlockedFunction(name) {
+			return fmt.Errorf("allowed_functions: %s", function)
+		}
+		p.functions[name] = true
+	}
+
+	for _, limit := range validationLimits {
+		value := limit.value(p)
+		if *value < 0 || *value > limit.max {
+			return fmt.Errorf("%s: %d (between 1 and %d)", limit.name, *value, limit.max)
+		}
+		if *value == 0 {
+			*value = limit.def
+			if n, ok := limits[limit.name]; ok {
+				*value = n
+			}
+		}
+	}
+	return nil
+}
+
+// allowsStatement reports whether a query may start with the keyword
+func (p *validationPolicy) allowsStatement(keyword string) bool {
+	return p.statements[strings.ToUpper(keyword)]
+}
+
+// allowsFunction reports whether a blocked function may be called
+func (p *validationPolicy) allowsFunction(name string) bool {
+	return p.functions[strings.ToUpper(name)]
+}
@@ -0,0 +1,131 @@
+package mcp
+
+import (
+	"strings"
+)
+
+// ValidationSeverity tells whether a violation rejects the query
+type ValidationSeverity string
+
+const (
+	SeverityError   ValidationSeverity = "error"   // the query is rejected
+	SeverityWarning ValidationSeverity = "warning" // the query runs, but may be slower or wider than meant
+)
+
+// ValidationViolation is a rule a query breaks. Line and Column, counted from 1, point at
+// the token that breaks it; they are 0 when the rule applies to the query as a whole.
+type ValidationViolation struct {
+	Rule     string             `json:"rule"`
+	Severity ValidationSeverity `json:"severity"`
+	Message  string             `json:"message"`
+	Line     int                `json:"line,omitempty"`
+	Column   int                `json:"column,omitempty"`
+	Err      error              `json:"-"`
+
+	offset int // position in the query in characters, -1 for the query as a whole
+}
+
+// ValidationResult lists every rule a query breaks, in the order the checks run, so a
+// query can be repaired in one pass. Warnings do not reject the query.
+type ValidationResult struct {
+	Violations []ValidationViolation `json:"violations"`
+}
+
+// ValidationError is the error of a query that breaks one or more rules. Its message lists
+// the errors of the violations, and errors.Is matches any of them.
+type ValidationError struct {
+	Violations []ValidationViolation
+}
+
+// Error returns the messages of the violations, separated by semicolons
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.Err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the errors of the violations
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Violations))
+	for i, violation := range e.Violations {
+		errs[i] = violation.Err
+	}
+	return errs
+}
+
+// Valid reports whether the query breaks no rule of error severity
+func (r *ValidationResult) Valid() bool {
+	return len(r.Errors()) == 0
+}
+
+// Errors returns the violations that reject the query
+func (r *ValidationResult) Errors() []ValidationViolation {
+	return r.bySeverity(SeverityError)
+}
+
+// Warnings returns the violations that do not reject the query
+func (r *ValidationResult) Warnings() []ValidationViolation {
+	return r.bySeverity(SeverityWarning)
+}
+
+// Err returns a *ValidationError with the errors of the result, or nil when the query is
+// valid, for callers that only need to accept or reject it
+func (r *ValidationResult) Err() error {
+	errs := r.Errors()
+	if len(errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: errs}
+}
+
+// bySeverity returns the violations of a severity
+func (r *ValidationResult) bySeverity(severity ValidationSeverity) []ValidationViolation {
+	var violations []ValidationViolation
+	for _, violation := range r.Violations {
+		if violation.Severity == severity {
+			violations = append(violations, violation)
+		}
+	}
+	return violations
+}
+
+// add records a violation at a position of the query, or at -1 for the query as a whole.
+// The checks run once per set of lexical rules, so a violation already found, wherever
+// another set of rules placed it, is skipped.
+func (r *ValidationResult) add(severity ValidationSeverity, err error, offset int) {
+	for _, violation := range r.Violations {
+		if violation.Err.Error() == err.Error() {
+			return
+		}
+	}
+	r.Violations = append(r.Violations, ValidationViolation{
+		Rule:     validationRule(err),
+		Severity: severity,
+		Message:  localizeError(err),
+		Err:      err,
+		offset:   offset,
+	})
+}
+
+// locate sets the line and column of the violations from their positions in the query
+func (r *ValidationResult) locate(query string) {
+	src := []rune(query)
+	for i := range r.Violations {
+		offset := r.Violations[i].offset
+		if offset < 0 || offset > len(src) {
+			continue
+		}
+		line, column := 1, 1
+		for _, c := range src[:offset] {
+			if c == '\n' {
+				line++
+				column = 1
+			} else {
+				column++
+			}
+		}
+		r.Violations[i].Line, r.Violations[i].Column = line, column
+	}
+}
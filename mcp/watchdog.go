@@ -0,0 +1,380 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WatchdogThresholds configures when a running query is logged (soft) or killed (hard).
+// Zero values disable the corresponding check.
+type WatchdogThresholds struct {
+	SoftDuration time.Duration
+	SoftRows     int64
+	HardDuration time.Duration
+	HardRows     int64
+}
+
+// queryWatchdog tracks in-flight queries and checks them against their thresholds
+type queryWatchdog struct {
+	mu       sync.Mutex
+	queries  map[uint64]*watchedQuery
+	nextID   uint64
+	defaults WatchdogThresholds
+	perTool  map[string]WatchdogThresholds
+	interval time.Duration
+	history  *queryHistory
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// watchedQuery is a query tracked by the watchdog
+type watchedQuery struct {
+	id         uint64
+	tool       string
+	query      string
+	started    time.Time
+	thresholds WatchdogThresholds
+	cancel     context.CancelFunc
+	rows       atomic.Int64
+	softLogged atomic.Bool
+	killReason atomic.Value // string
+	cancelled  atomic.Bool  // killed by cancel_query rather than a threshold
+	failure    atomic.Value // error reported through Cause
+	watchdog   *queryWatchdog
+}
+
+type watchedQueryKey struct{}
+
+// newQueryWatchdog creates a watchdog with thresholds read from the environment and starts it
+func newQueryWatchdog() *queryWatchdog {
+	w := &queryWatchdog{
+		queries:  make(map[uint64]*watchedQuery),
+		perTool:  make(map[string]WatchdogThresholds),
+		interval: WatchdogInterval,
+		history:  newQueryHistory(int(envInt("DB_QUERY_HISTORY_SIZE", DefaultQueryHistorySize))),
+		stop:     make(chan struct{}),
+	}
+	w.defaults = loadThresholds("DB_WATCHDOG_", WatchdogThresholds{
+		SoftDuration: WatchdogSoftDuration,
+		SoftRows:     WatchdogSoftRows,
+	})
+
+	go w.run()
+	return w
+}
+
+// Close stops the watchdog goroutine
+func (w *queryWatchdog) Close() {
+	if w == nil {
+		return
+	}
+	w.stopOnce.Do(func() { close(w.stop) })
+}
+
+// Watch registers a query for the given tool. The returned context is cancelled when a
+// hard threshold is exceeded; Done must be called once the query and its rows are consumed.
+func (w *queryWatchdog) Watch(ctx context.Context, tool, query string) (context.Context, *watchedQuery) {
+	if w == nil {
+		return ctx, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	q := &watchedQuery{
+		tool:       tool,
+		query:      query,
+		started:    time.Now(),
+		thresholds: w.thresholdsFor(tool),
+		cancel:     cancel,
+		watchdog:   w,
+	}
+
+	w.mu.Lock()
+	w.nextID++
+	q.id = w.nextID
+	w.queries[q.id] = q
+	w.mu.Unlock()
+
+	return context.WithValue(ctx, watchedQueryKey{}, q), q
+}
+
+// InFlight returns the number of queries currently tracked
+func (w *queryWatchdog) InFlight() int {
+	if w == nil {
+		return 0
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.queries)
+}
+
+// runningQuery is an in-flight query as listed by list_running_queries
+type runningQuery struct {
+	ID          uint64 `json:"id"`
+	Tool        string `json:"tool"`
+	Fingerprint string `json:"fingerprint"`
+	Query       string `json:"query"`
+	StartedAt   string `json:"started_at"`
+	ElapsedMs   int64  `json:"elapsed_ms"`
+	Rows        int64  `json:"rows"`
+	Killed      string `json:"killed,omitempty"`
+}
+
+// Running returns the in-flight queries, oldest first
+func (w *queryWatchdog) Running() []runningQuery {
+	if w == nil {
+		return []runningQuery{}
+	}
+	w.mu.Lock()
+	queries := make([]*watchedQuery, 0, len(w.queries))
+	for _, q := range w.queries {
+		queries = append(queries, q)
+	}
+	w.mu.Unlock()
+
+	sort.Slice(queries, func(i, j int) bool { return queries[i].id < queries[j].id })
+	running := make([]runningQuery, len(queries))
+	for i, q := range queries {
+		running[i] = q.snapshot()
+	}
+	return running
+}
+
+// Cancel kills an in-flight query on request and returns it, or false when no query with
+// that id is running
+func (w *queryWatchdog) Cancel(id uint64) (runningQuery, bool) {
+	if w == nil {
+		return runningQuery{}, false
+	}
+	w.mu.Lock()
+	q, ok := w.queries[id]
+	w.mu.Unlock()
+	if !ok {
+		return runningQuery{}, false
+	}
+
+	if q.killReason.CompareAndSwap(nil, translate("cancelled with cancel_query")) {
+		q.cancelled.Store(true)
+		log.Printf("Watchdog: cancelling query on request (tool=%s): %s", q.tool, shortenQuery(q.query))
+		q.cancel()
+	}
+	return q.snapshot(), true
+}
+
+// snapshot returns the current state of the query
+func (q *watchedQuery) snapshot() runningQuery {
+	running := runningQuery{
+		ID:          q.id,
+		Tool:        q.tool,
+		Fingerprint: queryFingerprint(q.query),
+		Query:       shortenQuery(q.query),
+		StartedAt:   q.started.Format("2006-01-02 15:04:05"),
+		ElapsedMs:   time.Since(q.started).Milliseconds(),
+		Rows:        q.rows.Load(),
+	}
+	running.Killed, _ = q.killReason.Load().(string)
+	return running
+}
+
+// watchedQueryFrom returns the query tracked for the context, if any
+func watchedQueryFrom(ctx context.Context) *watchedQuery {
+	q, _ := ctx.Value(watchedQueryKey{}).(*watchedQuery)
+	return q
+}
+
+// Row records a streamed row and kills the query when the hard row limit is reached
+func (q *watchedQuery) Row() {
+	if q == nil {
+		return
+	}
+	rows := q.rows.Add(1)
+	if q.thresholds.HardRows > 0 && rows > q.thresholds.HardRows {
+		q.kill(fmt.Sprintf(translate("streamed more than %d rows"), q.thresholds.HardRows))
+	}
+}
+
+// Err returns ErrQueryKilled when the watchdog killed the query, or ErrQueryCancelled when
+// it was cancelled with cancel_query
+func (q *watchedQuery) Err() error {
+	if q == nil {
+		return nil
+	}
+	if q.cancelled.Load() {
+		return ErrQueryCancelled
+	}
+	if reason, ok := q.killReason.Load().(string); ok {
+		return fmt.Errorf("%w: %s", ErrQueryKilled, reason)
+	}
+	return nil
+}
+
+// Cause returns the watchdog kill error in place of err when the query was killed,
+// since the driver only reports the cancelled context. Other errors are recorded as the
+// failure of the query in the query history.
+func (q *watchedQuery) Cause(err error) error {
+	if killErr := q.Err(); killErr != nil {
+		return killErr
+	}
+	if q != nil && err != nil {
+		q.failure.CompareAndSwap(nil, err)
+	}
+	return err
+}
+
+// Done unregisters the query, records it in the query history and logs its resource usage
+// if it exceeded a soft threshold
+func (q *watchedQuery) Done() {
+	if q == nil {
+		return
+	}
+	w := q.watchdog
+
+	w.mu.Lock()
+	delete(w.queries, q.id)
+	w.mu.Unlock()
+	q.cancel()
+	w.history.add(q.historyEntry())
+
+	if q.softLogged.Load() {
+		log.Printf("Watchdog: query finished after %s, %d rows (tool=%s): %s",
+			time.Since(q.started).Round(time.Millisecond), q.rows.Load(), q.tool, shortenQuery(q.query))
+	}
+}
+
+// kill cancels the query and records the reason
+func (q *watchedQuery) kill(reason string) {
+	if q.killReason.CompareAndSwap(nil, reason) {
+		log.Printf("Watchdog: killing query (tool=%s, %s): %s", q.tool, reason, shortenQuery(q.query))
+		q.cancel()
+	}
+}
+
+// run periodically checks the in-flight queries until the watchdog is closed
+func (w *queryWatchdog) run() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.check()
+		}
+	}
+}
+
+// check logs queries over their soft thresholds and kills queries over their hard ones
+func (w *queryWatchdog) check() {
+	w.mu.Lock()
+	queries := make([]*watchedQuery, 0, len(w.queries))
+	for _, q := range w.queries {
+		queries = append(queries, q)
+	}
+	w.mu.Unlock()
+
+	for _, q := range queries {
+		elapsed := time.Since(q.started)
+		rows := q.rows.Load()
+		t := q.thresholds
+
+		if t.HardDuration > 0 && elapsed > t.HardDuration {
+			q.kill(fmt.Sprintf(translate("running longer than %s"), t.HardDuration))
+			continue
+		}
+
+		overTime := t.SoftDuration > 0 && elapsed > t.SoftDuration
+		overRows := t.SoftRows > 0 && rows > t.SoftRows
+		if (overTime || overRows) && q.softLogged.CompareAndSwap(false, true) {
+			log.Printf("Watchdog: query still running after %s, %d rows streamed (tool=%s): %s",
+				elapsed.Round(time.Millisecond), rows, q.tool, shortenQuery(q.query))
+		}
+	}
+}
+
+// thresholdsFor returns the thresholds of a tool, applying DB_WATCHDOG_<TOOL>_* overrides
+func (w *queryWatchdog) thresholdsFor(tool string) WatchdogThresholds {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if t, ok := w.perTool[tool]; ok {
+		return t
+	}
+	t := loadThresholds("DB_WATCHDOG_"+strings.ToUpper(tool)+"_", w.defaults)
+	w.perTool[tool] = t
+	return t
+}
+
+// loadThresholds reads the thresholds with the given environment prefix over base
+func loadThresholds(prefix string, base WatchdogThresholds) WatchdogThresholds {
+	t := base
+	t.SoftDuration = envDuration(prefix+"SOFT_TIMEOUT", t.SoftDuration)
+	t.HardDuration = envDuration(prefix+"HARD_TIMEOUT", t.HardDuration)
+	t.SoftRows = envInt(prefix+"SOFT_ROWS", t.SoftRows)
+	t.HardRows = envInt(prefix+"HARD_ROWS", t.HardRows)
+	return t
+}
+
+// envDuration reads a duration such as "30s" from the environment
+func envDuration(key string, defaultVal time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultVal
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		log.Printf("Warning: Ignoring invalid %s=%q", key, value)
+		return defaultVal
+	}
+	return d
+}
+
+// envInt reads a non-negative integer from the environment
+func envInt(key string, defaultVal int64) int64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultVal
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		log.Printf("Warning: Ignoring invalid %s=%q", key, value)
+		return defaultVal
+	}
+	return n
+}
+
+// queryFingerprint returns a short hash of the shape of a query: comments, string and
+// number literals and spacing are ignored, so the same statement with other values shares
+// its fingerprint
+func queryFingerprint(query string) string {
+	hash := fnv.New64a()
+	hash.Write([]byte(strings.ToUpper(normalizeQuery(query))))
+	return fmt.Sprintf("%016x", hash.Sum64())
+}
+
+// normalizeQuery returns a query without comments, with its string and number literals
+// replaced by ? and its spacing collapsed
+func normalizeQuery(query string) string {
+	normalized := reLineComments.ReplaceAllString(query, " ")
+	normalized = reBlockComments.ReplaceAllString(normalized, " ")
+	normalized = reSingleQuotes.ReplaceAllString(normalized, "?")
+	normalized = reNumberLiterals.ReplaceAllString(normalized, "?")
+	return strings.Join(strings.Fields(normalized), " ")
+}
+
+// shortenQuery truncates a query for logging
+func shortenQuery(query string) string {
+	query = strings.Join(strings.Fields(query), " ")
+	if len(query) > WatchdogLogQueryLength {
+		return query[:WatchdogLogQueryLength] + "..."
+	}
+	return query
+}
@@ -0,0 +1,232 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"os"
+	"os/user"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// queryWatermark renders the comment prepended to every statement, so DBAs can attribute
+// queries seen in server-side monitoring to the MCP session and tool that issued them
+type queryWatermark struct {
+	template string
+	session  string
+	user     string
+	host     string
+}
+
+// watermarkCall is the tool call a statement belongs to
+type watermarkCall struct {
+	tool   string
+	client string
+}
+
+type watermarkCallKey struct{}
+
+var (
+	watermarkOnce sync.Once
+	watermark     *queryWatermark
+)
+
+// getQueryWatermark returns the process-wide watermark configured by DB_QUERY_WATERMARK,
+// or nil when watermarking is disabled
+func getQueryWatermark() *queryWatermark {
+	watermarkOnce.Do(func() {
+		template, set := os.LookupEnv("DB_QUERY_WATERMARK")
+		if !set || strings.TrimSpace(template) == "" {
+			template = DefaultQueryWatermark
+		}
+		if strings.EqualFold(strings.TrimSpace(template), "off") {
+			return
+		}
+
+		watermark = &queryWatermark{
+			template: template,
+			session:  newWatermarkSession(),
+			user:     currentUsername(),
+		}
+		watermark.host, _ = os.Hostname()
+	})
+	return watermark
+}
+
+// Apply prepends the rendered comment to a statement
+func (w *queryWatermark) Apply(ctx context.Context, query string) string {
+	if w == nil {
+		return query
+	}
+
+	call, _ := ctx.Value(watermarkCallKey{}).(watermarkCall)
+	comment := strings.NewReplacer(
+		"{session}", sanitizeWatermark(w.session),
+		"{tool}", sanitizeWatermark(call.tool),
+		"{client}", sanitizeWatermark(call.client),
+		"{user}", sanitizeWatermark(w.user),
+		"{host}", sanitizeWatermark(w.host),
+	).Replace(w.template)
+
+	return "/* " + sanitizeWatermark(comment) + " */ " + query
+}
+
+// watermarkMiddleware records the tool and client of a call in its context
+func watermarkMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		call := watermarkCall{tool: request.Params.Name}
+		if session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithClientInfo); ok {
+			call.client = session.GetClientInfo().Name
+		}
+		return next(withWatermarkCall(ctx, call), request)
+	}
+}
+
+// withWatermarkCall returns a context whose statements are attributed to the call
+func withWatermarkCall(ctx context.Context, call watermarkCall) context.Context {
+	return context.WithValue(ctx, watermarkCallKey{}, call)
+}
+
+// sanitizeWatermark keeps a value from closing the comment or spanning lines;
+// unknown values are rendered as -. Comment delimiters are removed until none is left, as
+// removing one can join the characters around it into another: the client sets its own name.
+func sanitizeWatermark(value string) string {
+	replacer := strings.NewReplacer("*/", "", "/*", "", "\r", " ", "\n", " ")
+	for {
+		sanitized := replacer.Replace(value)
+		if sanitized == value {
+			break
+		}
+		value = sanitized
+	}
+	if len(value) > MaxQueryWatermarkLength {
+		end := MaxQueryWatermarkLength
+		for end > 0 && !utf8.RuneStart(value[end]) {
+			end--
+		}
+		value = value[:end]
+	}
+	if strings.TrimSpace(value) == "" {
+		return "-"
+	}
+	return value
+}
+
+// newWatermarkSession returns a random identifier for this server process
+func newWatermarkSession() string {
+	buf := make([]byte, 6)
+	if _, err := rand.Read(buf); err != nil {
+		return strings.TrimSpace(os.Getenv("HOSTNAME"))
+	}
+	return hex.EncodeToString(buf)
+}
+
+// currentUsername returns the operating system user running the server
+func currentUsername() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return os.Getenv("USERNAME")
+}
+
+// watermarkConnector wraps the connections of a driver connector in watermarkConn
+type watermarkConnector struct {
+	driver.Connector
+	watermark *queryWatermark
+}
+
+// Connect opens a connection that watermarks its statements
+func (c *watermarkConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &watermarkConn{Conn: conn, watermark: c.watermark}, nil
+}
+
+// watermarkConn prepends the watermark to every query, statement and prepared statement,
+// forwarding the optional driver interfaces to the wrapped connection
+type watermarkConn struct {
+	driver.Conn
+	watermark *queryWatermark
+}
+
+// PrepareContext prepares a watermarked statement
+func (c *watermarkConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	query = c.watermark.Apply(ctx, query)
+	if prep, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return prep.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+// QueryContext runs a watermarked query, or lets database/sql prepare it
+func (c *watermarkConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	return queryer.QueryContext(ctx, c.watermark.Apply(ctx, query), args)
+}
+
+// ExecContext runs a watermarked statement, or lets database/sql prepare it
+func (c *watermarkConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	return execer.ExecContext(ctx, c.watermark.Apply(ctx, query), args)
+}
+
+// BeginTx starts a transaction on the wrapped connection
+func (c *watermarkConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) || opts.ReadOnly {
+		return nil, ErrTxOptionsNotSupported
+	}
+	return c.Conn.Begin() //nolint:staticcheck // fallback for drivers without ConnBeginTx
+}
+
+// Ping checks the wrapped connection
+func (c *watermarkConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// ResetSession resets the wrapped connection before it is reused
+func (c *watermarkConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+// IsValid reports whether the wrapped connection can be reused
+func (c *watermarkConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+// CheckNamedValue lets the wrapped connection convert arguments such as output parameters
+func (c *watermarkConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
@@ -0,0 +1,45 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSanitizeWatermark(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"plain", "claude-desktop 1.0", "claude-desktop 1.0"},
+		{"comment delimiters", "a*/b/*c", "abc"},
+		{"delimiter joined by a removal", "**//*/ DROP TABLE t -- ", " DROP TABLE t -- "},
+		{"line breaks", "a\r\nb", "a  b"},
+		{"empty", "*/", "-"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeWatermark(tt.value); got != tt.want {
+				t.Errorf("sanitizeWatermark(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+
+	long := strings.Repeat("é", MaxQueryWatermarkLength)
+	if got := sanitizeWatermark(long); len(got) > MaxQueryWatermarkLength || !utf8.ValidString(got) {
+		t.Errorf("sanitizeWatermark() of a long value = %d bytes, valid UTF-8 %v", len(got), utf8.ValidString(got))
+	}
+}
+
+// TestWatermarkClientName checks that a client name cannot close the comment before the
+// statement it is prepended to
+func TestWatermarkClientName(t *testing.T) {
+	w := &queryWatermark{template: "mcp tool={tool} client={client}"}
+	ctx := withWatermarkCall(context.Background(), watermarkCall{tool: "execute_query", client: "**//*/ DROP TABLE t -- "})
+	got := w.Apply(ctx, "SELECT 1")
+	if !strings.HasSuffix(got, " */ SELECT 1") || strings.Count(got, "*/") != 1 {
+		t.Errorf("Apply() = %q, want one comment before the statement", got)
+	}
+}
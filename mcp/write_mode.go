@@ -0,0 +1,424 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// writeMode holds the settings of the opt-in write tools. DB_ALLOW_WRITES registers
+// execute_insert, execute_update and execute_delete; every other tool stays read-only.
+// Each call runs in its own transaction, which is rolled back when its statements affect
+// more than DB_MAX_AFFECTED_ROWS rows, and is logged and, with DB_WRITE_AUDIT_FILE,
+// appended to the audit file as a JSON line.
+type writeMode struct {
+	mu              sync.Mutex
+	maxAffectedRows int64
+	auditPath       string
+}
+
+// writeAuditEntry is a write call as recorded in the audit file
+type writeAuditEntry struct {
+	Time         time.Time              `json:"time"`
+	Tool         string                 `json:"tool"`
+	Client       string                 `json:"client,omitempty"`
+	Schema       string                 `json:"schema"`
+	Table        string                 `json:"table"`
+	Statement    string                 `json:"statement"`
+	Arguments    map[string]interface{} `json:"arguments"`
+	AffectedRows int64                  `json:"affected_rows"`
+	Outcome      string                 `json:"outcome"`
+	Error        string                 `json:"error,omitempty"`
+}
+
+// writeStatement is a statement of a write call with its arguments
+type writeStatement struct {
+	query string
+	args  []interface{}
+}
+
+// newWriteMode returns the write mode settings, or nil when DB_ALLOW_WRITES is not set
+func newWriteMode() *writeMode {
+	if !getEnvAllowWrites() {
+		return nil
+	}
+	return &writeMode{
+		maxAffectedRows: getEnvMaxAffectedRows(),
+		auditPath:       strings.TrimSpace(os.Getenv("DB_WRITE_AUDIT_FILE")),
+	}
+}
+
+// getEnvAllowWrites reports whether DB_ALLOW_WRITES registers the write tools
+func getEnvAllowWrites() bool {
+	value := os.Getenv("DB_ALLOW_WRITES")
+	if value == "" {
+		return false
+	}
+	allow, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Warning: Ignoring invalid DB_ALLOW_WRITES=%q", value)
+		return false
+	}
+	return allow
+}
+
+// getEnvMaxAffectedRows reads the rows a write call may affect from DB_MAX_AFFECTED_ROWS
+func getEnvMaxAffectedRows() int64 {
+	value := os.Getenv("DB_MAX_AFFECTED_ROWS")
+	if value == "" {
+		return DefaultMaxAffectedRows
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n <= 0 {
+		log.Printf("Warning: Ignoring invalid DB_MAX_AFFECTED_ROWS=%q", value)
+		return DefaultMaxAffectedRows
+	}
+	return n
+}
+
+// limit returns the rows a call may affect: max_affected_rows, capped at
+// DB_MAX_AFFECTED_ROWS
+func (w *writeMode) limit(maxAffectedRows int64) int64 {
+	if maxAffectedRows <= 0 || maxAffectedRows > w.maxAffectedRows {
+		return w.maxAffectedRows
+	}
+	return maxAffectedRows
+}
+
+// audit logs a write call and appends it to the audit file when one is configured
+func (w *writeMode) audit(entry writeAuditEntry) {
+	message := fmt.Sprintf("Write %s (tool=%s, table=%s.%s, %d rows): %s",
+		entry.Outcome, entry.Tool, entry.Schema, entry.Table, entry.AffectedRows, shortenQuery(entry.Statement))
+	if entry.Error != "" {
+		message += "\nReason: " + entry.Error
+	}
+	log.Print(message)
+	if w.auditPath == "" {
+		return
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("Warning: %v: %v", ErrWritingAuditLog, err)
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	file, err := os.OpenFile(w.auditPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err == nil {
+		_, err = file.Write(append(data, '\n'))
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		log.Printf("Warning: %v: %v", ErrWritingAuditLog, err)
+	}
+}
+
+// writeTarget is the table a write call changes, with its columns
+type writeTarget struct {
+	schema  string
+	table   string
+	columns []string
+}
+
+// resolveWriteTarget checks the table of a write call and returns it with its columns
+func (s *DbMCPServer) resolveWriteTarget(ctx context.Context, schemaArg, tableName string) (writeTarget, *mcp.CallToolResult) {
+	if !isValidIdentifier(tableName) {
+		return writeTarget{}, toolErrorResult(ErrInvalidTableName)
+	}
+
+	defaultSchema := getDefaultSchema(s.queryBuilder.GetDriver())
+	schema, err := getValidSchema(schemaArg, defaultSchema)
+	if err != nil {
+		return writeTarget{}, toolErrorResult(err)
+	}
+
+	if exists, err := s.tableExists(ctx, schema, tableName); err != nil {
+		return writeTarget{}, s.dbErrorResult(ErrCheckingTable, err)
+	} else if !exists {
+		return writeTarget{}, toolErrorResult(fmt.Errorf("%w: %s.%s", ErrTableNotFound, schema, tableName))
+	}
+
+	columns, err := s.getTableColumns(ctx, schema, tableName)
+	if err != nil {
+		return writeTarget{}, s.dbErrorResult(ErrRetrievingColumns, err)
+	}
+	if len(columns) == 0 {
+		return writeTarget{}, toolErrorResult(ErrNoColumnsFound)
+	}
+	return writeTarget{schema: schema, table: tableName, columns: columns}, nil
+}
+
+// columnValues returns the quoted columns of a column-value map and their driver values,
+// sorted by column so the same columns always produce the same statement
+func (s *DbMCPServer) columnValues(values map[string]interface{}, columns []string) ([]string, []interface{}, error) {
+	var quoted []string
+	var args []interface{}
+	for _, column := range sortedParamNames(values) {
+		if !s.columnExists(columns, column) {
+			return nil, nil, fmt.Errorf("%w: %s", ErrColumnNotExists, column)
+		}
+		arg, ok := queryParameterValue(values[column])
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: "+translate("column %s must be a string, number, boolean or null"), ErrInvalidColumnValue, column)
+		}
+		quoted = append(quoted, s.queryBuilder.QuoteIdentifier(column))
+		args = append(args, arg)
+	}
+	return quoted, args, nil
+}
+
+// writeWhereClause returns the WHERE clause of an update or delete, whose placeholders start
+// at firstParam. A statement without a condition would change every row, so at least one
+// filter is required.
+func (s *DbMCPServer) writeWhereClause(filters []rowFilter, columns []string, firstParam int) (string, []interface{}, error) {
+	whereClauses, params, err := s.buildWhereClauseFrom(filters, columns, firstParam)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(whereClauses) == 0 {
+		return "", nil, ErrWriteFiltersRequired
+	}
+	return "WHERE " + strings.Join(whereClauses, " AND "), params, nil
+}
+
+// runWrite executes the statements of a write call in a transaction, which is committed
+// unless the statements affect more than limit rows or the call is a dry run
+func (s *DbMCPServer) runWrite(ctx context.Context, request mcp.CallToolRequest, target writeTarget, statements []writeStatement, limit int64, dryRun bool) *mcp.CallToolResult {
+	tool := request.Params.Name
+	entry := writeAuditEntry{
+		Time:      time.Now(),
+		Tool:      tool,
+		Schema:    target.schema,
+		Table:     target.table,
+		Statement: statements[0].query,
+		Arguments: request.GetArguments(),
+	}
+	if call, ok := ctx.Value(watermarkCallKey{}).(watermarkCall); ok {
+		entry.Client = call.client
+	}
+
+	fail := func(result *mcp.CallToolResult, err error) *mcp.CallToolResult {
+		entry.Outcome = "error"
+		entry.Error = err.Error()
+		s.writes.audit(entry)
+		return result
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fail(s.dbErrorResult(ErrExecutingWrite, err), err)
+	}
+	defer tx.Rollback()
+
+	ctx, watch := s.watchdog.Watch(ctx, tool, statements[0].query)
+	defer watch.Done()
+
+	for _, statement := range statements {
+		result, err := tx.ExecContext(ctx, statement.query, statement.args...)
+		if err != nil {
+			err = watch.Cause(err)
+			return fail(s.dbErrorResult(ErrExecutingWrite, err), err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fail(s.dbErrorResult(ErrExecutingWrite, err), err)
+		}
+		entry.AffectedRows += affected
+
+		// The transaction is rolled back as soon as the limit is passed
+		if entry.AffectedRows > limit {
+			err := fmt.Errorf("%w "+translate("(%d rows, maximum %d)"), ErrAffectedRowsExceeded, entry.AffectedRows, limit)
+			entry.Outcome = "rolled_back"
+			entry.Error = err.Error()
+			s.writes.audit(entry)
+			return toolErrorResult(watch.Cause(err))
+		}
+	}
+
+	if dryRun {
+		entry.Outcome = "dry_run"
+	} else {
+		if err := tx.Commit(); err != nil {
+			return fail(s.dbErrorResult(ErrExecutingWrite, err), err)
+		}
+		entry.Outcome = "committed"
+		// Cached responses may hold the rows just changed
+		s.queryCache.clear()
+	}
+	s.writes.audit(entry)
+
+	response := map[string]interface{}{
+		"status":        "success",
+		"table":         target.table,
+		"schema":        target.schema,
+		"affected_rows": entry.AffectedRows,
+		"committed":     !dryRun,
+	}
+	if dryRun {
+		response["message"] = translate("Dry run: the changes were rolled back")
+	}
+	return jsonToolResult(response)
+}
+
+// executeInsertArgs are the arguments of execute_insert
+type executeInsertArgs struct {
+	TableName       string                   `json:"table_name" jsonschema_description:"Table name"`
+	Schema          string                   `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	Rows            []map[string]interface{} `json:"rows" jsonschema_description:"Rows to insert, as objects of column values (e.g.: [{\"name\": \"john\", \"active\": true}])"`
+	MaxAffectedRows int64                    `json:"max_affected_rows,omitempty" jsonschema_description:"Roll back when more rows are inserted (default and maximum: DB_MAX_AFFECTED_ROWS)"`
+	DryRun          bool                     `json:"dry_run,omitempty" jsonschema_description:"Run the statements and report the affected rows, then roll them back"`
+}
+
+func (s *DbMCPServer) toolExecuteInsert() (mcp.Tool, server.ToolHandlerFunc) {
+	tool, handler := newTypedTool("execute_insert", "Insert rows into a table in a transaction that is rolled back when more rows than allowed are affected. Only registered with DB_ALLOW_WRITES", s.handleExecuteInsert)
+	tool.Annotations = mcp.ToolAnnotation{DestructiveHint: mcp.ToBoolPtr(false)}
+	return tool, handler
+}
+
+func (s *DbMCPServer) handleExecuteInsert(ctx context.Context, request mcp.CallToolRequest, args executeInsertArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	if len(args.Rows) == 0 {
+		return toolErrorResult(ErrInsertRowsRequired), nil
+	}
+	if len(args.Rows) > MaxInsertRows {
+		return toolErrorResult(fmt.Errorf("%w "+translate("(maximum %d)"), ErrTooManyInsertRows, MaxInsertRows)), nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, DefaultQueryTimeout)
+	defer cancel()
+
+	target, failed := s.resolveWriteTarget(ctx, args.Schema, args.TableName)
+	if failed != nil {
+		return failed, nil
+	}
+
+	// One statement per row, which every driver supports
+	qualifiedTable := s.queryBuilder.QualifyTable(target.schema, target.table)
+	statements := make([]writeStatement, 0, len(args.Rows))
+	for _, row := range args.Rows {
+		if len(row) == 0 {
+			return toolErrorResult(ErrInsertRowsRequired), nil
+		}
+		columns, values, err := s.columnValues(row, target.columns)
+		if err != nil {
+			return toolErrorResult(err), nil
+		}
+		placeholders := make([]string, len(values))
+		for i := range values {
+			placeholders[i] = s.queryBuilder.Placeholder(i + 1)
+		}
+		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", qualifiedTable, strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+		statements = append(statements, writeStatement{query: query, args: values})
+	}
+
+	return s.runWrite(ctx, request, target, statements, s.writes.limit(args.MaxAffectedRows), args.DryRun), nil
+}
+
+// executeUpdateArgs are the arguments of execute_update
+type executeUpdateArgs struct {
+	TableName       string                 `json:"table_name" jsonschema_description:"Table name"`
+	Schema          string                 `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	Values          map[string]interface{} `json:"values" jsonschema_description:"New column values (e.g.: {\"status\": \"paid\"})"`
+	Filters         []rowFilter            `json:"filters" jsonschema_description:"Filters selecting the rows to update, at least one (e.g.: [{\"column\": \"id\", \"operator\": \"eq\", \"value\": 42}])"`
+	MaxAffectedRows int64                  `json:"max_affected_rows,omitempty" jsonschema_description:"Roll back when more rows are updated (default and maximum: DB_MAX_AFFECTED_ROWS)"`
+	DryRun          bool                   `json:"dry_run,omitempty" jsonschema_description:"Run the statement and report the affected rows, then roll it back"`
+}
+
+func (s *DbMCPServer) toolExecuteUpdate() (mcp.Tool, server.ToolHandlerFunc) {
+	tool, handler := newTypedTool("execute_update", "Update the rows of a table matching the filters in a transaction that is rolled back when more rows than allowed are affected. Filters are required. Only registered with DB_ALLOW_WRITES", s.handleExecuteUpdate)
+	tool.Annotations = mcp.ToolAnnotation{DestructiveHint: mcp.ToBoolPtr(true)}
+	return tool, handler
+}
+
+func (s *DbMCPServer) handleExecuteUpdate(ctx context.Context, request mcp.CallToolRequest, args executeUpdateArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	if len(args.Values) == 0 {
+		return toolErrorResult(ErrUpdateValuesRequired), nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, DefaultQueryTimeout)
+	defer cancel()
+
+	target, failed := s.resolveWriteTarget(ctx, args.Schema, args.TableName)
+	if failed != nil {
+		return failed, nil
+	}
+
+	columns, values, err := s.columnValues(args.Values, target.columns)
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+	assignments := make([]string, len(columns))
+	for i, column := range columns {
+		assignments[i] = fmt.Sprintf("%s = %s", column, s.queryBuilder.Placeholder(i+1))
+	}
+
+	whereClause, params, err := s.writeWhereClause(args.Filters, target.columns, len(values)+1)
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s %s", s.queryBuilder.QualifyTable(target.schema, target.table), strings.Join(assignments, ", "), whereClause)
+	statements := []writeStatement{{query: query, args: append(values, params...)}}
+
+	return s.runWrite(ctx, request, target, statements, s.writes.limit(args.MaxAffectedRows), args.DryRun), nil
+}
+
+// executeDeleteArgs are the arguments of execute_delete
+type executeDeleteArgs struct {
+	TableName       string      `json:"table_name" jsonschema_description:"Table name"`
+	Schema          string      `json:"schema,omitempty" jsonschema_description:"Schema name (optional)"`
+	Filters         []rowFilter `json:"filters" jsonschema_description:"Filters selecting the rows to delete, at least one (e.g.: [{\"column\": \"id\", \"operator\": \"eq\", \"value\": 42}])"`
+	MaxAffectedRows int64       `json:"max_affected_rows,omitempty" jsonschema_description:"Roll back when more rows are deleted (default and maximum: DB_MAX_AFFECTED_ROWS)"`
+	DryRun          bool        `json:"dry_run,omitempty" jsonschema_description:"Run the statement and report the affected rows, then roll it back"`
+}
+
+func (s *DbMCPServer) toolExecuteDelete() (mcp.Tool, server.ToolHandlerFunc) {
+	tool, handler := newTypedTool("execute_delete", "Delete the rows of a table matching the filters in a transaction that is rolled back when more rows than allowed are affected. Filters are required. Only registered with DB_ALLOW_WRITES", s.handleExecuteDelete)
+	tool.Annotations = mcp.ToolAnnotation{DestructiveHint: mcp.ToBoolPtr(true)}
+	return tool, handler
+}
+
+func (s *DbMCPServer) handleExecuteDelete(ctx context.Context, request mcp.CallToolRequest, args executeDeleteArgs) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, DefaultQueryTimeout)
+	defer cancel()
+
+	target, failed := s.resolveWriteTarget(ctx, args.Schema, args.TableName)
+	if failed != nil {
+		return failed, nil
+	}
+
+	whereClause, params, err := s.writeWhereClause(args.Filters, target.columns, 1)
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s %s", s.queryBuilder.QualifyTable(target.schema, target.table), whereClause)
+	statements := []writeStatement{{query: query, args: params}}
+
+	return s.runWrite(ctx, request, target, statements, s.writes.limit(args.MaxAffectedRows), args.DryRun), nil
+}